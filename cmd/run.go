@@ -46,15 +46,41 @@ var (
 				}
 			}()
 
+			schedulerCtx, stopScheduler := context.WithCancel(context.Background())
+			go apiServer.Scheduler.Run(schedulerCtx)
+			go apiServer.Digest.Run(schedulerCtx)
+			go apiServer.TelegramInbox.Run(schedulerCtx)
+			go apiServer.SignalInbox.Run(schedulerCtx)
+			go apiServer.MailInbox.Run(schedulerCtx)
+
+			outboxCtx, stopOutbox := context.WithCancel(context.Background())
+			outboxDone := make(chan struct{})
+			go func() {
+				apiServer.Outbox.Run(outboxCtx)
+				close(outboxDone)
+			}()
+
 			// Wait for a shutdown signal, then gracefully shutdown the server with a
 			// timeout of 5 seconds.
 			waitForShutdown()
 
 			log.Infoln("shutdown ticker")
 
+			// Stop publishing scheduled messages, digests and inbound posts before the engines go away
+			stopScheduler()
+
 			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 			defer cancel()
 
+			// Let running deliveries finish; jobs which do not make it in time
+			// are picked up again after the next start.
+			stopOutbox()
+			select {
+			case <-outboxDone:
+			case <-ctx.Done():
+				log.Warn("outbox did not stop in time")
+			}
+
 			// Shutdown realtime engine first
 			if err := apiServer.Realtime.Shutdown(ctx); err != nil {
 				log.WithError(err).Warn("realtime engine shutdown failed")
@@ -0,0 +1,226 @@
+package activitypub
+
+import (
+	"fmt"
+	"html"
+	"strings"
+	"time"
+
+	"github.com/systemli/ticker/internal/storage"
+)
+
+const (
+	ContentType = "application/activity+json"
+	// Public is the special collection addressing an activity to everyone.
+	Public = "https://www.w3.org/ns/activitystreams#Public"
+	// BasePath is the path the sites of the tickers serve the ActivityPub
+	// endpoints below. Like the media, they are reached through the /api path
+	// of the site, which the API serves as /v1.
+	BasePath = "/api/activitypub"
+)
+
+var Context = []string{"https://www.w3.org/ns/activitystreams", "https://w3id.org/security/v1"}
+
+type Actor struct {
+	Context           any        `json:"@context,omitempty"`
+	ID                string     `json:"id"`
+	Type              string     `json:"type"`
+	PreferredUsername string     `json:"preferredUsername"`
+	Name              string     `json:"name,omitempty"`
+	Summary           string     `json:"summary,omitempty"`
+	URL               string     `json:"url,omitempty"`
+	Inbox             string     `json:"inbox"`
+	Outbox            string     `json:"outbox,omitempty"`
+	Followers         string     `json:"followers,omitempty"`
+	Endpoints         *Endpoints `json:"endpoints,omitempty"`
+	PublicKey         PublicKey  `json:"publicKey"`
+}
+
+type Endpoints struct {
+	SharedInbox string `json:"sharedInbox,omitempty"`
+}
+
+type PublicKey struct {
+	ID           string `json:"id"`
+	Owner        string `json:"owner"`
+	PublicKeyPem string `json:"publicKeyPem"`
+}
+
+type Activity struct {
+	Context any      `json:"@context,omitempty"`
+	ID      string   `json:"id"`
+	Type    string   `json:"type"`
+	Actor   string   `json:"actor"`
+	Object  any      `json:"object"`
+	To      []string `json:"to,omitempty"`
+	Cc      []string `json:"cc,omitempty"`
+}
+
+type Note struct {
+	Context      any          `json:"@context,omitempty"`
+	ID           string       `json:"id"`
+	Type         string       `json:"type"`
+	AttributedTo string       `json:"attributedTo"`
+	Content      string       `json:"content"`
+	Published    time.Time    `json:"published"`
+	Updated      *time.Time   `json:"updated,omitempty"`
+	URL          string       `json:"url,omitempty"`
+	To           []string     `json:"to"`
+	Cc           []string     `json:"cc"`
+	Attachment   []Attachment `json:"attachment,omitempty"`
+}
+
+type Attachment struct {
+	Type      string `json:"type"`
+	MediaType string `json:"mediaType"`
+	URL       string `json:"url"`
+}
+
+type Tombstone struct {
+	ID   string `json:"id"`
+	Type string `json:"type"`
+}
+
+type OrderedCollection struct {
+	Context      any    `json:"@context,omitempty"`
+	ID           string `json:"id"`
+	Type         string `json:"type"`
+	TotalItems   int    `json:"totalItems"`
+	OrderedItems []any  `json:"orderedItems,omitempty"`
+}
+
+// ActorID returns the IRI of the actor of the ticker, all other IRIs of the
+// ticker are below it.
+func ActorID(ticker storage.Ticker) string {
+	return fmt.Sprintf("%s%s/tickers/%d", ticker.ActivityPub.Origin, BasePath, ticker.ID)
+}
+
+func KeyID(ticker storage.Ticker) string {
+	return ActorID(ticker) + "#main-key"
+}
+
+func NoteID(ticker storage.Ticker, message storage.Message) string {
+	return fmt.Sprintf("%s/notes/%d", ActorID(ticker), message.ID)
+}
+
+func NewActor(ticker storage.Ticker) Actor {
+	id := ActorID(ticker)
+
+	return Actor{
+		Context:           Context,
+		ID:                id,
+		Type:              "Service",
+		PreferredUsername: ticker.ActivityPub.Username,
+		Name:              ticker.Title,
+		Summary:           html.EscapeString(ticker.Description),
+		URL:               ticker.ActivityPub.Origin,
+		Inbox:             id + "/inbox",
+		Outbox:            id + "/outbox",
+		Followers:         id + "/followers",
+		PublicKey: PublicKey{
+			ID:           KeyID(ticker),
+			Owner:        id,
+			PublicKeyPem: ticker.ActivityPub.PublicKey,
+		},
+	}
+}
+
+// NewNote returns the message as a public Note, addressed to the followers of
+// the ticker as well.
+func NewNote(ticker storage.Ticker, message storage.Message) Note {
+	var attachments []Attachment
+	for _, attachment := range message.Attachments {
+		attachments = append(attachments, Attachment{
+			Type:      "Document",
+			MediaType: attachment.ContentType,
+			URL:       ticker.ActivityPub.Origin + storage.MediaURL(attachment.FileName()),
+		})
+	}
+
+	return Note{
+		ID:           NoteID(ticker, message),
+		Type:         "Note",
+		AttributedTo: ActorID(ticker),
+		Content:      content(message.Text),
+		Published:    message.CreatedAt.UTC(),
+		URL:          ticker.ActivityPub.Origin,
+		To:           []string{Public},
+		Cc:           []string{ActorID(ticker) + "/followers"},
+		Attachment:   attachments,
+	}
+}
+
+func NewCreate(ticker storage.Ticker, message storage.Message) Activity {
+	note := NewNote(ticker, message)
+
+	return Activity{
+		Context: Context,
+		ID:      note.ID + "/activity",
+		Type:    "Create",
+		Actor:   note.AttributedTo,
+		Object:  note,
+		To:      note.To,
+		Cc:      note.Cc,
+	}
+}
+
+// NewUpdate announces the edited text of a message. Servers only apply the
+// update when the note carries the time it was updated.
+func NewUpdate(ticker storage.Ticker, message storage.Message) Activity {
+	note := NewNote(ticker, message)
+	updated := message.UpdatedAt.UTC()
+	note.Updated = &updated
+
+	return Activity{
+		Context: Context,
+		ID:      fmt.Sprintf("%s#updates/%d", note.ID, updated.Unix()),
+		Type:    "Update",
+		Actor:   note.AttributedTo,
+		Object:  note,
+		To:      note.To,
+		Cc:      note.Cc,
+	}
+}
+
+func NewDelete(ticker storage.Ticker, message storage.Message) Activity {
+	id := NoteID(ticker, message)
+
+	return Activity{
+		Context: Context,
+		ID:      id + "#delete",
+		Type:    "Delete",
+		Actor:   ActorID(ticker),
+		Object:  Tombstone{ID: id, Type: "Tombstone"},
+		To:      []string{Public},
+		Cc:      []string{ActorID(ticker) + "/followers"},
+	}
+}
+
+// NewAccept accepts the follow request of another actor.
+func NewAccept(ticker storage.Ticker, follow Activity) Activity {
+	return Activity{
+		Context: Context,
+		ID:      fmt.Sprintf("%s#accepts/%d", ActorID(ticker), time.Now().UnixNano()),
+		Type:    "Accept",
+		Actor:   ActorID(ticker),
+		Object:  follow,
+		To:      []string{follow.Actor},
+	}
+}
+
+// content turns the plain text of a message into the HTML servers expect,
+// with paragraphs for blank lines and breaks for single line breaks.
+func content(text string) string {
+	var paragraphs []string
+	for _, paragraph := range strings.Split(strings.ReplaceAll(text, "\r\n", "\n"), "\n\n") {
+		paragraph = strings.TrimSpace(paragraph)
+		if paragraph == "" {
+			continue
+		}
+
+		lines := strings.Split(html.EscapeString(paragraph), "\n")
+		paragraphs = append(paragraphs, "<p>"+strings.Join(lines, "<br>")+"</p>")
+	}
+
+	return strings.Join(paragraphs, "")
+}
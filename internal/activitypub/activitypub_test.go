@@ -0,0 +1,69 @@
+package activitypub
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/systemli/ticker/internal/storage"
+)
+
+var ticker = storage.Ticker{
+	ID:          1,
+	Title:       "Demo",
+	Description: "News & updates",
+	ActivityPub: storage.TickerActivityPub{Username: "ticker", Origin: "https://demo.example.org", PublicKey: "PublicKey"},
+}
+
+func TestNewActor(t *testing.T) {
+	actor := NewActor(ticker)
+
+	assert.Equal(t, "https://demo.example.org/api/activitypub/tickers/1", actor.ID)
+	assert.Equal(t, "ticker", actor.PreferredUsername)
+	assert.Equal(t, "News &amp; updates", actor.Summary)
+	assert.Equal(t, actor.ID+"/inbox", actor.Inbox)
+	assert.Equal(t, actor.ID+"#main-key", actor.PublicKey.ID)
+	assert.Equal(t, "PublicKey", actor.PublicKey.PublicKeyPem)
+}
+
+func TestNewCreate(t *testing.T) {
+	message := storage.Message{
+		ID:          2,
+		CreatedAt:   time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC),
+		Text:        "First <line>\nSecond line\n\nNew paragraph",
+		Attachments: []storage.Attachment{{UUID: "123", Extension: "jpg", ContentType: "image/jpeg"}},
+	}
+
+	activity := NewCreate(ticker, message)
+	note, ok := activity.Object.(Note)
+	assert.True(t, ok)
+	assert.Equal(t, "Create", activity.Type)
+	assert.Equal(t, "https://demo.example.org/api/activitypub/tickers/1/notes/2", note.ID)
+	assert.Equal(t, "<p>First &lt;line&gt;<br>Second line</p><p>New paragraph</p>", note.Content)
+	assert.Equal(t, []string{Public}, note.To)
+	assert.Equal(t, "https://demo.example.org/api/media/123.jpg", note.Attachment[0].URL)
+}
+
+func TestNewUpdate(t *testing.T) {
+	activity := NewUpdate(ticker, storage.Message{ID: 2, UpdatedAt: time.Now()})
+	note, ok := activity.Object.(Note)
+	assert.True(t, ok)
+	assert.Equal(t, "Update", activity.Type)
+	assert.NotNil(t, note.Updated)
+}
+
+func TestNewDelete(t *testing.T) {
+	activity := NewDelete(ticker, storage.Message{ID: 2})
+
+	assert.Equal(t, "Delete", activity.Type)
+	assert.Equal(t, Tombstone{ID: "https://demo.example.org/api/activitypub/tickers/1/notes/2", Type: "Tombstone"}, activity.Object)
+}
+
+func TestNewAccept(t *testing.T) {
+	follow := Activity{ID: "https://social.example.org/follows/1", Type: "Follow", Actor: "https://social.example.org/users/alice", Object: ActorID(ticker)}
+	activity := NewAccept(ticker, follow)
+
+	assert.Equal(t, "Accept", activity.Type)
+	assert.Equal(t, follow, activity.Object)
+	assert.Equal(t, []string{follow.Actor}, activity.To)
+}
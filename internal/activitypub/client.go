@@ -0,0 +1,122 @@
+package activitypub
+
+import (
+	"bytes"
+	"context"
+	"crypto/rsa"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/systemli/ticker/internal/util"
+)
+
+// ErrURL is returned for URLs which are not https, or point to an internal
+// address. The URLs come from other servers, so they are not trusted.
+var ErrURL = errors.New("url is not a public https url")
+
+// HTTPClient is shared by the clients of all actors. It refuses to connect to
+// internal addresses.
+var HTTPClient = &http.Client{Timeout: 10 * time.Second, Transport: util.PublicTransport()}
+
+// Client talks to other servers on behalf of an actor, every request is signed
+// with the key of the actor.
+type Client struct {
+	KeyID string
+	Key   *rsa.PrivateKey
+}
+
+func NewClient(keyID, privateKey string) (*Client, error) {
+	key, err := ParsePrivateKey(privateKey)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Client{KeyID: keyID, Key: key}, nil
+}
+
+// Post delivers the activity to an inbox.
+func (c *Client) Post(ctx context.Context, inbox string, activity any) error {
+	if !util.IsPublicURL(inbox, "https") {
+		return ErrURL
+	}
+
+	body, err := json.Marshal(activity)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, inbox, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", ContentType)
+	if err := Sign(req, body, c.KeyID, c.Key); err != nil {
+		return err
+	}
+
+	resp, err := HTTPClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("inbox %s responded with %s", inbox, resp.Status)
+	}
+
+	return nil
+}
+
+// FetchActor returns the actor behind the IRI. The request is signed, as
+// servers in authorized fetch mode refuse to answer otherwise.
+func (c *Client) FetchActor(ctx context.Context, iri string) (Actor, error) {
+	var actor Actor
+
+	if !util.IsPublicURL(iri, "https") {
+		return actor, ErrURL
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, iri, nil)
+	if err != nil {
+		return actor, err
+	}
+	req.Header.Set("Accept", ContentType)
+	if err := Sign(req, nil, c.KeyID, c.Key); err != nil {
+		return actor, err
+	}
+
+	resp, err := HTTPClient.Do(req)
+	if err != nil {
+		return actor, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return actor, fmt.Errorf("actor %s responded with %s", iri, resp.Status)
+	}
+
+	err = json.NewDecoder(io.LimitReader(resp.Body, 1<<20)).Decode(&actor)
+
+	return actor, err
+}
+
+// SameHost reports whether the URLs are on the same host. An actor is only
+// trusted for its own keys and inboxes, which live on its server.
+func SameHost(a, b string) bool {
+	ua, err := url.Parse(a)
+	if err != nil || ua.Host == "" {
+		return false
+	}
+	ub, err := url.Parse(b)
+	if err != nil {
+		return false
+	}
+
+	return ua.Scheme == ub.Scheme && strings.EqualFold(ua.Host, ub.Host)
+}
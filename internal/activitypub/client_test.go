@@ -0,0 +1,81 @@
+package activitypub
+
+import (
+	"context"
+	"crypto/rsa"
+	"io"
+	"net/http"
+	"testing"
+
+	"github.com/h2non/gock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClient(t *testing.T) {
+	privatePem, _, err := GenerateKeys()
+	require.NoError(t, err)
+	client, err := NewClient("https://demo.example.org/actor#main-key", privatePem)
+	require.NoError(t, err)
+
+	lookup := func(keyID string) (*rsa.PublicKey, error) {
+		return &client.Key.PublicKey, nil
+	}
+
+	var verified bool
+	verify := func(req *http.Request, _ *gock.Request) (bool, error) {
+		var body []byte
+		if req.Body != nil {
+			body, _ = io.ReadAll(req.Body)
+		}
+		_, err := Verify(req, body, lookup)
+		verified = err == nil
+
+		return true, nil
+	}
+
+	gock.InterceptClient(HTTPClient)
+	defer gock.RestoreClient(HTTPClient)
+	defer gock.Off()
+
+	t.Run("Post", func(t *testing.T) {
+		gock.New("https://social.example.org").Post("/inbox").AddMatcher(verify).Reply(http.StatusAccepted)
+
+		err := client.Post(context.Background(), "https://social.example.org/inbox", Activity{Type: "Follow"})
+		assert.NoError(t, err)
+		assert.True(t, verified)
+	})
+
+	t.Run("FetchActor", func(t *testing.T) {
+		gock.New("https://social.example.org").Get("/users/alice").AddMatcher(verify).Reply(http.StatusOK).
+			JSON(Actor{ID: "https://social.example.org/users/alice", Inbox: "https://social.example.org/users/alice/inbox"})
+		gock.New("https://social.example.org").Get("/users/bob").Reply(http.StatusNotFound)
+
+		actor, err := client.FetchActor(context.Background(), "https://social.example.org/users/alice")
+		assert.NoError(t, err)
+		assert.True(t, verified)
+		assert.Equal(t, "https://social.example.org/users/alice/inbox", actor.Inbox)
+
+		_, err = client.FetchActor(context.Background(), "https://social.example.org/users/bob")
+		assert.Error(t, err)
+	})
+
+	t.Run("when the url is internal", func(t *testing.T) {
+		for _, url := range []string{"http://social.example.org/inbox", "https://127.0.0.1/inbox", "https://169.254.169.254/latest", "https://localhost:8080/v1/rpc"} {
+			assert.ErrorIs(t, client.Post(context.Background(), url, Activity{Type: "Follow"}), ErrURL, url)
+
+			_, err := client.FetchActor(context.Background(), url)
+			assert.ErrorIs(t, err, ErrURL, url)
+		}
+	})
+
+	_, err = NewClient("https://demo.example.org/actor#main-key", "invalid")
+	assert.Error(t, err)
+}
+
+func TestSameHost(t *testing.T) {
+	assert.True(t, SameHost("https://social.example.org/users/alice#main-key", "https://Social.example.org/users/alice"))
+	assert.False(t, SameHost("https://evil.example.org/users/alice#main-key", "https://social.example.org/users/alice"))
+	assert.False(t, SameHost("http://social.example.org/users/alice", "https://social.example.org/users/alice"))
+	assert.False(t, SameHost("", ""))
+}
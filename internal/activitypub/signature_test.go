@@ -0,0 +1,87 @@
+package activitypub
+
+import (
+	"crypto/rsa"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGenerateKeys(t *testing.T) {
+	privatePem, publicPem, err := GenerateKeys()
+	require.NoError(t, err)
+
+	privateKey, err := ParsePrivateKey(privatePem)
+	require.NoError(t, err)
+	publicKey, err := ParsePublicKey(publicPem)
+	require.NoError(t, err)
+	assert.True(t, privateKey.PublicKey.Equal(publicKey))
+
+	_, err = ParsePrivateKey("invalid")
+	assert.Error(t, err)
+	_, err = ParsePublicKey("invalid")
+	assert.Error(t, err)
+}
+
+func TestSignAndVerify(t *testing.T) {
+	privatePem, _, err := GenerateKeys()
+	require.NoError(t, err)
+	key, err := ParsePrivateKey(privatePem)
+	require.NoError(t, err)
+
+	lookup := func(keyID string) (*rsa.PublicKey, error) {
+		if keyID != "https://example.org/actor#main-key" {
+			return nil, errors.New("unknown key")
+		}
+		return &key.PublicKey, nil
+	}
+	signed := func(body string) *http.Request {
+		req := httptest.NewRequest(http.MethodPost, "https://example.org/inbox", strings.NewReader(body))
+		require.NoError(t, Sign(req, []byte(body), "https://example.org/actor#main-key", key))
+		return req
+	}
+
+	t.Run("when the request is untouched", func(t *testing.T) {
+		keyID, err := Verify(signed(`{}`), []byte(`{}`), lookup)
+		assert.NoError(t, err)
+		assert.Equal(t, "https://example.org/actor#main-key", keyID)
+	})
+
+	t.Run("when the request is not signed", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodPost, "https://example.org/inbox", nil)
+		_, err := Verify(req, nil, lookup)
+		assert.ErrorIs(t, err, ErrMissingSignature)
+	})
+
+	t.Run("when the body was changed", func(t *testing.T) {
+		_, err := Verify(signed(`{}`), []byte(`{"type":"Follow"}`), lookup)
+		assert.ErrorIs(t, err, ErrInvalidSignature)
+	})
+
+	t.Run("when the path was changed", func(t *testing.T) {
+		req := signed(`{}`)
+		req.URL.Path = "/other"
+		_, err := Verify(req, []byte(`{}`), lookup)
+		assert.ErrorIs(t, err, ErrInvalidSignature)
+	})
+
+	t.Run("when the date is too old", func(t *testing.T) {
+		req := signed(`{}`)
+		req.Header.Set("Date", time.Now().Add(-24*time.Hour).UTC().Format(http.TimeFormat))
+		_, err := Verify(req, []byte(`{}`), lookup)
+		assert.ErrorIs(t, err, ErrInvalidSignature)
+	})
+
+	t.Run("when the key is unknown", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodPost, "https://example.org/inbox", strings.NewReader(`{}`))
+		require.NoError(t, Sign(req, []byte(`{}`), "https://example.org/other#main-key", key))
+		_, err := Verify(req, []byte(`{}`), lookup)
+		assert.Error(t, err)
+	})
+}
@@ -0,0 +1,361 @@
+package api
+
+import (
+	"crypto/rsa"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/systemli/ticker/internal/activitypub"
+	"github.com/systemli/ticker/internal/api/helper"
+	"github.com/systemli/ticker/internal/api/pagination"
+	"github.com/systemli/ticker/internal/api/response"
+	"github.com/systemli/ticker/internal/storage"
+)
+
+const (
+	activityPubDefaultUsername = "ticker"
+	// activityPubBridge is the name of the bridge delivering the messages to
+	// the followers, messages which leave it out are no notes of the actor.
+	activityPubBridge = "activityPub"
+)
+
+var activityPubUsername = regexp.MustCompile(`^[a-z0-9_]{1,30}$`)
+
+type ActivityPubParam struct {
+	Active   bool   `json:"active"`
+	Username string `json:"username"`
+}
+
+type webFinger struct {
+	Subject string          `json:"subject"`
+	Aliases []string        `json:"aliases"`
+	Links   []webFingerLink `json:"links"`
+}
+
+type webFingerLink struct {
+	Rel  string `json:"rel"`
+	Type string `json:"type,omitempty"`
+	Href string `json:"href"`
+}
+
+// GetWebFinger resolves acct:user@host to the actor of the ticker on the host.
+func (h *handler) GetWebFinger(c *gin.Context) {
+	username, host, ok := strings.Cut(strings.TrimPrefix(c.Query("resource"), "acct:"), "@")
+	if !ok || username == "" || host == "" {
+		c.JSON(http.StatusBadRequest, response.ErrorResponse(response.CodeDefault, response.FormError))
+		return
+	}
+
+	var ticker storage.Ticker
+	var err error
+	for _, scheme := range []string{"https", "http"} {
+		ticker, err = h.storage.FindTickerByOrigin(scheme+"://"+host, storage.WithPreload())
+		if err == nil {
+			break
+		}
+	}
+	if err != nil || !ticker.ActivityPub.Active || !ticker.ActivityPub.Connected() || ticker.ActivityPub.Username != username {
+		c.JSON(http.StatusNotFound, response.ErrorResponse(response.CodeNotFound, response.TickerNotFound))
+		return
+	}
+
+	actorID := activitypub.ActorID(ticker)
+	c.Header("Content-Type", "application/jrd+json")
+	c.JSON(http.StatusOK, webFinger{
+		Subject: "acct:" + username + "@" + host,
+		Aliases: []string{actorID},
+		Links: []webFingerLink{
+			{Rel: "self", Type: activitypub.ContentType, Href: actorID},
+			{Rel: "http://webfinger.net/rel/profile-page", Type: "text/html", Href: ticker.ActivityPub.Origin},
+		},
+	})
+}
+
+func (h *handler) GetActivityPubActor(c *gin.Context) {
+	ticker, ok := h.activityPubTicker(c)
+	if !ok {
+		return
+	}
+
+	c.Header("Content-Type", activitypub.ContentType)
+	c.JSON(http.StatusOK, activitypub.NewActor(ticker))
+}
+
+// GetActivityPubOutbox returns the latest messages of the ticker as Create
+// activities. Older pages are reached with the pagination of the timeline.
+func (h *handler) GetActivityPubOutbox(c *gin.Context) {
+	ticker, ok := h.activityPubTicker(c)
+	if !ok {
+		return
+	}
+
+	pagination := pagination.NewPagination(c)
+	messages, err := h.storage.FindMessagesByTickerAndPagination(ticker, *pagination, storage.WithAttachments())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, response.ErrorResponse(response.CodeDefault, response.MessageFetchError))
+		return
+	}
+
+	items := make([]any, 0, len(messages))
+	for _, message := range messages {
+		if !message.SendsTo(activityPubBridge) {
+			continue
+		}
+		items = append(items, activitypub.NewCreate(ticker, message))
+	}
+
+	c.Header("Content-Type", activitypub.ContentType)
+	c.JSON(http.StatusOK, activitypub.OrderedCollection{
+		Context:      activitypub.Context,
+		ID:           activitypub.ActorID(ticker) + "/outbox",
+		Type:         "OrderedCollection",
+		TotalItems:   len(items),
+		OrderedItems: items,
+	})
+}
+
+// GetActivityPubFollowers only tells the number of followers, the followers
+// themselves are not public.
+func (h *handler) GetActivityPubFollowers(c *gin.Context) {
+	ticker, ok := h.activityPubTicker(c)
+	if !ok {
+		return
+	}
+
+	followers, err := h.storage.FindActivityPubFollowers(ticker)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, response.ErrorResponse(response.CodeDefault, response.StorageError))
+		return
+	}
+
+	c.Header("Content-Type", activitypub.ContentType)
+	c.JSON(http.StatusOK, activitypub.OrderedCollection{
+		Context:    activitypub.Context,
+		ID:         activitypub.ActorID(ticker) + "/followers",
+		Type:       "OrderedCollection",
+		TotalItems: len(followers),
+	})
+}
+
+func (h *handler) GetActivityPubNote(c *gin.Context) {
+	ticker, ok := h.activityPubTicker(c)
+	if !ok {
+		return
+	}
+
+	messageID, err := strconv.Atoi(c.Param("messageID"))
+	if err != nil {
+		c.JSON(http.StatusNotFound, response.ErrorResponse(response.CodeNotFound, response.MessageNotFound))
+		return
+	}
+
+	message, err := h.storage.FindMessage(ticker.ID, messageID, storage.WithAttachments())
+	if err != nil || !message.IsPublished() || !message.SendsTo(activityPubBridge) {
+		c.JSON(http.StatusNotFound, response.ErrorResponse(response.CodeNotFound, response.MessageNotFound))
+		return
+	}
+
+	note := activitypub.NewNote(ticker, message)
+	note.Context = activitypub.Context
+
+	c.Header("Content-Type", activitypub.ContentType)
+	c.JSON(http.StatusOK, note)
+}
+
+// PostActivityPubInbox handles the activities other servers deliver to the
+// ticker. Only follows and their undos are of interest, everything else is
+// accepted and dropped.
+func (h *handler) PostActivityPubInbox(c *gin.Context) {
+	ticker, ok := h.activityPubTicker(c)
+	if !ok {
+		return
+	}
+
+	body, err := io.ReadAll(io.LimitReader(c.Request.Body, 1<<20))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, response.ErrorResponse(response.CodeDefault, response.FormError))
+		return
+	}
+
+	var activity activitypub.Activity
+	if err := json.Unmarshal(body, &activity); err != nil || activity.Actor == "" {
+		c.JSON(http.StatusBadRequest, response.ErrorResponse(response.CodeDefault, response.FormError))
+		return
+	}
+
+	client, err := activitypub.NewClient(activitypub.KeyID(ticker), ticker.ActivityPub.PrivateKey)
+	if err != nil {
+		log.WithError(err).WithField("ticker_id", ticker.ID).Error("failed to create activitypub client")
+		c.JSON(http.StatusInternalServerError, response.ErrorResponse(response.CodeDefault, response.StorageError))
+		return
+	}
+
+	// The key is only fetched from the server of the actor, otherwise anyone
+	// could host an actor document claiming to be someone else.
+	var actor activitypub.Actor
+	lookup := func(keyID string) (*rsa.PublicKey, error) {
+		if !activitypub.SameHost(keyID, activity.Actor) {
+			return nil, errors.New("key is not on the server of the actor")
+		}
+
+		actor, err = client.FetchActor(c.Request.Context(), strings.Split(keyID, "#")[0])
+		if err != nil {
+			return nil, err
+		}
+		if actor.ID != activity.Actor || actor.PublicKey.ID != keyID || !activitypub.SameHost(actor.ID, keyID) {
+			return nil, errors.New("key does not belong to the actor of the activity")
+		}
+
+		return activitypub.ParsePublicKey(actor.PublicKey.PublicKeyPem)
+	}
+
+	if _, err := activitypub.Verify(signedRequest(c.Request, ticker), body, lookup); err != nil {
+		log.WithError(err).WithField("actor", activity.Actor).Debug("rejected activity")
+		c.JSON(http.StatusUnauthorized, response.ErrorResponse(response.CodeBadCredentials, response.Unauthorized))
+		return
+	}
+
+	switch activity.Type {
+	case "Follow":
+		if activity.Object != activitypub.ActorID(ticker) {
+			break
+		}
+
+		if !activitypub.SameHost(actor.Inbox, actor.ID) {
+			c.JSON(http.StatusBadRequest, response.ErrorResponse(response.CodeDefault, response.FormError))
+			return
+		}
+
+		follower := storage.ActivityPubFollower{TickerID: ticker.ID, Actor: actor.ID, Inbox: actor.Inbox}
+		if actor.Endpoints != nil && activitypub.SameHost(actor.Endpoints.SharedInbox, actor.ID) {
+			follower.SharedInbox = actor.Endpoints.SharedInbox
+		}
+		if err := h.storage.SaveActivityPubFollower(&follower); err != nil {
+			c.JSON(http.StatusInternalServerError, response.ErrorResponse(response.CodeDefault, response.StorageError))
+			return
+		}
+
+		if err := client.Post(c.Request.Context(), actor.Inbox, activitypub.NewAccept(ticker, activity)); err != nil {
+			log.WithError(err).WithField("actor", actor.ID).Error("failed to accept follow")
+		}
+	case "Undo":
+		object, ok := activity.Object.(map[string]any)
+		if !ok || object["type"] != "Follow" {
+			break
+		}
+
+		if err := h.storage.DeleteActivityPubFollower(ticker, activity.Actor); err != nil {
+			c.JSON(http.StatusInternalServerError, response.ErrorResponse(response.CodeDefault, response.StorageError))
+			return
+		}
+	}
+
+	c.JSON(http.StatusAccepted, response.SuccessResponse(map[string]interface{}{}))
+}
+
+// PutTickerActivityPub turns the ticker into an actor on the first website of
+// the ticker. The keys of the actor are kept when the settings change, so
+// followers are able to verify the activities of the actor.
+func (h *handler) PutTickerActivityPub(c *gin.Context) {
+	ticker, err := helper.Ticker(c)
+	if err != nil {
+		c.JSON(http.StatusNotFound, response.ErrorResponse(response.CodeDefault, response.TickerNotFound))
+		return
+	}
+
+	var body ActivityPubParam
+	err = c.Bind(&body)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, response.ErrorResponse(response.CodeNotFound, response.FormError))
+		return
+	}
+
+	if body.Username == "" {
+		body.Username = activityPubDefaultUsername
+	}
+	if !activityPubUsername.MatchString(body.Username) || len(ticker.Websites) == 0 {
+		c.JSON(http.StatusBadRequest, response.ErrorResponse(response.CodeDefault, response.ActivityPubInvalid))
+		return
+	}
+
+	if ticker.ActivityPub.PrivateKey == "" {
+		privateKey, publicKey, err := activitypub.GenerateKeys()
+		if err != nil {
+			log.WithError(err).Error("failed to generate activitypub keys")
+			c.JSON(http.StatusInternalServerError, response.ErrorResponse(response.CodeDefault, response.StorageError))
+			return
+		}
+
+		ticker.ActivityPub.PrivateKey = privateKey
+		ticker.ActivityPub.PublicKey = publicKey
+	}
+
+	ticker.ActivityPub.Active = body.Active
+	ticker.ActivityPub.Username = body.Username
+	ticker.ActivityPub.Origin = strings.TrimSuffix(ticker.Websites[0].Origin, "/")
+
+	err = h.storage.SaveTicker(&ticker)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, response.ErrorResponse(response.CodeDefault, response.StorageError))
+		return
+	}
+
+	c.JSON(http.StatusOK, response.SuccessResponse(map[string]interface{}{"ticker": response.TickerResponse(ticker, h.getBotUsername())}))
+}
+
+func (h *handler) DeleteTickerActivityPub(c *gin.Context) {
+	ticker, err := helper.Ticker(c)
+	if err != nil {
+		c.JSON(http.StatusNotFound, response.ErrorResponse(response.CodeDefault, response.TickerNotFound))
+		return
+	}
+
+	err = h.storage.DeleteActivityPub(&ticker)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, response.ErrorResponse(response.CodeDefault, response.StorageError))
+		return
+	}
+
+	c.JSON(http.StatusOK, response.SuccessResponse(map[string]interface{}{"ticker": response.TickerResponse(ticker, h.getBotUsername())}))
+}
+
+// activityPubTicker loads the ticker from the url, as long as it is an active
+// actor.
+func (h *handler) activityPubTicker(c *gin.Context) (storage.Ticker, bool) {
+	tickerID, err := strconv.Atoi(c.Param("tickerID"))
+	if err != nil {
+		c.JSON(http.StatusNotFound, response.ErrorResponse(response.CodeNotFound, response.TickerNotFound))
+		return storage.Ticker{}, false
+	}
+
+	ticker, err := h.storage.FindTickerByID(tickerID, storage.WithPreload())
+	if err != nil || !ticker.ActivityPub.Active || !ticker.ActivityPub.Connected() {
+		c.JSON(http.StatusNotFound, response.ErrorResponse(response.CodeNotFound, response.TickerNotFound))
+		return storage.Ticker{}, false
+	}
+
+	return ticker, true
+}
+
+// signedRequest returns the request as the remote server signed it. It was sent
+// to the inbox below the website of the ticker, which forwards it to the API
+// with the /v1 path instead of /api.
+func signedRequest(req *http.Request, ticker storage.Ticker) *http.Request {
+	signed := req.Clone(req.Context())
+	signed.URL = &url.URL{
+		Path:     activitypub.BasePath + strings.TrimPrefix(req.URL.Path, "/v1/activitypub"),
+		RawQuery: req.URL.RawQuery,
+	}
+	if origin, err := url.Parse(ticker.ActivityPub.Origin); err == nil {
+		signed.Host = origin.Host
+	}
+
+	return signed
+}
@@ -0,0 +1,482 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/h2non/gock"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+	"github.com/systemli/ticker/internal/activitypub"
+	"github.com/systemli/ticker/internal/api/response"
+	"github.com/systemli/ticker/internal/config"
+	"github.com/systemli/ticker/internal/storage"
+)
+
+type ActivityPubTestSuite struct {
+	w      *httptest.ResponseRecorder
+	ctx    *gin.Context
+	store  *storage.MockStorage
+	cfg    config.Config
+	ticker storage.Ticker
+	remote struct{ privateKey, publicKey string }
+	suite.Suite
+}
+
+func (s *ActivityPubTestSuite) SetupSuite() {
+	privateKey, publicKey, err := activitypub.GenerateKeys()
+	s.Require().NoError(err)
+	s.ticker = storage.Ticker{
+		ID:       1,
+		Title:    "Demo",
+		Websites: []storage.TickerWebsite{{Origin: "https://demo.example.org"}},
+		ActivityPub: storage.TickerActivityPub{
+			Active:     true,
+			Username:   "ticker",
+			Origin:     "https://demo.example.org",
+			PrivateKey: privateKey,
+			PublicKey:  publicKey,
+		},
+	}
+
+	s.remote.privateKey, s.remote.publicKey, err = activitypub.GenerateKeys()
+	s.Require().NoError(err)
+
+	gock.InterceptClient(activitypub.HTTPClient)
+}
+
+func (s *ActivityPubTestSuite) TearDownSuite() {
+	gock.RestoreClient(activitypub.HTTPClient)
+}
+
+func (s *ActivityPubTestSuite) SetupTest() {
+	gin.SetMode(gin.TestMode)
+	gock.DisableNetworking()
+	defer gock.Off()
+}
+
+func (s *ActivityPubTestSuite) Run(name string, subtest func()) {
+	s.T().Run(name, func(t *testing.T) {
+		s.w = httptest.NewRecorder()
+		s.ctx, _ = gin.CreateTestContext(s.w)
+		s.ctx.Request = httptest.NewRequest(http.MethodGet, "/", nil)
+		s.ctx.Params = gin.Params{{Key: "tickerID", Value: "1"}}
+		s.store = &storage.MockStorage{}
+		s.store.On("GetTelegramSettings").Return(storage.TelegramSettings{}).Maybe()
+		s.cfg = config.LoadConfig("")
+
+		subtest()
+	})
+}
+
+func (s *ActivityPubTestSuite) TestGetWebFinger() {
+	s.Run("when resource is missing", func() {
+		h := s.handler()
+		h.GetWebFinger(s.ctx)
+
+		s.Equal(http.StatusBadRequest, s.w.Code)
+	})
+
+	s.Run("when ticker is not found", func() {
+		s.ctx.Request = httptest.NewRequest(http.MethodGet, "/.well-known/webfinger?resource=acct:ticker@unknown.example.org", nil)
+		s.store.On("FindTickerByOrigin", "https://unknown.example.org", mock.Anything).Return(storage.Ticker{}, errors.New("not found")).Once()
+		s.store.On("FindTickerByOrigin", "http://unknown.example.org", mock.Anything).Return(storage.Ticker{}, errors.New("not found")).Once()
+
+		h := s.handler()
+		h.GetWebFinger(s.ctx)
+
+		s.Equal(http.StatusNotFound, s.w.Code)
+		s.store.AssertExpectations(s.T())
+	})
+
+	s.Run("when username does not match", func() {
+		s.ctx.Request = httptest.NewRequest(http.MethodGet, "/.well-known/webfinger?resource=acct:other@demo.example.org", nil)
+		s.store.On("FindTickerByOrigin", "https://demo.example.org", mock.Anything).Return(s.ticker, nil).Once()
+
+		h := s.handler()
+		h.GetWebFinger(s.ctx)
+
+		s.Equal(http.StatusNotFound, s.w.Code)
+		s.store.AssertExpectations(s.T())
+	})
+
+	s.Run("when ticker is found", func() {
+		s.ctx.Request = httptest.NewRequest(http.MethodGet, "/.well-known/webfinger?resource=acct:ticker@demo.example.org", nil)
+		s.store.On("FindTickerByOrigin", "https://demo.example.org", mock.Anything).Return(s.ticker, nil).Once()
+
+		h := s.handler()
+		h.GetWebFinger(s.ctx)
+
+		s.Equal(http.StatusOK, s.w.Code)
+		s.Equal("application/jrd+json", s.w.Header().Get("Content-Type"))
+		s.Contains(s.w.Body.String(), `"href":"https://demo.example.org/api/activitypub/tickers/1"`)
+		s.store.AssertExpectations(s.T())
+	})
+}
+
+func (s *ActivityPubTestSuite) TestGetActivityPubActor() {
+	s.Run("when actor is not active", func() {
+		s.store.On("FindTickerByID", 1, mock.Anything).Return(storage.Ticker{ID: 1}, nil).Once()
+
+		h := s.handler()
+		h.GetActivityPubActor(s.ctx)
+
+		s.Equal(http.StatusNotFound, s.w.Code)
+		s.store.AssertExpectations(s.T())
+	})
+
+	s.Run("when actor is active", func() {
+		s.store.On("FindTickerByID", 1, mock.Anything).Return(s.ticker, nil).Once()
+
+		h := s.handler()
+		h.GetActivityPubActor(s.ctx)
+
+		var actor activitypub.Actor
+		s.Equal(http.StatusOK, s.w.Code)
+		s.Equal(activitypub.ContentType, s.w.Header().Get("Content-Type"))
+		s.NoError(json.Unmarshal(s.w.Body.Bytes(), &actor))
+		s.Equal("ticker", actor.PreferredUsername)
+		s.Equal(s.ticker.ActivityPub.PublicKey, actor.PublicKey.PublicKeyPem)
+		s.store.AssertExpectations(s.T())
+	})
+}
+
+func (s *ActivityPubTestSuite) TestGetActivityPubOutbox() {
+	s.Run("when fetching messages fails", func() {
+		s.store.On("FindTickerByID", 1, mock.Anything).Return(s.ticker, nil).Once()
+		s.store.On("FindMessagesByTickerAndPagination", mock.Anything, mock.Anything, mock.Anything).Return(nil, errors.New("storage error")).Once()
+
+		h := s.handler()
+		h.GetActivityPubOutbox(s.ctx)
+
+		s.Equal(http.StatusInternalServerError, s.w.Code)
+		s.store.AssertExpectations(s.T())
+	})
+
+	s.Run("when messages are found", func() {
+		s.store.On("FindTickerByID", 1, mock.Anything).Return(s.ticker, nil).Once()
+		s.store.On("FindMessagesByTickerAndPagination", mock.Anything, mock.Anything, mock.Anything).Return([]storage.Message{
+			{ID: 2, Text: "Hello World"},
+			{ID: 1, Text: "Telegram only", Bridges: []string{"telegram"}},
+		}, nil).Once()
+
+		h := s.handler()
+		h.GetActivityPubOutbox(s.ctx)
+
+		var collection activitypub.OrderedCollection
+		s.Equal(http.StatusOK, s.w.Code)
+		s.NoError(json.Unmarshal(s.w.Body.Bytes(), &collection))
+		s.Equal(1, collection.TotalItems)
+		s.store.AssertExpectations(s.T())
+	})
+}
+
+func (s *ActivityPubTestSuite) TestGetActivityPubFollowers() {
+	s.Run("when followers are found", func() {
+		s.store.On("FindTickerByID", 1, mock.Anything).Return(s.ticker, nil).Once()
+		s.store.On("FindActivityPubFollowers", mock.Anything).Return([]storage.ActivityPubFollower{{Actor: "https://social.example.org/users/alice"}}, nil).Once()
+
+		h := s.handler()
+		h.GetActivityPubFollowers(s.ctx)
+
+		s.Equal(http.StatusOK, s.w.Code)
+		s.Contains(s.w.Body.String(), `"totalItems":1`)
+		s.NotContains(s.w.Body.String(), "alice")
+		s.store.AssertExpectations(s.T())
+	})
+}
+
+func (s *ActivityPubTestSuite) TestGetActivityPubNote() {
+	s.Run("when message is a draft", func() {
+		s.ctx.Params = append(s.ctx.Params, gin.Param{Key: "messageID", Value: "2"})
+		s.store.On("FindTickerByID", 1, mock.Anything).Return(s.ticker, nil).Once()
+		s.store.On("FindMessage", 1, 2, mock.Anything).Return(storage.Message{ID: 2, Draft: true}, nil).Once()
+
+		h := s.handler()
+		h.GetActivityPubNote(s.ctx)
+
+		s.Equal(http.StatusNotFound, s.w.Code)
+		s.store.AssertExpectations(s.T())
+	})
+
+	s.Run("when message is published", func() {
+		s.ctx.Params = append(s.ctx.Params, gin.Param{Key: "messageID", Value: "2"})
+		s.store.On("FindTickerByID", 1, mock.Anything).Return(s.ticker, nil).Once()
+		s.store.On("FindMessage", 1, 2, mock.Anything).Return(storage.Message{ID: 2, Text: "Hello World"}, nil).Once()
+
+		h := s.handler()
+		h.GetActivityPubNote(s.ctx)
+
+		var note activitypub.Note
+		s.Equal(http.StatusOK, s.w.Code)
+		s.NoError(json.Unmarshal(s.w.Body.Bytes(), &note))
+		s.Equal("<p>Hello World</p>", note.Content)
+		s.store.AssertExpectations(s.T())
+	})
+}
+
+func (s *ActivityPubTestSuite) TestPostActivityPubInbox() {
+	follow := `{"id":"https://social.example.org/follows/1","type":"Follow","actor":"https://social.example.org/users/alice","object":"https://demo.example.org/api/activitypub/tickers/1"}`
+
+	s.Run("when activity is not signed", func() {
+		s.ctx.Request = httptest.NewRequest(http.MethodPost, "/v1/activitypub/tickers/1/inbox", strings.NewReader(follow))
+		s.store.On("FindTickerByID", 1, mock.Anything).Return(s.ticker, nil).Once()
+
+		h := s.handler()
+		h.PostActivityPubInbox(s.ctx)
+
+		s.Equal(http.StatusUnauthorized, s.w.Code)
+		s.store.AssertExpectations(s.T())
+	})
+
+	s.Run("when key belongs to another actor", func() {
+		s.ctx.Request = s.signedInboxRequest(follow, "https://social.example.org/users/mallory#main-key")
+		s.store.On("FindTickerByID", 1, mock.Anything).Return(s.ticker, nil).Once()
+		gock.New("https://social.example.org").
+			Get("/users/mallory").
+			Reply(200).
+			JSON(s.remoteActor("https://social.example.org/users/mallory"))
+
+		h := s.handler()
+		h.PostActivityPubInbox(s.ctx)
+
+		s.Equal(http.StatusUnauthorized, s.w.Code)
+		s.True(gock.IsDone())
+		s.store.AssertExpectations(s.T())
+	})
+
+	s.Run("when key is on another server", func() {
+		s.ctx.Request = s.signedInboxRequest(follow, "https://evil.example.org/users/alice#main-key")
+		s.store.On("FindTickerByID", 1, mock.Anything).Return(s.ticker, nil).Once()
+
+		h := s.handler()
+		h.PostActivityPubInbox(s.ctx)
+
+		s.Equal(http.StatusUnauthorized, s.w.Code)
+		s.True(gock.IsDone())
+		s.store.AssertExpectations(s.T())
+	})
+
+	s.Run("when key is on an internal address", func() {
+		internal := `{"id":"https://127.0.0.1/follows/1","type":"Follow","actor":"https://127.0.0.1/users/alice","object":"https://demo.example.org/api/activitypub/tickers/1"}`
+		s.ctx.Request = s.signedInboxRequest(internal, "https://127.0.0.1/users/alice#main-key")
+		s.store.On("FindTickerByID", 1, mock.Anything).Return(s.ticker, nil).Once()
+
+		h := s.handler()
+		h.PostActivityPubInbox(s.ctx)
+
+		s.Equal(http.StatusUnauthorized, s.w.Code)
+		s.store.AssertExpectations(s.T())
+	})
+
+	s.Run("when inbox is on another server", func() {
+		s.ctx.Request = s.signedInboxRequest(follow, "https://social.example.org/users/alice#main-key")
+		s.store.On("FindTickerByID", 1, mock.Anything).Return(s.ticker, nil).Once()
+		actor := s.remoteActor("https://social.example.org/users/alice")
+		actor.Inbox = "https://evil.example.org/inbox"
+		gock.New("https://social.example.org").
+			Get("/users/alice").
+			Reply(200).
+			JSON(actor)
+
+		h := s.handler()
+		h.PostActivityPubInbox(s.ctx)
+
+		s.Equal(http.StatusBadRequest, s.w.Code)
+		s.True(gock.IsDone())
+		s.store.AssertNotCalled(s.T(), "SaveActivityPubFollower", mock.Anything)
+	})
+
+	s.Run("when actor follows", func() {
+		s.ctx.Request = s.signedInboxRequest(follow, "https://social.example.org/users/alice#main-key")
+		s.store.On("FindTickerByID", 1, mock.Anything).Return(s.ticker, nil).Once()
+		s.store.On("SaveActivityPubFollower", mock.MatchedBy(func(f *storage.ActivityPubFollower) bool {
+			return f.TickerID == 1 && f.Actor == "https://social.example.org/users/alice" && f.SharedInbox == "https://social.example.org/inbox"
+		})).Return(nil).Once()
+		gock.New("https://social.example.org").
+			Get("/users/alice").
+			Reply(200).
+			JSON(s.remoteActor("https://social.example.org/users/alice"))
+		gock.New("https://social.example.org").
+			Post("/users/alice/inbox").
+			HeaderPresent("Signature").
+			Reply(202)
+
+		h := s.handler()
+		h.PostActivityPubInbox(s.ctx)
+
+		s.Equal(http.StatusAccepted, s.w.Code)
+		s.True(gock.IsDone())
+		s.store.AssertExpectations(s.T())
+	})
+
+	s.Run("when actor unfollows", func() {
+		undo := `{"id":"https://social.example.org/undos/1","type":"Undo","actor":"https://social.example.org/users/alice","object":` + follow + `}`
+		s.ctx.Request = s.signedInboxRequest(undo, "https://social.example.org/users/alice#main-key")
+		s.store.On("FindTickerByID", 1, mock.Anything).Return(s.ticker, nil).Once()
+		s.store.On("DeleteActivityPubFollower", mock.Anything, "https://social.example.org/users/alice").Return(nil).Once()
+		gock.New("https://social.example.org").
+			Get("/users/alice").
+			Reply(200).
+			JSON(s.remoteActor("https://social.example.org/users/alice"))
+
+		h := s.handler()
+		h.PostActivityPubInbox(s.ctx)
+
+		s.Equal(http.StatusAccepted, s.w.Code)
+		s.True(gock.IsDone())
+		s.store.AssertExpectations(s.T())
+	})
+}
+
+func (s *ActivityPubTestSuite) TestPutTickerActivityPub() {
+	s.Run("when ticker not found", func() {
+		h := s.handler()
+		h.PutTickerActivityPub(s.ctx)
+
+		s.Equal(http.StatusNotFound, s.w.Code)
+	})
+
+	s.Run("when ticker has no website", func() {
+		s.ctx.Set("ticker", storage.Ticker{ID: 1})
+		s.ctx.Request = httptest.NewRequest(http.MethodPut, "/v1/admin/tickers/1/activitypub", strings.NewReader(`{"active":true}`))
+		s.ctx.Request.Header.Add("Content-Type", "application/json")
+
+		h := s.handler()
+		h.PutTickerActivityPub(s.ctx)
+
+		s.Equal(http.StatusBadRequest, s.w.Code)
+		s.Contains(s.w.Body.String(), response.ActivityPubInvalid)
+	})
+
+	s.Run("when username is invalid", func() {
+		s.ctx.Set("ticker", storage.Ticker{ID: 1, Websites: s.ticker.Websites})
+		s.ctx.Request = httptest.NewRequest(http.MethodPut, "/v1/admin/tickers/1/activitypub", strings.NewReader(`{"active":true,"username":"Not Valid"}`))
+		s.ctx.Request.Header.Add("Content-Type", "application/json")
+
+		h := s.handler()
+		h.PutTickerActivityPub(s.ctx)
+
+		s.Equal(http.StatusBadRequest, s.w.Code)
+		s.Contains(s.w.Body.String(), response.ActivityPubInvalid)
+	})
+
+	s.Run("when storage returns error", func() {
+		s.ctx.Set("ticker", storage.Ticker{ID: 1, Websites: s.ticker.Websites})
+		s.ctx.Request = httptest.NewRequest(http.MethodPut, "/v1/admin/tickers/1/activitypub", strings.NewReader(`{"active":true}`))
+		s.ctx.Request.Header.Add("Content-Type", "application/json")
+		s.store.On("SaveTicker", mock.Anything).Return(errors.New("storage error")).Once()
+
+		h := s.handler()
+		h.PutTickerActivityPub(s.ctx)
+
+		s.Equal(http.StatusBadRequest, s.w.Code)
+		s.store.AssertExpectations(s.T())
+	})
+
+	s.Run("when actor is created", func() {
+		s.ctx.Set("ticker", storage.Ticker{ID: 1, Websites: []storage.TickerWebsite{{Origin: "https://demo.example.org/"}}})
+		s.ctx.Request = httptest.NewRequest(http.MethodPut, "/v1/admin/tickers/1/activitypub", strings.NewReader(`{"active":true}`))
+		s.ctx.Request.Header.Add("Content-Type", "application/json")
+		s.store.On("SaveTicker", mock.MatchedBy(func(t *storage.Ticker) bool {
+			return t.ActivityPub.Active && t.ActivityPub.Username == "ticker" && t.ActivityPub.Origin == "https://demo.example.org" && t.ActivityPub.PrivateKey != ""
+		})).Return(nil).Once()
+
+		h := s.handler()
+		h.PutTickerActivityPub(s.ctx)
+
+		s.Equal(http.StatusOK, s.w.Code)
+		s.Contains(s.w.Body.String(), `"handle":"@ticker@demo.example.org"`)
+		s.store.AssertExpectations(s.T())
+	})
+
+	s.Run("when keys exist", func() {
+		s.ctx.Set("ticker", s.ticker)
+		s.ctx.Request = httptest.NewRequest(http.MethodPut, "/v1/admin/tickers/1/activitypub", strings.NewReader(`{"active":false,"username":"news"}`))
+		s.ctx.Request.Header.Add("Content-Type", "application/json")
+		s.store.On("SaveTicker", mock.MatchedBy(func(t *storage.Ticker) bool {
+			return !t.ActivityPub.Active && t.ActivityPub.Username == "news" && t.ActivityPub.PrivateKey == s.ticker.ActivityPub.PrivateKey
+		})).Return(nil).Once()
+
+		h := s.handler()
+		h.PutTickerActivityPub(s.ctx)
+
+		s.Equal(http.StatusOK, s.w.Code)
+		s.store.AssertExpectations(s.T())
+	})
+}
+
+func (s *ActivityPubTestSuite) TestDeleteTickerActivityPub() {
+	s.Run("when ticker not found", func() {
+		h := s.handler()
+		h.DeleteTickerActivityPub(s.ctx)
+
+		s.Equal(http.StatusNotFound, s.w.Code)
+	})
+
+	s.Run("when storage returns error", func() {
+		s.ctx.Set("ticker", s.ticker)
+		s.store.On("DeleteActivityPub", mock.Anything).Return(errors.New("storage error")).Once()
+
+		h := s.handler()
+		h.DeleteTickerActivityPub(s.ctx)
+
+		s.Equal(http.StatusBadRequest, s.w.Code)
+		s.store.AssertExpectations(s.T())
+	})
+
+	s.Run("when actor is deleted", func() {
+		s.ctx.Set("ticker", s.ticker)
+		s.store.On("DeleteActivityPub", mock.Anything).Return(nil).Once()
+
+		h := s.handler()
+		h.DeleteTickerActivityPub(s.ctx)
+
+		s.Equal(http.StatusOK, s.w.Code)
+		s.store.AssertExpectations(s.T())
+	})
+}
+
+// signedInboxRequest returns the request as it reaches the API, signed by the
+// remote server for the public address of the inbox.
+func (s *ActivityPubTestSuite) signedInboxRequest(body, keyID string) *http.Request {
+	key, err := activitypub.ParsePrivateKey(s.remote.privateKey)
+	s.Require().NoError(err)
+
+	public := httptest.NewRequest(http.MethodPost, "https://demo.example.org/api/activitypub/tickers/1/inbox", nil)
+	s.Require().NoError(activitypub.Sign(public, []byte(body), keyID, key))
+
+	req := httptest.NewRequest(http.MethodPost, "/v1/activitypub/tickers/1/inbox", strings.NewReader(body))
+	req.Host = "api.example.org"
+	for _, header := range []string{"Date", "Digest", "Signature"} {
+		req.Header.Set(header, public.Header.Get(header))
+	}
+
+	return req
+}
+
+func (s *ActivityPubTestSuite) remoteActor(id string) activitypub.Actor {
+	return activitypub.Actor{
+		ID:        id,
+		Type:      "Person",
+		Inbox:     id + "/inbox",
+		Endpoints: &activitypub.Endpoints{SharedInbox: "https://social.example.org/inbox"},
+		PublicKey: activitypub.PublicKey{ID: id + "#main-key", Owner: id, PublicKeyPem: s.remote.publicKey},
+	}
+}
+
+func (s *ActivityPubTestSuite) handler() handler {
+	return handler{
+		storage: s.store,
+		config:  s.cfg,
+	}
+}
+
+func TestActivityPubTestSuite(t *testing.T) {
+	suite.Run(t, new(ActivityPubTestSuite))
+}
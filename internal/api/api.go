@@ -20,6 +20,8 @@ import (
 	"github.com/systemli/ticker/internal/cache"
 	"github.com/systemli/ticker/internal/config"
 	"github.com/systemli/ticker/internal/logger"
+	"github.com/systemli/ticker/internal/passkey"
+	"github.com/systemli/ticker/internal/sso"
 	"github.com/systemli/ticker/internal/storage"
 )
 
@@ -27,8 +29,14 @@ var log = logger.GetWithPackage("api")
 
 // Server wraps the gin engine and realtime engine for graceful shutdown
 type Server struct {
-	Router   *gin.Engine
-	Realtime *realtime.Engine
+	Router        *gin.Engine
+	Realtime      *realtime.Engine
+	Scheduler     *Scheduler
+	Outbox        *bridge.Outbox
+	Digest        *bridge.EmailDigest
+	TelegramInbox *TelegramInbox
+	SignalInbox   *SignalInbox
+	MailInbox     *MailInbox
 }
 
 type handler struct {
@@ -37,6 +45,10 @@ type handler struct {
 	bridges  bridge.Bridges
 	cache    *cache.Cache
 	realtime *realtime.Engine
+	// passkeys is nil unless WebAuthn is configured.
+	passkeys *passkey.RelyingParty
+	// sso is nil unless an identity provider is configured.
+	sso *sso.Provider
 }
 
 func API(config config.Config, store storage.Storage) *Server {
@@ -45,14 +57,29 @@ func API(config config.Config, store storage.Storage) *Server {
 	ws := realtime.New()
 	go ws.Run()
 
+	bridges := bridge.RegisterBridges(config, store)
+
 	handler := handler{
 		config:   config,
 		storage:  store,
-		bridges:  bridge.RegisterBridges(config, store),
+		bridges:  bridges,
 		cache:    inMemoryCache,
 		realtime: ws,
 	}
 
+	if config.WebAuthn.Enabled() {
+		passkeys, err := passkey.New(config.WebAuthn, store)
+		if err != nil {
+			log.WithError(err).Error("failed to set up passkeys")
+		} else {
+			handler.passkeys = passkeys
+		}
+	}
+
+	if config.OIDC.Enabled() {
+		handler.sso = sso.New(config.OIDC, store)
+	}
+
 	gin.SetMode(gin.ReleaseMode)
 
 	r := gin.New()
@@ -63,7 +90,7 @@ func API(config config.Config, store storage.Storage) *Server {
 	r.Use(limits.RequestSizeLimiter(1024 * 1024 * 10))
 
 	// the jwt middleware
-	authMiddleware := auth.AuthMiddleware(store, config.Secret)
+	authMiddleware := auth.AuthMiddleware(store, config.Secret, handler.passkeys, handler.sso)
 
 	admin := r.Group("/v1/admin")
 	{
@@ -75,65 +102,148 @@ func API(config config.Config, store storage.Storage) *Server {
 
 		admin.GET("/features", handler.GetFeatures)
 
+		// Until they have a second factor, users can only set it up when the
+		// security settings require one. Routes below are closed to them.
+		admin.GET(`/users/me/totp`, handler.GetTOTP)
+		admin.POST(`/users/me/totp`, handler.PostTOTP)
+		admin.PUT(`/users/me/totp`, handler.PutTOTP)
+		admin.DELETE(`/users/me/totp`, handler.DeleteTOTP)
+		admin.POST(`/users/me/totp/recovery_codes`, handler.PostRecoveryCodes)
+		admin.GET(`/users/me/passkeys`, handler.GetPasskeys)
+		admin.POST(`/users/me/passkeys/options`, handler.PostPasskeyOptions)
+		admin.POST(`/users/me/passkeys`, handler.PostPasskey)
+		admin.DELETE(`/users/me/passkeys/:passkeyID`, handler.DeletePasskey)
+		admin.Use(user.NeedTwoFactor(store, handler.sso != nil))
+
 		admin.GET(`/tickers`, handler.GetTickers)
-		admin.GET(`/tickers/:tickerID`, ticker.PrefetchTicker(store, storage.WithPreload()), handler.GetTicker)
 		admin.POST(`/tickers`, user.NeedAdmin(), handler.PostTicker)
-		admin.PUT(`/tickers/:tickerID`, ticker.PrefetchTicker(store, storage.WithPreload()), handler.PutTicker)
-		admin.DELETE(`/tickers/:tickerID/websites`, ticker.PrefetchTicker(store, storage.WithPreload()), handler.DeleteTickerWebsites)
-		admin.PUT(`/tickers/:tickerID/websites`, ticker.PrefetchTicker(store, storage.WithPreload()), handler.PutTickerWebsites)
-		admin.PUT(`/tickers/:tickerID/telegram`, ticker.PrefetchTicker(store, storage.WithPreload()), handler.PutTickerTelegram)
-		admin.DELETE(`/tickers/:tickerID/telegram`, ticker.PrefetchTicker(store, storage.WithPreload()), handler.DeleteTickerTelegram)
-		admin.PUT(`/tickers/:tickerID/mastodon`, ticker.PrefetchTicker(store, storage.WithPreload()), handler.PutTickerMastodon)
-		admin.DELETE(`/tickers/:tickerID/mastodon`, ticker.PrefetchTicker(store, storage.WithPreload()), handler.DeleteTickerMastodon)
-		admin.PUT(`/tickers/:tickerID/bluesky`, ticker.PrefetchTicker(store, storage.WithPreload()), handler.PutTickerBluesky)
-		admin.DELETE(`/tickers/:tickerID/bluesky`, ticker.PrefetchTicker(store, storage.WithPreload()), handler.DeleteTickerBluesky)
-		admin.PUT(`/tickers/:tickerID/signal_group`, ticker.PrefetchTicker(store, storage.WithPreload()), handler.PutTickerSignalGroup)
-		admin.DELETE(`/tickers/:tickerID/signal_group`, ticker.PrefetchTicker(store, storage.WithPreload()), handler.DeleteTickerSignalGroup)
-		admin.PUT(`/tickers/:tickerID/signal_group/admin`, ticker.PrefetchTicker(store, storage.WithPreload()), handler.PutTickerSignalGroupAdmin)
+		admin.PUT(`/tickers/:tickerID`, ticker.PrefetchTicker(store, storage.WithPreload()), ticker.NeedRole(storage.TickerRoleEditor), handler.PutTicker)
+		admin.DELETE(`/tickers/:tickerID/websites`, ticker.PrefetchTicker(store, storage.WithPreload()), ticker.NeedRole(storage.TickerRoleOwner), handler.DeleteTickerWebsites)
+		admin.PUT(`/tickers/:tickerID/websites`, ticker.PrefetchTicker(store, storage.WithPreload()), ticker.NeedRole(storage.TickerRoleOwner), handler.PutTickerWebsites)
+		admin.PUT(`/tickers/:tickerID/telegram`, ticker.PrefetchTicker(store, storage.WithPreload()), ticker.NeedRole(storage.TickerRoleOwner), handler.PutTickerTelegram)
+		admin.DELETE(`/tickers/:tickerID/telegram`, ticker.PrefetchTicker(store, storage.WithPreload()), ticker.NeedRole(storage.TickerRoleOwner), handler.DeleteTickerTelegram)
+		admin.PUT(`/tickers/:tickerID/mastodon`, ticker.PrefetchTicker(store, storage.WithPreload()), ticker.NeedRole(storage.TickerRoleOwner), handler.PutTickerMastodon)
+		admin.DELETE(`/tickers/:tickerID/mastodon`, ticker.PrefetchTicker(store, storage.WithPreload()), ticker.NeedRole(storage.TickerRoleOwner), handler.DeleteTickerMastodon)
+		admin.PUT(`/tickers/:tickerID/bluesky`, ticker.PrefetchTicker(store, storage.WithPreload()), ticker.NeedRole(storage.TickerRoleOwner), handler.PutTickerBluesky)
+		admin.DELETE(`/tickers/:tickerID/bluesky`, ticker.PrefetchTicker(store, storage.WithPreload()), ticker.NeedRole(storage.TickerRoleOwner), handler.DeleteTickerBluesky)
+		admin.PUT(`/tickers/:tickerID/webhooks`, ticker.PrefetchTicker(store, storage.WithPreload()), ticker.NeedRole(storage.TickerRoleOwner), handler.PutTickerWebhooks)
+		admin.DELETE(`/tickers/:tickerID/webhooks`, ticker.PrefetchTicker(store, storage.WithPreload()), ticker.NeedRole(storage.TickerRoleOwner), handler.DeleteTickerWebhooks)
+		admin.PUT(`/tickers/:tickerID/matrix`, ticker.PrefetchTicker(store, storage.WithPreload()), ticker.NeedRole(storage.TickerRoleOwner), handler.PutTickerMatrix)
+		admin.DELETE(`/tickers/:tickerID/matrix`, ticker.PrefetchTicker(store, storage.WithPreload()), ticker.NeedRole(storage.TickerRoleOwner), handler.DeleteTickerMatrix)
+		admin.PUT(`/tickers/:tickerID/activitypub`, ticker.PrefetchTicker(store, storage.WithPreload()), ticker.NeedRole(storage.TickerRoleOwner), handler.PutTickerActivityPub)
+		admin.DELETE(`/tickers/:tickerID/activitypub`, ticker.PrefetchTicker(store, storage.WithPreload()), ticker.NeedRole(storage.TickerRoleOwner), handler.DeleteTickerActivityPub)
+		admin.PUT(`/tickers/:tickerID/webpush`, ticker.PrefetchTicker(store, storage.WithPreload()), ticker.NeedRole(storage.TickerRoleOwner), handler.PutTickerWebPush)
+		admin.DELETE(`/tickers/:tickerID/webpush`, ticker.PrefetchTicker(store, storage.WithPreload()), ticker.NeedRole(storage.TickerRoleOwner), handler.DeleteTickerWebPush)
+		admin.PUT(`/tickers/:tickerID/email`, ticker.PrefetchTicker(store, storage.WithPreload()), ticker.NeedRole(storage.TickerRoleOwner), handler.PutTickerEmail)
+		admin.DELETE(`/tickers/:tickerID/email`, ticker.PrefetchTicker(store, storage.WithPreload()), ticker.NeedRole(storage.TickerRoleOwner), handler.DeleteTickerEmail)
+		admin.PUT(`/tickers/:tickerID/mailbox`, ticker.PrefetchTicker(store, storage.WithPreload()), ticker.NeedRole(storage.TickerRoleOwner), handler.PutTickerMailbox)
+		admin.DELETE(`/tickers/:tickerID/mailbox`, ticker.PrefetchTicker(store, storage.WithPreload()), ticker.NeedRole(storage.TickerRoleOwner), handler.DeleteTickerMailbox)
+		admin.PUT(`/tickers/:tickerID/signal_group`, ticker.PrefetchTicker(store, storage.WithPreload()), ticker.NeedRole(storage.TickerRoleOwner), handler.PutTickerSignalGroup)
+		admin.DELETE(`/tickers/:tickerID/signal_group`, ticker.PrefetchTicker(store, storage.WithPreload()), ticker.NeedRole(storage.TickerRoleOwner), handler.DeleteTickerSignalGroup)
+		admin.PUT(`/tickers/:tickerID/signal_group/admin`, ticker.PrefetchTicker(store, storage.WithPreload()), ticker.NeedRole(storage.TickerRoleOwner), handler.PutTickerSignalGroupAdmin)
+		admin.PUT(`/tickers/:tickerID/signal_senders`, ticker.PrefetchTicker(store, storage.WithPreload()), ticker.NeedRole(storage.TickerRoleOwner), handler.PutTickerSignalSenders)
+		admin.DELETE(`/tickers/:tickerID/signal_senders`, ticker.PrefetchTicker(store, storage.WithPreload()), ticker.NeedRole(storage.TickerRoleOwner), handler.DeleteTickerSignalSenders)
 		admin.DELETE(`/tickers/:tickerID`, user.NeedAdmin(), ticker.PrefetchTicker(store), handler.DeleteTicker)
-		admin.PUT(`/tickers/:tickerID/reset`, ticker.PrefetchTicker(store, storage.WithPreload()), ticker.PrefetchTicker(store), handler.ResetTicker)
+		admin.PUT(`/tickers/:tickerID/reset`, ticker.PrefetchTicker(store, storage.WithPreload()), ticker.NeedRole(storage.TickerRoleOwner), handler.ResetTicker)
 		admin.GET(`/tickers/:tickerID/users`, ticker.PrefetchTicker(store), handler.GetTickerUsers)
-		admin.PUT(`/tickers/:tickerID/users`, user.NeedAdmin(), ticker.PrefetchTicker(store), handler.PutTickerUsers)
-		admin.DELETE(`/tickers/:tickerID/users/:userID`, user.NeedAdmin(), ticker.PrefetchTicker(store), handler.DeleteTickerUser)
-
-		admin.GET(`/tickers/:tickerID/messages`, ticker.PrefetchTicker(store, storage.WithPreload()), handler.GetMessages)
-		admin.GET(`/tickers/:tickerID/messages/:messageID`, ticker.PrefetchTicker(store, storage.WithPreload()), message.PrefetchMessage(store), handler.GetMessage)
-		admin.POST(`/tickers/:tickerID/messages`, ticker.PrefetchTicker(store, storage.WithPreload()), handler.PostMessage)
-		admin.DELETE(`/tickers/:tickerID/messages/:messageID`, ticker.PrefetchTicker(store, storage.WithPreload()), message.PrefetchMessage(store), handler.DeleteMessage)
-
-		admin.POST(`/upload`, handler.PostUpload)
+		admin.PUT(`/tickers/:tickerID/users`, ticker.PrefetchTicker(store), ticker.NeedRole(storage.TickerRoleOwner), handler.PutTickerUsers)
+		admin.DELETE(`/tickers/:tickerID/users/:userID`, ticker.PrefetchTicker(store), ticker.NeedRole(storage.TickerRoleOwner), handler.DeleteTickerUser)
+
+		admin.GET(`/tickers/:tickerID/messages/scheduled`, ticker.PrefetchTicker(store, storage.WithPreload()), ticker.NeedRole(storage.TickerRoleContributor), handler.GetScheduledMessages)
+		admin.GET(`/tickers/:tickerID/messages/drafts`, ticker.PrefetchTicker(store, storage.WithPreload()), ticker.NeedRole(storage.TickerRoleContributor), handler.GetDraftMessages)
+		admin.GET(`/tickers/:tickerID/messages/reviews`, ticker.PrefetchTicker(store, storage.WithPreload()), ticker.NeedRole(storage.TickerRoleEditor), handler.GetPendingReviewMessages)
+		admin.GET(`/tickers/:tickerID/tags`, ticker.PrefetchTicker(store, storage.WithPreload()), ticker.NeedRole(storage.TickerRoleContributor), handler.GetMessageTags)
+		admin.GET(`/tickers/:tickerID/messages/:messageID/revisions`, ticker.PrefetchTicker(store, storage.WithPreload()), ticker.NeedRole(storage.TickerRoleContributor), handler.GetMessageRevisions)
+		admin.POST(`/tickers/:tickerID/messages/:messageID/publish`, ticker.PrefetchTicker(store, storage.WithPreload()), message.PrefetchMessage(store), ticker.NeedRole(storage.TickerRoleEditor), handler.PublishDraftMessage)
+		admin.POST(`/tickers/:tickerID/messages/:messageID/approve`, ticker.PrefetchTicker(store, storage.WithPreload()), message.PrefetchMessage(store), ticker.NeedRole(storage.TickerRoleEditor), handler.ApproveMessage)
+		admin.POST(`/tickers/:tickerID/messages/:messageID/reject`, ticker.PrefetchTicker(store, storage.WithPreload()), message.PrefetchMessage(store), ticker.NeedRole(storage.TickerRoleEditor), handler.RejectMessage)
+		admin.POST(`/tickers/:tickerID/messages/:messageID/resubmit`, ticker.PrefetchTicker(store, storage.WithPreload()), message.PrefetchMessage(store), ticker.NeedRole(storage.TickerRoleContributor), handler.ResubmitMessage)
+		admin.POST(`/tickers/:tickerID/messages/:messageID/pin`, ticker.PrefetchTicker(store, storage.WithPreload()), message.PrefetchMessage(store), ticker.NeedRole(storage.TickerRoleEditor), handler.PinMessage)
+		admin.DELETE(`/tickers/:tickerID/messages/:messageID/pin`, ticker.PrefetchTicker(store, storage.WithPreload()), message.PrefetchMessage(store), ticker.NeedRole(storage.TickerRoleEditor), handler.UnpinMessage)
 
 		admin.GET(`/users`, user.NeedAdmin(), handler.GetUsers)
 		admin.GET(`/users/:userID`, user.PrefetchUser(store), handler.GetUser)
 		admin.POST(`/users`, user.NeedAdmin(), handler.PostUser)
 		admin.PUT(`/users/me`, handler.PutMe)
+		admin.GET(`/users/me/api_tokens`, handler.GetAPITokens)
+		admin.POST(`/users/me/api_tokens`, handler.PostAPIToken)
+		admin.DELETE(`/users/me/api_tokens/:tokenID`, handler.DeleteAPIToken)
+		admin.POST(`/users/me/telegram`, handler.PostTelegramLink)
+		admin.DELETE(`/users/me/telegram`, handler.DeleteTelegramLink)
 		admin.PUT(`/users/:userID`, user.NeedAdmin(), user.PrefetchUser(store), handler.PutUser)
 		admin.DELETE(`/users/:userID`, user.NeedAdmin(), user.PrefetchUser(store), handler.DeleteUser)
+		admin.DELETE(`/users/:userID/totp`, user.NeedAdmin(), user.PrefetchUser(store), handler.DeleteUserTOTP)
+		admin.DELETE(`/users/:userID/passkeys`, user.NeedAdmin(), user.PrefetchUser(store), handler.DeleteUserPasskeys)
 
 		admin.GET(`/settings/:name`, user.NeedAdmin(), handler.GetSetting)
 		admin.PUT(`/settings/inactive_settings`, user.NeedAdmin(), handler.PutInactiveSettings)
 		admin.PUT(`/settings/telegram_settings`, user.NeedAdmin(), handler.PutTelegramSettings)
 		admin.PUT(`/settings/signal_group_settings`, user.NeedAdmin(), handler.PutSignalGroupSettings)
+		admin.PUT(`/settings/security_settings`, user.NeedAdmin(), handler.PutSecuritySettings)
+	}
+
+	// Routes which also take API tokens, each with the scope it needs. All
+	// other admin routes only take the JWT. The tokens are checked in front of
+	// the JWT middleware instead of inside it, so a token cannot reach a route
+	// by accident.
+	tokens := r.Group("/v1/admin")
+	{
+		tokens.Use(auth.TokenMiddleware(store, authMiddleware, handler.sso != nil))
+		tokens.Use(me.MeMiddleware(store))
+		tokens.Use(user.NeedTwoFactor(store, handler.sso != nil))
+
+		tokens.GET(`/tickers/:tickerID`, auth.NeedScope(storage.APITokenScopeMessagesRead), ticker.PrefetchTicker(store, storage.WithPreload()), handler.GetTicker)
+		tokens.GET(`/tickers/:tickerID/messages`, auth.NeedScope(storage.APITokenScopeMessagesRead), ticker.PrefetchTicker(store, storage.WithPreload()), handler.GetMessages)
+		tokens.GET(`/tickers/:tickerID/messages/:messageID`, auth.NeedScope(storage.APITokenScopeMessagesRead), ticker.PrefetchTicker(store, storage.WithPreload()), message.PrefetchMessage(store), handler.GetMessage)
+		tokens.POST(`/tickers/:tickerID/messages`, auth.NeedScope(storage.APITokenScopeMessagesWrite), ticker.PrefetchTicker(store, storage.WithPreload()), ticker.NeedRole(storage.TickerRoleContributor), handler.PostMessage)
+		tokens.PUT(`/tickers/:tickerID/messages/:messageID`, auth.NeedScope(storage.APITokenScopeMessagesWrite), ticker.PrefetchTicker(store, storage.WithPreload()), message.PrefetchMessage(store), ticker.NeedRole(storage.TickerRoleEditor), handler.PutMessage)
+		tokens.DELETE(`/tickers/:tickerID/messages/:messageID`, auth.NeedScope(storage.APITokenScopeMessagesWrite), ticker.PrefetchTicker(store, storage.WithPreload()), message.PrefetchMessage(store), ticker.NeedRole(storage.TickerRoleEditor), handler.DeleteMessage)
+
+		tokens.POST(`/upload`, auth.NeedScope(storage.APITokenScopeMessagesWrite), handler.PostUpload)
 	}
 
 	public := r.Group("/v1").Use()
 	{
 		public.POST(`/admin/login`, authMiddleware.LoginHandler)
+		public.POST(`/admin/login/webauthn`, handler.PostWebAuthnLogin)
+		public.POST(`/admin/login/oidc`, handler.PostOIDCLogin)
 
 		public.GET(`/init`, response_cache.CachePage(inMemoryCache, 5*time.Minute, handler.GetInit))
 		public.GET(`/manifest.json`, ticker.PrefetchTickerFromRequest(store), handler.HandleManifest)
 		public.GET(`/timeline`, ticker.PrefetchTickerFromRequest(store), response_cache.CachePage(inMemoryCache, 10*time.Second, handler.GetTimeline))
+		public.GET(`/timeline.geojson`, ticker.PrefetchTickerFromRequest(store), response_cache.CachePage(inMemoryCache, 10*time.Second, handler.GetTimelineGeoJSON))
+		public.GET(`/timeline/search`, ticker.PrefetchTickerFromRequest(store), response_cache.CachePage(inMemoryCache, 10*time.Second, handler.SearchTimeline))
 		public.GET(`/feed`, ticker.PrefetchTickerFromRequest(store), response_cache.CachePage(inMemoryCache, 5*time.Minute, handler.GetFeed))
 		public.GET(`/ws`, ticker.PrefetchTickerFromRequest(store), handler.HandleWebSocket)
 		public.GET(`/media/:fileName`, handler.GetMedia)
+		public.GET(`/webpush`, ticker.PrefetchTickerFromRequest(store, storage.WithPreload()), handler.GetWebPush)
+		public.POST(`/webpush/subscriptions`, ticker.PrefetchTickerFromRequest(store, storage.WithPreload()), handler.PostWebPushSubscription)
+		public.DELETE(`/webpush/subscriptions`, ticker.PrefetchTickerFromRequest(store), handler.DeleteWebPushSubscription)
+		public.POST(`/email/subscriptions`, ticker.PrefetchTickerFromRequest(store, storage.WithPreload()), handler.PostEmailSubscription)
+		public.GET(`/email/confirm`, handler.GetEmailConfirmation)
+		public.GET(`/email/unsubscribe`, handler.GetEmailUnsubscribe)
+		public.POST(`/email/unsubscribe`, handler.PostEmailUnsubscribe)
+		public.GET(`/activitypub/tickers/:tickerID`, handler.GetActivityPubActor)
+		public.GET(`/activitypub/tickers/:tickerID/outbox`, handler.GetActivityPubOutbox)
+		public.GET(`/activitypub/tickers/:tickerID/followers`, handler.GetActivityPubFollowers)
+		public.GET(`/activitypub/tickers/:tickerID/notes/:messageID`, handler.GetActivityPubNote)
+		public.POST(`/activitypub/tickers/:tickerID/inbox`, handler.PostActivityPubInbox)
 	}
 
+	r.GET("/.well-known/webfinger", handler.GetWebFinger)
+
 	r.GET("/healthz", func(c *gin.Context) {
 		c.String(http.StatusOK, "OK")
 	})
 
 	return &Server{
-		Router:   r,
-		Realtime: ws,
+		Router:        r,
+		Realtime:      ws,
+		Scheduler:     &Scheduler{handler: &handler, interval: schedulerInterval},
+		Outbox:        bridge.NewOutbox(bridges, store),
+		Digest:        bridge.NewEmailDigest(config, store),
+		TelegramInbox: &TelegramInbox{handler: &handler, idle: telegramIdleInterval, client: &http.Client{Timeout: 2 * telegramPollTimeout * time.Second}},
+		SignalInbox:   &SignalInbox{handler: &handler, idle: signalIdleInterval},
+		MailInbox:     &MailInbox{handler: &handler},
 	}
 }
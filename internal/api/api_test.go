@@ -121,6 +121,29 @@ func (s *APITestSuite) TestLogin() {
 		s.store.AssertExpectations(s.T())
 	})
 
+	s.Run("when a second factor is required", func() {
+		user, err := storage.NewUser("user@systemli.org", "password")
+		s.NoError(err)
+		user.TOTPSecret = "GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ"
+		user.TOTPEnabled = true
+		s.store = &storage.MockStorage{}
+		s.store.On("FindUserByEmail", mock.Anything, mock.Anything).Return(user, nil).Once()
+		server := API(s.cfg, s.store)
+
+		body := `{"username":"user@systemli.org","password":"password"}`
+		req := httptest.NewRequest(http.MethodPost, "/v1/admin/login", strings.NewReader(body))
+		req.Header.Add("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		server.Router.ServeHTTP(w, req)
+
+		var res response.Response
+		err = json.Unmarshal(w.Body.Bytes(), &res)
+		s.NoError(err)
+		s.Equal(http.StatusUnauthorized, w.Code)
+		s.Equal(response.CodeTwoFactorRequired, res.Error.Code)
+		s.Equal(response.TwoFactorRequired, res.Error.Message)
+	})
+
 	s.Run("when save user fails", func() {
 		user, err := storage.NewUser("user@systemli.org", "password")
 		s.NoError(err)
@@ -0,0 +1,128 @@
+package api
+
+import (
+	"net/http"
+	"slices"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/systemli/ticker/internal/api/helper"
+	"github.com/systemli/ticker/internal/api/response"
+	"github.com/systemli/ticker/internal/storage"
+)
+
+// APITokenParam holds a new token. TickerID limits it to one ticker of the
+// user, ExpiresAt to a time. Without ExpiresAt, the token expires after
+// storage.APITokenLifetime.
+type APITokenParam struct {
+	Name      string     `json:"name" binding:"required"`
+	Scopes    []string   `json:"scopes" binding:"required"`
+	TickerID  int        `json:"tickerID"`
+	ExpiresAt *time.Time `json:"expiresAt"`
+}
+
+// GetAPITokens lists the API tokens of the current user.
+func (h *handler) GetAPITokens(c *gin.Context) {
+	me, err := helper.Me(c)
+	if err != nil {
+		c.JSON(http.StatusForbidden, response.ErrorResponse(response.CodeDefault, response.Unauthorized))
+		return
+	}
+
+	tokens, err := h.storage.FindAPITokens(me)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, response.ErrorResponse(response.CodeDefault, response.StorageError))
+		return
+	}
+
+	c.JSON(http.StatusOK, response.SuccessResponse(map[string]interface{}{"apiTokens": response.APITokensResponse(tokens)}))
+}
+
+// PostAPIToken creates an API token for the current user. The token is only
+// returned here, afterwards only its hash is known.
+func (h *handler) PostAPIToken(c *gin.Context) {
+	me, err := helper.Me(c)
+	if err != nil {
+		c.JSON(http.StatusForbidden, response.ErrorResponse(response.CodeDefault, response.Unauthorized))
+		return
+	}
+
+	var body APITokenParam
+	if err := c.Bind(&body); err != nil {
+		c.JSON(http.StatusBadRequest, response.ErrorResponse(response.CodeDefault, response.FormError))
+		return
+	}
+
+	if len(body.Scopes) == 0 || slices.ContainsFunc(body.Scopes, func(scope string) bool { return !slices.Contains(storage.APITokenScopes, scope) }) {
+		c.JSON(http.StatusBadRequest, response.ErrorResponse(response.CodeDefault, response.APITokenInvalid))
+		return
+	}
+
+	now := time.Now()
+	if body.ExpiresAt != nil && !body.ExpiresAt.After(now) {
+		c.JSON(http.StatusBadRequest, response.ErrorResponse(response.CodeDefault, response.APITokenInvalid))
+		return
+	}
+
+	// Linked users leave through the identity provider, which cannot revoke
+	// their tokens. Their tokens have to expire soon instead.
+	if h.sso != nil && me.OIDCSubject != "" && (body.ExpiresAt == nil || body.ExpiresAt.After(now.Add(storage.LinkedAPITokenLifetime))) {
+		c.JSON(http.StatusBadRequest, response.ErrorResponse(response.CodeDefault, response.APITokenLifetime))
+		return
+	}
+
+	if body.ExpiresAt == nil {
+		expiresAt := now.Add(storage.APITokenLifetime)
+		body.ExpiresAt = &expiresAt
+	}
+
+	if body.TickerID != 0 {
+		if _, err := h.storage.FindTickerByUserAndID(me, body.TickerID); err != nil {
+			c.JSON(http.StatusBadRequest, response.ErrorResponse(response.CodeDefault, response.TickerNotFound))
+			return
+		}
+	}
+
+	plain, token, err := storage.NewAPIToken(me, body.Name, slices.Compact(slices.Sorted(slices.Values(body.Scopes))), body.TickerID)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, response.ErrorResponse(response.CodeDefault, response.StorageError))
+		return
+	}
+	token.ExpiresAt = body.ExpiresAt
+
+	if err := h.storage.SaveAPIToken(&token); err != nil {
+		c.JSON(http.StatusBadRequest, response.ErrorResponse(response.CodeDefault, response.StorageError))
+		return
+	}
+
+	c.JSON(http.StatusOK, response.SuccessResponse(map[string]interface{}{"apiToken": response.APITokenResponse(token), "token": plain}))
+}
+
+// DeleteAPIToken revokes an API token of the current user.
+func (h *handler) DeleteAPIToken(c *gin.Context) {
+	me, err := helper.Me(c)
+	if err != nil {
+		c.JSON(http.StatusForbidden, response.ErrorResponse(response.CodeDefault, response.Unauthorized))
+		return
+	}
+
+	tokenID, err := strconv.Atoi(c.Param("tokenID"))
+	if err != nil {
+		c.JSON(http.StatusNotFound, response.ErrorResponse(response.CodeNotFound, response.APITokenNotFound))
+		return
+	}
+
+	token, err := h.storage.FindAPITokenByID(me, tokenID)
+	if err != nil {
+		c.JSON(http.StatusNotFound, response.ErrorResponse(response.CodeNotFound, response.APITokenNotFound))
+		return
+	}
+
+	if err := h.storage.DeleteAPIToken(token); err != nil {
+		c.JSON(http.StatusBadRequest, response.ErrorResponse(response.CodeDefault, response.StorageError))
+		return
+	}
+
+	c.JSON(http.StatusOK, response.SuccessResponse(map[string]interface{}{}))
+}
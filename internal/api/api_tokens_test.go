@@ -0,0 +1,289 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+	"github.com/systemli/ticker/internal/api/response"
+	"github.com/systemli/ticker/internal/config"
+	"github.com/systemli/ticker/internal/sso"
+	"github.com/systemli/ticker/internal/storage"
+	"gorm.io/gorm"
+)
+
+type APITokensTestSuite struct {
+	w     *httptest.ResponseRecorder
+	ctx   *gin.Context
+	store *storage.MockStorage
+	user  storage.User
+	suite.Suite
+}
+
+func (s *APITokensTestSuite) SetupTest() {
+	gin.SetMode(gin.TestMode)
+
+	s.user = storage.User{ID: 1, Email: "user@systemli.org"}
+}
+
+func (s *APITokensTestSuite) Run(name string, subtest func()) {
+	s.T().Run(name, func(t *testing.T) {
+		s.w = httptest.NewRecorder()
+		s.ctx, _ = gin.CreateTestContext(s.w)
+		s.store = &storage.MockStorage{}
+
+		subtest()
+	})
+}
+
+func (s *APITokensTestSuite) TestGetAPITokens() {
+	s.Run("when user is missing", func() {
+		h := s.handler()
+		h.GetAPITokens(s.ctx)
+
+		s.Equal(http.StatusForbidden, s.w.Code)
+	})
+
+	s.Run("when storage returns error", func() {
+		s.ctx.Set("me", s.user)
+		s.store.On("FindAPITokens", s.user).Return(nil, errors.New("storage error")).Once()
+
+		h := s.handler()
+		h.GetAPITokens(s.ctx)
+
+		s.Equal(http.StatusBadRequest, s.w.Code)
+		s.store.AssertExpectations(s.T())
+	})
+
+	s.Run("when user has api tokens", func() {
+		s.ctx.Set("me", s.user)
+		s.store.On("FindAPITokens", s.user).Return([]storage.APIToken{{ID: 1, Name: "Sensor", Hash: "hash"}}, nil).Once()
+
+		h := s.handler()
+		h.GetAPITokens(s.ctx)
+
+		s.Equal(http.StatusOK, s.w.Code)
+		s.Contains(s.w.Body.String(), `"name":"Sensor"`)
+		s.NotContains(s.w.Body.String(), "hash")
+		s.store.AssertExpectations(s.T())
+	})
+}
+
+func (s *APITokensTestSuite) TestPostAPIToken() {
+	s.Run("when user is missing", func() {
+		h := s.handler()
+		h.PostAPIToken(s.ctx)
+
+		s.Equal(http.StatusForbidden, s.w.Code)
+	})
+
+	s.Run("when body is invalid", func() {
+		s.ctx.Set("me", s.user)
+		s.request(http.MethodPost, `{"scopes":["messages:read"]}`)
+
+		h := s.handler()
+		h.PostAPIToken(s.ctx)
+
+		s.Equal(http.StatusBadRequest, s.w.Code)
+		s.Contains(s.w.Body.String(), response.FormError)
+	})
+
+	s.Run("when scope is unknown", func() {
+		s.ctx.Set("me", s.user)
+		s.request(http.MethodPost, `{"name":"Sensor","scopes":["users:write"]}`)
+
+		h := s.handler()
+		h.PostAPIToken(s.ctx)
+
+		s.Equal(http.StatusBadRequest, s.w.Code)
+		s.Contains(s.w.Body.String(), response.APITokenInvalid)
+	})
+
+	s.Run("when scopes are empty", func() {
+		s.ctx.Set("me", s.user)
+		s.request(http.MethodPost, `{"name":"Sensor","scopes":[]}`)
+
+		h := s.handler()
+		h.PostAPIToken(s.ctx)
+
+		s.Equal(http.StatusBadRequest, s.w.Code)
+		s.Contains(s.w.Body.String(), response.APITokenInvalid)
+	})
+
+	s.Run("when expiry is in the past", func() {
+		s.ctx.Set("me", s.user)
+		s.request(http.MethodPost, `{"name":"Sensor","scopes":["messages:read"],"expiresAt":"2020-01-01T00:00:00Z"}`)
+
+		h := s.handler()
+		h.PostAPIToken(s.ctx)
+
+		s.Equal(http.StatusBadRequest, s.w.Code)
+		s.Contains(s.w.Body.String(), response.APITokenInvalid)
+	})
+
+	s.Run("when a linked user sends no expiry", func() {
+		linked := s.user
+		linked.OIDCSubject = "subject"
+		s.ctx.Set("me", linked)
+		s.request(http.MethodPost, `{"name":"Sensor","scopes":["messages:read"]}`)
+
+		h := s.handler()
+		h.sso = sso.New(config.OIDC{Issuer: "http://127.0.0.1:1"}, s.store)
+		h.PostAPIToken(s.ctx)
+
+		s.Equal(http.StatusBadRequest, s.w.Code)
+		s.Contains(s.w.Body.String(), response.APITokenLifetime)
+	})
+
+	s.Run("when a linked user sends a late expiry", func() {
+		linked := s.user
+		linked.OIDCSubject = "subject"
+		expiresAt := time.Now().Add(storage.LinkedAPITokenLifetime + time.Hour).UTC().Format(time.RFC3339)
+		s.ctx.Set("me", linked)
+		s.request(http.MethodPost, `{"name":"Sensor","scopes":["messages:read"],"expiresAt":"`+expiresAt+`"}`)
+
+		h := s.handler()
+		h.sso = sso.New(config.OIDC{Issuer: "http://127.0.0.1:1"}, s.store)
+		h.PostAPIToken(s.ctx)
+
+		s.Equal(http.StatusBadRequest, s.w.Code)
+		s.Contains(s.w.Body.String(), response.APITokenLifetime)
+	})
+
+	s.Run("when ticker is not accessible", func() {
+		s.ctx.Set("me", s.user)
+		s.request(http.MethodPost, `{"name":"Sensor","scopes":["messages:write"],"tickerID":5}`)
+		s.store.On("FindTickerByUserAndID", s.user, 5).Return(storage.Ticker{}, gorm.ErrRecordNotFound).Once()
+
+		h := s.handler()
+		h.PostAPIToken(s.ctx)
+
+		s.Equal(http.StatusBadRequest, s.w.Code)
+		s.Contains(s.w.Body.String(), response.TickerNotFound)
+		s.store.AssertExpectations(s.T())
+	})
+
+	s.Run("when storage returns error", func() {
+		s.ctx.Set("me", s.user)
+		s.request(http.MethodPost, `{"name":"Sensor","scopes":["messages:write"]}`)
+		s.store.On("SaveAPIToken", mock.Anything).Return(errors.New("storage error")).Once()
+
+		h := s.handler()
+		h.PostAPIToken(s.ctx)
+
+		s.Equal(http.StatusBadRequest, s.w.Code)
+		s.store.AssertExpectations(s.T())
+	})
+
+	s.Run("when api token is created without expiry", func() {
+		s.ctx.Set("me", s.user)
+		s.request(http.MethodPost, `{"name":"Sensor","scopes":["messages:read"]}`)
+		s.store.On("SaveAPIToken", mock.MatchedBy(func(t *storage.APIToken) bool {
+			return t.ExpiresAt != nil && t.ExpiresAt.After(time.Now().Add(storage.APITokenLifetime-time.Minute))
+		})).Return(nil).Once()
+
+		h := s.handler()
+		h.PostAPIToken(s.ctx)
+
+		s.Equal(http.StatusOK, s.w.Code)
+		s.store.AssertExpectations(s.T())
+	})
+
+	s.Run("when api token is created", func() {
+		expiresAt := time.Now().Add(time.Hour).UTC().Format(time.RFC3339)
+		s.ctx.Set("me", s.user)
+		s.request(http.MethodPost, `{"name":"Sensor","scopes":["messages:write","messages:read","messages:write"],"tickerID":5,"expiresAt":"`+expiresAt+`"}`)
+		s.store.On("FindTickerByUserAndID", s.user, 5).Return(storage.Ticker{ID: 5}, nil).Once()
+		s.store.On("SaveAPIToken", mock.MatchedBy(func(t *storage.APIToken) bool {
+			return t.UserID == 1 && t.Name == "Sensor" && t.TickerID == 5 && t.ExpiresAt != nil &&
+				len(t.Hash) == 64 && len(t.Scopes) == 2
+		})).Return(nil).Once()
+
+		h := s.handler()
+		h.PostAPIToken(s.ctx)
+
+		s.Equal(http.StatusOK, s.w.Code)
+		s.Contains(s.w.Body.String(), `"token":"`+storage.APITokenPrefix)
+		s.Contains(s.w.Body.String(), `"scopes":["messages:read","messages:write"]`)
+		s.store.AssertExpectations(s.T())
+	})
+}
+
+func (s *APITokensTestSuite) TestDeleteAPIToken() {
+	s.Run("when user is missing", func() {
+		h := s.handler()
+		h.DeleteAPIToken(s.ctx)
+
+		s.Equal(http.StatusForbidden, s.w.Code)
+	})
+
+	s.Run("when id is invalid", func() {
+		s.ctx.Set("me", s.user)
+		s.ctx.AddParam("tokenID", "token")
+
+		h := s.handler()
+		h.DeleteAPIToken(s.ctx)
+
+		s.Equal(http.StatusNotFound, s.w.Code)
+	})
+
+	s.Run("when api token is not found", func() {
+		s.ctx.Set("me", s.user)
+		s.ctx.AddParam("tokenID", "1")
+		s.store.On("FindAPITokenByID", s.user, 1).Return(storage.APIToken{}, gorm.ErrRecordNotFound).Once()
+
+		h := s.handler()
+		h.DeleteAPIToken(s.ctx)
+
+		s.Equal(http.StatusNotFound, s.w.Code)
+		s.store.AssertExpectations(s.T())
+	})
+
+	s.Run("when storage returns error", func() {
+		s.ctx.Set("me", s.user)
+		s.ctx.AddParam("tokenID", "1")
+		s.store.On("FindAPITokenByID", s.user, 1).Return(storage.APIToken{ID: 1}, nil).Once()
+		s.store.On("DeleteAPIToken", storage.APIToken{ID: 1}).Return(errors.New("storage error")).Once()
+
+		h := s.handler()
+		h.DeleteAPIToken(s.ctx)
+
+		s.Equal(http.StatusBadRequest, s.w.Code)
+		s.store.AssertExpectations(s.T())
+	})
+
+	s.Run("when api token is deleted", func() {
+		s.ctx.Set("me", s.user)
+		s.ctx.AddParam("tokenID", "1")
+		s.store.On("FindAPITokenByID", s.user, 1).Return(storage.APIToken{ID: 1}, nil).Once()
+		s.store.On("DeleteAPIToken", storage.APIToken{ID: 1}).Return(nil).Once()
+
+		h := s.handler()
+		h.DeleteAPIToken(s.ctx)
+
+		s.Equal(http.StatusOK, s.w.Code)
+		s.store.AssertExpectations(s.T())
+	})
+}
+
+func (s *APITokensTestSuite) request(method, body string) {
+	s.ctx.Request = httptest.NewRequest(method, "/v1/admin/users/me/api_tokens", strings.NewReader(body))
+	s.ctx.Request.Header.Add("Content-Type", "application/json")
+}
+
+func (s *APITokensTestSuite) handler() handler {
+	return handler{
+		storage: s.store,
+		config:  config.LoadConfig(""),
+	}
+}
+
+func TestAPITokensTestSuite(t *testing.T) {
+	suite.Run(t, new(APITokensTestSuite))
+}
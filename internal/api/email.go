@@ -0,0 +1,229 @@
+package api
+
+import (
+	"bytes"
+	"errors"
+	"html/template"
+	"net/http"
+	"net/mail"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/systemli/ticker/internal/api/helper"
+	"github.com/systemli/ticker/internal/api/response"
+	"github.com/systemli/ticker/internal/bridge"
+	"github.com/systemli/ticker/internal/mailer"
+	"github.com/systemli/ticker/internal/storage"
+	"gorm.io/gorm"
+)
+
+// emailConfirmationInterval is how long an unconfirmed address waits before
+// another subscription sends the confirmation mail again.
+const emailConfirmationInterval = time.Hour
+
+// unsubscribePage asks the reader to confirm, so that link scanners and
+// prefetching mail clients which open the link do not unsubscribe anyone.
+var unsubscribePage = template.Must(template.New("unsubscribe").Parse(`<!DOCTYPE html>
+<html>
+<head><meta charset="utf-8"><title>Unsubscribe</title></head>
+<body>
+<p>Do you want to stop receiving mails from the ticker?</p>
+<form method="post" action="?token={{.}}"><button type="submit">Unsubscribe</button></form>
+</body>
+</html>
+`))
+
+type EmailParam struct {
+	Active bool `json:"active"`
+	Digest bool `json:"digest"`
+}
+
+type EmailSubscriptionParam struct {
+	Email string `json:"email" binding:"required"`
+}
+
+// PostEmailSubscription subscribes an address to the ticker and mails the link
+// to confirm it. The response is the same for addresses which are subscribed
+// already, so it does not tell who follows the ticker.
+func (h *handler) PostEmailSubscription(c *gin.Context) {
+	ticker, err := helper.Ticker(c)
+	if err != nil {
+		c.JSON(http.StatusOK, response.ErrorResponse(response.CodeDefault, response.TickerNotFound))
+		return
+	}
+
+	if !ticker.Email.Active || !h.config.SMTP.Enabled() {
+		c.JSON(http.StatusOK, response.ErrorResponse(response.CodeDefault, response.EmailDisabled))
+		return
+	}
+
+	var body EmailSubscriptionParam
+	if err := c.ShouldBindJSON(&body); err != nil {
+		c.JSON(http.StatusOK, response.ErrorResponse(response.CodeDefault, response.FormError))
+		return
+	}
+
+	address, err := mail.ParseAddress(body.Email)
+	if err != nil || address.Address != body.Email {
+		c.JSON(http.StatusOK, response.ErrorResponse(response.CodeDefault, response.EmailInvalid))
+		return
+	}
+
+	subscription, err := h.storage.FindEmailSubscription(ticker, address.Address)
+	if err != nil {
+		if !errors.Is(err, gorm.ErrRecordNotFound) {
+			c.JSON(http.StatusOK, response.ErrorResponse(response.CodeDefault, response.StorageError))
+			return
+		}
+
+		subscription, err = storage.NewEmailSubscription(ticker.ID, address.Address)
+		if err == nil {
+			now := time.Now()
+			subscription.ConfirmationSentAt = &now
+			err = h.storage.SaveEmailSubscription(&subscription)
+		}
+		if err != nil {
+			c.JSON(http.StatusOK, response.ErrorResponse(response.CodeDefault, response.StorageError))
+			return
+		}
+	} else if !subscription.Confirmed() {
+		// Send the mail again at most once per interval, so the endpoint
+		// cannot be used to flood an address.
+		claimed, err := h.storage.ClaimEmailConfirmation(&subscription, emailConfirmationInterval)
+		if err != nil {
+			c.JSON(http.StatusOK, response.ErrorResponse(response.CodeDefault, response.StorageError))
+			return
+		}
+		if !claimed {
+			c.JSON(http.StatusOK, response.SuccessResponse(map[string]interface{}{}))
+			return
+		}
+	}
+
+	if !subscription.Confirmed() {
+		client, err := mailer.NewClient(h.config.SMTP)
+		if err == nil {
+			err = client.Send(c.Request.Context(), bridge.EmailConfirmation(ticker, subscription))
+		}
+		if err != nil {
+			log.WithError(err).WithField("ticker_id", ticker.ID).Error("failed to send confirmation mail")
+			// Let the reader retry right away when the mail was not sent.
+			subscription.ConfirmationSentAt = nil
+			if err := h.storage.SaveEmailSubscription(&subscription); err != nil {
+				log.WithError(err).WithField("ticker_id", ticker.ID).Error("failed to reset confirmation mail")
+			}
+			c.JSON(http.StatusOK, response.ErrorResponse(response.CodeDefault, response.EmailError))
+			return
+		}
+	}
+
+	c.JSON(http.StatusOK, response.SuccessResponse(map[string]interface{}{}))
+}
+
+// GetEmailConfirmation confirms the subscription of the token. Readers open the
+// link from the confirmation mail, so the response is plain text.
+func (h *handler) GetEmailConfirmation(c *gin.Context) {
+	subscription, err := h.storage.FindEmailSubscriptionByToken(c.Query("token"))
+	if err != nil {
+		c.String(http.StatusNotFound, "This link is not valid anymore. Please subscribe again.")
+		return
+	}
+
+	if !subscription.Confirmed() {
+		now := time.Now()
+		subscription.ConfirmedAt = &now
+		if err := h.storage.SaveEmailSubscription(&subscription); err != nil {
+			c.String(http.StatusInternalServerError, "Your subscription could not be confirmed. Please try again later.")
+			return
+		}
+	}
+
+	c.String(http.StatusOK, "Your subscription is confirmed. You will receive the next messages of the ticker by email.")
+}
+
+// GetEmailUnsubscribe shows the page to confirm the unsubscribe link in the
+// footer of every mail. It does not change anything, the page posts back to
+// the same link.
+func (h *handler) GetEmailUnsubscribe(c *gin.Context) {
+	token := c.Query("token")
+	if _, err := h.storage.FindEmailSubscriptionByToken(token); err != nil {
+		if !errors.Is(err, gorm.ErrRecordNotFound) {
+			c.String(http.StatusInternalServerError, "You could not be unsubscribed. Please try again later.")
+			return
+		}
+
+		c.String(http.StatusOK, "You are not subscribed to the ticker.")
+		return
+	}
+
+	var page bytes.Buffer
+	if err := unsubscribePage.Execute(&page, token); err != nil {
+		c.String(http.StatusInternalServerError, "You could not be unsubscribed. Please try again later.")
+		return
+	}
+
+	c.Data(http.StatusOK, "text/html; charset=utf-8", page.Bytes())
+}
+
+// PostEmailUnsubscribe removes the subscription of the token. It answers the
+// confirmation page as well as the one-click unsubscribe of the mail clients
+// (RFC 8058), which posts to the same link.
+func (h *handler) PostEmailUnsubscribe(c *gin.Context) {
+	subscription, err := h.storage.FindEmailSubscriptionByToken(c.Query("token"))
+	if err == nil {
+		err = h.storage.DeleteEmailSubscription(subscription)
+	}
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		c.String(http.StatusInternalServerError, "You could not be unsubscribed. Please try again later.")
+		return
+	}
+
+	c.String(http.StatusOK, "You are unsubscribed and will not receive any more mails from the ticker.")
+}
+
+func (h *handler) PutTickerEmail(c *gin.Context) {
+	ticker, err := helper.Ticker(c)
+	if err != nil {
+		c.JSON(http.StatusNotFound, response.ErrorResponse(response.CodeDefault, response.TickerNotFound))
+		return
+	}
+
+	var body EmailParam
+	err = c.Bind(&body)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, response.ErrorResponse(response.CodeNotFound, response.FormError))
+		return
+	}
+
+	if body.Active && !h.config.SMTP.Enabled() {
+		c.JSON(http.StatusBadRequest, response.ErrorResponse(response.CodeDefault, response.EmailDisabled))
+		return
+	}
+
+	ticker.Email.Active = body.Active
+	ticker.Email.Digest = body.Digest
+
+	err = h.storage.SaveTicker(&ticker)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, response.ErrorResponse(response.CodeDefault, response.StorageError))
+		return
+	}
+
+	c.JSON(http.StatusOK, response.SuccessResponse(map[string]interface{}{"ticker": response.TickerResponse(ticker, h.getBotUsername())}))
+}
+
+func (h *handler) DeleteTickerEmail(c *gin.Context) {
+	ticker, err := helper.Ticker(c)
+	if err != nil {
+		c.JSON(http.StatusNotFound, response.ErrorResponse(response.CodeDefault, response.TickerNotFound))
+		return
+	}
+
+	err = h.storage.DeleteEmail(&ticker)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, response.ErrorResponse(response.CodeDefault, response.StorageError))
+		return
+	}
+
+	c.JSON(http.StatusOK, response.SuccessResponse(map[string]interface{}{"ticker": response.TickerResponse(ticker, h.getBotUsername())}))
+}
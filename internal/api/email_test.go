@@ -0,0 +1,397 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+	"github.com/systemli/ticker/internal/api/response"
+	"github.com/systemli/ticker/internal/config"
+	"github.com/systemli/ticker/internal/mailer/mailertest"
+	"github.com/systemli/ticker/internal/storage"
+	"gorm.io/gorm"
+)
+
+type EmailTestSuite struct {
+	w      *httptest.ResponseRecorder
+	ctx    *gin.Context
+	store  *storage.MockStorage
+	cfg    config.Config
+	server *mailertest.Server
+	suite.Suite
+}
+
+func (s *EmailTestSuite) SetupTest() {
+	gin.SetMode(gin.TestMode)
+}
+
+func (s *EmailTestSuite) Run(name string, subtest func()) {
+	s.T().Run(name, func(t *testing.T) {
+		s.w = httptest.NewRecorder()
+		s.ctx, _ = gin.CreateTestContext(s.w)
+		s.store = &storage.MockStorage{}
+		s.store.On("GetTelegramSettings").Return(storage.TelegramSettings{}).Maybe()
+		s.server = mailertest.NewServer(t)
+		s.cfg = config.LoadConfig("")
+		s.cfg.SMTP = s.server.Config("ticker@example.org")
+
+		subtest()
+	})
+}
+
+func (s *EmailTestSuite) TestPostEmailSubscription() {
+	ticker := storage.Ticker{
+		ID:       1,
+		Title:    "Ticker",
+		Websites: []storage.TickerWebsite{{Origin: "https://demo.example.org"}},
+		Email:    storage.TickerEmail{Active: true},
+	}
+
+	s.Run("when ticker not found", func() {
+		h := s.handler()
+		h.PostEmailSubscription(s.ctx)
+
+		s.Equal(http.StatusOK, s.w.Code)
+		s.Contains(s.w.Body.String(), response.TickerNotFound)
+	})
+
+	s.Run("when email is disabled for the ticker", func() {
+		s.ctx.Set("ticker", storage.Ticker{ID: 1})
+
+		h := s.handler()
+		h.PostEmailSubscription(s.ctx)
+
+		s.Equal(http.StatusOK, s.w.Code)
+		s.Contains(s.w.Body.String(), response.EmailDisabled)
+	})
+
+	s.Run("when the address is invalid", func() {
+		s.ctx.Set("ticker", ticker)
+		s.ctx.Request = httptest.NewRequest(http.MethodPost, "/v1/email/subscriptions", strings.NewReader(`{"email":"Reader <reader@example.org>"}`))
+		s.ctx.Request.Header.Add("Content-Type", "application/json")
+
+		h := s.handler()
+		h.PostEmailSubscription(s.ctx)
+
+		s.Equal(http.StatusOK, s.w.Code)
+		s.Contains(s.w.Body.String(), response.EmailInvalid)
+	})
+
+	s.Run("when storage returns error", func() {
+		s.ctx.Set("ticker", ticker)
+		s.ctx.Request = httptest.NewRequest(http.MethodPost, "/v1/email/subscriptions", strings.NewReader(`{"email":"reader@example.org"}`))
+		s.ctx.Request.Header.Add("Content-Type", "application/json")
+		s.store.On("FindEmailSubscription", ticker, "reader@example.org").Return(storage.EmailSubscription{}, errors.New("storage error")).Once()
+
+		h := s.handler()
+		h.PostEmailSubscription(s.ctx)
+
+		s.Equal(http.StatusOK, s.w.Code)
+		s.Contains(s.w.Body.String(), response.StorageError)
+		s.store.AssertExpectations(s.T())
+	})
+
+	s.Run("when the address subscribes", func() {
+		s.ctx.Set("ticker", ticker)
+		s.ctx.Request = httptest.NewRequest(http.MethodPost, "/v1/email/subscriptions", strings.NewReader(`{"email":"reader@example.org"}`))
+		s.ctx.Request.Header.Add("Content-Type", "application/json")
+		s.store.On("FindEmailSubscription", ticker, "reader@example.org").Return(storage.EmailSubscription{}, gorm.ErrRecordNotFound).Once()
+		s.store.On("SaveEmailSubscription", mock.MatchedBy(func(subscription *storage.EmailSubscription) bool {
+			return subscription.TickerID == 1 && subscription.Email == "reader@example.org" && subscription.Token != "" && !subscription.Confirmed() && subscription.ConfirmationSentAt != nil
+		})).Return(nil).Once()
+
+		h := s.handler()
+		h.PostEmailSubscription(s.ctx)
+
+		s.Equal(http.StatusOK, s.w.Code)
+		s.Contains(s.w.Body.String(), response.StatusSuccess)
+		s.store.AssertExpectations(s.T())
+
+		mails := s.server.Mails()
+		s.Len(mails, 1)
+		s.Equal([]string{"reader@example.org"}, mails[0].To)
+		s.Contains(mails[0].Data, "https://demo.example.org/api/email/confirm?token=")
+	})
+
+	s.Run("when the address is subscribed already", func() {
+		now := time.Now()
+		s.ctx.Set("ticker", ticker)
+		s.ctx.Request = httptest.NewRequest(http.MethodPost, "/v1/email/subscriptions", strings.NewReader(`{"email":"reader@example.org"}`))
+		s.ctx.Request.Header.Add("Content-Type", "application/json")
+		s.store.On("FindEmailSubscription", ticker, "reader@example.org").Return(storage.EmailSubscription{ID: 1, Email: "reader@example.org", ConfirmedAt: &now}, nil).Once()
+
+		h := s.handler()
+		h.PostEmailSubscription(s.ctx)
+
+		s.Equal(http.StatusOK, s.w.Code)
+		s.Contains(s.w.Body.String(), response.StatusSuccess)
+		s.Empty(s.server.Mails())
+		s.store.AssertExpectations(s.T())
+	})
+
+	s.Run("when the address subscribes again", func() {
+		s.ctx.Set("ticker", ticker)
+		s.ctx.Request = httptest.NewRequest(http.MethodPost, "/v1/email/subscriptions", strings.NewReader(`{"email":"reader@example.org"}`))
+		s.ctx.Request.Header.Add("Content-Type", "application/json")
+		s.store.On("FindEmailSubscription", ticker, "reader@example.org").Return(storage.EmailSubscription{ID: 1, Email: "reader@example.org", Token: "token"}, nil).Once()
+		s.store.On("ClaimEmailConfirmation", mock.Anything, time.Hour).Return(true, nil).Once()
+
+		h := s.handler()
+		h.PostEmailSubscription(s.ctx)
+
+		s.Equal(http.StatusOK, s.w.Code)
+		s.Contains(s.w.Body.String(), response.StatusSuccess)
+		s.Len(s.server.Mails(), 1)
+		s.store.AssertExpectations(s.T())
+	})
+
+	s.Run("when the confirmation was sent recently", func() {
+		s.ctx.Set("ticker", ticker)
+		s.ctx.Request = httptest.NewRequest(http.MethodPost, "/v1/email/subscriptions", strings.NewReader(`{"email":"reader@example.org"}`))
+		s.ctx.Request.Header.Add("Content-Type", "application/json")
+		s.store.On("FindEmailSubscription", ticker, "reader@example.org").Return(storage.EmailSubscription{ID: 1, Email: "reader@example.org", Token: "token"}, nil).Once()
+		s.store.On("ClaimEmailConfirmation", mock.Anything, time.Hour).Return(false, nil).Once()
+
+		h := s.handler()
+		h.PostEmailSubscription(s.ctx)
+
+		s.Equal(http.StatusOK, s.w.Code)
+		s.Contains(s.w.Body.String(), response.StatusSuccess)
+		s.Empty(s.server.Mails())
+		s.store.AssertExpectations(s.T())
+	})
+
+	s.Run("when the confirmation cannot be sent", func() {
+		s.cfg.SMTP = config.SMTP{Host: "127.0.0.1", Port: 1, From: "ticker@example.org"}
+		s.ctx.Set("ticker", ticker)
+		s.ctx.Request = httptest.NewRequest(http.MethodPost, "/v1/email/subscriptions", strings.NewReader(`{"email":"reader@example.org"}`))
+		s.ctx.Request.Header.Add("Content-Type", "application/json")
+		s.store.On("FindEmailSubscription", ticker, "reader@example.org").Return(storage.EmailSubscription{ID: 1, Email: "reader@example.org", Token: "token"}, nil).Once()
+		s.store.On("ClaimEmailConfirmation", mock.Anything, time.Hour).Return(true, nil).Once()
+		s.store.On("SaveEmailSubscription", mock.MatchedBy(func(subscription *storage.EmailSubscription) bool {
+			return subscription.ConfirmationSentAt == nil
+		})).Return(nil).Once()
+
+		h := s.handler()
+		h.PostEmailSubscription(s.ctx)
+
+		s.Equal(http.StatusOK, s.w.Code)
+		s.Contains(s.w.Body.String(), response.EmailError)
+		s.store.AssertExpectations(s.T())
+	})
+}
+
+func (s *EmailTestSuite) TestGetEmailConfirmation() {
+	s.Run("when token is unknown", func() {
+		s.ctx.Request = httptest.NewRequest(http.MethodGet, "/v1/email/confirm?token=unknown", nil)
+		s.store.On("FindEmailSubscriptionByToken", "unknown").Return(storage.EmailSubscription{}, gorm.ErrRecordNotFound).Once()
+
+		h := s.handler()
+		h.GetEmailConfirmation(s.ctx)
+
+		s.Equal(http.StatusNotFound, s.w.Code)
+		s.store.AssertExpectations(s.T())
+	})
+
+	s.Run("when storage returns error", func() {
+		s.ctx.Request = httptest.NewRequest(http.MethodGet, "/v1/email/confirm?token=token", nil)
+		s.store.On("FindEmailSubscriptionByToken", "token").Return(storage.EmailSubscription{ID: 1, Token: "token"}, nil).Once()
+		s.store.On("SaveEmailSubscription", mock.Anything).Return(errors.New("storage error")).Once()
+
+		h := s.handler()
+		h.GetEmailConfirmation(s.ctx)
+
+		s.Equal(http.StatusInternalServerError, s.w.Code)
+		s.store.AssertExpectations(s.T())
+	})
+
+	s.Run("when the subscription is confirmed", func() {
+		s.ctx.Request = httptest.NewRequest(http.MethodGet, "/v1/email/confirm?token=token", nil)
+		s.store.On("FindEmailSubscriptionByToken", "token").Return(storage.EmailSubscription{ID: 1, Token: "token"}, nil).Once()
+		s.store.On("SaveEmailSubscription", mock.MatchedBy(func(subscription *storage.EmailSubscription) bool {
+			return subscription.Confirmed()
+		})).Return(nil).Once()
+
+		h := s.handler()
+		h.GetEmailConfirmation(s.ctx)
+
+		s.Equal(http.StatusOK, s.w.Code)
+		s.Contains(s.w.Body.String(), "confirmed")
+		s.store.AssertExpectations(s.T())
+	})
+}
+
+func (s *EmailTestSuite) TestGetEmailUnsubscribe() {
+	s.Run("when token is unknown", func() {
+		s.ctx.Request = httptest.NewRequest(http.MethodGet, "/v1/email/unsubscribe?token=unknown", nil)
+		s.store.On("FindEmailSubscriptionByToken", "unknown").Return(storage.EmailSubscription{}, gorm.ErrRecordNotFound).Once()
+
+		h := s.handler()
+		h.GetEmailUnsubscribe(s.ctx)
+
+		s.Equal(http.StatusOK, s.w.Code)
+		s.Contains(s.w.Body.String(), "not subscribed")
+		s.store.AssertExpectations(s.T())
+	})
+
+	s.Run("when storage returns error", func() {
+		s.ctx.Request = httptest.NewRequest(http.MethodGet, "/v1/email/unsubscribe?token=token", nil)
+		s.store.On("FindEmailSubscriptionByToken", "token").Return(storage.EmailSubscription{}, errors.New("storage error")).Once()
+
+		h := s.handler()
+		h.GetEmailUnsubscribe(s.ctx)
+
+		s.Equal(http.StatusInternalServerError, s.w.Code)
+		s.store.AssertExpectations(s.T())
+	})
+
+	s.Run("when the reader opens the link", func() {
+		s.ctx.Request = httptest.NewRequest(http.MethodGet, "/v1/email/unsubscribe?token=token", nil)
+		s.store.On("FindEmailSubscriptionByToken", "token").Return(storage.EmailSubscription{ID: 1, Token: "token"}, nil).Once()
+
+		h := s.handler()
+		h.GetEmailUnsubscribe(s.ctx)
+
+		s.Equal(http.StatusOK, s.w.Code)
+		s.Contains(s.w.Header().Get("Content-Type"), "text/html")
+		s.Contains(s.w.Body.String(), `<form method="post" action="?token=token">`)
+		s.store.AssertExpectations(s.T())
+	})
+}
+
+func (s *EmailTestSuite) TestPostEmailUnsubscribe() {
+	s.Run("when token is unknown", func() {
+		s.ctx.Request = httptest.NewRequest(http.MethodPost, "/v1/email/unsubscribe?token=unknown", strings.NewReader("List-Unsubscribe=One-Click"))
+		s.store.On("FindEmailSubscriptionByToken", "unknown").Return(storage.EmailSubscription{}, gorm.ErrRecordNotFound).Once()
+
+		h := s.handler()
+		h.PostEmailUnsubscribe(s.ctx)
+
+		s.Equal(http.StatusOK, s.w.Code)
+		s.store.AssertExpectations(s.T())
+	})
+
+	s.Run("when storage returns error", func() {
+		s.ctx.Request = httptest.NewRequest(http.MethodPost, "/v1/email/unsubscribe?token=token", nil)
+		s.store.On("FindEmailSubscriptionByToken", "token").Return(storage.EmailSubscription{ID: 1, Token: "token"}, nil).Once()
+		s.store.On("DeleteEmailSubscription", mock.Anything).Return(errors.New("storage error")).Once()
+
+		h := s.handler()
+		h.PostEmailUnsubscribe(s.ctx)
+
+		s.Equal(http.StatusInternalServerError, s.w.Code)
+		s.store.AssertExpectations(s.T())
+	})
+
+	s.Run("when the reader unsubscribes", func() {
+		s.ctx.Request = httptest.NewRequest(http.MethodPost, "/v1/email/unsubscribe?token=token", nil)
+		s.store.On("FindEmailSubscriptionByToken", "token").Return(storage.EmailSubscription{ID: 1, Token: "token"}, nil).Once()
+		s.store.On("DeleteEmailSubscription", storage.EmailSubscription{ID: 1, Token: "token"}).Return(nil).Once()
+
+		h := s.handler()
+		h.PostEmailUnsubscribe(s.ctx)
+
+		s.Equal(http.StatusOK, s.w.Code)
+		s.Contains(s.w.Body.String(), "unsubscribed")
+		s.store.AssertExpectations(s.T())
+	})
+}
+
+func (s *EmailTestSuite) TestPutTickerEmail() {
+	s.Run("when ticker not found", func() {
+		h := s.handler()
+		h.PutTickerEmail(s.ctx)
+
+		s.Equal(http.StatusNotFound, s.w.Code)
+	})
+
+	s.Run("when no mail server is configured", func() {
+		s.cfg.SMTP = config.SMTP{}
+		s.ctx.Set("ticker", storage.Ticker{ID: 1})
+		s.ctx.Request = httptest.NewRequest(http.MethodPut, "/v1/admin/tickers/1/email", strings.NewReader(`{"active":true}`))
+		s.ctx.Request.Header.Add("Content-Type", "application/json")
+
+		h := s.handler()
+		h.PutTickerEmail(s.ctx)
+
+		s.Equal(http.StatusBadRequest, s.w.Code)
+		s.Contains(s.w.Body.String(), response.EmailDisabled)
+	})
+
+	s.Run("when storage returns error", func() {
+		s.ctx.Set("ticker", storage.Ticker{ID: 1})
+		s.ctx.Request = httptest.NewRequest(http.MethodPut, "/v1/admin/tickers/1/email", strings.NewReader(`{"active":true}`))
+		s.ctx.Request.Header.Add("Content-Type", "application/json")
+		s.store.On("SaveTicker", mock.Anything).Return(errors.New("storage error")).Once()
+
+		h := s.handler()
+		h.PutTickerEmail(s.ctx)
+
+		s.Equal(http.StatusBadRequest, s.w.Code)
+		s.store.AssertExpectations(s.T())
+	})
+
+	s.Run("when email is turned on", func() {
+		s.ctx.Set("ticker", storage.Ticker{ID: 1})
+		s.ctx.Request = httptest.NewRequest(http.MethodPut, "/v1/admin/tickers/1/email", strings.NewReader(`{"active":true,"digest":true}`))
+		s.ctx.Request.Header.Add("Content-Type", "application/json")
+		s.store.On("SaveTicker", mock.MatchedBy(func(t *storage.Ticker) bool {
+			return t.Email.Active && t.Email.Digest
+		})).Return(nil).Once()
+
+		h := s.handler()
+		h.PutTickerEmail(s.ctx)
+
+		s.Equal(http.StatusOK, s.w.Code)
+		s.Contains(s.w.Body.String(), `"email":{"active":true,"digest":true}`)
+		s.store.AssertExpectations(s.T())
+	})
+}
+
+func (s *EmailTestSuite) TestDeleteTickerEmail() {
+	s.Run("when ticker not found", func() {
+		h := s.handler()
+		h.DeleteTickerEmail(s.ctx)
+
+		s.Equal(http.StatusNotFound, s.w.Code)
+	})
+
+	s.Run("when storage returns error", func() {
+		s.ctx.Set("ticker", storage.Ticker{ID: 1, Email: storage.TickerEmail{Active: true}})
+		s.store.On("DeleteEmail", mock.Anything).Return(errors.New("storage error")).Once()
+
+		h := s.handler()
+		h.DeleteTickerEmail(s.ctx)
+
+		s.Equal(http.StatusBadRequest, s.w.Code)
+		s.store.AssertExpectations(s.T())
+	})
+
+	s.Run("when email is turned off", func() {
+		s.ctx.Set("ticker", storage.Ticker{ID: 1, Email: storage.TickerEmail{Active: true}})
+		s.store.On("DeleteEmail", mock.Anything).Return(nil).Once()
+
+		h := s.handler()
+		h.DeleteTickerEmail(s.ctx)
+
+		s.Equal(http.StatusOK, s.w.Code)
+		s.store.AssertExpectations(s.T())
+	})
+}
+
+func (s *EmailTestSuite) handler() handler {
+	return handler{
+		storage: s.store,
+		config:  s.cfg,
+	}
+}
+
+func TestEmailTestSuite(t *testing.T) {
+	suite.Run(t, new(EmailTestSuite))
+}
@@ -5,21 +5,28 @@ import (
 
 	"github.com/gin-gonic/gin"
 	"github.com/systemli/ticker/internal/api/response"
+	"github.com/systemli/ticker/internal/config"
 	"github.com/systemli/ticker/internal/storage"
 )
 
 type FeaturesResponse map[string]bool
 
-func NewFeaturesResponse(storage storage.Storage) FeaturesResponse {
+func NewFeaturesResponse(config config.Config, storage storage.Storage) FeaturesResponse {
 	telegramSettings := storage.GetTelegramSettings()
 	signalGroupSettings := storage.GetSignalGroupSettings()
+	securitySettings := storage.GetSecuritySettings()
 	return FeaturesResponse{
 		"telegramEnabled":    telegramSettings.Token != "",
 		"signalGroupEnabled": signalGroupSettings.Enabled(),
+		"emailEnabled":       config.SMTP.Enabled(),
+		"mailboxEnabled":     config.InboundMail.Enabled(),
+		"twoFactorRequired":  securitySettings.RequireTwoFactor,
+		"passkeysEnabled":    config.WebAuthn.Enabled(),
+		"oidcEnabled":        config.OIDC.Enabled(),
 	}
 }
 
 func (h *handler) GetFeatures(c *gin.Context) {
-	features := NewFeaturesResponse(h.storage)
+	features := NewFeaturesResponse(h.config, h.storage)
 	c.JSON(http.StatusOK, response.SuccessResponse(map[string]interface{}{"features": features}))
 }
@@ -28,6 +28,7 @@ func (s *FeaturesTestSuite) TestGetFeatures() {
 	store.On("GetTelegramSettings").Return(storage.TelegramSettings{Token: ""})
 	// Mock GetSignalGroupSettings to return empty settings (disabled)
 	store.On("GetSignalGroupSettings").Return(storage.DefaultSignalGroupSettings())
+	store.On("GetSecuritySettings").Return(storage.SecuritySettings{})
 
 	h := handler{
 		storage: store,
@@ -37,7 +38,7 @@ func (s *FeaturesTestSuite) TestGetFeatures() {
 	h.GetFeatures(c)
 
 	s.Equal(http.StatusOK, w.Code)
-	s.Equal(`{"data":{"features":{"signalGroupEnabled":false,"telegramEnabled":false}},"status":"success","error":{}}`, w.Body.String())
+	s.Equal(`{"data":{"features":{"emailEnabled":false,"mailboxEnabled":false,"oidcEnabled":false,"passkeysEnabled":false,"signalGroupEnabled":false,"telegramEnabled":false,"twoFactorRequired":false}},"status":"success","error":{}}`, w.Body.String())
 }
 
 func TestFeaturesTestSuite(t *testing.T) {
@@ -43,7 +43,7 @@ func (s *FeedTestSuite) TestGetFeed() {
 
 	s.Run("when fetching messages fails", func() {
 		s.ctx.Set("ticker", storage.Ticker{})
-		s.store.On("FindMessagesByTickerAndPagination", mock.Anything, mock.Anything).Return([]storage.Message{}, errors.New("storage error")).Once()
+		s.store.On("FindMessagesByTickerAndPagination", mock.Anything, mock.Anything, mock.Anything).Return([]storage.Message{}, errors.New("storage error")).Once()
 
 		h := s.handler()
 		h.GetFeed(s.ctx)
@@ -69,7 +69,7 @@ func (s *FeedTestSuite) TestGetFeed() {
 			TickerID: ticker.ID,
 			Text:     "Text",
 		}
-		s.store.On("FindMessagesByTickerAndPagination", mock.Anything, mock.Anything).Return([]storage.Message{message}, nil).Once()
+		s.store.On("FindMessagesByTickerAndPagination", mock.Anything, mock.Anything, mock.Anything).Return([]storage.Message{message}, nil).Once()
 
 		h := s.handler()
 		h.GetFeed(s.ctx)
@@ -72,6 +72,17 @@ func Ticker(c *gin.Context) (storage.Ticker, error) {
 	return ticker.(storage.Ticker), nil
 }
 
+// TickerRole returns the role of the current user in the ticker, as set by the
+// ticker middleware.
+func TickerRole(c *gin.Context) (string, error) {
+	role, exists := c.Get("tickerRole")
+	if !exists {
+		return "", errors.New("ticker role not found")
+	}
+
+	return role.(string), nil
+}
+
 func Message(c *gin.Context) (storage.Message, error) {
 	message, exists := c.Get("message")
 	if !exists {
@@ -89,3 +100,14 @@ func User(c *gin.Context) (storage.User, error) {
 
 	return user.(storage.User), nil
 }
+
+// APIToken returns the token the request was made with. Requests with the JWT
+// have none.
+func APIToken(c *gin.Context) (storage.APIToken, error) {
+	token, exists := c.Get("apiToken")
+	if !exists {
+		return storage.APIToken{}, errors.New("api token not found")
+	}
+
+	return token.(storage.APIToken), nil
+}
@@ -175,6 +175,22 @@ func (s *UtilTestSuite) TestUser() {
 	})
 }
 
+func (s *UtilTestSuite) TestAPIToken() {
+	s.Run("when token is not set", func() {
+		c := &gin.Context{}
+		_, err := APIToken(c)
+		s.Equal("api token not found", err.Error())
+	})
+
+	s.Run("when token is set", func() {
+		c := &gin.Context{}
+		c.Set("apiToken", storage.APIToken{ID: 1})
+		token, err := APIToken(c)
+		s.NoError(err)
+		s.Equal(1, token.ID)
+	})
+}
+
 func (s *UtilTestSuite) buildContext(u url.URL, headers http.Header) *gin.Context {
 	req := http.Request{
 		Header: headers,
@@ -0,0 +1,79 @@
+package api
+
+import (
+	"bytes"
+	"errors"
+	"time"
+
+	"github.com/spf13/afero"
+	"github.com/systemli/ticker/internal/api/realtime"
+	"github.com/systemli/ticker/internal/api/response"
+	"github.com/systemli/ticker/internal/storage"
+	"github.com/systemli/ticker/internal/util"
+)
+
+// saveInboxImage stores an image sent through a messenger like an upload from
+// the admin interface: resized, apart from GIFs which are kept as they are.
+func (h *handler) saveInboxImage(ticker storage.Ticker, data []byte) (storage.Upload, error) {
+	contentType := util.DetectContentType(bytes.NewReader(data))
+	if _, allowed := storage.ExtensionForContentType(contentType); !allowed {
+		return storage.Upload{}, errors.New(contentType + " is not allowed to be uploaded")
+	}
+
+	upload := storage.NewUpload(contentType, ticker.ID)
+	if err := h.storage.SaveUpload(&upload); err != nil {
+		return storage.Upload{}, err
+	}
+
+	if err := preparePath(upload, h.config); err != nil {
+		return storage.Upload{}, err
+	}
+
+	path := upload.FullPath(h.config.Upload.Path)
+	if contentType == "image/gif" {
+		return upload, afero.WriteFile(h.config.FileBackend, path, data, 0640)
+	}
+
+	image, err := util.ResizeImage(bytes.NewReader(data), 1280)
+	if err != nil {
+		return storage.Upload{}, err
+	}
+
+	return upload, util.SaveImage(image, path)
+}
+
+// mayPostFromInbox reports whether the user may post through Telegram or
+// mail. While single sign-on is on, linked users need to have signed in
+// through the identity provider within storage.LinkedAPITokenLifetime, as
+// disabling them there does not reach these channels, like their API tokens.
+func (h *handler) mayPostFromInbox(user storage.User) bool {
+	return h.sso == nil || user.OIDCSubject == "" || time.Since(user.LastLogin) <= storage.LinkedAPITokenLifetime
+}
+
+// publishInboxMessage saves a message sent through a messenger and, unless it
+// waits for a review, hands it to the bridges and the open ticker pages. The
+// revision is attributed to the user, or to nobody for senders without one.
+func (h *handler) publishInboxMessage(ticker storage.Ticker, message *storage.Message, userID int) error {
+	if err := h.storage.SaveMessage(message); err != nil {
+		return err
+	}
+
+	h.saveRevision(*message, userID, storage.RevisionCreated)
+	h.saveMessageTags(message, nil)
+
+	if !message.IsPublished() {
+		return nil
+	}
+
+	h.deliverMessage(ticker, *message)
+	h.realtime.Broadcast(realtime.Message{
+		Type:     "message_created",
+		TickerID: ticker.ID,
+		Tags:     message.TagNames(),
+		Data: map[string]any{
+			"message": response.MessageResponse(*message),
+		},
+	})
+
+	return nil
+}
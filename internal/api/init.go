@@ -6,6 +6,7 @@ import (
 	"github.com/gin-gonic/gin"
 	"github.com/systemli/ticker/internal/api/helper"
 	"github.com/systemli/ticker/internal/api/response"
+	"github.com/systemli/ticker/internal/storage"
 )
 
 func (h *handler) GetInit(c *gin.Context) {
@@ -23,6 +24,15 @@ func (h *handler) GetInit(c *gin.Context) {
 		return
 	}
 
-	data := map[string]interface{}{"ticker": response.InitTickerResponse(ticker), "settings": settings}
+	pinned, err := h.storage.FindPinnedMessagesByTicker(ticker, storage.WithAttachments(), storage.WithTags())
+	if err != nil {
+		log.WithError(err).WithField("ticker_id", ticker.ID).Error("failed to find pinned messages")
+	}
+
+	data := map[string]interface{}{
+		"ticker":   response.InitTickerResponse(ticker),
+		"pinned":   response.TimelineResponse(pinned),
+		"settings": settings,
+	}
 	c.JSON(http.StatusOK, response.SuccessResponse(data))
 }
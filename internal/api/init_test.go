@@ -75,10 +75,12 @@ func (s *InitTestSuite) TestGetInit() {
 		ticker.Active = true
 		s.store.On("FindTickerByOrigin", "https://demoticker.org", mock.Anything).Return(ticker, nil).Once()
 		s.store.On("GetInactiveSettings").Return(storage.DefaultInactiveSettings()).Once()
+		s.store.On("FindPinnedMessagesByTicker", ticker, mock.Anything).Return([]storage.Message{{ID: 1, Pinned: true}}, nil).Once()
 		h := s.handler()
 		h.GetInit(s.ctx)
 
 		s.Equal(http.StatusOK, s.w.Code)
+		s.Contains(s.w.Body.String(), `"pinned":[{"id":1`)
 		s.store.AssertCalled(s.T(), "FindTickerByOrigin", "https://demoticker.org", mock.Anything)
 	})
 }
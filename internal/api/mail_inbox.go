@@ -0,0 +1,239 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/systemli/ticker/internal/api/helper"
+	"github.com/systemli/ticker/internal/api/response"
+	"github.com/systemli/ticker/internal/mailer"
+	"github.com/systemli/ticker/internal/storage"
+	"gorm.io/gorm"
+)
+
+// mailAttachmentLimit keeps attachments to the size of uploads through the
+// admin interface.
+const mailAttachmentLimit = 10 << 20
+
+type MailboxParam struct {
+	Active bool `json:"active"`
+	// Renew replaces the address, e.g. after it got known to others.
+	Renew bool `json:"renew"`
+}
+
+// PutTickerMailbox turns posting by mail on or off. The address is created
+// with the first activation and kept until it is renewed.
+func (h *handler) PutTickerMailbox(c *gin.Context) {
+	ticker, err := helper.Ticker(c)
+	if err != nil {
+		c.JSON(http.StatusNotFound, response.ErrorResponse(response.CodeDefault, response.TickerNotFound))
+		return
+	}
+
+	var body MailboxParam
+	err = c.Bind(&body)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, response.ErrorResponse(response.CodeNotFound, response.FormError))
+		return
+	}
+
+	if !h.config.InboundMail.Enabled() {
+		c.JSON(http.StatusBadRequest, response.ErrorResponse(response.CodeDefault, response.MailboxDisabled))
+		return
+	}
+
+	if ticker.Mailbox.Address == "" || body.Renew {
+		address, err := storage.NewMailboxAddress(h.config.InboundMail.Domain)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, response.ErrorResponse(response.CodeDefault, response.StorageError))
+			return
+		}
+		ticker.Mailbox.Address = address
+	}
+	ticker.Mailbox.Active = body.Active
+
+	err = h.storage.SaveTicker(&ticker)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, response.ErrorResponse(response.CodeDefault, response.StorageError))
+		return
+	}
+
+	c.JSON(http.StatusOK, response.SuccessResponse(map[string]interface{}{"ticker": response.TickerResponse(ticker, h.getBotUsername())}))
+}
+
+func (h *handler) DeleteTickerMailbox(c *gin.Context) {
+	ticker, err := helper.Ticker(c)
+	if err != nil {
+		c.JSON(http.StatusNotFound, response.ErrorResponse(response.CodeDefault, response.TickerNotFound))
+		return
+	}
+
+	err = h.storage.DeleteMailbox(&ticker)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, response.ErrorResponse(response.CodeDefault, response.StorageError))
+		return
+	}
+
+	c.JSON(http.StatusOK, response.SuccessResponse(map[string]interface{}{"ticker": response.TickerResponse(ticker, h.getBotUsername())}))
+}
+
+// MailInbox receives the mails sent to the addresses of the tickers with the
+// built-in mail server and posts them.
+type MailInbox struct {
+	handler *handler
+}
+
+// Run serves mails until the context is cancelled. Without inbound mail in the
+// configuration it returns right away.
+func (m *MailInbox) Run(ctx context.Context) {
+	cfg := m.handler.config.InboundMail
+	if !cfg.Enabled() {
+		return
+	}
+
+	listener, err := net.Listen("tcp", cfg.Listen)
+	if err != nil {
+		log.WithError(err).Error("failed to listen for inbound mail")
+		return
+	}
+
+	server := &mailer.Server{
+		Hostname: cfg.Domain,
+		Accept:   m.handler.acceptMail,
+		Deliver:  m.handler.deliverMail,
+	}
+	if err := server.Serve(ctx, listener); err != nil {
+		log.WithError(err).Error("failed to serve inbound mail")
+	}
+}
+
+// acceptMail reports whether the recipient is the address of a ticker which
+// takes mails.
+func (h *handler) acceptMail(recipient string) bool {
+	_, err := h.storage.FindTickerByMailboxAddress(recipient)
+
+	return err == nil
+}
+
+// deliverMail posts the mail to the ticker of every recipient. Mails which
+// cannot be read or come from someone who may not post are dropped, only
+// storage errors let the sending server try again. The retry covers all
+// recipients, so it is only asked for when the mail was posted nowhere, and
+// recipients failing next to others are given up instead of posted twice.
+func (h *handler) deliverMail(envelope mailer.Envelope) error {
+	received, err := mailer.Parse(envelope.Data)
+	if err != nil {
+		log.WithError(err).WithField("from", envelope.From).Info("ignore mail which cannot be read")
+		return nil
+	}
+
+	var errs []error
+	for _, recipient := range envelope.To {
+		if err := h.postMail(recipient, received); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	if len(errs) == len(envelope.To) {
+		return errors.Join(errs...)
+	}
+	for _, err := range errs {
+		log.WithError(err).WithField("from", envelope.From).Error("failed to post mail for one of the recipients")
+	}
+
+	return nil
+}
+
+// postMail creates a ticker message from the text and images of the mail.
+// The subject is taken as text when the body is empty. Like in the admin
+// interface, messages of contributors wait for a review, and so do all mails
+// whose sender the mail server in front did not vouch for.
+func (h *handler) postMail(recipient string, received mailer.Received) error {
+	logger := log.WithField("from", received.From)
+
+	ticker, err := h.storage.FindTickerByMailboxAddress(recipient, storage.WithPreload())
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil
+		}
+		return err
+	}
+	logger = logger.WithField("ticker_id", ticker.ID)
+
+	user, err := h.storage.FindUserByEmail(received.From)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			logger.Info("ignore mail from unknown sender")
+			return nil
+		}
+		return err
+	}
+
+	if !h.mayPostFromInbox(user) {
+		logger.Info("ignore mail from linked user who did not sign in recently")
+		return nil
+	}
+
+	role := storage.TickerRoleOwner
+	if !user.IsSuperAdmin {
+		tickerUser, err := h.storage.FindTickerUser(ticker, user)
+		if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+			return err
+		}
+		role = tickerUser.Role
+	}
+	if !storage.HasTickerRole(role, storage.TickerRoleContributor) {
+		logger.Info("ignore mail from user who cannot post to the ticker")
+		return nil
+	}
+
+	text := strings.TrimSpace(received.Text)
+	if text == "" {
+		text = strings.TrimSpace(received.Subject)
+	}
+	if text == "" {
+		logger.Info("ignore mail without text")
+		return nil
+	}
+
+	uploads := make([]storage.Upload, 0, len(received.Files))
+	for _, file := range received.Files {
+		upload, err := h.saveMailFile(ticker, file)
+		if err != nil {
+			logger.WithError(err).WithField("filename", file.Filename).Info("skip mail attachment")
+			continue
+		}
+		uploads = append(uploads, upload)
+	}
+
+	message := storage.NewMessage()
+	message.Text = text
+	message.TickerID = ticker.ID
+	message.AddAttachments(uploads)
+
+	authenticated := received.Authenticated(h.config.InboundMail.AuthServID)
+	if !authenticated {
+		logger.Info("hold mail from unauthenticated sender for review")
+	}
+	if !authenticated || !storage.HasTickerRole(role, storage.TickerRoleEditor) {
+		message.Draft = true
+		message.Review = storage.ReviewPending
+	}
+
+	return h.publishInboxMessage(ticker, &message, user.ID)
+}
+
+func (h *handler) saveMailFile(ticker storage.Ticker, file mailer.File) (storage.Upload, error) {
+	if !strings.HasPrefix(file.ContentType, "image/") {
+		return storage.Upload{}, errors.New(file.ContentType + " is not allowed to be uploaded")
+	}
+	if len(file.Data) > mailAttachmentLimit {
+		return storage.Upload{}, errors.New("attachment is too large")
+	}
+
+	return h.saveInboxImage(ticker, file.Data)
+}
@@ -0,0 +1,398 @@
+package api
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+	"github.com/systemli/ticker/internal/api/realtime"
+	"github.com/systemli/ticker/internal/api/response"
+	"github.com/systemli/ticker/internal/config"
+	"github.com/systemli/ticker/internal/mailer"
+	"github.com/systemli/ticker/internal/sso"
+	"github.com/systemli/ticker/internal/storage"
+	"gorm.io/gorm"
+)
+
+const mailTestAddress = "0123456789abcdef0123456789abcdef@ticker.example.org"
+
+type MailInboxTestSuite struct {
+	w     *httptest.ResponseRecorder
+	ctx   *gin.Context
+	store *storage.MockStorage
+	cfg   config.Config
+	suite.Suite
+}
+
+func (s *MailInboxTestSuite) SetupTest() {
+	gin.SetMode(gin.TestMode)
+}
+
+func (s *MailInboxTestSuite) Run(name string, subtest func()) {
+	s.T().Run(name, func(t *testing.T) {
+		s.w = httptest.NewRecorder()
+		s.ctx, _ = gin.CreateTestContext(s.w)
+		s.store = &storage.MockStorage{}
+		s.store.On("GetTelegramSettings").Return(storage.TelegramSettings{}).Maybe()
+		s.cfg = config.LoadConfig("")
+		s.cfg.Upload.Path = t.TempDir()
+		s.cfg.InboundMail = config.InboundMail{Listen: "127.0.0.1:0", Domain: "ticker.example.org", AuthServID: "mx.example.org"}
+
+		subtest()
+	})
+}
+
+func (s *MailInboxTestSuite) TestPutTickerMailbox() {
+	s.Run("when ticker not found", func() {
+		h := s.handler()
+		h.PutTickerMailbox(s.ctx)
+
+		s.Equal(http.StatusNotFound, s.w.Code)
+	})
+
+	s.Run("when inbound mail is not configured", func() {
+		s.cfg.InboundMail = config.InboundMail{}
+		s.ctx.Set("ticker", storage.Ticker{ID: 1})
+		s.ctx.Request = httptest.NewRequest(http.MethodPut, "/v1/admin/tickers/1/mailbox", strings.NewReader(`{"active":true}`))
+		s.ctx.Request.Header.Add("Content-Type", "application/json")
+
+		h := s.handler()
+		h.PutTickerMailbox(s.ctx)
+
+		s.Equal(http.StatusBadRequest, s.w.Code)
+		s.Contains(s.w.Body.String(), response.MailboxDisabled)
+	})
+
+	s.Run("when storage returns error", func() {
+		s.ctx.Set("ticker", storage.Ticker{ID: 1})
+		s.ctx.Request = httptest.NewRequest(http.MethodPut, "/v1/admin/tickers/1/mailbox", strings.NewReader(`{"active":true}`))
+		s.ctx.Request.Header.Add("Content-Type", "application/json")
+		s.store.On("SaveTicker", mock.Anything).Return(errors.New("storage error")).Once()
+
+		h := s.handler()
+		h.PutTickerMailbox(s.ctx)
+
+		s.Equal(http.StatusBadRequest, s.w.Code)
+		s.store.AssertExpectations(s.T())
+	})
+
+	s.Run("when the mailbox is turned on", func() {
+		s.ctx.Set("ticker", storage.Ticker{ID: 1})
+		s.ctx.Request = httptest.NewRequest(http.MethodPut, "/v1/admin/tickers/1/mailbox", strings.NewReader(`{"active":true}`))
+		s.ctx.Request.Header.Add("Content-Type", "application/json")
+		s.store.On("SaveTicker", mock.MatchedBy(func(t *storage.Ticker) bool {
+			return t.Mailbox.Active && strings.HasSuffix(t.Mailbox.Address, "@ticker.example.org")
+		})).Return(nil).Once()
+
+		h := s.handler()
+		h.PutTickerMailbox(s.ctx)
+
+		s.Equal(http.StatusOK, s.w.Code)
+		s.Contains(s.w.Body.String(), `"mailbox":{"active":true,"address":"`)
+		s.store.AssertExpectations(s.T())
+	})
+
+	s.Run("when the mailbox is turned off", func() {
+		s.ctx.Set("ticker", storage.Ticker{ID: 1, Mailbox: storage.TickerMailbox{Active: true, Address: mailTestAddress}})
+		s.ctx.Request = httptest.NewRequest(http.MethodPut, "/v1/admin/tickers/1/mailbox", strings.NewReader(`{"active":false}`))
+		s.ctx.Request.Header.Add("Content-Type", "application/json")
+		s.store.On("SaveTicker", mock.MatchedBy(func(t *storage.Ticker) bool {
+			return !t.Mailbox.Active && t.Mailbox.Address == mailTestAddress
+		})).Return(nil).Once()
+
+		h := s.handler()
+		h.PutTickerMailbox(s.ctx)
+
+		s.Equal(http.StatusOK, s.w.Code)
+		s.store.AssertExpectations(s.T())
+	})
+
+	s.Run("when the address is renewed", func() {
+		s.ctx.Set("ticker", storage.Ticker{ID: 1, Mailbox: storage.TickerMailbox{Active: true, Address: mailTestAddress}})
+		s.ctx.Request = httptest.NewRequest(http.MethodPut, "/v1/admin/tickers/1/mailbox", strings.NewReader(`{"active":true,"renew":true}`))
+		s.ctx.Request.Header.Add("Content-Type", "application/json")
+		s.store.On("SaveTicker", mock.MatchedBy(func(t *storage.Ticker) bool {
+			return t.Mailbox.Active && t.Mailbox.Address != mailTestAddress
+		})).Return(nil).Once()
+
+		h := s.handler()
+		h.PutTickerMailbox(s.ctx)
+
+		s.Equal(http.StatusOK, s.w.Code)
+		s.store.AssertExpectations(s.T())
+	})
+}
+
+func (s *MailInboxTestSuite) TestDeleteTickerMailbox() {
+	s.Run("when ticker not found", func() {
+		h := s.handler()
+		h.DeleteTickerMailbox(s.ctx)
+
+		s.Equal(http.StatusNotFound, s.w.Code)
+	})
+
+	s.Run("when storage returns error", func() {
+		s.ctx.Set("ticker", storage.Ticker{ID: 1, Mailbox: storage.TickerMailbox{Active: true, Address: mailTestAddress}})
+		s.store.On("DeleteMailbox", mock.Anything).Return(errors.New("storage error")).Once()
+
+		h := s.handler()
+		h.DeleteTickerMailbox(s.ctx)
+
+		s.Equal(http.StatusBadRequest, s.w.Code)
+		s.store.AssertExpectations(s.T())
+	})
+
+	s.Run("when the mailbox is deleted", func() {
+		s.ctx.Set("ticker", storage.Ticker{ID: 1, Mailbox: storage.TickerMailbox{Active: true, Address: mailTestAddress}})
+		s.store.On("DeleteMailbox", mock.Anything).Return(nil).Once()
+
+		h := s.handler()
+		h.DeleteTickerMailbox(s.ctx)
+
+		s.Equal(http.StatusOK, s.w.Code)
+		s.store.AssertExpectations(s.T())
+	})
+}
+
+func (s *MailInboxTestSuite) TestDeliverMail() {
+	ticker := storage.Ticker{ID: 1, Title: "Ticker", Mailbox: storage.TickerMailbox{Active: true, Address: mailTestAddress}}
+	user := storage.User{ID: 2, Email: "reporter@example.org"}
+	envelope := mailer.Envelope{From: "reporter@example.org", To: []string{mailTestAddress}, Data: s.mail("Update", "Police blocks the street #demo", nil)}
+
+	s.Run("when the mail cannot be read", func() {
+		h := s.handler()
+		err := h.deliverMail(mailer.Envelope{From: "reporter@example.org", To: []string{mailTestAddress}, Data: []byte("no mail")})
+
+		s.NoError(err)
+		s.store.AssertExpectations(s.T())
+	})
+
+	s.Run("when the mailbox is gone", func() {
+		s.store.On("FindTickerByMailboxAddress", mailTestAddress, mock.Anything).Return(storage.Ticker{}, gorm.ErrRecordNotFound).Once()
+
+		h := s.handler()
+		s.NoError(h.deliverMail(envelope))
+		s.store.AssertExpectations(s.T())
+	})
+
+	s.Run("when the sender is unknown", func() {
+		s.store.On("FindTickerByMailboxAddress", mailTestAddress, mock.Anything).Return(ticker, nil).Once()
+		s.store.On("FindUserByEmail", "reporter@example.org").Return(storage.User{}, gorm.ErrRecordNotFound).Once()
+
+		h := s.handler()
+		s.NoError(h.deliverMail(envelope))
+		s.store.AssertExpectations(s.T())
+	})
+
+	s.Run("when the user cannot post to the ticker", func() {
+		s.store.On("FindTickerByMailboxAddress", mailTestAddress, mock.Anything).Return(ticker, nil).Once()
+		s.store.On("FindUserByEmail", "reporter@example.org").Return(user, nil).Once()
+		s.store.On("FindTickerUser", ticker, user).Return(storage.TickerUser{Role: storage.TickerRoleViewer}, nil).Once()
+
+		h := s.handler()
+		s.NoError(h.deliverMail(envelope))
+		s.store.AssertExpectations(s.T())
+	})
+
+	s.Run("when linked user did not sign in recently", func() {
+		linked := user
+		linked.OIDCSubject = "subject"
+		linked.LastLogin = time.Now().Add(-storage.LinkedAPITokenLifetime - time.Hour)
+		s.store.On("FindTickerByMailboxAddress", mailTestAddress, mock.Anything).Return(ticker, nil).Once()
+		s.store.On("FindUserByEmail", "reporter@example.org").Return(linked, nil).Once()
+
+		h := s.handler()
+		h.sso = sso.New(config.OIDC{Issuer: "http://127.0.0.1:1"}, s.store)
+		s.NoError(h.deliverMail(envelope))
+		s.store.AssertExpectations(s.T())
+	})
+
+	s.Run("when storage returns error", func() {
+		s.store.On("FindTickerByMailboxAddress", mailTestAddress, mock.Anything).Return(storage.Ticker{}, errors.New("storage error")).Once()
+
+		h := s.handler()
+		s.Error(h.deliverMail(envelope))
+		s.store.AssertExpectations(s.T())
+	})
+
+	s.Run("when storage returns error for one of the recipients", func() {
+		other := "other@ticker.example.org"
+		s.store.On("FindTickerByMailboxAddress", mailTestAddress, mock.Anything).Return(ticker, nil).Once()
+		s.store.On("FindTickerByMailboxAddress", other, mock.Anything).Return(storage.Ticker{}, errors.New("storage error")).Once()
+		s.store.On("FindUserByEmail", "reporter@example.org").Return(user, nil).Once()
+		s.store.On("FindTickerUser", ticker, user).Return(storage.TickerUser{Role: storage.TickerRoleContributor}, nil).Once()
+		s.store.On("SaveMessage", mock.Anything).Return(nil).Once()
+		s.store.On("SaveMessageRevision", mock.Anything).Return(nil).Once()
+		s.store.On("SaveMessageTags", mock.Anything, mock.Anything).Return(nil).Once()
+
+		h := s.handler()
+		s.NoError(h.deliverMail(mailer.Envelope{From: envelope.From, To: []string{mailTestAddress, other}, Data: envelope.Data}))
+		s.store.AssertExpectations(s.T())
+	})
+
+	s.Run("when a contributor sends a mail", func() {
+		s.store.On("FindTickerByMailboxAddress", mailTestAddress, mock.Anything).Return(ticker, nil).Once()
+		s.store.On("FindUserByEmail", "reporter@example.org").Return(user, nil).Once()
+		s.store.On("FindTickerUser", ticker, user).Return(storage.TickerUser{Role: storage.TickerRoleContributor}, nil).Once()
+		s.store.On("SaveMessage", mock.MatchedBy(func(m *storage.Message) bool {
+			return m.Text == "Police blocks the street #demo" && m.Draft && m.Review == storage.ReviewPending
+		})).Return(nil).Once()
+		s.store.On("SaveMessageRevision", mock.MatchedBy(func(r *storage.MessageRevision) bool {
+			return r.UserID == 2
+		})).Return(nil).Once()
+		s.store.On("SaveMessageTags", mock.Anything, []string{"demo"}).Return(nil).Once()
+
+		h := s.handler()
+		s.NoError(h.deliverMail(envelope))
+		s.store.AssertExpectations(s.T())
+	})
+
+	s.Run("when the sender is not authenticated", func() {
+		s.store.On("FindTickerByMailboxAddress", mailTestAddress, mock.Anything).Return(ticker, nil).Once()
+		s.store.On("FindUserByEmail", "reporter@example.org").Return(storage.User{ID: 2, Email: "reporter@example.org", IsSuperAdmin: true}, nil).Once()
+		s.store.On("SaveMessage", mock.MatchedBy(func(m *storage.Message) bool {
+			return m.Draft && m.Review == storage.ReviewPending
+		})).Return(nil).Once()
+		s.store.On("SaveMessageRevision", mock.Anything).Return(nil).Once()
+		s.store.On("SaveMessageTags", mock.Anything, mock.Anything).Return(nil).Once()
+
+		h := s.handler()
+		s.NoError(h.deliverMail(mailer.Envelope{From: "reporter@example.org", To: []string{mailTestAddress}, Data: s.unauthenticatedMail("Update", "Police blocks the street")}))
+		s.store.AssertExpectations(s.T())
+	})
+
+	s.Run("when an editor sends a mail with images", func() {
+		photo, err := os.ReadFile("../../testdata/gopher.jpg")
+		s.Require().NoError(err)
+
+		s.store.On("FindTickerByMailboxAddress", mailTestAddress, mock.Anything).Return(ticker, nil).Once()
+		s.store.On("FindUserByEmail", "reporter@example.org").Return(user, nil).Once()
+		s.store.On("FindTickerUser", ticker, user).Return(storage.TickerUser{Role: storage.TickerRoleEditor}, nil).Once()
+		s.store.On("SaveUpload", mock.MatchedBy(func(u *storage.Upload) bool {
+			return u.TickerID == 1 && u.ContentType == "image/jpeg"
+		})).Return(nil).Once()
+		s.store.On("SaveMessage", mock.MatchedBy(func(m *storage.Message) bool {
+			return m.Text == "Update" && !m.Draft && len(m.Attachments) == 1
+		})).Return(nil).Once()
+		s.store.On("SaveMessageRevision", mock.Anything).Return(nil).Once()
+		s.store.On("SaveMessageTags", mock.Anything, mock.Anything).Return(nil).Once()
+
+		h := s.handler()
+		err = h.deliverMail(mailer.Envelope{
+			From: "reporter@example.org",
+			To:   []string{mailTestAddress},
+			Data: s.mail("Update", "", map[string][]byte{"image/jpeg": photo, "application/pdf": []byte("%PDF-1.4")}),
+		})
+		s.NoError(err)
+		s.store.AssertExpectations(s.T())
+	})
+}
+
+func (s *MailInboxTestSuite) TestRun() {
+	s.Run("when inbound mail is not configured", func() {
+		s.cfg.InboundMail = config.InboundMail{}
+		h := s.handler()
+		inbox := &MailInbox{handler: &h}
+
+		done := make(chan struct{})
+		go func() {
+			inbox.Run(context.Background())
+			close(done)
+		}()
+
+		select {
+		case <-done:
+		case <-time.After(time.Second):
+			s.Fail("inbox did not return")
+		}
+	})
+
+	s.Run("when a mail is received", func() {
+		listener, err := net.Listen("tcp", "127.0.0.1:0")
+		s.Require().NoError(err)
+		addr := listener.Addr().(*net.TCPAddr)
+		s.Require().NoError(listener.Close())
+		s.cfg.InboundMail.Listen = addr.String()
+
+		ticker := storage.Ticker{ID: 1, Mailbox: storage.TickerMailbox{Active: true, Address: mailTestAddress}}
+		user := storage.User{ID: 2, Email: "reporter@example.org", IsSuperAdmin: true}
+		s.store.On("FindTickerByMailboxAddress", mailTestAddress, mock.Anything).Return(ticker, nil)
+		s.store.On("FindTickerByMailboxAddress", mock.Anything, mock.Anything).Return(storage.Ticker{}, gorm.ErrRecordNotFound)
+		s.store.On("FindUserByEmail", "reporter@example.org").Return(user, nil).Once()
+		s.store.On("SaveMessage", mock.Anything).Return(nil).Once()
+		s.store.On("SaveMessageRevision", mock.Anything).Return(nil).Once()
+		s.store.On("SaveMessageTags", mock.Anything, mock.Anything).Return(nil).Once()
+
+		h := s.handler()
+		inbox := &MailInbox{handler: &h}
+
+		ctx, cancel := context.WithCancel(context.Background())
+		done := make(chan struct{})
+		go func() {
+			inbox.Run(ctx)
+			close(done)
+		}()
+
+		client, err := mailer.NewClient(config.SMTP{Host: addr.IP.String(), Port: addr.Port, From: "reporter@example.org"})
+		s.Require().NoError(err)
+		s.Eventually(func() bool {
+			return client.Send(context.Background(), mailer.Mail{To: mailTestAddress, Subject: "Update", Text: "From the field"}) == nil
+		}, time.Second, 10*time.Millisecond)
+
+		err = client.Send(context.Background(), mailer.Mail{To: "other@ticker.example.org", Text: "Relay me"})
+		s.ErrorContains(err, "550")
+
+		cancel()
+		select {
+		case <-done:
+		case <-time.After(time.Second):
+			s.Fail("inbox did not stop")
+		}
+		s.store.AssertExpectations(s.T())
+	})
+}
+
+// mail returns a mail from the reporter with the text and the files as
+// attachments, keyed by their content type. The mail server in front vouches
+// for the sender.
+func (s *MailInboxTestSuite) mail(subject, text string, files map[string][]byte) []byte {
+	var b strings.Builder
+	b.WriteString("Authentication-Results: mx.example.org; dkim=pass header.d=example.org; dmarc=pass header.from=example.org\r\n")
+	fmt.Fprintf(&b, "From: Reporter <reporter@example.org>\r\nTo: %s\r\nSubject: %s\r\nMIME-Version: 1.0\r\n", mailTestAddress, subject)
+	b.WriteString("Content-Type: multipart/mixed; boundary=\"boundary\"\r\n\r\n")
+	fmt.Fprintf(&b, "--boundary\r\nContent-Type: text/plain; charset=utf-8\r\n\r\n%s\r\n", text)
+	for contentType, data := range files {
+		fmt.Fprintf(&b, "--boundary\r\nContent-Type: %s\r\nContent-Disposition: attachment; filename=\"file\"\r\nContent-Transfer-Encoding: base64\r\n\r\n%s\r\n", contentType, base64.StdEncoding.EncodeToString(data))
+	}
+	b.WriteString("--boundary--\r\n")
+
+	return []byte(b.String())
+}
+
+// unauthenticatedMail returns a mail from the reporter as anyone could send it.
+func (s *MailInboxTestSuite) unauthenticatedMail(subject, text string) []byte {
+	return []byte(fmt.Sprintf("From: reporter@example.org\r\nTo: %s\r\nSubject: %s\r\n\r\n%s\r\n", mailTestAddress, subject, text))
+}
+
+func (s *MailInboxTestSuite) handler() handler {
+	return handler{
+		storage:  s.store,
+		config:   s.cfg,
+		realtime: realtime.New(),
+	}
+}
+
+func TestMailInboxTestSuite(t *testing.T) {
+	suite.Run(t, new(MailInboxTestSuite))
+}
@@ -1,9 +1,13 @@
 package api
 
 import (
+	"encoding/json"
 	"fmt"
 	"net/http"
+	"slices"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/systemli/ticker/internal/api/helper"
@@ -11,6 +15,8 @@ import (
 	"github.com/systemli/ticker/internal/api/realtime"
 	"github.com/systemli/ticker/internal/api/response"
 	"github.com/systemli/ticker/internal/storage"
+	"github.com/systemli/ticker/internal/util"
+	"gorm.io/gorm"
 )
 
 func (h *handler) GetMessages(c *gin.Context) {
@@ -21,7 +27,47 @@ func (h *handler) GetMessages(c *gin.Context) {
 	}
 
 	pagination := pagination.NewPagination(c)
-	messages, err := h.storage.FindMessagesByTickerAndPagination(ticker, *pagination, storage.WithAttachments())
+	opts := append(messageListOptions(c), storage.WithDeliveries())
+	var messages []storage.Message
+	if query := c.Query("q"); query != "" {
+		messages, err = h.storage.SearchMessagesByTicker(ticker, query, *pagination, opts...)
+	} else {
+		messages, err = h.storage.FindMessagesByTickerAndPagination(ticker, *pagination, opts...)
+	}
+	if err != nil {
+		c.JSON(http.StatusNotFound, response.ErrorResponse(response.CodeDefault, response.StorageError))
+		return
+	}
+
+	data := map[string]any{"messages": response.MessagesResponse(messages)}
+	c.JSON(http.StatusOK, response.SuccessResponse(data))
+}
+
+func (h *handler) GetScheduledMessages(c *gin.Context) {
+	ticker, err := helper.Ticker(c)
+	if err != nil {
+		c.JSON(http.StatusNotFound, response.ErrorResponse(response.CodeDefault, response.TickerNotFound))
+		return
+	}
+
+	messages, err := h.storage.FindScheduledMessagesByTicker(ticker, authorMessageOptions(c)...)
+	if err != nil {
+		c.JSON(http.StatusNotFound, response.ErrorResponse(response.CodeDefault, response.StorageError))
+		return
+	}
+
+	data := map[string]any{"messages": response.MessagesResponse(messages)}
+	c.JSON(http.StatusOK, response.SuccessResponse(data))
+}
+
+func (h *handler) GetDraftMessages(c *gin.Context) {
+	ticker, err := helper.Ticker(c)
+	if err != nil {
+		c.JSON(http.StatusNotFound, response.ErrorResponse(response.CodeDefault, response.TickerNotFound))
+		return
+	}
+
+	messages, err := h.storage.FindDraftMessagesByTicker(ticker, authorMessageOptions(c)...)
 	if err != nil {
 		c.JSON(http.StatusNotFound, response.ErrorResponse(response.CodeDefault, response.StorageError))
 		return
@@ -38,10 +84,90 @@ func (h *handler) GetMessage(c *gin.Context) {
 		return
 	}
 
+	// The deliveries are only loaded here, as the message of the request is
+	// also sent to the readers in the realtime events.
+	message, err = h.storage.FindMessage(message.TickerID, message.ID, storage.WithAttachments(), storage.WithTags(), storage.WithDeliveries())
+	if err != nil {
+		c.JSON(http.StatusNotFound, response.ErrorResponse(response.CodeDefault, response.MessageNotFound))
+		return
+	}
+
 	data := map[string]any{"message": response.MessageResponse(message)}
 	c.JSON(http.StatusOK, response.SuccessResponse(data))
 }
 
+// GetMessageRevisions returns the history of a message. It is looked up by
+// the ID alone, so the history of deleted messages stays available.
+func (h *handler) GetMessageRevisions(c *gin.Context) {
+	ticker, err := helper.Ticker(c)
+	if err != nil {
+		c.JSON(http.StatusNotFound, response.ErrorResponse(response.CodeDefault, response.TickerNotFound))
+		return
+	}
+
+	messageID, err := strconv.Atoi(c.Param("messageID"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, response.ErrorResponse(response.CodeDefault, response.TickerIdentifierMissing))
+		return
+	}
+
+	revisions, err := h.storage.FindMessageRevisions(ticker.ID, messageID)
+	if err != nil {
+		c.JSON(http.StatusNotFound, response.ErrorResponse(response.CodeDefault, response.StorageError))
+		return
+	}
+
+	// Contributors only follow the messages they wrote.
+	if authorOnly(c) {
+		me, _ := helper.Me(c)
+		if len(revisions) == 0 || revisions[0].Action != storage.RevisionCreated || revisions[0].UserID != me.ID {
+			c.JSON(http.StatusForbidden, response.ErrorResponse(response.CodeInsufficientPermissions, response.InsufficientPermissions))
+			return
+		}
+	}
+
+	// Messages written before the history was recorded have no revisions.
+	if len(revisions) == 0 {
+		if _, err := h.storage.FindMessage(ticker.ID, messageID); err != nil {
+			c.JSON(http.StatusNotFound, response.ErrorResponse(response.CodeNotFound, response.MessageNotFound))
+			return
+		}
+	}
+
+	userIDs := make([]int, 0)
+	for _, revision := range revisions {
+		userIDs = append(userIDs, revision.UserID)
+	}
+
+	users, err := h.storage.FindUsersByIDs(userIDs)
+	if err != nil {
+		c.JSON(http.StatusNotFound, response.ErrorResponse(response.CodeDefault, response.StorageError))
+		return
+	}
+
+	data := map[string]any{"revisions": response.MessageRevisionsResponse(revisions, users)}
+	c.JSON(http.StatusOK, response.SuccessResponse(data))
+}
+
+// GetMessageTags lists the tags used in the published messages of the ticker
+// with the number of messages for each.
+func (h *handler) GetMessageTags(c *gin.Context) {
+	ticker, err := helper.Ticker(c)
+	if err != nil {
+		c.JSON(http.StatusNotFound, response.ErrorResponse(response.CodeDefault, response.TickerNotFound))
+		return
+	}
+
+	tags, err := h.storage.FindTagsByTicker(ticker)
+	if err != nil {
+		c.JSON(http.StatusNotFound, response.ErrorResponse(response.CodeDefault, response.StorageError))
+		return
+	}
+
+	data := map[string]any{"tags": response.MessageTagsResponse(tags)}
+	c.JSON(http.StatusOK, response.SuccessResponse(data))
+}
+
 func (h *handler) PostMessage(c *gin.Context) {
 	ticker, err := helper.Ticker(c)
 	if err != nil {
@@ -50,8 +176,13 @@ func (h *handler) PostMessage(c *gin.Context) {
 	}
 
 	var body struct {
-		Text        string `json:"text" binding:"required"`
-		Attachments []int  `json:"attachments"`
+		Text        string            `json:"text" binding:"required"`
+		Attachments []int             `json:"attachments"`
+		PublishAt   *time.Time        `json:"publishAt"`
+		Draft       bool              `json:"draft"`
+		Tags        []string          `json:"tags"`
+		Geometry    *storage.Geometry `json:"geometry"`
+		Bridges     []string          `json:"bridges"`
 	}
 	err = c.Bind(&body)
 	if err != nil {
@@ -59,6 +190,18 @@ func (h *handler) PostMessage(c *gin.Context) {
 		return
 	}
 
+	if body.Geometry != nil && body.Geometry.Validate() != nil {
+		c.JSON(http.StatusBadRequest, response.ErrorResponse(response.CodeDefault, response.GeometryInvalid))
+		return
+	}
+
+	for _, name := range body.Bridges {
+		if _, ok := h.bridges[name]; !ok {
+			c.JSON(http.StatusBadRequest, response.ErrorResponse(response.CodeDefault, response.BridgeUnknown))
+			return
+		}
+	}
+
 	var uploads []storage.Upload
 	if len(body.Attachments) > 0 {
 		uploads, err = h.storage.FindUploadsByIDs(body.Attachments)
@@ -71,9 +214,21 @@ func (h *handler) PostMessage(c *gin.Context) {
 	message := storage.NewMessage()
 	message.Text = body.Text
 	message.TickerID = ticker.ID
+	message.Geometry = body.Geometry
+	message.Bridges = body.Bridges
 	message.AddAttachments(uploads)
 
-	_ = h.bridges.Send(ticker, &message)
+	// A publishing date in the past is treated like no date at all.
+	if body.PublishAt != nil && body.PublishAt.After(time.Now()) {
+		message.PublishAt = body.PublishAt
+	}
+
+	message.Draft = body.Draft
+
+	if h.isContributor(c) {
+		message.Draft = true
+		message.Review = storage.ReviewPending
+	}
 
 	err = h.storage.SaveMessage(&message)
 	if err != nil {
@@ -81,10 +236,213 @@ func (h *handler) PostMessage(c *gin.Context) {
 		return
 	}
 
+	h.saveMessageRevision(c, message, storage.RevisionCreated)
+	h.saveMessageTags(&message, body.Tags)
+
+	if message.IsPublished() {
+		h.deliverMessage(ticker, message)
+	}
+
 	serializedMessage := response.MessageResponse(message)
+	if !message.IsPublished() {
+		c.JSON(http.StatusOK, response.SuccessResponse(map[string]any{"message": serializedMessage}))
+		return
+	}
+
 	h.realtime.Broadcast(realtime.Message{
 		Type:     "message_created",
 		TickerID: ticker.ID,
+		Tags:     message.TagNames(),
+		Origin:   helper.GetOriginHost(c),
+		Data: map[string]any{
+			"message": serializedMessage,
+		},
+	})
+
+	c.JSON(http.StatusOK, response.SuccessResponse(map[string]any{"message": serializedMessage}))
+}
+
+func (h *handler) PutMessage(c *gin.Context) {
+	ticker, err := helper.Ticker(c)
+	if err != nil {
+		c.JSON(http.StatusNotFound, response.ErrorResponse(response.CodeDefault, response.TickerNotFound))
+		return
+	}
+
+	message, err := helper.Message(c)
+	if err != nil {
+		c.JSON(http.StatusNotFound, response.ErrorResponse(response.CodeDefault, response.MessageNotFound))
+		return
+	}
+
+	var body struct {
+		Text string `json:"text" binding:"required"`
+		// Tags replace the tags of the message. Without them, the tags
+		// which were set explicitly are kept.
+		Tags     *[]string     `json:"tags"`
+		Geometry geometryParam `json:"geometry"`
+	}
+	err = c.Bind(&body)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, response.ErrorResponse(response.CodeDefault, response.FormError))
+		return
+	}
+
+	if body.Geometry.Geometry != nil && body.Geometry.Geometry.Validate() != nil {
+		c.JSON(http.StatusBadRequest, response.ErrorResponse(response.CodeDefault, response.GeometryInvalid))
+		return
+	}
+
+	previousTags := message.TagNames()
+	tags := explicitTags(message)
+	if body.Tags != nil {
+		tags = *body.Tags
+	}
+
+	message.Text = body.Text
+	if body.Geometry.Set {
+		message.Geometry = body.Geometry.Geometry
+	}
+
+	err = h.storage.SaveMessage(&message)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, response.ErrorResponse(response.CodeDefault, response.StorageError))
+		return
+	}
+
+	if message.IsPublished() {
+		h.editMessage(ticker, message)
+	}
+
+	h.saveMessageRevision(c, message, storage.RevisionEdited)
+	h.saveMessageTags(&message, tags)
+
+	serializedMessage := response.MessageResponse(message)
+	if !message.IsPublished() {
+		c.JSON(http.StatusOK, response.SuccessResponse(map[string]any{"message": serializedMessage}))
+		return
+	}
+
+	h.ClearMessagesCache(&ticker)
+	if message.Pinned {
+		h.ClearTickerCache(&ticker)
+	}
+	// Clients filtering by a tag the message lost still need the edit.
+	h.realtime.Broadcast(realtime.Message{
+		Type:     "message_updated",
+		TickerID: ticker.ID,
+		Tags:     append(previousTags, message.TagNames()...),
+		Origin:   helper.GetOriginHost(c),
+		Data: map[string]any{
+			"message": serializedMessage,
+		},
+	})
+
+	c.JSON(http.StatusOK, response.SuccessResponse(map[string]any{"message": serializedMessage}))
+}
+
+// PublishDraftMessage publishes a draft. Messages from contributors have to go
+// through the review instead.
+func (h *handler) PublishDraftMessage(c *gin.Context) {
+	ticker, err := helper.Ticker(c)
+	if err != nil {
+		c.JSON(http.StatusNotFound, response.ErrorResponse(response.CodeDefault, response.TickerNotFound))
+		return
+	}
+
+	message, err := helper.Message(c)
+	if err != nil {
+		c.JSON(http.StatusNotFound, response.ErrorResponse(response.CodeDefault, response.MessageNotFound))
+		return
+	}
+
+	if !message.Draft {
+		c.JSON(http.StatusBadRequest, response.ErrorResponse(response.CodeDefault, response.MessageNotDraft))
+		return
+	}
+
+	if message.IsPendingReview() {
+		c.JSON(http.StatusBadRequest, response.ErrorResponse(response.CodeDefault, response.MessagePendingReview))
+		return
+	}
+
+	h.publishDraft(c, ticker, message, storage.RevisionPublished)
+}
+
+// publishDraft publishes a draft and writes the response. A draft with a
+// publishing date in the future becomes a scheduled message instead. The
+// action is recorded in the history of the message.
+func (h *handler) publishDraft(c *gin.Context, ticker storage.Ticker, message storage.Message, action string) {
+	var err error
+	if message.PublishAt != nil && message.PublishAt.After(time.Now()) {
+		message.Draft = false
+		err = h.storage.SaveMessage(&message)
+	} else {
+		err = h.publishMessage(ticker, &message, helper.GetOriginHost(c))
+	}
+	if err != nil {
+		c.JSON(http.StatusBadRequest, response.ErrorResponse(response.CodeDefault, response.StorageError))
+		return
+	}
+
+	h.saveMessageRevision(c, message, action)
+
+	c.JSON(http.StatusOK, response.SuccessResponse(map[string]any{"message": response.MessageResponse(message)}))
+}
+
+func (h *handler) PinMessage(c *gin.Context) {
+	h.setMessagePinned(c, true)
+}
+
+func (h *handler) UnpinMessage(c *gin.Context) {
+	h.setMessagePinned(c, false)
+}
+
+// setMessagePinned pins or unpins a published message, on the bridges as well
+// as in the timeline.
+func (h *handler) setMessagePinned(c *gin.Context, pinned bool) {
+	ticker, err := helper.Ticker(c)
+	if err != nil {
+		c.JSON(http.StatusNotFound, response.ErrorResponse(response.CodeDefault, response.TickerNotFound))
+		return
+	}
+
+	message, err := helper.Message(c)
+	if err != nil {
+		c.JSON(http.StatusNotFound, response.ErrorResponse(response.CodeDefault, response.MessageNotFound))
+		return
+	}
+
+	if !message.IsPublished() {
+		c.JSON(http.StatusBadRequest, response.ErrorResponse(response.CodeDefault, response.MessageNotPublished))
+		return
+	}
+
+	message.Pinned = pinned
+
+	eventType, action := "message_pinned", storage.RevisionPinned
+	if pinned {
+		_ = h.bridges.Pin(ticker, &message)
+	} else {
+		eventType, action = "message_unpinned", storage.RevisionUnpinned
+		_ = h.bridges.Unpin(ticker, &message)
+	}
+
+	err = h.storage.SaveMessage(&message)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, response.ErrorResponse(response.CodeDefault, response.StorageError))
+		return
+	}
+
+	h.saveMessageRevision(c, message, action)
+
+	h.ClearMessagesCache(&ticker)
+	h.ClearTickerCache(&ticker)
+
+	serializedMessage := response.MessageResponse(message)
+	h.realtime.Broadcast(realtime.Message{
+		Type:     eventType,
+		TickerID: ticker.ID,
 		Origin:   helper.GetOriginHost(c),
 		Data: map[string]any{
 			"message": serializedMessage,
@@ -107,15 +465,32 @@ func (h *handler) DeleteMessage(c *gin.Context) {
 		return
 	}
 
-	_ = h.bridges.Delete(ticker, &message)
+	// The bridges remove the message in the background, after the sends and
+	// edits queued before. Bridges disabled in the meantime are asked as well,
+	// they only delete what they stored on the message.
+	var bridges []string
+	if message.IsPublished() {
+		for name := range h.bridges {
+			if message.SendsTo(name) {
+				bridges = append(bridges, name)
+			}
+		}
+		slices.Sort(bridges)
+	}
 
-	err = h.storage.DeleteMessage(message)
+	err = h.storage.DeleteMessage(message, bridges)
 	if err != nil {
 		c.JSON(http.StatusNotFound, response.ErrorResponse(response.CodeDefault, response.StorageError))
 		return
 	}
 
+	h.saveMessageRevision(c, message, storage.RevisionDeleted)
+
 	h.ClearMessagesCache(&ticker)
+	// Pinned messages are part of the init response.
+	if message.Pinned {
+		h.ClearTickerCache(&ticker)
+	}
 
 	h.realtime.Broadcast(realtime.Message{
 		Type:     "message_deleted",
@@ -129,6 +504,54 @@ func (h *handler) DeleteMessage(c *gin.Context) {
 	c.JSON(http.StatusOK, response.SuccessResponse(map[string]any{}))
 }
 
+// publishMessage moves a draft or scheduled message into the timeline and
+// sends it out like a newly posted message. Claiming the message in the
+// storage first makes sure it is only sent once, even when it was deleted or
+// published by someone else in the meantime.
+func (h *handler) publishMessage(ticker storage.Ticker, message *storage.Message, origin string) error {
+	if err := h.storage.PublishMessage(message); err != nil {
+		return err
+	}
+
+	if err := h.storage.SaveMessage(message); err != nil {
+		log.WithError(err).WithField("message_id", message.ID).Error("failed to save published message")
+	}
+
+	h.deliverMessage(ticker, *message)
+	h.ClearMessagesCache(&ticker)
+
+	h.realtime.Broadcast(realtime.Message{
+		Type:     "message_created",
+		TickerID: ticker.ID,
+		Tags:     message.TagNames(),
+		Origin:   origin,
+		Data: map[string]any{
+			"message": response.MessageResponse(*message),
+		},
+	})
+
+	return nil
+}
+
+// saveMessageRevision records the current state of the message together with
+// the user who made the change. A failure is logged but does not fail the
+// request, as the message itself is already saved at this point.
+func (h *handler) saveMessageRevision(c *gin.Context, message storage.Message, action string) {
+	me, _ := helper.Me(c)
+
+	h.saveRevision(message, me.ID, action)
+}
+
+// saveRevision records a change of the message outside of a request of the
+// admin interface. The user is 0 for the scheduler and for senders without
+// an account.
+func (h *handler) saveRevision(message storage.Message, userID int, action string) {
+	revision := storage.NewMessageRevision(message, userID, action)
+	if err := h.storage.SaveMessageRevision(&revision); err != nil {
+		log.WithError(err).WithField("message_id", message.ID).Error("failed to save message revision")
+	}
+}
+
 // ClearMessagesCache clears the cache for the timeline endpoint of a ticker
 func (h *handler) ClearMessagesCache(ticker *storage.Ticker) {
 	h.cache.Range(func(key, value any) bool {
@@ -139,3 +562,124 @@ func (h *handler) ClearMessagesCache(ticker *storage.Ticker) {
 		return true
 	})
 }
+
+// deliverMessage queues the message for the bridges of the ticker which are
+// selected for the message. The outbox sends it in the background, so slow
+// services do not hold up the request.
+func (h *handler) deliverMessage(ticker storage.Ticker, message storage.Message) {
+	bridges := h.messageBridges(ticker, message)
+	if len(bridges) == 0 {
+		return
+	}
+
+	if err := h.storage.CreateOutboxJobs(message, bridges); err != nil {
+		log.WithError(err).WithField("message_id", message.ID).Error("failed to queue message for the bridges")
+	}
+}
+
+// editMessage queues the edit of a published message for its bridges. The
+// outbox waits until the message was sent to a bridge before editing it
+// there.
+func (h *handler) editMessage(ticker storage.Ticker, message storage.Message) {
+	bridges := h.messageBridges(ticker, message)
+	if len(bridges) == 0 {
+		return
+	}
+
+	if err := h.storage.CreateOutboxEditJobs(message, bridges); err != nil {
+		log.WithError(err).WithField("message_id", message.ID).Error("failed to queue edit for the bridges")
+	}
+}
+
+// messageBridges returns the bridges of the ticker which are selected for the
+// message.
+func (h *handler) messageBridges(ticker storage.Ticker, message storage.Message) []string {
+	bridges := make([]string, 0)
+	for _, name := range h.bridges.Enabled(ticker) {
+		if message.SendsTo(name) {
+			bridges = append(bridges, name)
+		}
+	}
+
+	return bridges
+}
+
+// saveMessageTags stores the hashtags of the text together with the tags set
+// explicitly as the tags of the message. Like the revisions, a failure is only
+// logged.
+func (h *handler) saveMessageTags(message *storage.Message, tags []string) {
+	names := storage.NormalizeTags(append(util.ExtractHashtags(message.Text), tags...))
+	if err := h.storage.SaveMessageTags(message, names); err != nil {
+		log.WithError(err).WithField("message_id", message.ID).Error("failed to save message tags")
+	}
+}
+
+// geometryParam is the geometry in an edit of a message. Set tells a
+// geometry which was left out, and leaves the location as it is, apart from
+// an explicit null, which removes it.
+type geometryParam struct {
+	Set      bool
+	Geometry *storage.Geometry
+}
+
+func (p *geometryParam) UnmarshalJSON(data []byte) error {
+	p.Set = true
+
+	return json.Unmarshal(data, &p.Geometry)
+}
+
+// explicitTags returns the tags of the message which do not come from a
+// hashtag in its text.
+func explicitTags(message storage.Message) []string {
+	hashtags := storage.NormalizeTags(util.ExtractHashtags(message.Text))
+
+	tags := make([]string, 0)
+	for _, name := range message.TagNames() {
+		if !slices.Contains(hashtags, name) {
+			tags = append(tags, name)
+		}
+	}
+
+	return tags
+}
+
+// messageListOptions returns the storage options for listing messages,
+// filtered by the tag in the query if given.
+func messageListOptions(c *gin.Context) []func(*gorm.DB) *gorm.DB {
+	opts := []func(*gorm.DB) *gorm.DB{storage.WithAttachments(), storage.WithTags()}
+	if tag := queryTag(c); tag != "" {
+		opts = append(opts, storage.WithTag(tag))
+	}
+
+	return opts
+}
+
+// authorOnly reports whether the role of the current user in the ticker only
+// gives access to the messages they wrote, as for contributors.
+func authorOnly(c *gin.Context) bool {
+	role, _ := helper.TickerRole(c)
+
+	return !storage.HasTickerRole(role, storage.TickerRoleEditor)
+}
+
+// authorMessageOptions returns the options for the lists of unpublished
+// messages, which contributors only see their own messages in.
+func authorMessageOptions(c *gin.Context) []func(*gorm.DB) *gorm.DB {
+	opts := []func(*gorm.DB) *gorm.DB{storage.WithAttachments(), storage.WithTags()}
+	if authorOnly(c) {
+		me, _ := helper.Me(c)
+		opts = append(opts, storage.WithAuthor(me.ID))
+	}
+
+	return opts
+}
+
+// queryTag returns the normalized tag from the query, or an empty string.
+func queryTag(c *gin.Context) string {
+	tag := storage.NormalizeTags([]string{c.Query("tag")})
+	if len(tag) == 0 {
+		return ""
+	}
+
+	return tag[0]
+}
@@ -12,9 +12,12 @@ import (
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/suite"
 	"github.com/systemli/ticker/internal/api/realtime"
+	"github.com/systemli/ticker/internal/api/response"
+	"github.com/systemli/ticker/internal/bridge"
 	"github.com/systemli/ticker/internal/cache"
 	"github.com/systemli/ticker/internal/config"
 	"github.com/systemli/ticker/internal/storage"
+	"gorm.io/gorm"
 )
 
 type MessagesTestSuite struct {
@@ -72,6 +75,19 @@ func (s *MessagesTestSuite) TestGetMessages() {
 		s.Equal(http.StatusOK, s.w.Code)
 		s.store.AssertExpectations(s.T())
 	})
+
+	s.Run("when searching messages", func() {
+		ticker := storage.Ticker{ID: 1}
+		s.ctx.Set("ticker", ticker)
+		s.ctx.Request = httptest.NewRequest(http.MethodGet, "/v1/admin/tickers/1/messages?q=water+station", nil)
+		s.store.On("SearchMessagesByTicker", ticker, "water station", mock.Anything, mock.Anything).Return([]storage.Message{{ID: 1, Text: "water station"}}, nil).Once()
+		h := s.handler()
+		h.GetMessages(s.ctx)
+
+		s.Equal(http.StatusOK, s.w.Code)
+		s.Contains(s.w.Body.String(), "water station")
+		s.store.AssertExpectations(s.T())
+	})
 }
 
 func (s *MessagesTestSuite) TestGetMessage() {
@@ -83,17 +99,276 @@ func (s *MessagesTestSuite) TestGetMessage() {
 		s.True(s.store.AssertExpectations(s.T()))
 	})
 
+	s.Run("when deliveries can not be loaded", func() {
+		message := storage.Message{ID: 1, TickerID: 1}
+		s.ctx.Set("message", message)
+		s.store.On("FindMessage", 1, 1, mock.Anything).Return(storage.Message{}, errors.New("storage error")).Once()
+		h := s.handler()
+		h.GetMessage(s.ctx)
+
+		s.Equal(http.StatusNotFound, s.w.Code)
+		s.store.AssertExpectations(s.T())
+	})
+
 	s.Run("when message found", func() {
-		message := storage.Message{ID: 1}
+		message := storage.Message{ID: 1, TickerID: 1}
 		s.ctx.Set("message", message)
+		message.Deliveries = []storage.OutboxJob{{Bridge: "telegram", Status: storage.OutboxFailed, Attempts: 10, LastError: "timeout"}}
+		s.store.On("FindMessage", 1, 1, mock.Anything).Return(message, nil).Once()
 		h := s.handler()
 		h.GetMessage(s.ctx)
 
 		s.Equal(http.StatusOK, s.w.Code)
+		s.Contains(s.w.Body.String(), `"deliveries":[{"bridge":"telegram","action":"send","status":"failed","attempts":10,"error":"timeout"`)
 		s.store.AssertExpectations(s.T())
 	})
 }
 
+func (s *MessagesTestSuite) TestGetScheduledMessages() {
+	s.Run("when ticker not found", func() {
+		h := s.handler()
+		h.GetScheduledMessages(s.ctx)
+
+		s.Equal(http.StatusNotFound, s.w.Code)
+		s.True(s.store.AssertExpectations(s.T()))
+	})
+
+	s.Run("when storage returns error", func() {
+		ticker := storage.Ticker{ID: 1}
+		s.ctx.Set("ticker", ticker)
+		s.store.On("FindScheduledMessagesByTicker", ticker, mock.Anything).Return([]storage.Message{}, errors.New("storage error")).Once()
+		h := s.handler()
+		h.GetScheduledMessages(s.ctx)
+
+		s.Equal(http.StatusNotFound, s.w.Code)
+		s.True(s.store.AssertExpectations(s.T()))
+	})
+
+	s.Run("happy path", func() {
+		ticker := storage.Ticker{ID: 1}
+		s.ctx.Set("ticker", ticker)
+		publishAt := time.Now().Add(time.Hour)
+		s.store.On("FindScheduledMessagesByTicker", ticker, mock.Anything).Return([]storage.Message{{ID: 1, PublishAt: &publishAt}}, nil).Once()
+		h := s.handler()
+		h.GetScheduledMessages(s.ctx)
+
+		s.Equal(http.StatusOK, s.w.Code)
+		s.Contains(s.w.Body.String(), "publishAt")
+		s.True(s.store.AssertExpectations(s.T()))
+	})
+
+	s.Run("when contributor lists them", func() {
+		ticker := storage.Ticker{ID: 1}
+		s.ctx.Set("ticker", ticker)
+		s.ctx.Set("tickerRole", storage.TickerRoleContributor)
+		s.ctx.Set("me", storage.User{ID: 2})
+		s.store.On("FindScheduledMessagesByTicker", ticker, mock.MatchedBy(func(opts []func(*gorm.DB) *gorm.DB) bool {
+			return len(opts) == 3
+		})).Return([]storage.Message{}, nil).Once()
+		h := s.handler()
+		h.GetScheduledMessages(s.ctx)
+
+		s.Equal(http.StatusOK, s.w.Code)
+		s.True(s.store.AssertExpectations(s.T()))
+	})
+}
+
+func (s *MessagesTestSuite) TestGetDraftMessages() {
+	s.Run("when ticker not found", func() {
+		h := s.handler()
+		h.GetDraftMessages(s.ctx)
+
+		s.Equal(http.StatusNotFound, s.w.Code)
+		s.True(s.store.AssertExpectations(s.T()))
+	})
+
+	s.Run("when storage returns error", func() {
+		ticker := storage.Ticker{ID: 1}
+		s.ctx.Set("ticker", ticker)
+		s.store.On("FindDraftMessagesByTicker", ticker, mock.Anything).Return([]storage.Message{}, errors.New("storage error")).Once()
+		h := s.handler()
+		h.GetDraftMessages(s.ctx)
+
+		s.Equal(http.StatusNotFound, s.w.Code)
+		s.True(s.store.AssertExpectations(s.T()))
+	})
+
+	s.Run("happy path", func() {
+		ticker := storage.Ticker{ID: 1}
+		s.ctx.Set("ticker", ticker)
+		s.store.On("FindDraftMessagesByTicker", ticker, mock.Anything).Return([]storage.Message{{ID: 1, Draft: true}}, nil).Once()
+		h := s.handler()
+		h.GetDraftMessages(s.ctx)
+
+		s.Equal(http.StatusOK, s.w.Code)
+		s.Contains(s.w.Body.String(), `"draft":true`)
+		s.True(s.store.AssertExpectations(s.T()))
+	})
+
+	s.Run("when contributor lists them", func() {
+		ticker := storage.Ticker{ID: 1}
+		s.ctx.Set("ticker", ticker)
+		s.ctx.Set("tickerRole", storage.TickerRoleContributor)
+		s.ctx.Set("me", storage.User{ID: 2})
+		s.store.On("FindDraftMessagesByTicker", ticker, mock.MatchedBy(func(opts []func(*gorm.DB) *gorm.DB) bool {
+			return len(opts) == 3
+		})).Return([]storage.Message{}, nil).Once()
+		h := s.handler()
+		h.GetDraftMessages(s.ctx)
+
+		s.Equal(http.StatusOK, s.w.Code)
+		s.True(s.store.AssertExpectations(s.T()))
+	})
+}
+
+func (s *MessagesTestSuite) TestGetMessageRevisions() {
+	s.Run("when ticker not found", func() {
+		h := s.handler()
+		h.GetMessageRevisions(s.ctx)
+
+		s.Equal(http.StatusNotFound, s.w.Code)
+		s.True(s.store.AssertExpectations(s.T()))
+	})
+
+	s.Run("when message id is invalid", func() {
+		s.ctx.Set("ticker", storage.Ticker{ID: 1})
+		s.ctx.Params = gin.Params{{Key: "messageID", Value: "abc"}}
+		h := s.handler()
+		h.GetMessageRevisions(s.ctx)
+
+		s.Equal(http.StatusBadRequest, s.w.Code)
+		s.True(s.store.AssertExpectations(s.T()))
+	})
+
+	s.Run("when message not found", func() {
+		s.ctx.Set("ticker", storage.Ticker{ID: 1})
+		s.ctx.Set("tickerRole", storage.TickerRoleEditor)
+		s.ctx.Params = gin.Params{{Key: "messageID", Value: "1"}}
+		s.store.On("FindMessageRevisions", 1, 1).Return([]storage.MessageRevision{}, nil).Once()
+		s.store.On("FindMessage", 1, 1).Return(storage.Message{}, errors.New("not found")).Once()
+		h := s.handler()
+		h.GetMessageRevisions(s.ctx)
+
+		s.Equal(http.StatusNotFound, s.w.Code)
+		s.True(s.store.AssertExpectations(s.T()))
+	})
+
+	s.Run("when storage returns error", func() {
+		s.ctx.Set("ticker", storage.Ticker{ID: 1})
+		s.ctx.Set("tickerRole", storage.TickerRoleEditor)
+		s.ctx.Params = gin.Params{{Key: "messageID", Value: "1"}}
+		s.store.On("FindMessageRevisions", 1, 1).Return([]storage.MessageRevision{}, errors.New("storage error")).Once()
+		h := s.handler()
+		h.GetMessageRevisions(s.ctx)
+
+		s.Equal(http.StatusNotFound, s.w.Code)
+		s.True(s.store.AssertExpectations(s.T()))
+	})
+
+	s.Run("when users can't be found", func() {
+		s.ctx.Set("ticker", storage.Ticker{ID: 1})
+		s.ctx.Set("tickerRole", storage.TickerRoleEditor)
+		s.ctx.Params = gin.Params{{Key: "messageID", Value: "1"}}
+		s.store.On("FindMessageRevisions", 1, 1).Return([]storage.MessageRevision{{ID: 1, MessageID: 1, UserID: 1}}, nil).Once()
+		s.store.On("FindUsersByIDs", []int{1}).Return([]storage.User{}, errors.New("storage error")).Once()
+		h := s.handler()
+		h.GetMessageRevisions(s.ctx)
+
+		s.Equal(http.StatusNotFound, s.w.Code)
+		s.True(s.store.AssertExpectations(s.T()))
+	})
+
+	s.Run("when the message was written before the history", func() {
+		s.ctx.Set("ticker", storage.Ticker{ID: 1})
+		s.ctx.Set("tickerRole", storage.TickerRoleEditor)
+		s.ctx.Params = gin.Params{{Key: "messageID", Value: "1"}}
+		s.store.On("FindMessageRevisions", 1, 1).Return([]storage.MessageRevision{}, nil).Once()
+		s.store.On("FindMessage", 1, 1).Return(storage.Message{ID: 1}, nil).Once()
+		s.store.On("FindUsersByIDs", []int{}).Return([]storage.User{}, nil).Once()
+		h := s.handler()
+		h.GetMessageRevisions(s.ctx)
+
+		s.Equal(http.StatusOK, s.w.Code)
+		s.Contains(s.w.Body.String(), `"revisions":[]`)
+		s.True(s.store.AssertExpectations(s.T()))
+	})
+
+	s.Run("happy path", func() {
+		s.ctx.Set("ticker", storage.Ticker{ID: 1})
+		s.ctx.Set("tickerRole", storage.TickerRoleEditor)
+		s.ctx.Params = gin.Params{{Key: "messageID", Value: "1"}}
+		s.store.On("FindMessageRevisions", 1, 1).Return([]storage.MessageRevision{{ID: 1, MessageID: 1, UserID: 1, Action: storage.RevisionDeleted, Text: "text"}}, nil).Once()
+		s.store.On("FindUsersByIDs", []int{1}).Return([]storage.User{{ID: 1, Email: "user@systemli.org"}}, nil).Once()
+		h := s.handler()
+		h.GetMessageRevisions(s.ctx)
+
+		s.Equal(http.StatusOK, s.w.Code)
+		s.Contains(s.w.Body.String(), "user@systemli.org")
+		s.Contains(s.w.Body.String(), `"action":"deleted"`)
+		s.True(s.store.AssertExpectations(s.T()))
+	})
+
+	s.Run("when contributor did not write the message", func() {
+		s.ctx.Set("ticker", storage.Ticker{ID: 1})
+		s.ctx.Set("tickerRole", storage.TickerRoleContributor)
+		s.ctx.Set("me", storage.User{ID: 2})
+		s.ctx.Params = gin.Params{{Key: "messageID", Value: "1"}}
+		s.store.On("FindMessageRevisions", 1, 1).Return([]storage.MessageRevision{{ID: 1, MessageID: 1, UserID: 1, Action: storage.RevisionCreated}}, nil).Once()
+		h := s.handler()
+		h.GetMessageRevisions(s.ctx)
+
+		s.Equal(http.StatusForbidden, s.w.Code)
+		s.True(s.store.AssertExpectations(s.T()))
+	})
+
+	s.Run("when contributor wrote the message", func() {
+		s.ctx.Set("ticker", storage.Ticker{ID: 1})
+		s.ctx.Set("tickerRole", storage.TickerRoleContributor)
+		s.ctx.Set("me", storage.User{ID: 1})
+		s.ctx.Params = gin.Params{{Key: "messageID", Value: "1"}}
+		s.store.On("FindMessageRevisions", 1, 1).Return([]storage.MessageRevision{{ID: 1, MessageID: 1, UserID: 1, Action: storage.RevisionCreated}}, nil).Once()
+		s.store.On("FindUsersByIDs", []int{1}).Return([]storage.User{{ID: 1}}, nil).Once()
+		h := s.handler()
+		h.GetMessageRevisions(s.ctx)
+
+		s.Equal(http.StatusOK, s.w.Code)
+		s.True(s.store.AssertExpectations(s.T()))
+	})
+}
+
+func (s *MessagesTestSuite) TestGetMessageTags() {
+	s.Run("when ticker not found", func() {
+		h := s.handler()
+		h.GetMessageTags(s.ctx)
+
+		s.Equal(http.StatusNotFound, s.w.Code)
+		s.True(s.store.AssertExpectations(s.T()))
+	})
+
+	s.Run("when storage returns error", func() {
+		ticker := storage.Ticker{ID: 1}
+		s.ctx.Set("ticker", ticker)
+		s.store.On("FindTagsByTicker", ticker).Return([]storage.TagCount{}, errors.New("storage error")).Once()
+		h := s.handler()
+		h.GetMessageTags(s.ctx)
+
+		s.Equal(http.StatusNotFound, s.w.Code)
+		s.True(s.store.AssertExpectations(s.T()))
+	})
+
+	s.Run("happy path", func() {
+		ticker := storage.Ticker{ID: 1}
+		s.ctx.Set("ticker", ticker)
+		s.store.On("FindTagsByTicker", ticker).Return([]storage.TagCount{{Name: "police", Count: 3}}, nil).Once()
+		h := s.handler()
+		h.GetMessageTags(s.ctx)
+
+		s.Equal(http.StatusOK, s.w.Code)
+		s.Contains(s.w.Body.String(), `{"name":"police","count":3}`)
+		s.True(s.store.AssertExpectations(s.T()))
+	})
+}
+
 func (s *MessagesTestSuite) TestPostMessage() {
 	s.Run("when ticker not found", func() {
 		h := s.handler()
@@ -136,6 +411,7 @@ func (s *MessagesTestSuite) TestPostMessage() {
 		s.ctx.Request.Header.Add("Content-Type", "application/json")
 		s.ctx.AddParam("tickerID", "1")
 		s.store.On("FindUploadsByIDs", []int{1}).Return([]storage.Upload{}, nil).Once()
+		s.ctx.Set("tickerRole", storage.TickerRoleEditor)
 		s.store.On("SaveMessage", mock.Anything).Return(errors.New("storage error")).Once()
 		h := s.handler()
 		h.PostMessage(s.ctx)
@@ -152,7 +428,200 @@ func (s *MessagesTestSuite) TestPostMessage() {
 		s.ctx.Request.Header.Add("Content-Type", "application/json")
 		s.ctx.AddParam("tickerID", "1")
 		s.store.On("FindUploadsByIDs", []int{1}).Return([]storage.Upload{}, nil).Once()
+		s.ctx.Set("tickerRole", storage.TickerRoleEditor)
 		s.store.On("SaveMessage", mock.Anything).Return(nil).Once()
+		s.store.On("SaveMessageRevision", mock.Anything).Return(nil).Once()
+		s.store.On("SaveMessageTags", mock.Anything, mock.Anything).Return(nil).Once()
+		h := s.handler()
+		h.PostMessage(s.ctx)
+
+		s.Equal(http.StatusOK, s.w.Code)
+		s.True(s.store.AssertExpectations(s.T()))
+	})
+
+	s.Run("with tags", func() {
+		ticker := storage.Ticker{ID: 1}
+		s.ctx.Set("ticker", ticker)
+		json := `{"text":"Road blocked #Police #traffic","tags":["traffic","Berlin"]}`
+		s.ctx.Request = httptest.NewRequest(http.MethodPost, "/v1/messages", strings.NewReader(json))
+		s.ctx.Request.Header.Add("Content-Type", "application/json")
+		s.ctx.Set("tickerRole", storage.TickerRoleEditor)
+		s.store.On("SaveMessage", mock.Anything).Return(nil).Once()
+		s.store.On("SaveMessageRevision", mock.Anything).Return(nil).Once()
+		s.store.On("SaveMessageTags", mock.Anything, []string{"police", "traffic", "berlin"}).Return(nil).Once()
+		h := s.handler()
+		h.PostMessage(s.ctx)
+
+		s.Equal(http.StatusOK, s.w.Code)
+		s.True(s.store.AssertExpectations(s.T()))
+	})
+
+	s.Run("when bridges are enabled", func() {
+		ticker := storage.Ticker{ID: 1}
+		s.ctx.Set("ticker", ticker)
+		s.ctx.Request = httptest.NewRequest(http.MethodPost, "/v1/messages", strings.NewReader(`{"text":"text"}`))
+		s.ctx.Request.Header.Add("Content-Type", "application/json")
+		s.ctx.Set("tickerRole", storage.TickerRoleEditor)
+		s.store.On("SaveMessage", mock.Anything).Return(nil).Once()
+		s.store.On("SaveMessageRevision", mock.Anything).Return(nil).Once()
+		s.store.On("SaveMessageTags", mock.Anything, mock.Anything).Return(nil).Once()
+		s.store.On("CreateOutboxJobs", mock.Anything, []string{"mock"}).Return(nil).Once()
+		mockBridge := &bridge.MockBridge{}
+		mockBridge.On("Enabled", ticker).Return(true).Once()
+		h := s.handler()
+		h.bridges = bridge.Bridges{"mock": mockBridge}
+		h.PostMessage(s.ctx)
+
+		s.Equal(http.StatusOK, s.w.Code)
+		s.True(s.store.AssertExpectations(s.T()))
+		s.True(mockBridge.AssertExpectations(s.T()))
+	})
+
+	s.Run("with selected bridges", func() {
+		ticker := storage.Ticker{ID: 1}
+		s.ctx.Set("ticker", ticker)
+		s.ctx.Request = httptest.NewRequest(http.MethodPost, "/v1/messages", strings.NewReader(`{"text":"text","bridges":["signalGroup"]}`))
+		s.ctx.Request.Header.Add("Content-Type", "application/json")
+		s.ctx.Set("tickerRole", storage.TickerRoleEditor)
+		s.store.On("SaveMessage", mock.MatchedBy(func(m *storage.Message) bool {
+			return len(m.Bridges) == 1 && m.Bridges[0] == "signalGroup"
+		})).Return(nil).Once()
+		s.store.On("SaveMessageRevision", mock.Anything).Return(nil).Once()
+		s.store.On("SaveMessageTags", mock.Anything, mock.Anything).Return(nil).Once()
+		s.store.On("CreateOutboxJobs", mock.Anything, []string{"signalGroup"}).Return(nil).Once()
+		signalGroup := &bridge.MockBridge{}
+		signalGroup.On("Enabled", ticker).Return(true).Once()
+		mastodon := &bridge.MockBridge{}
+		mastodon.On("Enabled", ticker).Return(true).Once()
+		h := s.handler()
+		h.bridges = bridge.Bridges{"signalGroup": signalGroup, "mastodon": mastodon}
+		h.PostMessage(s.ctx)
+
+		s.Equal(http.StatusOK, s.w.Code)
+		s.Contains(s.w.Body.String(), `"bridges":["signalGroup"]`)
+		s.True(s.store.AssertExpectations(s.T()))
+	})
+
+	s.Run("with unknown bridge", func() {
+		ticker := storage.Ticker{ID: 1}
+		s.ctx.Set("ticker", ticker)
+		s.ctx.Request = httptest.NewRequest(http.MethodPost, "/v1/messages", strings.NewReader(`{"text":"text","bridges":["twitter"]}`))
+		s.ctx.Request.Header.Add("Content-Type", "application/json")
+		h := s.handler()
+		h.PostMessage(s.ctx)
+
+		s.Equal(http.StatusBadRequest, s.w.Code)
+		s.Contains(s.w.Body.String(), response.BridgeUnknown)
+		s.True(s.store.AssertExpectations(s.T()))
+	})
+
+	s.Run("with geometry", func() {
+		ticker := storage.Ticker{ID: 1}
+		s.ctx.Set("ticker", ticker)
+		json := `{"text":"Route blocked","geometry":{"type":"LineString","coordinates":[[13.40,52.52],[13.41,52.53]]}}`
+		s.ctx.Request = httptest.NewRequest(http.MethodPost, "/v1/messages", strings.NewReader(json))
+		s.ctx.Request.Header.Add("Content-Type", "application/json")
+		s.ctx.Set("tickerRole", storage.TickerRoleEditor)
+		s.store.On("SaveMessage", mock.MatchedBy(func(m *storage.Message) bool {
+			return m.Geometry != nil && m.Geometry.Type == storage.GeometryLineString
+		})).Return(nil).Once()
+		s.store.On("SaveMessageRevision", mock.Anything).Return(nil).Once()
+		s.store.On("SaveMessageTags", mock.Anything, mock.Anything).Return(nil).Once()
+		h := s.handler()
+		h.PostMessage(s.ctx)
+
+		s.Equal(http.StatusOK, s.w.Code)
+		s.Contains(s.w.Body.String(), `"geometry":{"type":"LineString"`)
+		s.True(s.store.AssertExpectations(s.T()))
+	})
+
+	s.Run("with invalid geometry", func() {
+		ticker := storage.Ticker{ID: 1}
+		s.ctx.Set("ticker", ticker)
+		json := `{"text":"Route blocked","geometry":{"type":"LineString","coordinates":[[13.40,52.52]]}}`
+		s.ctx.Request = httptest.NewRequest(http.MethodPost, "/v1/messages", strings.NewReader(json))
+		s.ctx.Request.Header.Add("Content-Type", "application/json")
+		h := s.handler()
+		h.PostMessage(s.ctx)
+
+		s.Equal(http.StatusBadRequest, s.w.Code)
+		s.Contains(s.w.Body.String(), response.GeometryInvalid)
+		s.True(s.store.AssertExpectations(s.T()))
+	})
+
+	s.Run("when message is scheduled", func() {
+		ticker := storage.Ticker{ID: 1}
+		s.ctx.Set("ticker", ticker)
+		publishAt := time.Now().Add(time.Hour).UTC().Format(time.RFC3339)
+		json := `{"text":"text","publishAt":"` + publishAt + `"}`
+		s.ctx.Request = httptest.NewRequest(http.MethodPost, "/v1/messages", strings.NewReader(json))
+		s.ctx.Request.Header.Add("Content-Type", "application/json")
+		s.ctx.Set("tickerRole", storage.TickerRoleEditor)
+		s.store.On("SaveMessage", mock.MatchedBy(func(m *storage.Message) bool {
+			return m.IsScheduled()
+		})).Return(nil).Once()
+		s.store.On("SaveMessageRevision", mock.Anything).Return(nil).Once()
+		s.store.On("SaveMessageTags", mock.Anything, mock.Anything).Return(nil).Once()
+		h := s.handler()
+		h.PostMessage(s.ctx)
+
+		s.Equal(http.StatusOK, s.w.Code)
+		s.Contains(s.w.Body.String(), "publishAt")
+		s.True(s.store.AssertExpectations(s.T()))
+	})
+
+	s.Run("when message is a draft", func() {
+		ticker := storage.Ticker{ID: 1}
+		s.ctx.Set("ticker", ticker)
+		s.ctx.Request = httptest.NewRequest(http.MethodPost, "/v1/messages", strings.NewReader(`{"text":"text","draft":true}`))
+		s.ctx.Request.Header.Add("Content-Type", "application/json")
+		s.ctx.Set("tickerRole", storage.TickerRoleEditor)
+		s.store.On("SaveMessage", mock.MatchedBy(func(m *storage.Message) bool {
+			return m.Draft
+		})).Return(nil).Once()
+		s.store.On("SaveMessageRevision", mock.Anything).Return(nil).Once()
+		s.store.On("SaveMessageTags", mock.Anything, mock.Anything).Return(nil).Once()
+		h := s.handler()
+		h.PostMessage(s.ctx)
+
+		s.Equal(http.StatusOK, s.w.Code)
+		s.Contains(s.w.Body.String(), `"draft":true`)
+		s.True(s.store.AssertExpectations(s.T()))
+	})
+
+	s.Run("when user is a contributor", func() {
+		ticker := storage.Ticker{ID: 1}
+		s.ctx.Set("ticker", ticker)
+		s.ctx.Set("me", storage.User{ID: 2})
+		s.ctx.Request = httptest.NewRequest(http.MethodPost, "/v1/messages", strings.NewReader(`{"text":"text"}`))
+		s.ctx.Request.Header.Add("Content-Type", "application/json")
+		s.ctx.Set("tickerRole", storage.TickerRoleContributor)
+		s.store.On("SaveMessage", mock.MatchedBy(func(m *storage.Message) bool {
+			return m.Draft && m.IsPendingReview()
+		})).Return(nil).Once()
+		s.store.On("SaveMessageRevision", mock.Anything).Return(nil).Once()
+		s.store.On("SaveMessageTags", mock.Anything, mock.Anything).Return(nil).Once()
+		h := s.handler()
+		h.PostMessage(s.ctx)
+
+		s.Equal(http.StatusOK, s.w.Code)
+		s.Contains(s.w.Body.String(), `"status":"pending"`)
+		s.True(s.store.AssertExpectations(s.T()))
+	})
+
+	s.Run("when publishing date is in the past", func() {
+		ticker := storage.Ticker{ID: 1}
+		s.ctx.Set("ticker", ticker)
+		publishAt := time.Now().Add(-time.Hour).UTC().Format(time.RFC3339)
+		json := `{"text":"text","publishAt":"` + publishAt + `"}`
+		s.ctx.Request = httptest.NewRequest(http.MethodPost, "/v1/messages", strings.NewReader(json))
+		s.ctx.Request.Header.Add("Content-Type", "application/json")
+		s.ctx.Set("tickerRole", storage.TickerRoleEditor)
+		s.store.On("SaveMessage", mock.MatchedBy(func(m *storage.Message) bool {
+			return !m.IsScheduled()
+		})).Return(nil).Once()
+		s.store.On("SaveMessageRevision", mock.Anything).Return(nil).Once()
+		s.store.On("SaveMessageTags", mock.Anything, mock.Anything).Return(nil).Once()
 		h := s.handler()
 		h.PostMessage(s.ctx)
 
@@ -161,6 +630,375 @@ func (s *MessagesTestSuite) TestPostMessage() {
 	})
 }
 
+func (s *MessagesTestSuite) TestPutMessage() {
+	s.Run("when ticker not found", func() {
+		h := s.handler()
+		h.PutMessage(s.ctx)
+
+		s.Equal(http.StatusNotFound, s.w.Code)
+		s.True(s.store.AssertExpectations(s.T()))
+	})
+
+	s.Run("when message not found", func() {
+		s.ctx.Set("ticker", storage.Ticker{ID: 1})
+		h := s.handler()
+		h.PutMessage(s.ctx)
+
+		s.Equal(http.StatusNotFound, s.w.Code)
+		s.True(s.store.AssertExpectations(s.T()))
+	})
+
+	s.Run("when form is invalid", func() {
+		s.ctx.Set("ticker", storage.Ticker{ID: 1})
+		s.ctx.Set("message", storage.Message{ID: 1})
+		s.ctx.Request = httptest.NewRequest(http.MethodPut, "/v1/tickers/1/messages/1", nil)
+		h := s.handler()
+		h.PutMessage(s.ctx)
+
+		s.Equal(http.StatusBadRequest, s.w.Code)
+		s.True(s.store.AssertExpectations(s.T()))
+	})
+
+	s.Run("when database returns error", func() {
+		s.ctx.Set("ticker", storage.Ticker{ID: 1})
+		s.ctx.Set("message", storage.Message{ID: 1, Text: "text"})
+		s.ctx.Request = httptest.NewRequest(http.MethodPut, "/v1/tickers/1/messages/1", strings.NewReader(`{"text":"edited"}`))
+		s.ctx.Request.Header.Add("Content-Type", "application/json")
+		s.store.On("SaveMessage", mock.Anything).Return(errors.New("storage error")).Once()
+		h := s.handler()
+		h.PutMessage(s.ctx)
+
+		s.Equal(http.StatusBadRequest, s.w.Code)
+		s.True(s.store.AssertExpectations(s.T()))
+	})
+
+	s.Run("happy path", func() {
+		s.ctx.Set("ticker", storage.Ticker{ID: 1, Domain: "localhost"})
+		s.ctx.Set("message", storage.Message{ID: 1, Text: "text"})
+		s.ctx.Set("me", storage.User{ID: 2})
+		s.cache.Set("response:localhost:/v1/timeline", true, time.Minute)
+		s.ctx.Request = httptest.NewRequest(http.MethodPut, "/v1/tickers/1/messages/1", strings.NewReader(`{"text":"edited"}`))
+		s.ctx.Request.Header.Add("Content-Type", "application/json")
+		s.store.On("SaveMessage", mock.MatchedBy(func(m *storage.Message) bool {
+			return m.ID == 1 && m.Text == "edited"
+		})).Return(nil).Once()
+		s.store.On("SaveMessageRevision", mock.MatchedBy(func(r *storage.MessageRevision) bool {
+			return r.MessageID == 1 && r.UserID == 2 && r.Text == "edited"
+		})).Return(nil).Once()
+		s.store.On("SaveMessageTags", mock.Anything, []string{}).Return(nil).Once()
+		h := s.handler()
+		h.PutMessage(s.ctx)
+
+		s.Equal(http.StatusOK, s.w.Code)
+		s.Nil(s.cache.Get("response:localhost:/v1/timeline"))
+		s.True(s.store.AssertExpectations(s.T()))
+	})
+
+	s.Run("when message is pinned", func() {
+		s.ctx.Set("ticker", storage.Ticker{ID: 1, Domain: "localhost", Websites: []storage.TickerWebsite{{Origin: "https://localhost"}}})
+		s.ctx.Set("message", storage.Message{ID: 1, Text: "text", Pinned: true})
+		s.cache.Set("response:https://localhost:/v1/init", true, time.Minute)
+		s.ctx.Request = httptest.NewRequest(http.MethodPut, "/v1/tickers/1/messages/1", strings.NewReader(`{"text":"edited"}`))
+		s.ctx.Request.Header.Add("Content-Type", "application/json")
+		s.store.On("SaveMessage", mock.Anything).Return(nil).Once()
+		s.store.On("SaveMessageRevision", mock.Anything).Return(nil).Once()
+		s.store.On("SaveMessageTags", mock.Anything, []string{}).Return(nil).Once()
+		h := s.handler()
+		h.PutMessage(s.ctx)
+
+		s.Equal(http.StatusOK, s.w.Code)
+		s.Nil(s.cache.Get("response:https://localhost:/v1/init"))
+		s.True(s.store.AssertExpectations(s.T()))
+	})
+
+	s.Run("without geometry", func() {
+		geometry := &storage.Geometry{Type: storage.GeometryPoint, Coordinates: []byte(`[13.4,52.5]`)}
+		s.ctx.Set("ticker", storage.Ticker{ID: 1})
+		s.ctx.Set("message", storage.Message{ID: 1, Text: "text", Geometry: geometry})
+		s.ctx.Request = httptest.NewRequest(http.MethodPut, "/v1/tickers/1/messages/1", strings.NewReader(`{"text":"edited"}`))
+		s.ctx.Request.Header.Add("Content-Type", "application/json")
+		s.store.On("SaveMessage", mock.MatchedBy(func(m *storage.Message) bool {
+			return m.Geometry == geometry
+		})).Return(nil).Once()
+		s.store.On("SaveMessageRevision", mock.Anything).Return(nil).Once()
+		s.store.On("SaveMessageTags", mock.Anything, mock.Anything).Return(nil).Once()
+		h := s.handler()
+		h.PutMessage(s.ctx)
+
+		s.Equal(http.StatusOK, s.w.Code)
+		s.True(s.store.AssertExpectations(s.T()))
+	})
+
+	s.Run("when geometry is null", func() {
+		geometry := &storage.Geometry{Type: storage.GeometryPoint, Coordinates: []byte(`[13.4,52.5]`)}
+		s.ctx.Set("ticker", storage.Ticker{ID: 1})
+		s.ctx.Set("message", storage.Message{ID: 1, Text: "text", Geometry: geometry})
+		s.ctx.Request = httptest.NewRequest(http.MethodPut, "/v1/tickers/1/messages/1", strings.NewReader(`{"text":"edited","geometry":null}`))
+		s.ctx.Request.Header.Add("Content-Type", "application/json")
+		s.store.On("SaveMessage", mock.MatchedBy(func(m *storage.Message) bool {
+			return m.Geometry == nil
+		})).Return(nil).Once()
+		s.store.On("SaveMessageRevision", mock.Anything).Return(nil).Once()
+		s.store.On("SaveMessageTags", mock.Anything, mock.Anything).Return(nil).Once()
+		h := s.handler()
+		h.PutMessage(s.ctx)
+
+		s.Equal(http.StatusOK, s.w.Code)
+		s.True(s.store.AssertExpectations(s.T()))
+	})
+
+	s.Run("without tags", func() {
+		message := storage.Message{ID: 1, Text: "#water at the station", Tags: []storage.MessageTag{{Name: "water"}, {Name: "police"}}}
+		s.ctx.Set("ticker", storage.Ticker{ID: 1})
+		s.ctx.Set("message", message)
+		s.ctx.Request = httptest.NewRequest(http.MethodPut, "/v1/tickers/1/messages/1", strings.NewReader(`{"text":"#food at the station"}`))
+		s.ctx.Request.Header.Add("Content-Type", "application/json")
+		s.store.On("SaveMessage", mock.Anything).Return(nil).Once()
+		s.store.On("SaveMessageRevision", mock.Anything).Return(nil).Once()
+		s.store.On("SaveMessageTags", mock.Anything, []string{"food", "police"}).Return(nil).Once()
+		h := s.handler()
+		h.PutMessage(s.ctx)
+
+		s.Equal(http.StatusOK, s.w.Code)
+		s.True(s.store.AssertExpectations(s.T()))
+	})
+
+	s.Run("with empty tags", func() {
+		message := storage.Message{ID: 1, Text: "#water at the station", Tags: []storage.MessageTag{{Name: "water"}, {Name: "police"}}}
+		s.ctx.Set("ticker", storage.Ticker{ID: 1})
+		s.ctx.Set("message", message)
+		s.ctx.Request = httptest.NewRequest(http.MethodPut, "/v1/tickers/1/messages/1", strings.NewReader(`{"text":"#water at the station","tags":[]}`))
+		s.ctx.Request.Header.Add("Content-Type", "application/json")
+		s.store.On("SaveMessage", mock.Anything).Return(nil).Once()
+		s.store.On("SaveMessageRevision", mock.Anything).Return(nil).Once()
+		s.store.On("SaveMessageTags", mock.Anything, []string{"water"}).Return(nil).Once()
+		h := s.handler()
+		h.PutMessage(s.ctx)
+
+		s.Equal(http.StatusOK, s.w.Code)
+		s.True(s.store.AssertExpectations(s.T()))
+	})
+
+	s.Run("when bridges are enabled", func() {
+		ticker := storage.Ticker{ID: 1, Domain: "localhost"}
+		s.ctx.Set("ticker", ticker)
+		s.ctx.Set("message", storage.Message{ID: 1, Text: "text"})
+		s.ctx.Request = httptest.NewRequest(http.MethodPut, "/v1/tickers/1/messages/1", strings.NewReader(`{"text":"edited"}`))
+		s.ctx.Request.Header.Add("Content-Type", "application/json")
+		s.store.On("SaveMessage", mock.Anything).Return(nil).Once()
+		s.store.On("SaveMessageRevision", mock.Anything).Return(nil).Once()
+		s.store.On("SaveMessageTags", mock.Anything, mock.Anything).Return(nil).Once()
+		s.store.On("CreateOutboxEditJobs", mock.MatchedBy(func(m storage.Message) bool { return m.Text == "edited" }), []string{"mock"}).Return(nil).Once()
+		mockBridge := &bridge.MockBridge{}
+		mockBridge.On("Enabled", ticker).Return(true).Once()
+		h := s.handler()
+		h.bridges = bridge.Bridges{"mock": mockBridge}
+		h.PutMessage(s.ctx)
+
+		s.Equal(http.StatusOK, s.w.Code)
+		s.True(s.store.AssertExpectations(s.T()))
+		mockBridge.AssertNotCalled(s.T(), "Edit", mock.Anything, mock.Anything)
+	})
+
+	s.Run("when message is scheduled", func() {
+		publishAt := time.Now().Add(time.Hour)
+		s.ctx.Set("ticker", storage.Ticker{ID: 1, Domain: "localhost"})
+		s.ctx.Set("message", storage.Message{ID: 1, Text: "text", PublishAt: &publishAt})
+		s.cache.Set("response:localhost:/v1/timeline", true, time.Minute)
+		s.ctx.Request = httptest.NewRequest(http.MethodPut, "/v1/tickers/1/messages/1", strings.NewReader(`{"text":"edited"}`))
+		s.ctx.Request.Header.Add("Content-Type", "application/json")
+		s.store.On("SaveMessage", mock.Anything).Return(nil).Once()
+		s.store.On("SaveMessageRevision", mock.Anything).Return(nil).Once()
+		s.store.On("SaveMessageTags", mock.Anything, mock.Anything).Return(nil).Once()
+		h := s.handler()
+		h.PutMessage(s.ctx)
+
+		s.Equal(http.StatusOK, s.w.Code)
+		s.NotNil(s.cache.Get("response:localhost:/v1/timeline"))
+		s.True(s.store.AssertExpectations(s.T()))
+	})
+}
+
+func (s *MessagesTestSuite) TestPublishDraftMessage() {
+	s.Run("when ticker not found", func() {
+		h := s.handler()
+		h.PublishDraftMessage(s.ctx)
+
+		s.Equal(http.StatusNotFound, s.w.Code)
+		s.True(s.store.AssertExpectations(s.T()))
+	})
+
+	s.Run("when message not found", func() {
+		s.ctx.Set("ticker", storage.Ticker{ID: 1})
+		h := s.handler()
+		h.PublishDraftMessage(s.ctx)
+
+		s.Equal(http.StatusNotFound, s.w.Code)
+		s.True(s.store.AssertExpectations(s.T()))
+	})
+
+	s.Run("when message is not a draft", func() {
+		s.ctx.Set("ticker", storage.Ticker{ID: 1})
+		s.ctx.Set("message", storage.Message{ID: 1})
+		h := s.handler()
+		h.PublishDraftMessage(s.ctx)
+
+		s.Equal(http.StatusBadRequest, s.w.Code)
+		s.True(s.store.AssertExpectations(s.T()))
+	})
+
+	s.Run("when message is pending review", func() {
+		s.ctx.Set("ticker", storage.Ticker{ID: 1})
+		s.ctx.Set("message", storage.Message{ID: 1, Draft: true, Review: storage.ReviewPending})
+		h := s.handler()
+		h.PublishDraftMessage(s.ctx)
+
+		s.Equal(http.StatusBadRequest, s.w.Code)
+		s.True(s.store.AssertExpectations(s.T()))
+	})
+
+	s.Run("when message was published in the meantime", func() {
+		s.ctx.Set("ticker", storage.Ticker{ID: 1})
+		s.ctx.Set("message", storage.Message{ID: 1, Draft: true})
+		s.ctx.Request = httptest.NewRequest(http.MethodPost, "/v1/tickers/1/messages/1/publish", nil)
+		s.store.On("PublishMessage", mock.Anything).Return(errors.New("record not found")).Once()
+		h := s.handler()
+		h.PublishDraftMessage(s.ctx)
+
+		s.Equal(http.StatusBadRequest, s.w.Code)
+		s.True(s.store.AssertExpectations(s.T()))
+	})
+
+	s.Run("when draft has a publishing date", func() {
+		s.store.On("SaveMessageRevision", mock.Anything).Return(nil).Once()
+		publishAt := time.Now().Add(time.Hour)
+		s.ctx.Set("ticker", storage.Ticker{ID: 1})
+		s.ctx.Set("message", storage.Message{ID: 1, Draft: true, PublishAt: &publishAt})
+		s.store.On("SaveMessage", mock.MatchedBy(func(m *storage.Message) bool {
+			return !m.Draft && m.IsScheduled()
+		})).Return(nil).Once()
+		h := s.handler()
+		h.PublishDraftMessage(s.ctx)
+
+		s.Equal(http.StatusOK, s.w.Code)
+		s.True(s.store.AssertExpectations(s.T()))
+	})
+
+	s.Run("happy path", func() {
+		s.store.On("SaveMessageRevision", mock.MatchedBy(func(r *storage.MessageRevision) bool {
+			return r.Action == storage.RevisionPublished
+		})).Return(nil).Once()
+		s.ctx.Set("ticker", storage.Ticker{ID: 1, Domain: "localhost"})
+		s.ctx.Set("message", storage.Message{ID: 1, Draft: true})
+		s.ctx.Request = httptest.NewRequest(http.MethodPost, "/v1/tickers/1/messages/1/publish", nil)
+		s.cache.Set("response:localhost:/v1/timeline", true, time.Minute)
+		s.store.On("PublishMessage", mock.Anything).Return(nil).Run(func(args mock.Arguments) {
+			args.Get(0).(*storage.Message).Draft = false
+		}).Once()
+		s.store.On("SaveMessage", mock.Anything).Return(nil).Once()
+		h := s.handler()
+		h.PublishDraftMessage(s.ctx)
+
+		s.Equal(http.StatusOK, s.w.Code)
+		s.NotContains(s.w.Body.String(), `"draft":true`)
+		s.Nil(s.cache.Get("response:localhost:/v1/timeline"))
+		s.True(s.store.AssertExpectations(s.T()))
+	})
+}
+
+func (s *MessagesTestSuite) TestPinMessage() {
+	s.Run("when ticker not found", func() {
+		h := s.handler()
+		h.PinMessage(s.ctx)
+
+		s.Equal(http.StatusNotFound, s.w.Code)
+		s.True(s.store.AssertExpectations(s.T()))
+	})
+
+	s.Run("when message not found", func() {
+		s.ctx.Set("ticker", storage.Ticker{ID: 1})
+		h := s.handler()
+		h.PinMessage(s.ctx)
+
+		s.Equal(http.StatusNotFound, s.w.Code)
+		s.True(s.store.AssertExpectations(s.T()))
+	})
+
+	s.Run("when message is a draft", func() {
+		s.ctx.Set("ticker", storage.Ticker{ID: 1})
+		s.ctx.Set("message", storage.Message{ID: 1, Draft: true})
+		h := s.handler()
+		h.PinMessage(s.ctx)
+
+		s.Equal(http.StatusBadRequest, s.w.Code)
+		s.Contains(s.w.Body.String(), response.MessageNotPublished)
+		s.True(s.store.AssertExpectations(s.T()))
+	})
+
+	s.Run("when storage returns error", func() {
+		s.ctx.Set("ticker", storage.Ticker{ID: 1})
+		s.ctx.Set("message", storage.Message{ID: 1})
+		s.store.On("SaveMessage", mock.Anything).Return(errors.New("storage error")).Once()
+		h := s.handler()
+		h.PinMessage(s.ctx)
+
+		s.Equal(http.StatusBadRequest, s.w.Code)
+		s.True(s.store.AssertExpectations(s.T()))
+	})
+
+	s.Run("happy path", func() {
+		s.store.On("SaveMessageRevision", mock.MatchedBy(func(r *storage.MessageRevision) bool {
+			return r.Action == storage.RevisionPinned
+		})).Return(nil).Once()
+		ticker := storage.Ticker{ID: 1, Domain: "localhost", Websites: []storage.TickerWebsite{{Origin: "https://localhost"}}}
+		s.cache.Set("response:localhost:/v1/timeline", true, time.Minute)
+		s.cache.Set("response:https://localhost:/v1/init", true, time.Minute)
+		s.ctx.Request = httptest.NewRequest(http.MethodPost, "/v1/admin/tickers/1/messages/1/pin", nil)
+		s.ctx.Set("ticker", ticker)
+		s.ctx.Set("message", storage.Message{ID: 1})
+		s.store.On("SaveMessage", mock.MatchedBy(func(m *storage.Message) bool {
+			return m.Pinned
+		})).Return(nil).Once()
+		mockBridge := &bridge.MockBridge{}
+		mockBridge.On("Pin", ticker, mock.Anything).Return(nil).Once()
+		h := s.handler()
+		h.bridges = bridge.Bridges{"mock": mockBridge}
+		h.PinMessage(s.ctx)
+
+		s.Equal(http.StatusOK, s.w.Code)
+		s.Contains(s.w.Body.String(), `"pinned":true`)
+		s.Nil(s.cache.Get("response:localhost:/v1/timeline"))
+		s.Nil(s.cache.Get("response:https://localhost:/v1/init"))
+		s.True(s.store.AssertExpectations(s.T()))
+		s.True(mockBridge.AssertExpectations(s.T()))
+	})
+}
+
+func (s *MessagesTestSuite) TestUnpinMessage() {
+	s.Run("happy path", func() {
+		s.store.On("SaveMessageRevision", mock.MatchedBy(func(r *storage.MessageRevision) bool {
+			return r.Action == storage.RevisionUnpinned
+		})).Return(nil).Once()
+		ticker := storage.Ticker{ID: 1, Domain: "localhost"}
+		s.ctx.Request = httptest.NewRequest(http.MethodDelete, "/v1/admin/tickers/1/messages/1/pin", nil)
+		s.ctx.Set("ticker", ticker)
+		s.ctx.Set("message", storage.Message{ID: 1, Pinned: true})
+		s.store.On("SaveMessage", mock.MatchedBy(func(m *storage.Message) bool {
+			return !m.Pinned
+		})).Return(nil).Once()
+		mockBridge := &bridge.MockBridge{}
+		mockBridge.On("Unpin", ticker, mock.Anything).Return(nil).Once()
+		h := s.handler()
+		h.bridges = bridge.Bridges{"mock": mockBridge}
+		h.UnpinMessage(s.ctx)
+
+		s.Equal(http.StatusOK, s.w.Code)
+		s.NotContains(s.w.Body.String(), `"pinned"`)
+		s.True(s.store.AssertExpectations(s.T()))
+		s.True(mockBridge.AssertExpectations(s.T()))
+	})
+}
+
 func (s *MessagesTestSuite) TestDeleteMessage() {
 	s.Run("when ticker not found", func() {
 		h := s.handler()
@@ -185,7 +1023,7 @@ func (s *MessagesTestSuite) TestDeleteMessage() {
 		message := storage.Message{ID: 1}
 		s.ctx.Set("ticker", ticker)
 		s.ctx.Set("message", message)
-		s.store.On("DeleteMessage", message).Return(errors.New("storage error")).Once()
+		s.store.On("DeleteMessage", message, []string(nil)).Return(errors.New("storage error")).Once()
 		h := s.handler()
 		h.DeleteMessage(s.ctx)
 
@@ -201,13 +1039,52 @@ func (s *MessagesTestSuite) TestDeleteMessage() {
 		s.ctx.Request.Header.Add("Content-Type", "application/json")
 		s.ctx.Set("ticker", ticker)
 		s.ctx.Set("message", message)
-		s.store.On("DeleteMessage", message).Return(nil).Once()
+		s.store.On("DeleteMessage", message, []string{"mastodon", "mock"}).Return(nil).Once()
+		s.store.On("SaveMessageRevision", mock.MatchedBy(func(r *storage.MessageRevision) bool {
+			return r.MessageID == 1 && r.Action == storage.RevisionDeleted
+		})).Return(nil).Once()
+		mockBridge := &bridge.MockBridge{}
 		h := s.handler()
+		h.bridges = bridge.Bridges{"mock": mockBridge, "mastodon": mockBridge}
 		h.DeleteMessage(s.ctx)
 
 		s.Equal(http.StatusOK, s.w.Code)
 		s.Nil(s.cache.Get("response:localhost:/v1/timeline"))
 		s.True(s.store.AssertExpectations(s.T()))
+		mockBridge.AssertNotCalled(s.T(), "Delete", mock.Anything, mock.Anything)
+	})
+
+	s.Run("when message is a draft", func() {
+		ticker := storage.Ticker{ID: 1}
+		message := storage.Message{ID: 1, Draft: true}
+		s.ctx.Request = httptest.NewRequest(http.MethodDelete, "/v1/tickers/1/messages/1", nil)
+		s.ctx.Set("ticker", ticker)
+		s.ctx.Set("message", message)
+		s.store.On("DeleteMessage", message, []string(nil)).Return(nil).Once()
+		s.store.On("SaveMessageRevision", mock.Anything).Return(nil).Once()
+		h := s.handler()
+		h.bridges = bridge.Bridges{"mock": &bridge.MockBridge{}}
+		h.DeleteMessage(s.ctx)
+
+		s.Equal(http.StatusOK, s.w.Code)
+		s.True(s.store.AssertExpectations(s.T()))
+	})
+
+	s.Run("when message is pinned", func() {
+		ticker := storage.Ticker{ID: 1, Domain: "localhost", Websites: []storage.TickerWebsite{{Origin: "https://localhost"}}}
+		message := storage.Message{ID: 1, Pinned: true}
+		s.cache.Set("response:https://localhost:/v1/init", true, time.Minute)
+		s.ctx.Request = httptest.NewRequest(http.MethodDelete, "/v1/tickers/1/messages/1", nil)
+		s.ctx.Set("ticker", ticker)
+		s.ctx.Set("message", message)
+		s.store.On("DeleteMessage", message, []string(nil)).Return(nil).Once()
+		s.store.On("SaveMessageRevision", mock.Anything).Return(nil).Once()
+		h := s.handler()
+		h.DeleteMessage(s.ctx)
+
+		s.Equal(http.StatusOK, s.w.Code)
+		s.Nil(s.cache.Get("response:https://localhost:/v1/init"))
+		s.True(s.store.AssertExpectations(s.T()))
 	})
 }
 
@@ -8,18 +8,47 @@ import (
 	"github.com/gin-gonic/gin"
 	"github.com/systemli/ticker/internal/api/response"
 	"github.com/systemli/ticker/internal/logger"
+	"github.com/systemli/ticker/internal/passkey"
+	"github.com/systemli/ticker/internal/sso"
 	"github.com/systemli/ticker/internal/storage"
+	"github.com/systemli/ticker/internal/totp"
 )
 
 var log = logger.GetWithPackage("auth")
 
-func AuthMiddleware(s storage.Storage, secret string) *jwt.GinJWTMiddleware {
+const (
+	// maxSecondFactorAttempts is how many codes a user can enter in a row
+	// before the codes are refused for secondFactorLockout. A correct code
+	// starts over.
+	maxSecondFactorAttempts = 5
+	secondFactorLockout     = 15 * time.Minute
+)
+
+// ErrTwoFactorRequired is returned for the correct password of a user with a
+// second factor when the login comes without a code.
+var ErrTwoFactorRequired = errors.New("second factor required")
+
+// ErrSingleSignOnRequired is returned for the correct password or passkey of
+// a user who is linked to the identity provider, as these users only log in
+// through it.
+var ErrSingleSignOnRequired = errors.New("single sign-on required")
+
+// ErrSecondFactorLocked is returned while the codes of a user are refused
+// after too many wrong ones.
+var ErrSecondFactorLocked = errors.New("too many wrong codes")
+
+// errAuthenticationFailed is returned for a wrong password or second factor.
+var errAuthenticationFailed = errors.New("authentication failed")
+
+// AuthMiddleware returns the middleware for the JWT of the admin interface.
+// Passkeys and the identity provider can be nil when they are not configured.
+func AuthMiddleware(s storage.Storage, secret string, passkeys *passkey.RelyingParty, provider *sso.Provider) *jwt.GinJWTMiddleware {
 	config := &jwt.GinJWTMiddleware{
 		Realm:         "ticker admin",
 		Key:           []byte(secret),
 		Timeout:       time.Hour * 24,
 		MaxRefresh:    time.Hour * 24,
-		Authenticator: Authenticator(s),
+		Authenticator: Authenticator(s, passkeys, provider),
 		Authorizator:  Authorizator(s),
 		Unauthorized:  Unauthorized,
 		PayloadFunc:   FillClaim,
@@ -36,11 +65,20 @@ func AuthMiddleware(s storage.Storage, secret string) *jwt.GinJWTMiddleware {
 	return middleware
 }
 
-func Authenticator(s storage.Storage) func(c *gin.Context) (interface{}, error) {
+func Authenticator(s storage.Storage, passkeys *passkey.RelyingParty, provider *sso.Provider) func(c *gin.Context) (interface{}, error) {
 	return func(c *gin.Context) (interface{}, error) {
 		type login struct {
-			Username string `form:"username" json:"username" binding:"required"`
-			Password string `form:"password" json:"password" binding:"required"`
+			Username string `form:"username" json:"username"`
+			Password string `form:"password" json:"password"`
+			// Code is a code of the authenticator app or a recovery code.
+			Code string `form:"code" json:"code"`
+			// Passkey answers the options of /login/webauthn. Without a
+			// password it replaces the password, otherwise it is the second
+			// factor.
+			Passkey *passkey.Response `json:"passkey"`
+			// OIDC is the callback of the identity provider after the
+			// single sign-on.
+			OIDC *sso.Callback `json:"oidc"`
 		}
 
 		var form login
@@ -48,23 +86,180 @@ func Authenticator(s storage.Storage) func(c *gin.Context) (interface{}, error)
 			return "", jwt.ErrMissingLoginValues
 		}
 
+		if form.OIDC != nil {
+			return singleSignOn(c, provider, *form.OIDC)
+		}
+
+		if form.Password == "" && form.Passkey != nil {
+			return passkeyLogin(s, passkeys, provider, *form.Passkey)
+		}
+
+		if form.Username == "" || form.Password == "" {
+			return "", jwt.ErrMissingLoginValues
+		}
+
 		user, err := s.FindUserByEmail(form.Username, storage.WithPreload())
 		if err != nil {
 			log.WithError(err).Debug("user not found")
 			return "", err
 		}
 
-		if user.Authenticate(form.Password) {
-			user.LastLogin = time.Now()
-			if err = s.SaveUser(&user); err != nil {
-				log.WithError(err).Error("failed to save user")
-			}
+		if !user.Authenticate(form.Password) {
+			return "", errAuthenticationFailed
+		}
 
-			return user, nil
+		if linked(provider, user) {
+			return "", ErrSingleSignOnRequired
 		}
 
-		return "", errors.New("authentication failed")
+		if err := checkSecondFactor(s, passkeys, &user, form.Code, form.Passkey); err != nil {
+			return "", err
+		}
+
+		saveLastLogin(s, &user)
+
+		return user, nil
+	}
+}
+
+// singleSignOn logs the user in whom the identity provider sent back.
+func singleSignOn(c *gin.Context, provider *sso.Provider, callback sso.Callback) (interface{}, error) {
+	if provider == nil {
+		return "", errAuthenticationFailed
+	}
+
+	user, err := provider.Login(c.Request.Context(), callback)
+	if err != nil {
+		log.WithError(err).Info("single sign-on failed")
+		return "", errAuthenticationFailed
 	}
+
+	return user, nil
+}
+
+// passkeyLogin logs the user in whom the passkey belongs to.
+func passkeyLogin(s storage.Storage, passkeys *passkey.RelyingParty, provider *sso.Provider, response passkey.Response) (interface{}, error) {
+	if passkeys == nil {
+		return "", errAuthenticationFailed
+	}
+
+	user, err := passkeys.FinishLogin(response)
+	if err != nil {
+		log.WithError(err).Debug("passkey not verified")
+		return "", errAuthenticationFailed
+	}
+
+	if linked(provider, user) {
+		return "", ErrSingleSignOnRequired
+	}
+
+	saveLastLogin(s, &user)
+
+	return user, nil
+}
+
+// checkSecondFactor asks users with a second factor for a code of the
+// authenticator app, a recovery code or a passkey.
+func checkSecondFactor(s storage.Storage, passkeys *passkey.RelyingParty, user *storage.User, code string, response *passkey.Response) error {
+	hasPasskeys := false
+	if passkeys != nil {
+		count, err := s.CountWebAuthnCredentials(*user)
+		if err != nil {
+			log.WithError(err).Error("failed to count passkeys")
+			return err
+		}
+		hasPasskeys = count > 0
+	}
+
+	if !user.TOTPEnabled && !hasPasskeys {
+		return nil
+	}
+
+	switch {
+	case response != nil && hasPasskeys:
+		if err := passkeys.Verify(*user, *response); err != nil {
+			log.WithError(err).Debug("passkey not verified")
+			return errAuthenticationFailed
+		}
+	case code != "" && user.TOTPEnabled:
+		valid, err := VerifySecondFactor(s, user, code)
+		if errors.Is(err, ErrSecondFactorLocked) {
+			return err
+		}
+		if err != nil {
+			log.WithError(err).Error("failed to verify second factor")
+			return err
+		}
+		if !valid {
+			return errAuthenticationFailed
+		}
+	default:
+		return ErrTwoFactorRequired
+	}
+
+	return nil
+}
+
+// linked reports whether the user has to log in through the identity
+// provider. Once it is turned off, linked users log in like everybody else.
+func linked(provider *sso.Provider, user storage.User) bool {
+	return provider != nil && user.OIDCSubject != ""
+}
+
+func saveLastLogin(s storage.Storage, user *storage.User) {
+	user.LastLogin = time.Now()
+	if err := s.SaveUser(user); err != nil {
+		log.WithError(err).Error("failed to save user")
+	}
+}
+
+// VerifySecondFactor checks a code of the authenticator app, or a recovery
+// code. Both can be used only once.
+func VerifySecondFactor(s storage.Storage, user *storage.User, code string) (bool, error) {
+	return ThrottleSecondFactor(s, user, func() (bool, error) {
+		if step, valid := totp.Validate(user.TOTPSecret, code, time.Now()); valid {
+			return s.UseTOTPStep(user, step)
+		}
+
+		return s.UseRecoveryCode(*user, code)
+	})
+}
+
+// ThrottleSecondFactor runs verify for a code of the user, unless the user
+// entered too many wrong codes. Every code is counted before it is checked,
+// so parallel guesses count too, and a correct one resets the count. Once
+// the count is exceeded, the codes of the user are refused for a while with
+// ErrSecondFactorLocked.
+func ThrottleSecondFactor(s storage.Storage, user *storage.User, verify func() (bool, error)) (bool, error) {
+	if time.Now().Before(user.TOTPLockedUntil) {
+		return false, ErrSecondFactorLocked
+	}
+
+	attempts, err := s.AddTOTPAttempt(user)
+	if err != nil {
+		return false, err
+	}
+	if attempts > maxSecondFactorAttempts {
+		log.WithField("user_id", user.ID).Warn("too many wrong codes, lock second factor")
+		user.TOTPAttempts = 0
+		user.TOTPLockedUntil = time.Now().Add(secondFactorLockout)
+		if err := s.SaveTOTPLock(user); err != nil {
+			return false, err
+		}
+		return false, ErrSecondFactorLocked
+	}
+
+	valid, err := verify()
+	if err != nil || !valid {
+		return false, err
+	}
+
+	user.TOTPAttempts = 0
+	if err := s.SaveTOTPLock(user); err != nil {
+		return false, err
+	}
+
+	return true, nil
 }
 
 func Authorizator(s storage.Storage) func(data interface{}, c *gin.Context) bool {
@@ -82,6 +277,18 @@ func Authorizator(s storage.Storage) func(data interface{}, c *gin.Context) bool
 
 func Unauthorized(c *gin.Context, code int, message string) {
 	log.WithFields(map[string]interface{}{"code": code, "message": message, "url": c.Request.URL.String()}).Debug("unauthorized")
+	if message == ErrTwoFactorRequired.Error() {
+		c.JSON(code, response.ErrorResponse(response.CodeTwoFactorRequired, response.TwoFactorRequired))
+		return
+	}
+	if message == ErrSecondFactorLocked.Error() {
+		c.JSON(code, response.ErrorResponse(response.CodeBadCredentials, response.TwoFactorLocked))
+		return
+	}
+	if message == ErrSingleSignOnRequired.Error() {
+		c.JSON(code, response.ErrorResponse(response.CodeSingleSignOnRequired, response.SingleSignOnRequired))
+		return
+	}
 	c.JSON(code, response.ErrorResponse(response.CodeBadCredentials, response.Unauthorized))
 }
 
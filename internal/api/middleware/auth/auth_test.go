@@ -1,22 +1,31 @@
 package auth
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
 	"net/http"
 	"net/http/httptest"
 	"strings"
 	"testing"
+	"time"
 
 	jwt "github.com/appleboy/gin-jwt/v2"
 	"github.com/gin-gonic/gin"
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/suite"
 	"github.com/systemli/ticker/internal/api/response"
+	"github.com/systemli/ticker/internal/config"
+	"github.com/systemli/ticker/internal/passkey"
+	"github.com/systemli/ticker/internal/passkey/passkeytest"
+	"github.com/systemli/ticker/internal/sso"
+	"github.com/systemli/ticker/internal/sso/ssotest"
 	"github.com/systemli/ticker/internal/storage"
+	"github.com/systemli/ticker/internal/totp"
 )
 
 type AuthTestSuite struct {
+	store *storage.MockStorage
 	suite.Suite
 }
 
@@ -27,7 +36,7 @@ func (s *AuthTestSuite) SetupTest() {
 func (s *AuthTestSuite) TestAuthenticator() {
 	s.Run("when form is empty", func() {
 		mockStorage := &storage.MockStorage{}
-		authenticator := Authenticator(mockStorage)
+		authenticator := Authenticator(mockStorage, nil, nil)
 		c, _ := gin.CreateTestContext(httptest.NewRecorder())
 		c.Request = httptest.NewRequest(http.MethodPost, "/login", strings.NewReader(`{}`))
 
@@ -40,7 +49,7 @@ func (s *AuthTestSuite) TestAuthenticator() {
 		mockStorage := &storage.MockStorage{}
 		mockStorage.On("FindUserByEmail", mock.Anything, mock.Anything).Return(storage.User{}, errors.New("not found"))
 
-		authenticator := Authenticator(mockStorage)
+		authenticator := Authenticator(mockStorage, nil, nil)
 		c, _ := gin.CreateTestContext(httptest.NewRecorder())
 		c.Request = httptest.NewRequest(http.MethodPost, "/login", strings.NewReader(`{"username": "user@systemli.org", "password": "password"}`))
 		c.Request.Header.Set("Content-Type", "application/json")
@@ -57,7 +66,7 @@ func (s *AuthTestSuite) TestAuthenticator() {
 		mockStorage := &storage.MockStorage{}
 		mockStorage.On("FindUserByEmail", mock.Anything, mock.Anything).Return(user, nil)
 		mockStorage.On("SaveUser", mock.Anything).Return(nil)
-		authenticator := Authenticator(mockStorage)
+		authenticator := Authenticator(mockStorage, nil, nil)
 
 		s.Run("with correct password", func() {
 			c, _ := gin.CreateTestContext(httptest.NewRecorder())
@@ -82,6 +91,304 @@ func (s *AuthTestSuite) TestAuthenticator() {
 	})
 }
 
+func (s *AuthTestSuite) TestAuthenticatorWithSecondFactor() {
+	secret, err := totp.GenerateSecret()
+	s.NoError(err)
+	user, err := storage.NewUser("user@systemli.org", "password")
+	s.NoError(err)
+	user.TOTPSecret = secret
+	user.TOTPEnabled = true
+
+	login := func(body string) (interface{}, error) {
+		c, _ := gin.CreateTestContext(httptest.NewRecorder())
+		c.Request = httptest.NewRequest(http.MethodPost, "/login", strings.NewReader(body))
+		c.Request.Header.Set("Content-Type", "application/json")
+
+		return Authenticator(s.store, nil, nil)(c)
+	}
+
+	s.Run("when the code is missing", func() {
+		s.store = &storage.MockStorage{}
+		s.store.On("FindUserByEmail", mock.Anything, mock.Anything).Return(user, nil)
+
+		_, err := login(`{"username": "user@systemli.org", "password": "password"}`)
+		s.Equal(ErrTwoFactorRequired, err)
+	})
+
+	s.Run("when the password is wrong", func() {
+		s.store = &storage.MockStorage{}
+		s.store.On("FindUserByEmail", mock.Anything, mock.Anything).Return(user, nil)
+
+		_, err := login(`{"username": "user@systemli.org", "password": "password1"}`)
+		s.Equal("authentication failed", err.Error())
+	})
+
+	s.Run("with a code of the authenticator app", func() {
+		code, err := totp.Code(secret, time.Now())
+		s.NoError(err)
+
+		s.store = &storage.MockStorage{}
+		s.store.On("FindUserByEmail", mock.Anything, mock.Anything).Return(user, nil)
+		s.store.On("AddTOTPAttempt", mock.Anything).Return(1, nil).Once()
+		s.store.On("UseTOTPStep", mock.Anything, mock.Anything).Return(true, nil).Once()
+		s.store.On("SaveTOTPLock", mock.Anything).Return(nil).Once()
+		s.store.On("SaveUser", mock.Anything).Return(nil)
+
+		_, err = login(`{"username": "user@systemli.org", "password": "password", "code": "` + code + `"}`)
+		s.NoError(err)
+		s.store.AssertExpectations(s.T())
+	})
+
+	s.Run("with a used code of the authenticator app", func() {
+		code, err := totp.Code(secret, time.Now())
+		s.NoError(err)
+
+		s.store = &storage.MockStorage{}
+		s.store.On("FindUserByEmail", mock.Anything, mock.Anything).Return(user, nil)
+		s.store.On("AddTOTPAttempt", mock.Anything).Return(1, nil).Once()
+		s.store.On("UseTOTPStep", mock.Anything, mock.Anything).Return(false, nil).Once()
+
+		_, err = login(`{"username": "user@systemli.org", "password": "password", "code": "` + code + `"}`)
+		s.Equal("authentication failed", err.Error())
+	})
+
+	s.Run("with a recovery code", func() {
+		s.store = &storage.MockStorage{}
+		s.store.On("FindUserByEmail", mock.Anything, mock.Anything).Return(user, nil)
+		s.store.On("AddTOTPAttempt", mock.Anything).Return(1, nil).Once()
+		s.store.On("UseRecoveryCode", mock.Anything, "abcde-12345").Return(true, nil).Once()
+		s.store.On("SaveTOTPLock", mock.Anything).Return(nil).Once()
+		s.store.On("SaveUser", mock.Anything).Return(nil)
+
+		_, err := login(`{"username": "user@systemli.org", "password": "password", "code": "abcde-12345"}`)
+		s.NoError(err)
+		s.store.AssertExpectations(s.T())
+	})
+
+	s.Run("with a wrong code", func() {
+		s.store = &storage.MockStorage{}
+		s.store.On("FindUserByEmail", mock.Anything, mock.Anything).Return(user, nil)
+		s.store.On("AddTOTPAttempt", mock.Anything).Return(1, nil).Once()
+		s.store.On("UseRecoveryCode", mock.Anything, "wrong").Return(false, nil).Once()
+
+		_, err := login(`{"username": "user@systemli.org", "password": "password", "code": "wrong"}`)
+		s.Equal("authentication failed", err.Error())
+	})
+
+	s.Run("after too many wrong codes", func() {
+		s.store = &storage.MockStorage{}
+		s.store.On("FindUserByEmail", mock.Anything, mock.Anything).Return(user, nil)
+		s.store.On("AddTOTPAttempt", mock.Anything).Return(6, nil).Once()
+		s.store.On("SaveTOTPLock", mock.MatchedBy(func(u *storage.User) bool {
+			return u.TOTPAttempts == 0 && u.TOTPLockedUntil.After(time.Now())
+		})).Return(nil).Once()
+
+		_, err := login(`{"username": "user@systemli.org", "password": "password", "code": "wrong"}`)
+		s.Equal(ErrSecondFactorLocked, err)
+		s.store.AssertExpectations(s.T())
+	})
+
+	s.Run("while the codes are locked", func() {
+		locked := user
+		locked.TOTPLockedUntil = time.Now().Add(time.Minute)
+		code, err := totp.Code(secret, time.Now())
+		s.NoError(err)
+
+		s.store = &storage.MockStorage{}
+		s.store.On("FindUserByEmail", mock.Anything, mock.Anything).Return(locked, nil)
+
+		_, err = login(`{"username": "user@systemli.org", "password": "password", "code": "` + code + `"}`)
+		s.Equal(ErrSecondFactorLocked, err)
+		s.store.AssertExpectations(s.T())
+	})
+
+	s.Run("when storage returns error", func() {
+		s.store = &storage.MockStorage{}
+		s.store.On("FindUserByEmail", mock.Anything, mock.Anything).Return(user, nil)
+		s.store.On("AddTOTPAttempt", mock.Anything).Return(1, nil).Once()
+		s.store.On("UseRecoveryCode", mock.Anything, "wrong").Return(false, errors.New("storage error")).Once()
+
+		_, err := login(`{"username": "user@systemli.org", "password": "password", "code": "wrong"}`)
+		s.Error(err)
+	})
+}
+
+func (s *AuthTestSuite) TestAuthenticatorWithPasskey() {
+	user, err := storage.NewUser("user@systemli.org", "password")
+	s.NoError(err)
+	user.ID = 1
+	user.WebAuthnHandle = "handle"
+
+	sessions := make(map[string]storage.WebAuthnSession)
+	var credentials []storage.WebAuthnCredential
+	s.store = &storage.MockStorage{}
+	s.store.On("SaveWebAuthnSession", mock.Anything).Run(func(args mock.Arguments) {
+		session := args.Get(0).(*storage.WebAuthnSession)
+		sessions[session.Token] = *session
+	}).Return(nil)
+	s.store.On("TakeWebAuthnSession", mock.Anything).Return(func(token string) (storage.WebAuthnSession, error) {
+		session, ok := sessions[token]
+		if !ok {
+			return storage.WebAuthnSession{}, errors.New("not found")
+		}
+		delete(sessions, token)
+
+		return session, nil
+	})
+	s.store.On("FindWebAuthnCredentials", mock.Anything).Return(func(storage.User) ([]storage.WebAuthnCredential, error) {
+		return credentials, nil
+	})
+	s.store.On("SaveWebAuthnCredential", mock.Anything).Run(func(args mock.Arguments) {
+		credential := args.Get(0).(*storage.WebAuthnCredential)
+		credential.ID = 1
+		credentials = []storage.WebAuthnCredential{*credential}
+	}).Return(nil)
+	s.store.On("CountWebAuthnCredentials", mock.Anything).Return(int64(1), nil)
+	s.store.On("FindUserByEmail", mock.Anything, mock.Anything).Return(user, nil)
+	s.store.On("FindUserByWebAuthnHandle", "handle", mock.Anything).Return(user, nil)
+	s.store.On("SaveUser", mock.Anything).Return(nil)
+
+	passkeys, err := passkey.New(config.WebAuthn{RPID: passkeytest.RPID, Origins: []string{passkeytest.Origin}}, s.store)
+	s.Require().NoError(err)
+
+	authenticator := passkeytest.NewAuthenticator(s.T())
+	creation, token, err := passkeys.BeginRegistration(&user)
+	s.Require().NoError(err)
+	_, err = passkeys.FinishRegistration(user, "Laptop", passkey.Response{Session: token, Credential: authenticator.Create(s.T(), creation)})
+	s.Require().NoError(err)
+
+	login := func(rp *passkey.RelyingParty, body map[string]interface{}) (interface{}, error) {
+		b, err := json.Marshal(body)
+		s.Require().NoError(err)
+
+		c, _ := gin.CreateTestContext(httptest.NewRecorder())
+		c.Request = httptest.NewRequest(http.MethodPost, "/login", strings.NewReader(string(b)))
+		c.Request.Header.Set("Content-Type", "application/json")
+
+		return Authenticator(s.store, rp, nil)(c)
+	}
+
+	answer := func(user *storage.User) passkey.Response {
+		assertion, token, err := passkeys.BeginLogin(user)
+		s.Require().NoError(err)
+
+		return passkey.Response{Session: token, Credential: authenticator.Get(s.T(), assertion)}
+	}
+
+	s.Run("without a password", func() {
+		u, err := login(passkeys, map[string]interface{}{"passkey": answer(nil)})
+		s.NoError(err)
+		s.Equal(1, u.(storage.User).ID)
+	})
+
+	s.Run("when passkeys are disabled", func() {
+		_, err := login(nil, map[string]interface{}{"passkey": answer(nil)})
+		s.Equal("authentication failed", err.Error())
+	})
+
+	s.Run("when the session is used again", func() {
+		response := answer(nil)
+		_, err := login(passkeys, map[string]interface{}{"passkey": response})
+		s.NoError(err)
+
+		_, err = login(passkeys, map[string]interface{}{"passkey": response})
+		s.Equal("authentication failed", err.Error())
+	})
+
+	s.Run("when the passkey is missing", func() {
+		_, err := login(passkeys, map[string]interface{}{"username": "user@systemli.org", "password": "password"})
+		s.Equal(ErrTwoFactorRequired, err)
+	})
+
+	s.Run("with the passkey as second factor", func() {
+		_, err := login(passkeys, map[string]interface{}{"username": "user@systemli.org", "password": "password", "passkey": answer(&user)})
+		s.NoError(err)
+	})
+
+	s.Run("with a code instead of the passkey", func() {
+		_, err := login(passkeys, map[string]interface{}{"username": "user@systemli.org", "password": "password", "code": "123456"})
+		s.Equal(ErrTwoFactorRequired, err)
+	})
+}
+
+func (s *AuthTestSuite) TestAuthenticatorWithSingleSignOn() {
+	server := ssotest.NewServer(s.T())
+	user, err := storage.NewUser("user@systemli.org", "password")
+	s.NoError(err)
+	user.ID = 1
+	user.OIDCSubject = "subject"
+
+	sessions := make(map[string]storage.OIDCSession)
+	s.store = &storage.MockStorage{}
+	s.store.On("SaveOIDCSession", mock.Anything).Run(func(args mock.Arguments) {
+		session := args.Get(0).(*storage.OIDCSession)
+		sessions[session.State] = *session
+	}).Return(nil)
+	s.store.On("TakeOIDCSession", mock.Anything).Return(func(state string) (storage.OIDCSession, error) {
+		session, ok := sessions[state]
+		if !ok {
+			return storage.OIDCSession{}, errors.New("not found")
+		}
+		delete(sessions, state)
+
+		return session, nil
+	})
+	s.store.On("FindUserByOIDCSubject", "subject", mock.Anything).Return(user, nil)
+	s.store.On("FindUserByEmail", mock.Anything, mock.Anything).Return(user, nil)
+	s.store.On("SaveUser", mock.Anything).Return(nil)
+
+	provider := sso.New(server.Config(), s.store)
+
+	login := func(provider *sso.Provider, body map[string]interface{}) (interface{}, error) {
+		b, err := json.Marshal(body)
+		s.Require().NoError(err)
+
+		c, _ := gin.CreateTestContext(httptest.NewRecorder())
+		c.Request = httptest.NewRequest(http.MethodPost, "/login", strings.NewReader(string(b)))
+		c.Request.Header.Set("Content-Type", "application/json")
+
+		return Authenticator(s.store, nil, provider)(c)
+	}
+
+	callback := func() sso.Callback {
+		url, err := provider.AuthURL(context.Background())
+		s.Require().NoError(err)
+		code, state := server.Authorize(s.T(), url, map[string]interface{}{"sub": "subject"})
+
+		return sso.Callback{Code: code, State: state}
+	}
+
+	s.Run("with the callback of the identity provider", func() {
+		u, err := login(provider, map[string]interface{}{"oidc": callback()})
+		s.NoError(err)
+		s.Equal(1, u.(storage.User).ID)
+	})
+
+	s.Run("when single sign-on is disabled", func() {
+		_, err := login(nil, map[string]interface{}{"oidc": callback()})
+		s.Equal("authentication failed", err.Error())
+	})
+
+	s.Run("when the callback is used again", func() {
+		cb := callback()
+		_, err := login(provider, map[string]interface{}{"oidc": cb})
+		s.NoError(err)
+
+		_, err = login(provider, map[string]interface{}{"oidc": cb})
+		s.Equal("authentication failed", err.Error())
+	})
+
+	s.Run("when a linked user logs in with the password", func() {
+		_, err := login(provider, map[string]interface{}{"username": "user@systemli.org", "password": "password"})
+		s.Equal(ErrSingleSignOnRequired, err)
+	})
+
+	s.Run("when a linked user logs in while single sign-on is disabled", func() {
+		_, err := login(nil, map[string]interface{}{"username": "user@systemli.org", "password": "password"})
+		s.NoError(err)
+	})
+}
+
 func (s *AuthTestSuite) TestAuthorizator() {
 	s.Run("when user is not found", func() {
 		mockStorage := &storage.MockStorage{}
@@ -116,6 +423,45 @@ func (s *AuthTestSuite) TestUnauthorized() {
 		s.NoError(err)
 		s.Equal(403, rr.Code)
 	})
+
+	s.Run("when the second factor is missing", func() {
+		rr := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(rr)
+		c.Request = httptest.NewRequest(http.MethodPost, "/login", nil)
+
+		Unauthorized(c, 401, ErrTwoFactorRequired.Error())
+
+		var res response.Response
+		s.NoError(json.Unmarshal(rr.Body.Bytes(), &res))
+		s.Equal(401, rr.Code)
+		s.Equal(response.CodeTwoFactorRequired, res.Error.Code)
+	})
+
+	s.Run("when the codes are locked", func() {
+		rr := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(rr)
+		c.Request = httptest.NewRequest(http.MethodPost, "/login", nil)
+
+		Unauthorized(c, 401, ErrSecondFactorLocked.Error())
+
+		var res response.Response
+		s.NoError(json.Unmarshal(rr.Body.Bytes(), &res))
+		s.Equal(401, rr.Code)
+		s.Equal(response.TwoFactorLocked, res.Error.Message)
+	})
+
+	s.Run("when the user has to use single sign-on", func() {
+		rr := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(rr)
+		c.Request = httptest.NewRequest(http.MethodPost, "/login", nil)
+
+		Unauthorized(c, 401, ErrSingleSignOnRequired.Error())
+
+		var res response.Response
+		s.NoError(json.Unmarshal(rr.Body.Bytes(), &res))
+		s.Equal(401, rr.Code)
+		s.Equal(response.CodeSingleSignOnRequired, res.Error.Code)
+	})
 }
 
 func (s *AuthTestSuite) TestFillClaims() {
@@ -0,0 +1,88 @@
+package auth
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	jwt "github.com/appleboy/gin-jwt/v2"
+	"github.com/gin-gonic/gin"
+	"github.com/systemli/ticker/internal/api/helper"
+	"github.com/systemli/ticker/internal/api/response"
+	"github.com/systemli/ticker/internal/storage"
+)
+
+// lastUsedInterval keeps busy tokens from writing to the database on every
+// request.
+const lastUsedInterval = time.Minute
+
+// TokenMiddleware authenticates a request with an API token, and falls back to
+// the JWT for requests without one. Routes behind it need NeedScope. While
+// single sign-on is on, tokens of linked users are only accepted when they
+// expire within storage.LinkedAPITokenLifetime, as the identity provider
+// cannot revoke them.
+//
+// It wraps the JWT middleware rather than being part of AuthMiddleware, so that
+// tokens only reach the routes which opt in with a scope.
+func TokenMiddleware(s storage.Storage, jwtMiddleware *jwt.GinJWTMiddleware, singleSignOn bool) gin.HandlerFunc {
+	jwtHandler := jwtMiddleware.MiddlewareFunc()
+
+	return func(c *gin.Context) {
+		plain := strings.TrimPrefix(c.GetHeader("Authorization"), "Bearer ")
+		if !strings.HasPrefix(plain, storage.APITokenPrefix) {
+			jwtHandler(c)
+			return
+		}
+
+		token, err := s.FindAPITokenByHash(storage.HashAPIToken(plain))
+		if err != nil || token.Expired() {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, response.ErrorResponse(response.CodeBadCredentials, response.Unauthorized))
+			return
+		}
+
+		if singleSignOn && token.Outlives(storage.LinkedAPITokenLifetime) {
+			user, err := s.FindUserByID(token.UserID)
+			if err != nil || user.OIDCSubject != "" {
+				c.AbortWithStatusJSON(http.StatusUnauthorized, response.ErrorResponse(response.CodeBadCredentials, response.Unauthorized))
+				return
+			}
+		}
+
+		if time.Since(token.LastUsed) > lastUsedInterval {
+			token.LastUsed = time.Now()
+			if err := s.SaveAPITokenLastUsed(&token); err != nil {
+				log.WithError(err).WithField("token_id", token.ID).Error("failed to save last use of api token")
+			}
+		}
+
+		// The identity as the JWT middleware sets it, for the me middleware.
+		c.Set("id", float64(token.UserID))
+		c.Set("apiToken", token)
+	}
+}
+
+// NeedScope aborts requests with an API token which lacks the scope, or which
+// is limited to another ticker than the one in the url. Requests with the JWT
+// pass.
+func NeedScope(scope string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		token, err := helper.APIToken(c)
+		if err != nil {
+			return
+		}
+
+		if !token.HasScope(scope) {
+			c.AbortWithStatusJSON(http.StatusForbidden, response.ErrorResponse(response.CodeInsufficientPermissions, response.InsufficientPermissions))
+			return
+		}
+
+		if c.Param("tickerID") != "" {
+			tickerID, err := strconv.Atoi(c.Param("tickerID"))
+			if err != nil || !token.AllowsTicker(tickerID) {
+				c.AbortWithStatusJSON(http.StatusForbidden, response.ErrorResponse(response.CodeInsufficientPermissions, response.InsufficientPermissions))
+				return
+			}
+		}
+	}
+}
@@ -0,0 +1,191 @@
+package auth
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+	"github.com/systemli/ticker/internal/storage"
+)
+
+type TokenTestSuite struct {
+	suite.Suite
+}
+
+func (s *TokenTestSuite) SetupTest() {
+	gin.SetMode(gin.TestMode)
+}
+
+func (s *TokenTestSuite) TestTokenMiddleware() {
+	plain, token, err := storage.NewAPIToken(storage.User{ID: 1}, "Sensor", []string{storage.APITokenScopeMessagesWrite}, 0)
+	s.Require().NoError(err)
+	token.ID = 1
+
+	request := func(store *storage.MockStorage, authorization string, singleSignOn ...bool) (*gin.Context, *httptest.ResponseRecorder) {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = httptest.NewRequest(http.MethodGet, "/v1/admin/tickers/1/messages", nil)
+		if authorization != "" {
+			c.Request.Header.Set("Authorization", authorization)
+		}
+
+		TokenMiddleware(store, AuthMiddleware(store, "secret", nil, nil), len(singleSignOn) > 0 && singleSignOn[0])(c)
+
+		return c, w
+	}
+
+	s.Run("when the request has neither token nor jwt", func() {
+		c, w := request(&storage.MockStorage{}, "")
+
+		s.True(c.IsAborted())
+		s.Equal(http.StatusUnauthorized, w.Code)
+	})
+
+	s.Run("when the token is unknown", func() {
+		store := &storage.MockStorage{}
+		store.On("FindAPITokenByHash", storage.HashAPIToken(plain)).Return(storage.APIToken{}, errors.New("not found"))
+
+		c, w := request(store, "Bearer "+plain)
+
+		s.True(c.IsAborted())
+		s.Equal(http.StatusUnauthorized, w.Code)
+	})
+
+	s.Run("when the token is expired", func() {
+		expired := token
+		past := time.Now().Add(-time.Hour)
+		expired.ExpiresAt = &past
+		store := &storage.MockStorage{}
+		store.On("FindAPITokenByHash", storage.HashAPIToken(plain)).Return(expired, nil)
+
+		c, w := request(store, "Bearer "+plain)
+
+		s.True(c.IsAborted())
+		s.Equal(http.StatusUnauthorized, w.Code)
+	})
+
+	s.Run("when the token is valid", func() {
+		store := &storage.MockStorage{}
+		store.On("FindAPITokenByHash", storage.HashAPIToken(plain)).Return(token, nil)
+		store.On("SaveAPITokenLastUsed", mock.MatchedBy(func(t *storage.APIToken) bool { return !t.LastUsed.IsZero() })).Return(nil).Once()
+
+		c, _ := request(store, "Bearer "+plain)
+
+		s.False(c.IsAborted())
+		s.Equal(float64(1), c.MustGet("id"))
+		s.Equal(1, c.MustGet("apiToken").(storage.APIToken).ID)
+		store.AssertExpectations(s.T())
+	})
+
+	s.Run("when the token of a linked user does not expire soon", func() {
+		store := &storage.MockStorage{}
+		store.On("FindAPITokenByHash", storage.HashAPIToken(plain)).Return(token, nil)
+		store.On("FindUserByID", 1).Return(storage.User{ID: 1, OIDCSubject: "subject"}, nil).Once()
+
+		c, w := request(store, "Bearer "+plain, true)
+
+		s.True(c.IsAborted())
+		s.Equal(http.StatusUnauthorized, w.Code)
+		store.AssertExpectations(s.T())
+	})
+
+	s.Run("when the token of a linked user expires soon", func() {
+		soon := token
+		soon.CreatedAt = time.Now()
+		expiresAt := soon.CreatedAt.Add(time.Hour)
+		soon.ExpiresAt = &expiresAt
+		store := &storage.MockStorage{}
+		store.On("FindAPITokenByHash", storage.HashAPIToken(plain)).Return(soon, nil)
+		store.On("SaveAPITokenLastUsed", mock.Anything).Return(nil).Once()
+
+		c, _ := request(store, "Bearer "+plain, true)
+
+		s.False(c.IsAborted())
+		store.AssertNotCalled(s.T(), "FindUserByID", mock.Anything)
+	})
+
+	s.Run("when the token of a local user does not expire soon", func() {
+		store := &storage.MockStorage{}
+		store.On("FindAPITokenByHash", storage.HashAPIToken(plain)).Return(token, nil)
+		store.On("FindUserByID", 1).Return(storage.User{ID: 1}, nil).Once()
+		store.On("SaveAPITokenLastUsed", mock.Anything).Return(nil).Once()
+
+		c, _ := request(store, "Bearer "+plain, true)
+
+		s.False(c.IsAborted())
+		store.AssertExpectations(s.T())
+	})
+
+	s.Run("when the token was used a moment ago", func() {
+		used := token
+		used.LastUsed = time.Now().Add(-time.Second)
+		store := &storage.MockStorage{}
+		store.On("FindAPITokenByHash", storage.HashAPIToken(plain)).Return(used, nil)
+
+		c, _ := request(store, "Bearer "+plain)
+
+		s.False(c.IsAborted())
+		store.AssertNotCalled(s.T(), "SaveAPITokenLastUsed", mock.Anything)
+	})
+}
+
+func (s *TokenTestSuite) TestNeedScope() {
+	token := storage.APIToken{Scopes: []string{storage.APITokenScopeMessagesWrite}, TickerID: 5}
+
+	request := func(token *storage.APIToken, tickerID string) (*gin.Context, *httptest.ResponseRecorder) {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		if token != nil {
+			c.Set("apiToken", *token)
+		}
+		if tickerID != "" {
+			c.AddParam("tickerID", tickerID)
+		}
+
+		NeedScope(storage.APITokenScopeMessagesWrite)(c)
+
+		return c, w
+	}
+
+	s.Run("when the request has the jwt", func() {
+		c, _ := request(nil, "1")
+
+		s.False(c.IsAborted())
+	})
+
+	s.Run("when the token lacks the scope", func() {
+		readOnly := storage.APIToken{Scopes: []string{storage.APITokenScopeMessagesRead}}
+		c, w := request(&readOnly, "5")
+
+		s.True(c.IsAborted())
+		s.Equal(http.StatusForbidden, w.Code)
+	})
+
+	s.Run("when the token is limited to another ticker", func() {
+		c, w := request(&token, "1")
+
+		s.True(c.IsAborted())
+		s.Equal(http.StatusForbidden, w.Code)
+	})
+
+	s.Run("when the token may be used", func() {
+		c, _ := request(&token, "5")
+
+		s.False(c.IsAborted())
+	})
+
+	s.Run("when the route has no ticker", func() {
+		c, _ := request(&token, "")
+
+		s.False(c.IsAborted())
+	})
+}
+
+func TestTokenTestSuite(t *testing.T) {
+	suite.Run(t, new(TokenTestSuite))
+}
@@ -20,7 +20,7 @@ func PrefetchMessage(s storage.Storage) gin.HandlerFunc {
 			return
 		}
 
-		message, err := s.FindMessage(ticker.ID, messageID, storage.WithAttachments())
+		message, err := s.FindMessage(ticker.ID, messageID, storage.WithAttachments(), storage.WithTags())
 		if err != nil {
 			c.JSON(http.StatusNotFound, response.ErrorResponse(response.CodeNotFound, response.MessageNotFound))
 			return
@@ -11,6 +11,8 @@ import (
 	"gorm.io/gorm"
 )
 
+// PrefetchTicker loads the ticker from the url together with the role of the
+// current user in it. Super admins act as owners of every ticker.
 func PrefetchTicker(s storage.Storage, opts ...func(*gorm.DB) *gorm.DB) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		user, _ := helper.Me(c)
@@ -26,7 +28,18 @@ func PrefetchTicker(s storage.Storage, opts ...func(*gorm.DB) *gorm.DB) gin.Hand
 			return
 		}
 
+		role := storage.TickerRoleOwner
+		if !user.IsSuperAdmin {
+			tickerUser, err := s.FindTickerUser(ticker, user)
+			if err != nil {
+				c.JSON(http.StatusNotFound, response.ErrorResponse(response.CodeNotFound, response.TickerNotFound))
+				return
+			}
+			role = tickerUser.Role
+		}
+
 		c.Set("ticker", ticker)
+		c.Set("tickerRole", role)
 	}
 }
 
@@ -47,3 +60,20 @@ func PrefetchTickerFromRequest(s storage.Storage, opts ...func(*gorm.DB) *gorm.D
 		c.Set("ticker", ticker)
 	}
 }
+
+// NeedRole aborts the request when the role of the current user in the ticker
+// is below the required role. It must run after PrefetchTicker.
+func NeedRole(required string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		role, err := helper.TickerRole(c)
+		if err != nil {
+			c.AbortWithStatusJSON(http.StatusNotFound, response.ErrorResponse(response.CodeNotFound, response.TickerNotFound))
+			return
+		}
+
+		if !storage.HasTickerRole(role, required) {
+			c.AbortWithStatusJSON(http.StatusForbidden, response.ErrorResponse(response.CodeInsufficientPermissions, response.InsufficientPermissions))
+			return
+		}
+	}
+}
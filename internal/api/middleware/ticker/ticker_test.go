@@ -45,13 +45,33 @@ func (s *TickerTestSuite) TestPrefetchTicker() {
 		s.Equal(http.StatusNotFound, w.Code)
 	})
 
+	s.Run("when user is no member", func() {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.AddParam("tickerID", "1")
+		c.Set("me", storage.User{ID: 1})
+		store := &storage.MockStorage{}
+		ticker := storage.Ticker{ID: 1}
+		store.On("FindTickerByUserAndID", mock.Anything, mock.Anything, mock.Anything).Return(ticker, nil)
+		store.On("FindTickerUser", ticker, storage.User{ID: 1}).Return(storage.TickerUser{}, errors.New("not found"))
+		mw := PrefetchTicker(store)
+
+		mw(c)
+
+		s.Equal(http.StatusNotFound, w.Code)
+		_, e := c.Get("ticker")
+		s.False(e)
+	})
+
 	s.Run("storage returns ticker", func() {
 		w := httptest.NewRecorder()
 		c, _ := gin.CreateTestContext(w)
 		c.AddParam("tickerID", "1")
+		c.Set("me", storage.User{ID: 1})
 		store := &storage.MockStorage{}
 		ticker := storage.Ticker{ID: 1}
 		store.On("FindTickerByUserAndID", mock.Anything, mock.Anything, mock.Anything).Return(ticker, nil)
+		store.On("FindTickerUser", ticker, storage.User{ID: 1}).Return(storage.TickerUser{TickerID: 1, UserID: 1, Role: storage.TickerRoleViewer}, nil)
 		mw := PrefetchTicker(store)
 
 		mw(c)
@@ -59,6 +79,26 @@ func (s *TickerTestSuite) TestPrefetchTicker() {
 		ti, e := c.Get("ticker")
 		s.True(e)
 		s.Equal(ticker, ti.(storage.Ticker))
+		role, e := c.Get("tickerRole")
+		s.True(e)
+		s.Equal(storage.TickerRoleViewer, role)
+	})
+
+	s.Run("when user is super admin", func() {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.AddParam("tickerID", "1")
+		c.Set("me", storage.User{ID: 1, IsSuperAdmin: true})
+		store := &storage.MockStorage{}
+		store.On("FindTickerByUserAndID", mock.Anything, mock.Anything, mock.Anything).Return(storage.Ticker{ID: 1}, nil)
+		mw := PrefetchTicker(store)
+
+		mw(c)
+
+		role, e := c.Get("tickerRole")
+		s.True(e)
+		s.Equal(storage.TickerRoleOwner, role)
+		store.AssertExpectations(s.T())
 	})
 }
 
@@ -113,6 +153,53 @@ func (s *TickerTestSuite) TestPrefetchTickerFromRequest() {
 	})
 }
 
+func (s *TickerTestSuite) TestNeedRole() {
+	s.Run("when role is missing", func() {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		mw := NeedRole(storage.TickerRoleEditor)
+
+		mw(c)
+
+		s.True(c.IsAborted())
+		s.Equal(http.StatusNotFound, w.Code)
+	})
+
+	s.Run("when role is below the required role", func() {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Set("tickerRole", storage.TickerRoleContributor)
+		mw := NeedRole(storage.TickerRoleEditor)
+
+		mw(c)
+
+		s.True(c.IsAborted())
+		s.Equal(http.StatusForbidden, w.Code)
+	})
+
+	s.Run("when role is the required role", func() {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Set("tickerRole", storage.TickerRoleEditor)
+		mw := NeedRole(storage.TickerRoleEditor)
+
+		mw(c)
+
+		s.False(c.IsAborted())
+	})
+
+	s.Run("when role is above the required role", func() {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Set("tickerRole", storage.TickerRoleOwner)
+		mw := NeedRole(storage.TickerRoleEditor)
+
+		mw(c)
+
+		s.False(c.IsAborted())
+	})
+}
+
 func TestTickerTestSuite(t *testing.T) {
 	suite.Run(t, new(TickerTestSuite))
 }
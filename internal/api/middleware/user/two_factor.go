@@ -0,0 +1,34 @@
+package user
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/systemli/ticker/internal/api/helper"
+	"github.com/systemli/ticker/internal/api/response"
+	"github.com/systemli/ticker/internal/storage"
+)
+
+// NeedTwoFactor stops users without a second factor while the security
+// settings require one. An authenticator app and a passkey both count. With
+// single sign-on, linked users are left to the identity provider, which asks
+// for its own second factor.
+func NeedTwoFactor(store storage.Storage, singleSignOn bool) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		user, err := helper.Me(c)
+		if err != nil {
+			c.AbortWithStatusJSON(http.StatusBadRequest, response.ErrorResponse(response.CodeDefault, response.UserIdentifierMissing))
+			return
+		}
+
+		if user.TOTPEnabled || (singleSignOn && user.OIDCSubject != "") || !store.GetSecuritySettings().RequireTwoFactor {
+			return
+		}
+
+		count, err := store.CountWebAuthnCredentials(user)
+		if err != nil || count == 0 {
+			c.AbortWithStatusJSON(http.StatusForbidden, response.ErrorResponse(response.CodeTwoFactorRequired, response.TwoFactorSetupRequired))
+			return
+		}
+	}
+}
@@ -0,0 +1,115 @@
+package user
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+	"github.com/systemli/ticker/internal/storage"
+)
+
+type TwoFactorTestSuite struct {
+	suite.Suite
+}
+
+func (s *TwoFactorTestSuite) SetupTest() {
+	gin.SetMode(gin.TestMode)
+}
+
+func (s *TwoFactorTestSuite) TestNeedTwoFactor() {
+	s.Run("when user is missing", func() {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		mw := NeedTwoFactor(&storage.MockStorage{}, false)
+
+		mw(c)
+
+		s.Equal(http.StatusBadRequest, w.Code)
+	})
+
+	s.Run("when a second factor is not required", func() {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Set("me", storage.User{})
+		store := &storage.MockStorage{}
+		store.On("GetSecuritySettings").Return(storage.SecuritySettings{})
+		mw := NeedTwoFactor(store, false)
+
+		mw(c)
+
+		s.False(c.IsAborted())
+	})
+
+	s.Run("when user has no second factor", func() {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Set("me", storage.User{})
+		store := &storage.MockStorage{}
+		store.On("GetSecuritySettings").Return(storage.SecuritySettings{RequireTwoFactor: true})
+		store.On("CountWebAuthnCredentials", storage.User{}).Return(int64(0), nil)
+		mw := NeedTwoFactor(store, false)
+
+		mw(c)
+
+		s.Equal(http.StatusForbidden, w.Code)
+	})
+
+	s.Run("when user has a passkey", func() {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Set("me", storage.User{ID: 1})
+		store := &storage.MockStorage{}
+		store.On("GetSecuritySettings").Return(storage.SecuritySettings{RequireTwoFactor: true})
+		store.On("CountWebAuthnCredentials", storage.User{ID: 1}).Return(int64(1), nil)
+		mw := NeedTwoFactor(store, false)
+
+		mw(c)
+
+		s.False(c.IsAborted())
+	})
+
+	s.Run("when user has a second factor", func() {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Set("me", storage.User{TOTPEnabled: true})
+		mw := NeedTwoFactor(&storage.MockStorage{}, false)
+
+		mw(c)
+
+		s.False(c.IsAborted())
+	})
+}
+
+func (s *TwoFactorTestSuite) TestNeedTwoFactorWithSingleSignOn() {
+	s.Run("when user is linked", func() {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Set("me", storage.User{OIDCSubject: "subject"})
+		mw := NeedTwoFactor(&storage.MockStorage{}, true)
+
+		mw(c)
+
+		s.False(c.IsAborted())
+	})
+
+	s.Run("when user is linked but single sign-on is disabled", func() {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Set("me", storage.User{OIDCSubject: "subject"})
+		store := &storage.MockStorage{}
+		store.On("GetSecuritySettings").Return(storage.SecuritySettings{RequireTwoFactor: true})
+		store.On("CountWebAuthnCredentials", mock.Anything).Return(int64(0), nil)
+		mw := NeedTwoFactor(store, false)
+
+		mw(c)
+
+		s.Equal(http.StatusForbidden, w.Code)
+	})
+}
+
+func TestTwoFactorTestSuite(t *testing.T) {
+	suite.Run(t, new(TwoFactorTestSuite))
+}
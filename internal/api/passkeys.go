@@ -0,0 +1,214 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/systemli/ticker/internal/api/helper"
+	"github.com/systemli/ticker/internal/api/response"
+	"github.com/systemli/ticker/internal/passkey"
+	"github.com/systemli/ticker/internal/storage"
+)
+
+type PasskeyOptionsParam struct {
+	Password string `json:"password" binding:"required"`
+}
+
+type PasskeyParam struct {
+	Name string `json:"name" binding:"required"`
+	passkey.Response
+}
+
+type PasskeyDeleteParam struct {
+	Password string `json:"password" binding:"required"`
+}
+
+// WebAuthnLoginParam holds the email and password when the passkey is the
+// second factor. Without them, any passkey of the site can be used.
+type WebAuthnLoginParam struct {
+	Username string `json:"username"`
+	Password string `json:"password"`
+}
+
+// GetPasskeys lists the passkeys of the current user.
+func (h *handler) GetPasskeys(c *gin.Context) {
+	me, err := helper.Me(c)
+	if err != nil {
+		c.JSON(http.StatusForbidden, response.ErrorResponse(response.CodeDefault, response.Unauthorized))
+		return
+	}
+
+	credentials, err := h.storage.FindWebAuthnCredentials(me)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, response.ErrorResponse(response.CodeDefault, response.StorageError))
+		return
+	}
+
+	c.JSON(http.StatusOK, response.SuccessResponse(map[string]interface{}{"passkeys": response.PasskeysResponse(credentials)}))
+}
+
+// PostPasskeyOptions starts adding a passkey to the current user. The options
+// are handed to navigator.credentials.create() in the browser, and its answer
+// is sent to PostPasskey together with the session.
+func (h *handler) PostPasskeyOptions(c *gin.Context) {
+	me, err := helper.Me(c)
+	if err != nil {
+		c.JSON(http.StatusForbidden, response.ErrorResponse(response.CodeDefault, response.Unauthorized))
+		return
+	}
+
+	if h.passkeys == nil {
+		c.JSON(http.StatusBadRequest, response.ErrorResponse(response.CodeDefault, response.PasskeysDisabled))
+		return
+	}
+
+	var body PasskeyOptionsParam
+	if err := c.Bind(&body); err != nil {
+		c.JSON(http.StatusBadRequest, response.ErrorResponse(response.CodeDefault, response.FormError))
+		return
+	}
+
+	if !me.Authenticate(body.Password) {
+		c.JSON(http.StatusBadRequest, response.ErrorResponse(response.CodeDefault, response.PasswordError))
+		return
+	}
+
+	options, session, err := h.passkeys.BeginRegistration(&me)
+	if err != nil {
+		log.WithError(err).Error("failed to begin passkey registration")
+		c.JSON(http.StatusBadRequest, response.ErrorResponse(response.CodeDefault, response.StorageError))
+		return
+	}
+
+	c.JSON(http.StatusOK, response.SuccessResponse(map[string]interface{}{"passkey": map[string]interface{}{"options": options, "session": session}}))
+}
+
+// PostPasskey stores the passkey the browser created for the options of
+// PostPasskeyOptions.
+func (h *handler) PostPasskey(c *gin.Context) {
+	me, err := helper.Me(c)
+	if err != nil {
+		c.JSON(http.StatusForbidden, response.ErrorResponse(response.CodeDefault, response.Unauthorized))
+		return
+	}
+
+	if h.passkeys == nil {
+		c.JSON(http.StatusBadRequest, response.ErrorResponse(response.CodeDefault, response.PasskeysDisabled))
+		return
+	}
+
+	var body PasskeyParam
+	if err := c.Bind(&body); err != nil {
+		c.JSON(http.StatusBadRequest, response.ErrorResponse(response.CodeDefault, response.FormError))
+		return
+	}
+
+	credential, err := h.passkeys.FinishRegistration(me, body.Name, body.Response)
+	if err != nil {
+		log.WithError(err).Debug("failed to register passkey")
+		c.JSON(http.StatusBadRequest, response.ErrorResponse(response.CodeDefault, response.PasskeyInvalid))
+		return
+	}
+
+	c.JSON(http.StatusOK, response.SuccessResponse(map[string]interface{}{"passkey": response.PasskeyResponse(credential)}))
+}
+
+// DeletePasskey removes a passkey of the current user. It takes the password,
+// so a stolen session is not enough.
+func (h *handler) DeletePasskey(c *gin.Context) {
+	me, err := helper.Me(c)
+	if err != nil {
+		c.JSON(http.StatusForbidden, response.ErrorResponse(response.CodeDefault, response.Unauthorized))
+		return
+	}
+
+	passkeyID, err := strconv.Atoi(c.Param("passkeyID"))
+	if err != nil {
+		c.JSON(http.StatusNotFound, response.ErrorResponse(response.CodeNotFound, response.PasskeyNotFound))
+		return
+	}
+
+	var body PasskeyDeleteParam
+	if err := c.Bind(&body); err != nil {
+		c.JSON(http.StatusBadRequest, response.ErrorResponse(response.CodeDefault, response.FormError))
+		return
+	}
+
+	if !me.Authenticate(body.Password) {
+		c.JSON(http.StatusBadRequest, response.ErrorResponse(response.CodeDefault, response.PasswordError))
+		return
+	}
+
+	credential, err := h.storage.FindWebAuthnCredentialByID(me, passkeyID)
+	if err != nil {
+		c.JSON(http.StatusNotFound, response.ErrorResponse(response.CodeNotFound, response.PasskeyNotFound))
+		return
+	}
+
+	if err := h.storage.DeleteWebAuthnCredential(credential); err != nil {
+		c.JSON(http.StatusBadRequest, response.ErrorResponse(response.CodeDefault, response.StorageError))
+		return
+	}
+
+	c.JSON(http.StatusOK, response.SuccessResponse(map[string]interface{}{}))
+}
+
+// DeleteUserPasskeys lets admins remove all passkeys of a user, for users who
+// lost their devices.
+func (h *handler) DeleteUserPasskeys(c *gin.Context) {
+	user, err := helper.User(c)
+	if err != nil {
+		c.JSON(http.StatusNotFound, response.ErrorResponse(response.CodeDefault, response.UserNotFound))
+		return
+	}
+
+	if err := h.storage.DeleteWebAuthnCredentials(user); err != nil {
+		c.JSON(http.StatusBadRequest, response.ErrorResponse(response.CodeDefault, response.StorageError))
+		return
+	}
+
+	c.JSON(http.StatusOK, response.SuccessResponse(map[string]interface{}{"user": response.UserResponse(user)}))
+}
+
+// PostWebAuthnLogin returns the options for navigator.credentials.get() in
+// the browser. The answer is sent as the passkey of the login, either alone
+// or next to the email and password when the passkey is the second factor.
+func (h *handler) PostWebAuthnLogin(c *gin.Context) {
+	if h.passkeys == nil {
+		c.JSON(http.StatusBadRequest, response.ErrorResponse(response.CodeDefault, response.PasskeysDisabled))
+		return
+	}
+
+	var body WebAuthnLoginParam
+	if c.Request.ContentLength != 0 {
+		if err := c.ShouldBindJSON(&body); err != nil {
+			c.JSON(http.StatusBadRequest, response.ErrorResponse(response.CodeDefault, response.FormError))
+			return
+		}
+	}
+
+	var user *storage.User
+	if body.Username != "" || body.Password != "" {
+		found, err := h.storage.FindUserByEmail(body.Username)
+		if err != nil || !found.Authenticate(body.Password) {
+			c.JSON(http.StatusUnauthorized, response.ErrorResponse(response.CodeBadCredentials, response.PasswordError))
+			return
+		}
+		user = &found
+	}
+
+	options, session, err := h.passkeys.BeginLogin(user)
+	if errors.Is(err, passkey.ErrNoCredentials) {
+		c.JSON(http.StatusBadRequest, response.ErrorResponse(response.CodeDefault, response.PasskeyNotFound))
+		return
+	}
+	if err != nil {
+		log.WithError(err).Error("failed to begin passkey login")
+		c.JSON(http.StatusBadRequest, response.ErrorResponse(response.CodeDefault, response.StorageError))
+		return
+	}
+
+	c.JSON(http.StatusOK, response.SuccessResponse(map[string]interface{}{"passkey": map[string]interface{}{"options": options, "session": session}}))
+}
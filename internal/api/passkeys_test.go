@@ -0,0 +1,353 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-webauthn/webauthn/protocol"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+	"github.com/systemli/ticker/internal/api/response"
+	"github.com/systemli/ticker/internal/config"
+	"github.com/systemli/ticker/internal/passkey"
+	"github.com/systemli/ticker/internal/passkey/passkeytest"
+	"github.com/systemli/ticker/internal/storage"
+	"gorm.io/gorm"
+)
+
+type PasskeysTestSuite struct {
+	w        *httptest.ResponseRecorder
+	ctx      *gin.Context
+	store    *storage.MockStorage
+	user     storage.User
+	sessions map[string]storage.WebAuthnSession
+	suite.Suite
+}
+
+func (s *PasskeysTestSuite) SetupTest() {
+	gin.SetMode(gin.TestMode)
+
+	user, err := storage.NewUser("user@systemli.org", "password")
+	s.Require().NoError(err)
+	user.ID = 1
+	user.WebAuthnHandle = "handle"
+	s.user = user
+}
+
+func (s *PasskeysTestSuite) Run(name string, subtest func()) {
+	s.T().Run(name, func(t *testing.T) {
+		s.w = httptest.NewRecorder()
+		s.ctx, _ = gin.CreateTestContext(s.w)
+		s.store = &storage.MockStorage{}
+		s.sessions = make(map[string]storage.WebAuthnSession)
+
+		subtest()
+	})
+}
+
+func (s *PasskeysTestSuite) TestGetPasskeys() {
+	s.Run("when user is missing", func() {
+		h := s.handler()
+		h.GetPasskeys(s.ctx)
+
+		s.Equal(http.StatusForbidden, s.w.Code)
+	})
+
+	s.Run("when storage returns error", func() {
+		s.ctx.Set("me", s.user)
+		s.store.On("FindWebAuthnCredentials", mock.Anything).Return(nil, errors.New("storage error")).Once()
+
+		h := s.handler()
+		h.GetPasskeys(s.ctx)
+
+		s.Equal(http.StatusBadRequest, s.w.Code)
+		s.store.AssertExpectations(s.T())
+	})
+
+	s.Run("when user has passkeys", func() {
+		s.ctx.Set("me", s.user)
+		s.store.On("FindWebAuthnCredentials", mock.Anything).Return([]storage.WebAuthnCredential{{ID: 1, Name: "Laptop"}}, nil).Once()
+
+		h := s.handler()
+		h.GetPasskeys(s.ctx)
+
+		s.Equal(http.StatusOK, s.w.Code)
+		s.Contains(s.w.Body.String(), `"name":"Laptop"`)
+		s.store.AssertExpectations(s.T())
+	})
+}
+
+func (s *PasskeysTestSuite) TestPostPasskeyOptions() {
+	s.Run("when passkeys are disabled", func() {
+		s.ctx.Set("me", s.user)
+		s.request(http.MethodPost, `{"password":"password"}`)
+
+		h := s.handler()
+		h.passkeys = nil
+		h.PostPasskeyOptions(s.ctx)
+
+		s.Equal(http.StatusBadRequest, s.w.Code)
+		s.Contains(s.w.Body.String(), response.PasskeysDisabled)
+	})
+
+	s.Run("when password is wrong", func() {
+		s.ctx.Set("me", s.user)
+		s.request(http.MethodPost, `{"password":"wrong"}`)
+
+		h := s.handler()
+		h.PostPasskeyOptions(s.ctx)
+
+		s.Equal(http.StatusBadRequest, s.w.Code)
+		s.Contains(s.w.Body.String(), response.PasswordError)
+	})
+
+	s.Run("when a passkey is added", func() {
+		s.ctx.Set("me", s.user)
+		s.request(http.MethodPost, `{"password":"password"}`)
+		s.store.On("FindWebAuthnCredentials", mock.Anything).Return([]storage.WebAuthnCredential{}, nil)
+		s.store.On("SaveWebAuthnCredential", mock.MatchedBy(func(c *storage.WebAuthnCredential) bool {
+			return c.UserID == 1 && c.Name == "Laptop"
+		})).Return(nil).Once()
+
+		h := s.handler()
+		h.PostPasskeyOptions(s.ctx)
+		s.Equal(http.StatusOK, s.w.Code)
+
+		var options struct {
+			Data struct {
+				Passkey struct {
+					Options protocol.CredentialCreation `json:"options"`
+					Session string                      `json:"session"`
+				} `json:"passkey"`
+			} `json:"data"`
+		}
+		s.Require().NoError(json.Unmarshal(s.w.Body.Bytes(), &options))
+
+		authenticator := passkeytest.NewAuthenticator(s.T())
+		body, err := json.Marshal(PasskeyParam{Name: "Laptop", Response: passkey.Response{
+			Session:    options.Data.Passkey.Session,
+			Credential: authenticator.Create(s.T(), &options.Data.Passkey.Options),
+		}})
+		s.Require().NoError(err)
+
+		s.w = httptest.NewRecorder()
+		s.ctx, _ = gin.CreateTestContext(s.w)
+		s.ctx.Set("me", s.user)
+		s.request(http.MethodPost, string(body))
+
+		h.PostPasskey(s.ctx)
+
+		s.Equal(http.StatusOK, s.w.Code)
+		s.Contains(s.w.Body.String(), `"name":"Laptop"`)
+		s.store.AssertExpectations(s.T())
+	})
+}
+
+func (s *PasskeysTestSuite) TestPostPasskey() {
+	s.Run("when form is invalid", func() {
+		s.ctx.Set("me", s.user)
+		s.request(http.MethodPost, `{"session":"token"}`)
+
+		h := s.handler()
+		h.PostPasskey(s.ctx)
+
+		s.Equal(http.StatusBadRequest, s.w.Code)
+		s.Contains(s.w.Body.String(), response.FormError)
+	})
+
+	s.Run("when session is unknown", func() {
+		s.ctx.Set("me", s.user)
+		s.request(http.MethodPost, `{"name":"Laptop","session":"token","credential":{}}`)
+
+		h := s.handler()
+		h.PostPasskey(s.ctx)
+
+		s.Equal(http.StatusBadRequest, s.w.Code)
+		s.Contains(s.w.Body.String(), response.PasskeyInvalid)
+	})
+}
+
+func (s *PasskeysTestSuite) TestDeletePasskey() {
+	s.Run("when password is wrong", func() {
+		s.ctx.Set("me", s.user)
+		s.ctx.AddParam("passkeyID", "1")
+		s.request(http.MethodDelete, `{"password":"wrong"}`)
+
+		h := s.handler()
+		h.DeletePasskey(s.ctx)
+
+		s.Equal(http.StatusBadRequest, s.w.Code)
+		s.Contains(s.w.Body.String(), response.PasswordError)
+	})
+
+	s.Run("when passkey is not found", func() {
+		s.ctx.Set("me", s.user)
+		s.ctx.AddParam("passkeyID", "1")
+		s.request(http.MethodDelete, `{"password":"password"}`)
+		s.store.On("FindWebAuthnCredentialByID", mock.Anything, 1).Return(storage.WebAuthnCredential{}, gorm.ErrRecordNotFound).Once()
+
+		h := s.handler()
+		h.DeletePasskey(s.ctx)
+
+		s.Equal(http.StatusNotFound, s.w.Code)
+		s.store.AssertExpectations(s.T())
+	})
+
+	s.Run("when passkey is deleted", func() {
+		s.ctx.Set("me", s.user)
+		s.ctx.AddParam("passkeyID", "1")
+		s.request(http.MethodDelete, `{"password":"password"}`)
+		s.store.On("FindWebAuthnCredentialByID", mock.Anything, 1).Return(storage.WebAuthnCredential{ID: 1}, nil).Once()
+		s.store.On("DeleteWebAuthnCredential", storage.WebAuthnCredential{ID: 1}).Return(nil).Once()
+
+		h := s.handler()
+		h.DeletePasskey(s.ctx)
+
+		s.Equal(http.StatusOK, s.w.Code)
+		s.store.AssertExpectations(s.T())
+	})
+}
+
+func (s *PasskeysTestSuite) TestDeleteUserPasskeys() {
+	s.Run("when user is missing", func() {
+		h := s.handler()
+		h.DeleteUserPasskeys(s.ctx)
+
+		s.Equal(http.StatusNotFound, s.w.Code)
+	})
+
+	s.Run("when storage returns error", func() {
+		s.ctx.Set("user", s.user)
+		s.store.On("DeleteWebAuthnCredentials", mock.Anything).Return(errors.New("storage error")).Once()
+
+		h := s.handler()
+		h.DeleteUserPasskeys(s.ctx)
+
+		s.Equal(http.StatusBadRequest, s.w.Code)
+		s.store.AssertExpectations(s.T())
+	})
+
+	s.Run("when the passkeys are removed", func() {
+		s.ctx.Set("user", s.user)
+		s.store.On("DeleteWebAuthnCredentials", mock.Anything).Return(nil).Once()
+
+		h := s.handler()
+		h.DeleteUserPasskeys(s.ctx)
+
+		s.Equal(http.StatusOK, s.w.Code)
+		s.store.AssertExpectations(s.T())
+	})
+}
+
+func (s *PasskeysTestSuite) TestPostWebAuthnLogin() {
+	s.Run("when passkeys are disabled", func() {
+		s.request(http.MethodPost, ``)
+
+		h := s.handler()
+		h.passkeys = nil
+		h.PostWebAuthnLogin(s.ctx)
+
+		s.Equal(http.StatusBadRequest, s.w.Code)
+		s.Contains(s.w.Body.String(), response.PasskeysDisabled)
+	})
+
+	s.Run("when any passkey can be used", func() {
+		s.request(http.MethodPost, ``)
+
+		h := s.handler()
+		h.PostWebAuthnLogin(s.ctx)
+
+		s.Equal(http.StatusOK, s.w.Code)
+		s.Contains(s.w.Body.String(), `"userVerification":"required"`)
+		s.Len(s.sessions, 1)
+	})
+
+	s.Run("when password is wrong", func() {
+		s.request(http.MethodPost, `{"username":"user@systemli.org","password":"wrong"}`)
+		s.store.On("FindUserByEmail", "user@systemli.org").Return(s.user, nil).Once()
+
+		h := s.handler()
+		h.PostWebAuthnLogin(s.ctx)
+
+		s.Equal(http.StatusUnauthorized, s.w.Code)
+		s.Empty(s.sessions)
+		s.store.AssertExpectations(s.T())
+	})
+
+	s.Run("when user has no passkeys", func() {
+		s.request(http.MethodPost, `{"username":"user@systemli.org","password":"password"}`)
+		s.store.On("FindUserByEmail", "user@systemli.org").Return(s.user, nil).Once()
+		s.store.On("FindWebAuthnCredentials", mock.Anything).Return([]storage.WebAuthnCredential{}, nil).Once()
+
+		h := s.handler()
+		h.PostWebAuthnLogin(s.ctx)
+
+		s.Equal(http.StatusBadRequest, s.w.Code)
+		s.Contains(s.w.Body.String(), response.PasskeyNotFound)
+		s.store.AssertExpectations(s.T())
+	})
+
+	s.Run("when the passkey is the second factor", func() {
+		s.request(http.MethodPost, `{"username":"user@systemli.org","password":"password"}`)
+		s.store.On("FindUserByEmail", "user@systemli.org").Return(s.user, nil).Once()
+		s.store.On("FindWebAuthnCredentials", mock.Anything).Return([]storage.WebAuthnCredential{{ID: 1, CredentialID: []byte("id")}}, nil).Once()
+
+		h := s.handler()
+		h.PostWebAuthnLogin(s.ctx)
+
+		s.Equal(http.StatusOK, s.w.Code)
+		s.Contains(s.w.Body.String(), `"allowCredentials":[{"type":"public-key","id":"aWQ"}]`)
+		s.Equal(1, s.sessions[s.session()].UserID)
+		s.store.AssertExpectations(s.T())
+	})
+}
+
+func (s *PasskeysTestSuite) request(method, body string) {
+	s.ctx.Request = httptest.NewRequest(method, "/v1/admin/users/me/passkeys", strings.NewReader(body))
+	s.ctx.Request.Header.Add("Content-Type", "application/json")
+}
+
+// session returns the token of the only session started.
+func (s *PasskeysTestSuite) session() string {
+	for token := range s.sessions {
+		return token
+	}
+
+	return ""
+}
+
+// handler returns a handler with passkeys, whose sessions are kept in the
+// suite.
+func (s *PasskeysTestSuite) handler() handler {
+	s.store.On("SaveWebAuthnSession", mock.Anything).Run(func(args mock.Arguments) {
+		session := args.Get(0).(*storage.WebAuthnSession)
+		s.sessions[session.Token] = *session
+	}).Return(nil).Maybe()
+	s.store.On("TakeWebAuthnSession", mock.Anything).Return(func(token string) (storage.WebAuthnSession, error) {
+		session, ok := s.sessions[token]
+		if !ok {
+			return storage.WebAuthnSession{}, gorm.ErrRecordNotFound
+		}
+		delete(s.sessions, token)
+
+		return session, nil
+	}).Maybe()
+
+	passkeys, err := passkey.New(config.WebAuthn{RPID: passkeytest.RPID, Origins: []string{passkeytest.Origin}}, s.store)
+	s.Require().NoError(err)
+
+	return handler{
+		storage:  s.store,
+		config:   config.LoadConfig(""),
+		passkeys: passkeys,
+	}
+}
+
+func TestPasskeysTestSuite(t *testing.T) {
+	suite.Run(t, new(PasskeysTestSuite))
+}
@@ -78,6 +78,7 @@ type Engine struct {
 //     volume and latency requirements.
 //   - TickerID: The ID of the ticker this Client is subscribed to.
 //   - Origin: The origin of the WebSocket connection.
+//   - Tag: If set, new messages are only sent when they carry this tag.
 //   - closed: A flag indicating whether the Client has been closed.
 //   - mu: A mutex to protect concurrent access to the Client's fields.
 //   - unregisterOnce: Ensures unregistration happens only once.
@@ -87,6 +88,7 @@ type Client struct {
 	Send           chan Message
 	TickerID       int
 	Origin         string
+	Tag            string
 	closed         bool
 	mu             sync.Mutex
 	unregisterOnce sync.Once
@@ -98,18 +100,25 @@ type Client struct {
 // - Type: A string indicating the type of message. Expected values include:
 //   - "message_deleted": Indicates that a message was deleted.
 //   - "message_created": Indicates that a new message was created.
+//   - "message_updated": Indicates that the text of a message was edited.
+//   - "message_pinned", "message_unpinned": Indicates that a message was pinned
+//     to or unpinned from the top of the timeline.
 //     Additional types may be added as needed.
 //   - TickerID: An integer representing the ID of the ticker associated with the message.
 //   - Data: A flexible field of type `any` that contains additional data related to the message.
 //   - Origin: A string representing the origin of the message, used for logging and metrics.
+//   - Tags: The tags of a new or edited message, used to filter the clients subscribed to a tag.
+//     Messages without tags are sent to all clients.
 //     The structure of this data depends on the `Type` field. For example:
 //   - For "message_created", `Data` might include the content of the new message.
+//   - For "message_updated", `Data` might include the content of the edited message.
 //   - For "message_deleted", `Data` might include the ID of the deleted message.
 type Message struct {
-	Type     string `json:"type"`
-	TickerID int    `json:"tickerId"`
-	Data     any    `json:"data"`
-	Origin   string `json:"-"` // The origin of the message, used for logging and metrics
+	Type     string   `json:"type"`
+	TickerID int      `json:"tickerId"`
+	Data     any      `json:"data"`
+	Origin   string   `json:"-"` // The origin of the message, used for logging and metrics
+	Tags     []string `json:"-"`
 }
 
 // New creates a new realtime messaging engine.
@@ -387,6 +396,10 @@ func (e *Engine) broadcastMessage(message Message) {
 		droppedCount := 0
 
 		for client := range clients {
+			if !client.wantsMessage(message) {
+				continue
+			}
+
 			select {
 			case client.Send <- message:
 				sentCount++
@@ -504,3 +517,20 @@ func (c *Client) ReadPump() {
 		}
 	}
 }
+
+// wantsMessage reports whether the message should be sent to the client. Only
+// messages with tags are filtered, so clients subscribed to a tag still learn
+// about edits and deletions.
+func (c *Client) wantsMessage(message Message) bool {
+	if c.Tag == "" || message.Tags == nil {
+		return true
+	}
+
+	for _, tag := range message.Tags {
+		if tag == c.Tag {
+			return true
+		}
+	}
+
+	return false
+}
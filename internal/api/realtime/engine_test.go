@@ -158,6 +158,23 @@ func (s *EngineTestSuite) TestBroadcast() {
 	})
 }
 
+func (s *EngineTestSuite) TestBroadcastWithTag() {
+	engine := New()
+	all := &Client{Engine: engine, Send: make(chan Message, 4), TickerID: 1}
+	police := &Client{Engine: engine, Send: make(chan Message, 4), TickerID: 1, Tag: "police"}
+	engine.registerClient(all)
+	engine.registerClient(police)
+
+	engine.broadcastMessage(Message{Type: "message_created", TickerID: 1, Tags: []string{"traffic"}})
+	engine.broadcastMessage(Message{Type: "message_created", TickerID: 1, Tags: []string{"police", "traffic"}})
+	engine.broadcastMessage(Message{Type: "message_deleted", TickerID: 1})
+
+	s.Len(all.Send, 3)
+	s.Len(police.Send, 2)
+	s.Equal("message_created", (<-police.Send).Type)
+	s.Equal("message_deleted", (<-police.Send).Type)
+}
+
 func (s *EngineTestSuite) TestClientManagement() {
 	s.Run("register and unregister clients", func() {
 		engine := New()
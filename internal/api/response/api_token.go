@@ -0,0 +1,38 @@
+package response
+
+import (
+	"time"
+
+	"github.com/systemli/ticker/internal/storage"
+)
+
+type APIToken struct {
+	ID        int        `json:"id"`
+	CreatedAt time.Time  `json:"createdAt"`
+	LastUsed  time.Time  `json:"lastUsed"`
+	ExpiresAt *time.Time `json:"expiresAt"`
+	Name      string     `json:"name"`
+	Scopes    []string   `json:"scopes"`
+	TickerID  int        `json:"tickerID"`
+}
+
+func APITokenResponse(token storage.APIToken) APIToken {
+	return APIToken{
+		ID:        token.ID,
+		CreatedAt: token.CreatedAt,
+		LastUsed:  token.LastUsed,
+		ExpiresAt: token.ExpiresAt,
+		Name:      token.Name,
+		Scopes:    token.Scopes,
+		TickerID:  token.TickerID,
+	}
+}
+
+func APITokensResponse(tokens []storage.APIToken) []APIToken {
+	t := make([]APIToken, 0)
+	for _, token := range tokens {
+		t = append(t, APITokenResponse(token))
+	}
+
+	return t
+}
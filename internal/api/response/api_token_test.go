@@ -0,0 +1,24 @@
+package response
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+	"github.com/systemli/ticker/internal/storage"
+)
+
+type APITokenResponseTestSuite struct {
+	suite.Suite
+}
+
+func (s *APITokenResponseTestSuite) TestAPITokensResponse() {
+	token := storage.APIToken{ID: 1, UserID: 2, Name: "Sensor", Hash: "hash", Scopes: []string{storage.APITokenScopeMessagesWrite}, TickerID: 5}
+
+	response := APITokensResponse([]storage.APIToken{token})
+
+	s.Equal([]APIToken{{ID: 1, Name: "Sensor", Scopes: []string{storage.APITokenScopeMessagesWrite}, TickerID: 5}}, response)
+}
+
+func TestAPITokenResponseTestSuite(t *testing.T) {
+	suite.Run(t, new(APITokenResponseTestSuite))
+}
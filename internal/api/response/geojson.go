@@ -0,0 +1,51 @@
+package response
+
+import (
+	"time"
+
+	"github.com/systemli/ticker/internal/storage"
+)
+
+type FeatureCollection struct {
+	Type     string    `json:"type"`
+	Features []Feature `json:"features"`
+}
+
+type Feature struct {
+	Type       string            `json:"type"`
+	ID         int               `json:"id"`
+	Geometry   *storage.Geometry `json:"geometry"`
+	Properties FeatureProperties `json:"properties"`
+}
+
+type FeatureProperties struct {
+	CreatedAt time.Time `json:"createdAt"`
+	Text      string    `json:"text"`
+	Pinned    bool      `json:"pinned,omitempty"`
+	Tags      []string  `json:"tags,omitempty"`
+}
+
+// GeoJSONResponse returns the messages with a geometry as a GeoJSON
+// FeatureCollection, one feature per message.
+func GeoJSONResponse(messages []storage.Message) FeatureCollection {
+	features := make([]Feature, 0)
+	for _, message := range messages {
+		if message.Geometry == nil {
+			continue
+		}
+
+		features = append(features, Feature{
+			Type:     "Feature",
+			ID:       message.ID,
+			Geometry: message.Geometry,
+			Properties: FeatureProperties{
+				CreatedAt: message.CreatedAt,
+				Text:      message.Text,
+				Pinned:    message.Pinned,
+				Tags:      tagNames(message),
+			},
+		})
+	}
+
+	return FeatureCollection{Type: "FeatureCollection", Features: features}
+}
@@ -9,12 +9,40 @@ import (
 type Message struct {
 	ID          int                 `json:"id"`
 	CreatedAt   time.Time           `json:"createdAt"`
+	UpdatedAt   time.Time           `json:"updatedAt"`
+	PublishAt   *time.Time          `json:"publishAt,omitempty"`
+	Draft       bool                `json:"draft,omitempty"`
+	Pinned      bool                `json:"pinned,omitempty"`
+	Review      *MessageReview      `json:"review,omitempty"`
 	Text        string              `json:"text"`
 	Ticker      int                 `json:"ticker"`
 	TelegramURL string              `json:"telegramUrl,omitempty"`
 	MastodonURL string              `json:"mastodonUrl,omitempty"`
 	BlueskyURL  string              `json:"blueskyUrl,omitempty"`
+	MatrixURL   string              `json:"matrixUrl,omitempty"`
 	Attachments []MessageAttachment `json:"attachments"`
+	Tags        []string            `json:"tags,omitempty"`
+	Geometry    *storage.Geometry   `json:"geometry,omitempty"`
+	Deliveries  []MessageDelivery   `json:"deliveries,omitempty"`
+	Bridges     []string            `json:"bridges"`
+}
+
+// MessageDelivery is the state of the delivery of a message to a bridge.
+type MessageDelivery struct {
+	Bridge    string    `json:"bridge"`
+	Action    string    `json:"action"`
+	Status    string    `json:"status"`
+	Attempts  int       `json:"attempts"`
+	Error     string    `json:"error,omitempty"`
+	UpdatedAt time.Time `json:"updatedAt"`
+}
+
+// MessageReview is the outcome of the editorial review of a message from a
+// contributor. It is left out once the message is approved, so review comments
+// never reach the readers.
+type MessageReview struct {
+	Status  string `json:"status"`
+	Comment string `json:"comment,omitempty"`
 }
 
 type MessageAttachment struct {
@@ -22,25 +50,103 @@ type MessageAttachment struct {
 	ContentType string `json:"contentType"`
 }
 
-func MessageResponse(message storage.Message) Message {
-	var attachments []MessageAttachment
+type MessageTag struct {
+	Name  string `json:"name"`
+	Count int    `json:"count"`
+}
+
+type MessageRevision struct {
+	ID          int                 `json:"id"`
+	CreatedAt   time.Time           `json:"createdAt"`
+	Action      string              `json:"action"`
+	Text        string              `json:"text"`
+	Attachments []MessageAttachment `json:"attachments"`
+	User        MessageRevisionUser `json:"user"`
+}
+
+type MessageRevisionUser struct {
+	ID    int    `json:"id"`
+	Email string `json:"email"`
+}
 
-	for _, attachment := range message.Attachments {
-		attachments = append(attachments, MessageAttachment{URL: storage.MediaURL(attachment.FileName()), ContentType: attachment.ContentType})
+func MessageResponse(message storage.Message) Message {
+	var review *MessageReview
+	if message.Review != "" && message.Review != storage.ReviewApproved {
+		review = &MessageReview{Status: message.Review, Comment: message.ReviewComment}
 	}
 
 	return Message{
 		ID:          message.ID,
 		CreatedAt:   message.CreatedAt,
+		UpdatedAt:   message.UpdatedAt,
+		PublishAt:   message.PublishAt,
+		Draft:       message.Draft,
+		Pinned:      message.Pinned,
+		Review:      review,
 		Text:        message.Text,
 		Ticker:      message.TickerID,
 		TelegramURL: message.TelegramURL(),
 		MastodonURL: message.MastodonURL(),
 		BlueskyURL:  message.BlueskyURL(),
-		Attachments: attachments,
+		MatrixURL:   message.MatrixURL(),
+		Attachments: MessageAttachmentsResponse(message.Attachments),
+		Tags:        tagNames(message),
+		Geometry:    message.Geometry,
+		Deliveries:  MessageDeliveriesResponse(message.Deliveries),
+		Bridges:     message.Bridges,
 	}
 }
 
+func MessageDeliveriesResponse(jobs []storage.OutboxJob) []MessageDelivery {
+	var d []MessageDelivery
+
+	for _, job := range jobs {
+		d = append(d, MessageDelivery{
+			Bridge:    job.Bridge,
+			Action:    deliveryAction(job),
+			Status:    job.Status,
+			Attempts:  job.Attempts,
+			Error:     job.LastError,
+			UpdatedAt: job.UpdatedAt,
+		})
+	}
+
+	return d
+}
+
+// deliveryAction returns the action of the job, with sends from before edits
+// were queued named as well.
+func deliveryAction(job storage.OutboxJob) string {
+	if job.IsEdit() {
+		return storage.OutboxEdit
+	}
+	if job.IsDelete() {
+		return storage.OutboxDelete
+	}
+
+	return storage.OutboxSend
+}
+
+// tagNames returns the names of the tags, or nil for a message without tags
+// so they are left out of the JSON.
+func tagNames(message storage.Message) []string {
+	if len(message.Tags) == 0 {
+		return nil
+	}
+
+	return message.TagNames()
+}
+
+func MessageAttachmentsResponse(attachments []storage.Attachment) []MessageAttachment {
+	var a []MessageAttachment
+
+	for _, attachment := range attachments {
+		a = append(a, MessageAttachment{URL: storage.MediaURL(attachment.FileName()), ContentType: attachment.ContentType})
+	}
+
+	return a
+}
+
 func MessagesResponse(messages []storage.Message) []Message {
 	msgs := make([]Message, 0)
 	for _, message := range messages {
@@ -48,3 +154,38 @@ func MessagesResponse(messages []storage.Message) []Message {
 	}
 	return msgs
 }
+
+func MessageTagsResponse(tags []storage.TagCount) []MessageTag {
+	t := make([]MessageTag, 0)
+	for _, tag := range tags {
+		t = append(t, MessageTag{Name: tag.Name, Count: tag.Count})
+	}
+	return t
+}
+
+// MessageRevisionsResponse serializes the revisions of a message. The users
+// are used to resolve the author of each revision; revisions of users which
+// no longer exist only carry the user ID.
+func MessageRevisionsResponse(revisions []storage.MessageRevision, users []storage.User) []MessageRevision {
+	emails := make(map[int]string, len(users))
+	for _, user := range users {
+		emails[user.ID] = user.Email
+	}
+
+	r := make([]MessageRevision, 0)
+	for _, revision := range revisions {
+		r = append(r, MessageRevision{
+			ID:          revision.ID,
+			CreatedAt:   revision.CreatedAt,
+			Action:      revision.Action,
+			Text:        revision.Text,
+			Attachments: MessageAttachmentsResponse(revision.Attachments),
+			User: MessageRevisionUser{
+				ID:    revision.UserID,
+				Email: emails[revision.UserID],
+			},
+		})
+	}
+
+	return r
+}
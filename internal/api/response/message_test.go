@@ -28,6 +28,41 @@ func (s *MessagesResponseTestSuite) TestMessagesResponse() {
 	s.Equal("/api/media/uuid.jpg", attachments[0].URL)
 }
 
+func (s *MessagesResponseTestSuite) TestMessageRevisionsResponse() {
+	revisions := []storage.MessageRevision{
+		{ID: 1, UserID: 1, Action: storage.RevisionCreated, Text: "first", Attachments: []storage.Attachment{{UUID: "uuid", Extension: "jpg", ContentType: "image/jpg"}}},
+		{ID: 2, UserID: 2, Text: "second"},
+	}
+	users := []storage.User{{ID: 1, Email: "louis@systemli.org"}}
+
+	response := MessageRevisionsResponse(revisions, users)
+
+	s.Len(response, 2)
+	s.Equal("first", response[0].Text)
+	s.Equal(storage.RevisionCreated, response[0].Action)
+	s.Equal("louis@systemli.org", response[0].User.Email)
+	s.Equal("/api/media/uuid.jpg", response[0].Attachments[0].URL)
+	s.Equal(2, response[1].User.ID)
+	s.Empty(response[1].User.Email)
+}
+
+func (s *MessagesResponseTestSuite) TestMessageDeliveriesResponse() {
+	message := storage.Message{ID: 1}
+	jobs := []storage.OutboxJob{storage.NewOutboxJob(message, "mastodon"), storage.NewOutboxEditJob(message, "mastodon"), {Bridge: "telegram"}}
+
+	response := MessageDeliveriesResponse(jobs)
+
+	s.Len(response, 3)
+	s.Equal(storage.OutboxSend, response[0].Action)
+	s.Equal(storage.OutboxEdit, response[1].Action)
+	s.Equal(storage.OutboxSend, response[2].Action)
+}
+
+func (s *MessagesResponseTestSuite) TestMessageTagsResponse() {
+	s.Empty(MessageTagsResponse([]storage.TagCount{}))
+	s.Equal([]MessageTag{{Name: "police", Count: 2}}, MessageTagsResponse([]storage.TagCount{{Name: "police", Count: 2}}))
+}
+
 func TestMessagesResponseTestSuite(t *testing.T) {
 	suite.Run(t, new(MessagesResponseTestSuite))
 }
@@ -0,0 +1,36 @@
+package response
+
+import (
+	"time"
+
+	"github.com/systemli/ticker/internal/storage"
+)
+
+type Passkey struct {
+	ID        int       `json:"id"`
+	CreatedAt time.Time `json:"createdAt"`
+	LastUsed  time.Time `json:"lastUsed"`
+	Name      string    `json:"name"`
+	// Synced tells whether the passkey is backed up to other devices of the
+	// user, as opposed to a security key.
+	Synced bool `json:"synced"`
+}
+
+func PasskeyResponse(credential storage.WebAuthnCredential) Passkey {
+	return Passkey{
+		ID:        credential.ID,
+		CreatedAt: credential.CreatedAt,
+		LastUsed:  credential.LastUsed,
+		Name:      credential.Name,
+		Synced:    credential.BackupState,
+	}
+}
+
+func PasskeysResponse(credentials []storage.WebAuthnCredential) []Passkey {
+	p := make([]Passkey, 0)
+	for _, credential := range credentials {
+		p = append(p, PasskeyResponse(credential))
+	}
+
+	return p
+}
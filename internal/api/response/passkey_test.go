@@ -0,0 +1,24 @@
+package response
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+	"github.com/systemli/ticker/internal/storage"
+)
+
+type PasskeyResponseTestSuite struct {
+	suite.Suite
+}
+
+func (s *PasskeyResponseTestSuite) TestPasskeysResponse() {
+	credential := storage.WebAuthnCredential{ID: 1, Name: "Laptop", PublicKey: []byte("key"), BackupState: true}
+
+	response := PasskeysResponse([]storage.WebAuthnCredential{credential})
+
+	s.Equal([]Passkey{{ID: 1, Name: "Laptop", Synced: true}}, response)
+}
+
+func TestPasskeyResponseTestSuite(t *testing.T) {
+	suite.Run(t, new(PasskeyResponseTestSuite))
+}
@@ -5,28 +5,66 @@ const (
 	CodeNotFound                ErrorCode = 1001
 	CodeBadCredentials          ErrorCode = 1002
 	CodeInsufficientPermissions ErrorCode = 1003
+	CodeTwoFactorRequired       ErrorCode = 1004
+	CodeSingleSignOnRequired    ErrorCode = 1005
 
-	InsufficientPermissions ErrorMessage = "insufficient permissions"
-	Unauthorized            ErrorMessage = "unauthorized"
-	UserIdentifierMissing   ErrorMessage = "user identifier not found"
-	TickerIdentifierMissing ErrorMessage = "ticker identifier not found"
-	MessageNotFound         ErrorMessage = "message not found"
-	FilesIdentifierMissing  ErrorMessage = "files identifier not found"
-	TooMuchFiles            ErrorMessage = "upload limit exceeded"
-	UserNotFound            ErrorMessage = "user not found"
-	TickerNotFound          ErrorMessage = "ticker not found"
-	SettingNotFound         ErrorMessage = "setting not found"
-	MessageFetchError       ErrorMessage = "messages couldn't fetched"
-	FormError               ErrorMessage = "invalid form values"
-	StorageError            ErrorMessage = "failed to save"
-	UploadsNotFound         ErrorMessage = "uploads not found"
-	BridgeError             ErrorMessage = "unable to update ticker in bridges"
-	MastodonError           ErrorMessage = "unable to connect to mastodon"
-	BlueskyError            ErrorMessage = "unable to connect to bluesky"
-	TelegramError           ErrorMessage = "unable to connect to telegram"
-	SignalGroupError        ErrorMessage = "unable to connect to signal"
-	SignalGroupDeleteError  ErrorMessage = "unable to delete signal group"
-	PasswordError           ErrorMessage = "could not authenticate password"
+	InsufficientPermissions    ErrorMessage = "insufficient permissions"
+	Unauthorized               ErrorMessage = "unauthorized"
+	UserIdentifierMissing      ErrorMessage = "user identifier not found"
+	TickerIdentifierMissing    ErrorMessage = "ticker identifier not found"
+	MessageNotFound            ErrorMessage = "message not found"
+	MessageNotDraft            ErrorMessage = "message is not a draft"
+	MessagePendingReview       ErrorMessage = "message is pending review"
+	MessageNotPendingReview    ErrorMessage = "message is not pending review"
+	MessageNotRejected         ErrorMessage = "message is not rejected"
+	MessageNotPublished        ErrorMessage = "message is not published"
+	SearchQueryMissing         ErrorMessage = "search query is missing"
+	GeometryInvalid            ErrorMessage = "invalid geometry"
+	BridgeUnknown              ErrorMessage = "unknown bridge"
+	WebhookInvalid             ErrorMessage = "invalid webhook"
+	SignalSenderInvalid        ErrorMessage = "invalid signal sender"
+	ActivityPubInvalid         ErrorMessage = "invalid activitypub settings"
+	MatrixInvalid              ErrorMessage = "invalid matrix homeserver"
+	WebPushDisabled            ErrorMessage = "push notifications are disabled"
+	WebPushSubscriptionInvalid ErrorMessage = "invalid push subscription"
+	WebPushSubscriptionLimit   ErrorMessage = "too many push subscriptions"
+	EmailDisabled              ErrorMessage = "email subscriptions are disabled"
+	EmailInvalid               ErrorMessage = "invalid email address"
+	EmailError                 ErrorMessage = "unable to send email"
+	TwoFactorRequired          ErrorMessage = "second factor required"
+	TwoFactorSetupRequired     ErrorMessage = "second factor must be set up"
+	TwoFactorInvalid           ErrorMessage = "invalid code"
+	TwoFactorLocked            ErrorMessage = "too many wrong codes, try again later"
+	TwoFactorEnabled           ErrorMessage = "second factor is set up already"
+	TwoFactorNotEnabled        ErrorMessage = "second factor is not set up"
+	PasskeysDisabled           ErrorMessage = "passkeys are disabled"
+	PasskeyInvalid             ErrorMessage = "invalid passkey"
+	PasskeyNotFound            ErrorMessage = "passkey not found"
+	SingleSignOnRequired       ErrorMessage = "log in through single sign-on"
+	SingleSignOnDisabled       ErrorMessage = "single sign-on is disabled"
+	SingleSignOnError          ErrorMessage = "unable to connect to the identity provider"
+	APITokenInvalid            ErrorMessage = "invalid scopes, ticker or expiry"
+	APITokenLifetime           ErrorMessage = "linked users need an expiry within 30 days"
+	APITokenNotFound           ErrorMessage = "api token not found"
+	MailboxDisabled            ErrorMessage = "posting by mail is disabled"
+	FilesIdentifierMissing     ErrorMessage = "files identifier not found"
+	TooMuchFiles               ErrorMessage = "upload limit exceeded"
+	UserNotFound               ErrorMessage = "user not found"
+	TickerNotFound             ErrorMessage = "ticker not found"
+	SettingNotFound            ErrorMessage = "setting not found"
+	MessageFetchError          ErrorMessage = "messages couldn't fetched"
+	FormError                  ErrorMessage = "invalid form values"
+	StorageError               ErrorMessage = "failed to save"
+	UploadsNotFound            ErrorMessage = "uploads not found"
+	BridgeError                ErrorMessage = "unable to update ticker in bridges"
+	MastodonError              ErrorMessage = "unable to connect to mastodon"
+	BlueskyError               ErrorMessage = "unable to connect to bluesky"
+	MatrixError                ErrorMessage = "unable to connect to matrix"
+	TelegramError              ErrorMessage = "unable to connect to telegram"
+	TelegramInboundDisabled    ErrorMessage = "posting through telegram is disabled"
+	SignalGroupError           ErrorMessage = "unable to connect to signal"
+	SignalGroupDeleteError     ErrorMessage = "unable to delete signal group"
+	PasswordError              ErrorMessage = "could not authenticate password"
 
 	StatusSuccess Status = `success`
 	StatusError   Status = `error`
@@ -14,6 +14,7 @@ type Setting struct {
 type TelegramSettingsValue struct {
 	Token       string `json:"token"`
 	BotUsername string `json:"botUsername"`
+	Inbound     bool   `json:"inbound"`
 }
 
 func InactiveSettingsResponse(inactiveSettings storage.InactiveSettings) Setting {
@@ -29,6 +30,7 @@ func TelegramSettingsResponse(telegramSettings storage.TelegramSettings) Setting
 		Value: TelegramSettingsValue{
 			Token:       maskToken(telegramSettings.Token),
 			BotUsername: telegramSettings.BotUsername,
+			Inbound:     telegramSettings.Inbound,
 		},
 	}
 }
@@ -40,6 +42,13 @@ func SignalGroupSettingsResponse(signalGroupSettings storage.SignalGroupSettings
 	}
 }
 
+func SecuritySettingsResponse(securitySettings storage.SecuritySettings) Setting {
+	return Setting{
+		Name:  storage.SettingSecurityName,
+		Value: securitySettings,
+	}
+}
+
 // maskToken returns a masked version of the token, showing only the last 4 characters.
 // If the token is empty, it returns an empty string.
 func maskToken(token string) string {
@@ -7,18 +7,25 @@ import (
 )
 
 type Ticker struct {
-	ID          int         `json:"id"`
-	CreatedAt   time.Time   `json:"createdAt"`
-	Title       string      `json:"title"`
-	Description string      `json:"description"`
-	Active      bool        `json:"active"`
-	Information Information `json:"information"`
-	Websites    []Website   `json:"websites"`
-	Telegram    Telegram    `json:"telegram"`
-	Mastodon    Mastodon    `json:"mastodon"`
-	Bluesky     Bluesky     `json:"bluesky"`
-	SignalGroup SignalGroup `json:"signalGroup"`
-	Location    Location    `json:"location"`
+	ID            int            `json:"id"`
+	CreatedAt     time.Time      `json:"createdAt"`
+	Title         string         `json:"title"`
+	Description   string         `json:"description"`
+	Active        bool           `json:"active"`
+	Information   Information    `json:"information"`
+	Websites      []Website      `json:"websites"`
+	Webhooks      []Webhook      `json:"webhooks"`
+	SignalSenders []SignalSender `json:"signalSenders"`
+	Telegram      Telegram       `json:"telegram"`
+	Mastodon      Mastodon       `json:"mastodon"`
+	Bluesky       Bluesky        `json:"bluesky"`
+	SignalGroup   SignalGroup    `json:"signalGroup"`
+	Matrix        Matrix         `json:"matrix"`
+	ActivityPub   ActivityPub    `json:"activityPub"`
+	WebPush       WebPush        `json:"webPush"`
+	Email         Email          `json:"email"`
+	Mailbox       Mailbox        `json:"mailbox"`
+	Location      Location       `json:"location"`
 }
 
 type Information struct {
@@ -40,6 +47,20 @@ type Website struct {
 	Origin    string    `json:"origin"`
 }
 
+// Webhook leaves out the secret, it is only ever written.
+type Webhook struct {
+	ID        int       `json:"id"`
+	CreatedAt time.Time `json:"createdAt"`
+	URL       string    `json:"url"`
+}
+
+type SignalSender struct {
+	ID        int       `json:"id"`
+	CreatedAt time.Time `json:"createdAt"`
+	Number    string    `json:"number"`
+	Name      string    `json:"name"`
+}
+
 type Telegram struct {
 	Active      bool   `json:"active"`
 	Connected   bool   `json:"connected"`
@@ -71,6 +92,35 @@ type SignalGroup struct {
 	GroupInviteLink string `json:"groupInviteLink"`
 }
 
+type Matrix struct {
+	Active     bool   `json:"active"`
+	Connected  bool   `json:"connected"`
+	Homeserver string `json:"homeserver"`
+	UserID     string `json:"userID"`
+	RoomID     string `json:"roomID"`
+}
+
+type ActivityPub struct {
+	Active    bool   `json:"active"`
+	Connected bool   `json:"connected"`
+	Username  string `json:"username"`
+	Handle    string `json:"handle"`
+}
+
+type WebPush struct {
+	Active bool `json:"active"`
+}
+
+type Email struct {
+	Active bool `json:"active"`
+	Digest bool `json:"digest"`
+}
+
+type Mailbox struct {
+	Active  bool   `json:"active"`
+	Address string `json:"address"`
+}
+
 type Location struct {
 	Lat float64 `json:"lat"`
 	Lon float64 `json:"lon"`
@@ -86,6 +136,25 @@ func TickerResponse(t storage.Ticker, botUsername string) Ticker {
 		})
 	}
 
+	webhooks := make([]Webhook, 0)
+	for _, webhook := range t.Webhooks {
+		webhooks = append(webhooks, Webhook{
+			ID:        webhook.ID,
+			CreatedAt: webhook.CreatedAt,
+			URL:       webhook.URL,
+		})
+	}
+
+	signalSenders := make([]SignalSender, 0)
+	for _, sender := range t.SignalSenders {
+		signalSenders = append(signalSenders, SignalSender{
+			ID:        sender.ID,
+			CreatedAt: sender.CreatedAt,
+			Number:    sender.Number,
+			Name:      sender.Name,
+		})
+	}
+
 	return Ticker{
 		ID:          t.ID,
 		CreatedAt:   t.CreatedAt,
@@ -104,7 +173,9 @@ func TickerResponse(t storage.Ticker, botUsername string) Ticker {
 			Mastodon:  t.Information.Mastodon,
 			Bluesky:   t.Information.Bluesky,
 		},
-		Websites: websites,
+		Websites:      websites,
+		Webhooks:      webhooks,
+		SignalSenders: signalSenders,
 		Telegram: Telegram{
 			Active:      t.Telegram.Active,
 			Connected:   t.Telegram.Connected(),
@@ -131,6 +202,30 @@ func TickerResponse(t storage.Ticker, botUsername string) Ticker {
 			GroupID:         t.SignalGroup.GroupID,
 			GroupInviteLink: t.SignalGroup.GroupInviteLink,
 		},
+		Matrix: Matrix{
+			Active:     t.Matrix.Active,
+			Connected:  t.Matrix.Connected(),
+			Homeserver: t.Matrix.Homeserver,
+			UserID:     t.Matrix.UserID,
+			RoomID:     t.Matrix.RoomID,
+		},
+		ActivityPub: ActivityPub{
+			Active:    t.ActivityPub.Active,
+			Connected: t.ActivityPub.Connected(),
+			Username:  t.ActivityPub.Username,
+			Handle:    t.ActivityPub.Handle(),
+		},
+		WebPush: WebPush{
+			Active: t.WebPush.Active,
+		},
+		Email: Email{
+			Active: t.Email.Active,
+			Digest: t.Email.Digest,
+		},
+		Mailbox: Mailbox{
+			Active:  t.Mailbox.Active,
+			Address: t.Mailbox.Address,
+		},
 		Location: Location{
 			Lat: t.Location.Lat,
 			Lon: t.Location.Lon,
@@ -9,10 +9,14 @@ import (
 type Timeline []TimelineEntry
 
 type TimelineEntry struct {
-	ID          int          `json:"id"`
-	CreatedAt   time.Time    `json:"createdAt"`
-	Text        string       `json:"text"`
-	Attachments []Attachment `json:"attachments"`
+	ID          int               `json:"id"`
+	CreatedAt   time.Time         `json:"createdAt"`
+	UpdatedAt   time.Time         `json:"updatedAt"`
+	Text        string            `json:"text"`
+	Pinned      bool              `json:"pinned,omitempty"`
+	Attachments []Attachment      `json:"attachments"`
+	Tags        []string          `json:"tags,omitempty"`
+	Geometry    *storage.Geometry `json:"geometry,omitempty"`
 }
 
 type Attachment struct {
@@ -31,8 +35,12 @@ func TimelineResponse(messages []storage.Message) []TimelineEntry {
 		timeline = append(timeline, TimelineEntry{
 			ID:          message.ID,
 			CreatedAt:   message.CreatedAt,
+			UpdatedAt:   message.UpdatedAt,
 			Text:        message.Text,
+			Pinned:      message.Pinned,
 			Attachments: attachments,
+			Tags:        tagNames(message),
+			Geometry:    message.Geometry,
 		})
 
 	}
@@ -25,6 +25,22 @@ func (s *TimelineTestSuite) TestTimelineResponse() {
 	s.Equal("/api/media/uuid.jpg", attachments[0].URL)
 }
 
+func (s *TimelineTestSuite) TestGeoJSONResponse() {
+	geometry := &storage.Geometry{Type: storage.GeometryPoint, Coordinates: []byte(`[13.4,52.5]`)}
+	messages := []storage.Message{
+		{ID: 1, Text: "Kettle", Geometry: geometry, Tags: []storage.MessageTag{{Name: "police"}}},
+		{ID: 2, Text: "No location"},
+	}
+
+	response := GeoJSONResponse(messages)
+
+	s.Equal("FeatureCollection", response.Type)
+	s.Len(response.Features, 1)
+	s.Equal(1, response.Features[0].ID)
+	s.Equal(geometry, response.Features[0].Geometry)
+	s.Equal([]string{"police"}, response.Features[0].Properties.Tags)
+}
+
 func TestTimelineTestSuite(t *testing.T) {
 	suite.Run(t, new(TimelineTestSuite))
 }
@@ -14,6 +14,14 @@ type User struct {
 	Role         string       `json:"role"`
 	Tickers      []UserTicker `json:"tickers"`
 	IsSuperAdmin bool         `json:"isSuperAdmin"`
+	// TelegramLinked tells whether the user posts through the Telegram bot.
+	TelegramLinked bool `json:"telegramLinked"`
+	// TwoFactorEnabled tells whether the login asks for a code of the
+	// authenticator app.
+	TwoFactorEnabled bool `json:"twoFactorEnabled"`
+	// OIDCLinked tells whether the user is linked to an account at the
+	// identity provider of the single sign-on.
+	OIDCLinked bool `json:"oidcLinked"`
 }
 
 type UserTicker struct {
@@ -24,12 +32,15 @@ type UserTicker struct {
 
 func UserResponse(user storage.User) User {
 	return User{
-		ID:           user.ID,
-		CreatedAt:    user.CreatedAt,
-		LastLogin:    user.LastLogin,
-		Email:        user.Email,
-		IsSuperAdmin: user.IsSuperAdmin,
-		Tickers:      UserTickersResponse(user.Tickers),
+		ID:               user.ID,
+		CreatedAt:        user.CreatedAt,
+		LastLogin:        user.LastLogin,
+		Email:            user.Email,
+		IsSuperAdmin:     user.IsSuperAdmin,
+		Tickers:          UserTickersResponse(user.Tickers),
+		TelegramLinked:   user.TelegramID != nil,
+		TwoFactorEnabled: user.TOTPEnabled,
+		OIDCLinked:       user.OIDCSubject != "",
 	}
 }
 
@@ -42,6 +53,23 @@ func UsersResponse(users []storage.User) []User {
 	return u
 }
 
+// TickerUsersResponse returns the members of a ticker together with their role.
+func TickerUsersResponse(users []storage.User, tickerUsers []storage.TickerUser) []User {
+	roles := make(map[int]string, len(tickerUsers))
+	for _, tickerUser := range tickerUsers {
+		roles[tickerUser.UserID] = tickerUser.Role
+	}
+
+	u := make([]User, 0)
+	for _, user := range users {
+		r := UserResponse(user)
+		r.Role = roles[user.ID]
+		u = append(u, r)
+	}
+
+	return u
+}
+
 func UserTickersResponse(tickers []storage.Ticker) []UserTicker {
 	t := make([]UserTicker, 0)
 	for _, ticker := range tickers {
@@ -37,6 +37,7 @@ func (s *UsersResponseTestSuite) TestUsersResponse() {
 	s.Equal(users[0].LastLogin, usersResponse[0].LastLogin)
 	s.Equal(users[0].Email, usersResponse[0].Email)
 	s.Equal(users[0].IsSuperAdmin, usersResponse[0].IsSuperAdmin)
+	s.False(usersResponse[0].TelegramLinked)
 	s.Equal(1, len(usersResponse[0].Tickers))
 	s.Equal(users[0].Tickers[0].ID, usersResponse[0].Tickers[0].ID)
 	s.Equal(users[0].Tickers[0].Domain, usersResponse[0].Tickers[0].Domain)
@@ -0,0 +1,189 @@
+package api
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/systemli/ticker/internal/api/helper"
+	"github.com/systemli/ticker/internal/api/response"
+	"github.com/systemli/ticker/internal/storage"
+)
+
+func (h *handler) GetPendingReviewMessages(c *gin.Context) {
+	ticker, err := helper.Ticker(c)
+	if err != nil {
+		c.JSON(http.StatusNotFound, response.ErrorResponse(response.CodeDefault, response.TickerNotFound))
+		return
+	}
+
+	messages, err := h.storage.FindPendingReviewMessagesByTicker(ticker, storage.WithAttachments(), storage.WithTags())
+	if err != nil {
+		c.JSON(http.StatusNotFound, response.ErrorResponse(response.CodeDefault, response.StorageError))
+		return
+	}
+
+	data := map[string]any{"messages": response.MessagesResponse(messages)}
+	c.JSON(http.StatusOK, response.SuccessResponse(data))
+}
+
+// ApproveMessage publishes a message from a contributor.
+func (h *handler) ApproveMessage(c *gin.Context) {
+	ticker, message, ok := h.pendingReviewMessage(c)
+	if !ok {
+		return
+	}
+
+	var body struct {
+		Comment string `json:"comment"`
+	}
+	// The comment is optional, and so is the body.
+	if c.Request.ContentLength != 0 {
+		if err := c.ShouldBindJSON(&body); err != nil {
+			c.JSON(http.StatusBadRequest, response.ErrorResponse(response.CodeDefault, response.FormError))
+			return
+		}
+	}
+
+	message.Review = storage.ReviewApproved
+	message.ReviewComment = body.Comment
+
+	h.publishDraft(c, ticker, message, storage.RevisionApproved)
+}
+
+// RejectMessage sends a message back to the contributor. The message stays a
+// draft and carries the comment, so the contributor can revise it with
+// ResubmitMessage.
+func (h *handler) RejectMessage(c *gin.Context) {
+	_, message, ok := h.pendingReviewMessage(c)
+	if !ok {
+		return
+	}
+
+	var body struct {
+		Comment string `json:"comment" binding:"required"`
+	}
+	if err := c.Bind(&body); err != nil {
+		c.JSON(http.StatusBadRequest, response.ErrorResponse(response.CodeDefault, response.FormError))
+		return
+	}
+
+	message.Review = storage.ReviewRejected
+	message.ReviewComment = body.Comment
+
+	if err := h.storage.SaveMessage(&message); err != nil {
+		c.JSON(http.StatusBadRequest, response.ErrorResponse(response.CodeDefault, response.StorageError))
+		return
+	}
+
+	h.saveMessageRevision(c, message, storage.RevisionRejected)
+
+	c.JSON(http.StatusOK, response.SuccessResponse(map[string]any{"message": response.MessageResponse(message)}))
+}
+
+// ResubmitMessage revises a rejected message and puts it back into the review.
+// Only the user who wrote the message can resubmit it. The comment of the
+// rejection stays until the next review.
+func (h *handler) ResubmitMessage(c *gin.Context) {
+	ticker, err := helper.Ticker(c)
+	if err != nil {
+		c.JSON(http.StatusNotFound, response.ErrorResponse(response.CodeDefault, response.TickerNotFound))
+		return
+	}
+
+	message, err := helper.Message(c)
+	if err != nil {
+		c.JSON(http.StatusNotFound, response.ErrorResponse(response.CodeDefault, response.MessageNotFound))
+		return
+	}
+
+	if message.Review != storage.ReviewRejected {
+		c.JSON(http.StatusBadRequest, response.ErrorResponse(response.CodeDefault, response.MessageNotRejected))
+		return
+	}
+
+	me, err := helper.Me(c)
+	if err != nil {
+		c.JSON(http.StatusForbidden, response.ErrorResponse(response.CodeDefault, response.Unauthorized))
+		return
+	}
+
+	// Messages do not know their author, the first revision does.
+	revisions, err := h.storage.FindMessageRevisions(ticker.ID, message.ID)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, response.ErrorResponse(response.CodeDefault, response.StorageError))
+		return
+	}
+	if len(revisions) == 0 || revisions[0].Action != storage.RevisionCreated || revisions[0].UserID != me.ID {
+		c.JSON(http.StatusForbidden, response.ErrorResponse(response.CodeInsufficientPermissions, response.InsufficientPermissions))
+		return
+	}
+
+	var body struct {
+		Text string `json:"text" binding:"required"`
+		// Tags replace the tags of the message. Without them, the tags
+		// which were set explicitly are kept.
+		Tags     *[]string     `json:"tags"`
+		Geometry geometryParam `json:"geometry"`
+	}
+	if err := c.Bind(&body); err != nil {
+		c.JSON(http.StatusBadRequest, response.ErrorResponse(response.CodeDefault, response.FormError))
+		return
+	}
+
+	if body.Geometry.Geometry != nil && body.Geometry.Geometry.Validate() != nil {
+		c.JSON(http.StatusBadRequest, response.ErrorResponse(response.CodeDefault, response.GeometryInvalid))
+		return
+	}
+
+	tags := explicitTags(message)
+	if body.Tags != nil {
+		tags = *body.Tags
+	}
+
+	message.Text = body.Text
+	if body.Geometry.Set {
+		message.Geometry = body.Geometry.Geometry
+	}
+	message.Review = storage.ReviewPending
+
+	if err := h.storage.SaveMessage(&message); err != nil {
+		c.JSON(http.StatusBadRequest, response.ErrorResponse(response.CodeDefault, response.StorageError))
+		return
+	}
+
+	h.saveMessageRevision(c, message, storage.RevisionResubmitted)
+	h.saveMessageTags(&message, tags)
+
+	c.JSON(http.StatusOK, response.SuccessResponse(map[string]any{"message": response.MessageResponse(message)}))
+}
+
+// pendingReviewMessage returns the ticker and message of the request, or writes
+// an error response when the message is not waiting for a review.
+func (h *handler) pendingReviewMessage(c *gin.Context) (storage.Ticker, storage.Message, bool) {
+	ticker, err := helper.Ticker(c)
+	if err != nil {
+		c.JSON(http.StatusNotFound, response.ErrorResponse(response.CodeDefault, response.TickerNotFound))
+		return ticker, storage.Message{}, false
+	}
+
+	message, err := helper.Message(c)
+	if err != nil {
+		c.JSON(http.StatusNotFound, response.ErrorResponse(response.CodeDefault, response.MessageNotFound))
+		return ticker, message, false
+	}
+
+	if !message.IsPendingReview() {
+		c.JSON(http.StatusBadRequest, response.ErrorResponse(response.CodeDefault, response.MessageNotPendingReview))
+		return ticker, message, false
+	}
+
+	return ticker, message, true
+}
+
+// isContributor reports whether the current user may only submit messages for
+// review. Without a known role the user is treated as a contributor.
+func (h *handler) isContributor(c *gin.Context) bool {
+	role, _ := helper.TickerRole(c)
+
+	return !storage.HasTickerRole(role, storage.TickerRoleEditor)
+}
@@ -0,0 +1,288 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+	"github.com/systemli/ticker/internal/api/realtime"
+	"github.com/systemli/ticker/internal/api/response"
+	"github.com/systemli/ticker/internal/cache"
+	"github.com/systemli/ticker/internal/config"
+	"github.com/systemli/ticker/internal/storage"
+)
+
+type ReviewsTestSuite struct {
+	w     *httptest.ResponseRecorder
+	ctx   *gin.Context
+	store *storage.MockStorage
+	cfg   config.Config
+	cache *cache.Cache
+	suite.Suite
+}
+
+func (s *ReviewsTestSuite) SetupTest() {
+	gin.SetMode(gin.TestMode)
+}
+
+func (s *ReviewsTestSuite) Run(name string, subtest func()) {
+	s.T().Run(name, func(t *testing.T) {
+		s.w = httptest.NewRecorder()
+		s.ctx, _ = gin.CreateTestContext(s.w)
+		s.store = &storage.MockStorage{}
+		s.cfg = config.LoadConfig("")
+		s.cache = cache.NewCache(time.Minute)
+
+		subtest()
+	})
+}
+
+func (s *ReviewsTestSuite) TestGetPendingReviewMessages() {
+	s.Run("when ticker not found", func() {
+		h := s.handler()
+		h.GetPendingReviewMessages(s.ctx)
+
+		s.Equal(http.StatusNotFound, s.w.Code)
+		s.True(s.store.AssertExpectations(s.T()))
+	})
+
+	s.Run("when storage returns error", func() {
+		ticker := storage.Ticker{ID: 1}
+		s.ctx.Set("ticker", ticker)
+		s.store.On("FindPendingReviewMessagesByTicker", ticker, mock.Anything).Return([]storage.Message{}, errors.New("storage error")).Once()
+		h := s.handler()
+		h.GetPendingReviewMessages(s.ctx)
+
+		s.Equal(http.StatusNotFound, s.w.Code)
+		s.True(s.store.AssertExpectations(s.T()))
+	})
+
+	s.Run("happy path", func() {
+		ticker := storage.Ticker{ID: 1}
+		s.ctx.Set("ticker", ticker)
+		s.store.On("FindPendingReviewMessagesByTicker", ticker, mock.Anything).Return([]storage.Message{{ID: 1, Draft: true, Review: storage.ReviewPending}}, nil).Once()
+		h := s.handler()
+		h.GetPendingReviewMessages(s.ctx)
+
+		s.Equal(http.StatusOK, s.w.Code)
+		s.Contains(s.w.Body.String(), `"status":"pending"`)
+		s.True(s.store.AssertExpectations(s.T()))
+	})
+}
+
+func (s *ReviewsTestSuite) TestApproveMessage() {
+	s.Run("when ticker not found", func() {
+		h := s.handler()
+		h.ApproveMessage(s.ctx)
+
+		s.Equal(http.StatusNotFound, s.w.Code)
+		s.True(s.store.AssertExpectations(s.T()))
+	})
+
+	s.Run("when message not found", func() {
+		s.ctx.Set("ticker", storage.Ticker{ID: 1})
+		h := s.handler()
+		h.ApproveMessage(s.ctx)
+
+		s.Equal(http.StatusNotFound, s.w.Code)
+		s.True(s.store.AssertExpectations(s.T()))
+	})
+
+	s.Run("when message is not pending review", func() {
+		s.ctx.Set("ticker", storage.Ticker{ID: 1})
+		s.ctx.Set("message", storage.Message{ID: 1, Draft: true})
+		h := s.handler()
+		h.ApproveMessage(s.ctx)
+
+		s.Equal(http.StatusBadRequest, s.w.Code)
+		s.True(s.store.AssertExpectations(s.T()))
+	})
+
+	s.Run("when body is invalid", func() {
+		s.ctx.Set("ticker", storage.Ticker{ID: 1})
+		s.ctx.Set("message", storage.Message{ID: 1, Draft: true, Review: storage.ReviewPending})
+		s.ctx.Request = httptest.NewRequest(http.MethodPost, "/v1/admin/tickers/1/messages/1/approve", strings.NewReader(`{"comment":`))
+		s.ctx.Request.Header.Add("Content-Type", "application/json")
+		h := s.handler()
+		h.ApproveMessage(s.ctx)
+
+		s.Equal(http.StatusBadRequest, s.w.Code)
+		s.True(s.store.AssertExpectations(s.T()))
+	})
+
+	s.Run("happy path", func() {
+		s.store.On("SaveMessageRevision", mock.MatchedBy(func(r *storage.MessageRevision) bool {
+			return r.Action == storage.RevisionApproved
+		})).Return(nil).Once()
+		s.ctx.Set("ticker", storage.Ticker{ID: 1, Domain: "localhost"})
+		s.ctx.Set("message", storage.Message{ID: 1, Draft: true, Review: storage.ReviewPending})
+		s.ctx.Request = httptest.NewRequest(http.MethodPost, "/v1/admin/tickers/1/messages/1/approve", strings.NewReader(`{"comment":"thanks"}`))
+		s.ctx.Request.Header.Add("Content-Type", "application/json")
+		s.store.On("PublishMessage", mock.Anything).Return(nil).Run(func(args mock.Arguments) {
+			args.Get(0).(*storage.Message).Draft = false
+		}).Once()
+		s.store.On("SaveMessage", mock.MatchedBy(func(m *storage.Message) bool {
+			return m.Review == storage.ReviewApproved && m.ReviewComment == "thanks"
+		})).Return(nil).Once()
+		h := s.handler()
+		h.ApproveMessage(s.ctx)
+
+		s.Equal(http.StatusOK, s.w.Code)
+		s.NotContains(s.w.Body.String(), "thanks")
+		s.True(s.store.AssertExpectations(s.T()))
+	})
+
+	s.Run("without a comment", func() {
+		s.store.On("SaveMessageRevision", mock.Anything).Return(nil).Once()
+		s.ctx.Set("ticker", storage.Ticker{ID: 1, Domain: "localhost"})
+		s.ctx.Set("message", storage.Message{ID: 1, Draft: true, Review: storage.ReviewPending})
+		s.ctx.Request = httptest.NewRequest(http.MethodPost, "/v1/admin/tickers/1/messages/1/approve", nil)
+		s.store.On("PublishMessage", mock.Anything).Return(nil).Once()
+		s.store.On("SaveMessage", mock.Anything).Return(nil).Once()
+		h := s.handler()
+		h.ApproveMessage(s.ctx)
+
+		s.Equal(http.StatusOK, s.w.Code)
+		s.True(s.store.AssertExpectations(s.T()))
+	})
+}
+
+func (s *ReviewsTestSuite) TestRejectMessage() {
+	s.Run("when message is not pending review", func() {
+		s.ctx.Set("ticker", storage.Ticker{ID: 1})
+		s.ctx.Set("message", storage.Message{ID: 1})
+		h := s.handler()
+		h.RejectMessage(s.ctx)
+
+		s.Equal(http.StatusBadRequest, s.w.Code)
+		s.True(s.store.AssertExpectations(s.T()))
+	})
+
+	s.Run("when comment is missing", func() {
+		s.ctx.Set("ticker", storage.Ticker{ID: 1})
+		s.ctx.Set("message", storage.Message{ID: 1, Draft: true, Review: storage.ReviewPending})
+		s.ctx.Request = httptest.NewRequest(http.MethodPost, "/v1/admin/tickers/1/messages/1/reject", strings.NewReader(`{}`))
+		s.ctx.Request.Header.Add("Content-Type", "application/json")
+		h := s.handler()
+		h.RejectMessage(s.ctx)
+
+		s.Equal(http.StatusBadRequest, s.w.Code)
+		s.True(s.store.AssertExpectations(s.T()))
+	})
+
+	s.Run("when storage returns error", func() {
+		s.ctx.Set("ticker", storage.Ticker{ID: 1})
+		s.ctx.Set("message", storage.Message{ID: 1, Draft: true, Review: storage.ReviewPending})
+		s.ctx.Request = httptest.NewRequest(http.MethodPost, "/v1/admin/tickers/1/messages/1/reject", strings.NewReader(`{"comment":"source missing"}`))
+		s.ctx.Request.Header.Add("Content-Type", "application/json")
+		s.store.On("SaveMessage", mock.Anything).Return(errors.New("storage error")).Once()
+		h := s.handler()
+		h.RejectMessage(s.ctx)
+
+		s.Equal(http.StatusBadRequest, s.w.Code)
+		s.True(s.store.AssertExpectations(s.T()))
+	})
+
+	s.Run("happy path", func() {
+		s.store.On("SaveMessageRevision", mock.MatchedBy(func(r *storage.MessageRevision) bool {
+			return r.Action == storage.RevisionRejected
+		})).Return(nil).Once()
+		s.ctx.Set("ticker", storage.Ticker{ID: 1})
+		s.ctx.Set("message", storage.Message{ID: 1, Draft: true, Review: storage.ReviewPending})
+		s.ctx.Request = httptest.NewRequest(http.MethodPost, "/v1/admin/tickers/1/messages/1/reject", strings.NewReader(`{"comment":"source missing"}`))
+		s.ctx.Request.Header.Add("Content-Type", "application/json")
+		s.store.On("SaveMessage", mock.MatchedBy(func(m *storage.Message) bool {
+			return m.Draft && m.Review == storage.ReviewRejected && m.ReviewComment == "source missing"
+		})).Return(nil).Once()
+		h := s.handler()
+		h.RejectMessage(s.ctx)
+
+		s.Equal(http.StatusOK, s.w.Code)
+		s.Contains(s.w.Body.String(), "source missing")
+		s.True(s.store.AssertExpectations(s.T()))
+	})
+}
+
+func (s *ReviewsTestSuite) TestResubmitMessage() {
+	me := storage.User{ID: 2}
+	rejected := storage.Message{ID: 1, TickerID: 1, Draft: true, Review: storage.ReviewRejected, ReviewComment: "source missing"}
+
+	s.Run("when message is not rejected", func() {
+		s.ctx.Set("ticker", storage.Ticker{ID: 1})
+		s.ctx.Set("message", storage.Message{ID: 1, Draft: true, Review: storage.ReviewPending})
+		s.ctx.Set("me", me)
+		h := s.handler()
+		h.ResubmitMessage(s.ctx)
+
+		s.Equal(http.StatusBadRequest, s.w.Code)
+		s.Contains(s.w.Body.String(), response.MessageNotRejected)
+		s.True(s.store.AssertExpectations(s.T()))
+	})
+
+	s.Run("when the message is from another user", func() {
+		s.ctx.Set("ticker", storage.Ticker{ID: 1})
+		s.ctx.Set("message", rejected)
+		s.ctx.Set("me", me)
+		s.store.On("FindMessageRevisions", 1, 1).Return([]storage.MessageRevision{{UserID: 3, Action: storage.RevisionCreated}}, nil).Once()
+		h := s.handler()
+		h.ResubmitMessage(s.ctx)
+
+		s.Equal(http.StatusForbidden, s.w.Code)
+		s.True(s.store.AssertExpectations(s.T()))
+	})
+
+	s.Run("when text is missing", func() {
+		s.ctx.Set("ticker", storage.Ticker{ID: 1})
+		s.ctx.Set("message", rejected)
+		s.ctx.Set("me", me)
+		s.ctx.Request = httptest.NewRequest(http.MethodPost, "/v1/admin/tickers/1/messages/1/resubmit", strings.NewReader(`{}`))
+		s.ctx.Request.Header.Add("Content-Type", "application/json")
+		s.store.On("FindMessageRevisions", 1, 1).Return([]storage.MessageRevision{{UserID: 2, Action: storage.RevisionCreated}}, nil).Once()
+		h := s.handler()
+		h.ResubmitMessage(s.ctx)
+
+		s.Equal(http.StatusBadRequest, s.w.Code)
+		s.True(s.store.AssertExpectations(s.T()))
+	})
+
+	s.Run("happy path", func() {
+		s.ctx.Set("ticker", storage.Ticker{ID: 1})
+		s.ctx.Set("message", rejected)
+		s.ctx.Set("me", me)
+		s.ctx.Request = httptest.NewRequest(http.MethodPost, "/v1/admin/tickers/1/messages/1/resubmit", strings.NewReader(`{"text":"Revised, with source","tags":[]}`))
+		s.ctx.Request.Header.Add("Content-Type", "application/json")
+		s.store.On("FindMessageRevisions", 1, 1).Return([]storage.MessageRevision{{UserID: 2, Action: storage.RevisionCreated}, {UserID: 1, Action: storage.RevisionRejected}}, nil).Once()
+		s.store.On("SaveMessage", mock.MatchedBy(func(m *storage.Message) bool {
+			return m.Draft && m.Review == storage.ReviewPending && m.Text == "Revised, with source"
+		})).Return(nil).Once()
+		s.store.On("SaveMessageRevision", mock.MatchedBy(func(r *storage.MessageRevision) bool {
+			return r.Action == storage.RevisionResubmitted && r.UserID == 2
+		})).Return(nil).Once()
+		s.store.On("SaveMessageTags", mock.Anything, mock.Anything).Return(nil).Once()
+		h := s.handler()
+		h.ResubmitMessage(s.ctx)
+
+		s.Equal(http.StatusOK, s.w.Code)
+		s.Contains(s.w.Body.String(), `"status":"pending"`)
+		s.True(s.store.AssertExpectations(s.T()))
+	})
+}
+
+func (s *ReviewsTestSuite) handler() handler {
+	return handler{
+		storage:  s.store,
+		config:   s.cfg,
+		cache:    s.cache,
+		realtime: realtime.New(),
+	}
+}
+
+func TestReviewsTestSuite(t *testing.T) {
+	suite.Run(t, new(ReviewsTestSuite))
+}
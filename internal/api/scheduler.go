@@ -0,0 +1,62 @@
+package api
+
+import (
+	"context"
+	"time"
+
+	"github.com/systemli/ticker/internal/storage"
+)
+
+// schedulerInterval is how often the scheduler looks for due messages, and
+// with that the maximum delay after the publishing date of a message.
+const schedulerInterval = 30 * time.Second
+
+// Scheduler publishes scheduled messages once their publishing date is reached.
+type Scheduler struct {
+	handler  *handler
+	interval time.Duration
+}
+
+// Run publishes due messages periodically until the context is cancelled.
+func (s *Scheduler) Run(ctx context.Context) {
+	t := time.NewTicker(s.interval)
+	defer t.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-t.C:
+			s.handler.PublishDueMessages()
+		}
+	}
+}
+
+// PublishDueMessages sends all messages whose publishing date has passed to the
+// bridges and the connected clients, as if they were posted right now.
+func (h *handler) PublishDueMessages() {
+	messages, err := h.storage.FindDueMessages(time.Now(), storage.WithAttachments(), storage.WithTags())
+	if err != nil {
+		log.WithError(err).Error("failed to find due messages")
+		return
+	}
+
+	for _, message := range messages {
+		h.publishDueMessage(message)
+	}
+}
+
+func (h *handler) publishDueMessage(message storage.Message) {
+	ticker, err := h.storage.FindTickerByID(message.TickerID, storage.WithPreload())
+	if err != nil {
+		log.WithError(err).WithField("message_id", message.ID).Error("failed to find ticker for scheduled message")
+		return
+	}
+
+	if err := h.publishMessage(ticker, &message, ""); err != nil {
+		log.WithError(err).WithField("message_id", message.ID).Warn("scheduled message was not published")
+		return
+	}
+
+	h.saveRevision(message, 0, storage.RevisionPublished)
+}
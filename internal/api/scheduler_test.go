@@ -0,0 +1,140 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+	"github.com/systemli/ticker/internal/api/realtime"
+	"github.com/systemli/ticker/internal/bridge"
+	"github.com/systemli/ticker/internal/cache"
+	"github.com/systemli/ticker/internal/storage"
+)
+
+type SchedulerTestSuite struct {
+	store  *storage.MockStorage
+	bridge *bridge.MockBridge
+	cache  *cache.Cache
+	suite.Suite
+}
+
+func (s *SchedulerTestSuite) SetupTest() {
+	logrus.SetOutput(io.Discard)
+}
+
+func (s *SchedulerTestSuite) Run(name string, subtest func()) {
+	s.T().Run(name, func(t *testing.T) {
+		s.store = &storage.MockStorage{}
+		s.bridge = &bridge.MockBridge{}
+		s.cache = cache.NewCache(time.Minute)
+
+		subtest()
+	})
+}
+
+func (s *SchedulerTestSuite) TestPublishDueMessages() {
+	publishAt := time.Now().Add(-time.Minute)
+	message := storage.Message{ID: 1, TickerID: 1, Text: "text", PublishAt: &publishAt}
+	ticker := storage.Ticker{ID: 1, Domain: "localhost"}
+
+	s.Run("when storage returns error", func() {
+		s.store.On("FindDueMessages", mock.Anything, mock.Anything).Return([]storage.Message{}, errors.New("storage error")).Once()
+		h := s.handler()
+		h.PublishDueMessages()
+
+		s.True(s.store.AssertExpectations(s.T()))
+		s.True(s.bridge.AssertExpectations(s.T()))
+	})
+
+	s.Run("when ticker not found", func() {
+		s.store.On("FindDueMessages", mock.Anything, mock.Anything).Return([]storage.Message{message}, nil).Once()
+		s.store.On("FindTickerByID", 1, mock.Anything).Return(storage.Ticker{}, errors.New("not found")).Once()
+		h := s.handler()
+		h.PublishDueMessages()
+
+		s.True(s.store.AssertExpectations(s.T()))
+		s.True(s.bridge.AssertExpectations(s.T()))
+	})
+
+	s.Run("when message was cancelled", func() {
+		s.store.On("FindDueMessages", mock.Anything, mock.Anything).Return([]storage.Message{message}, nil).Once()
+		s.store.On("FindTickerByID", 1, mock.Anything).Return(ticker, nil).Once()
+		s.store.On("PublishMessage", mock.Anything).Return(errors.New("record not found")).Once()
+		h := s.handler()
+		h.PublishDueMessages()
+
+		s.True(s.store.AssertExpectations(s.T()))
+		s.True(s.bridge.AssertExpectations(s.T()))
+	})
+
+	s.Run("happy path", func() {
+		s.store.On("SaveMessageRevision", mock.MatchedBy(func(r *storage.MessageRevision) bool {
+			return r.Action == storage.RevisionPublished
+		})).Return(nil).Once()
+		s.cache.Set("response:localhost:/v1/timeline", true, time.Minute)
+		s.store.On("FindDueMessages", mock.Anything, mock.Anything).Return([]storage.Message{message}, nil).Once()
+		s.store.On("FindTickerByID", 1, mock.Anything).Return(ticker, nil).Once()
+		s.store.On("PublishMessage", mock.Anything).Return(nil).Once()
+		s.store.On("SaveMessage", mock.Anything).Return(nil).Once()
+		s.bridge.On("Enabled", ticker).Return(true).Once()
+		s.store.On("CreateOutboxJobs", mock.Anything, []string{"mock"}).Return(nil).Once()
+		h := s.handler()
+		h.PublishDueMessages()
+
+		s.Nil(s.cache.Get("response:localhost:/v1/timeline"))
+		s.True(s.store.AssertExpectations(s.T()))
+		s.True(s.bridge.AssertExpectations(s.T()))
+	})
+}
+
+func (s *SchedulerTestSuite) TestRun() {
+	s.Run("stops when the context is cancelled", func() {
+		called := make(chan struct{}, 1)
+		s.store.On("FindDueMessages", mock.Anything, mock.Anything).Return([]storage.Message{}, nil).Run(func(args mock.Arguments) {
+			select {
+			case called <- struct{}{}:
+			default:
+			}
+		})
+		h := s.handler()
+		scheduler := Scheduler{handler: &h, interval: time.Millisecond}
+
+		ctx, cancel := context.WithCancel(context.Background())
+		done := make(chan struct{})
+		go func() {
+			scheduler.Run(ctx)
+			close(done)
+		}()
+
+		select {
+		case <-called:
+		case <-time.After(time.Second):
+			s.Fail("scheduler did not look for due messages")
+		}
+
+		cancel()
+		select {
+		case <-done:
+		case <-time.After(time.Second):
+			s.Fail("scheduler did not stop")
+		}
+	})
+}
+
+func (s *SchedulerTestSuite) handler() handler {
+	return handler{
+		storage:  s.store,
+		bridges:  bridge.Bridges{"mock": s.bridge},
+		cache:    s.cache,
+		realtime: realtime.New(),
+	}
+}
+
+func TestSchedulerTestSuite(t *testing.T) {
+	suite.Run(t, new(SchedulerTestSuite))
+}
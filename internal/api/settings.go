@@ -38,6 +38,13 @@ func (h *handler) GetSetting(c *gin.Context) {
 		return
 	}
 
+	if c.Param("name") == storage.SettingSecurityName {
+		setting := h.storage.GetSecuritySettings()
+		data := map[string]interface{}{"setting": response.SecuritySettingsResponse(setting)}
+		c.JSON(http.StatusOK, response.SuccessResponse(data))
+		return
+	}
+
 	c.JSON(http.StatusNotFound, response.ErrorResponse(response.CodeDefault, response.SettingNotFound))
 }
 
@@ -117,3 +124,22 @@ func (h *handler) PutSignalGroupSettings(c *gin.Context) {
 	data := map[string]interface{}{"setting": response.SignalGroupSettingsResponse(setting)}
 	c.JSON(http.StatusOK, response.SuccessResponse(data))
 }
+
+func (h *handler) PutSecuritySettings(c *gin.Context) {
+	value := storage.SecuritySettings{}
+	err := c.Bind(&value)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, response.ErrorResponse(response.CodeDefault, response.FormError))
+		return
+	}
+
+	err = h.storage.SaveSecuritySettings(value)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, response.ErrorResponse(response.CodeDefault, response.StorageError))
+		return
+	}
+
+	setting := h.storage.GetSecuritySettings()
+	data := map[string]interface{}{"setting": response.SecuritySettingsResponse(setting)}
+	c.JSON(http.StatusOK, response.SuccessResponse(data))
+}
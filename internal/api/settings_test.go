@@ -94,6 +94,17 @@ func (s *SettingsTestSuite) TestGetSetting() {
 		s.store.AssertExpectations(s.T())
 	})
 
+	s.Run("get security settings", func() {
+		s.ctx.AddParam("name", storage.SettingSecurityName)
+		s.store.On("GetSecuritySettings").Return(storage.SecuritySettings{RequireTwoFactor: true}).Once()
+		h := s.handler()
+		h.GetSetting(s.ctx)
+
+		s.Equal(http.StatusOK, s.w.Code)
+		s.Contains(s.w.Body.String(), `"requireTwoFactor":true`)
+		s.store.AssertExpectations(s.T())
+	})
+
 	s.Run("get unknown setting", func() {
 		s.ctx.AddParam("name", "unknown_setting")
 		h := s.handler()
@@ -223,6 +234,42 @@ func (s *SettingsTestSuite) TestPutSignalGroupSettings() {
 	})
 }
 
+func (s *SettingsTestSuite) TestPutSecuritySettings() {
+	s.Run("when body is invalid", func() {
+		s.ctx.Request = httptest.NewRequest(http.MethodPut, "/v1/admin/settings/security_settings", strings.NewReader(`{"requireTwoFactor":"yes"}`))
+		s.ctx.Request.Header.Add("Content-Type", "application/json")
+		h := s.handler()
+		h.PutSecuritySettings(s.ctx)
+
+		s.Equal(http.StatusBadRequest, s.w.Code)
+		s.store.AssertExpectations(s.T())
+	})
+
+	s.Run("when storage returns error", func() {
+		s.ctx.Request = httptest.NewRequest(http.MethodPut, "/v1/admin/settings/security_settings", strings.NewReader(`{"requireTwoFactor":true}`))
+		s.ctx.Request.Header.Add("Content-Type", "application/json")
+		s.store.On("SaveSecuritySettings", mock.Anything).Return(errors.New("storage error")).Once()
+		h := s.handler()
+		h.PutSecuritySettings(s.ctx)
+
+		s.Equal(http.StatusBadRequest, s.w.Code)
+		s.store.AssertExpectations(s.T())
+	})
+
+	s.Run("when a second factor is required", func() {
+		s.ctx.Request = httptest.NewRequest(http.MethodPut, "/v1/admin/settings/security_settings", strings.NewReader(`{"requireTwoFactor":true}`))
+		s.ctx.Request.Header.Add("Content-Type", "application/json")
+		s.store.On("SaveSecuritySettings", storage.SecuritySettings{RequireTwoFactor: true}).Return(nil).Once()
+		s.store.On("GetSecuritySettings").Return(storage.SecuritySettings{RequireTwoFactor: true})
+		h := s.handler()
+		h.PutSecuritySettings(s.ctx)
+
+		s.Equal(http.StatusOK, s.w.Code)
+		s.Contains(s.w.Body.String(), "security_settings")
+		s.store.AssertExpectations(s.T())
+	})
+}
+
 func (s *SettingsTestSuite) handler() handler {
 	return handler{
 		storage: s.store,
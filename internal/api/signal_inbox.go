@@ -0,0 +1,148 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/systemli/ticker/internal/signal"
+	"github.com/systemli/ticker/internal/storage"
+)
+
+const (
+	// signalIdleInterval is the pause while posting through Signal is turned
+	// off, after the stream failed and between checks of the settings.
+	signalIdleInterval = 30 * time.Second
+	// signalAttachmentLimit keeps attachments to the size of uploads through
+	// the admin interface.
+	signalAttachmentLimit = 10 << 20
+)
+
+// SignalInbox posts the messages the Signal account receives from the numbers
+// allow-listed on a ticker to that ticker. It follows the Signal settings and
+// reconnects when they change.
+type SignalInbox struct {
+	handler *handler
+	idle    time.Duration
+}
+
+// Run receives messages until the context is cancelled.
+func (s *SignalInbox) Run(ctx context.Context) {
+	for ctx.Err() == nil {
+		settings := s.handler.storage.GetSignalGroupSettings()
+		if !settings.InboundEnabled() {
+			s.wait(ctx)
+			continue
+		}
+
+		streamCtx, cancel := context.WithCancel(ctx)
+		go s.watch(streamCtx, cancel, settings)
+
+		receiver := signal.NewReceiverFromSettings(settings)
+		err := receiver.Receive(streamCtx, func(message signal.IncomingMessage) {
+			s.handler.handleSignalMessage(streamCtx, receiver, message)
+		})
+		changed := streamCtx.Err() != nil
+		cancel()
+
+		if !changed {
+			log.WithError(err).Error("failed to receive signal messages")
+			s.wait(ctx)
+		}
+	}
+}
+
+// watch cancels the stream once the settings differ from the ones it was
+// opened with.
+func (s *SignalInbox) watch(ctx context.Context, cancel context.CancelFunc, settings storage.SignalGroupSettings) {
+	t := time.NewTicker(s.idle)
+	defer t.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-t.C:
+			if s.handler.storage.GetSignalGroupSettings() != settings {
+				cancel()
+				return
+			}
+		}
+	}
+}
+
+func (s *SignalInbox) wait(ctx context.Context) {
+	select {
+	case <-ctx.Done():
+	case <-time.After(s.idle):
+	}
+}
+
+// handleSignalMessage posts the message when the number is allow-listed on a
+// ticker. The senders are trusted by the owners of the ticker, their messages
+// are published right away unless single sign-on is on. Attachments other
+// than images are left out.
+func (h *handler) handleSignalMessage(ctx context.Context, receiver *signal.Receiver, incoming signal.IncomingMessage) {
+	logger := log.WithField("number", incoming.Number)
+
+	sender, err := h.storage.FindTickerSignalSenderByNumber(incoming.Number)
+	if err != nil {
+		logger.Debug("ignore signal message from unknown number")
+		return
+	}
+
+	ticker, err := h.storage.FindTickerByID(sender.TickerID, storage.WithPreload())
+	if err != nil {
+		logger.WithError(err).Error("failed to find ticker for signal message")
+		return
+	}
+
+	text := strings.TrimSpace(incoming.Text)
+	if text == "" {
+		logger.Info("ignore signal message without text")
+		return
+	}
+
+	uploads := make([]storage.Upload, 0, len(incoming.Attachments))
+	for _, attachment := range incoming.Attachments {
+		upload, err := h.saveSignalAttachment(ctx, receiver, ticker, incoming.Number, attachment)
+		if err != nil {
+			logger.WithError(err).WithField("attachment", attachment.ID).Error("failed to save signal attachment")
+			continue
+		}
+		uploads = append(uploads, upload)
+	}
+
+	message := storage.NewMessage()
+	message.Text = text
+	message.TickerID = ticker.ID
+	message.AddAttachments(uploads)
+
+	// The numbers are no users the identity provider could disable, so with
+	// single sign-on their messages wait for an editor who signed in there.
+	if h.sso != nil {
+		message.Draft = true
+		message.Review = storage.ReviewPending
+	}
+
+	if err := h.publishInboxMessage(ticker, &message, 0); err != nil {
+		logger.WithError(err).Error("failed to save signal message")
+	}
+}
+
+func (h *handler) saveSignalAttachment(ctx context.Context, receiver *signal.Receiver, ticker storage.Ticker, number string, attachment signal.Attachment) (storage.Upload, error) {
+	if !strings.HasPrefix(attachment.ContentType, "image/") {
+		return storage.Upload{}, errors.New(attachment.ContentType + " is not allowed to be uploaded")
+	}
+	if attachment.Size > signalAttachmentLimit {
+		return storage.Upload{}, errors.New("attachment is too large")
+	}
+
+	data, err := receiver.Attachment(ctx, number, attachment)
+	if err != nil {
+		return storage.Upload{}, err
+	}
+
+	return h.saveInboxImage(ticker, data)
+}
@@ -0,0 +1,246 @@
+package api
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+	"github.com/systemli/ticker/internal/api/realtime"
+	"github.com/systemli/ticker/internal/config"
+	"github.com/systemli/ticker/internal/signal"
+	"github.com/systemli/ticker/internal/sso"
+	"github.com/systemli/ticker/internal/storage"
+)
+
+const signalTestAccount = "+491234567890"
+
+type SignalInboxTestSuite struct {
+	store  *storage.MockStorage
+	cfg    config.Config
+	server *httptest.Server
+	events chan string
+	suite.Suite
+}
+
+func (s *SignalInboxTestSuite) Run(name string, subtest func()) {
+	s.T().Run(name, func(t *testing.T) {
+		s.store = &storage.MockStorage{}
+		s.cfg = config.LoadConfig("")
+		s.cfg.Upload.Path = t.TempDir()
+		s.events = make(chan string, 1)
+		s.server = s.signalCLI()
+		defer s.server.Close()
+
+		subtest()
+	})
+}
+
+// signalCLI stubs the HTTP endpoints of signal-cli: the events stream sends
+// what is put into the channel, getAttachment returns the gopher.
+func (s *SignalInboxTestSuite) signalCLI() *httptest.Server {
+	photo, err := os.ReadFile("../../testdata/gopher.jpg")
+	s.Require().NoError(err)
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /api/v1/events", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/event-stream")
+		w.WriteHeader(http.StatusOK)
+		w.(http.Flusher).Flush()
+
+		for {
+			select {
+			case <-r.Context().Done():
+				return
+			case event := <-s.events:
+				fmt.Fprintf(w, "event:receive\ndata:%s\n\n", event)
+				w.(http.Flusher).Flush()
+			}
+		}
+	})
+	mux.HandleFunc("POST /api/v1/rpc", func(w http.ResponseWriter, r *http.Request) {
+		var request struct {
+			ID     int               `json:"id"`
+			Params map[string]string `json:"params"`
+		}
+		s.Require().NoError(json.NewDecoder(r.Body).Decode(&request))
+
+		if request.Params["id"] != "gopher.jpg" {
+			fmt.Fprintf(w, `{"jsonrpc":"2.0","id":%d,"error":{"code":-1,"message":"not found"}}`, request.ID)
+			return
+		}
+		fmt.Fprintf(w, `{"jsonrpc":"2.0","id":%d,"result":{"data":"%s"}}`, request.ID, base64.StdEncoding.EncodeToString(photo))
+	})
+
+	return httptest.NewServer(mux)
+}
+
+func (s *SignalInboxTestSuite) TestHandleSignalMessage() {
+	sender := storage.TickerSignalSender{ID: 1, TickerID: 1, Number: "+4915112345678"}
+	ticker := storage.Ticker{ID: 1, Title: "Ticker"}
+
+	s.Run("when number is unknown", func() {
+		s.store.On("FindTickerSignalSenderByNumber", "+4915112345678").Return(storage.TickerSignalSender{}, errors.New("not found")).Once()
+
+		h := s.handler()
+		h.handleSignalMessage(context.Background(), s.receiver(), signal.IncomingMessage{Number: "+4915112345678", Text: "text"})
+
+		s.store.AssertExpectations(s.T())
+	})
+
+	s.Run("when ticker is not found", func() {
+		s.store.On("FindTickerSignalSenderByNumber", "+4915112345678").Return(sender, nil).Once()
+		s.store.On("FindTickerByID", 1, mock.Anything).Return(storage.Ticker{}, errors.New("not found")).Once()
+
+		h := s.handler()
+		h.handleSignalMessage(context.Background(), s.receiver(), signal.IncomingMessage{Number: "+4915112345678", Text: "text"})
+
+		s.store.AssertExpectations(s.T())
+	})
+
+	s.Run("when message has no text", func() {
+		s.store.On("FindTickerSignalSenderByNumber", "+4915112345678").Return(sender, nil).Once()
+		s.store.On("FindTickerByID", 1, mock.Anything).Return(ticker, nil).Once()
+
+		h := s.handler()
+		h.handleSignalMessage(context.Background(), s.receiver(), signal.IncomingMessage{
+			Number:      "+4915112345678",
+			Attachments: []signal.Attachment{{ID: "gopher.jpg", ContentType: "image/jpeg"}},
+		})
+
+		s.store.AssertExpectations(s.T())
+	})
+
+	s.Run("when single sign-on is on", func() {
+		s.store.On("FindTickerSignalSenderByNumber", "+4915112345678").Return(sender, nil).Once()
+		s.store.On("FindTickerByID", 1, mock.Anything).Return(ticker, nil).Once()
+		s.store.On("SaveMessage", mock.MatchedBy(func(m *storage.Message) bool {
+			return m.Text == "text" && m.Draft && m.Review == storage.ReviewPending
+		})).Return(nil).Once()
+		s.store.On("SaveMessageRevision", mock.Anything).Return(nil).Once()
+		s.store.On("SaveMessageTags", mock.Anything, mock.Anything).Return(nil).Once()
+
+		h := s.handler()
+		h.sso = sso.New(config.OIDC{Issuer: "http://127.0.0.1:1"}, s.store)
+		h.handleSignalMessage(context.Background(), s.receiver(), signal.IncomingMessage{Number: "+4915112345678", Text: "text"})
+
+		s.store.AssertExpectations(s.T())
+	})
+
+	s.Run("when message has attachments", func() {
+		s.store.On("FindTickerSignalSenderByNumber", "+4915112345678").Return(sender, nil).Once()
+		s.store.On("FindTickerByID", 1, mock.Anything).Return(ticker, nil).Once()
+		s.store.On("SaveUpload", mock.MatchedBy(func(u *storage.Upload) bool {
+			return u.TickerID == 1 && u.ContentType == "image/jpeg"
+		})).Return(nil).Once()
+		s.store.On("SaveMessage", mock.MatchedBy(func(m *storage.Message) bool {
+			return m.Text == "Hello #world" && m.TickerID == 1 && !m.Draft && len(m.Attachments) == 1
+		})).Return(nil).Once()
+		s.store.On("SaveMessageRevision", mock.MatchedBy(func(r *storage.MessageRevision) bool {
+			return r.UserID == 0
+		})).Return(nil).Once()
+		s.store.On("SaveMessageTags", mock.Anything, []string{"world"}).Return(nil).Once()
+
+		h := s.handler()
+		h.handleSignalMessage(context.Background(), s.receiver(), signal.IncomingMessage{
+			Number: "+4915112345678",
+			Text:   " Hello #world ",
+			Attachments: []signal.Attachment{
+				{ID: "gopher.jpg", ContentType: "image/jpeg"},
+				{ID: "missing.jpg", ContentType: "image/jpeg"},
+				{ID: "video.mp4", ContentType: "video/mp4"},
+				{ID: "large.jpg", ContentType: "image/jpeg", Size: signalAttachmentLimit + 1},
+			},
+		})
+
+		s.store.AssertExpectations(s.T())
+	})
+}
+
+func (s *SignalInboxTestSuite) TestRun() {
+	s.Run("when inbound is disabled", func() {
+		s.store.On("GetSignalGroupSettings").Return(storage.SignalGroupSettings{ApiUrl: s.server.URL + "/api/v1/rpc", Account: signalTestAccount})
+		h := s.handler()
+		inbox := &SignalInbox{handler: &h, idle: time.Millisecond}
+
+		ctx, cancel := context.WithCancel(context.Background())
+		done := make(chan struct{})
+		go func() {
+			inbox.Run(ctx)
+			close(done)
+		}()
+
+		time.Sleep(10 * time.Millisecond)
+		cancel()
+
+		select {
+		case <-done:
+		case <-time.After(time.Second):
+			s.Fail("inbox did not stop")
+		}
+	})
+
+	s.Run("when a message is received", func() {
+		settings := storage.SignalGroupSettings{ApiUrl: s.server.URL + "/api/v1/rpc", Account: signalTestAccount, Inbound: true}
+		s.store.On("GetSignalGroupSettings").Return(settings)
+		s.store.On("FindTickerSignalSenderByNumber", "+4915112345678").Return(storage.TickerSignalSender{TickerID: 1}, nil).Once()
+		s.store.On("FindTickerByID", 1, mock.Anything).Return(storage.Ticker{ID: 1}, nil).Once()
+		s.store.On("SaveMessageRevision", mock.Anything).Return(nil).Once()
+		s.store.On("SaveMessageTags", mock.Anything, mock.Anything).Return(nil).Once()
+
+		saved := make(chan storage.Message, 1)
+		s.store.On("SaveMessage", mock.Anything).Return(nil).Once().Run(func(args mock.Arguments) {
+			saved <- *args.Get(0).(*storage.Message)
+		})
+
+		h := s.handler()
+		inbox := &SignalInbox{handler: &h, idle: time.Minute}
+
+		ctx, cancel := context.WithCancel(context.Background())
+		done := make(chan struct{})
+		go func() {
+			inbox.Run(ctx)
+			close(done)
+		}()
+
+		s.events <- `{"envelope":{"sourceNumber":"+4915112345678","timestamp":1700000000000,"dataMessage":{"message":"From the field"}},"account":"+491234567890"}`
+
+		select {
+		case message := <-saved:
+			s.Equal("From the field", message.Text)
+		case <-time.After(time.Second):
+			s.Fail("message was not saved")
+		}
+
+		cancel()
+		select {
+		case <-done:
+		case <-time.After(time.Second):
+			s.Fail("inbox did not stop")
+		}
+	})
+}
+
+func (s *SignalInboxTestSuite) receiver() *signal.Receiver {
+	return signal.NewReceiverFromSettings(storage.SignalGroupSettings{ApiUrl: s.server.URL + "/api/v1/rpc", Account: signalTestAccount})
+}
+
+func (s *SignalInboxTestSuite) handler() handler {
+	return handler{
+		storage:  s.store,
+		config:   s.cfg,
+		realtime: realtime.New(),
+	}
+}
+
+func TestSignalInboxTestSuite(t *testing.T) {
+	suite.Run(t, new(SignalInboxTestSuite))
+}
@@ -0,0 +1,28 @@
+package api
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/systemli/ticker/internal/api/response"
+)
+
+// PostOIDCLogin starts the single sign-on and returns the URL of the identity
+// provider to send the user to. The provider sends the user back to the
+// admin interface with a code and the state, which are sent as the oidc of
+// the login.
+func (h *handler) PostOIDCLogin(c *gin.Context) {
+	if h.sso == nil {
+		c.JSON(http.StatusBadRequest, response.ErrorResponse(response.CodeDefault, response.SingleSignOnDisabled))
+		return
+	}
+
+	url, err := h.sso.AuthURL(c.Request.Context())
+	if err != nil {
+		log.WithError(err).Error("failed to start single sign-on")
+		c.JSON(http.StatusBadRequest, response.ErrorResponse(response.CodeDefault, response.SingleSignOnError))
+		return
+	}
+
+	c.JSON(http.StatusOK, response.SuccessResponse(map[string]interface{}{"oidc": map[string]interface{}{"url": url}}))
+}
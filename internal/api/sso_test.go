@@ -0,0 +1,81 @@
+package api
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+	"github.com/systemli/ticker/internal/api/response"
+	"github.com/systemli/ticker/internal/config"
+	"github.com/systemli/ticker/internal/sso"
+	"github.com/systemli/ticker/internal/sso/ssotest"
+	"github.com/systemli/ticker/internal/storage"
+)
+
+type SSOTestSuite struct {
+	w     *httptest.ResponseRecorder
+	ctx   *gin.Context
+	store *storage.MockStorage
+	suite.Suite
+}
+
+func (s *SSOTestSuite) SetupTest() {
+	gin.SetMode(gin.TestMode)
+}
+
+func (s *SSOTestSuite) Run(name string, subtest func()) {
+	s.T().Run(name, func(t *testing.T) {
+		s.w = httptest.NewRecorder()
+		s.ctx, _ = gin.CreateTestContext(s.w)
+		s.ctx.Request = httptest.NewRequest(http.MethodPost, "/v1/admin/login/oidc", nil)
+		s.store = &storage.MockStorage{}
+
+		subtest()
+	})
+}
+
+func (s *SSOTestSuite) TestPostOIDCLogin() {
+	s.Run("when single sign-on is disabled", func() {
+		h := s.handler()
+		h.PostOIDCLogin(s.ctx)
+
+		s.Equal(http.StatusBadRequest, s.w.Code)
+		s.Contains(s.w.Body.String(), response.SingleSignOnDisabled)
+	})
+
+	s.Run("when the identity provider is down", func() {
+		h := s.handler()
+		h.sso = sso.New(config.OIDC{Issuer: "http://127.0.0.1:1", ClientID: ssotest.ClientID, RedirectURL: ssotest.RedirectURL}, s.store)
+		h.PostOIDCLogin(s.ctx)
+
+		s.Equal(http.StatusBadRequest, s.w.Code)
+		s.Contains(s.w.Body.String(), response.SingleSignOnError)
+	})
+
+	s.Run("when the login starts", func() {
+		server := ssotest.NewServer(s.T())
+		s.store.On("SaveOIDCSession", mock.Anything).Return(nil).Once()
+
+		h := s.handler()
+		h.sso = sso.New(server.Config(), s.store)
+		h.PostOIDCLogin(s.ctx)
+
+		s.Equal(http.StatusOK, s.w.Code)
+		s.Contains(s.w.Body.String(), `"url":"`+server.URL+`/auth?`)
+		s.store.AssertExpectations(s.T())
+	})
+}
+
+func (s *SSOTestSuite) handler() handler {
+	return handler{
+		storage: s.store,
+		config:  config.LoadConfig(""),
+	}
+}
+
+func TestSSOTestSuite(t *testing.T) {
+	suite.Run(t, new(SSOTestSuite))
+}
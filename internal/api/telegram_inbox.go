@@ -0,0 +1,403 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"github.com/systemli/ticker/internal/api/helper"
+	"github.com/systemli/ticker/internal/api/response"
+	"github.com/systemli/ticker/internal/storage"
+)
+
+const (
+	// telegramPollTimeout is how long, in seconds, a request for updates
+	// waits for a new message to arrive.
+	telegramPollTimeout = 30
+	// telegramIdleInterval is the pause while posting through Telegram is
+	// turned off, and after a failed request.
+	telegramIdleInterval = 30 * time.Second
+	// telegramPhotoLimit is the largest file the bot API hands out.
+	telegramPhotoLimit = 20 << 20
+)
+
+const (
+	telegramReplyHelp      = "Send me a text or a photo with a caption and I post it to your ticker.\n\n/tickers lists the tickers you can post to\n/ticker <id> chooses one of them\n/stop unlinks your Telegram account"
+	telegramReplyNotLinked = "Your Telegram account is not linked to the ticker. Link it on your profile in the admin interface."
+	telegramReplySignIn    = "Sign in to the admin interface to keep posting through Telegram."
+	telegramReplyNoTickers = "You cannot post to any ticker."
+	telegramReplyNoText    = "A message needs a text. Add a caption to send a photo."
+)
+
+// PostTelegramLink returns a code for the current user to link a Telegram
+// account, as the link which starts the bot with it.
+func (h *handler) PostTelegramLink(c *gin.Context) {
+	me, err := helper.Me(c)
+	if err != nil {
+		c.JSON(http.StatusForbidden, response.ErrorResponse(response.CodeDefault, response.Unauthorized))
+		return
+	}
+
+	settings := h.storage.GetTelegramSettings()
+	if !settings.InboundEnabled() {
+		c.JSON(http.StatusBadRequest, response.ErrorResponse(response.CodeDefault, response.TelegramInboundDisabled))
+		return
+	}
+
+	link, err := storage.NewTelegramLink(me)
+	if err == nil {
+		err = h.storage.SaveTelegramLink(&link)
+	}
+	if err != nil {
+		c.JSON(http.StatusBadRequest, response.ErrorResponse(response.CodeDefault, response.StorageError))
+		return
+	}
+
+	c.JSON(http.StatusOK, response.SuccessResponse(map[string]interface{}{"telegram": map[string]interface{}{
+		"code":      link.Code,
+		"url":       fmt.Sprintf("https://t.me/%s?start=%s", settings.BotUsername, link.Code),
+		"expiresAt": link.CreatedAt.Add(storage.TelegramLinkTTL),
+	}}))
+}
+
+func (h *handler) DeleteTelegramLink(c *gin.Context) {
+	me, err := helper.Me(c)
+	if err != nil {
+		c.JSON(http.StatusForbidden, response.ErrorResponse(response.CodeDefault, response.Unauthorized))
+		return
+	}
+
+	err = h.storage.UnlinkTelegramAccount(&me)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, response.ErrorResponse(response.CodeDefault, response.StorageError))
+		return
+	}
+
+	c.JSON(http.StatusOK, response.SuccessResponse(map[string]interface{}{"user": response.UserResponse(me)}))
+}
+
+// TelegramInbox receives the messages sent to the bot by long polling, so the
+// API needs no public address for it. It follows the Telegram settings: it
+// only polls while posting through Telegram is turned on.
+type TelegramInbox struct {
+	handler *handler
+	idle    time.Duration
+	client  *http.Client
+}
+
+// Run polls for messages until the context is cancelled. Each request for
+// updates confirms the ones handled before, so none is posted twice.
+func (t *TelegramInbox) Run(ctx context.Context) {
+	var bot *tgbotapi.BotAPI
+	offset := 0
+
+	for ctx.Err() == nil {
+		settings := t.handler.storage.GetTelegramSettings()
+		if !settings.InboundEnabled() {
+			bot = nil
+			t.wait(ctx)
+			continue
+		}
+
+		if bot == nil || bot.Token != settings.Token {
+			var err error
+			bot, err = tgbotapi.NewBotAPIWithClient(settings.Token, tgbotapi.APIEndpoint, t.client)
+			if err != nil {
+				log.WithError(err).Error("failed to connect to telegram")
+				bot = nil
+				t.wait(ctx)
+				continue
+			}
+		}
+
+		updates, err := bot.GetUpdates(tgbotapi.UpdateConfig{Offset: offset, Timeout: telegramPollTimeout, AllowedUpdates: []string{"message"}})
+		if err != nil {
+			log.WithError(err).Error("failed to receive telegram updates")
+			t.wait(ctx)
+			continue
+		}
+
+		for _, messages := range telegramPosts(updates) {
+			t.handler.handleTelegramMessages(bot, messages)
+		}
+		if len(updates) > 0 {
+			offset = updates[len(updates)-1].UpdateID + 1
+		}
+	}
+
+}
+
+func (t *TelegramInbox) wait(ctx context.Context) {
+	select {
+	case <-ctx.Done():
+	case <-time.After(t.idle):
+	}
+}
+
+// telegramPosts returns the messages of the updates, with the photos of an
+// album, which arrive as one message each, grouped together.
+func telegramPosts(updates []tgbotapi.Update) [][]*tgbotapi.Message {
+	posts := make([][]*tgbotapi.Message, 0)
+	for _, update := range updates {
+		message := update.Message
+		if message == nil {
+			continue
+		}
+
+		if n := len(posts); n > 0 && message.MediaGroupID != "" {
+			last := posts[n-1][0]
+			if last.MediaGroupID == message.MediaGroupID && last.Chat.ID == message.Chat.ID {
+				posts[n-1] = append(posts[n-1], message)
+				continue
+			}
+		}
+
+		posts = append(posts, []*tgbotapi.Message{message})
+	}
+
+	return posts
+}
+
+// handleTelegramMessages answers a command or posts the messages to the ticker
+// the user chose. Only private chats with the bot are taken into account.
+func (h *handler) handleTelegramMessages(bot *tgbotapi.BotAPI, messages []*tgbotapi.Message) {
+	first := messages[0]
+	if first.From == nil || first.Chat == nil || !first.Chat.IsPrivate() {
+		return
+	}
+
+	reply := func(text string) {
+		if _, err := bot.Send(tgbotapi.NewMessage(first.Chat.ID, text)); err != nil {
+			log.WithError(err).Error("failed to reply on telegram")
+		}
+	}
+
+	if first.IsCommand() && first.Command() == "start" && first.CommandArguments() != "" {
+		reply(h.linkTelegramAccount(first.CommandArguments(), first.From.ID))
+		return
+	}
+
+	user, err := h.storage.FindUserByTelegramID(first.From.ID)
+	if err != nil {
+		reply(telegramReplyNotLinked)
+		return
+	}
+
+	if !h.mayPostFromInbox(user) && !(first.IsCommand() && first.Command() == "stop") {
+		reply(telegramReplySignIn)
+		return
+	}
+
+	if first.IsCommand() {
+		switch first.Command() {
+		case "tickers":
+			reply(h.listTelegramTickers(user))
+		case "ticker":
+			reply(h.chooseTelegramTicker(user, first.CommandArguments()))
+		case "stop":
+			if err := h.storage.UnlinkTelegramAccount(&user); err != nil {
+				reply("Your Telegram account could not be unlinked, please try again.")
+				return
+			}
+			reply("Your Telegram account is unlinked.")
+		default:
+			reply(telegramReplyHelp)
+		}
+		return
+	}
+
+	reply(h.postTelegramMessages(bot, user, messages))
+}
+
+func (h *handler) linkTelegramAccount(code string, telegramID int64) string {
+	link, err := h.storage.FindTelegramLinkByCode(code)
+	if err != nil || link.Expired() {
+		return "This link is not valid anymore. Create a new one on your profile in the admin interface."
+	}
+
+	if err := h.storage.LinkTelegramAccount(link, telegramID); err != nil {
+		log.WithError(err).WithField("user_id", link.UserID).Error("failed to link telegram account")
+		return "Your Telegram account could not be linked, please try again."
+	}
+
+	return "Your Telegram account is linked.\n\n" + telegramReplyHelp
+}
+
+// telegramTicker is a ticker the user may post to, with the role of the user.
+type telegramTicker struct {
+	ticker storage.Ticker
+	role   string
+}
+
+func (h *handler) telegramTickers(user storage.User) ([]telegramTicker, error) {
+	tickers, err := h.storage.FindTickersByUser(user, storage.NewTickerFilter(nil), storage.WithPreload())
+	if err != nil {
+		return nil, err
+	}
+
+	allowed := make([]telegramTicker, 0, len(tickers))
+	for _, ticker := range tickers {
+		role := storage.TickerRoleOwner
+		if !user.IsSuperAdmin {
+			tickerUser, err := h.storage.FindTickerUser(ticker, user)
+			if err != nil {
+				continue
+			}
+			role = tickerUser.Role
+		}
+
+		if storage.HasTickerRole(role, storage.TickerRoleContributor) {
+			allowed = append(allowed, telegramTicker{ticker: ticker, role: role})
+		}
+	}
+
+	return allowed, nil
+}
+
+func (h *handler) listTelegramTickers(user storage.User) string {
+	tickers, err := h.telegramTickers(user)
+	if err != nil {
+		return "Your tickers could not be loaded, please try again."
+	}
+	if len(tickers) == 0 {
+		return telegramReplyNoTickers
+	}
+
+	var b strings.Builder
+	b.WriteString("You can post to these tickers:\n")
+	for _, t := range tickers {
+		marker := ""
+		if t.ticker.ID == user.TelegramTickerID {
+			marker = " (chosen)"
+		}
+		fmt.Fprintf(&b, "\n%d: %s%s", t.ticker.ID, t.ticker.Title, marker)
+	}
+
+	return b.String()
+}
+
+func (h *handler) chooseTelegramTicker(user storage.User, argument string) string {
+	id, err := strconv.Atoi(strings.TrimSpace(argument))
+	if err != nil {
+		return "Send /ticker with the number of the ticker, /tickers lists them."
+	}
+
+	tickers, err := h.telegramTickers(user)
+	if err != nil {
+		return "Your tickers could not be loaded, please try again."
+	}
+
+	for _, t := range tickers {
+		if t.ticker.ID != id {
+			continue
+		}
+
+		user.TelegramTickerID = id
+		if err := h.storage.SaveTelegramTicker(&user); err != nil {
+			return "The ticker could not be chosen, please try again."
+		}
+
+		return fmt.Sprintf("Your messages are posted to %s now.", t.ticker.Title)
+	}
+
+	return "You cannot post to this ticker, /tickers lists the ones you can."
+}
+
+// postTelegramMessages creates a ticker message from the text and photos. Like
+// in the admin interface, messages of contributors wait for a review.
+func (h *handler) postTelegramMessages(bot *tgbotapi.BotAPI, user storage.User, messages []*tgbotapi.Message) string {
+	tickers, err := h.telegramTickers(user)
+	if err != nil {
+		return "Your tickers could not be loaded, please try again."
+	}
+	if len(tickers) == 0 {
+		return telegramReplyNoTickers
+	}
+
+	var target *telegramTicker
+	for i := range tickers {
+		if tickers[i].ticker.ID == user.TelegramTickerID || len(tickers) == 1 {
+			target = &tickers[i]
+			break
+		}
+	}
+	if target == nil {
+		return "Choose the ticker to post to with /ticker <id> first, /tickers lists them."
+	}
+
+	var text string
+	var photos []tgbotapi.PhotoSize
+	for _, message := range messages {
+		if text == "" {
+			text = strings.TrimSpace(message.Text + message.Caption)
+		}
+		if len(message.Photo) > 0 {
+			photos = append(photos, message.Photo[len(message.Photo)-1])
+		}
+	}
+	if text == "" {
+		return telegramReplyNoText
+	}
+
+	uploads := make([]storage.Upload, 0, len(photos))
+	for _, photo := range photos {
+		upload, err := h.saveTelegramPhoto(bot, target.ticker, photo)
+		if err != nil {
+			log.WithError(err).WithField("ticker_id", target.ticker.ID).Error("failed to save telegram photo")
+			return "The photo could not be saved, please try again."
+		}
+		uploads = append(uploads, upload)
+	}
+
+	message := storage.NewMessage()
+	message.Text = text
+	message.TickerID = target.ticker.ID
+	message.AddAttachments(uploads)
+
+	if !storage.HasTickerRole(target.role, storage.TickerRoleEditor) {
+		message.Draft = true
+		message.Review = storage.ReviewPending
+	}
+
+	if err := h.publishInboxMessage(target.ticker, &message, user.ID); err != nil {
+		return "The message could not be saved, please try again."
+	}
+
+	if !message.IsPublished() {
+		return fmt.Sprintf("Your message waits for a review by the editors of %s.", target.ticker.Title)
+	}
+
+	return fmt.Sprintf("Your message is published on %s.", target.ticker.Title)
+}
+
+// saveTelegramPhoto downloads the photo from Telegram and stores it as an
+// upload of the ticker.
+func (h *handler) saveTelegramPhoto(bot *tgbotapi.BotAPI, ticker storage.Ticker, photo tgbotapi.PhotoSize) (storage.Upload, error) {
+	url, err := bot.GetFileDirectURL(photo.FileID)
+	if err != nil {
+		return storage.Upload{}, err
+	}
+
+	resp, err := bot.Client.(*http.Client).Get(url)
+	if err != nil {
+		return storage.Upload{}, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return storage.Upload{}, fmt.Errorf("telegram responded with %s", resp.Status)
+	}
+
+	data, err := io.ReadAll(io.LimitReader(resp.Body, telegramPhotoLimit))
+	if err != nil {
+		return storage.Upload{}, err
+	}
+
+	return h.saveInboxImage(ticker, data)
+}
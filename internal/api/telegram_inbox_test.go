@@ -0,0 +1,456 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"github.com/h2non/gock"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+	"github.com/systemli/ticker/internal/api/realtime"
+	"github.com/systemli/ticker/internal/api/response"
+	"github.com/systemli/ticker/internal/config"
+	"github.com/systemli/ticker/internal/sso"
+	"github.com/systemli/ticker/internal/storage"
+)
+
+const telegramTestToken = "123456789:token"
+
+type TelegramInboxTestSuite struct {
+	w     *httptest.ResponseRecorder
+	ctx   *gin.Context
+	store *storage.MockStorage
+	cfg   config.Config
+	suite.Suite
+}
+
+func (s *TelegramInboxTestSuite) SetupTest() {
+	gin.SetMode(gin.TestMode)
+	gock.DisableNetworking()
+}
+
+func (s *TelegramInboxTestSuite) TearDownTest() {
+	gock.Off()
+}
+
+func (s *TelegramInboxTestSuite) Run(name string, subtest func()) {
+	s.T().Run(name, func(t *testing.T) {
+		s.w = httptest.NewRecorder()
+		s.ctx, _ = gin.CreateTestContext(s.w)
+		s.store = &storage.MockStorage{}
+		s.cfg = config.LoadConfig("")
+		s.cfg.Upload.Path = t.TempDir()
+
+		subtest()
+	})
+}
+
+func (s *TelegramInboxTestSuite) TestPostTelegramLink() {
+	user := storage.User{ID: 1, Email: "user@systemli.org"}
+	settings := storage.TelegramSettings{Token: telegramTestToken, BotUsername: "ticker_bot", Inbound: true}
+
+	s.Run("when user is missing", func() {
+		h := s.handler()
+		h.PostTelegramLink(s.ctx)
+
+		s.Equal(http.StatusForbidden, s.w.Code)
+		s.store.AssertExpectations(s.T())
+	})
+
+	s.Run("when inbound is disabled", func() {
+		s.ctx.Set("me", user)
+		s.store.On("GetTelegramSettings").Return(storage.TelegramSettings{Token: telegramTestToken}).Once()
+
+		h := s.handler()
+		h.PostTelegramLink(s.ctx)
+
+		s.Equal(http.StatusBadRequest, s.w.Code)
+		s.Contains(s.w.Body.String(), response.TelegramInboundDisabled)
+		s.store.AssertExpectations(s.T())
+	})
+
+	s.Run("when storage returns error", func() {
+		s.ctx.Set("me", user)
+		s.store.On("GetTelegramSettings").Return(settings).Once()
+		s.store.On("SaveTelegramLink", mock.Anything).Return(errors.New("storage error")).Once()
+
+		h := s.handler()
+		h.PostTelegramLink(s.ctx)
+
+		s.Equal(http.StatusBadRequest, s.w.Code)
+		s.store.AssertExpectations(s.T())
+	})
+
+	s.Run("when link is created", func() {
+		s.ctx.Set("me", user)
+		s.store.On("GetTelegramSettings").Return(settings).Once()
+		s.store.On("SaveTelegramLink", mock.MatchedBy(func(link *storage.TelegramLink) bool {
+			return link.UserID == 1 && len(link.Code) == 32
+		})).Return(nil).Once()
+
+		h := s.handler()
+		h.PostTelegramLink(s.ctx)
+
+		s.Equal(http.StatusOK, s.w.Code)
+		s.Contains(s.w.Body.String(), `"url":"https://t.me/ticker_bot?start=`)
+		s.Contains(s.w.Body.String(), `"expiresAt"`)
+		s.store.AssertExpectations(s.T())
+	})
+}
+
+func (s *TelegramInboxTestSuite) TestDeleteTelegramLink() {
+	s.Run("when user is missing", func() {
+		h := s.handler()
+		h.DeleteTelegramLink(s.ctx)
+
+		s.Equal(http.StatusForbidden, s.w.Code)
+		s.store.AssertExpectations(s.T())
+	})
+
+	s.Run("when storage returns error", func() {
+		s.ctx.Set("me", storage.User{ID: 1})
+		s.store.On("UnlinkTelegramAccount", mock.Anything).Return(errors.New("storage error")).Once()
+
+		h := s.handler()
+		h.DeleteTelegramLink(s.ctx)
+
+		s.Equal(http.StatusBadRequest, s.w.Code)
+		s.store.AssertExpectations(s.T())
+	})
+
+	s.Run("when account is unlinked", func() {
+		s.ctx.Set("me", storage.User{ID: 1})
+		s.store.On("UnlinkTelegramAccount", mock.Anything).Return(nil).Once()
+
+		h := s.handler()
+		h.DeleteTelegramLink(s.ctx)
+
+		s.Equal(http.StatusOK, s.w.Code)
+		s.Contains(s.w.Body.String(), `"telegramLinked":false`)
+		s.store.AssertExpectations(s.T())
+	})
+}
+
+func (s *TelegramInboxTestSuite) TestTelegramPosts() {
+	chat := &tgbotapi.Chat{ID: 1, Type: "private"}
+	updates := []tgbotapi.Update{
+		{UpdateID: 1, Message: &tgbotapi.Message{MessageID: 1, Chat: chat, MediaGroupID: "album"}},
+		{UpdateID: 2, Message: &tgbotapi.Message{MessageID: 2, Chat: chat, MediaGroupID: "album"}},
+		{UpdateID: 3},
+		{UpdateID: 4, Message: &tgbotapi.Message{MessageID: 3, Chat: chat}},
+		{UpdateID: 5, Message: &tgbotapi.Message{MessageID: 4, Chat: chat, MediaGroupID: "other"}},
+	}
+
+	posts := telegramPosts(updates)
+
+	s.Len(posts, 3)
+	s.Len(posts[0], 2)
+	s.Equal(3, posts[1][0].MessageID)
+	s.Equal(4, posts[2][0].MessageID)
+}
+
+func (s *TelegramInboxTestSuite) TestHandleTelegramMessages() {
+	user := storage.User{ID: 1, Email: "user@systemli.org", TelegramTickerID: 1}
+	ticker := storage.Ticker{ID: 1, Title: "Ticker"}
+	tickerUser := storage.TickerUser{TickerID: 1, UserID: 1, Role: storage.TickerRoleEditor}
+
+	s.Run("when chat is not private", func() {
+		message := s.message("text")
+		message.Chat.Type = "group"
+
+		h := s.handler()
+		h.handleTelegramMessages(s.bot(), []*tgbotapi.Message{message})
+
+		s.store.AssertExpectations(s.T())
+	})
+
+	s.Run("when link is expired", func() {
+		s.store.On("FindTelegramLinkByCode", "code").Return(storage.TelegramLink{UserID: 1, CreatedAt: time.Now().Add(-time.Hour)}, nil).Once()
+		s.expectReply("not valid anymore")
+
+		h := s.handler()
+		h.handleTelegramMessages(s.bot(), []*tgbotapi.Message{s.command("/start code")})
+
+		s.store.AssertExpectations(s.T())
+		s.True(gock.IsDone())
+	})
+
+	s.Run("when account is linked", func() {
+		link := storage.TelegramLink{UserID: 1, CreatedAt: time.Now()}
+		s.store.On("FindTelegramLinkByCode", "code").Return(link, nil).Once()
+		s.store.On("LinkTelegramAccount", link, int64(42)).Return(nil).Once()
+		s.expectReply("is linked")
+
+		h := s.handler()
+		h.handleTelegramMessages(s.bot(), []*tgbotapi.Message{s.command("/start code")})
+
+		s.store.AssertExpectations(s.T())
+		s.True(gock.IsDone())
+	})
+
+	s.Run("when account is not linked", func() {
+		s.store.On("FindUserByTelegramID", int64(42)).Return(storage.User{}, errors.New("not found")).Once()
+		s.expectReply("not linked")
+
+		h := s.handler()
+		h.handleTelegramMessages(s.bot(), []*tgbotapi.Message{s.message("text")})
+
+		s.store.AssertExpectations(s.T())
+		s.True(gock.IsDone())
+	})
+
+	s.Run("when tickers are listed", func() {
+		s.store.On("FindUserByTelegramID", int64(42)).Return(user, nil).Once()
+		s.store.On("FindTickersByUser", user, mock.Anything, mock.Anything).Return([]storage.Ticker{ticker}, nil).Once()
+		s.store.On("FindTickerUser", ticker, user).Return(tickerUser, nil).Once()
+		s.expectReply("1: Ticker (chosen)")
+
+		h := s.handler()
+		h.handleTelegramMessages(s.bot(), []*tgbotapi.Message{s.command("/tickers")})
+
+		s.store.AssertExpectations(s.T())
+		s.True(gock.IsDone())
+	})
+
+	s.Run("when ticker is chosen", func() {
+		unchosen := user
+		unchosen.TelegramTickerID = 0
+		s.store.On("FindUserByTelegramID", int64(42)).Return(unchosen, nil).Once()
+		s.store.On("FindTickersByUser", unchosen, mock.Anything, mock.Anything).Return([]storage.Ticker{ticker}, nil).Once()
+		s.store.On("FindTickerUser", ticker, unchosen).Return(tickerUser, nil).Once()
+		s.store.On("SaveTelegramTicker", mock.MatchedBy(func(u *storage.User) bool {
+			return u.TelegramTickerID == 1
+		})).Return(nil).Once()
+		s.expectReply("posted to Ticker")
+
+		h := s.handler()
+		h.handleTelegramMessages(s.bot(), []*tgbotapi.Message{s.command("/ticker 1")})
+
+		s.store.AssertExpectations(s.T())
+		s.True(gock.IsDone())
+	})
+
+	s.Run("when account is unlinked", func() {
+		s.store.On("FindUserByTelegramID", int64(42)).Return(user, nil).Once()
+		s.store.On("UnlinkTelegramAccount", mock.Anything).Return(nil).Once()
+		s.expectReply("is unlinked")
+
+		h := s.handler()
+		h.handleTelegramMessages(s.bot(), []*tgbotapi.Message{s.command("/stop")})
+
+		s.store.AssertExpectations(s.T())
+		s.True(gock.IsDone())
+	})
+
+	s.Run("when user may not post to the ticker", func() {
+		s.store.On("FindUserByTelegramID", int64(42)).Return(user, nil).Once()
+		s.store.On("FindTickersByUser", user, mock.Anything, mock.Anything).Return([]storage.Ticker{ticker}, nil).Once()
+		s.store.On("FindTickerUser", ticker, user).Return(storage.TickerUser{Role: storage.TickerRoleViewer}, nil).Once()
+		s.expectReply("cannot post to any ticker")
+
+		h := s.handler()
+		h.handleTelegramMessages(s.bot(), []*tgbotapi.Message{s.message("text")})
+
+		s.store.AssertExpectations(s.T())
+		s.True(gock.IsDone())
+	})
+
+	s.Run("when linked user did not sign in recently", func() {
+		linked := user
+		linked.OIDCSubject = "subject"
+		linked.LastLogin = time.Now().Add(-storage.LinkedAPITokenLifetime - time.Hour)
+		s.store.On("FindUserByTelegramID", int64(42)).Return(linked, nil).Once()
+		s.expectReply("Sign in to the admin interface")
+
+		h := s.handler()
+		h.sso = sso.New(config.OIDC{Issuer: "http://127.0.0.1:1"}, s.store)
+		h.handleTelegramMessages(s.bot(), []*tgbotapi.Message{s.message("text")})
+
+		s.store.AssertExpectations(s.T())
+		s.True(gock.IsDone())
+	})
+
+	s.Run("when message has no text", func() {
+		message := s.message("")
+		message.Photo = []tgbotapi.PhotoSize{{FileID: "photo"}}
+		s.store.On("FindUserByTelegramID", int64(42)).Return(user, nil).Once()
+		s.store.On("FindTickersByUser", user, mock.Anything, mock.Anything).Return([]storage.Ticker{ticker}, nil).Once()
+		s.store.On("FindTickerUser", ticker, user).Return(tickerUser, nil).Once()
+		s.expectReply("needs a text")
+
+		h := s.handler()
+		h.handleTelegramMessages(s.bot(), []*tgbotapi.Message{message})
+
+		s.store.AssertExpectations(s.T())
+		s.True(gock.IsDone())
+	})
+
+	s.Run("when editor posts a message", func() {
+		s.store.On("FindUserByTelegramID", int64(42)).Return(user, nil).Once()
+		s.store.On("FindTickersByUser", user, mock.Anything, mock.Anything).Return([]storage.Ticker{ticker}, nil).Once()
+		s.store.On("FindTickerUser", ticker, user).Return(tickerUser, nil).Once()
+		s.store.On("SaveMessage", mock.MatchedBy(func(m *storage.Message) bool {
+			return m.Text == "Hello #world" && m.TickerID == 1 && !m.Draft
+		})).Return(nil).Once()
+		s.store.On("SaveMessageRevision", mock.Anything).Return(nil).Once()
+		s.store.On("SaveMessageTags", mock.Anything, []string{"world"}).Return(nil).Once()
+		s.expectReply("is published on Ticker")
+
+		h := s.handler()
+		h.handleTelegramMessages(s.bot(), []*tgbotapi.Message{s.message("Hello #world")})
+
+		s.store.AssertExpectations(s.T())
+		s.True(gock.IsDone())
+	})
+
+	s.Run("when contributor posts a message", func() {
+		s.store.On("FindUserByTelegramID", int64(42)).Return(user, nil).Once()
+		s.store.On("FindTickersByUser", user, mock.Anything, mock.Anything).Return([]storage.Ticker{ticker}, nil).Once()
+		s.store.On("FindTickerUser", ticker, user).Return(storage.TickerUser{Role: storage.TickerRoleContributor}, nil).Once()
+		s.store.On("SaveMessage", mock.MatchedBy(func(m *storage.Message) bool {
+			return m.Draft && m.Review == storage.ReviewPending
+		})).Return(nil).Once()
+		s.store.On("SaveMessageRevision", mock.Anything).Return(nil).Once()
+		s.store.On("SaveMessageTags", mock.Anything, mock.Anything).Return(nil).Once()
+		s.expectReply("waits for a review")
+
+		h := s.handler()
+		h.handleTelegramMessages(s.bot(), []*tgbotapi.Message{s.message("text")})
+
+		s.store.AssertExpectations(s.T())
+		s.True(gock.IsDone())
+	})
+
+	s.Run("when album is posted", func() {
+		photo, err := os.ReadFile("../../testdata/gopher.jpg")
+		s.NoError(err)
+
+		first := s.message("")
+		first.Caption = "Photos"
+		first.MediaGroupID = "album"
+		first.Photo = []tgbotapi.PhotoSize{{FileID: "small"}, {FileID: "large"}}
+		second := s.message("")
+		second.MediaGroupID = "album"
+		second.Photo = []tgbotapi.PhotoSize{{FileID: "large"}}
+
+		s.store.On("FindUserByTelegramID", int64(42)).Return(user, nil).Once()
+		s.store.On("FindTickersByUser", user, mock.Anything, mock.Anything).Return([]storage.Ticker{ticker}, nil).Once()
+		s.store.On("FindTickerUser", ticker, user).Return(tickerUser, nil).Once()
+		s.store.On("SaveUpload", mock.MatchedBy(func(u *storage.Upload) bool {
+			return u.TickerID == 1 && u.ContentType == "image/jpeg"
+		})).Return(nil).Twice()
+		s.store.On("SaveMessage", mock.MatchedBy(func(m *storage.Message) bool {
+			return m.Text == "Photos" && len(m.Attachments) == 2
+		})).Return(nil).Once()
+		s.store.On("SaveMessageRevision", mock.Anything).Return(nil).Once()
+		s.store.On("SaveMessageTags", mock.Anything, mock.Anything).Return(nil).Once()
+		gock.New("https://api.telegram.org").
+			Post("/bot" + telegramTestToken + "/getFile").
+			Times(2).
+			Reply(http.StatusOK).
+			JSON(map[string]any{"ok": true, "result": map[string]any{"file_id": "large", "file_path": "photos/large.jpg"}})
+		gock.New("https://api.telegram.org").
+			Get("/file/bot" + telegramTestToken + "/photos/large.jpg").
+			Times(2).
+			Reply(http.StatusOK).
+			Body(bytes.NewReader(photo))
+		s.expectReply("is published on Ticker")
+
+		h := s.handler()
+		h.handleTelegramMessages(s.bot(), []*tgbotapi.Message{first, second})
+
+		s.store.AssertExpectations(s.T())
+		s.True(gock.IsDone())
+	})
+}
+
+func (s *TelegramInboxTestSuite) TestRun() {
+	s.Run("when inbound is disabled", func() {
+		s.store.On("GetTelegramSettings").Return(storage.TelegramSettings{Token: telegramTestToken})
+		h := s.handler()
+		inbox := &TelegramInbox{handler: &h, idle: time.Millisecond, client: &http.Client{}}
+
+		ctx, cancel := context.WithCancel(context.Background())
+		done := make(chan struct{})
+		go func() {
+			inbox.Run(ctx)
+			close(done)
+		}()
+
+		time.Sleep(10 * time.Millisecond)
+		cancel()
+
+		select {
+		case <-done:
+		case <-time.After(time.Second):
+			s.Fail("inbox did not stop")
+		}
+		s.True(gock.IsDone())
+	})
+}
+
+// bot returns a bot talking to the mocked Telegram API.
+func (s *TelegramInboxTestSuite) bot() *tgbotapi.BotAPI {
+	gock.New("https://api.telegram.org").
+		Post("/bot" + telegramTestToken + "/getMe").
+		Reply(http.StatusOK).
+		JSON(map[string]any{"ok": true, "result": map[string]any{"id": 1, "is_bot": true, "username": "ticker_bot"}})
+
+	bot, err := tgbotapi.NewBotAPIWithClient(telegramTestToken, tgbotapi.APIEndpoint, &http.Client{})
+	s.Require().NoError(err)
+
+	return bot
+}
+
+func (s *TelegramInboxTestSuite) expectReply(text string) {
+	gock.New("https://api.telegram.org").
+		Post("/bot" + telegramTestToken + "/sendMessage").
+		BodyString(regexp.QuoteMeta(url.QueryEscape(text))).
+		Reply(http.StatusOK).
+		JSON(map[string]any{"ok": true, "result": map[string]any{"message_id": 1, "chat": map[string]any{"id": 42}}})
+}
+
+func (s *TelegramInboxTestSuite) message(text string) *tgbotapi.Message {
+	return &tgbotapi.Message{
+		MessageID: 1,
+		From:      &tgbotapi.User{ID: 42},
+		Chat:      &tgbotapi.Chat{ID: 42, Type: "private"},
+		Text:      text,
+	}
+}
+
+func (s *TelegramInboxTestSuite) command(text string) *tgbotapi.Message {
+	message := s.message(text)
+	length := len(text)
+	for i, r := range text {
+		if r == ' ' {
+			length = i
+			break
+		}
+	}
+	message.Entities = []tgbotapi.MessageEntity{{Type: "bot_command", Offset: 0, Length: length}}
+
+	return message
+}
+
+func (s *TelegramInboxTestSuite) handler() handler {
+	return handler{
+		storage:  s.store,
+		config:   s.cfg,
+		realtime: realtime.New(),
+	}
+}
+
+func TestTelegramInboxTestSuite(t *testing.T) {
+	suite.Run(t, new(TelegramInboxTestSuite))
+}
@@ -3,6 +3,7 @@ package api
 import (
 	"fmt"
 	"net/http"
+	"regexp"
 	"strconv"
 	"strings"
 
@@ -11,10 +12,16 @@ import (
 	"github.com/systemli/ticker/internal/api/helper"
 	"github.com/systemli/ticker/internal/api/response"
 	"github.com/systemli/ticker/internal/bluesky"
+	"github.com/systemli/ticker/internal/matrix"
 	"github.com/systemli/ticker/internal/signal"
 	"github.com/systemli/ticker/internal/storage"
+	"github.com/systemli/ticker/internal/util"
 )
 
+// phoneNumberPattern matches phone numbers in the international format Signal
+// identifies accounts by (E.164).
+var phoneNumberPattern = regexp.MustCompile(`^\+[1-9][0-9]{6,14}$`)
+
 type TickerParam struct {
 	Title       string `json:"title" binding:"required"`
 	Description string `json:"description"`
@@ -44,7 +51,8 @@ type TickerLocationParam struct {
 
 type TickerUsersParam struct {
 	Users []struct {
-		ID int `json:"id"`
+		ID   int    `json:"id"`
+		Role string `json:"role"`
 	} `json:"users" binding:"required"`
 }
 
@@ -57,6 +65,28 @@ type TickerWebsiteParam struct {
 	Origin string `json:"origin" binding:"required"`
 }
 
+type TickerWebhooksParam struct {
+	Webhooks []TickerWebhookParam `json:"webhooks" binding:"required"`
+}
+
+// TickerWebhookParam is a webhook to create, or to update when it has an ID.
+// The secret can be left out for existing webhooks to keep it.
+type TickerWebhookParam struct {
+	ID     int    `json:"id"`
+	URL    string `json:"url" binding:"required"`
+	Secret string `json:"secret"`
+}
+
+type TickerSignalSendersParam struct {
+	SignalSenders []TickerSignalSenderParam `json:"signalSenders" binding:"required"`
+}
+
+type TickerSignalSenderParam struct {
+	ID     int    `json:"id"`
+	Number string `json:"number" binding:"required"`
+	Name   string `json:"name"`
+}
+
 func (h *handler) GetTickers(c *gin.Context) {
 	me, err := helper.Me(c)
 	if err != nil {
@@ -92,8 +122,9 @@ func (h *handler) GetTickerUsers(c *gin.Context) {
 	}
 
 	users, _ := h.storage.FindUsersByTicker(ticker)
+	tickerUsers, _ := h.storage.FindTickerUsers(ticker)
 
-	c.JSON(http.StatusOK, response.SuccessResponse(map[string]interface{}{"users": response.UsersResponse(users)}))
+	c.JSON(http.StatusOK, response.SuccessResponse(map[string]interface{}{"users": response.TickerUsersResponse(users, tickerUsers)}))
 }
 
 func (h *handler) PostTicker(c *gin.Context) {
@@ -144,6 +175,8 @@ func (h *handler) PutTicker(c *gin.Context) {
 	c.JSON(http.StatusOK, response.SuccessResponse(map[string]interface{}{"ticker": response.TickerResponse(ticker, h.getBotUsername())}))
 }
 
+// PutTickerUsers replaces the members of a ticker. Members without a role
+// become editors.
 func (h *handler) PutTickerUsers(c *gin.Context) {
 	ticker, err := helper.Ticker(c)
 	if err != nil {
@@ -159,8 +192,19 @@ func (h *handler) PutTickerUsers(c *gin.Context) {
 	}
 
 	userIds := make([]int, 0)
+	roles := make(map[int]string)
 	for _, user := range body.Users {
+		role := user.Role
+		if role == "" {
+			role = storage.TickerRoleEditor
+		}
+		if !storage.IsValidTickerRole(role) {
+			c.JSON(http.StatusBadRequest, response.ErrorResponse(response.CodeDefault, response.FormError))
+			return
+		}
+
 		userIds = append(userIds, user.ID)
+		roles[user.ID] = role
 	}
 	users, err := h.storage.FindUsersByIDs(userIds)
 	if err != nil {
@@ -168,14 +212,18 @@ func (h *handler) PutTickerUsers(c *gin.Context) {
 		return
 	}
 
-	ticker.Users = users
-	err = h.storage.SaveTicker(&ticker)
+	tickerUsers := make([]storage.TickerUser, 0, len(users))
+	for _, user := range users {
+		tickerUsers = append(tickerUsers, storage.TickerUser{TickerID: ticker.ID, UserID: user.ID, Role: roles[user.ID]})
+	}
+
+	err = h.storage.SaveTickerUsers(&ticker, tickerUsers)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, response.ErrorResponse(response.CodeDefault, response.StorageError))
 		return
 	}
 
-	c.JSON(http.StatusOK, response.SuccessResponse(map[string]interface{}{"users": response.UsersResponse(ticker.Users)}))
+	c.JSON(http.StatusOK, response.SuccessResponse(map[string]interface{}{"users": response.TickerUsersResponse(users, tickerUsers)}))
 }
 
 func (h *handler) PutTickerWebsites(c *gin.Context) {
@@ -277,6 +325,147 @@ func (h *handler) DeleteTickerTelegram(c *gin.Context) {
 	c.JSON(http.StatusOK, response.SuccessResponse(map[string]interface{}{"ticker": response.TickerResponse(ticker, h.getBotUsername())}))
 }
 
+func (h *handler) PutTickerWebhooks(c *gin.Context) {
+	ticker, err := helper.Ticker(c)
+	if err != nil {
+		c.JSON(http.StatusNotFound, response.ErrorResponse(response.CodeDefault, response.TickerNotFound))
+		return
+	}
+
+	var body TickerWebhooksParam
+	err = c.Bind(&body)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, response.ErrorResponse(response.CodeDefault, response.FormError))
+		return
+	}
+
+	existing := make(map[int]storage.TickerWebhook)
+	for _, webhook := range ticker.Webhooks {
+		existing[webhook.ID] = webhook
+	}
+
+	webhooks := make([]storage.TickerWebhook, 0)
+	for _, param := range body.Webhooks {
+		webhook := storage.TickerWebhook{URL: param.URL, Secret: param.Secret}
+		if param.ID != 0 {
+			current, ok := existing[param.ID]
+			if !ok {
+				c.JSON(http.StatusBadRequest, response.ErrorResponse(response.CodeDefault, response.WebhookInvalid))
+				return
+			}
+			webhook.ID = current.ID
+			webhook.CreatedAt = current.CreatedAt
+			if webhook.Secret == "" {
+				webhook.Secret = current.Secret
+			}
+		}
+
+		if !util.IsPublicURL(webhook.URL, "http", "https") || webhook.Secret == "" {
+			c.JSON(http.StatusBadRequest, response.ErrorResponse(response.CodeDefault, response.WebhookInvalid))
+			return
+		}
+
+		webhooks = append(webhooks, webhook)
+	}
+
+	if len(webhooks) == 0 {
+		err = h.storage.DeleteTickerWebhooks(&ticker)
+	} else {
+		err = h.storage.SaveTickerWebhooks(&ticker, webhooks)
+	}
+	if err != nil {
+		c.JSON(http.StatusBadRequest, response.ErrorResponse(response.CodeDefault, response.StorageError))
+		return
+	}
+
+	c.JSON(http.StatusOK, response.SuccessResponse(map[string]interface{}{"ticker": response.TickerResponse(ticker, h.getBotUsername())}))
+}
+
+func (h *handler) DeleteTickerWebhooks(c *gin.Context) {
+	ticker, err := helper.Ticker(c)
+	if err != nil {
+		c.JSON(http.StatusNotFound, response.ErrorResponse(response.CodeDefault, response.TickerNotFound))
+		return
+	}
+
+	err = h.storage.DeleteTickerWebhooks(&ticker)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, response.ErrorResponse(response.CodeDefault, response.StorageError))
+		return
+	}
+
+	c.JSON(http.StatusOK, response.SuccessResponse(map[string]interface{}{"ticker": response.TickerResponse(ticker, h.getBotUsername())}))
+}
+
+func (h *handler) PutTickerSignalSenders(c *gin.Context) {
+	ticker, err := helper.Ticker(c)
+	if err != nil {
+		c.JSON(http.StatusNotFound, response.ErrorResponse(response.CodeDefault, response.TickerNotFound))
+		return
+	}
+
+	var body TickerSignalSendersParam
+	err = c.Bind(&body)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, response.ErrorResponse(response.CodeDefault, response.FormError))
+		return
+	}
+
+	existing := make(map[int]storage.TickerSignalSender)
+	for _, sender := range ticker.SignalSenders {
+		existing[sender.ID] = sender
+	}
+
+	senders := make([]storage.TickerSignalSender, 0)
+	for _, param := range body.SignalSenders {
+		sender := storage.TickerSignalSender{Number: strings.ReplaceAll(param.Number, " ", ""), Name: param.Name}
+		if param.ID != 0 {
+			current, ok := existing[param.ID]
+			if !ok {
+				c.JSON(http.StatusBadRequest, response.ErrorResponse(response.CodeDefault, response.SignalSenderInvalid))
+				return
+			}
+			sender.ID = current.ID
+			sender.CreatedAt = current.CreatedAt
+		}
+
+		if !phoneNumberPattern.MatchString(sender.Number) {
+			c.JSON(http.StatusBadRequest, response.ErrorResponse(response.CodeDefault, response.SignalSenderInvalid))
+			return
+		}
+
+		senders = append(senders, sender)
+	}
+
+	if len(senders) == 0 {
+		err = h.storage.DeleteTickerSignalSenders(&ticker)
+	} else {
+		err = h.storage.SaveTickerSignalSenders(&ticker, senders)
+	}
+	if err != nil {
+		c.JSON(http.StatusBadRequest, response.ErrorResponse(response.CodeDefault, response.StorageError))
+		return
+	}
+
+	c.JSON(http.StatusOK, response.SuccessResponse(map[string]interface{}{"ticker": response.TickerResponse(ticker, h.getBotUsername())}))
+}
+
+func (h *handler) DeleteTickerSignalSenders(c *gin.Context) {
+	ticker, err := helper.Ticker(c)
+	if err != nil {
+		c.JSON(http.StatusNotFound, response.ErrorResponse(response.CodeDefault, response.TickerNotFound))
+		return
+	}
+
+	err = h.storage.DeleteTickerSignalSenders(&ticker)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, response.ErrorResponse(response.CodeDefault, response.StorageError))
+		return
+	}
+
+	c.JSON(http.StatusOK, response.SuccessResponse(map[string]interface{}{"ticker": response.TickerResponse(ticker, h.getBotUsername())}))
+}
+
 func (h *handler) PutTickerMastodon(c *gin.Context) {
 	ticker, err := helper.Ticker(c)
 	if err != nil {
@@ -395,6 +584,76 @@ func (h *handler) DeleteTickerBluesky(c *gin.Context) {
 	c.JSON(http.StatusOK, response.SuccessResponse(map[string]interface{}{"ticker": response.TickerResponse(ticker, h.getBotUsername())}))
 }
 
+// PutTickerMatrix connects the ticker to a Matrix room. The room can be given
+// by its ID or an alias, the account of the access token joins it if needed.
+func (h *handler) PutTickerMatrix(c *gin.Context) {
+	ticker, err := helper.Ticker(c)
+	if err != nil {
+		c.JSON(http.StatusNotFound, response.ErrorResponse(response.CodeDefault, response.TickerNotFound))
+		return
+	}
+
+	var body storage.TickerMatrix
+	err = c.Bind(&body)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, response.ErrorResponse(response.CodeNotFound, response.FormError))
+		return
+	}
+
+	if body.Homeserver != "" || body.AccessToken != "" || body.RoomID != "" {
+		if !util.IsPublicURL(body.Homeserver, "https") {
+			c.JSON(http.StatusBadRequest, response.ErrorResponse(response.CodeDefault, response.MatrixInvalid))
+			return
+		}
+
+		client := matrix.NewClient(body.Homeserver, body.AccessToken)
+
+		userID, err := client.WhoAmI(c.Request.Context())
+		if err != nil {
+			c.JSON(http.StatusBadRequest, response.ErrorResponse(response.CodeBadCredentials, response.MatrixError))
+			return
+		}
+
+		roomID, err := client.JoinRoom(c.Request.Context(), body.RoomID)
+		if err != nil {
+			log.WithError(err).Error("failed to join matrix room")
+			c.JSON(http.StatusBadRequest, response.ErrorResponse(response.CodeBadCredentials, response.MatrixError))
+			return
+		}
+
+		ticker.Matrix.Homeserver = client.Homeserver
+		ticker.Matrix.AccessToken = body.AccessToken
+		ticker.Matrix.UserID = userID
+		ticker.Matrix.RoomID = roomID
+	}
+
+	ticker.Matrix.Active = body.Active
+
+	err = h.storage.SaveTicker(&ticker)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, response.ErrorResponse(response.CodeDefault, response.StorageError))
+		return
+	}
+
+	c.JSON(http.StatusOK, response.SuccessResponse(map[string]interface{}{"ticker": response.TickerResponse(ticker, h.getBotUsername())}))
+}
+
+func (h *handler) DeleteTickerMatrix(c *gin.Context) {
+	ticker, err := helper.Ticker(c)
+	if err != nil {
+		c.JSON(http.StatusNotFound, response.ErrorResponse(response.CodeDefault, response.TickerNotFound))
+		return
+	}
+
+	err = h.storage.DeleteMatrix(&ticker)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, response.ErrorResponse(response.CodeDefault, response.StorageError))
+		return
+	}
+
+	c.JSON(http.StatusOK, response.SuccessResponse(map[string]interface{}{"ticker": response.TickerResponse(ticker, h.getBotUsername())}))
+}
+
 func (h *handler) PutTickerSignalGroup(c *gin.Context) {
 	ticker, err := helper.Ticker(c)
 	if err != nil {
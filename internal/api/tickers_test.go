@@ -18,6 +18,7 @@ import (
 	"github.com/stretchr/testify/suite"
 	"github.com/systemli/ticker/internal/cache"
 	"github.com/systemli/ticker/internal/config"
+	"github.com/systemli/ticker/internal/matrix"
 	"github.com/systemli/ticker/internal/storage"
 )
 
@@ -30,6 +31,14 @@ type TickerTestSuite struct {
 	suite.Suite
 }
 
+func (s *TickerTestSuite) SetupSuite() {
+	gock.InterceptClient(matrix.HTTPClient)
+}
+
+func (s *TickerTestSuite) TearDownSuite() {
+	gock.RestoreClient(matrix.HTTPClient)
+}
+
 func (s *TickerTestSuite) SetupTest() {
 	gin.SetMode(gin.TestMode)
 	gock.DisableNetworking()
@@ -117,11 +126,13 @@ func (s *TickerTestSuite) TestGetTickerUsers() {
 
 	s.Run("when ticker found", func() {
 		s.ctx.Set("ticker", storage.Ticker{})
-		s.store.On("FindUsersByTicker", mock.Anything, mock.Anything).Return([]storage.User{}, nil).Once()
+		s.store.On("FindUsersByTicker", mock.Anything, mock.Anything).Return([]storage.User{{ID: 1}}, nil).Once()
+		s.store.On("FindTickerUsers", mock.Anything).Return([]storage.TickerUser{{UserID: 1, Role: storage.TickerRoleOwner}}, nil).Once()
 		h := s.handler()
 		h.GetTickerUsers(s.ctx)
 
 		s.Equal(http.StatusOK, s.w.Code)
+		s.Contains(s.w.Body.String(), `"role":"owner"`)
 		s.store.AssertExpectations(s.T())
 	})
 }
@@ -294,7 +305,7 @@ func (s *TickerTestSuite) TestPutTickerUsers() {
 		s.ctx.Request = httptest.NewRequest(http.MethodPut, "/v1/admin/tickers/1/user", strings.NewReader(body))
 		s.ctx.Request.Header.Add("Content-Type", "application/json")
 		s.store.On("FindUsersByIDs", mock.Anything).Return([]storage.User{{ID: 1}, {ID: 2}, {ID: 3}}, nil).Once()
-		s.store.On("SaveTicker", mock.Anything).Return(errors.New("storage error")).Once()
+		s.store.On("SaveTickerUsers", mock.Anything, mock.Anything).Return(errors.New("storage error")).Once()
 		h := s.handler()
 		h.PutTickerUsers(s.ctx)
 
@@ -302,13 +313,29 @@ func (s *TickerTestSuite) TestPutTickerUsers() {
 		s.store.AssertExpectations(s.T())
 	})
 
-	s.Run("when storage returns ticker", func() {
+	s.Run("when role is invalid", func() {
 		s.ctx.Set("ticker", storage.Ticker{})
-		body := `{"users":[{"id":1},{"id":2},{"id":3}]}`
+		body := `{"users":[{"id":1,"role":"admin"}]}`
+		s.ctx.Request = httptest.NewRequest(http.MethodPut, "/v1/admin/tickers/1/user", strings.NewReader(body))
+		s.ctx.Request.Header.Add("Content-Type", "application/json")
+		h := s.handler()
+		h.PutTickerUsers(s.ctx)
+
+		s.Equal(http.StatusBadRequest, s.w.Code)
+		s.store.AssertExpectations(s.T())
+	})
+
+	s.Run("when storage returns ticker", func() {
+		s.ctx.Set("ticker", storage.Ticker{ID: 1})
+		body := `{"users":[{"id":1,"role":"owner"},{"id":2,"role":"viewer"},{"id":3}]}`
 		s.ctx.Request = httptest.NewRequest(http.MethodPut, "/v1/admin/tickers/1/user", strings.NewReader(body))
 		s.ctx.Request.Header.Add("Content-Type", "application/json")
 		s.store.On("FindUsersByIDs", mock.Anything).Return([]storage.User{{ID: 1}, {ID: 2}, {ID: 3}}, nil).Once()
-		s.store.On("SaveTicker", mock.Anything).Return(nil).Once()
+		s.store.On("SaveTickerUsers", mock.Anything, []storage.TickerUser{
+			{TickerID: 1, UserID: 1, Role: storage.TickerRoleOwner},
+			{TickerID: 1, UserID: 2, Role: storage.TickerRoleViewer},
+			{TickerID: 1, UserID: 3, Role: storage.TickerRoleEditor},
+		}).Return(nil).Once()
 		h := s.handler()
 		h.PutTickerUsers(s.ctx)
 
@@ -767,6 +794,413 @@ func (s *TickerTestSuite) TestDeleteTickerBluesky() {
 	})
 }
 
+func (s *TickerTestSuite) TestPutTickerWebhooks() {
+	s.Run("when ticker not found", func() {
+		h := s.handler()
+		h.PutTickerWebhooks(s.ctx)
+
+		s.Equal(http.StatusNotFound, s.w.Code)
+		s.store.AssertExpectations(s.T())
+	})
+
+	s.Run("when body is invalid", func() {
+		s.ctx.Set("ticker", storage.Ticker{})
+		s.ctx.Request = httptest.NewRequest(http.MethodPut, "/v1/admin/tickers/1/webhooks", nil)
+		s.ctx.Request.Header.Add("Content-Type", "application/json")
+		h := s.handler()
+		h.PutTickerWebhooks(s.ctx)
+
+		s.Equal(http.StatusBadRequest, s.w.Code)
+		s.store.AssertExpectations(s.T())
+	})
+
+	s.Run("when url is invalid", func() {
+		s.ctx.Set("ticker", storage.Ticker{})
+		body := `{"webhooks":[{"url":"ftp://hooks.example.org","secret":"secret"}]}`
+		s.ctx.Request = httptest.NewRequest(http.MethodPut, "/v1/admin/tickers/1/webhooks", strings.NewReader(body))
+		s.ctx.Request.Header.Add("Content-Type", "application/json")
+		h := s.handler()
+		h.PutTickerWebhooks(s.ctx)
+
+		s.Equal(http.StatusBadRequest, s.w.Code)
+		s.store.AssertExpectations(s.T())
+	})
+
+	s.Run("when url points to an internal address", func() {
+		s.ctx.Set("ticker", storage.Ticker{})
+		body := `{"webhooks":[{"url":"http://localhost:8080/hook","secret":"secret"}]}`
+		s.ctx.Request = httptest.NewRequest(http.MethodPut, "/v1/admin/tickers/1/webhooks", strings.NewReader(body))
+		s.ctx.Request.Header.Add("Content-Type", "application/json")
+		h := s.handler()
+		h.PutTickerWebhooks(s.ctx)
+
+		s.Equal(http.StatusBadRequest, s.w.Code)
+		s.store.AssertExpectations(s.T())
+	})
+
+	s.Run("when secret is missing for a new webhook", func() {
+		s.ctx.Set("ticker", storage.Ticker{})
+		body := `{"webhooks":[{"url":"https://hooks.example.org"}]}`
+		s.ctx.Request = httptest.NewRequest(http.MethodPut, "/v1/admin/tickers/1/webhooks", strings.NewReader(body))
+		s.ctx.Request.Header.Add("Content-Type", "application/json")
+		h := s.handler()
+		h.PutTickerWebhooks(s.ctx)
+
+		s.Equal(http.StatusBadRequest, s.w.Code)
+		s.store.AssertExpectations(s.T())
+	})
+
+	s.Run("when webhook belongs to another ticker", func() {
+		s.ctx.Set("ticker", storage.Ticker{})
+		body := `{"webhooks":[{"id":2,"url":"https://hooks.example.org"}]}`
+		s.ctx.Request = httptest.NewRequest(http.MethodPut, "/v1/admin/tickers/1/webhooks", strings.NewReader(body))
+		s.ctx.Request.Header.Add("Content-Type", "application/json")
+		h := s.handler()
+		h.PutTickerWebhooks(s.ctx)
+
+		s.Equal(http.StatusBadRequest, s.w.Code)
+		s.store.AssertExpectations(s.T())
+	})
+
+	s.Run("when webhooks are empty", func() {
+		s.ctx.Set("ticker", storage.Ticker{})
+		body := `{"webhooks":[]}`
+		s.ctx.Request = httptest.NewRequest(http.MethodPut, "/v1/admin/tickers/1/webhooks", strings.NewReader(body))
+		s.ctx.Request.Header.Add("Content-Type", "application/json")
+		s.store.On("DeleteTickerWebhooks", mock.Anything).Return(nil).Once()
+		h := s.handler()
+		h.PutTickerWebhooks(s.ctx)
+
+		s.Equal(http.StatusOK, s.w.Code)
+		s.store.AssertExpectations(s.T())
+	})
+
+	s.Run("when storage returns error", func() {
+		s.ctx.Set("ticker", storage.Ticker{})
+		body := `{"webhooks":[{"url":"https://hooks.example.org","secret":"secret"}]}`
+		s.ctx.Request = httptest.NewRequest(http.MethodPut, "/v1/admin/tickers/1/webhooks", strings.NewReader(body))
+		s.ctx.Request.Header.Add("Content-Type", "application/json")
+		s.store.On("SaveTickerWebhooks", mock.Anything, mock.Anything).Return(errors.New("storage error")).Once()
+		h := s.handler()
+		h.PutTickerWebhooks(s.ctx)
+
+		s.Equal(http.StatusBadRequest, s.w.Code)
+		s.store.AssertExpectations(s.T())
+	})
+
+	s.Run("when existing webhook keeps its secret", func() {
+		s.ctx.Set("ticker", storage.Ticker{Webhooks: []storage.TickerWebhook{{ID: 2, URL: "https://hooks.example.org", Secret: "secret"}}})
+		body := `{"webhooks":[{"id":2,"url":"https://hooks.example.org/new"},{"url":"https://other.example.org","secret":"other"}]}`
+		s.ctx.Request = httptest.NewRequest(http.MethodPut, "/v1/admin/tickers/1/webhooks", strings.NewReader(body))
+		s.ctx.Request.Header.Add("Content-Type", "application/json")
+		s.store.On("SaveTickerWebhooks", mock.Anything, []storage.TickerWebhook{
+			{ID: 2, URL: "https://hooks.example.org/new", Secret: "secret"},
+			{URL: "https://other.example.org", Secret: "other"},
+		}).Return(nil).Once()
+		h := s.handler()
+		h.PutTickerWebhooks(s.ctx)
+
+		s.Equal(http.StatusOK, s.w.Code)
+		s.NotContains(s.w.Body.String(), "secret")
+		s.store.AssertExpectations(s.T())
+	})
+}
+
+func (s *TickerTestSuite) TestDeleteTickerWebhooks() {
+	s.Run("when ticker not found", func() {
+		h := s.handler()
+		h.DeleteTickerWebhooks(s.ctx)
+
+		s.Equal(http.StatusNotFound, s.w.Code)
+		s.store.AssertExpectations(s.T())
+	})
+
+	s.Run("when storage returns error", func() {
+		s.ctx.Set("ticker", storage.Ticker{})
+		s.store.On("DeleteTickerWebhooks", mock.Anything).Return(errors.New("storage error")).Once()
+		h := s.handler()
+		h.DeleteTickerWebhooks(s.ctx)
+
+		s.Equal(http.StatusBadRequest, s.w.Code)
+		s.store.AssertExpectations(s.T())
+	})
+
+	s.Run("when storage returns ticker", func() {
+		s.ctx.Set("ticker", storage.Ticker{})
+		s.store.On("DeleteTickerWebhooks", mock.Anything).Return(nil).Once()
+		h := s.handler()
+		h.DeleteTickerWebhooks(s.ctx)
+
+		s.Equal(http.StatusOK, s.w.Code)
+		s.store.AssertExpectations(s.T())
+	})
+}
+
+func (s *TickerTestSuite) TestPutTickerSignalSenders() {
+	s.Run("when ticker not found", func() {
+		h := s.handler()
+		h.PutTickerSignalSenders(s.ctx)
+
+		s.Equal(http.StatusNotFound, s.w.Code)
+		s.store.AssertExpectations(s.T())
+	})
+
+	s.Run("when body is invalid", func() {
+		s.ctx.Set("ticker", storage.Ticker{})
+		s.ctx.Request = httptest.NewRequest(http.MethodPut, "/v1/admin/tickers/1/signal_senders", nil)
+		s.ctx.Request.Header.Add("Content-Type", "application/json")
+		h := s.handler()
+		h.PutTickerSignalSenders(s.ctx)
+
+		s.Equal(http.StatusBadRequest, s.w.Code)
+		s.store.AssertExpectations(s.T())
+	})
+
+	s.Run("when number is invalid", func() {
+		s.ctx.Set("ticker", storage.Ticker{})
+		body := `{"signalSenders":[{"number":"015112345678"}]}`
+		s.ctx.Request = httptest.NewRequest(http.MethodPut, "/v1/admin/tickers/1/signal_senders", strings.NewReader(body))
+		s.ctx.Request.Header.Add("Content-Type", "application/json")
+		h := s.handler()
+		h.PutTickerSignalSenders(s.ctx)
+
+		s.Equal(http.StatusBadRequest, s.w.Code)
+		s.store.AssertExpectations(s.T())
+	})
+
+	s.Run("when sender belongs to another ticker", func() {
+		s.ctx.Set("ticker", storage.Ticker{})
+		body := `{"signalSenders":[{"id":2,"number":"+4915112345678"}]}`
+		s.ctx.Request = httptest.NewRequest(http.MethodPut, "/v1/admin/tickers/1/signal_senders", strings.NewReader(body))
+		s.ctx.Request.Header.Add("Content-Type", "application/json")
+		h := s.handler()
+		h.PutTickerSignalSenders(s.ctx)
+
+		s.Equal(http.StatusBadRequest, s.w.Code)
+		s.store.AssertExpectations(s.T())
+	})
+
+	s.Run("when senders are empty", func() {
+		s.ctx.Set("ticker", storage.Ticker{})
+		body := `{"signalSenders":[]}`
+		s.ctx.Request = httptest.NewRequest(http.MethodPut, "/v1/admin/tickers/1/signal_senders", strings.NewReader(body))
+		s.ctx.Request.Header.Add("Content-Type", "application/json")
+		s.store.On("DeleteTickerSignalSenders", mock.Anything).Return(nil).Once()
+		h := s.handler()
+		h.PutTickerSignalSenders(s.ctx)
+
+		s.Equal(http.StatusOK, s.w.Code)
+		s.store.AssertExpectations(s.T())
+	})
+
+	s.Run("when storage returns error", func() {
+		s.ctx.Set("ticker", storage.Ticker{})
+		body := `{"signalSenders":[{"number":"+4915112345678"}]}`
+		s.ctx.Request = httptest.NewRequest(http.MethodPut, "/v1/admin/tickers/1/signal_senders", strings.NewReader(body))
+		s.ctx.Request.Header.Add("Content-Type", "application/json")
+		s.store.On("SaveTickerSignalSenders", mock.Anything, mock.Anything).Return(errors.New("storage error")).Once()
+		h := s.handler()
+		h.PutTickerSignalSenders(s.ctx)
+
+		s.Equal(http.StatusBadRequest, s.w.Code)
+		s.store.AssertExpectations(s.T())
+	})
+
+	s.Run("when senders are saved", func() {
+		s.ctx.Set("ticker", storage.Ticker{SignalSenders: []storage.TickerSignalSender{{ID: 2, Number: "+4915112345678"}}})
+		body := `{"signalSenders":[{"id":2,"number":"+49 151 12345678","name":"Reporter"},{"number":"+4915187654321"}]}`
+		s.ctx.Request = httptest.NewRequest(http.MethodPut, "/v1/admin/tickers/1/signal_senders", strings.NewReader(body))
+		s.ctx.Request.Header.Add("Content-Type", "application/json")
+		s.store.On("SaveTickerSignalSenders", mock.Anything, []storage.TickerSignalSender{
+			{ID: 2, Number: "+4915112345678", Name: "Reporter"},
+			{Number: "+4915187654321"},
+		}).Return(nil).Once()
+		h := s.handler()
+		h.PutTickerSignalSenders(s.ctx)
+
+		s.Equal(http.StatusOK, s.w.Code)
+		s.store.AssertExpectations(s.T())
+	})
+}
+
+func (s *TickerTestSuite) TestDeleteTickerSignalSenders() {
+	s.Run("when ticker not found", func() {
+		h := s.handler()
+		h.DeleteTickerSignalSenders(s.ctx)
+
+		s.Equal(http.StatusNotFound, s.w.Code)
+		s.store.AssertExpectations(s.T())
+	})
+
+	s.Run("when storage returns error", func() {
+		s.ctx.Set("ticker", storage.Ticker{})
+		s.store.On("DeleteTickerSignalSenders", mock.Anything).Return(errors.New("storage error")).Once()
+		h := s.handler()
+		h.DeleteTickerSignalSenders(s.ctx)
+
+		s.Equal(http.StatusBadRequest, s.w.Code)
+		s.store.AssertExpectations(s.T())
+	})
+
+	s.Run("when storage returns ticker", func() {
+		s.ctx.Set("ticker", storage.Ticker{})
+		s.store.On("DeleteTickerSignalSenders", mock.Anything).Return(nil).Once()
+		h := s.handler()
+		h.DeleteTickerSignalSenders(s.ctx)
+
+		s.Equal(http.StatusOK, s.w.Code)
+		s.store.AssertExpectations(s.T())
+	})
+}
+
+func (s *TickerTestSuite) TestPutTickerMatrix() {
+	s.Run("when ticker not found", func() {
+		h := s.handler()
+		h.PutTickerMatrix(s.ctx)
+
+		s.Equal(http.StatusNotFound, s.w.Code)
+		s.store.AssertExpectations(s.T())
+	})
+
+	s.Run("when body is invalid", func() {
+		s.ctx.Set("ticker", storage.Ticker{})
+		s.ctx.Request = httptest.NewRequest(http.MethodPut, "/v1/admin/tickers/1/matrix", nil)
+		s.ctx.Request.Header.Add("Content-Type", "application/json")
+		h := s.handler()
+		h.PutTickerMatrix(s.ctx)
+
+		s.Equal(http.StatusBadRequest, s.w.Code)
+		s.store.AssertExpectations(s.T())
+	})
+
+	s.Run("when homeserver is internal", func() {
+		s.ctx.Set("ticker", storage.Ticker{})
+		body := `{"active":true,"homeserver":"https://127.0.0.1:8448","accessToken":"token","roomID":"#ticker:example.org"}`
+		s.ctx.Request = httptest.NewRequest(http.MethodPut, "/v1/admin/tickers/1/matrix", strings.NewReader(body))
+		s.ctx.Request.Header.Add("Content-Type", "application/json")
+		h := s.handler()
+		h.PutTickerMatrix(s.ctx)
+
+		s.Equal(http.StatusBadRequest, s.w.Code)
+		s.store.AssertExpectations(s.T())
+	})
+
+	s.Run("when access token is invalid", func() {
+		gock.New("https://matrix.example.org").
+			Get("/_matrix/client/v3/account/whoami").
+			Reply(401).
+			JSON(map[string]string{"errcode": "M_UNKNOWN_TOKEN", "error": "Invalid access token"})
+
+		s.ctx.Set("ticker", storage.Ticker{})
+		body := `{"active":true,"homeserver":"https://matrix.example.org","accessToken":"token","roomID":"#ticker:example.org"}`
+		s.ctx.Request = httptest.NewRequest(http.MethodPut, "/v1/admin/tickers/1/matrix", strings.NewReader(body))
+		s.ctx.Request.Header.Add("Content-Type", "application/json")
+		h := s.handler()
+		h.PutTickerMatrix(s.ctx)
+
+		s.Equal(http.StatusBadRequest, s.w.Code)
+		s.True(gock.IsDone())
+		s.store.AssertExpectations(s.T())
+	})
+
+	s.Run("when room can not be joined", func() {
+		gock.New("https://matrix.example.org").
+			Get("/_matrix/client/v3/account/whoami").
+			Reply(200).
+			JSON(map[string]string{"user_id": "@ticker:example.org"})
+		gock.New("https://matrix.example.org").
+			Post("/_matrix/client/v3/join/").
+			Reply(403).
+			JSON(map[string]string{"errcode": "M_FORBIDDEN", "error": "You are not invited to this room."})
+
+		s.ctx.Set("ticker", storage.Ticker{})
+		body := `{"active":true,"homeserver":"https://matrix.example.org","accessToken":"token","roomID":"#ticker:example.org"}`
+		s.ctx.Request = httptest.NewRequest(http.MethodPut, "/v1/admin/tickers/1/matrix", strings.NewReader(body))
+		s.ctx.Request.Header.Add("Content-Type", "application/json")
+		h := s.handler()
+		h.PutTickerMatrix(s.ctx)
+
+		s.Equal(http.StatusBadRequest, s.w.Code)
+		s.True(gock.IsDone())
+		s.store.AssertExpectations(s.T())
+	})
+
+	s.Run("when storage returns error", func() {
+		s.ctx.Set("ticker", storage.Ticker{})
+		body := `{"active":true}`
+		s.ctx.Request = httptest.NewRequest(http.MethodPut, "/v1/admin/tickers/1/matrix", strings.NewReader(body))
+		s.ctx.Request.Header.Add("Content-Type", "application/json")
+		s.store.On("SaveTicker", mock.Anything).Return(errors.New("storage error")).Once()
+
+		h := s.handler()
+		h.PutTickerMatrix(s.ctx)
+
+		s.Equal(http.StatusBadRequest, s.w.Code)
+		s.store.AssertExpectations(s.T())
+	})
+
+	s.Run("when enabling matrix successfully", func() {
+		gock.New("https://matrix.example.org").
+			Get("/_matrix/client/v3/account/whoami").
+			MatchHeader("Authorization", "Bearer token").
+			Reply(200).
+			JSON(map[string]string{"user_id": "@ticker:example.org"})
+		gock.New("https://matrix.example.org").
+			Post("/_matrix/client/v3/join/").
+			Reply(200).
+			JSON(map[string]string{"room_id": "!room:example.org"})
+
+		s.ctx.Set("ticker", storage.Ticker{})
+		body := `{"active":true,"homeserver":"https://matrix.example.org/","accessToken":"token","roomID":"#ticker:example.org"}`
+		s.ctx.Request = httptest.NewRequest(http.MethodPut, "/v1/admin/tickers/1/matrix", strings.NewReader(body))
+		s.ctx.Request.Header.Add("Content-Type", "application/json")
+		s.store.On("SaveTicker", mock.AnythingOfType("*storage.Ticker")).Run(func(args mock.Arguments) {
+			ticker := args.Get(0).(*storage.Ticker)
+			s.True(ticker.Matrix.Active)
+			s.Equal("https://matrix.example.org", ticker.Matrix.Homeserver)
+			s.Equal("@ticker:example.org", ticker.Matrix.UserID)
+			s.Equal("!room:example.org", ticker.Matrix.RoomID)
+		}).Return(nil).Once()
+
+		h := s.handler()
+		h.PutTickerMatrix(s.ctx)
+
+		s.Equal(http.StatusOK, s.w.Code)
+		s.True(gock.IsDone())
+		s.store.AssertExpectations(s.T())
+	})
+}
+
+func (s *TickerTestSuite) TestDeleteTickerMatrix() {
+	s.Run("when ticker not found", func() {
+		h := s.handler()
+		h.DeleteTickerMatrix(s.ctx)
+
+		s.Equal(http.StatusNotFound, s.w.Code)
+		s.store.AssertExpectations(s.T())
+	})
+
+	s.Run("when storage returns error", func() {
+		s.ctx.Set("ticker", storage.Ticker{})
+		s.store.On("DeleteMatrix", mock.Anything).Return(errors.New("storage error")).Once()
+		h := s.handler()
+		h.DeleteTickerMatrix(s.ctx)
+
+		s.Equal(http.StatusBadRequest, s.w.Code)
+		s.store.AssertExpectations(s.T())
+	})
+
+	s.Run("when storage returns ticker", func() {
+		s.ctx.Set("ticker", storage.Ticker{})
+		s.store.On("DeleteMatrix", mock.Anything).Return(nil).Once()
+		h := s.handler()
+		h.DeleteTickerMatrix(s.ctx)
+
+		s.Equal(http.StatusOK, s.w.Code)
+		s.store.AssertExpectations(s.T())
+	})
+}
+
 func (s *TickerTestSuite) TestPutTickerSignalGroup() {
 	s.Run("when ticker not found", func() {
 		h := s.handler()
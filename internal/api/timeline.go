@@ -18,9 +18,49 @@ func (h *handler) GetTimeline(c *gin.Context) {
 	}
 
 	messages := make([]storage.Message, 0)
+	pinned := make([]storage.Message, 0)
 	if ticker.Active {
 		pagination := pagination.NewPagination(c)
-		messages, err = h.storage.FindMessagesByTickerAndPagination(ticker, *pagination, storage.WithAttachments())
+		opts := append(messageListOptions(c), storage.WithoutPinned())
+		messages, err = h.storage.FindMessagesByTickerAndPagination(ticker, *pagination, opts...)
+		if err != nil {
+			c.JSON(http.StatusOK, response.ErrorResponse(response.CodeDefault, response.MessageFetchError))
+			return
+		}
+
+		pinned, err = h.storage.FindPinnedMessagesByTicker(ticker, storage.WithAttachments(), storage.WithTags())
+		if err != nil {
+			c.JSON(http.StatusOK, response.ErrorResponse(response.CodeDefault, response.MessageFetchError))
+			return
+		}
+	}
+
+	data := map[string]interface{}{
+		"messages": response.TimelineResponse(messages),
+		"pinned":   response.TimelineResponse(pinned),
+	}
+	c.JSON(http.StatusOK, response.SuccessResponse(data))
+}
+
+// SearchTimeline returns the messages of the ticker matching the query in q,
+// paginated like the timeline.
+func (h *handler) SearchTimeline(c *gin.Context) {
+	ticker, err := helper.Ticker(c)
+	if err != nil {
+		c.JSON(http.StatusOK, response.ErrorResponse(response.CodeDefault, response.TickerNotFound))
+		return
+	}
+
+	query := c.Query("q")
+	if query == "" {
+		c.JSON(http.StatusOK, response.ErrorResponse(response.CodeDefault, response.SearchQueryMissing))
+		return
+	}
+
+	messages := make([]storage.Message, 0)
+	if ticker.Active {
+		pagination := pagination.NewPagination(c)
+		messages, err = h.storage.SearchMessagesByTicker(ticker, query, *pagination, messageListOptions(c)...)
 		if err != nil {
 			c.JSON(http.StatusOK, response.ErrorResponse(response.CodeDefault, response.MessageFetchError))
 			return
@@ -29,3 +69,28 @@ func (h *handler) GetTimeline(c *gin.Context) {
 
 	c.JSON(http.StatusOK, response.SuccessResponse(map[string]interface{}{"messages": response.TimelineResponse(messages)}))
 }
+
+// GetTimelineGeoJSON returns the messages with a geometry as a GeoJSON
+// FeatureCollection for map views. It is paginated and filtered like the
+// timeline.
+func (h *handler) GetTimelineGeoJSON(c *gin.Context) {
+	ticker, err := helper.Ticker(c)
+	if err != nil {
+		c.JSON(http.StatusOK, response.ErrorResponse(response.CodeDefault, response.TickerNotFound))
+		return
+	}
+
+	messages := make([]storage.Message, 0)
+	if ticker.Active {
+		pagination := pagination.NewPagination(c)
+		opts := append(messageListOptions(c), storage.WithGeometry())
+		messages, err = h.storage.FindMessagesByTickerAndPagination(ticker, *pagination, opts...)
+		if err != nil {
+			c.JSON(http.StatusOK, response.ErrorResponse(response.CodeDefault, response.MessageFetchError))
+			return
+		}
+	}
+
+	c.Header("Content-Type", "application/geo+json")
+	c.JSON(http.StatusOK, response.GeoJSONResponse(messages))
+}
@@ -12,6 +12,7 @@ import (
 	"github.com/systemli/ticker/internal/api/response"
 	"github.com/systemli/ticker/internal/config"
 	"github.com/systemli/ticker/internal/storage"
+	"gorm.io/gorm"
 )
 
 type TimelineTestSuite struct {
@@ -64,10 +65,137 @@ func (s *TimelineTestSuite) TestGetTimeline() {
 		s.ctx.Request = httptest.NewRequest(http.MethodGet, "/v1/timeline", nil)
 		s.ctx.Set("ticker", storage.Ticker{Active: true})
 		s.store.On("FindMessagesByTickerAndPagination", mock.Anything, mock.Anything, mock.Anything).Return([]storage.Message{}, nil).Once()
+		s.store.On("FindPinnedMessagesByTicker", mock.Anything, mock.Anything).Return([]storage.Message{{ID: 1, Text: "Legal aid", Pinned: true}}, nil).Once()
 		h := s.handler()
 		h.GetTimeline(s.ctx)
 
 		s.Equal(http.StatusOK, s.w.Code)
+		s.Contains(s.w.Body.String(), `"pinned":[{"id":1`)
+		s.store.AssertExpectations(s.T())
+	})
+
+	s.Run("when filtering by tag", func() {
+		s.ctx.Request = httptest.NewRequest(http.MethodGet, "/v1/timeline?tag=%23Police", nil)
+		s.ctx.Set("ticker", storage.Ticker{Active: true})
+		s.store.On("FindMessagesByTickerAndPagination", mock.Anything, mock.Anything, mock.MatchedBy(func(opts []func(*gorm.DB) *gorm.DB) bool {
+			return len(opts) == 4
+		})).Return([]storage.Message{{ID: 2, Text: "Kettle", Tags: []storage.MessageTag{{Name: "police"}}}}, nil).Once()
+		s.store.On("FindPinnedMessagesByTicker", mock.Anything, mock.Anything).Return([]storage.Message{}, nil).Once()
+		h := s.handler()
+		h.GetTimeline(s.ctx)
+
+		s.Equal(http.StatusOK, s.w.Code)
+		s.Contains(s.w.Body.String(), `"tags":["police"]`)
+		s.store.AssertExpectations(s.T())
+	})
+
+	s.Run("when storage returns an error for pinned messages", func() {
+		s.ctx.Request = httptest.NewRequest(http.MethodGet, "/v1/timeline", nil)
+		s.ctx.Set("ticker", storage.Ticker{Active: true})
+		s.store.On("FindMessagesByTickerAndPagination", mock.Anything, mock.Anything, mock.Anything).Return([]storage.Message{}, nil).Once()
+		s.store.On("FindPinnedMessagesByTicker", mock.Anything, mock.Anything).Return(nil, errors.New("storage error")).Once()
+		h := s.handler()
+		h.GetTimeline(s.ctx)
+
+		s.Equal(http.StatusOK, s.w.Code)
+		s.Contains(s.w.Body.String(), response.MessageFetchError)
+		s.store.AssertExpectations(s.T())
+	})
+}
+
+func (s *TimelineTestSuite) TestSearchTimeline() {
+	s.Run("when ticker is missing", func() {
+		s.ctx.Request = httptest.NewRequest(http.MethodGet, "/v1/timeline/search?q=water", nil)
+		h := s.handler()
+		h.SearchTimeline(s.ctx)
+
+		s.Equal(http.StatusOK, s.w.Code)
+		s.Contains(s.w.Body.String(), response.TickerNotFound)
+		s.store.AssertExpectations(s.T())
+	})
+
+	s.Run("when query is missing", func() {
+		s.ctx.Request = httptest.NewRequest(http.MethodGet, "/v1/timeline/search", nil)
+		s.ctx.Set("ticker", storage.Ticker{Active: true})
+		h := s.handler()
+		h.SearchTimeline(s.ctx)
+
+		s.Equal(http.StatusOK, s.w.Code)
+		s.Contains(s.w.Body.String(), response.SearchQueryMissing)
+		s.store.AssertExpectations(s.T())
+	})
+
+	s.Run("when ticker is inactive", func() {
+		s.ctx.Request = httptest.NewRequest(http.MethodGet, "/v1/timeline/search?q=water", nil)
+		s.ctx.Set("ticker", storage.Ticker{Active: false})
+		h := s.handler()
+		h.SearchTimeline(s.ctx)
+
+		s.Equal(http.StatusOK, s.w.Code)
+		s.Contains(s.w.Body.String(), `"messages":[]`)
+		s.store.AssertExpectations(s.T())
+	})
+
+	s.Run("when storage returns an error", func() {
+		s.ctx.Request = httptest.NewRequest(http.MethodGet, "/v1/timeline/search?q=water", nil)
+		s.ctx.Set("ticker", storage.Ticker{Active: true})
+		s.store.On("SearchMessagesByTicker", mock.Anything, "water", mock.Anything, mock.Anything).Return(nil, errors.New("storage error")).Once()
+		h := s.handler()
+		h.SearchTimeline(s.ctx)
+
+		s.Equal(http.StatusOK, s.w.Code)
+		s.Contains(s.w.Body.String(), response.MessageFetchError)
+		s.store.AssertExpectations(s.T())
+	})
+
+	s.Run("when storage returns messages", func() {
+		s.ctx.Request = httptest.NewRequest(http.MethodGet, "/v1/timeline/search?q=water", nil)
+		s.ctx.Set("ticker", storage.Ticker{Active: true})
+		s.store.On("SearchMessagesByTicker", mock.Anything, "water", mock.Anything, mock.Anything).Return([]storage.Message{{ID: 1, Text: "The water station moved"}}, nil).Once()
+		h := s.handler()
+		h.SearchTimeline(s.ctx)
+
+		s.Equal(http.StatusOK, s.w.Code)
+		s.Contains(s.w.Body.String(), "The water station moved")
+		s.store.AssertExpectations(s.T())
+	})
+}
+
+func (s *TimelineTestSuite) TestGetTimelineGeoJSON() {
+	s.Run("when ticker is missing", func() {
+		s.ctx.Request = httptest.NewRequest(http.MethodGet, "/v1/timeline.geojson", nil)
+		h := s.handler()
+		h.GetTimelineGeoJSON(s.ctx)
+
+		s.Equal(http.StatusOK, s.w.Code)
+		s.Contains(s.w.Body.String(), response.TickerNotFound)
+		s.store.AssertExpectations(s.T())
+	})
+
+	s.Run("when storage returns an error", func() {
+		s.ctx.Request = httptest.NewRequest(http.MethodGet, "/v1/timeline.geojson", nil)
+		s.ctx.Set("ticker", storage.Ticker{Active: true})
+		s.store.On("FindMessagesByTickerAndPagination", mock.Anything, mock.Anything, mock.Anything).Return(nil, errors.New("storage error")).Once()
+		h := s.handler()
+		h.GetTimelineGeoJSON(s.ctx)
+
+		s.Equal(http.StatusOK, s.w.Code)
+		s.Contains(s.w.Body.String(), response.MessageFetchError)
+		s.store.AssertExpectations(s.T())
+	})
+
+	s.Run("when storage returns messages", func() {
+		s.ctx.Request = httptest.NewRequest(http.MethodGet, "/v1/timeline.geojson", nil)
+		s.ctx.Set("ticker", storage.Ticker{Active: true})
+		geometry := &storage.Geometry{Type: storage.GeometryPoint, Coordinates: []byte(`[13.4,52.5]`)}
+		s.store.On("FindMessagesByTickerAndPagination", mock.Anything, mock.Anything, mock.Anything).Return([]storage.Message{{ID: 1, Text: "Kettle", Geometry: geometry}}, nil).Once()
+		h := s.handler()
+		h.GetTimelineGeoJSON(s.ctx)
+
+		s.Equal(http.StatusOK, s.w.Code)
+		s.Equal("application/geo+json", s.w.Header().Get("Content-Type"))
+		s.Contains(s.w.Body.String(), `"type":"FeatureCollection"`)
+		s.Contains(s.w.Body.String(), `"geometry":{"type":"Point","coordinates":[13.4,52.5]}`)
 		s.store.AssertExpectations(s.T())
 	})
 }
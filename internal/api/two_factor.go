@@ -0,0 +1,246 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/systemli/ticker/internal/api/helper"
+	"github.com/systemli/ticker/internal/api/middleware/auth"
+	"github.com/systemli/ticker/internal/api/response"
+	"github.com/systemli/ticker/internal/storage"
+	"github.com/systemli/ticker/internal/totp"
+)
+
+// totpIssuer is the name authenticator apps show for the account.
+const totpIssuer = "Ticker"
+
+type TOTPSetupParam struct {
+	Password string `json:"password" binding:"required"`
+}
+
+type TOTPCodeParam struct {
+	Code string `json:"code" binding:"required"`
+}
+
+type TOTPDisableParam struct {
+	Password string `json:"password" binding:"required"`
+	Code     string `json:"code" binding:"required"`
+}
+
+// GetTOTP returns whether the current user has a second factor and how many
+// recovery codes are left.
+func (h *handler) GetTOTP(c *gin.Context) {
+	me, err := helper.Me(c)
+	if err != nil {
+		c.JSON(http.StatusForbidden, response.ErrorResponse(response.CodeDefault, response.Unauthorized))
+		return
+	}
+
+	count, err := h.storage.CountRecoveryCodes(me)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, response.ErrorResponse(response.CodeDefault, response.StorageError))
+		return
+	}
+
+	c.JSON(http.StatusOK, response.SuccessResponse(map[string]interface{}{"totp": map[string]interface{}{"enabled": me.TOTPEnabled, "recoveryCodes": count}}))
+}
+
+// PostTOTP starts setting up the authenticator app. The secret is returned
+// with the provisioning URI for the QR code, it takes effect once the user
+// confirms it with a code through PutTOTP.
+func (h *handler) PostTOTP(c *gin.Context) {
+	me, err := helper.Me(c)
+	if err != nil {
+		c.JSON(http.StatusForbidden, response.ErrorResponse(response.CodeDefault, response.Unauthorized))
+		return
+	}
+
+	var body TOTPSetupParam
+	if err := c.Bind(&body); err != nil {
+		c.JSON(http.StatusBadRequest, response.ErrorResponse(response.CodeDefault, response.FormError))
+		return
+	}
+
+	if !me.Authenticate(body.Password) {
+		c.JSON(http.StatusBadRequest, response.ErrorResponse(response.CodeDefault, response.PasswordError))
+		return
+	}
+
+	if me.TOTPEnabled {
+		c.JSON(http.StatusBadRequest, response.ErrorResponse(response.CodeDefault, response.TwoFactorEnabled))
+		return
+	}
+
+	me.TOTPSecret, err = totp.GenerateSecret()
+	if err == nil {
+		err = h.storage.SaveTOTPSecret(&me)
+	}
+	if err != nil {
+		c.JSON(http.StatusBadRequest, response.ErrorResponse(response.CodeDefault, response.StorageError))
+		return
+	}
+
+	c.JSON(http.StatusOK, response.SuccessResponse(map[string]interface{}{"totp": map[string]interface{}{
+		"secret": me.TOTPSecret,
+		"uri":    totp.URI(me.TOTPSecret, totpIssuer, me.Email),
+	}}))
+}
+
+// PutTOTP turns the second factor on with a first code of the authenticator
+// app. The recovery codes are returned once and cannot be shown again.
+func (h *handler) PutTOTP(c *gin.Context) {
+	me, err := helper.Me(c)
+	if err != nil {
+		c.JSON(http.StatusForbidden, response.ErrorResponse(response.CodeDefault, response.Unauthorized))
+		return
+	}
+
+	var body TOTPCodeParam
+	if err := c.Bind(&body); err != nil {
+		c.JSON(http.StatusBadRequest, response.ErrorResponse(response.CodeDefault, response.FormError))
+		return
+	}
+
+	if me.TOTPEnabled {
+		c.JSON(http.StatusBadRequest, response.ErrorResponse(response.CodeDefault, response.TwoFactorEnabled))
+		return
+	}
+	if me.TOTPSecret == "" {
+		c.JSON(http.StatusBadRequest, response.ErrorResponse(response.CodeDefault, response.TwoFactorNotEnabled))
+		return
+	}
+
+	var step int64
+	valid, err := auth.ThrottleSecondFactor(h.storage, &me, func() (bool, error) {
+		var valid bool
+		step, valid = totp.Validate(me.TOTPSecret, body.Code, time.Now())
+		return valid, nil
+	})
+	if !h.checkSecondFactor(c, valid, err) {
+		return
+	}
+
+	plain, codes, err := storage.NewRecoveryCodes(me)
+	if err == nil {
+		err = h.storage.EnableTOTP(&me, step, codes)
+	}
+	if err != nil {
+		c.JSON(http.StatusBadRequest, response.ErrorResponse(response.CodeDefault, response.StorageError))
+		return
+	}
+
+	c.JSON(http.StatusOK, response.SuccessResponse(map[string]interface{}{"user": response.UserResponse(me), "recoveryCodes": plain}))
+}
+
+// DeleteTOTP turns the second factor of the current user off. It takes the
+// password and a code, so a stolen session is not enough.
+func (h *handler) DeleteTOTP(c *gin.Context) {
+	me, err := helper.Me(c)
+	if err != nil {
+		c.JSON(http.StatusForbidden, response.ErrorResponse(response.CodeDefault, response.Unauthorized))
+		return
+	}
+
+	var body TOTPDisableParam
+	if err := c.Bind(&body); err != nil {
+		c.JSON(http.StatusBadRequest, response.ErrorResponse(response.CodeDefault, response.FormError))
+		return
+	}
+
+	if !me.Authenticate(body.Password) {
+		c.JSON(http.StatusBadRequest, response.ErrorResponse(response.CodeDefault, response.PasswordError))
+		return
+	}
+
+	if !h.verifySecondFactor(c, &me, body.Code) {
+		return
+	}
+
+	if err := h.storage.DisableTOTP(&me); err != nil {
+		c.JSON(http.StatusBadRequest, response.ErrorResponse(response.CodeDefault, response.StorageError))
+		return
+	}
+
+	c.JSON(http.StatusOK, response.SuccessResponse(map[string]interface{}{"user": response.UserResponse(me)}))
+}
+
+// PostRecoveryCodes replaces the recovery codes of the current user, e.g.
+// when most of them are used up.
+func (h *handler) PostRecoveryCodes(c *gin.Context) {
+	me, err := helper.Me(c)
+	if err != nil {
+		c.JSON(http.StatusForbidden, response.ErrorResponse(response.CodeDefault, response.Unauthorized))
+		return
+	}
+
+	var body TOTPCodeParam
+	if err := c.Bind(&body); err != nil {
+		c.JSON(http.StatusBadRequest, response.ErrorResponse(response.CodeDefault, response.FormError))
+		return
+	}
+
+	if !h.verifySecondFactor(c, &me, body.Code) {
+		return
+	}
+
+	plain, codes, err := storage.NewRecoveryCodes(me)
+	if err == nil {
+		err = h.storage.SaveRecoveryCodes(me, codes)
+	}
+	if err != nil {
+		c.JSON(http.StatusBadRequest, response.ErrorResponse(response.CodeDefault, response.StorageError))
+		return
+	}
+
+	c.JSON(http.StatusOK, response.SuccessResponse(map[string]interface{}{"recoveryCodes": plain}))
+}
+
+// DeleteUserTOTP lets admins turn the second factor of a user off, for users
+// who lost their device together with the recovery codes.
+func (h *handler) DeleteUserTOTP(c *gin.Context) {
+	user, err := helper.User(c)
+	if err != nil {
+		c.JSON(http.StatusNotFound, response.ErrorResponse(response.CodeDefault, response.UserNotFound))
+		return
+	}
+
+	if err := h.storage.DisableTOTP(&user); err != nil {
+		c.JSON(http.StatusBadRequest, response.ErrorResponse(response.CodeDefault, response.StorageError))
+		return
+	}
+
+	c.JSON(http.StatusOK, response.SuccessResponse(map[string]interface{}{"user": response.UserResponse(user)}))
+}
+
+// verifySecondFactor checks the code of a user with a second factor and
+// writes the error response if it does not match.
+func (h *handler) verifySecondFactor(c *gin.Context, me *storage.User, code string) bool {
+	if !me.TOTPEnabled {
+		c.JSON(http.StatusBadRequest, response.ErrorResponse(response.CodeDefault, response.TwoFactorNotEnabled))
+		return false
+	}
+
+	valid, err := auth.VerifySecondFactor(h.storage, me, code)
+
+	return h.checkSecondFactor(c, valid, err)
+}
+
+// checkSecondFactor writes the error response for a code that was refused.
+func (h *handler) checkSecondFactor(c *gin.Context, valid bool, err error) bool {
+	if errors.Is(err, auth.ErrSecondFactorLocked) {
+		c.JSON(http.StatusTooManyRequests, response.ErrorResponse(response.CodeDefault, response.TwoFactorLocked))
+		return false
+	}
+	if err != nil {
+		c.JSON(http.StatusBadRequest, response.ErrorResponse(response.CodeDefault, response.StorageError))
+		return false
+	}
+	if !valid {
+		c.JSON(http.StatusBadRequest, response.ErrorResponse(response.CodeDefault, response.TwoFactorInvalid))
+		return false
+	}
+
+	return true
+}
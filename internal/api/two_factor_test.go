@@ -0,0 +1,375 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+	"github.com/systemli/ticker/internal/api/response"
+	"github.com/systemli/ticker/internal/config"
+	"github.com/systemli/ticker/internal/storage"
+	"github.com/systemli/ticker/internal/totp"
+)
+
+type TwoFactorTestSuite struct {
+	w      *httptest.ResponseRecorder
+	ctx    *gin.Context
+	store  *storage.MockStorage
+	user   storage.User
+	secret string
+	suite.Suite
+}
+
+func (s *TwoFactorTestSuite) SetupTest() {
+	gin.SetMode(gin.TestMode)
+
+	user, err := storage.NewUser("user@systemli.org", "password")
+	s.Require().NoError(err)
+	user.ID = 1
+	s.user = user
+
+	s.secret, err = totp.GenerateSecret()
+	s.Require().NoError(err)
+}
+
+func (s *TwoFactorTestSuite) Run(name string, subtest func()) {
+	s.T().Run(name, func(t *testing.T) {
+		s.w = httptest.NewRecorder()
+		s.ctx, _ = gin.CreateTestContext(s.w)
+		s.store = &storage.MockStorage{}
+
+		subtest()
+	})
+}
+
+func (s *TwoFactorTestSuite) TestGetTOTP() {
+	s.Run("when user is missing", func() {
+		h := s.handler()
+		h.GetTOTP(s.ctx)
+
+		s.Equal(http.StatusForbidden, s.w.Code)
+	})
+
+	s.Run("when storage returns error", func() {
+		s.ctx.Set("me", s.user)
+		s.store.On("CountRecoveryCodes", mock.Anything).Return(int64(0), errors.New("storage error")).Once()
+
+		h := s.handler()
+		h.GetTOTP(s.ctx)
+
+		s.Equal(http.StatusBadRequest, s.w.Code)
+		s.store.AssertExpectations(s.T())
+	})
+
+	s.Run("when user has a second factor", func() {
+		s.ctx.Set("me", s.enrolled())
+		s.store.On("CountRecoveryCodes", mock.Anything).Return(int64(8), nil).Once()
+
+		h := s.handler()
+		h.GetTOTP(s.ctx)
+
+		s.Equal(http.StatusOK, s.w.Code)
+		s.Contains(s.w.Body.String(), `"totp":{"enabled":true,"recoveryCodes":8}`)
+		s.store.AssertExpectations(s.T())
+	})
+}
+
+func (s *TwoFactorTestSuite) TestPostTOTP() {
+	s.Run("when user is missing", func() {
+		h := s.handler()
+		h.PostTOTP(s.ctx)
+
+		s.Equal(http.StatusForbidden, s.w.Code)
+	})
+
+	s.Run("when password is wrong", func() {
+		s.ctx.Set("me", s.user)
+		s.request(http.MethodPost, `{"password":"wrong"}`)
+
+		h := s.handler()
+		h.PostTOTP(s.ctx)
+
+		s.Equal(http.StatusBadRequest, s.w.Code)
+		s.Contains(s.w.Body.String(), response.PasswordError)
+	})
+
+	s.Run("when user has a second factor already", func() {
+		s.ctx.Set("me", s.enrolled())
+		s.request(http.MethodPost, `{"password":"password"}`)
+
+		h := s.handler()
+		h.PostTOTP(s.ctx)
+
+		s.Equal(http.StatusBadRequest, s.w.Code)
+		s.Contains(s.w.Body.String(), response.TwoFactorEnabled)
+	})
+
+	s.Run("when storage returns error", func() {
+		s.ctx.Set("me", s.user)
+		s.request(http.MethodPost, `{"password":"password"}`)
+		s.store.On("SaveTOTPSecret", mock.Anything).Return(errors.New("storage error")).Once()
+
+		h := s.handler()
+		h.PostTOTP(s.ctx)
+
+		s.Equal(http.StatusBadRequest, s.w.Code)
+		s.store.AssertExpectations(s.T())
+	})
+
+	s.Run("when the setup starts", func() {
+		s.ctx.Set("me", s.user)
+		s.request(http.MethodPost, `{"password":"password"}`)
+		s.store.On("SaveTOTPSecret", mock.MatchedBy(func(u *storage.User) bool {
+			return u.ID == 1 && u.TOTPSecret != ""
+		})).Return(nil).Once()
+
+		h := s.handler()
+		h.PostTOTP(s.ctx)
+
+		s.Equal(http.StatusOK, s.w.Code)
+		s.Contains(s.w.Body.String(), `"uri":"otpauth://totp/Ticker:user@systemli.org?`)
+		s.store.AssertExpectations(s.T())
+	})
+}
+
+func (s *TwoFactorTestSuite) TestPutTOTP() {
+	s.Run("when user is missing", func() {
+		h := s.handler()
+		h.PutTOTP(s.ctx)
+
+		s.Equal(http.StatusForbidden, s.w.Code)
+	})
+
+	s.Run("when the setup was not started", func() {
+		s.ctx.Set("me", s.user)
+		s.request(http.MethodPut, `{"code":"123456"}`)
+
+		h := s.handler()
+		h.PutTOTP(s.ctx)
+
+		s.Equal(http.StatusBadRequest, s.w.Code)
+		s.Contains(s.w.Body.String(), response.TwoFactorNotEnabled)
+	})
+
+	s.Run("when the code is wrong", func() {
+		user := s.user
+		user.TOTPSecret = s.secret
+		s.ctx.Set("me", user)
+		s.request(http.MethodPut, `{"code":"abcdef"}`)
+		s.store.On("AddTOTPAttempt", mock.Anything).Return(1, nil).Once()
+
+		h := s.handler()
+		h.PutTOTP(s.ctx)
+
+		s.Equal(http.StatusBadRequest, s.w.Code)
+		s.Contains(s.w.Body.String(), response.TwoFactorInvalid)
+	})
+
+	s.Run("when the second factor is turned on", func() {
+		user := s.user
+		user.TOTPSecret = s.secret
+		s.ctx.Set("me", user)
+		s.request(http.MethodPut, `{"code":"`+s.code()+`"}`)
+		s.store.On("AddTOTPAttempt", mock.Anything).Return(1, nil).Once()
+		s.store.On("SaveTOTPLock", mock.Anything).Return(nil).Once()
+		s.store.On("EnableTOTP", mock.Anything, mock.Anything, mock.MatchedBy(func(codes []storage.RecoveryCode) bool {
+			return len(codes) == 10
+		})).Return(nil).Once()
+
+		h := s.handler()
+		h.PutTOTP(s.ctx)
+
+		s.Equal(http.StatusOK, s.w.Code)
+		s.Contains(s.w.Body.String(), `"recoveryCodes":["`)
+		s.store.AssertExpectations(s.T())
+	})
+}
+
+func (s *TwoFactorTestSuite) TestDeleteTOTP() {
+	s.Run("when user is missing", func() {
+		h := s.handler()
+		h.DeleteTOTP(s.ctx)
+
+		s.Equal(http.StatusForbidden, s.w.Code)
+	})
+
+	s.Run("when password is wrong", func() {
+		s.ctx.Set("me", s.enrolled())
+		s.request(http.MethodDelete, `{"password":"wrong","code":"123456"}`)
+
+		h := s.handler()
+		h.DeleteTOTP(s.ctx)
+
+		s.Equal(http.StatusBadRequest, s.w.Code)
+		s.Contains(s.w.Body.String(), response.PasswordError)
+	})
+
+	s.Run("when the code is wrong", func() {
+		s.ctx.Set("me", s.enrolled())
+		s.request(http.MethodDelete, `{"password":"password","code":"wrong"}`)
+		s.store.On("AddTOTPAttempt", mock.Anything).Return(1, nil).Once()
+		s.store.On("UseRecoveryCode", mock.Anything, "wrong").Return(false, nil).Once()
+
+		h := s.handler()
+		h.DeleteTOTP(s.ctx)
+
+		s.Equal(http.StatusBadRequest, s.w.Code)
+		s.Contains(s.w.Body.String(), response.TwoFactorInvalid)
+		s.store.AssertExpectations(s.T())
+	})
+
+	s.Run("when too many codes were wrong", func() {
+		s.ctx.Set("me", s.enrolled())
+		s.request(http.MethodDelete, `{"password":"password","code":"wrong"}`)
+		s.store.On("AddTOTPAttempt", mock.Anything).Return(6, nil).Once()
+		s.store.On("SaveTOTPLock", mock.MatchedBy(func(u *storage.User) bool {
+			return u.TOTPLockedUntil.After(time.Now())
+		})).Return(nil).Once()
+
+		h := s.handler()
+		h.DeleteTOTP(s.ctx)
+
+		s.Equal(http.StatusTooManyRequests, s.w.Code)
+		s.Contains(s.w.Body.String(), response.TwoFactorLocked)
+		s.store.AssertExpectations(s.T())
+	})
+
+	s.Run("when the codes are locked", func() {
+		user := s.enrolled()
+		user.TOTPLockedUntil = time.Now().Add(time.Minute)
+		s.ctx.Set("me", user)
+		s.request(http.MethodDelete, `{"password":"password","code":"`+s.code()+`"}`)
+
+		h := s.handler()
+		h.DeleteTOTP(s.ctx)
+
+		s.Equal(http.StatusTooManyRequests, s.w.Code)
+		s.store.AssertExpectations(s.T())
+	})
+
+	s.Run("when the second factor is turned off", func() {
+		s.ctx.Set("me", s.enrolled())
+		s.request(http.MethodDelete, `{"password":"password","code":"`+s.code()+`"}`)
+		s.store.On("AddTOTPAttempt", mock.Anything).Return(1, nil).Once()
+		s.store.On("SaveTOTPLock", mock.Anything).Return(nil).Once()
+		s.store.On("UseTOTPStep", mock.Anything, mock.Anything).Return(true, nil).Once()
+		s.store.On("DisableTOTP", mock.Anything).Return(nil).Once()
+
+		h := s.handler()
+		h.DeleteTOTP(s.ctx)
+
+		s.Equal(http.StatusOK, s.w.Code)
+		s.store.AssertExpectations(s.T())
+	})
+}
+
+func (s *TwoFactorTestSuite) TestPostRecoveryCodes() {
+	s.Run("when user has no second factor", func() {
+		s.ctx.Set("me", s.user)
+		s.request(http.MethodPost, `{"code":"123456"}`)
+
+		h := s.handler()
+		h.PostRecoveryCodes(s.ctx)
+
+		s.Equal(http.StatusBadRequest, s.w.Code)
+		s.Contains(s.w.Body.String(), response.TwoFactorNotEnabled)
+	})
+
+	s.Run("when storage returns error", func() {
+		s.ctx.Set("me", s.enrolled())
+		s.request(http.MethodPost, `{"code":"abcde-12345"}`)
+		s.store.On("AddTOTPAttempt", mock.Anything).Return(1, nil).Once()
+		s.store.On("UseRecoveryCode", mock.Anything, "abcde-12345").Return(false, errors.New("storage error")).Once()
+
+		h := s.handler()
+		h.PostRecoveryCodes(s.ctx)
+
+		s.Equal(http.StatusBadRequest, s.w.Code)
+		s.Contains(s.w.Body.String(), response.StorageError)
+		s.store.AssertExpectations(s.T())
+	})
+
+	s.Run("when the codes are renewed", func() {
+		s.ctx.Set("me", s.enrolled())
+		s.request(http.MethodPost, `{"code":"abcde-12345"}`)
+		s.store.On("AddTOTPAttempt", mock.Anything).Return(1, nil).Once()
+		s.store.On("UseRecoveryCode", mock.Anything, "abcde-12345").Return(true, nil).Once()
+		s.store.On("SaveTOTPLock", mock.Anything).Return(nil).Once()
+		s.store.On("SaveRecoveryCodes", mock.Anything, mock.Anything).Return(nil).Once()
+
+		h := s.handler()
+		h.PostRecoveryCodes(s.ctx)
+
+		s.Equal(http.StatusOK, s.w.Code)
+		s.Contains(s.w.Body.String(), `"recoveryCodes":["`)
+		s.store.AssertExpectations(s.T())
+	})
+}
+
+func (s *TwoFactorTestSuite) TestDeleteUserTOTP() {
+	s.Run("when user is missing", func() {
+		h := s.handler()
+		h.DeleteUserTOTP(s.ctx)
+
+		s.Equal(http.StatusNotFound, s.w.Code)
+	})
+
+	s.Run("when storage returns error", func() {
+		s.ctx.Set("user", s.enrolled())
+		s.store.On("DisableTOTP", mock.Anything).Return(errors.New("storage error")).Once()
+
+		h := s.handler()
+		h.DeleteUserTOTP(s.ctx)
+
+		s.Equal(http.StatusBadRequest, s.w.Code)
+		s.store.AssertExpectations(s.T())
+	})
+
+	s.Run("when the second factor is reset", func() {
+		s.ctx.Set("user", s.enrolled())
+		s.store.On("DisableTOTP", mock.Anything).Return(nil).Once()
+
+		h := s.handler()
+		h.DeleteUserTOTP(s.ctx)
+
+		s.Equal(http.StatusOK, s.w.Code)
+		s.store.AssertExpectations(s.T())
+	})
+}
+
+func (s *TwoFactorTestSuite) enrolled() storage.User {
+	user := s.user
+	user.TOTPSecret = s.secret
+	user.TOTPEnabled = true
+
+	return user
+}
+
+func (s *TwoFactorTestSuite) code() string {
+	code, err := totp.Code(s.secret, time.Now())
+	s.Require().NoError(err)
+
+	return code
+}
+
+func (s *TwoFactorTestSuite) request(method, body string) {
+	s.ctx.Request = httptest.NewRequest(method, "/v1/admin/users/me/totp", strings.NewReader(body))
+	s.ctx.Request.Header.Add("Content-Type", "application/json")
+}
+
+func (s *TwoFactorTestSuite) handler() handler {
+	return handler{
+		storage: s.store,
+		config:  config.LoadConfig(""),
+	}
+}
+
+func TestTwoFactorTestSuite(t *testing.T) {
+	suite.Run(t, new(TwoFactorTestSuite))
+}
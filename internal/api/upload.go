@@ -39,12 +39,27 @@ func (h *handler) PostUpload(c *gin.Context) {
 		return
 	}
 
+	// The ticker is in the form, so NeedScope cannot check it.
+	if token, err := helper.APIToken(c); err == nil && !token.AllowsTicker(tickerID) {
+		c.JSON(http.StatusForbidden, response.ErrorResponse(response.CodeInsufficientPermissions, response.InsufficientPermissions))
+		return
+	}
+
 	ticker, err := h.storage.FindTickerByUserAndID(me, tickerID)
 	if err != nil {
 		c.JSON(http.StatusBadRequest, response.ErrorResponse(response.CodeDefault, response.TickerNotFound))
 		return
 	}
 
+	// Viewers can see the ticker but are not allowed to add anything to it.
+	if !me.IsSuperAdmin {
+		tickerUser, err := h.storage.FindTickerUser(ticker, me)
+		if err != nil || !storage.HasTickerRole(tickerUser.Role, storage.TickerRoleContributor) {
+			c.JSON(http.StatusForbidden, response.ErrorResponse(response.CodeInsufficientPermissions, response.InsufficientPermissions))
+			return
+		}
+	}
+
 	files := form.File["files"]
 	if len(files) < 1 {
 		c.JSON(http.StatusBadRequest, response.ErrorResponse(response.CodeDefault, response.FilesIdentifierMissing))
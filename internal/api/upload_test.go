@@ -105,6 +105,39 @@ func (s *UploadTestSuite) TestPostUpload() {
 		s.store.AssertExpectations(s.T())
 	})
 
+	s.Run("when the api token is limited to another ticker", func() {
+		body := new(bytes.Buffer)
+		writer := multipart.NewWriter(body)
+		writer.WriteField("ticker", "1")
+		_ = writer.Close()
+		s.ctx.Request = httptest.NewRequest(http.MethodPost, "/upload", body)
+		s.ctx.Request.Header.Add("Content-Type", writer.FormDataContentType())
+		s.ctx.Set("me", storage.User{IsSuperAdmin: true})
+		s.ctx.Set("apiToken", storage.APIToken{Scopes: []string{storage.APITokenScopeMessagesWrite}, TickerID: 2})
+		h := s.handler()
+		h.PostUpload(s.ctx)
+
+		s.Equal(http.StatusForbidden, s.w.Code)
+		s.store.AssertNotCalled(s.T(), "FindTickerByUserAndID", mock.Anything, mock.Anything)
+	})
+
+	s.Run("when user is a viewer", func() {
+		body := new(bytes.Buffer)
+		writer := multipart.NewWriter(body)
+		writer.WriteField("ticker", "1")
+		_ = writer.Close()
+		s.ctx.Request = httptest.NewRequest(http.MethodPost, "/upload", body)
+		s.ctx.Request.Header.Add("Content-Type", writer.FormDataContentType())
+		s.ctx.Set("me", storage.User{ID: 2})
+		s.store.On("FindTickerByUserAndID", mock.Anything, 1).Return(storage.Ticker{ID: 1}, nil).Once()
+		s.store.On("FindTickerUser", storage.Ticker{ID: 1}, storage.User{ID: 2}).Return(storage.TickerUser{Role: storage.TickerRoleViewer}, nil).Once()
+		h := s.handler()
+		h.PostUpload(s.ctx)
+
+		s.Equal(http.StatusForbidden, s.w.Code)
+		s.store.AssertExpectations(s.T())
+	})
+
 	s.Run("when form files are missing", func() {
 		body := new(bytes.Buffer)
 		writer := multipart.NewWriter(body)
@@ -0,0 +1,184 @@
+package api
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/systemli/ticker/internal/api/helper"
+	"github.com/systemli/ticker/internal/api/response"
+	"github.com/systemli/ticker/internal/storage"
+	"github.com/systemli/ticker/internal/webpush"
+)
+
+// maxWebPushSubscriptions caps the subscriptions of a ticker. Anyone can
+// subscribe, and every message is sent to all of them.
+const maxWebPushSubscriptions = 10000
+
+type WebPushParam struct {
+	Active bool `json:"active"`
+}
+
+// WebPushSubscriptionParam is the PushSubscription of the browser, as
+// PushSubscription.toJSON() returns it.
+type WebPushSubscriptionParam struct {
+	Endpoint string `json:"endpoint" binding:"required"`
+	Keys     struct {
+		P256dh string `json:"p256dh"`
+		Auth   string `json:"auth"`
+	} `json:"keys"`
+}
+
+type WebPushUnsubscribeParam struct {
+	Endpoint string `json:"endpoint" binding:"required"`
+}
+
+// GetWebPush returns the key the browsers of the readers subscribe with, as
+// applicationServerKey of PushManager.subscribe().
+func (h *handler) GetWebPush(c *gin.Context) {
+	_, settings, ok := h.webPushTicker(c)
+	if !ok {
+		return
+	}
+
+	c.JSON(http.StatusOK, response.SuccessResponse(map[string]interface{}{"webPush": map[string]string{"publicKey": settings.PublicKey}}))
+}
+
+func (h *handler) PostWebPushSubscription(c *gin.Context) {
+	ticker, _, ok := h.webPushTicker(c)
+	if !ok {
+		return
+	}
+
+	var body WebPushSubscriptionParam
+	if err := c.ShouldBindJSON(&body); err != nil {
+		c.JSON(http.StatusOK, response.ErrorResponse(response.CodeDefault, response.FormError))
+		return
+	}
+
+	subscription := webpush.Subscription{Endpoint: body.Endpoint, P256dh: body.Keys.P256dh, Auth: body.Keys.Auth}
+	if err := subscription.Validate(); err != nil {
+		c.JSON(http.StatusOK, response.ErrorResponse(response.CodeDefault, response.WebPushSubscriptionInvalid))
+		return
+	}
+
+	count, err := h.storage.CountWebPushSubscriptions(ticker)
+	if err != nil {
+		c.JSON(http.StatusOK, response.ErrorResponse(response.CodeDefault, response.StorageError))
+		return
+	}
+	if count >= maxWebPushSubscriptions {
+		c.JSON(http.StatusOK, response.ErrorResponse(response.CodeDefault, response.WebPushSubscriptionLimit))
+		return
+	}
+
+	err = h.storage.SaveWebPushSubscription(&storage.WebPushSubscription{
+		TickerID: ticker.ID,
+		Endpoint: subscription.Endpoint,
+		P256dh:   subscription.P256dh,
+		Auth:     subscription.Auth,
+	})
+	if err != nil {
+		c.JSON(http.StatusOK, response.ErrorResponse(response.CodeDefault, response.StorageError))
+		return
+	}
+
+	c.JSON(http.StatusOK, response.SuccessResponse(map[string]interface{}{}))
+}
+
+func (h *handler) DeleteWebPushSubscription(c *gin.Context) {
+	ticker, err := helper.Ticker(c)
+	if err != nil {
+		c.JSON(http.StatusOK, response.ErrorResponse(response.CodeDefault, response.TickerNotFound))
+		return
+	}
+
+	var body WebPushUnsubscribeParam
+	if err := c.ShouldBindJSON(&body); err != nil {
+		c.JSON(http.StatusOK, response.ErrorResponse(response.CodeDefault, response.FormError))
+		return
+	}
+
+	err = h.storage.DeleteWebPushSubscription(ticker, body.Endpoint)
+	if err != nil {
+		c.JSON(http.StatusOK, response.ErrorResponse(response.CodeDefault, response.StorageError))
+		return
+	}
+
+	c.JSON(http.StatusOK, response.SuccessResponse(map[string]interface{}{}))
+}
+
+// PutTickerWebPush turns push notifications for the ticker on or off. The
+// keys of the instance are created when a ticker first turns them on.
+func (h *handler) PutTickerWebPush(c *gin.Context) {
+	ticker, err := helper.Ticker(c)
+	if err != nil {
+		c.JSON(http.StatusNotFound, response.ErrorResponse(response.CodeDefault, response.TickerNotFound))
+		return
+	}
+
+	var body WebPushParam
+	err = c.Bind(&body)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, response.ErrorResponse(response.CodeNotFound, response.FormError))
+		return
+	}
+
+	if settings := h.storage.GetWebPushSettings(); body.Active && !settings.Enabled() {
+		publicKey, privateKey, err := webpush.GenerateVAPIDKeys()
+		if err != nil {
+			log.WithError(err).Error("failed to generate web push keys")
+			c.JSON(http.StatusInternalServerError, response.ErrorResponse(response.CodeDefault, response.StorageError))
+			return
+		}
+
+		err = h.storage.SaveWebPushSettings(storage.WebPushSettings{PublicKey: publicKey, PrivateKey: privateKey})
+		if err != nil {
+			c.JSON(http.StatusBadRequest, response.ErrorResponse(response.CodeDefault, response.StorageError))
+			return
+		}
+	}
+
+	ticker.WebPush.Active = body.Active
+
+	err = h.storage.SaveTicker(&ticker)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, response.ErrorResponse(response.CodeDefault, response.StorageError))
+		return
+	}
+
+	c.JSON(http.StatusOK, response.SuccessResponse(map[string]interface{}{"ticker": response.TickerResponse(ticker, h.getBotUsername())}))
+}
+
+func (h *handler) DeleteTickerWebPush(c *gin.Context) {
+	ticker, err := helper.Ticker(c)
+	if err != nil {
+		c.JSON(http.StatusNotFound, response.ErrorResponse(response.CodeDefault, response.TickerNotFound))
+		return
+	}
+
+	err = h.storage.DeleteWebPush(&ticker)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, response.ErrorResponse(response.CodeDefault, response.StorageError))
+		return
+	}
+
+	c.JSON(http.StatusOK, response.SuccessResponse(map[string]interface{}{"ticker": response.TickerResponse(ticker, h.getBotUsername())}))
+}
+
+// webPushTicker returns the ticker of the request together with the keys of
+// the instance, as long as readers may subscribe to it.
+func (h *handler) webPushTicker(c *gin.Context) (storage.Ticker, storage.WebPushSettings, bool) {
+	ticker, err := helper.Ticker(c)
+	if err != nil {
+		c.JSON(http.StatusOK, response.ErrorResponse(response.CodeDefault, response.TickerNotFound))
+		return ticker, storage.WebPushSettings{}, false
+	}
+
+	settings := h.storage.GetWebPushSettings()
+	if !ticker.WebPush.Active || !settings.Enabled() {
+		c.JSON(http.StatusOK, response.ErrorResponse(response.CodeDefault, response.WebPushDisabled))
+		return ticker, settings, false
+	}
+
+	return ticker, settings, true
+}
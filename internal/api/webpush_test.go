@@ -0,0 +1,273 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+	"github.com/systemli/ticker/internal/api/response"
+	"github.com/systemli/ticker/internal/config"
+	"github.com/systemli/ticker/internal/storage"
+)
+
+const webPushSubscriptionBody = `{"endpoint":"https://push.example.org/send/1","expirationTime":null,"keys":{"p256dh":"BCVxsr7N_eNgVRqvHtD0zTZsEc6-VV-JvLexhqUzORcxaOzi6-AYWXvTBHm4bjyPjs7Vd8pZGH6SRpkNtoIAiw4","auth":"BTBZMqHH6r4Tts7J_aSIgg"}}`
+
+type WebPushTestSuite struct {
+	w     *httptest.ResponseRecorder
+	ctx   *gin.Context
+	store *storage.MockStorage
+	cfg   config.Config
+	suite.Suite
+}
+
+func (s *WebPushTestSuite) SetupTest() {
+	gin.SetMode(gin.TestMode)
+}
+
+func (s *WebPushTestSuite) Run(name string, subtest func()) {
+	s.T().Run(name, func(t *testing.T) {
+		s.w = httptest.NewRecorder()
+		s.ctx, _ = gin.CreateTestContext(s.w)
+		s.store = &storage.MockStorage{}
+		s.store.On("GetTelegramSettings").Return(storage.TelegramSettings{}).Maybe()
+		s.cfg = config.LoadConfig("")
+
+		subtest()
+	})
+}
+
+func (s *WebPushTestSuite) TestGetWebPush() {
+	s.Run("when ticker not found", func() {
+		h := s.handler()
+		h.GetWebPush(s.ctx)
+
+		s.Equal(http.StatusOK, s.w.Code)
+		s.Contains(s.w.Body.String(), response.TickerNotFound)
+	})
+
+	s.Run("when push is disabled for the ticker", func() {
+		s.ctx.Set("ticker", storage.Ticker{ID: 1})
+		s.store.On("GetWebPushSettings").Return(storage.WebPushSettings{PublicKey: "public", PrivateKey: "private"}).Once()
+
+		h := s.handler()
+		h.GetWebPush(s.ctx)
+
+		s.Equal(http.StatusOK, s.w.Code)
+		s.Contains(s.w.Body.String(), response.WebPushDisabled)
+		s.store.AssertExpectations(s.T())
+	})
+
+	s.Run("when push is enabled", func() {
+		s.ctx.Set("ticker", storage.Ticker{ID: 1, WebPush: storage.TickerWebPush{Active: true}})
+		s.store.On("GetWebPushSettings").Return(storage.WebPushSettings{PublicKey: "public", PrivateKey: "private"}).Once()
+
+		h := s.handler()
+		h.GetWebPush(s.ctx)
+
+		s.Equal(http.StatusOK, s.w.Code)
+		s.Contains(s.w.Body.String(), `"publicKey":"public"`)
+		s.NotContains(s.w.Body.String(), "private")
+		s.store.AssertExpectations(s.T())
+	})
+}
+
+func (s *WebPushTestSuite) TestPostWebPushSubscription() {
+	s.Run("when subscription is invalid", func() {
+		s.ctx.Set("ticker", storage.Ticker{ID: 1, WebPush: storage.TickerWebPush{Active: true}})
+		s.ctx.Request = httptest.NewRequest(http.MethodPost, "/v1/webpush/subscriptions", strings.NewReader(`{"endpoint":"http://push.example.org/send/1"}`))
+		s.ctx.Request.Header.Add("Content-Type", "application/json")
+		s.store.On("GetWebPushSettings").Return(storage.WebPushSettings{PublicKey: "public", PrivateKey: "private"}).Once()
+
+		h := s.handler()
+		h.PostWebPushSubscription(s.ctx)
+
+		s.Equal(http.StatusOK, s.w.Code)
+		s.Contains(s.w.Body.String(), response.WebPushSubscriptionInvalid)
+		s.store.AssertExpectations(s.T())
+	})
+
+	s.Run("when the endpoint is internal", func() {
+		s.ctx.Set("ticker", storage.Ticker{ID: 1, WebPush: storage.TickerWebPush{Active: true}})
+		body := strings.Replace(webPushSubscriptionBody, "https://push.example.org", "https://127.0.0.1", 1)
+		s.ctx.Request = httptest.NewRequest(http.MethodPost, "/v1/webpush/subscriptions", strings.NewReader(body))
+		s.ctx.Request.Header.Add("Content-Type", "application/json")
+		s.store.On("GetWebPushSettings").Return(storage.WebPushSettings{PublicKey: "public", PrivateKey: "private"}).Once()
+
+		h := s.handler()
+		h.PostWebPushSubscription(s.ctx)
+
+		s.Equal(http.StatusOK, s.w.Code)
+		s.Contains(s.w.Body.String(), response.WebPushSubscriptionInvalid)
+		s.store.AssertExpectations(s.T())
+	})
+
+	s.Run("when the ticker has too many subscriptions", func() {
+		s.ctx.Set("ticker", storage.Ticker{ID: 1, WebPush: storage.TickerWebPush{Active: true}})
+		s.ctx.Request = httptest.NewRequest(http.MethodPost, "/v1/webpush/subscriptions", strings.NewReader(webPushSubscriptionBody))
+		s.ctx.Request.Header.Add("Content-Type", "application/json")
+		s.store.On("GetWebPushSettings").Return(storage.WebPushSettings{PublicKey: "public", PrivateKey: "private"}).Once()
+		s.store.On("CountWebPushSubscriptions", mock.Anything).Return(int64(maxWebPushSubscriptions), nil).Once()
+
+		h := s.handler()
+		h.PostWebPushSubscription(s.ctx)
+
+		s.Equal(http.StatusOK, s.w.Code)
+		s.Contains(s.w.Body.String(), response.WebPushSubscriptionLimit)
+		s.store.AssertExpectations(s.T())
+	})
+
+	s.Run("when storage returns error", func() {
+		s.ctx.Set("ticker", storage.Ticker{ID: 1, WebPush: storage.TickerWebPush{Active: true}})
+		s.ctx.Request = httptest.NewRequest(http.MethodPost, "/v1/webpush/subscriptions", strings.NewReader(webPushSubscriptionBody))
+		s.ctx.Request.Header.Add("Content-Type", "application/json")
+		s.store.On("GetWebPushSettings").Return(storage.WebPushSettings{PublicKey: "public", PrivateKey: "private"}).Once()
+		s.store.On("CountWebPushSubscriptions", mock.Anything).Return(int64(0), nil).Once()
+		s.store.On("SaveWebPushSubscription", mock.Anything).Return(errors.New("storage error")).Once()
+
+		h := s.handler()
+		h.PostWebPushSubscription(s.ctx)
+
+		s.Equal(http.StatusOK, s.w.Code)
+		s.Contains(s.w.Body.String(), response.StorageError)
+		s.store.AssertExpectations(s.T())
+	})
+
+	s.Run("when subscription is saved", func() {
+		s.ctx.Set("ticker", storage.Ticker{ID: 1, WebPush: storage.TickerWebPush{Active: true}})
+		s.ctx.Request = httptest.NewRequest(http.MethodPost, "/v1/webpush/subscriptions", strings.NewReader(webPushSubscriptionBody))
+		s.ctx.Request.Header.Add("Content-Type", "application/json")
+		s.store.On("GetWebPushSettings").Return(storage.WebPushSettings{PublicKey: "public", PrivateKey: "private"}).Once()
+		s.store.On("CountWebPushSubscriptions", mock.Anything).Return(int64(0), nil).Once()
+		s.store.On("SaveWebPushSubscription", mock.MatchedBy(func(subscription *storage.WebPushSubscription) bool {
+			return subscription.TickerID == 1 && subscription.Endpoint == "https://push.example.org/send/1" && subscription.Auth == "BTBZMqHH6r4Tts7J_aSIgg"
+		})).Return(nil).Once()
+
+		h := s.handler()
+		h.PostWebPushSubscription(s.ctx)
+
+		s.Equal(http.StatusOK, s.w.Code)
+		s.Contains(s.w.Body.String(), response.StatusSuccess)
+		s.store.AssertExpectations(s.T())
+	})
+}
+
+func (s *WebPushTestSuite) TestDeleteWebPushSubscription() {
+	s.Run("when body is invalid", func() {
+		s.ctx.Set("ticker", storage.Ticker{ID: 1})
+		s.ctx.Request = httptest.NewRequest(http.MethodDelete, "/v1/webpush/subscriptions", strings.NewReader(`{}`))
+		s.ctx.Request.Header.Add("Content-Type", "application/json")
+
+		h := s.handler()
+		h.DeleteWebPushSubscription(s.ctx)
+
+		s.Equal(http.StatusOK, s.w.Code)
+		s.Contains(s.w.Body.String(), response.FormError)
+	})
+
+	s.Run("when subscription is deleted", func() {
+		s.ctx.Set("ticker", storage.Ticker{ID: 1})
+		s.ctx.Request = httptest.NewRequest(http.MethodDelete, "/v1/webpush/subscriptions", strings.NewReader(`{"endpoint":"https://push.example.org/send/1"}`))
+		s.ctx.Request.Header.Add("Content-Type", "application/json")
+		s.store.On("DeleteWebPushSubscription", mock.Anything, "https://push.example.org/send/1").Return(nil).Once()
+
+		h := s.handler()
+		h.DeleteWebPushSubscription(s.ctx)
+
+		s.Equal(http.StatusOK, s.w.Code)
+		s.Contains(s.w.Body.String(), response.StatusSuccess)
+		s.store.AssertExpectations(s.T())
+	})
+}
+
+func (s *WebPushTestSuite) TestPutTickerWebPush() {
+	s.Run("when ticker not found", func() {
+		h := s.handler()
+		h.PutTickerWebPush(s.ctx)
+
+		s.Equal(http.StatusNotFound, s.w.Code)
+	})
+
+	s.Run("when keys are created", func() {
+		s.ctx.Set("ticker", storage.Ticker{ID: 1})
+		s.ctx.Request = httptest.NewRequest(http.MethodPut, "/v1/admin/tickers/1/webpush", strings.NewReader(`{"active":true}`))
+		s.ctx.Request.Header.Add("Content-Type", "application/json")
+		s.store.On("GetWebPushSettings").Return(storage.WebPushSettings{}).Once()
+		s.store.On("SaveWebPushSettings", mock.MatchedBy(func(settings storage.WebPushSettings) bool {
+			return settings.Enabled()
+		})).Return(nil).Once()
+		s.store.On("SaveTicker", mock.MatchedBy(func(t *storage.Ticker) bool {
+			return t.WebPush.Active
+		})).Return(nil).Once()
+
+		h := s.handler()
+		h.PutTickerWebPush(s.ctx)
+
+		s.Equal(http.StatusOK, s.w.Code)
+		s.Contains(s.w.Body.String(), `"webPush":{"active":true}`)
+		s.store.AssertExpectations(s.T())
+	})
+
+	s.Run("when keys exist", func() {
+		s.ctx.Set("ticker", storage.Ticker{ID: 1})
+		s.ctx.Request = httptest.NewRequest(http.MethodPut, "/v1/admin/tickers/1/webpush", strings.NewReader(`{"active":true}`))
+		s.ctx.Request.Header.Add("Content-Type", "application/json")
+		s.store.On("GetWebPushSettings").Return(storage.WebPushSettings{PublicKey: "public", PrivateKey: "private"}).Once()
+		s.store.On("SaveTicker", mock.Anything).Return(nil).Once()
+
+		h := s.handler()
+		h.PutTickerWebPush(s.ctx)
+
+		s.Equal(http.StatusOK, s.w.Code)
+		s.store.AssertExpectations(s.T())
+	})
+
+	s.Run("when storage returns error", func() {
+		s.ctx.Set("ticker", storage.Ticker{ID: 1})
+		s.ctx.Request = httptest.NewRequest(http.MethodPut, "/v1/admin/tickers/1/webpush", strings.NewReader(`{"active":false}`))
+		s.ctx.Request.Header.Add("Content-Type", "application/json")
+		s.store.On("GetWebPushSettings").Return(storage.WebPushSettings{}).Once()
+		s.store.On("SaveTicker", mock.Anything).Return(errors.New("storage error")).Once()
+
+		h := s.handler()
+		h.PutTickerWebPush(s.ctx)
+
+		s.Equal(http.StatusBadRequest, s.w.Code)
+		s.store.AssertExpectations(s.T())
+	})
+}
+
+func (s *WebPushTestSuite) TestDeleteTickerWebPush() {
+	s.Run("when ticker not found", func() {
+		h := s.handler()
+		h.DeleteTickerWebPush(s.ctx)
+
+		s.Equal(http.StatusNotFound, s.w.Code)
+	})
+
+	s.Run("when push is turned off", func() {
+		s.ctx.Set("ticker", storage.Ticker{ID: 1, WebPush: storage.TickerWebPush{Active: true}})
+		s.store.On("DeleteWebPush", mock.Anything).Return(nil).Once()
+
+		h := s.handler()
+		h.DeleteTickerWebPush(s.ctx)
+
+		s.Equal(http.StatusOK, s.w.Code)
+		s.store.AssertExpectations(s.T())
+	})
+}
+
+func (s *WebPushTestSuite) handler() handler {
+	return handler{
+		storage: s.store,
+		config:  s.cfg,
+	}
+}
+
+func TestWebPushTestSuite(t *testing.T) {
+	suite.Run(t, new(WebPushTestSuite))
+}
@@ -39,6 +39,7 @@ func (h *handler) HandleWebSocket(c *gin.Context) {
 		Send:     make(chan realtime.Message, 256), // Buffer to prevent blocking
 		TickerID: ticker.ID,
 		Origin:   origin, // Use only the host part of the origin
+		Tag:      queryTag(c),
 	}
 
 	h.realtime.Register(client)
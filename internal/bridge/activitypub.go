@@ -0,0 +1,89 @@
+package bridge
+
+import (
+	"context"
+
+	"github.com/systemli/ticker/internal/activitypub"
+	"github.com/systemli/ticker/internal/config"
+	"github.com/systemli/ticker/internal/storage"
+)
+
+// ActivityPubBridge delivers the messages to the followers of the actor of the
+// ticker. Delivery is best effort: an unreachable follower is logged and
+// skipped, as retrying the whole message would reach all others twice.
+type ActivityPubBridge struct {
+	config  config.Config
+	storage storage.Storage
+}
+
+func (ab *ActivityPubBridge) Enabled(ticker storage.Ticker) bool {
+	return ticker.ActivityPub.Active && ticker.ActivityPub.Connected()
+}
+
+func (ab *ActivityPubBridge) Update(ticker storage.Ticker) error {
+	return nil
+}
+
+func (ab *ActivityPubBridge) Send(ticker storage.Ticker, message *storage.Message) error {
+	if !ab.Enabled(ticker) {
+		return nil
+	}
+
+	return ab.deliver(ticker, activitypub.NewCreate(ticker, *message))
+}
+
+func (ab *ActivityPubBridge) Edit(ticker storage.Ticker, message *storage.Message) error {
+	if !ab.Enabled(ticker) {
+		return nil
+	}
+
+	return ab.deliver(ticker, activitypub.NewUpdate(ticker, *message))
+}
+
+func (ab *ActivityPubBridge) Delete(ticker storage.Ticker, message *storage.Message) error {
+	if !ab.Enabled(ticker) {
+		return nil
+	}
+
+	return ab.deliver(ticker, activitypub.NewDelete(ticker, *message))
+}
+
+func (ab *ActivityPubBridge) Pin(ticker storage.Ticker, message *storage.Message) error {
+	return nil
+}
+
+func (ab *ActivityPubBridge) Unpin(ticker storage.Ticker, message *storage.Message) error {
+	return nil
+}
+
+// deliver posts the activity once to every server with followers, through the
+// shared inbox where the server offers one.
+func (ab *ActivityPubBridge) deliver(ticker storage.Ticker, activity activitypub.Activity) error {
+	followers, err := ab.storage.FindActivityPubFollowers(ticker)
+	if err != nil {
+		return err
+	}
+
+	client, err := activitypub.NewClient(activitypub.KeyID(ticker), ticker.ActivityPub.PrivateKey)
+	if err != nil {
+		return err
+	}
+
+	delivered := make(map[string]bool)
+	for _, follower := range followers {
+		inbox := follower.Inbox
+		if follower.SharedInbox != "" {
+			inbox = follower.SharedInbox
+		}
+		if delivered[inbox] {
+			continue
+		}
+		delivered[inbox] = true
+
+		if err := client.Post(context.Background(), inbox, activity); err != nil {
+			log.WithError(err).WithField("inbox", inbox).Error("failed to deliver activity")
+		}
+	}
+
+	return nil
+}
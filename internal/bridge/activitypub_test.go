@@ -0,0 +1,113 @@
+package bridge
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+
+	"github.com/h2non/gock"
+	"github.com/stretchr/testify/mock"
+	"github.com/systemli/ticker/internal/activitypub"
+	"github.com/systemli/ticker/internal/config"
+	"github.com/systemli/ticker/internal/storage"
+)
+
+func (s *BridgeTestSuite) TestActivityPubEnabled() {
+	bridge := ActivityPubBridge{config: config.Config{}, storage: &storage.MockStorage{}}
+
+	s.False(bridge.Enabled(tickerWithoutBridges))
+	s.True(bridge.Enabled(s.tickerWithActivityPub()))
+}
+
+func (s *BridgeTestSuite) TestActivityPubSend() {
+	s.Run("when ticker has no actor", func() {
+		bridge := ActivityPubBridge{config: config.Config{}, storage: &storage.MockStorage{}}
+
+		err := bridge.Send(tickerWithoutBridges, &storage.Message{ID: 1, Text: "Hello World"})
+		s.NoError(err)
+	})
+
+	s.Run("when followers are not found", func() {
+		store := &storage.MockStorage{}
+		store.On("FindActivityPubFollowers", mock.Anything).Return(nil, errors.New("not found"))
+		bridge := ActivityPubBridge{config: config.Config{}, storage: store}
+
+		err := bridge.Send(s.tickerWithActivityPub(), &storage.Message{ID: 1, Text: "Hello World"})
+		s.Error(err)
+	})
+
+	s.Run("when followers share an inbox", func() {
+		store := &storage.MockStorage{}
+		store.On("FindActivityPubFollowers", mock.Anything).Return([]storage.ActivityPubFollower{
+			{Actor: "https://social.example.org/users/alice", Inbox: "https://social.example.org/users/alice/inbox", SharedInbox: "https://social.example.org/inbox"},
+			{Actor: "https://social.example.org/users/bob", Inbox: "https://social.example.org/users/bob/inbox", SharedInbox: "https://social.example.org/inbox"},
+			{Actor: "https://other.example.org/carol", Inbox: "https://other.example.org/carol/inbox"},
+		}, nil)
+		bridge := ActivityPubBridge{config: config.Config{}, storage: store}
+		var activity activitypub.Activity
+
+		gock.New("https://social.example.org").
+			Post("/inbox").
+			MatchHeader("Content-Type", activitypub.ContentType).
+			HeaderPresent("Signature").
+			AddMatcher(func(req *http.Request, _ *gock.Request) (bool, error) {
+				body, err := io.ReadAll(req.Body)
+				if err != nil {
+					return false, err
+				}
+				req.Body = io.NopCloser(bytes.NewReader(body))
+
+				return true, json.Unmarshal(body, &activity)
+			}).
+			Reply(202)
+		gock.New("https://other.example.org").
+			Post("/carol/inbox").
+			Reply(500)
+
+		err := bridge.Send(s.tickerWithActivityPub(), &storage.Message{ID: 1, Text: "Hello World"})
+		s.NoError(err)
+		s.Equal("Create", activity.Type)
+		s.Equal("https://demo.example.org/api/activitypub/tickers/1", activity.Actor)
+		s.True(gock.IsDone())
+	})
+}
+
+func (s *BridgeTestSuite) TestActivityPubDelete() {
+	store := &storage.MockStorage{}
+	store.On("FindActivityPubFollowers", mock.Anything).Return([]storage.ActivityPubFollower{
+		{Actor: "https://social.example.org/users/alice", Inbox: "https://social.example.org/users/alice/inbox"},
+	}, nil)
+	bridge := ActivityPubBridge{config: config.Config{}, storage: store}
+
+	var activity activitypub.Activity
+
+	gock.New("https://social.example.org").
+		Post("/users/alice/inbox").
+		AddMatcher(func(req *http.Request, _ *gock.Request) (bool, error) {
+			return true, json.NewDecoder(req.Body).Decode(&activity)
+		}).
+		Reply(202)
+
+	err := bridge.Delete(s.tickerWithActivityPub(), &storage.Message{ID: 1})
+	s.NoError(err)
+	s.Equal("Delete", activity.Type)
+	s.True(gock.IsDone())
+}
+
+func (s *BridgeTestSuite) tickerWithActivityPub() storage.Ticker {
+	privateKey, publicKey, err := activitypub.GenerateKeys()
+	s.Require().NoError(err)
+
+	return storage.Ticker{
+		ID: 1,
+		ActivityPub: storage.TickerActivityPub{
+			Active:     true,
+			Username:   "ticker",
+			Origin:     "https://demo.example.org",
+			PrivateKey: privateKey,
+			PublicKey:  publicKey,
+		},
+	}
+}
@@ -24,10 +24,22 @@ type BlueskyBridge struct {
 	storage storage.Storage
 }
 
+func (bb *BlueskyBridge) Enabled(ticker storage.Ticker) bool {
+	return ticker.Bluesky.Connected() && ticker.Bluesky.Active
+}
+
 func (bb *BlueskyBridge) Update(ticker storage.Ticker) error {
 	return nil
 }
 
+// blueskyPostLimit is the maximum length of a post. Bluesky counts graphemes,
+// which are never more than the characters counted by util.SplitText.
+const blueskyPostLimit = 300
+
+// Send posts the message. Messages which are too long for a single post are
+// split into a thread of replies, with the attachments on the first post.
+// When a reply fails, the retry continues the thread after the last reply
+// which was posted.
 func (bb *BlueskyBridge) Send(ticker storage.Ticker, message *storage.Message) error {
 	if !ticker.Bluesky.Connected() || !ticker.Bluesky.Active {
 		return nil
@@ -39,17 +51,90 @@ func (bb *BlueskyBridge) Send(ticker storage.Ticker, message *storage.Message) e
 		return err
 	}
 
-	post := &bsky.FeedPost{
-		Text:      message.Text,
-		CreatedAt: time.Now().Local().Format(time.RFC3339),
-		Facets:    []*bsky.RichtextFacet{},
+	var root, parent *comatproto.RepoStrongRef
+	if message.Bluesky.Uri != "" {
+		root = &comatproto.RepoStrongRef{Uri: message.Bluesky.Uri, Cid: message.Bluesky.Cid}
+		parent = root
+		if n := len(message.Bluesky.Replies); n > 0 {
+			parent = &comatproto.RepoStrongRef{Uri: message.Bluesky.Replies[n-1].Uri, Cid: message.Bluesky.Replies[n-1].Cid}
+		}
+	}
+
+	chunks := util.SplitText(message.Text, blueskyPostLimit)
+	posted := 0
+	if root != nil {
+		posted = min(1+len(message.Bluesky.Replies), len(chunks))
+	}
+
+	for i, chunk := range chunks[posted:] {
+		post := &bsky.FeedPost{
+			Text:      chunk,
+			CreatedAt: time.Now().Local().Format(time.RFC3339),
+			Facets:    buildFacets(chunk),
+		}
+
+		if posted+i == 0 && len(message.Attachments) > 0 {
+			post.Embed = &bsky.FeedPost_Embed{
+				EmbedImages: &bsky.EmbedImages{
+					Images: bb.uploadImages(client, message.Attachments),
+				},
+			}
+		}
+
+		if root != nil {
+			post.Reply = &bsky.FeedPost_ReplyRef{Root: root, Parent: parent}
+		}
+
+		resp, err := comatproto.RepoCreateRecord(context.TODO(), client, &comatproto.RepoCreateRecord_Input{
+			Collection: "app.bsky.feed.post",
+			Repo:       client.Auth.Did,
+			Record: &lexutil.LexiconTypeDecoder{
+				Val: post,
+			},
+		})
+		if err != nil {
+			// What was posted so far is kept on the message, so it can be
+			// deleted along with it and the retry does not post it again.
+			log.WithError(err).Error("failed to create post")
+			return err
+		}
+
+		parent = &comatproto.RepoStrongRef{Uri: resp.Uri, Cid: resp.Cid}
+		if root == nil {
+			root = parent
+			message.Bluesky = storage.BlueskyMeta{
+				Handle: ticker.Bluesky.Handle,
+				Uri:    resp.Uri,
+				Cid:    resp.Cid,
+			}
+			continue
+		}
+
+		message.Bluesky.Replies = append(message.Bluesky.Replies, storage.BlueskyPost{Uri: resp.Uri, Cid: resp.Cid})
 	}
 
-	links := util.ExtractURLs(message.Text)
+	// Create thread gate if reply restriction is configured. It is only
+	// reached once the whole thread was posted.
+	if ticker.Bluesky.ReplyRestriction != "" {
+		err = bb.createThreadGate(client, root.Uri, ticker.Bluesky.ReplyRestriction)
+		if err != nil {
+			log.WithError(err).Warn("failed to create thread gate")
+		}
+	}
+
+	return nil
+}
+
+// buildFacets marks the links and hashtags in the text, so Bluesky renders
+// them as such.
+func buildFacets(text string) []*bsky.RichtextFacet {
+	facets := []*bsky.RichtextFacet{}
+
+	links := util.ExtractURLs(text)
 	for _, link := range links {
-		startIndex := strings.Index(message.Text, link)
+		startIndex := strings.Index(text, link)
 		endIndex := startIndex + len(link)
-		post.Facets = append(post.Facets, &bsky.RichtextFacet{
+		facets = append(facets, &bsky.RichtextFacet{
 			Features: []*bsky.RichtextFacet_Features_Elem{
 				{
 					RichtextFacet_Link: &bsky.RichtextFacet_Link{
@@ -64,11 +149,11 @@ func (bb *BlueskyBridge) Send(ticker storage.Ticker, message *storage.Message) e
 		})
 	}
 
-	hashtags := util.ExtractHashtags(message.Text)
+	hashtags := util.ExtractHashtags(text)
 	for _, hashtag := range hashtags {
-		startIndex := strings.Index(message.Text, hashtag)
+		startIndex := strings.Index(text, hashtag)
 		endIndex := startIndex + len(hashtag)
-		post.Facets = append(post.Facets, &bsky.RichtextFacet{
+		facets = append(facets, &bsky.RichtextFacet{
 			Features: []*bsky.RichtextFacet_Features_Elem{
 				{
 					RichtextFacet_Tag: &bsky.RichtextFacet_Tag{
@@ -83,75 +168,80 @@ func (bb *BlueskyBridge) Send(ticker storage.Ticker, message *storage.Message) e
 		})
 	}
 
-	if len(message.Attachments) > 0 {
-		var images []*bsky.EmbedImages_Image
-
-		for _, attachment := range message.Attachments {
-			upload, err := bb.storage.FindUploadByUUID(attachment.UUID)
-			if err != nil {
-				log.WithError(err).Error("failed to find upload")
-				continue
-			}
-
-			b, err := os.ReadFile(upload.FullPath(bb.config.Upload.Path))
-			if err != nil {
-				log.WithError(err).Error("failed to read file")
-				continue
-			}
+	return facets
+}
 
-			resp, err := comatproto.RepoUploadBlob(context.TODO(), client, bytes.NewReader(b))
-			if err != nil {
-				log.WithError(err).Error("failed to upload blob")
-				continue
-			}
+func (bb *BlueskyBridge) uploadImages(client *xrpc.Client, attachments []storage.Attachment) []*bsky.EmbedImages_Image {
+	var images []*bsky.EmbedImages_Image
 
-			images = append(images, &bsky.EmbedImages_Image{
-				Image: &lexutil.LexBlob{
-					Ref:      resp.Blob.Ref,
-					MimeType: http.DetectContentType(b),
-					Size:     resp.Blob.Size,
-				},
-			})
+	for _, attachment := range attachments {
+		upload, err := bb.storage.FindUploadByUUID(attachment.UUID)
+		if err != nil {
+			log.WithError(err).Error("failed to find upload")
+			continue
 		}
 
-		if post.Embed == nil {
-			post.Embed = &bsky.FeedPost_Embed{}
+		b, err := os.ReadFile(upload.FullPath(bb.config.Upload.Path))
+		if err != nil {
+			log.WithError(err).Error("failed to read file")
+			continue
 		}
 
-		post.Embed.EmbedImages = &bsky.EmbedImages{
-			Images: images,
+		resp, err := comatproto.RepoUploadBlob(context.TODO(), client, bytes.NewReader(b))
+		if err != nil {
+			log.WithError(err).Error("failed to upload blob")
+			continue
 		}
+
+		images = append(images, &bsky.EmbedImages_Image{
+			Image: &lexutil.LexBlob{
+				Ref:      resp.Blob.Ref,
+				MimeType: http.DetectContentType(b),
+				Size:     resp.Blob.Size,
+			},
+		})
 	}
 
-	resp, err := comatproto.RepoCreateRecord(context.TODO(), client, &comatproto.RepoCreateRecord_Input{
-		Collection: "app.bsky.feed.post",
-		Repo:       client.Auth.Did,
-		Record: &lexutil.LexiconTypeDecoder{
-			Val: post,
-		},
-	})
-	if err != nil {
-		log.WithError(err).Error("failed to create post")
-		return err
+	return images
+}
+
+// Edit replaces the post with a new one. Bluesky has no way to edit a post, so
+// the message is posted again and the post referenced by the stored
+// BlueskyMeta is deleted afterwards. The old post is kept until the new one
+// exists, so a failed edit can be retried.
+func (bb *BlueskyBridge) Edit(ticker storage.Ticker, message *storage.Message) error {
+	if !ticker.Bluesky.Connected() || !ticker.Bluesky.Active {
+		return nil
 	}
 
-	// Create thread gate if reply restriction is configured
-	if ticker.Bluesky.ReplyRestriction != "" {
-		err = bb.createThreadGate(client, resp.Uri, ticker.Bluesky.ReplyRestriction)
-		if err != nil {
-			log.WithError(err).Warn("failed to create thread gate")
+	if message.Bluesky.Uri == "" {
+		return nil
+	}
+
+	previous := message.Bluesky
+	message.Bluesky = storage.BlueskyMeta{}
+
+	if err := bb.Send(ticker, message); err != nil {
+		// Remove what was posted of the new thread, the retry starts over.
+		if message.Bluesky.Uri != "" {
+			_ = bb.Delete(ticker, message)
 		}
+		message.Bluesky = previous
+		return err
 	}
 
-	message.Bluesky = storage.BlueskyMeta{
-		Handle: ticker.Bluesky.Handle,
-		Uri:    resp.Uri,
-		Cid:    resp.Cid,
+	replacement := message.Bluesky
+	message.Bluesky = previous
+	if err := bb.Delete(ticker, message); err != nil {
+		log.WithError(err).WithField("uri", previous.Uri).Error("failed to delete the post of the edited message")
 	}
+	message.Bluesky = replacement
 
 	return nil
 }
 
+// Delete removes the post of the message, and the replies if the message was
+// posted as a thread.
 func (bb *BlueskyBridge) Delete(ticker storage.Ticker, message *storage.Message) error {
 	if !ticker.Bluesky.Connected() {
 		return nil
@@ -167,14 +257,29 @@ func (bb *BlueskyBridge) Delete(ticker storage.Ticker, message *storage.Message)
 		return err
 	}
 
-	uri := message.Bluesky.Uri
+	for i := len(message.Bluesky.Replies) - 1; i >= 0; i-- {
+		if err := deletePost(client, message.Bluesky.Replies[i].Uri); err != nil {
+			log.WithError(err).Error("failed to delete reply")
+		}
+	}
+
+	err = deletePost(client, message.Bluesky.Uri)
+	if err != nil {
+		log.WithError(err).Error("failed to delete post")
+	}
+
+	return err
+}
+
+// deletePost deletes the post with the uri and its thread gate.
+func deletePost(client *xrpc.Client, uri string) error {
 	if !strings.HasPrefix(uri, "at://did:plc:") {
 		uri = "at://did:plc:" + uri
 	}
 
 	parts := strings.Split(uri, "/")
 	if len(parts) < 3 {
-		log.WithError(err).WithField("uri", uri).Error("invalid post uri")
+		log.WithField("uri", uri).Error("invalid post uri")
 		return fmt.Errorf("invalid post uri")
 	}
 	rkey := parts[len(parts)-1]
@@ -187,14 +292,11 @@ func (bb *BlueskyBridge) Delete(ticker storage.Ticker, message *storage.Message)
 		Rkey:       rkey,
 	})
 
-	_, err = comatproto.RepoDeleteRecord(context.TODO(), client, &comatproto.RepoDeleteRecord_Input{
+	_, err := comatproto.RepoDeleteRecord(context.TODO(), client, &comatproto.RepoDeleteRecord_Input{
 		Repo:       client.Auth.Did,
 		Collection: schema,
 		Rkey:       rkey,
 	})
-	if err != nil {
-		log.WithError(err).Error("failed to delete post")
-	}
 
 	return err
 }
@@ -255,3 +357,13 @@ func buildAllowRules(replyRestriction string) []*bsky.FeedThreadgate_Allow_Elem
 
 	return rules
 }
+
+// Pin does nothing, Bluesky can only pin a single post on the profile, which
+// is left to the account owner.
+func (bb *BlueskyBridge) Pin(ticker storage.Ticker, message *storage.Message) error {
+	return nil
+}
+
+func (bb *BlueskyBridge) Unpin(ticker storage.Ticker, message *storage.Message) error {
+	return nil
+}
@@ -2,6 +2,7 @@ package bridge
 
 import (
 	"errors"
+	"strings"
 
 	"github.com/h2non/gock"
 	"github.com/systemli/ticker/internal/config"
@@ -35,7 +36,8 @@ func (s *BridgeTestSuite) TestBlueskySend() {
 			Post("/xrpc/com.atproto.server.createSession").
 			Reply(401)
 
-		err := bridge.Send(tickerWithBridges, &messageWithoutBridges)
+		message := messageWithoutBridges
+		err := bridge.Send(tickerWithBridges, &message)
 		s.Error(err)
 		s.True(gock.IsDone())
 	})
@@ -65,16 +67,122 @@ func (s *BridgeTestSuite) TestBlueskySend() {
 				"cid": "sample-cid",
 			})
 
-		err := bridge.Send(tickerWithBridges, &messageWithoutBridges)
+		message := messageWithoutBridges
+		err := bridge.Send(tickerWithBridges, &message)
 		s.NoError(err)
-		s.Equal("sample-uri", messageWithoutBridges.Bluesky.Uri)
-		s.Equal("sample-cid", messageWithoutBridges.Bluesky.Cid)
-		s.Equal("handle", messageWithoutBridges.Bluesky.Handle)
+		s.Equal("sample-uri", message.Bluesky.Uri)
+		s.Equal("sample-cid", message.Bluesky.Cid)
+		s.Equal("handle", message.Bluesky.Handle)
 
 		s.True(gock.IsDone())
 		s.True(mockStorage.AssertExpectations(s.T()))
 	})
 
+	s.Run("when message is too long for a single post", func() {
+		bridge := s.blueskyBridge(config.Config{}, &storage.MockStorage{})
+
+		gock.New("https://bsky.social").
+			Post("/xrpc/com.atproto.server.createSession").
+			Reply(200).
+			JSON(map[string]string{
+				"Did":        "sample-did",
+				"AccessJwt":  "sample-access-jwt",
+				"RefreshJwt": "sample-refresh-jwt",
+			})
+
+		gock.New("https://bsky.social").
+			Post("/xrpc/com.atproto.repo.createRecord").
+			Reply(200).
+			JSON(map[string]string{
+				"uri": "root-uri",
+				"cid": "root-cid",
+			})
+
+		gock.New("https://bsky.social").
+			Post("/xrpc/com.atproto.repo.createRecord").
+			BodyString(`"reply":\{"parent":\{"cid":"root-cid","uri":"root-uri"\},"root":\{"cid":"root-cid","uri":"root-uri"\}\}`).
+			Reply(200).
+			JSON(map[string]string{
+				"uri": "reply-uri",
+				"cid": "reply-cid",
+			})
+
+		message := storage.Message{Text: strings.Repeat("Lorem ipsum dolor sit amet. ", 15)}
+		err := bridge.Send(tickerWithBridges, &message)
+		s.NoError(err)
+		s.Equal("root-uri", message.Bluesky.Uri)
+		s.Equal([]storage.BlueskyPost{{Uri: "reply-uri", Cid: "reply-cid"}}, message.Bluesky.Replies)
+		s.True(gock.IsDone())
+	})
+
+	s.Run("when a reply fails", func() {
+		bridge := s.blueskyBridge(config.Config{}, &storage.MockStorage{})
+
+		gock.New("https://bsky.social").
+			Post("/xrpc/com.atproto.server.createSession").
+			Reply(200).
+			JSON(map[string]string{
+				"Did":        "sample-did",
+				"AccessJwt":  "sample-access-jwt",
+				"RefreshJwt": "sample-refresh-jwt",
+			})
+
+		gock.New("https://bsky.social").
+			Post("/xrpc/com.atproto.repo.createRecord").
+			Reply(200).
+			JSON(map[string]string{
+				"uri": "root-uri",
+				"cid": "root-cid",
+			})
+
+		gock.New("https://bsky.social").
+			Post("/xrpc/com.atproto.repo.createRecord").
+			Reply(500)
+
+		message := storage.Message{Text: strings.Repeat("Lorem ipsum dolor sit amet. ", 15)}
+		err := bridge.Send(tickerWithBridges, &message)
+		s.Error(err)
+		s.Equal("root-uri", message.Bluesky.Uri)
+		s.Empty(message.Bluesky.Replies)
+		s.True(gock.IsDone())
+	})
+
+	s.Run("when the thread is continued", func() {
+		bridge := s.blueskyBridge(config.Config{}, &storage.MockStorage{})
+
+		gock.New("https://bsky.social").
+			Post("/xrpc/com.atproto.server.createSession").
+			Reply(200).
+			JSON(map[string]string{
+				"Did":        "sample-did",
+				"AccessJwt":  "sample-access-jwt",
+				"RefreshJwt": "sample-refresh-jwt",
+			})
+
+		gock.New("https://bsky.social").
+			Post("/xrpc/com.atproto.repo.createRecord").
+			BodyString(`"reply":\{"parent":\{"cid":"reply-cid","uri":"reply-uri"\},"root":\{"cid":"root-cid","uri":"root-uri"\}\}`).
+			Reply(200).
+			JSON(map[string]string{
+				"uri": "second-uri",
+				"cid": "second-cid",
+			})
+
+		message := storage.Message{
+			Text: strings.Repeat("Lorem ipsum dolor sit amet. ", 25),
+			Bluesky: storage.BlueskyMeta{
+				Uri:     "root-uri",
+				Cid:     "root-cid",
+				Replies: []storage.BlueskyPost{{Uri: "reply-uri", Cid: "reply-cid"}},
+			},
+		}
+		err := bridge.Send(tickerWithBridges, &message)
+		s.NoError(err)
+		s.Equal("root-uri", message.Bluesky.Uri)
+		s.Equal([]storage.BlueskyPost{{Uri: "reply-uri", Cid: "reply-cid"}, {Uri: "second-uri", Cid: "second-cid"}}, message.Bluesky.Replies)
+		s.True(gock.IsDone())
+	})
+
 	s.Run("when bluesky is active and upload is not found", func() {
 		mockStorage := &storage.MockStorage{}
 		mockStorage.On("FindUploadByUUID", "123").Return(storage.Upload{}, errors.New("not found")).Once()
@@ -100,11 +208,12 @@ func (s *BridgeTestSuite) TestBlueskySend() {
 				"cid": "sample-cid",
 			})
 
-		err := bridge.Send(tickerWithBridges, &messageWithoutBridges)
+		message := messageWithoutBridges
+		err := bridge.Send(tickerWithBridges, &message)
 		s.NoError(err)
-		s.Equal("sample-uri", messageWithoutBridges.Bluesky.Uri)
-		s.Equal("sample-cid", messageWithoutBridges.Bluesky.Cid)
-		s.Equal("handle", messageWithoutBridges.Bluesky.Handle)
+		s.Equal("sample-uri", message.Bluesky.Uri)
+		s.Equal("sample-cid", message.Bluesky.Cid)
+		s.Equal("handle", message.Bluesky.Handle)
 
 		s.True(gock.IsDone())
 		s.True(mockStorage.AssertExpectations(s.T()))
@@ -131,7 +240,8 @@ func (s *BridgeTestSuite) TestBlueskySend() {
 			Post("/xrpc/com.atproto.repo.createRecord").
 			Reply(500)
 
-		err := bridge.Send(tickerWithBridges, &messageWithoutBridges)
+		message := messageWithoutBridges
+		err := bridge.Send(tickerWithBridges, &message)
 		s.Error(err)
 		s.True(gock.IsDone())
 		s.True(mockStorage.AssertExpectations(s.T()))
@@ -228,6 +338,119 @@ func (s *BridgeTestSuite) TestBlueskyDelete() {
 		s.NoError(err)
 		s.True(gock.IsDone())
 	})
+
+	s.Run("when message was posted as a thread", func() {
+		bridge := s.blueskyBridge(config.Config{}, &storage.MockStorage{})
+
+		gock.New("https://bsky.social").
+			Post("/xrpc/com.atproto.server.createSession").
+			Reply(200).
+			JSON(map[string]string{
+				"Did":        "sample-did",
+				"AccessJwt":  "sample-access-jwt",
+				"RefreshJwt": "sample-refresh-jwt",
+			})
+
+		// Thread gate and post deletion for the reply and the root post
+		gock.New("https://bsky.social").
+			Post("/xrpc/com.atproto.repo.deleteRecord").
+			Times(4).
+			Reply(200).
+			JSON(map[string]string{})
+
+		message := messageWithBridges
+		message.Bluesky.Replies = []storage.BlueskyPost{{Uri: "at://did:plc:reply-uri", Cid: "reply-cid"}}
+		err := bridge.Delete(tickerWithBridges, &message)
+		s.NoError(err)
+		s.True(gock.IsDone())
+	})
+}
+
+func (s *BridgeTestSuite) TestBlueskyEdit() {
+	s.Run("when bluesky not connected", func() {
+		bridge := s.blueskyBridge(config.Config{}, &storage.MockStorage{})
+
+		err := bridge.Edit(tickerWithoutBridges, &messageWithBridges)
+		s.NoError(err)
+	})
+
+	s.Run("when message has no bluesky meta", func() {
+		bridge := s.blueskyBridge(config.Config{}, &storage.MockStorage{})
+
+		err := bridge.Edit(tickerWithBridges, &storage.Message{})
+		s.NoError(err)
+	})
+
+	s.Run("when delete fails", func() {
+		bridge := s.blueskyBridge(config.Config{}, &storage.MockStorage{})
+
+		gock.New("https://bsky.social").
+			Post("/xrpc/com.atproto.server.createSession").
+			Reply(401)
+
+		message := messageWithBridges
+		err := bridge.Edit(tickerWithBridges, &message)
+		s.Error(err)
+		s.Equal("at://did:plc:sample-uri", message.Bluesky.Uri)
+		s.True(gock.IsDone())
+	})
+
+	s.Run("happy path", func() {
+		bridge := s.blueskyBridge(config.Config{}, &storage.MockStorage{})
+
+		gock.New("https://bsky.social").
+			Post("/xrpc/com.atproto.server.createSession").
+			Times(2).
+			Reply(200).
+			JSON(map[string]string{
+				"Did":        "sample-did",
+				"AccessJwt":  "sample-access-jwt",
+				"RefreshJwt": "sample-refresh-jwt",
+			})
+
+		gock.New("https://bsky.social").
+			Post("/xrpc/com.atproto.repo.deleteRecord").
+			Times(2).
+			Reply(200).
+			JSON(map[string]string{})
+
+		gock.New("https://bsky.social").
+			Post("/xrpc/com.atproto.repo.createRecord").
+			Reply(200).
+			JSON(map[string]string{
+				"uri": "at://did:plc:new-uri",
+				"cid": "new-cid",
+			})
+
+		message := storage.Message{Text: "Edited", Bluesky: messageWithBridges.Bluesky}
+		err := bridge.Edit(tickerWithBridges, &message)
+		s.NoError(err)
+		s.Equal("at://did:plc:new-uri", message.Bluesky.Uri)
+		s.Equal("new-cid", message.Bluesky.Cid)
+		s.True(gock.IsDone())
+	})
+	s.Run("when the new post fails", func() {
+		bridge := s.blueskyBridge(config.Config{}, &storage.MockStorage{})
+
+		gock.New("https://bsky.social").
+			Post("/xrpc/com.atproto.server.createSession").
+			Reply(200).
+			JSON(map[string]string{
+				"Did":        "sample-did",
+				"AccessJwt":  "sample-access-jwt",
+				"RefreshJwt": "sample-refresh-jwt",
+			})
+
+		gock.New("https://bsky.social").
+			Post("/xrpc/com.atproto.repo.createRecord").
+			Reply(500)
+
+		message := storage.Message{Text: "Edited", Bluesky: messageWithBridges.Bluesky}
+		err := bridge.Edit(tickerWithBridges, &message)
+		s.Error(err)
+		s.Equal(messageWithBridges.Bluesky, message.Bluesky)
+		s.True(gock.IsDone())
+	})
 }
 
 func (s *BridgeTestSuite) blueskyBridge(config config.Config, storage storage.Storage) *BlueskyBridge {
@@ -1,6 +1,8 @@
 package bridge
 
 import (
+	"sort"
+
 	"github.com/systemli/ticker/internal/config"
 	"github.com/systemli/ticker/internal/logger"
 	"github.com/systemli/ticker/internal/storage"
@@ -9,9 +11,14 @@ import (
 var log = logger.GetWithPackage("bridge")
 
 type Bridge interface {
+	// Enabled reports whether messages of the ticker are sent to the bridge.
+	Enabled(ticker storage.Ticker) bool
 	Update(ticker storage.Ticker) error
 	Send(ticker storage.Ticker, message *storage.Message) error
+	Edit(ticker storage.Ticker, message *storage.Message) error
 	Delete(ticker storage.Ticker, message *storage.Message) error
+	Pin(ticker storage.Ticker, message *storage.Message) error
+	Unpin(ticker storage.Ticker, message *storage.Message) error
 }
 
 type Bridges map[string]Bridge
@@ -21,8 +28,27 @@ func RegisterBridges(config config.Config, storage storage.Storage) Bridges {
 	mastodon := MastodonBridge{config, storage}
 	bluesky := BlueskyBridge{config, storage}
 	signalGroup := SignalGroupBridge{config, storage}
+	matrix := MatrixBridge{config, storage}
+	webhook := WebhookBridge{config, storage}
+	activityPub := ActivityPubBridge{config, storage}
+	webPush := WebPushBridge{config, storage}
+	email := EmailBridge{config, storage}
 
-	return Bridges{"telegram": &telegram, "mastodon": &mastodon, "bluesky": &bluesky, "signalGroup": &signalGroup}
+	return Bridges{"telegram": &telegram, "mastodon": &mastodon, "bluesky": &bluesky, "signalGroup": &signalGroup, "matrix": &matrix, "webhook": &webhook, "activityPub": &activityPub, "webPush": &webPush, "email": &email}
+}
+
+// Enabled returns the names of the bridges the messages of the ticker are
+// sent to, in alphabetical order.
+func (b *Bridges) Enabled(ticker storage.Ticker) []string {
+	names := make([]string, 0)
+	for name, bridge := range *b {
+		if bridge.Enabled(ticker) {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	return names
 }
 
 func (b *Bridges) Update(ticker storage.Ticker) error {
@@ -37,9 +63,15 @@ func (b *Bridges) Update(ticker storage.Ticker) error {
 	return err
 }
 
+// Send, Edit, Delete, Pin and Unpin leave out the bridges which are not
+// selected for the message.
 func (b *Bridges) Send(ticker storage.Ticker, message *storage.Message) error {
 	var err error
 	for name, bridge := range *b {
+		if message != nil && !message.SendsTo(name) {
+			continue
+		}
+
 		err := bridge.Send(ticker, message)
 		if err != nil {
 			log.WithError(err).WithField("bridge_name", name).Error("failed to send message")
@@ -49,9 +81,29 @@ func (b *Bridges) Send(ticker storage.Ticker, message *storage.Message) error {
 	return err
 }
 
+func (b *Bridges) Edit(ticker storage.Ticker, message *storage.Message) error {
+	var err error
+	for name, bridge := range *b {
+		if message != nil && !message.SendsTo(name) {
+			continue
+		}
+
+		err := bridge.Edit(ticker, message)
+		if err != nil {
+			log.WithError(err).WithField("bridge_name", name).Error("failed to edit message")
+		}
+	}
+
+	return err
+}
+
 func (b *Bridges) Delete(ticker storage.Ticker, message *storage.Message) error {
 	var err error
 	for name, bridge := range *b {
+		if message != nil && !message.SendsTo(name) {
+			continue
+		}
+
 		err := bridge.Delete(ticker, message)
 		if err != nil {
 			log.WithError(err).WithField("bridge_name", name).Error("failed to delete message")
@@ -60,3 +112,35 @@ func (b *Bridges) Delete(ticker storage.Ticker, message *storage.Message) error
 
 	return err
 }
+
+func (b *Bridges) Pin(ticker storage.Ticker, message *storage.Message) error {
+	var err error
+	for name, bridge := range *b {
+		if message != nil && !message.SendsTo(name) {
+			continue
+		}
+
+		err := bridge.Pin(ticker, message)
+		if err != nil {
+			log.WithError(err).WithField("bridge_name", name).Error("failed to pin message")
+		}
+	}
+
+	return err
+}
+
+func (b *Bridges) Unpin(ticker storage.Ticker, message *storage.Message) error {
+	var err error
+	for name, bridge := range *b {
+		if message != nil && !message.SendsTo(name) {
+			continue
+		}
+
+		err := bridge.Unpin(ticker, message)
+		if err != nil {
+			log.WithError(err).WithField("bridge_name", name).Error("failed to unpin message")
+		}
+	}
+
+	return err
+}
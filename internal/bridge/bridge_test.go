@@ -9,8 +9,11 @@ import (
 	"github.com/h2non/gock"
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/suite"
+	"github.com/systemli/ticker/internal/activitypub"
 	"github.com/systemli/ticker/internal/config"
+	"github.com/systemli/ticker/internal/matrix"
 	"github.com/systemli/ticker/internal/storage"
+	"github.com/systemli/ticker/internal/webpush"
 )
 
 var tickerWithoutBridges storage.Ticker
@@ -23,6 +26,22 @@ type BridgeTestSuite struct {
 	suite.Suite
 }
 
+// SetupSuite lets gock intercept the clients which only connect to public
+// addresses.
+func (s *BridgeTestSuite) SetupSuite() {
+	gock.InterceptClient(activitypub.HTTPClient)
+	gock.InterceptClient(matrix.HTTPClient)
+	gock.InterceptClient(webhookClient)
+	gock.InterceptClient(webpush.HTTPClient)
+}
+
+func (s *BridgeTestSuite) TearDownSuite() {
+	gock.RestoreClient(activitypub.HTTPClient)
+	gock.RestoreClient(matrix.HTTPClient)
+	gock.RestoreClient(webhookClient)
+	gock.RestoreClient(webpush.HTTPClient)
+}
+
 func (s *BridgeTestSuite) SetupTest() {
 	log.Logger.SetOutput(io.Discard)
 	gock.DisableNetworking()
@@ -57,6 +76,12 @@ func (s *BridgeTestSuite) SetupTest() {
 			Active:  true,
 			GroupID: "sample-group-id",
 		},
+		Matrix: storage.TickerMatrix{
+			Active:      true,
+			Homeserver:  "https://matrix.example.org",
+			AccessToken: "access_token",
+			RoomID:      "!room:example.org",
+		},
 	}
 	messageWithoutBridges = storage.Message{
 		Text: "Hello World https://example.com",
@@ -97,9 +122,25 @@ func (s *BridgeTestSuite) SetupTest() {
 		SignalGroup: storage.SignalGroupMeta{
 			Timestamp: 123,
 		},
+		Matrix: storage.MatrixMeta{
+			RoomID:      "!room:example.org",
+			EventID:     "$event",
+			Attachments: []string{"$image"},
+		},
 	}
 }
 
+func (s *BridgeTestSuite) TestEnabled() {
+	ticker := storage.Ticker{}
+	enabled := MockBridge{}
+	enabled.On("Enabled", ticker).Return(true)
+	disabled := MockBridge{}
+	disabled.On("Enabled", ticker).Return(false)
+
+	bridges := Bridges{"b": &enabled, "c": &disabled, "a": &enabled}
+	s.Equal([]string{"a", "b"}, bridges.Enabled(ticker))
+}
+
 func (s *BridgeTestSuite) TestUpdate() {
 	s.Run("when successful", func() {
 		ticker := storage.Ticker{}
@@ -146,6 +187,42 @@ func (s *BridgeTestSuite) TestSend() {
 	})
 }
 
+func (s *BridgeTestSuite) TestSendWithSelectedBridges() {
+	ticker := storage.Ticker{}
+	message := &storage.Message{Bridges: []string{"signalGroup"}}
+	selected := MockBridge{}
+	selected.On("Send", ticker, message).Return(nil).Once()
+	other := MockBridge{}
+
+	bridges := Bridges{"signalGroup": &selected, "mastodon": &other}
+	s.NoError(bridges.Send(ticker, message))
+	s.True(selected.AssertExpectations(s.T()))
+	other.AssertNotCalled(s.T(), "Send", ticker, message)
+}
+
+func (s *BridgeTestSuite) TestEdit() {
+	s.Run("when successful", func() {
+		ticker := storage.Ticker{}
+		bridge := MockBridge{}
+		bridge.On("Edit", ticker, mock.Anything).Return(nil).Once()
+
+		bridges := Bridges{"mock": &bridge}
+		err := bridges.Edit(ticker, nil)
+		s.NoError(err)
+		s.True(bridge.AssertExpectations(s.T()))
+	})
+
+	s.Run("when failed", func() {
+		ticker := storage.Ticker{}
+		bridge := MockBridge{}
+		bridge.On("Edit", ticker, mock.Anything).Return(errors.New("failed to edit message")).Once()
+
+		bridges := Bridges{"mock": &bridge}
+		_ = bridges.Edit(ticker, nil)
+		s.True(bridge.AssertExpectations(s.T()))
+	})
+}
+
 func (s *BridgeTestSuite) TestDelete() {
 	s.Run("when successful", func() {
 		ticker := storage.Ticker{}
@@ -169,9 +246,55 @@ func (s *BridgeTestSuite) TestDelete() {
 	})
 }
 
+func (s *BridgeTestSuite) TestPin() {
+	s.Run("when successful", func() {
+		ticker := storage.Ticker{}
+		bridge := MockBridge{}
+		bridge.On("Pin", ticker, mock.Anything).Return(nil).Once()
+
+		bridges := Bridges{"mock": &bridge}
+		err := bridges.Pin(ticker, nil)
+		s.NoError(err)
+		s.True(bridge.AssertExpectations(s.T()))
+	})
+
+	s.Run("when failed", func() {
+		ticker := storage.Ticker{}
+		bridge := MockBridge{}
+		bridge.On("Pin", ticker, mock.Anything).Return(errors.New("failed to pin message")).Once()
+
+		bridges := Bridges{"mock": &bridge}
+		_ = bridges.Pin(ticker, nil)
+		s.True(bridge.AssertExpectations(s.T()))
+	})
+}
+
+func (s *BridgeTestSuite) TestUnpin() {
+	s.Run("when successful", func() {
+		ticker := storage.Ticker{}
+		bridge := MockBridge{}
+		bridge.On("Unpin", ticker, mock.Anything).Return(nil).Once()
+
+		bridges := Bridges{"mock": &bridge}
+		err := bridges.Unpin(ticker, nil)
+		s.NoError(err)
+		s.True(bridge.AssertExpectations(s.T()))
+	})
+
+	s.Run("when failed", func() {
+		ticker := storage.Ticker{}
+		bridge := MockBridge{}
+		bridge.On("Unpin", ticker, mock.Anything).Return(errors.New("failed to unpin message")).Once()
+
+		bridges := Bridges{"mock": &bridge}
+		_ = bridges.Unpin(ticker, nil)
+		s.True(bridge.AssertExpectations(s.T()))
+	})
+}
+
 func (s *BridgeTestSuite) TestRegisterBridges() {
 	bridges := RegisterBridges(config.Config{}, nil)
-	s.Equal(4, len(bridges))
+	s.Equal(9, len(bridges))
 }
 
 func TestBrigde(t *testing.T) {
@@ -0,0 +1,267 @@
+package bridge
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/systemli/ticker/internal/config"
+	"github.com/systemli/ticker/internal/mailer"
+	"github.com/systemli/ticker/internal/storage"
+	"github.com/systemli/ticker/internal/util"
+)
+
+const (
+	// emailBasePath is the path the sites of the tickers serve the links in
+	// the mails under, the /api/ rule of the site forwards it to the API.
+	emailBasePath = "/api/email"
+	// emailDigestPeriod is the time between two digests of a ticker.
+	emailDigestPeriod = time.Hour
+	// emailDigestInterval is how often the digests are checked for being due.
+	emailDigestInterval = time.Minute
+	// emailSubjectLimit keeps the subject of a mail to a readable length.
+	emailSubjectLimit = 80
+	// emailWorkers is the number of mails sent at the same time.
+	emailWorkers = 4
+)
+
+// EmailBridge mails the messages to the readers who subscribed to the ticker
+// and confirmed their address, or collects them for an hourly digest. A mail
+// that cannot be delivered is logged; the message is only retried when no
+// subscriber could be reached, as a retry would reach all others twice.
+type EmailBridge struct {
+	config  config.Config
+	storage storage.Storage
+}
+
+func (eb *EmailBridge) Enabled(ticker storage.Ticker) bool {
+	return ticker.Email.Active && eb.config.SMTP.Enabled()
+}
+
+func (eb *EmailBridge) Update(ticker storage.Ticker) error {
+	return nil
+}
+
+func (eb *EmailBridge) Send(ticker storage.Ticker, message *storage.Message) error {
+	if !eb.Enabled(ticker) {
+		return nil
+	}
+
+	if ticker.Email.Digest {
+		return eb.storage.SaveEmailDigestEntry(&storage.EmailDigestEntry{TickerID: ticker.ID, MessageID: message.ID})
+	}
+
+	return eb.deliver(ticker, emailSubject(ticker, *message), message.Text)
+}
+
+// Edit does nothing, mails cannot be changed once they are sent.
+func (eb *EmailBridge) Edit(ticker storage.Ticker, message *storage.Message) error {
+	return nil
+}
+
+func (eb *EmailBridge) Delete(ticker storage.Ticker, message *storage.Message) error {
+	return nil
+}
+
+func (eb *EmailBridge) Pin(ticker storage.Ticker, message *storage.Message) error {
+	return nil
+}
+
+func (eb *EmailBridge) Unpin(ticker storage.Ticker, message *storage.Message) error {
+	return nil
+}
+
+// deliver sends the text to every confirmed subscriber, each with the link to
+// unsubscribe in the footer and in the List-Unsubscribe header (RFC 8058).
+func (eb *EmailBridge) deliver(ticker storage.Ticker, subject, text string) error {
+	subscriptions, err := eb.storage.FindConfirmedEmailSubscriptions(ticker)
+	if err != nil {
+		return err
+	}
+	if len(subscriptions) == 0 {
+		return nil
+	}
+
+	client, err := mailer.NewClient(eb.config.SMTP)
+	if err != nil {
+		return err
+	}
+
+	var failed atomic.Int32
+	var lastErr atomic.Value
+	var wg sync.WaitGroup
+	queue := make(chan storage.EmailSubscription)
+	for range emailWorkers {
+		wg.Go(func() {
+			for subscription := range queue {
+				unsubscribe := EmailLink(ticker, "unsubscribe", subscription)
+				err := client.Send(context.Background(), mailer.Mail{
+					To:      subscription.Email,
+					Subject: subject,
+					Text:    text + emailFooter(ticker, unsubscribe),
+					Headers: map[string]string{
+						"List-Unsubscribe":      "<" + unsubscribe + ">",
+						"List-Unsubscribe-Post": "List-Unsubscribe=One-Click",
+					},
+				})
+				if err != nil {
+					log.WithError(err).WithField("subscription_id", subscription.ID).Error("failed to send mail")
+					failed.Add(1)
+					lastErr.Store(err)
+				}
+			}
+		})
+	}
+	for _, subscription := range subscriptions {
+		queue <- subscription
+	}
+	close(queue)
+	wg.Wait()
+
+	if int(failed.Load()) == len(subscriptions) {
+		return fmt.Errorf("no subscriber could be reached: %w", lastErr.Load().(error))
+	}
+
+	return nil
+}
+
+// EmailDigest sends the messages collected for the tickers with a digest,
+// once the oldest of them waited for an hour.
+type EmailDigest struct {
+	bridge   *EmailBridge
+	interval time.Duration
+}
+
+func NewEmailDigest(config config.Config, storage storage.Storage) *EmailDigest {
+	return &EmailDigest{
+		bridge:   &EmailBridge{config, storage},
+		interval: emailDigestInterval,
+	}
+}
+
+// Run sends due digests periodically until the context is cancelled.
+func (d *EmailDigest) Run(ctx context.Context) {
+	t := time.NewTicker(d.interval)
+	defer t.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-t.C:
+			d.SendDue(time.Now())
+		}
+	}
+}
+
+// SendDue sends the digests which are due at the given time. A digest which
+// reached none of the subscribers is tried again on the next run.
+func (d *EmailDigest) SendDue(now time.Time) {
+	store := d.bridge.storage
+
+	tickerIDs, err := store.FindDueEmailDigests(now.Add(-emailDigestPeriod))
+	if err != nil {
+		log.WithError(err).Error("failed to find due email digests")
+		return
+	}
+
+	for _, tickerID := range tickerIDs {
+		ticker, err := store.FindTickerByID(tickerID, storage.WithPreload())
+		if err != nil {
+			log.WithError(err).WithField("ticker_id", tickerID).Error("failed to find ticker for email digest")
+			continue
+		}
+
+		messages, err := store.FindEmailDigestMessages(ticker, now)
+		if err != nil {
+			log.WithError(err).WithField("ticker_id", tickerID).Error("failed to find messages for email digest")
+			continue
+		}
+
+		if d.bridge.Enabled(ticker) && len(messages) > 0 {
+			if err := d.bridge.deliver(ticker, emailDigestSubject(ticker, messages), emailDigestText(messages)); err != nil {
+				log.WithError(err).WithField("ticker_id", tickerID).Error("failed to send email digest")
+				continue
+			}
+		}
+
+		if err := store.DeleteEmailDigestEntries(ticker, now); err != nil {
+			log.WithError(err).WithField("ticker_id", tickerID).Error("failed to clear email digest")
+		}
+	}
+}
+
+// EmailLink returns the link for the subscription to the given action of the
+// public API, confirm or unsubscribe, on the first website of the ticker.
+func EmailLink(ticker storage.Ticker, action string, subscription storage.EmailSubscription) string {
+	return fmt.Sprintf("%s%s/%s?token=%s", emailOrigin(ticker), emailBasePath, action, subscription.Token)
+}
+
+// EmailConfirmation is the mail asking the reader to confirm the address, so
+// nobody receives mails from the ticker without asking for them.
+func EmailConfirmation(ticker storage.Ticker, subscription storage.EmailSubscription) mailer.Mail {
+	text := fmt.Sprintf("Someone, hopefully you, subscribed this address to the updates of %s.\n\n"+
+		"To receive them, confirm the subscription by opening this link:\n\n%s\n\n"+
+		"If you did not ask for this, ignore this mail and you will not hear from us again.",
+		ticker.Title, EmailLink(ticker, "confirm", subscription))
+
+	return mailer.Mail{
+		To:      subscription.Email,
+		Subject: "Confirm your subscription to " + ticker.Title,
+		Text:    text,
+	}
+}
+
+func emailOrigin(ticker storage.Ticker) string {
+	if len(ticker.Websites) == 0 {
+		return ""
+	}
+
+	return strings.TrimSuffix(ticker.Websites[0].Origin, "/")
+}
+
+func emailFooter(ticker storage.Ticker, unsubscribe string) string {
+	footer := "\n\n-- \n"
+	if origin := emailOrigin(ticker); origin != "" {
+		footer += "Follow the ticker: " + origin + "\n"
+	}
+
+	return footer + "Unsubscribe: " + unsubscribe + "\n"
+}
+
+// emailSubject is the ticker title together with the beginning of the
+// message, so readers find the news in their inbox.
+func emailSubject(ticker storage.Ticker, message storage.Message) string {
+	line := strings.SplitN(strings.TrimSpace(message.Text), "\n", 2)[0]
+	chunks := util.SplitText(line, emailSubjectLimit)
+	if chunks[0] == "" {
+		return ticker.Title
+	}
+
+	subject := chunks[0]
+	if len(chunks) > 1 {
+		subject += "…"
+	}
+
+	return ticker.Title + ": " + subject
+}
+
+func emailDigestSubject(ticker storage.Ticker, messages []storage.Message) string {
+	if len(messages) == 1 {
+		return emailSubject(ticker, messages[0])
+	}
+
+	return fmt.Sprintf("%s: %d new messages", ticker.Title, len(messages))
+}
+
+func emailDigestText(messages []storage.Message) string {
+	parts := make([]string, 0, len(messages))
+	for _, message := range messages {
+		parts = append(parts, message.CreatedAt.Format("2006-01-02 15:04")+"\n\n"+message.Text)
+	}
+
+	return strings.Join(parts, "\n\n* * *\n\n")
+}
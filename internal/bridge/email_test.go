@@ -0,0 +1,174 @@
+package bridge
+
+import (
+	"errors"
+	"net/mail"
+	"strings"
+	"time"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/systemli/ticker/internal/config"
+	"github.com/systemli/ticker/internal/mailer/mailertest"
+	"github.com/systemli/ticker/internal/storage"
+)
+
+func (s *BridgeTestSuite) TestEmailEnabled() {
+	server := mailertest.NewServer(s.T())
+	ticker := storage.Ticker{Email: storage.TickerEmail{Active: true}}
+
+	bridge := EmailBridge{config: config.Config{}, storage: &storage.MockStorage{}}
+	s.False(bridge.Enabled(tickerWithoutBridges))
+	s.False(bridge.Enabled(ticker))
+
+	bridge = EmailBridge{config: config.Config{SMTP: server.Config("ticker@example.org")}, storage: &storage.MockStorage{}}
+	s.False(bridge.Enabled(tickerWithoutBridges))
+	s.True(bridge.Enabled(ticker))
+}
+
+func (s *BridgeTestSuite) TestEmailSend() {
+	ticker := storage.Ticker{
+		ID:       1,
+		Title:    "Ticker",
+		Websites: []storage.TickerWebsite{{Origin: "https://ticker.example.org"}},
+		Email:    storage.TickerEmail{Active: true},
+	}
+	now := time.Now()
+	subscriptions := []storage.EmailSubscription{
+		{ID: 1, TickerID: 1, Email: "alice@example.org", Token: "alice", ConfirmedAt: &now},
+		{ID: 2, TickerID: 1, Email: "bob@example.org", Token: "bob", ConfirmedAt: &now},
+	}
+
+	s.Run("when subscriptions are not found", func() {
+		server := mailertest.NewServer(s.T())
+		store := &storage.MockStorage{}
+		store.On("FindConfirmedEmailSubscriptions", mock.Anything).Return(nil, errors.New("not found")).Once()
+		bridge := EmailBridge{config: config.Config{SMTP: server.Config("ticker@example.org")}, storage: store}
+
+		err := bridge.Send(ticker, &storage.Message{ID: 1, Text: "Hello World"})
+		s.Error(err)
+		store.AssertExpectations(s.T())
+	})
+
+	s.Run("when the mail server is unreachable", func() {
+		store := &storage.MockStorage{}
+		store.On("FindConfirmedEmailSubscriptions", mock.Anything).Return(subscriptions, nil).Once()
+		bridge := EmailBridge{config: config.Config{SMTP: config.SMTP{Host: "127.0.0.1", Port: 1, From: "ticker@example.org"}}, storage: store}
+
+		err := bridge.Send(ticker, &storage.Message{ID: 1, Text: "Hello World"})
+		s.Error(err)
+		store.AssertExpectations(s.T())
+	})
+
+	s.Run("when the message is mailed", func() {
+		server := mailertest.NewServer(s.T())
+		store := &storage.MockStorage{}
+		store.On("FindConfirmedEmailSubscriptions", mock.Anything).Return(subscriptions, nil).Once()
+		bridge := EmailBridge{config: config.Config{SMTP: server.Config("ticker@example.org")}, storage: store}
+
+		err := bridge.Send(ticker, &storage.Message{ID: 1, Text: "Hello World\n\nThe demonstration starts at noon."})
+		s.NoError(err)
+		store.AssertExpectations(s.T())
+
+		mails := server.Mails()
+		s.Len(mails, 2)
+
+		for _, m := range mails {
+			msg, err := mail.ReadMessage(strings.NewReader(m.Data))
+			s.NoError(err)
+			s.Equal("Ticker: Hello World", msg.Header.Get("Subject"))
+			s.Equal("<https://ticker.example.org/api/email/unsubscribe?token="+strings.Split(m.To[0], "@")[0]+">", msg.Header.Get("List-Unsubscribe"))
+			s.Equal("List-Unsubscribe=One-Click", msg.Header.Get("List-Unsubscribe-Post"))
+		}
+	})
+
+	s.Run("when the ticker sends a digest", func() {
+		store := &storage.MockStorage{}
+		store.On("SaveEmailDigestEntry", mock.MatchedBy(func(entry *storage.EmailDigestEntry) bool {
+			return entry.TickerID == 1 && entry.MessageID == 2
+		})).Return(nil).Once()
+		bridge := EmailBridge{config: config.Config{SMTP: config.SMTP{Host: "localhost", From: "ticker@example.org"}}, storage: store}
+
+		digestTicker := ticker
+		digestTicker.Email.Digest = true
+
+		err := bridge.Send(digestTicker, &storage.Message{ID: 2, Text: "Hello World"})
+		s.NoError(err)
+		store.AssertExpectations(s.T())
+	})
+}
+
+func (s *BridgeTestSuite) TestEmailDigestSendDue() {
+	ticker := storage.Ticker{
+		ID:       1,
+		Title:    "Ticker",
+		Websites: []storage.TickerWebsite{{Origin: "https://ticker.example.org"}},
+		Email:    storage.TickerEmail{Active: true, Digest: true},
+	}
+	now := time.Now()
+	subscriptions := []storage.EmailSubscription{{ID: 1, TickerID: 1, Email: "alice@example.org", Token: "alice", ConfirmedAt: &now}}
+	messages := []storage.Message{{ID: 1, Text: "First"}, {ID: 2, Text: "Second"}}
+
+	s.Run("when the digest is sent", func() {
+		server := mailertest.NewServer(s.T())
+		store := &storage.MockStorage{}
+		store.On("FindDueEmailDigests", now.Add(-emailDigestPeriod)).Return([]int{1}, nil).Once()
+		store.On("FindTickerByID", 1, mock.Anything).Return(ticker, nil).Once()
+		store.On("FindEmailDigestMessages", ticker, now).Return(messages, nil).Once()
+		store.On("FindConfirmedEmailSubscriptions", ticker).Return(subscriptions, nil).Once()
+		store.On("DeleteEmailDigestEntries", ticker, now).Return(nil).Once()
+
+		digest := NewEmailDigest(config.Config{SMTP: server.Config("ticker@example.org")}, store)
+		digest.SendDue(now)
+		store.AssertExpectations(s.T())
+
+		mails := server.Mails()
+		s.Len(mails, 1)
+		msg, err := mail.ReadMessage(strings.NewReader(mails[0].Data))
+		s.NoError(err)
+		s.Equal("Ticker: 2 new messages", msg.Header.Get("Subject"))
+		s.Contains(mails[0].Data, "First")
+		s.Contains(mails[0].Data, "Second")
+	})
+
+	s.Run("when the digest reaches nobody", func() {
+		store := &storage.MockStorage{}
+		store.On("FindDueEmailDigests", mock.Anything).Return([]int{1}, nil).Once()
+		store.On("FindTickerByID", 1, mock.Anything).Return(ticker, nil).Once()
+		store.On("FindEmailDigestMessages", ticker, now).Return(messages, nil).Once()
+		store.On("FindConfirmedEmailSubscriptions", ticker).Return(subscriptions, nil).Once()
+
+		digest := NewEmailDigest(config.Config{SMTP: config.SMTP{Host: "127.0.0.1", Port: 1, From: "ticker@example.org"}}, store)
+		digest.SendDue(now)
+		store.AssertExpectations(s.T())
+		store.AssertNotCalled(s.T(), "DeleteEmailDigestEntries", mock.Anything, mock.Anything)
+	})
+
+	s.Run("when email was turned off", func() {
+		store := &storage.MockStorage{}
+		store.On("FindDueEmailDigests", mock.Anything).Return([]int{1}, nil).Once()
+		store.On("FindTickerByID", 1, mock.Anything).Return(tickerWithoutBridges, nil).Once()
+		store.On("FindEmailDigestMessages", tickerWithoutBridges, now).Return(messages, nil).Once()
+		store.On("DeleteEmailDigestEntries", tickerWithoutBridges, now).Return(nil).Once()
+
+		digest := NewEmailDigest(config.Config{SMTP: config.SMTP{Host: "localhost", From: "ticker@example.org"}}, store)
+		digest.SendDue(now)
+		store.AssertExpectations(s.T())
+	})
+}
+
+func (s *BridgeTestSuite) TestEmailSubject() {
+	ticker := storage.Ticker{Title: "Ticker"}
+
+	s.Equal("Ticker", emailSubject(ticker, storage.Message{Text: "  "}))
+	s.Equal("Ticker: Police blocks the bridge", emailSubject(ticker, storage.Message{Text: "Police blocks the bridge\nTake the tunnel instead."}))
+	s.Equal("Ticker: "+strings.Repeat("a ", 39)+"a…", emailSubject(ticker, storage.Message{Text: strings.Repeat("a ", 50)}))
+}
+
+func (s *BridgeTestSuite) TestEmailConfirmation() {
+	ticker := storage.Ticker{Title: "Ticker", Websites: []storage.TickerWebsite{{Origin: "https://ticker.example.org/"}}}
+	confirmation := EmailConfirmation(ticker, storage.EmailSubscription{Email: "alice@example.org", Token: "token"})
+
+	s.Equal("alice@example.org", confirmation.To)
+	s.Equal("Confirm your subscription to Ticker", confirmation.Subject)
+	s.Contains(confirmation.Text, "https://ticker.example.org/api/email/confirm?token=token")
+}
@@ -3,21 +3,36 @@ package bridge
 import (
 	"context"
 	"errors"
+	"fmt"
+	"net/http"
+	"unicode/utf8"
 
 	"github.com/mattn/go-mastodon"
 	"github.com/systemli/ticker/internal/config"
 	"github.com/systemli/ticker/internal/storage"
+	"github.com/systemli/ticker/internal/util"
 )
 
+// mastodonStatusLimit is the default length of a status. Instances may allow
+// longer statuses, which is only asked for when a message exceeds the default.
+const mastodonStatusLimit = 500
+
 type MastodonBridge struct {
 	config  config.Config
 	storage storage.Storage
 }
 
+func (mb *MastodonBridge) Enabled(ticker storage.Ticker) bool {
+	return ticker.Mastodon.Active
+}
+
 func (mb *MastodonBridge) Update(ticker storage.Ticker) error {
 	return nil
 }
 
+// Send posts the message as a status. Messages which are too long for a
+// single status are continued in a thread of replies. When a reply fails, the
+// retry continues the thread after the last reply which was posted.
 func (mb *MastodonBridge) Send(ticker storage.Ticker, message *storage.Message) error {
 	if !ticker.Mastodon.Active {
 		return nil
@@ -26,43 +41,133 @@ func (mb *MastodonBridge) Send(ticker storage.Ticker, message *storage.Message)
 	ctx := context.Background()
 	client := client(ticker)
 
-	var mediaIDs []mastodon.ID
-	if len(message.Attachments) > 0 {
-		for _, attachment := range message.Attachments {
-			upload, err := mb.storage.FindUploadByUUID(attachment.UUID)
-			if err != nil {
-				log.WithError(err).Error("failed to find upload")
-				continue
-			}
+	chunks := util.SplitText(message.Text, statusLimit(ctx, client, message.Text))
 
-			media, err := client.UploadMedia(ctx, upload.FullPath(mb.config.Upload.Path))
-			if err != nil {
-				log.WithError(err).Error("unable to upload the attachment")
-				continue
+	if message.Mastodon.ID == "" {
+		var mediaIDs []mastodon.ID
+		if len(message.Attachments) > 0 {
+			for _, attachment := range message.Attachments {
+				upload, err := mb.storage.FindUploadByUUID(attachment.UUID)
+				if err != nil {
+					log.WithError(err).Error("failed to find upload")
+					continue
+				}
+
+				media, err := client.UploadMedia(ctx, upload.FullPath(mb.config.Upload.Path))
+				if err != nil {
+					log.WithError(err).Error("unable to upload the attachment")
+					continue
+				}
+				mediaIDs = append(mediaIDs, media.ID)
 			}
-			mediaIDs = append(mediaIDs, media.ID)
+		}
+
+		toot := mastodon.Toot{
+			Status:   chunks[0],
+			MediaIDs: mediaIDs,
+		}
+
+		status, err := client.PostStatus(ctx, &toot)
+		if err != nil {
+			return err
+		}
+
+		message.Mastodon = storage.MastodonMeta{
+			ID:  string(status.ID),
+			URI: status.URI,
+			URL: status.URL,
 		}
 	}
 
+	parent := mastodon.ID(message.Mastodon.ID)
+	if n := len(message.Mastodon.Replies); n > 0 {
+		parent = mastodon.ID(message.Mastodon.Replies[n-1])
+	}
+
+	for _, chunk := range chunks[min(1+len(message.Mastodon.Replies), len(chunks)):] {
+		// What was posted so far is kept on the message, so it can be
+		// deleted along with it and the retry does not post it again.
+		reply, err := client.PostStatus(ctx, &mastodon.Toot{Status: chunk, InReplyToID: parent})
+		if err != nil {
+			return fmt.Errorf("failed to post reply: %w", err)
+		}
+
+		parent = reply.ID
+		message.Mastodon.Replies = append(message.Mastodon.Replies, string(reply.ID))
+	}
+
+	return nil
+}
+
+// Edit updates the status in place. Mastodon drops media that is not listed in
+// an update, so the attachments of the current status are passed along. When
+// the text needs more or fewer replies than before, replies are posted to or
+// deleted from the end of the thread.
+func (mb *MastodonBridge) Edit(ticker storage.Ticker, message *storage.Message) error {
+	if !ticker.Mastodon.Active || message.Mastodon.ID == "" {
+		return nil
+	}
+
+	ctx := context.Background()
+	client := client(ticker)
+
+	status, err := client.GetStatus(ctx, mastodon.ID(message.Mastodon.ID))
+	if err != nil {
+		return err
+	}
+
+	var mediaIDs []mastodon.ID
+	for _, attachment := range status.MediaAttachments {
+		mediaIDs = append(mediaIDs, attachment.ID)
+	}
+
+	chunks := util.SplitText(message.Text, statusLimit(ctx, client, message.Text))
+
 	toot := mastodon.Toot{
-		Status:   message.Text,
+		Status:   chunks[0],
 		MediaIDs: mediaIDs,
 	}
 
-	status, err := client.PostStatus(ctx, &toot)
+	status, err = client.UpdateStatus(ctx, &toot, status.ID)
 	if err != nil {
 		return err
 	}
 
-	message.Mastodon = storage.MastodonMeta{
-		ID:  string(status.ID),
-		URI: status.URI,
-		URL: status.URL,
+	message.Mastodon.URI = status.URI
+	message.Mastodon.URL = status.URL
+
+	replies := message.Mastodon.Replies
+	message.Mastodon.Replies = nil
+	parent := status.ID
+	for i, chunk := range chunks[1:] {
+		var reply *mastodon.Status
+		if i < len(replies) {
+			reply, err = client.UpdateStatus(ctx, &mastodon.Toot{Status: chunk}, mastodon.ID(replies[i]))
+		} else {
+			reply, err = client.PostStatus(ctx, &mastodon.Toot{Status: chunk, InReplyToID: parent})
+		}
+		if err != nil {
+			// Keep the replies which were not touched, so they are still
+			// deleted along with the message.
+			message.Mastodon.Replies = append(message.Mastodon.Replies, replies[min(i, len(replies)):]...)
+			return err
+		}
+
+		parent = reply.ID
+		message.Mastodon.Replies = append(message.Mastodon.Replies, string(reply.ID))
+	}
+
+	for i := len(replies) - 1; i >= len(chunks)-1; i-- {
+		if err := client.DeleteStatus(ctx, mastodon.ID(replies[i])); err != nil {
+			log.WithError(err).Error("failed to delete reply")
+		}
 	}
 
 	return nil
 }
 
+// Delete removes the status, and the replies if the message was posted as a
+// thread.
 func (mb *MastodonBridge) Delete(ticker storage.Ticker, message *storage.Message) error {
 	if message.Mastodon.ID == "" {
 		return nil
@@ -75,9 +180,71 @@ func (mb *MastodonBridge) Delete(ticker storage.Ticker, message *storage.Message
 	ctx := context.Background()
 	client := client(ticker)
 
+	for i := len(message.Mastodon.Replies) - 1; i >= 0; i-- {
+		if err := client.DeleteStatus(ctx, mastodon.ID(message.Mastodon.Replies[i])); err != nil {
+			log.WithError(err).Error("failed to delete reply")
+		}
+	}
+
 	return client.DeleteStatus(ctx, mastodon.ID(message.Mastodon.ID))
 }
 
+// Pin features the status on the profile of the account.
+func (mb *MastodonBridge) Pin(ticker storage.Ticker, message *storage.Message) error {
+	return mb.pin(ticker, message, "pin")
+}
+
+func (mb *MastodonBridge) Unpin(ticker storage.Ticker, message *storage.Message) error {
+	return mb.pin(ticker, message, "unpin")
+}
+
+// pin calls the pin or unpin endpoint of the status. The client library has no
+// support for them, so the request is made with its http client directly.
+func (mb *MastodonBridge) pin(ticker storage.Ticker, message *storage.Message, action string) error {
+	if !ticker.Mastodon.Active || message.Mastodon.ID == "" {
+		return nil
+	}
+
+	client := client(ticker)
+	url := fmt.Sprintf("%s/api/v1/statuses/%s/%s", ticker.Mastodon.Server, message.Mastodon.ID, action)
+	req, err := http.NewRequestWithContext(context.Background(), http.MethodPost, url, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "Bearer "+ticker.Mastodon.AccessToken)
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("unable to %s the status: %s", action, resp.Status)
+	}
+
+	return nil
+}
+
+// statusLimit returns the maximum length of a status on the instance. The
+// instance is only asked when the text is longer than the default limit.
+func statusLimit(ctx context.Context, client *mastodon.Client, text string) int {
+	if utf8.RuneCountInString(text) <= mastodonStatusLimit {
+		return mastodonStatusLimit
+	}
+
+	instance, err := client.GetInstance(ctx)
+	if err != nil || instance.Configuration == nil || instance.Configuration.Statuses == nil {
+		return mastodonStatusLimit
+	}
+
+	if max, ok := (*instance.Configuration.Statuses)["max_characters"].(float64); ok && max > 0 {
+		return int(max)
+	}
+
+	return mastodonStatusLimit
+}
+
 func client(ticker storage.Ticker) *mastodon.Client {
 	return mastodon.NewClient(&mastodon.Config{
 		Server:       ticker.Mastodon.Server,
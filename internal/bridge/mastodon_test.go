@@ -2,6 +2,7 @@ package bridge
 
 import (
 	"errors"
+	"strings"
 
 	"github.com/h2non/gock"
 	"github.com/systemli/ticker/internal/config"
@@ -39,11 +40,12 @@ func (s *BridgeTestSuite) TestMastodonSend() {
 				"url": "https://systemli.social/@systemli/123",
 			})
 
-		err := bridge.Send(tickerWithBridges, &messageWithoutBridges)
+		message := messageWithoutBridges
+		err := bridge.Send(tickerWithBridges, &message)
 		s.NoError(err)
-		s.Equal("123", messageWithoutBridges.Mastodon.ID)
-		s.Equal("https://systemli.social/@systemli/123", messageWithoutBridges.Mastodon.URI)
-		s.Equal("https://systemli.social/@systemli/123", messageWithoutBridges.Mastodon.URL)
+		s.Equal("123", message.Mastodon.ID)
+		s.Equal("https://systemli.social/@systemli/123", message.Mastodon.URI)
+		s.Equal("https://systemli.social/@systemli/123", message.Mastodon.URL)
 		s.True(gock.IsDone())
 		s.True(mockStorage.AssertExpectations(s.T()))
 	})
@@ -62,11 +64,12 @@ func (s *BridgeTestSuite) TestMastodonSend() {
 				"url": "https://systemli.social/@systemli/123",
 			})
 
-		err := bridge.Send(tickerWithBridges, &messageWithoutBridges)
+		message := messageWithoutBridges
+		err := bridge.Send(tickerWithBridges, &message)
 		s.NoError(err)
-		s.Equal("123", messageWithoutBridges.Mastodon.ID)
-		s.Equal("https://systemli.social/@systemli/123", messageWithoutBridges.Mastodon.URI)
-		s.Equal("https://systemli.social/@systemli/123", messageWithoutBridges.Mastodon.URL)
+		s.Equal("123", message.Mastodon.ID)
+		s.Equal("https://systemli.social/@systemli/123", message.Mastodon.URI)
+		s.Equal("https://systemli.social/@systemli/123", message.Mastodon.URL)
 		s.True(gock.IsDone())
 		s.True(mockStorage.AssertExpectations(s.T()))
 	})
@@ -80,11 +83,240 @@ func (s *BridgeTestSuite) TestMastodonSend() {
 			Post("/api/v1/statuses").
 			Reply(500)
 
-		err := bridge.Send(tickerWithBridges, &messageWithoutBridges)
+		message := messageWithoutBridges
+		err := bridge.Send(tickerWithBridges, &message)
 		s.Error(err)
 		s.True(gock.IsDone())
 		s.True(mockStorage.AssertExpectations(s.T()))
 	})
+
+	s.Run("when message is too long for a single status", func() {
+		bridge := s.mastodonBridge(config.Config{}, &storage.MockStorage{})
+
+		gock.New("https://systemli.social").
+			Get("/api/v1/instance").
+			Reply(200).
+			JSON(map[string]interface{}{
+				"configuration": map[string]interface{}{
+					"statuses": map[string]interface{}{"max_characters": 400},
+				},
+			})
+
+		gock.New("https://systemli.social").
+			Post("/api/v1/statuses").
+			Reply(200).
+			JSON(map[string]string{"id": "123"})
+
+		gock.New("https://systemli.social").
+			Post("/api/v1/statuses").
+			BodyString("in_reply_to_id=123").
+			Reply(200).
+			JSON(map[string]string{"id": "124"})
+
+		message := storage.Message{Text: strings.Repeat("Lorem ipsum dolor sit amet. ", 20)}
+		err := bridge.Send(tickerWithBridges, &message)
+		s.NoError(err)
+		s.Equal("123", message.Mastodon.ID)
+		s.Equal([]string{"124"}, message.Mastodon.Replies)
+		s.True(gock.IsDone())
+	})
+
+	s.Run("when a reply fails", func() {
+		bridge := s.mastodonBridge(config.Config{}, &storage.MockStorage{})
+
+		gock.New("https://systemli.social").
+			Get("/api/v1/instance").
+			Reply(200).
+			JSON(map[string]interface{}{
+				"configuration": map[string]interface{}{
+					"statuses": map[string]interface{}{"max_characters": 400},
+				},
+			})
+
+		gock.New("https://systemli.social").
+			Post("/api/v1/statuses").
+			Reply(200).
+			JSON(map[string]string{"id": "123"})
+
+		gock.New("https://systemli.social").
+			Post("/api/v1/statuses").
+			BodyString("in_reply_to_id=123").
+			Reply(500)
+
+		message := storage.Message{Text: strings.Repeat("Lorem ipsum dolor sit amet. ", 20)}
+		err := bridge.Send(tickerWithBridges, &message)
+		s.Error(err)
+		s.Equal("123", message.Mastodon.ID)
+		s.Empty(message.Mastodon.Replies)
+		s.True(gock.IsDone())
+	})
+
+	s.Run("when the thread is continued", func() {
+		bridge := s.mastodonBridge(config.Config{}, &storage.MockStorage{})
+
+		gock.New("https://systemli.social").
+			Get("/api/v1/instance").
+			Reply(200).
+			JSON(map[string]interface{}{
+				"configuration": map[string]interface{}{
+					"statuses": map[string]interface{}{"max_characters": 400},
+				},
+			})
+
+		gock.New("https://systemli.social").
+			Post("/api/v1/statuses").
+			BodyString("in_reply_to_id=124").
+			Reply(200).
+			JSON(map[string]string{"id": "125"})
+
+		message := storage.Message{
+			Text:     strings.Repeat("Lorem ipsum dolor sit amet. ", 40),
+			Mastodon: storage.MastodonMeta{ID: "123", Replies: []string{"124"}},
+		}
+		err := bridge.Send(tickerWithBridges, &message)
+		s.NoError(err)
+		s.Equal("123", message.Mastodon.ID)
+		s.Equal([]string{"124", "125"}, message.Mastodon.Replies)
+		s.True(gock.IsDone())
+	})
+}
+
+func (s *BridgeTestSuite) TestMastodonEdit() {
+	s.Run("when message has no mastodon meta", func() {
+		bridge := s.mastodonBridge(config.Config{}, &storage.MockStorage{})
+
+		err := bridge.Edit(tickerWithBridges, &storage.Message{})
+		s.NoError(err)
+	})
+
+	s.Run("when mastodon is inactive", func() {
+		bridge := s.mastodonBridge(config.Config{}, &storage.MockStorage{})
+
+		err := bridge.Edit(tickerWithoutBridges, &messageWithBridges)
+		s.NoError(err)
+	})
+
+	s.Run("when status can not be fetched", func() {
+		bridge := s.mastodonBridge(config.Config{}, &storage.MockStorage{})
+
+		gock.New("https://systemli.social").
+			Get("/api/v1/statuses/123").
+			Reply(404)
+
+		err := bridge.Edit(tickerWithBridges, &messageWithBridges)
+		s.Error(err)
+		s.True(gock.IsDone())
+	})
+
+	s.Run("when mastodon is active", func() {
+		bridge := s.mastodonBridge(config.Config{}, &storage.MockStorage{})
+
+		gock.New("https://systemli.social").
+			Get("/api/v1/statuses/123").
+			Reply(200).
+			JSON(map[string]interface{}{
+				"id":                "123",
+				"media_attachments": []map[string]string{{"id": "456"}},
+			})
+
+		gock.New("https://systemli.social").
+			Put("/api/v1/statuses/123").
+			BodyString("media_ids%5B%5D=456").
+			Reply(200).
+			JSON(map[string]string{
+				"id":  "123",
+				"uri": "https://systemli.social/@systemli/123",
+				"url": "https://systemli.social/@systemli/123",
+			})
+
+		message := storage.Message{Text: "Edited", Mastodon: storage.MastodonMeta{ID: "123"}}
+		err := bridge.Edit(tickerWithBridges, &message)
+		s.NoError(err)
+		s.Equal("123", message.Mastodon.ID)
+		s.Equal("https://systemli.social/@systemli/123", message.Mastodon.URL)
+		s.True(gock.IsDone())
+	})
+
+	s.Run("when the thread gets shorter", func() {
+		bridge := s.mastodonBridge(config.Config{}, &storage.MockStorage{})
+
+		gock.New("https://systemli.social").
+			Get("/api/v1/statuses/123").
+			Reply(200).
+			JSON(map[string]interface{}{"id": "123"})
+
+		gock.New("https://systemli.social").
+			Put("/api/v1/statuses/123").
+			Reply(200).
+			JSON(map[string]string{"id": "123"})
+
+		gock.New("https://systemli.social").
+			Delete("/api/v1/statuses/124").
+			Reply(200)
+
+		message := storage.Message{Text: "Edited", Mastodon: storage.MastodonMeta{ID: "123", Replies: []string{"124"}}}
+		err := bridge.Edit(tickerWithBridges, &message)
+		s.NoError(err)
+		s.Empty(message.Mastodon.Replies)
+		s.True(gock.IsDone())
+	})
+}
+
+func (s *BridgeTestSuite) TestMastodonPin() {
+	s.Run("when message has no mastodon meta", func() {
+		bridge := s.mastodonBridge(config.Config{}, &storage.MockStorage{})
+
+		err := bridge.Pin(tickerWithBridges, &storage.Message{})
+		s.NoError(err)
+	})
+
+	s.Run("when mastodon is inactive", func() {
+		bridge := s.mastodonBridge(config.Config{}, &storage.MockStorage{})
+
+		err := bridge.Pin(tickerWithoutBridges, &messageWithBridges)
+		s.NoError(err)
+	})
+
+	s.Run("when pinning fails", func() {
+		bridge := s.mastodonBridge(config.Config{}, &storage.MockStorage{})
+
+		gock.New("https://systemli.social").
+			Post("/api/v1/statuses/123/pin").
+			Reply(422)
+
+		err := bridge.Pin(tickerWithBridges, &messageWithBridges)
+		s.Error(err)
+		s.True(gock.IsDone())
+	})
+
+	s.Run("when mastodon is active", func() {
+		bridge := s.mastodonBridge(config.Config{}, &storage.MockStorage{})
+
+		gock.New("https://systemli.social").
+			Post("/api/v1/statuses/123/pin").
+			MatchHeader("Authorization", "Bearer access_token").
+			Reply(200).
+			JSON(map[string]interface{}{"id": "123", "pinned": true})
+
+		err := bridge.Pin(tickerWithBridges, &messageWithBridges)
+		s.NoError(err)
+		s.True(gock.IsDone())
+	})
+}
+
+func (s *BridgeTestSuite) TestMastodonUnpin() {
+	s.Run("when mastodon is active", func() {
+		bridge := s.mastodonBridge(config.Config{}, &storage.MockStorage{})
+
+		gock.New("https://systemli.social").
+			Post("/api/v1/statuses/123/unpin").
+			Reply(200).
+			JSON(map[string]interface{}{"id": "123", "pinned": false})
+
+		err := bridge.Unpin(tickerWithBridges, &messageWithBridges)
+		s.NoError(err)
+		s.True(gock.IsDone())
+	})
 }
 
 func (s *BridgeTestSuite) TestMastodonDelete() {
@@ -114,6 +346,24 @@ func (s *BridgeTestSuite) TestMastodonDelete() {
 		s.NoError(err)
 		s.True(gock.IsDone())
 	})
+
+	s.Run("when message was posted as a thread", func() {
+		bridge := s.mastodonBridge(config.Config{}, &storage.MockStorage{})
+
+		gock.New("https://systemli.social").
+			Delete("/api/v1/statuses/124").
+			Reply(200)
+
+		gock.New("https://systemli.social").
+			Delete("/api/v1/statuses/123").
+			Reply(200)
+
+		message := messageWithBridges
+		message.Mastodon.Replies = []string{"124"}
+		err := bridge.Delete(tickerWithBridges, &message)
+		s.NoError(err)
+		s.True(gock.IsDone())
+	})
 }
 
 func (s *BridgeTestSuite) mastodonBridge(config config.Config, storage storage.Storage) *MastodonBridge {
@@ -0,0 +1,156 @@
+package bridge
+
+import (
+	"context"
+	"os"
+
+	"github.com/systemli/ticker/internal/config"
+	"github.com/systemli/ticker/internal/matrix"
+	"github.com/systemli/ticker/internal/storage"
+)
+
+type MatrixBridge struct {
+	config  config.Config
+	storage storage.Storage
+}
+
+type matrixText struct {
+	MsgType    string              `json:"msgtype"`
+	Body       string              `json:"body"`
+	NewContent *matrixText         `json:"m.new_content,omitempty"`
+	RelatesTo  *matrixRelationship `json:"m.relates_to,omitempty"`
+}
+
+type matrixRelationship struct {
+	RelType string `json:"rel_type"`
+	EventID string `json:"event_id"`
+}
+
+type matrixImage struct {
+	MsgType string          `json:"msgtype"`
+	Body    string          `json:"body"`
+	URL     string          `json:"url"`
+	Info    matrixImageInfo `json:"info"`
+}
+
+type matrixImageInfo struct {
+	MimeType string `json:"mimetype"`
+	Size     int    `json:"size"`
+}
+
+func (mb *MatrixBridge) Enabled(ticker storage.Ticker) bool {
+	return ticker.Matrix.Connected() && ticker.Matrix.Active
+}
+
+func (mb *MatrixBridge) Update(ticker storage.Ticker) error {
+	return nil
+}
+
+// Send posts the text of the message to the room, followed by one event for
+// each image.
+func (mb *MatrixBridge) Send(ticker storage.Ticker, message *storage.Message) error {
+	if !mb.Enabled(ticker) {
+		return nil
+	}
+
+	ctx := context.Background()
+	client := matrix.NewClient(ticker.Matrix.Homeserver, ticker.Matrix.AccessToken)
+
+	eventID, err := client.SendMessage(ctx, ticker.Matrix.RoomID, matrixText{MsgType: "m.text", Body: message.Text})
+	if err != nil {
+		return err
+	}
+
+	message.Matrix = storage.MatrixMeta{
+		RoomID:  ticker.Matrix.RoomID,
+		EventID: eventID,
+	}
+
+	for _, attachment := range message.Attachments {
+		upload, err := mb.storage.FindUploadByUUID(attachment.UUID)
+		if err != nil {
+			log.WithError(err).Error("failed to find upload")
+			continue
+		}
+
+		b, err := os.ReadFile(upload.FullPath(mb.config.Upload.Path))
+		if err != nil {
+			log.WithError(err).Error("failed to read file")
+			continue
+		}
+
+		uri, err := client.Upload(ctx, upload.FileName(), upload.ContentType, b)
+		if err != nil {
+			log.WithError(err).Error("failed to upload the attachment")
+			continue
+		}
+
+		image := matrixImage{
+			MsgType: "m.image",
+			Body:    upload.FileName(),
+			URL:     uri,
+			Info:    matrixImageInfo{MimeType: upload.ContentType, Size: len(b)},
+		}
+		eventID, err := client.SendMessage(ctx, ticker.Matrix.RoomID, image)
+		if err != nil {
+			log.WithError(err).Error("failed to send the attachment")
+			continue
+		}
+
+		message.Matrix.Attachments = append(message.Matrix.Attachments, eventID)
+	}
+
+	return nil
+}
+
+// Edit sends a replacement for the text event, which clients show in place of
+// the original text, marked as edited.
+func (mb *MatrixBridge) Edit(ticker storage.Ticker, message *storage.Message) error {
+	if !mb.Enabled(ticker) || message.Matrix.EventID == "" {
+		return nil
+	}
+
+	client := matrix.NewClient(ticker.Matrix.Homeserver, ticker.Matrix.AccessToken)
+	content := matrixText{
+		MsgType:    "m.text",
+		Body:       "* " + message.Text,
+		NewContent: &matrixText{MsgType: "m.text", Body: message.Text},
+		RelatesTo:  &matrixRelationship{RelType: "m.replace", EventID: message.Matrix.EventID},
+	}
+
+	_, err := client.SendMessage(context.Background(), message.Matrix.RoomID, content)
+
+	return err
+}
+
+// Delete redacts the events of the images and the text.
+func (mb *MatrixBridge) Delete(ticker storage.Ticker, message *storage.Message) error {
+	if message.Matrix.EventID == "" {
+		return nil
+	}
+
+	if ticker.Matrix.Homeserver == "" || ticker.Matrix.AccessToken == "" {
+		return nil
+	}
+
+	ctx := context.Background()
+	client := matrix.NewClient(ticker.Matrix.Homeserver, ticker.Matrix.AccessToken)
+
+	for _, eventID := range message.Matrix.Attachments {
+		if err := client.Redact(ctx, message.Matrix.RoomID, eventID); err != nil {
+			log.WithError(err).Error("failed to redact the attachment")
+		}
+	}
+
+	return client.Redact(ctx, message.Matrix.RoomID, message.Matrix.EventID)
+}
+
+// Pin does nothing, pinned events in Matrix need a power level the account
+// usually does not have.
+func (mb *MatrixBridge) Pin(ticker storage.Ticker, message *storage.Message) error {
+	return nil
+}
+
+func (mb *MatrixBridge) Unpin(ticker storage.Ticker, message *storage.Message) error {
+	return nil
+}
@@ -0,0 +1,142 @@
+package bridge
+
+import (
+	"errors"
+
+	"github.com/h2non/gock"
+	"github.com/systemli/ticker/internal/config"
+	"github.com/systemli/ticker/internal/storage"
+)
+
+func (s *BridgeTestSuite) TestMatrixEnabled() {
+	bridge := s.matrixBridge(config.Config{}, &storage.MockStorage{})
+
+	s.True(bridge.Enabled(tickerWithBridges))
+	s.False(bridge.Enabled(tickerWithoutBridges))
+}
+
+func (s *BridgeTestSuite) TestMatrixSend() {
+	s.Run("when matrix is inactive", func() {
+		bridge := s.matrixBridge(config.Config{}, &storage.MockStorage{})
+
+		err := bridge.Send(tickerWithoutBridges, &messageWithoutBridges)
+		s.NoError(err)
+	})
+
+	s.Run("when sending fails", func() {
+		bridge := s.matrixBridge(config.Config{}, &storage.MockStorage{})
+
+		gock.New("https://matrix.example.org").
+			Put("/_matrix/client/v3/rooms/!room:example.org/send/m.room.message/.+").
+			Reply(403).
+			JSON(map[string]string{"errcode": "M_FORBIDDEN", "error": "not in the room"})
+
+		message := storage.Message{Text: "Hello World"}
+		err := bridge.Send(tickerWithBridges, &message)
+		s.Error(err)
+		s.Empty(message.Matrix.EventID)
+		s.True(gock.IsDone())
+	})
+
+	s.Run("when upload is not found", func() {
+		mockStorage := &storage.MockStorage{}
+		mockStorage.On("FindUploadByUUID", "123").Return(storage.Upload{}, errors.New("upload not found")).Once()
+		bridge := s.matrixBridge(config.Config{}, mockStorage)
+
+		gock.New("https://matrix.example.org").
+			Put("/_matrix/client/v3/rooms/!room:example.org/send/m.room.message/.+").
+			MatchHeader("Authorization", "Bearer access_token").
+			JSON(map[string]string{"msgtype": "m.text", "body": "Hello World https://example.com"}).
+			Reply(200).
+			JSON(map[string]string{"event_id": "$event"})
+
+		message := messageWithoutBridges
+		err := bridge.Send(tickerWithBridges, &message)
+		s.NoError(err)
+		s.Equal("!room:example.org", message.Matrix.RoomID)
+		s.Equal("$event", message.Matrix.EventID)
+		s.Empty(message.Matrix.Attachments)
+		s.True(gock.IsDone())
+		s.True(mockStorage.AssertExpectations(s.T()))
+	})
+}
+
+func (s *BridgeTestSuite) TestMatrixEdit() {
+	s.Run("when message has no matrix meta", func() {
+		bridge := s.matrixBridge(config.Config{}, &storage.MockStorage{})
+
+		err := bridge.Edit(tickerWithBridges, &storage.Message{})
+		s.NoError(err)
+	})
+
+	s.Run("when matrix is active", func() {
+		bridge := s.matrixBridge(config.Config{}, &storage.MockStorage{})
+
+		gock.New("https://matrix.example.org").
+			Put("/_matrix/client/v3/rooms/!room:example.org/send/m.room.message/.+").
+			BodyString(`"m.relates_to":\{"rel_type":"m.replace","event_id":"\$event"\}`).
+			Reply(200).
+			JSON(map[string]string{"event_id": "$edit"})
+
+		message := messageWithBridges
+		message.Text = "Edited"
+		err := bridge.Edit(tickerWithBridges, &message)
+		s.NoError(err)
+		s.Equal("$event", message.Matrix.EventID)
+		s.True(gock.IsDone())
+	})
+}
+
+func (s *BridgeTestSuite) TestMatrixDelete() {
+	s.Run("when message has no matrix meta", func() {
+		bridge := s.matrixBridge(config.Config{}, &storage.MockStorage{})
+
+		err := bridge.Delete(tickerWithBridges, &messageWithoutBridges)
+		s.NoError(err)
+	})
+
+	s.Run("when redacting fails", func() {
+		bridge := s.matrixBridge(config.Config{}, &storage.MockStorage{})
+
+		gock.New("https://matrix.example.org").
+			Put("/_matrix/client/v3/rooms/!room:example.org/redact/.+").
+			Times(2).
+			Reply(500)
+
+		err := bridge.Delete(tickerWithBridges, &messageWithBridges)
+		s.Error(err)
+		s.True(gock.IsDone())
+	})
+
+	s.Run("when matrix is active", func() {
+		bridge := s.matrixBridge(config.Config{}, &storage.MockStorage{})
+
+		gock.New("https://matrix.example.org").
+			Put("/_matrix/client/v3/rooms/!room:example.org/redact/\\$image/.+").
+			Reply(200).
+			JSON(map[string]string{"event_id": "$redaction"})
+
+		gock.New("https://matrix.example.org").
+			Put("/_matrix/client/v3/rooms/!room:example.org/redact/\\$event/.+").
+			Reply(200).
+			JSON(map[string]string{"event_id": "$redaction"})
+
+		err := bridge.Delete(tickerWithBridges, &messageWithBridges)
+		s.NoError(err)
+		s.True(gock.IsDone())
+	})
+}
+
+func (s *BridgeTestSuite) TestMatrixPin() {
+	bridge := s.matrixBridge(config.Config{}, &storage.MockStorage{})
+
+	s.NoError(bridge.Pin(tickerWithBridges, &messageWithBridges))
+	s.NoError(bridge.Unpin(tickerWithBridges, &messageWithBridges))
+}
+
+func (s *BridgeTestSuite) matrixBridge(config config.Config, storage storage.Storage) *MatrixBridge {
+	return &MatrixBridge{
+		config:  config,
+		storage: storage,
+	}
+}
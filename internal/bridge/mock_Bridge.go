@@ -47,6 +47,60 @@ func (_m *MockBridge) Delete(ticker storage.Ticker, message *storage.Message) er
 	return r0
 }
 
+// Enabled provides a mock function with given fields: ticker
+func (_m *MockBridge) Enabled(ticker storage.Ticker) bool {
+	ret := _m.Called(ticker)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Enabled")
+	}
+
+	var r0 bool
+	if rf, ok := ret.Get(0).(func(storage.Ticker) bool); ok {
+		r0 = rf(ticker)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	return r0
+}
+
+// Edit provides a mock function with given fields: ticker, message
+func (_m *MockBridge) Edit(ticker storage.Ticker, message *storage.Message) error {
+	ret := _m.Called(ticker, message)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Edit")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(storage.Ticker, *storage.Message) error); ok {
+		r0 = rf(ticker, message)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Pin provides a mock function with given fields: ticker, message
+func (_m *MockBridge) Pin(ticker storage.Ticker, message *storage.Message) error {
+	ret := _m.Called(ticker, message)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Pin")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(storage.Ticker, *storage.Message) error); ok {
+		r0 = rf(ticker, message)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // Send provides a mock function with given fields: ticker, message
 func (_m *MockBridge) Send(ticker storage.Ticker, message *storage.Message) error {
 	ret := _m.Called(ticker, message)
@@ -65,6 +119,24 @@ func (_m *MockBridge) Send(ticker storage.Ticker, message *storage.Message) erro
 	return r0
 }
 
+// Unpin provides a mock function with given fields: ticker, message
+func (_m *MockBridge) Unpin(ticker storage.Ticker, message *storage.Message) error {
+	ret := _m.Called(ticker, message)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Unpin")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(storage.Ticker, *storage.Message) error); ok {
+		r0 = rf(ticker, message)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // NewMockBridge creates a new instance of MockBridge. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
 // The first argument is typically a *testing.T value.
 func NewMockBridge(t interface {
@@ -0,0 +1,240 @@
+package bridge
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/systemli/ticker/internal/storage"
+	"gorm.io/gorm"
+)
+
+const (
+	// outboxInterval is how often the outbox looks for due jobs.
+	outboxInterval = 2 * time.Second
+	// outboxWorkers is the number of deliveries running at the same time.
+	outboxWorkers = 4
+	// outboxLease is how long a claimed job is left alone before it is
+	// assumed lost, e.g. because the process was stopped while delivering.
+	outboxLease = 5 * time.Minute
+	// outboxMaxAttempts is the number of attempts before a job fails for good.
+	outboxMaxAttempts = 10
+	outboxRetryBase   = 30 * time.Second
+	outboxRetryMax    = time.Hour
+	// outboxEditWait is how long an edit or a deletion waits for the message
+	// to be sent to the bridge before it looks again.
+	outboxEditWait = 10 * time.Second
+)
+
+// Outbox delivers queued messages to the bridges with a pool of workers and
+// retries failed deliveries with exponential backoff.
+type Outbox struct {
+	bridges  Bridges
+	storage  storage.Storage
+	interval time.Duration
+	workers  int
+}
+
+func NewOutbox(bridges Bridges, storage storage.Storage) *Outbox {
+	return &Outbox{
+		bridges:  bridges,
+		storage:  storage,
+		interval: outboxInterval,
+		workers:  outboxWorkers,
+	}
+}
+
+// Run delivers due jobs until the context is cancelled. Deliveries which are
+// running at that moment are finished first.
+func (o *Outbox) Run(ctx context.Context) {
+	jobs := make(chan storage.OutboxJob)
+	var wg sync.WaitGroup
+	for i := 0; i < o.workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for job := range jobs {
+				o.Deliver(job)
+			}
+		}()
+	}
+	defer wg.Wait()
+	defer close(jobs)
+
+	t := time.NewTicker(o.interval)
+	defer t.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-t.C:
+			claimed, err := o.storage.ClaimOutboxJobs(time.Now(), o.workers*2, outboxLease)
+			if err != nil {
+				log.WithError(err).Error("failed to claim outbox jobs")
+				continue
+			}
+
+			for _, job := range claimed {
+				select {
+				case jobs <- job:
+				case <-ctx.Done():
+					return
+				}
+			}
+		}
+	}
+}
+
+// Deliver sends the message of the job to its bridge, edits or deletes it
+// there, and records the outcome on the job. The message is loaded anew, so
+// the bridge always gets the latest text.
+func (o *Outbox) Deliver(job storage.OutboxJob) {
+	logger := log.WithField("bridge_name", job.Bridge).WithField("message_id", job.MessageID)
+
+	bridge, ok := o.bridges[job.Bridge]
+	if !ok {
+		o.finish(&job, errors.New("bridge is not available"))
+		return
+	}
+
+	opts := []func(*gorm.DB) *gorm.DB{storage.WithAttachments(), storage.WithDeliveries()}
+	if job.IsDelete() {
+		opts = append(opts, storage.WithDeleted())
+	}
+
+	message, err := o.storage.FindMessage(job.TickerID, job.MessageID, opts...)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		// The message was deleted before it could be delivered.
+		o.finish(&job, errors.New("message was deleted"))
+		return
+	}
+	if err != nil {
+		o.retry(&job, err)
+		return
+	}
+
+	ticker, err := o.storage.FindTickerByID(job.TickerID, storage.WithPreload())
+	if err != nil {
+		o.retry(&job, err)
+		return
+	}
+
+	var sendErr error
+	if job.IsDelete() {
+		// The bridge only knows how to delete the message once the sends and
+		// edits before have stored what they got back.
+		if busy(message, job) {
+			o.wait(&job)
+			return
+		}
+
+		sendErr = bridge.Delete(ticker, &message)
+	} else if job.IsEdit() {
+		switch sendStatus(message, job.Bridge) {
+		case storage.OutboxPending:
+			o.wait(&job)
+			return
+		case storage.OutboxFailed:
+			o.finish(&job, errors.New("message was not sent"))
+			return
+		}
+
+		sendErr = bridge.Edit(ticker, &message)
+	} else {
+		sendErr = bridge.Send(ticker, &message)
+	}
+
+	// A failed delivery may still have reached part of the bridge, like some of
+	// the webhooks, which the retry must not repeat.
+	if err := o.storage.SaveMessageBridgeMeta(&message, job.Bridge); err != nil {
+		logger.WithError(err).Error("failed to save message after delivery")
+	}
+
+	if sendErr != nil {
+		logger.WithError(sendErr).Warn("failed to deliver message")
+		o.retry(&job, sendErr)
+		return
+	}
+
+	o.finish(&job, nil)
+}
+
+// wait looks at the job again later, without counting it as an attempt.
+func (o *Outbox) wait(job *storage.OutboxJob) {
+	job.NextAttemptAt = time.Now().Add(outboxEditWait)
+
+	o.save(job)
+}
+
+// sendStatus returns the status of sending the message to the bridge. Messages
+// sent before the outbox existed have no job and count as sent.
+func sendStatus(message storage.Message, bridge string) string {
+	for _, job := range message.Deliveries {
+		if job.Bridge == bridge && !job.IsEdit() && !job.IsDelete() {
+			return job.Status
+		}
+	}
+
+	return storage.OutboxSent
+}
+
+// busy reports whether a send or edit of the message is still pending on the
+// bridge of the delete job.
+func busy(message storage.Message, job storage.OutboxJob) bool {
+	for _, delivery := range message.Deliveries {
+		if delivery.Bridge == job.Bridge && !delivery.IsDelete() && delivery.Status == storage.OutboxPending {
+			return true
+		}
+	}
+
+	return false
+}
+
+// retry schedules the next attempt of the job, or fails it for good when it
+// ran out of attempts.
+func (o *Outbox) retry(job *storage.OutboxJob, err error) {
+	job.Attempts++
+	job.LastError = err.Error()
+
+	if job.Attempts >= outboxMaxAttempts {
+		job.Status = storage.OutboxFailed
+	} else {
+		job.NextAttemptAt = time.Now().Add(retryDelay(job.Attempts))
+	}
+
+	o.save(job)
+}
+
+func (o *Outbox) finish(job *storage.OutboxJob, err error) {
+	job.Attempts++
+	job.Status = storage.OutboxSent
+	job.LastError = ""
+	if err != nil {
+		job.Status = storage.OutboxFailed
+		job.LastError = err.Error()
+	}
+
+	o.save(job)
+}
+
+func (o *Outbox) save(job *storage.OutboxJob) {
+	if err := o.storage.SaveOutboxJob(job); err != nil {
+		log.WithError(err).WithField("job_id", job.ID).Error("failed to save outbox job")
+	}
+}
+
+// retryDelay doubles the delay with every attempt, starting at half a minute
+// and capped at an hour.
+func retryDelay(attempts int) time.Duration {
+	delay := outboxRetryBase
+	for i := 1; i < attempts; i++ {
+		delay *= 2
+		if delay >= outboxRetryMax {
+			return outboxRetryMax
+		}
+	}
+
+	return delay
+}
@@ -0,0 +1,280 @@
+package bridge
+
+import (
+	"context"
+	"errors"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+	"github.com/systemli/ticker/internal/storage"
+	"gorm.io/gorm"
+)
+
+type OutboxTestSuite struct {
+	store  *storage.MockStorage
+	bridge *MockBridge
+	outbox *Outbox
+	suite.Suite
+}
+
+func (s *OutboxTestSuite) SetupTest() {
+	log.Logger.SetOutput(io.Discard)
+}
+
+func (s *OutboxTestSuite) Run(name string, subtest func()) {
+	s.T().Run(name, func(t *testing.T) {
+		s.store = &storage.MockStorage{}
+		s.bridge = &MockBridge{}
+		s.outbox = NewOutbox(Bridges{"mock": s.bridge}, s.store)
+
+		subtest()
+	})
+}
+
+func (s *OutboxTestSuite) TestDeliver() {
+	ticker := storage.Ticker{ID: 1}
+	message := storage.Message{ID: 2, TickerID: 1, Text: "text"}
+	job := storage.NewOutboxJob(message, "mock")
+
+	s.Run("when delivery succeeds", func() {
+		s.store.On("FindMessage", 1, 2, mock.Anything).Return(message, nil).Once()
+		s.store.On("FindTickerByID", 1, mock.Anything).Return(ticker, nil).Once()
+		s.bridge.On("Send", ticker, mock.Anything).Return(nil).Once()
+		s.store.On("SaveMessageBridgeMeta", mock.Anything, "mock").Return(nil).Once()
+		s.store.On("SaveOutboxJob", mock.MatchedBy(func(j *storage.OutboxJob) bool {
+			return j.Status == storage.OutboxSent && j.Attempts == 1
+		})).Return(nil).Once()
+		s.outbox.Deliver(job)
+
+		s.True(s.store.AssertExpectations(s.T()))
+		s.True(s.bridge.AssertExpectations(s.T()))
+	})
+
+	s.Run("when delivery fails", func() {
+		s.store.On("FindMessage", 1, 2, mock.Anything).Return(message, nil).Once()
+		s.store.On("FindTickerByID", 1, mock.Anything).Return(ticker, nil).Once()
+		s.bridge.On("Send", ticker, mock.Anything).Return(errors.New("timeout")).Once()
+		s.store.On("SaveMessageBridgeMeta", mock.Anything, "mock").Return(nil).Once()
+		s.store.On("SaveOutboxJob", mock.MatchedBy(func(j *storage.OutboxJob) bool {
+			return j.Status == storage.OutboxPending && j.Attempts == 1 && j.LastError == "timeout" && j.NextAttemptAt.After(time.Now())
+		})).Return(nil).Once()
+		s.outbox.Deliver(job)
+
+		s.True(s.store.AssertExpectations(s.T()))
+		s.True(s.bridge.AssertExpectations(s.T()))
+	})
+
+	s.Run("when delivery fails for the last time", func() {
+		last := job
+		last.Attempts = outboxMaxAttempts - 1
+		s.store.On("FindMessage", 1, 2, mock.Anything).Return(message, nil).Once()
+		s.store.On("FindTickerByID", 1, mock.Anything).Return(ticker, nil).Once()
+		s.bridge.On("Send", ticker, mock.Anything).Return(errors.New("timeout")).Once()
+		s.store.On("SaveMessageBridgeMeta", mock.Anything, "mock").Return(nil).Once()
+		s.store.On("SaveOutboxJob", mock.MatchedBy(func(j *storage.OutboxJob) bool {
+			return j.Status == storage.OutboxFailed && j.Attempts == outboxMaxAttempts
+		})).Return(nil).Once()
+		s.outbox.Deliver(last)
+
+		s.True(s.store.AssertExpectations(s.T()))
+	})
+
+	s.Run("when message was deleted", func() {
+		s.store.On("FindMessage", 1, 2, mock.Anything).Return(storage.Message{}, gorm.ErrRecordNotFound).Once()
+		s.store.On("SaveOutboxJob", mock.MatchedBy(func(j *storage.OutboxJob) bool {
+			return j.Status == storage.OutboxFailed
+		})).Return(nil).Once()
+		s.outbox.Deliver(job)
+
+		s.True(s.store.AssertExpectations(s.T()))
+		s.True(s.bridge.AssertExpectations(s.T()))
+	})
+
+	s.Run("when bridge is not available", func() {
+		unknown := storage.NewOutboxJob(message, "unknown")
+		s.store.On("SaveOutboxJob", mock.MatchedBy(func(j *storage.OutboxJob) bool {
+			return j.Status == storage.OutboxFailed
+		})).Return(nil).Once()
+		s.outbox.Deliver(unknown)
+
+		s.True(s.store.AssertExpectations(s.T()))
+	})
+}
+
+func (s *OutboxTestSuite) TestDeliverEdit() {
+	ticker := storage.Ticker{ID: 1}
+	message := storage.Message{ID: 2, TickerID: 1, Text: "text"}
+	job := storage.NewOutboxEditJob(message, "mock")
+	withSend := func(status string) storage.Message {
+		send := storage.NewOutboxJob(message, "mock")
+		send.Status = status
+		m := message
+		m.Deliveries = []storage.OutboxJob{send, job}
+
+		return m
+	}
+
+	s.Run("when the message was sent", func() {
+		s.store.On("FindMessage", 1, 2, mock.Anything).Return(withSend(storage.OutboxSent), nil).Once()
+		s.store.On("FindTickerByID", 1, mock.Anything).Return(ticker, nil).Once()
+		s.bridge.On("Edit", ticker, mock.Anything).Return(nil).Once()
+		s.store.On("SaveMessageBridgeMeta", mock.Anything, "mock").Return(nil).Once()
+		s.store.On("SaveOutboxJob", mock.MatchedBy(func(j *storage.OutboxJob) bool {
+			return j.Status == storage.OutboxSent && j.Attempts == 1
+		})).Return(nil).Once()
+		s.outbox.Deliver(job)
+
+		s.True(s.store.AssertExpectations(s.T()))
+		s.True(s.bridge.AssertExpectations(s.T()))
+	})
+
+	s.Run("when the message was sent before the outbox", func() {
+		s.store.On("FindMessage", 1, 2, mock.Anything).Return(message, nil).Once()
+		s.store.On("FindTickerByID", 1, mock.Anything).Return(ticker, nil).Once()
+		s.bridge.On("Edit", ticker, mock.Anything).Return(nil).Once()
+		s.store.On("SaveMessageBridgeMeta", mock.Anything, "mock").Return(nil).Once()
+		s.store.On("SaveOutboxJob", mock.Anything).Return(nil).Once()
+		s.outbox.Deliver(job)
+
+		s.True(s.bridge.AssertExpectations(s.T()))
+	})
+
+	s.Run("when the message is not sent yet", func() {
+		s.store.On("FindMessage", 1, 2, mock.Anything).Return(withSend(storage.OutboxPending), nil).Once()
+		s.store.On("FindTickerByID", 1, mock.Anything).Return(ticker, nil).Once()
+		s.store.On("SaveOutboxJob", mock.MatchedBy(func(j *storage.OutboxJob) bool {
+			return j.Status == storage.OutboxPending && j.Attempts == 0 && j.NextAttemptAt.After(time.Now())
+		})).Return(nil).Once()
+		s.outbox.Deliver(job)
+
+		s.True(s.store.AssertExpectations(s.T()))
+		s.bridge.AssertNotCalled(s.T(), "Edit", mock.Anything, mock.Anything)
+	})
+
+	s.Run("when the message could not be sent", func() {
+		s.store.On("FindMessage", 1, 2, mock.Anything).Return(withSend(storage.OutboxFailed), nil).Once()
+		s.store.On("FindTickerByID", 1, mock.Anything).Return(ticker, nil).Once()
+		s.store.On("SaveOutboxJob", mock.MatchedBy(func(j *storage.OutboxJob) bool {
+			return j.Status == storage.OutboxFailed
+		})).Return(nil).Once()
+		s.outbox.Deliver(job)
+
+		s.True(s.store.AssertExpectations(s.T()))
+		s.bridge.AssertNotCalled(s.T(), "Edit", mock.Anything, mock.Anything)
+	})
+}
+
+func (s *OutboxTestSuite) TestDeliverDelete() {
+	ticker := storage.Ticker{ID: 1}
+	message := storage.Message{ID: 2, TickerID: 1}
+	job := storage.NewOutboxDeleteJob(message, "mock")
+	withSend := func(status string) storage.Message {
+		send := storage.NewOutboxJob(message, "mock")
+		send.Status = status
+		m := message
+		m.Deliveries = []storage.OutboxJob{send, job}
+
+		return m
+	}
+	withDeleted := mock.MatchedBy(func(opts []func(*gorm.DB) *gorm.DB) bool {
+		return len(opts) == 3
+	})
+
+	s.Run("when the message was sent", func() {
+		s.store.On("FindMessage", 1, 2, withDeleted).Return(withSend(storage.OutboxSent), nil).Once()
+		s.store.On("FindTickerByID", 1, mock.Anything).Return(ticker, nil).Once()
+		s.bridge.On("Delete", ticker, mock.Anything).Return(nil).Once()
+		s.store.On("SaveMessageBridgeMeta", mock.Anything, "mock").Return(nil).Once()
+		s.store.On("SaveOutboxJob", mock.MatchedBy(func(j *storage.OutboxJob) bool {
+			return j.Status == storage.OutboxSent && j.Attempts == 1
+		})).Return(nil).Once()
+		s.outbox.Deliver(job)
+
+		s.True(s.store.AssertExpectations(s.T()))
+		s.True(s.bridge.AssertExpectations(s.T()))
+	})
+
+	s.Run("when the send is still pending", func() {
+		s.store.On("FindMessage", 1, 2, withDeleted).Return(withSend(storage.OutboxPending), nil).Once()
+		s.store.On("FindTickerByID", 1, mock.Anything).Return(ticker, nil).Once()
+		s.store.On("SaveOutboxJob", mock.MatchedBy(func(j *storage.OutboxJob) bool {
+			return j.Status == storage.OutboxPending && j.Attempts == 0 && j.NextAttemptAt.After(time.Now())
+		})).Return(nil).Once()
+		s.outbox.Deliver(job)
+
+		s.True(s.store.AssertExpectations(s.T()))
+		s.bridge.AssertNotCalled(s.T(), "Delete", mock.Anything, mock.Anything)
+	})
+
+	s.Run("when the send failed", func() {
+		s.store.On("FindMessage", 1, 2, withDeleted).Return(withSend(storage.OutboxFailed), nil).Once()
+		s.store.On("FindTickerByID", 1, mock.Anything).Return(ticker, nil).Once()
+		s.bridge.On("Delete", ticker, mock.Anything).Return(nil).Once()
+		s.store.On("SaveMessageBridgeMeta", mock.Anything, "mock").Return(nil).Once()
+		s.store.On("SaveOutboxJob", mock.Anything).Return(nil).Once()
+		s.outbox.Deliver(job)
+
+		s.True(s.bridge.AssertExpectations(s.T()))
+	})
+
+	s.Run("when the deletion fails", func() {
+		s.store.On("FindMessage", 1, 2, withDeleted).Return(withSend(storage.OutboxSent), nil).Once()
+		s.store.On("FindTickerByID", 1, mock.Anything).Return(ticker, nil).Once()
+		s.bridge.On("Delete", ticker, mock.Anything).Return(errors.New("timeout")).Once()
+		s.store.On("SaveMessageBridgeMeta", mock.Anything, "mock").Return(nil).Once()
+		s.store.On("SaveOutboxJob", mock.MatchedBy(func(j *storage.OutboxJob) bool {
+			return j.Status == storage.OutboxPending && j.Attempts == 1 && j.LastError == "timeout"
+		})).Return(nil).Once()
+		s.outbox.Deliver(job)
+
+		s.True(s.store.AssertExpectations(s.T()))
+		s.True(s.bridge.AssertExpectations(s.T()))
+	})
+}
+
+func (s *OutboxTestSuite) TestRun() {
+	s.Run("delivers claimed jobs until the context is cancelled", func() {
+		message := storage.Message{ID: 2, TickerID: 1}
+		delivered := make(chan struct{}, 1)
+		s.store.On("ClaimOutboxJobs", mock.Anything, mock.Anything, outboxLease).Return([]storage.OutboxJob{storage.NewOutboxJob(message, "unknown")}, nil).Once()
+		s.store.On("ClaimOutboxJobs", mock.Anything, mock.Anything, outboxLease).Return([]storage.OutboxJob{}, nil)
+		s.store.On("SaveOutboxJob", mock.Anything).Return(nil).Run(func(args mock.Arguments) {
+			delivered <- struct{}{}
+		}).Once()
+		s.outbox.interval = time.Millisecond
+
+		ctx, cancel := context.WithCancel(context.Background())
+		done := make(chan struct{})
+		go func() {
+			s.outbox.Run(ctx)
+			close(done)
+		}()
+
+		select {
+		case <-delivered:
+		case <-time.After(time.Second):
+			s.Fail("job was not delivered")
+		}
+
+		cancel()
+		select {
+		case <-done:
+		case <-time.After(time.Second):
+			s.Fail("outbox did not stop")
+		}
+	})
+}
+
+func (s *OutboxTestSuite) TestRetryDelay() {
+	s.Equal(30*time.Second, retryDelay(1))
+	s.Equal(time.Minute, retryDelay(2))
+	s.Equal(4*time.Minute, retryDelay(4))
+	s.Equal(time.Hour, retryDelay(9))
+}
+
+func TestOutboxTestSuite(t *testing.T) {
+	suite.Run(t, new(OutboxTestSuite))
+}
@@ -21,6 +21,15 @@ type SignalGroupResponse struct {
 	Timestamp int `json:"timestamp"`
 }
 
+func (sb *SignalGroupBridge) Enabled(ticker storage.Ticker) bool {
+	if !ticker.SignalGroup.Connected() || !ticker.SignalGroup.Active {
+		return false
+	}
+
+	settings := sb.storage.GetSignalGroupSettings()
+	return settings.Enabled()
+}
+
 func (sb *SignalGroupBridge) Update(ticker storage.Ticker) error {
 	settings := sb.storage.GetSignalGroupSettings()
 	if !settings.Enabled() || !ticker.SignalGroup.Connected() {
@@ -93,6 +102,36 @@ func (sb *SignalGroupBridge) Send(ticker storage.Ticker, message *storage.Messag
 	return nil
 }
 
+// Edit sends an edit for the message to the group. Signal identifies the
+// edited message by the timestamp of the original send, which is kept as is.
+func (sb *SignalGroupBridge) Edit(ticker storage.Ticker, message *storage.Message) error {
+	settings := sb.storage.GetSignalGroupSettings()
+	if !settings.Enabled() || !ticker.SignalGroup.Connected() || !ticker.SignalGroup.Active || message.SignalGroup.Timestamp == 0 {
+		return nil
+	}
+
+	client := signal.ClientFromSettings(settings)
+	params := struct {
+		Account       string `json:"account"`
+		GroupID       string `json:"group-id"`
+		Message       string `json:"message"`
+		EditTimestamp int    `json:"edit-timestamp"`
+	}{
+		Account:       settings.Account,
+		GroupID:       ticker.SignalGroup.GroupID,
+		Message:       message.Text,
+		EditTimestamp: message.SignalGroup.Timestamp,
+	}
+
+	var response SignalGroupResponse
+	err := client.CallFor(context.Background(), &response, "send", &params)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
 func (sb *SignalGroupBridge) Delete(ticker storage.Ticker, message *storage.Message) error {
 	settings := sb.storage.GetSignalGroupSettings()
 	if !settings.Enabled() || !ticker.SignalGroup.Connected() || !ticker.SignalGroup.Active || message.SignalGroup.Timestamp == 0 {
@@ -118,3 +157,12 @@ func (sb *SignalGroupBridge) Delete(ticker storage.Ticker, message *storage.Mess
 
 	return nil
 }
+
+// Pin does nothing, as groups have no pinned messages.
+func (sb *SignalGroupBridge) Pin(ticker storage.Ticker, message *storage.Message) error {
+	return nil
+}
+
+func (sb *SignalGroupBridge) Unpin(ticker storage.Ticker, message *storage.Message) error {
+	return nil
+}
@@ -217,6 +217,77 @@ func (s *BridgeTestSuite) TestSignalGroupSend() {
 	})
 }
 
+func (s *BridgeTestSuite) TestSignalGroupEdit() {
+	s.Run("when signal not connected", func() {
+		mockStorage := &storage.MockStorage{}
+		mockStorage.On("GetSignalGroupSettings").Return(storage.DefaultSignalGroupSettings())
+		bridge := s.signalGroupBridge(config.Config{}, mockStorage)
+
+		err := bridge.Edit(tickerWithoutBridges, &messageWithBridges)
+		s.NoError(err)
+		mockStorage.AssertExpectations(s.T())
+	})
+
+	s.Run("when message has no signal meta", func() {
+		mockStorage := &storage.MockStorage{}
+		mockStorage.On("GetSignalGroupSettings").Return(storage.SignalGroupSettings{
+			ApiUrl:  "https://signal-cli.example.org/api/v1/rpc",
+			Account: "0123456789",
+		})
+		bridge := s.signalGroupBridge(config.Config{}, mockStorage)
+
+		err := bridge.Edit(tickerWithBridges, &messageWithoutBridges)
+		s.NoError(err)
+		mockStorage.AssertExpectations(s.T())
+	})
+
+	s.Run("when edit fails", func() {
+		mockStorage := &storage.MockStorage{}
+		mockStorage.On("GetSignalGroupSettings").Return(storage.SignalGroupSettings{
+			ApiUrl:  "https://signal-cli.example.org/api/v1/rpc",
+			Account: "0123456789",
+		})
+		bridge := s.signalGroupBridge(config.Config{}, mockStorage)
+
+		gock.New("https://signal-cli.example.org").
+			Post("/api/v1/rpc").
+			Reply(500)
+
+		err := bridge.Edit(tickerWithBridges, &messageWithBridges)
+		s.Error(err)
+		s.True(gock.IsDone())
+		mockStorage.AssertExpectations(s.T())
+	})
+
+	s.Run("happy path", func() {
+		mockStorage := &storage.MockStorage{}
+		mockStorage.On("GetSignalGroupSettings").Return(storage.SignalGroupSettings{
+			ApiUrl:  "https://signal-cli.example.org/api/v1/rpc",
+			Account: "0123456789",
+		})
+		bridge := s.signalGroupBridge(config.Config{}, mockStorage)
+
+		gock.New("https://signal-cli.example.org").
+			Post("/api/v1/rpc").
+			BodyString(`"edit-timestamp":123`).
+			Reply(200).
+			JSON(map[string]interface{}{
+				"jsonrpc": "2.0",
+				"result": map[string]int{
+					"timestamp": 456,
+				},
+				"id": 1,
+			})
+
+		message := messageWithBridges
+		err := bridge.Edit(tickerWithBridges, &message)
+		s.NoError(err)
+		s.Equal(123, message.SignalGroup.Timestamp)
+		s.True(gock.IsDone())
+		mockStorage.AssertExpectations(s.T())
+	})
+}
+
 func (s *BridgeTestSuite) TestSignalDelete() {
 	s.Run("when signal not connected", func() {
 		mockStorage := &storage.MockStorage{}
@@ -11,6 +11,10 @@ type TelegramBridge struct {
 	storage storage.Storage
 }
 
+func (tb *TelegramBridge) Enabled(ticker storage.Ticker) bool {
+	return ticker.Telegram.ChannelName != "" && ticker.Telegram.Active && tb.storage.GetTelegramSettings().Token != ""
+}
+
 func (tb *TelegramBridge) Update(ticker storage.Ticker) error {
 	return nil
 }
@@ -78,6 +82,45 @@ func (tb *TelegramBridge) Send(ticker storage.Ticker, message *storage.Message)
 	return nil
 }
 
+// Edit replaces the text of the message in the channel. Messages with
+// attachments were sent as a media group, so the caption of the first item is
+// edited instead.
+func (tb *TelegramBridge) Edit(ticker storage.Ticker, message *storage.Message) error {
+	if ticker.Telegram.ChannelName == "" || !ticker.Telegram.Active {
+		return nil
+	}
+
+	if len(message.Telegram.Messages) == 0 {
+		return nil
+	}
+
+	// Get Telegram token from database settings
+	telegramSettings := tb.storage.GetTelegramSettings()
+	if telegramSettings.Token == "" {
+		return nil
+	}
+
+	bot, err := tgbotapi.NewBotAPI(telegramSettings.Token)
+	if err != nil {
+		return err
+	}
+
+	edit := tgbotapi.BaseEdit{MessageID: message.Telegram.Messages[0].MessageID}
+	if chat := message.Telegram.Messages[0].Chat; chat != nil {
+		edit.ChatID = chat.ID
+	} else {
+		edit.ChannelUsername = ticker.Telegram.ChannelName
+	}
+
+	if len(message.Attachments) == 0 {
+		_, err = bot.Request(tgbotapi.EditMessageTextConfig{BaseEdit: edit, Text: message.Text})
+	} else {
+		_, err = bot.Request(tgbotapi.EditMessageCaptionConfig{BaseEdit: edit, Caption: message.Text})
+	}
+
+	return err
+}
+
 func (tb *TelegramBridge) Delete(ticker storage.Ticker, message *storage.Message) error {
 	if ticker.Telegram.ChannelName == "" {
 		return nil
@@ -110,6 +153,67 @@ func (tb *TelegramBridge) Delete(ticker storage.Ticker, message *storage.Message
 	return nil
 }
 
+// Pin pins the message in the channel without notifying the subscribers. For
+// a media group the first item is pinned.
+func (tb *TelegramBridge) Pin(ticker storage.Ticker, message *storage.Message) error {
+	bot, ok, err := tb.pinBot(ticker, message)
+	if !ok {
+		return err
+	}
+
+	config := tgbotapi.PinChatMessageConfig{MessageID: message.Telegram.Messages[0].MessageID, DisableNotification: true}
+	if chat := message.Telegram.Messages[0].Chat; chat != nil {
+		config.ChatID = chat.ID
+	} else {
+		config.ChannelUsername = ticker.Telegram.ChannelName
+	}
+
+	_, err = bot.Request(config)
+	return err
+}
+
+func (tb *TelegramBridge) Unpin(ticker storage.Ticker, message *storage.Message) error {
+	bot, ok, err := tb.pinBot(ticker, message)
+	if !ok {
+		return err
+	}
+
+	config := tgbotapi.UnpinChatMessageConfig{MessageID: message.Telegram.Messages[0].MessageID}
+	if chat := message.Telegram.Messages[0].Chat; chat != nil {
+		config.ChatID = chat.ID
+	} else {
+		config.ChannelUsername = ticker.Telegram.ChannelName
+	}
+
+	_, err = bot.Request(config)
+	return err
+}
+
+// pinBot returns the bot to change the pinned messages of the channel, and
+// false when there is nothing to do for the message.
+func (tb *TelegramBridge) pinBot(ticker storage.Ticker, message *storage.Message) (*tgbotapi.BotAPI, bool, error) {
+	if ticker.Telegram.ChannelName == "" || !ticker.Telegram.Active {
+		return nil, false, nil
+	}
+
+	if len(message.Telegram.Messages) == 0 {
+		return nil, false, nil
+	}
+
+	// Get Telegram token from database settings
+	telegramSettings := tb.storage.GetTelegramSettings()
+	if telegramSettings.Token == "" {
+		return nil, false, nil
+	}
+
+	bot, err := tgbotapi.NewBotAPI(telegramSettings.Token)
+	if err != nil {
+		return nil, false, err
+	}
+
+	return bot, true, nil
+}
+
 func BotUser(token string) (tgbotapi.User, error) {
 	if token == "" {
 		return tgbotapi.User{}, nil
@@ -186,6 +186,173 @@ func (s *BridgeTestSuite) TestTelegramSend() {
 	})
 }
 
+func (s *BridgeTestSuite) TestTelegramEdit() {
+	s.Run("when telegram is inactive", func() {
+		mockStorage := &storage.MockStorage{}
+		bridge := s.telegramBridge(config.Config{}, mockStorage)
+
+		err := bridge.Edit(tickerWithoutBridges, &messageWithBridges)
+		s.NoError(err)
+		mockStorage.AssertExpectations(s.T())
+	})
+
+	s.Run("when message has no telegram meta", func() {
+		mockStorage := &storage.MockStorage{}
+		bridge := s.telegramBridge(config.Config{}, mockStorage)
+
+		err := bridge.Edit(tickerWithBridges, &messageWithoutBridges)
+		s.NoError(err)
+		mockStorage.AssertExpectations(s.T())
+	})
+
+	s.Run("when telegram is active but bot api fails", func() {
+		mockStorage := &storage.MockStorage{}
+		mockStorage.On("GetTelegramSettings").Return(storage.TelegramSettings{Token: "123"})
+		bridge := s.telegramBridge(config.Config{}, mockStorage)
+
+		gock.New("https://api.telegram.org").
+			Post("/bot123/getMe").
+			Reply(500)
+
+		err := bridge.Edit(tickerWithBridges, &messageWithBridges)
+		s.Error(err)
+		s.True(gock.IsDone())
+		mockStorage.AssertExpectations(s.T())
+	})
+
+	s.Run("when message has attachments", func() {
+		mockStorage := &storage.MockStorage{}
+		mockStorage.On("GetTelegramSettings").Return(storage.TelegramSettings{Token: "123"})
+		bridge := s.telegramBridge(config.Config{}, mockStorage)
+
+		gock.New("https://api.telegram.org").
+			Post("/bot123/getMe").
+			Reply(200).
+			JSON(map[string]interface{}{
+				"ok":     true,
+				"result": map[string]interface{}{"id": 123},
+			})
+
+		gock.New("https://api.telegram.org").
+			Post("/bot123/editMessageCaption").
+			Reply(200).
+			JSON(map[string]interface{}{
+				"ok":     true,
+				"result": map[string]interface{}{"message_id": 123},
+			})
+
+		err := bridge.Edit(tickerWithBridges, &messageWithBridges)
+		s.NoError(err)
+		s.True(gock.IsDone())
+		mockStorage.AssertExpectations(s.T())
+	})
+
+	s.Run("when message has no attachments", func() {
+		mockStorage := &storage.MockStorage{}
+		mockStorage.On("GetTelegramSettings").Return(storage.TelegramSettings{Token: "123"})
+		bridge := s.telegramBridge(config.Config{}, mockStorage)
+
+		gock.New("https://api.telegram.org").
+			Post("/bot123/getMe").
+			Reply(200).
+			JSON(map[string]interface{}{
+				"ok":     true,
+				"result": map[string]interface{}{"id": 123},
+			})
+
+		gock.New("https://api.telegram.org").
+			Post("/bot123/editMessageText").
+			Reply(500)
+
+		message := storage.Message{Text: "Edited", Telegram: messageWithBridges.Telegram}
+		err := bridge.Edit(tickerWithBridges, &message)
+		s.Error(err)
+		s.True(gock.IsDone())
+		mockStorage.AssertExpectations(s.T())
+	})
+}
+
+func (s *BridgeTestSuite) TestTelegramPin() {
+	s.Run("when telegram is inactive", func() {
+		mockStorage := &storage.MockStorage{}
+		bridge := s.telegramBridge(config.Config{}, mockStorage)
+
+		err := bridge.Pin(tickerWithoutBridges, &messageWithBridges)
+		s.NoError(err)
+		mockStorage.AssertExpectations(s.T())
+	})
+
+	s.Run("when message has no telegram meta", func() {
+		mockStorage := &storage.MockStorage{}
+		bridge := s.telegramBridge(config.Config{}, mockStorage)
+
+		err := bridge.Pin(tickerWithBridges, &messageWithoutBridges)
+		s.NoError(err)
+		mockStorage.AssertExpectations(s.T())
+	})
+
+	s.Run("when telegram is active", func() {
+		mockStorage := &storage.MockStorage{}
+		mockStorage.On("GetTelegramSettings").Return(storage.TelegramSettings{Token: "123"})
+		bridge := s.telegramBridge(config.Config{}, mockStorage)
+
+		gock.New("https://api.telegram.org").
+			Post("/bot123/getMe").
+			Reply(200).
+			JSON(map[string]interface{}{
+				"ok":     true,
+				"result": map[string]interface{}{"id": 123},
+			})
+
+		gock.New("https://api.telegram.org").
+			Post("/bot123/pinChatMessage").
+			Reply(200).
+			JSON(map[string]interface{}{
+				"ok":     true,
+				"result": true,
+			})
+
+		err := bridge.Pin(tickerWithBridges, &messageWithBridges)
+		s.NoError(err)
+		s.True(gock.IsDone())
+		mockStorage.AssertExpectations(s.T())
+	})
+}
+
+func (s *BridgeTestSuite) TestTelegramUnpin() {
+	s.Run("when telegram is inactive", func() {
+		mockStorage := &storage.MockStorage{}
+		bridge := s.telegramBridge(config.Config{}, mockStorage)
+
+		err := bridge.Unpin(tickerWithoutBridges, &messageWithBridges)
+		s.NoError(err)
+		mockStorage.AssertExpectations(s.T())
+	})
+
+	s.Run("when unpinning fails", func() {
+		mockStorage := &storage.MockStorage{}
+		mockStorage.On("GetTelegramSettings").Return(storage.TelegramSettings{Token: "123"})
+		bridge := s.telegramBridge(config.Config{}, mockStorage)
+
+		gock.New("https://api.telegram.org").
+			Post("/bot123/getMe").
+			Reply(200).
+			JSON(map[string]interface{}{
+				"ok":     true,
+				"result": map[string]interface{}{"id": 123},
+			})
+
+		gock.New("https://api.telegram.org").
+			Post("/bot123/unpinChatMessage").
+			Reply(500)
+
+		err := bridge.Unpin(tickerWithBridges, &messageWithBridges)
+		s.Error(err)
+		s.True(gock.IsDone())
+		mockStorage.AssertExpectations(s.T())
+	})
+}
+
 func (s *BridgeTestSuite) TestTelegramDelete() {
 	s.Run("when telegram is inactive", func() {
 		mockStorage := &storage.MockStorage{}
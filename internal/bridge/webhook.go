@@ -0,0 +1,211 @@
+package bridge
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/systemli/ticker/internal/config"
+	"github.com/systemli/ticker/internal/storage"
+	"github.com/systemli/ticker/internal/util"
+)
+
+const (
+	WebhookMessageCreated = "message.created"
+	WebhookMessageDeleted = "message.deleted"
+
+	// WebhookSignatureHeader carries the HMAC-SHA256 of the timestamp, a dot
+	// and the request body, keyed with the secret of the webhook, as
+	// "sha256=<hex>". The signed timestamp lets receivers refuse replays.
+	WebhookSignatureHeader = "X-Ticker-Signature"
+	WebhookTimestampHeader = "X-Ticker-Timestamp"
+	WebhookEventHeader     = "X-Ticker-Event"
+)
+
+// webhookClient only connects to public addresses, as anyone editing a ticker
+// chooses the URLs.
+var webhookClient = &http.Client{Timeout: 10 * time.Second, Transport: util.PublicTransport()}
+
+// WebhookBridge posts the messages of the ticker as signed JSON to the webhooks
+// of the ticker, for receivers the ticker has no bridge of its own for.
+type WebhookBridge struct {
+	config  config.Config
+	storage storage.Storage
+}
+
+type WebhookPayload struct {
+	Event     string         `json:"event"`
+	CreatedAt time.Time      `json:"createdAt"`
+	Ticker    WebhookTicker  `json:"ticker"`
+	Message   WebhookMessage `json:"message"`
+}
+
+type WebhookTicker struct {
+	ID          int    `json:"id"`
+	Title       string `json:"title"`
+	Description string `json:"description"`
+	URL         string `json:"url,omitempty"`
+}
+
+type WebhookMessage struct {
+	ID          int                 `json:"id"`
+	CreatedAt   time.Time           `json:"createdAt"`
+	Text        string              `json:"text,omitempty"`
+	Geometry    *storage.Geometry   `json:"geometry,omitempty"`
+	Attachments []WebhookAttachment `json:"attachments,omitempty"`
+}
+
+type WebhookAttachment struct {
+	URL         string `json:"url"`
+	ContentType string `json:"contentType"`
+}
+
+func (wb *WebhookBridge) Enabled(ticker storage.Ticker) bool {
+	return len(ticker.Webhooks) > 0
+}
+
+func (wb *WebhookBridge) Update(ticker storage.Ticker) error {
+	return nil
+}
+
+// Send posts the message to the webhooks which have not accepted it yet. A
+// failing webhook does not keep the message from the others.
+func (wb *WebhookBridge) Send(ticker storage.Ticker, message *storage.Message) error {
+	payload := webhookPayload(WebhookMessageCreated, ticker, *message)
+
+	var errs []error
+	for _, webhook := range ticker.Webhooks {
+		if slices.Contains(message.Webhook.Delivered, webhook.ID) {
+			continue
+		}
+
+		if err := postWebhook(webhook, payload); err != nil {
+			errs = append(errs, err)
+			continue
+		}
+
+		message.Webhook.Delivered = append(message.Webhook.Delivered, webhook.ID)
+	}
+
+	return errors.Join(errs...)
+}
+
+// Edit does nothing, webhooks are only told about new and deleted messages.
+func (wb *WebhookBridge) Edit(ticker storage.Ticker, message *storage.Message) error {
+	return nil
+}
+
+// Delete tells the webhooks about the deletion once any of them accepted the
+// message. All webhooks are told, as a failed delivery may have been received
+// anyway.
+func (wb *WebhookBridge) Delete(ticker storage.Ticker, message *storage.Message) error {
+	if message.Webhook.Delivered == nil {
+		return nil
+	}
+
+	payload := webhookPayload(WebhookMessageDeleted, ticker, storage.Message{ID: message.ID, CreatedAt: message.CreatedAt})
+
+	var errs []error
+	for _, webhook := range ticker.Webhooks {
+		if err := postWebhook(webhook, payload); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+func (wb *WebhookBridge) Pin(ticker storage.Ticker, message *storage.Message) error {
+	return nil
+}
+
+func (wb *WebhookBridge) Unpin(ticker storage.Ticker, message *storage.Message) error {
+	return nil
+}
+
+func webhookPayload(event string, ticker storage.Ticker, message storage.Message) WebhookPayload {
+	// Media is served relative to the site of the ticker, the first website
+	// makes the links absolute for receivers outside of it.
+	var origin string
+	if len(ticker.Websites) > 0 {
+		origin = strings.TrimSuffix(ticker.Websites[0].Origin, "/")
+	}
+
+	var attachments []WebhookAttachment
+	for _, attachment := range message.Attachments {
+		attachments = append(attachments, WebhookAttachment{
+			URL:         origin + storage.MediaURL(attachment.FileName()),
+			ContentType: attachment.ContentType,
+		})
+	}
+
+	return WebhookPayload{
+		Event:     event,
+		CreatedAt: time.Now().UTC(),
+		Ticker: WebhookTicker{
+			ID:          ticker.ID,
+			Title:       ticker.Title,
+			Description: ticker.Description,
+			URL:         origin,
+		},
+		Message: WebhookMessage{
+			ID:          message.ID,
+			CreatedAt:   message.CreatedAt,
+			Text:        message.Text,
+			Geometry:    message.Geometry,
+			Attachments: attachments,
+		},
+	}
+}
+
+func postWebhook(webhook storage.TickerWebhook, payload WebhookPayload) error {
+	if !util.IsPublicURL(webhook.URL, "http", "https") {
+		return fmt.Errorf("webhook %d: %w", webhook.ID, util.ErrPrivateAddress)
+	}
+
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequest(http.MethodPost, webhook.URL, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(WebhookEventHeader, payload.Event)
+	timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+	req.Header.Set(WebhookTimestampHeader, timestamp)
+	req.Header.Set(WebhookSignatureHeader, SignWebhook(webhook.Secret, timestamp, body))
+
+	resp, err := webhookClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("webhook %d responded with %s", webhook.ID, resp.Status)
+	}
+
+	return nil
+}
+
+// SignWebhook returns the value of the signature header for the body sent at
+// the timestamp, in seconds since the epoch.
+func SignWebhook(secret, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp + "."))
+	mac.Write(body)
+
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,163 @@
+package bridge
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+
+	"github.com/h2non/gock"
+	"github.com/systemli/ticker/internal/config"
+	"github.com/systemli/ticker/internal/storage"
+	"github.com/systemli/ticker/internal/util"
+)
+
+func (s *BridgeTestSuite) TestWebhookEnabled() {
+	bridge := s.webhookBridge()
+
+	s.False(bridge.Enabled(tickerWithoutBridges))
+	s.True(bridge.Enabled(s.tickerWithWebhooks()))
+}
+
+func (s *BridgeTestSuite) TestWebhookSend() {
+	s.Run("when ticker has no webhooks", func() {
+		bridge := s.webhookBridge()
+
+		message := storage.Message{ID: 1, Text: "Hello World"}
+		err := bridge.Send(tickerWithoutBridges, &message)
+		s.NoError(err)
+		s.Empty(message.Webhook.Delivered)
+	})
+
+	s.Run("when webhooks accept the message", func() {
+		bridge := s.webhookBridge()
+		var payload WebhookPayload
+
+		gock.New("https://hooks.example.org").
+			Post("/one").
+			MatchHeader("Content-Type", "application/json").
+			MatchHeader(WebhookEventHeader, WebhookMessageCreated).
+			MatchHeader(WebhookTimestampHeader, `^[0-9]+$`).
+			AddMatcher(func(req *http.Request, _ *gock.Request) (bool, error) {
+				body, err := io.ReadAll(req.Body)
+				if err != nil {
+					return false, err
+				}
+				req.Body = io.NopCloser(bytes.NewReader(body))
+				if err := json.Unmarshal(body, &payload); err != nil {
+					return false, err
+				}
+
+				return req.Header.Get(WebhookSignatureHeader) == SignWebhook("secret-one", req.Header.Get(WebhookTimestampHeader), body), nil
+			}).
+			Reply(200)
+		gock.New("https://hooks.example.org").
+			Post("/two").
+			Reply(204)
+
+		message := storage.Message{
+			ID:          1,
+			Text:        "Hello World",
+			Attachments: []storage.Attachment{{UUID: "123", Extension: "jpg", ContentType: "image/jpeg"}},
+		}
+		err := bridge.Send(s.tickerWithWebhooks(), &message)
+		s.NoError(err)
+		s.Equal([]int{1, 2}, message.Webhook.Delivered)
+		s.Equal(WebhookMessageCreated, payload.Event)
+		s.Equal("Ticker", payload.Ticker.Title)
+		s.Equal("https://ticker.example.org", payload.Ticker.URL)
+		s.Equal("Hello World", payload.Message.Text)
+		s.Equal("https://ticker.example.org/api/media/123.jpg", payload.Message.Attachments[0].URL)
+		s.True(gock.IsDone())
+	})
+
+	s.Run("when a webhook fails", func() {
+		bridge := s.webhookBridge()
+
+		gock.New("https://hooks.example.org").
+			Post("/one").
+			Reply(500)
+		gock.New("https://hooks.example.org").
+			Post("/two").
+			Reply(200)
+
+		message := storage.Message{ID: 1, Text: "Hello World"}
+		err := bridge.Send(s.tickerWithWebhooks(), &message)
+		s.Error(err)
+		s.Equal([]int{2}, message.Webhook.Delivered)
+		s.True(gock.IsDone())
+	})
+
+	s.Run("when retrying a partial delivery", func() {
+		bridge := s.webhookBridge()
+
+		gock.New("https://hooks.example.org").
+			Post("/one").
+			Reply(200)
+
+		message := storage.Message{ID: 1, Text: "Hello World", Webhook: storage.WebhookMeta{Delivered: []int{2}}}
+		err := bridge.Send(s.tickerWithWebhooks(), &message)
+		s.NoError(err)
+		s.Equal([]int{2, 1}, message.Webhook.Delivered)
+		s.True(gock.IsDone())
+	})
+	s.Run("when a webhook points to an internal address", func() {
+		bridge := s.webhookBridge()
+		ticker := storage.Ticker{Webhooks: []storage.TickerWebhook{{ID: 1, URL: "http://127.0.0.1:8080/hook", Secret: "secret"}}}
+
+		message := storage.Message{ID: 1, Text: "Hello World"}
+		err := bridge.Send(ticker, &message)
+		s.ErrorIs(err, util.ErrPrivateAddress)
+		s.Empty(message.Webhook.Delivered)
+	})
+}
+
+func (s *BridgeTestSuite) TestWebhookDelete() {
+	s.Run("when message was not delivered", func() {
+		bridge := s.webhookBridge()
+
+		err := bridge.Delete(s.tickerWithWebhooks(), &storage.Message{ID: 1})
+		s.NoError(err)
+	})
+
+	s.Run("when message was delivered", func() {
+		bridge := s.webhookBridge()
+
+		gock.New("https://hooks.example.org").
+			Post("/one").
+			MatchHeader(WebhookEventHeader, WebhookMessageDeleted).
+			Reply(200)
+		gock.New("https://hooks.example.org").
+			Post("/two").
+			MatchHeader(WebhookEventHeader, WebhookMessageDeleted).
+			Reply(404)
+
+		message := storage.Message{ID: 1, Text: "Hello World", Webhook: storage.WebhookMeta{Delivered: []int{1}}}
+		err := bridge.Delete(s.tickerWithWebhooks(), &message)
+		s.Error(err)
+		s.True(gock.IsDone())
+	})
+}
+
+func (s *BridgeTestSuite) TestSignWebhook() {
+	s.Equal("sha256=2f658d6aef4f246e91cd741bbcded7479e9605f9d41c9e248122a117e0e1765b", SignWebhook("key", "1700000000", []byte("The quick brown fox jumps over the lazy dog")))
+}
+
+func (s *BridgeTestSuite) tickerWithWebhooks() storage.Ticker {
+	return storage.Ticker{
+		ID:       1,
+		Title:    "Ticker",
+		Websites: []storage.TickerWebsite{{Origin: "https://ticker.example.org/"}},
+		Webhooks: []storage.TickerWebhook{
+			{ID: 1, URL: "https://hooks.example.org/one", Secret: "secret-one"},
+			{ID: 2, URL: "https://hooks.example.org/two", Secret: "secret-two"},
+		},
+	}
+}
+
+func (s *BridgeTestSuite) webhookBridge() *WebhookBridge {
+	return &WebhookBridge{
+		config:  config.Config{},
+		storage: &storage.MockStorage{},
+	}
+}
@@ -0,0 +1,153 @@
+package bridge
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/systemli/ticker/internal/config"
+	"github.com/systemli/ticker/internal/storage"
+	"github.com/systemli/ticker/internal/util"
+	"github.com/systemli/ticker/internal/webpush"
+)
+
+const (
+	// webPushBodyLimit keeps the text of a notification to what the
+	// notification centers show anyway.
+	webPushBodyLimit = 240
+	// webPushTTL is how long the push services keep a notification for a
+	// browser which is offline. Older news is of little use on the street.
+	webPushTTL = 12 * time.Hour
+	// webPushWorkers is the number of notifications sent at the same time.
+	webPushWorkers = 8
+)
+
+// WebPushBridge notifies the readers who subscribed to the ticker in their
+// browsers about new messages. Like for ActivityPub, delivery is best effort:
+// a failed notification is logged, and subscriptions the push service no
+// longer knows are removed.
+type WebPushBridge struct {
+	config  config.Config
+	storage storage.Storage
+}
+
+// WebPushNotification is the payload the service worker of the ticker page
+// receives in its push event.
+type WebPushNotification struct {
+	Title string `json:"title"`
+	Body  string `json:"body"`
+	URL   string `json:"url,omitempty"`
+	// Tag is the same for all notifications about one message, so the browser
+	// replaces instead of stacking them.
+	Tag string `json:"tag"`
+}
+
+func (wb *WebPushBridge) Enabled(ticker storage.Ticker) bool {
+	return ticker.WebPush.Active
+}
+
+func (wb *WebPushBridge) Update(ticker storage.Ticker) error {
+	return nil
+}
+
+func (wb *WebPushBridge) Send(ticker storage.Ticker, message *storage.Message) error {
+	if !wb.Enabled(ticker) {
+		return nil
+	}
+
+	settings := wb.storage.GetWebPushSettings()
+	if !settings.Enabled() {
+		return nil
+	}
+
+	subscriptions, err := wb.storage.FindWebPushSubscriptions(ticker)
+	if err != nil {
+		return err
+	}
+
+	var origin string
+	if len(ticker.Websites) > 0 {
+		origin = strings.TrimSuffix(ticker.Websites[0].Origin, "/")
+	}
+
+	client, err := webpush.NewClient(settings.PublicKey, settings.PrivateKey, origin)
+	if err != nil {
+		return err
+	}
+
+	payload, err := json.Marshal(webPushNotification(ticker, *message, origin))
+	if err != nil {
+		return err
+	}
+
+	var wg sync.WaitGroup
+	queue := make(chan storage.WebPushSubscription)
+	for range webPushWorkers {
+		wg.Go(func() {
+			for subscription := range queue {
+				wb.notify(client, ticker, subscription, payload)
+			}
+		})
+	}
+	for _, subscription := range subscriptions {
+		queue <- subscription
+	}
+	close(queue)
+	wg.Wait()
+
+	return nil
+}
+
+// Edit does nothing, readers were notified about the message already.
+func (wb *WebPushBridge) Edit(ticker storage.Ticker, message *storage.Message) error {
+	return nil
+}
+
+func (wb *WebPushBridge) Delete(ticker storage.Ticker, message *storage.Message) error {
+	return nil
+}
+
+func (wb *WebPushBridge) Pin(ticker storage.Ticker, message *storage.Message) error {
+	return nil
+}
+
+func (wb *WebPushBridge) Unpin(ticker storage.Ticker, message *storage.Message) error {
+	return nil
+}
+
+func (wb *WebPushBridge) notify(client *webpush.Client, ticker storage.Ticker, subscription storage.WebPushSubscription, payload []byte) {
+	err := client.Send(context.Background(), webpush.Subscription{
+		Endpoint: subscription.Endpoint,
+		P256dh:   subscription.P256dh,
+		Auth:     subscription.Auth,
+	}, payload, webPushTTL)
+
+	if errors.Is(err, webpush.ErrSubscriptionGone) {
+		if err := wb.storage.DeleteWebPushSubscription(ticker, subscription.Endpoint); err != nil {
+			log.WithError(err).WithField("subscription_id", subscription.ID).Error("failed to delete push subscription")
+		}
+		return
+	}
+	if err != nil {
+		log.WithError(err).WithField("subscription_id", subscription.ID).Error("failed to send push notification")
+	}
+}
+
+func webPushNotification(ticker storage.Ticker, message storage.Message, origin string) WebPushNotification {
+	chunks := util.SplitText(message.Text, webPushBodyLimit)
+	body := chunks[0]
+	if len(chunks) > 1 {
+		body += "…"
+	}
+
+	return WebPushNotification{
+		Title: ticker.Title,
+		Body:  body,
+		URL:   origin,
+		Tag:   fmt.Sprintf("message-%d", message.ID),
+	}
+}
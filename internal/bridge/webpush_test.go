@@ -0,0 +1,109 @@
+package bridge
+
+import (
+	"crypto/ecdh"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"strings"
+
+	"github.com/h2non/gock"
+	"github.com/stretchr/testify/mock"
+	"github.com/systemli/ticker/internal/config"
+	"github.com/systemli/ticker/internal/storage"
+	"github.com/systemli/ticker/internal/webpush"
+)
+
+func (s *BridgeTestSuite) TestWebPushEnabled() {
+	bridge := WebPushBridge{config: config.Config{}, storage: &storage.MockStorage{}}
+
+	s.False(bridge.Enabled(tickerWithoutBridges))
+	s.True(bridge.Enabled(storage.Ticker{WebPush: storage.TickerWebPush{Active: true}}))
+}
+
+func (s *BridgeTestSuite) TestWebPushSend() {
+	ticker := storage.Ticker{
+		ID:       1,
+		Title:    "Ticker",
+		Websites: []storage.TickerWebsite{{Origin: "https://ticker.example.org"}},
+		WebPush:  storage.TickerWebPush{Active: true},
+	}
+
+	s.Run("when push is not configured", func() {
+		store := &storage.MockStorage{}
+		store.On("GetWebPushSettings").Return(storage.WebPushSettings{}).Once()
+		bridge := WebPushBridge{config: config.Config{}, storage: store}
+
+		err := bridge.Send(ticker, &storage.Message{ID: 1, Text: "Hello World"})
+		s.NoError(err)
+		store.AssertExpectations(s.T())
+	})
+
+	s.Run("when subscriptions are not found", func() {
+		store := &storage.MockStorage{}
+		store.On("GetWebPushSettings").Return(s.webPushSettings()).Once()
+		store.On("FindWebPushSubscriptions", mock.Anything).Return(nil, errors.New("not found")).Once()
+		bridge := WebPushBridge{config: config.Config{}, storage: store}
+
+		err := bridge.Send(ticker, &storage.Message{ID: 1, Text: "Hello World"})
+		s.Error(err)
+		store.AssertExpectations(s.T())
+	})
+
+	s.Run("when subscriptions are notified", func() {
+		store := &storage.MockStorage{}
+		store.On("GetWebPushSettings").Return(s.webPushSettings()).Once()
+		store.On("FindWebPushSubscriptions", mock.Anything).Return([]storage.WebPushSubscription{
+			s.webPushSubscription("https://push.example.org/send/1"),
+			s.webPushSubscription("https://push.example.org/send/2"),
+		}, nil).Once()
+		store.On("DeleteWebPushSubscription", mock.Anything, "https://push.example.org/send/2").Return(nil).Once()
+		bridge := WebPushBridge{config: config.Config{}, storage: store}
+
+		gock.New("https://push.example.org").
+			Post("/send/1").
+			MatchHeader("Content-Encoding", "aes128gcm").
+			MatchHeader("Authorization", "^vapid t=").
+			Reply(201)
+		gock.New("https://push.example.org").
+			Post("/send/2").
+			Reply(410)
+
+		err := bridge.Send(ticker, &storage.Message{ID: 1, Text: "Hello World"})
+		s.NoError(err)
+		s.True(gock.IsDone())
+		store.AssertExpectations(s.T())
+	})
+}
+
+func (s *BridgeTestSuite) TestWebPushNotification() {
+	ticker := storage.Ticker{Title: "Ticker"}
+
+	notification := webPushNotification(ticker, storage.Message{ID: 1, Text: "Hello World"}, "https://ticker.example.org")
+	s.Equal("Ticker", notification.Title)
+	s.Equal("Hello World", notification.Body)
+	s.Equal("https://ticker.example.org", notification.URL)
+	s.Equal("message-1", notification.Tag)
+
+	notification = webPushNotification(ticker, storage.Message{ID: 1, Text: strings.Repeat("word ", 100)}, "")
+	s.True(strings.HasSuffix(notification.Body, "…"))
+	s.LessOrEqual(len([]rune(notification.Body)), webPushBodyLimit+1)
+}
+
+func (s *BridgeTestSuite) webPushSettings() storage.WebPushSettings {
+	publicKey, privateKey, err := webpush.GenerateVAPIDKeys()
+	s.Require().NoError(err)
+
+	return storage.WebPushSettings{PublicKey: publicKey, PrivateKey: privateKey}
+}
+
+func (s *BridgeTestSuite) webPushSubscription(endpoint string) storage.WebPushSubscription {
+	key, err := ecdh.P256().GenerateKey(rand.Reader)
+	s.Require().NoError(err)
+
+	return storage.WebPushSubscription{
+		Endpoint: endpoint,
+		P256dh:   base64.RawURLEncoding.EncodeToString(key.PublicKey().Bytes()),
+		Auth:     "BTBZMqHH6r4Tts7J_aSIgg",
+	}
+}
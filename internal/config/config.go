@@ -3,6 +3,8 @@ package config
 import (
 	"os"
 	"path/filepath"
+	"strconv"
+	"strings"
 
 	"github.com/sethvargo/go-password/password"
 	"github.com/spf13/afero"
@@ -13,13 +15,17 @@ import (
 var log = logger.GetWithPackage("config")
 
 type Config struct {
-	Listen        string   `yaml:"listen"`
-	LogLevel      string   `yaml:"log_level"`
-	LogFormat     string   `yaml:"log_format"`
-	Secret        string   `yaml:"secret"`
-	Database      Database `yaml:"database"`
-	MetricsListen string   `yaml:"metrics_listen"`
-	Upload        Upload   `yaml:"upload"`
+	Listen        string      `yaml:"listen"`
+	LogLevel      string      `yaml:"log_level"`
+	LogFormat     string      `yaml:"log_format"`
+	Secret        string      `yaml:"secret"`
+	Database      Database    `yaml:"database"`
+	MetricsListen string      `yaml:"metrics_listen"`
+	Upload        Upload      `yaml:"upload"`
+	SMTP          SMTP        `yaml:"smtp"`
+	InboundMail   InboundMail `yaml:"inbound_mail"`
+	WebAuthn      WebAuthn    `yaml:"webauthn"`
+	OIDC          OIDC        `yaml:"oidc"`
 	FileBackend   afero.Fs
 }
 
@@ -32,6 +38,79 @@ type Upload struct {
 	Path string `yaml:"path"`
 }
 
+// SMTP is the mail server the email subscriptions are sent through.
+type SMTP struct {
+	Host     string `yaml:"host"`
+	Port     int    `yaml:"port"`
+	Username string `yaml:"username"`
+	Password string `yaml:"password"`
+	From     string `yaml:"from"`
+}
+
+// Enabled reports whether a mail server is configured.
+func (s SMTP) Enabled() bool {
+	return s.Host != "" && s.From != ""
+}
+
+// InboundMail is the mail server built into the API, which receives the mails
+// to the posting addresses of the tickers.
+type InboundMail struct {
+	Listen string `yaml:"listen"`
+	// Domain is the domain of the posting addresses, its MX record has to
+	// point to the listener.
+	Domain string `yaml:"domain"`
+	// AuthServID is the name the mail server in front of the listener puts
+	// into its Authentication-Results header. Only mails it vouches for are
+	// posted right away, all others wait for a review.
+	AuthServID string `yaml:"authserv_id"`
+}
+
+// Enabled reports whether mails are received.
+func (m InboundMail) Enabled() bool {
+	return m.Listen != "" && m.Domain != ""
+}
+
+// WebAuthn is the relying party of the passkey logins. Passkeys are bound to
+// the RPID, so changing it later makes all of them unusable.
+type WebAuthn struct {
+	// RPID is the domain of the admin interface, or a parent domain of it.
+	RPID string `yaml:"rp_id"`
+	// Origins are the URLs the admin interface is served from, e.g.
+	// "https://admin.ticker.example.org".
+	Origins []string `yaml:"origins"`
+}
+
+// Enabled reports whether users can log in with passkeys.
+func (w WebAuthn) Enabled() bool {
+	return w.RPID != "" && len(w.Origins) > 0
+}
+
+// OIDC is the identity provider for the single sign-on to the admin
+// interface, e.g. a Keycloak realm.
+type OIDC struct {
+	// Issuer is the URL of the provider, the discovery document is read from
+	// its /.well-known/openid-configuration.
+	Issuer       string `yaml:"issuer"`
+	ClientID     string `yaml:"client_id"`
+	ClientSecret string `yaml:"client_secret"`
+	// RedirectURL is the page of the admin interface the provider sends the
+	// users back to after the login.
+	RedirectURL string `yaml:"redirect_url"`
+	// RoleClaim is the claim of the ID token which lists the roles or groups
+	// of the user, e.g. "groups".
+	RoleClaim string `yaml:"role_claim"`
+	// AdminRoles make a user a super admin, UserRoles let a user in without
+	// admin rights. Once either is set, users without one of the roles are
+	// refused.
+	AdminRoles []string `yaml:"admin_roles"`
+	UserRoles  []string `yaml:"user_roles"`
+}
+
+// Enabled reports whether users can log in through the identity provider.
+func (o OIDC) Enabled() bool {
+	return o.Issuer != "" && o.ClientID != "" && o.RedirectURL != ""
+}
+
 func defaultConfig() Config {
 	secret, _ := password.Generate(64, 12, 12, false, true)
 
@@ -45,6 +124,9 @@ func defaultConfig() Config {
 		Upload: Upload{
 			Path: "uploads",
 		},
+		SMTP: SMTP{
+			Port: 587,
+		},
 		FileBackend: afero.NewOsFs(),
 	}
 }
@@ -88,6 +170,62 @@ func LoadConfig(path string) Config {
 	if os.Getenv("TICKER_UPLOAD_PATH") != "" {
 		c.Upload.Path = os.Getenv("TICKER_UPLOAD_PATH")
 	}
+	if os.Getenv("TICKER_SMTP_HOST") != "" {
+		c.SMTP.Host = os.Getenv("TICKER_SMTP_HOST")
+	}
+	if os.Getenv("TICKER_SMTP_PORT") != "" {
+		port, err := strconv.Atoi(os.Getenv("TICKER_SMTP_PORT"))
+		if err != nil {
+			log.WithError(err).Error("TICKER_SMTP_PORT is not a number")
+		} else {
+			c.SMTP.Port = port
+		}
+	}
+	if os.Getenv("TICKER_SMTP_USERNAME") != "" {
+		c.SMTP.Username = os.Getenv("TICKER_SMTP_USERNAME")
+	}
+	if os.Getenv("TICKER_SMTP_PASSWORD") != "" {
+		c.SMTP.Password = os.Getenv("TICKER_SMTP_PASSWORD")
+	}
+	if os.Getenv("TICKER_SMTP_FROM") != "" {
+		c.SMTP.From = os.Getenv("TICKER_SMTP_FROM")
+	}
+	if os.Getenv("TICKER_INBOUND_MAIL_LISTEN") != "" {
+		c.InboundMail.Listen = os.Getenv("TICKER_INBOUND_MAIL_LISTEN")
+	}
+	if os.Getenv("TICKER_INBOUND_MAIL_DOMAIN") != "" {
+		c.InboundMail.Domain = os.Getenv("TICKER_INBOUND_MAIL_DOMAIN")
+	}
+	if os.Getenv("TICKER_INBOUND_MAIL_AUTHSERV_ID") != "" {
+		c.InboundMail.AuthServID = os.Getenv("TICKER_INBOUND_MAIL_AUTHSERV_ID")
+	}
+	if os.Getenv("TICKER_WEBAUTHN_RP_ID") != "" {
+		c.WebAuthn.RPID = os.Getenv("TICKER_WEBAUTHN_RP_ID")
+	}
+	if os.Getenv("TICKER_WEBAUTHN_ORIGINS") != "" {
+		c.WebAuthn.Origins = strings.Split(os.Getenv("TICKER_WEBAUTHN_ORIGINS"), ",")
+	}
+	if os.Getenv("TICKER_OIDC_ISSUER") != "" {
+		c.OIDC.Issuer = os.Getenv("TICKER_OIDC_ISSUER")
+	}
+	if os.Getenv("TICKER_OIDC_CLIENT_ID") != "" {
+		c.OIDC.ClientID = os.Getenv("TICKER_OIDC_CLIENT_ID")
+	}
+	if os.Getenv("TICKER_OIDC_CLIENT_SECRET") != "" {
+		c.OIDC.ClientSecret = os.Getenv("TICKER_OIDC_CLIENT_SECRET")
+	}
+	if os.Getenv("TICKER_OIDC_REDIRECT_URL") != "" {
+		c.OIDC.RedirectURL = os.Getenv("TICKER_OIDC_REDIRECT_URL")
+	}
+	if os.Getenv("TICKER_OIDC_ROLE_CLAIM") != "" {
+		c.OIDC.RoleClaim = os.Getenv("TICKER_OIDC_ROLE_CLAIM")
+	}
+	if os.Getenv("TICKER_OIDC_ADMIN_ROLES") != "" {
+		c.OIDC.AdminRoles = strings.Split(os.Getenv("TICKER_OIDC_ADMIN_ROLES"), ",")
+	}
+	if os.Getenv("TICKER_OIDC_USER_ROLES") != "" {
+		c.OIDC.UserRoles = strings.Split(os.Getenv("TICKER_OIDC_USER_ROLES"), ",")
+	}
 	if os.Getenv("TICKER_UPLOAD_URL") != "" {
 		log.Warn("TICKER_UPLOAD_URL is no longer used and can be removed, attachment links are relative to the site serving them")
 	}
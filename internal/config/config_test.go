@@ -18,14 +18,31 @@ func (s *ConfigTestSuite) SetupTest() {
 	log.Logger.SetOutput(io.Discard)
 
 	s.envs = map[string]string{
-		"TICKER_LISTEN":         ":7070",
-		"TICKER_LOG_LEVEL":      "trace",
-		"TICKER_LOG_FORMAT":     "text",
-		"TICKER_SECRET":         "secret",
-		"TICKER_DATABASE_TYPE":  "mysql",
-		"TICKER_DATABASE_DSN":   "user:password@tcp(localhost:3306)/ticker?charset=utf8mb4&parseTime=True&loc=Local",
-		"TICKER_METRICS_LISTEN": ":9191",
-		"TICKER_UPLOAD_PATH":    "/data/uploads",
+		"TICKER_LISTEN":                   ":7070",
+		"TICKER_LOG_LEVEL":                "trace",
+		"TICKER_LOG_FORMAT":               "text",
+		"TICKER_SECRET":                   "secret",
+		"TICKER_DATABASE_TYPE":            "mysql",
+		"TICKER_DATABASE_DSN":             "user:password@tcp(localhost:3306)/ticker?charset=utf8mb4&parseTime=True&loc=Local",
+		"TICKER_METRICS_LISTEN":           ":9191",
+		"TICKER_UPLOAD_PATH":              "/data/uploads",
+		"TICKER_SMTP_HOST":                "mail.example.org",
+		"TICKER_SMTP_PORT":                "465",
+		"TICKER_SMTP_USERNAME":            "ticker",
+		"TICKER_SMTP_PASSWORD":            "password",
+		"TICKER_SMTP_FROM":                "Ticker <ticker@example.org>",
+		"TICKER_INBOUND_MAIL_LISTEN":      ":2525",
+		"TICKER_INBOUND_MAIL_DOMAIN":      "ticker.example.org",
+		"TICKER_INBOUND_MAIL_AUTHSERV_ID": "mx.example.org",
+		"TICKER_WEBAUTHN_RP_ID":           "ticker.example.org",
+		"TICKER_WEBAUTHN_ORIGINS":         "https://admin.ticker.example.org,https://ticker.example.org",
+		"TICKER_OIDC_ISSUER":              "https://id.example.org/realms/collective",
+		"TICKER_OIDC_CLIENT_ID":           "ticker",
+		"TICKER_OIDC_CLIENT_SECRET":       "secret",
+		"TICKER_OIDC_REDIRECT_URL":        "https://admin.ticker.example.org/login/oidc",
+		"TICKER_OIDC_ROLE_CLAIM":          "groups",
+		"TICKER_OIDC_ADMIN_ROLES":         "ticker-admins",
+		"TICKER_OIDC_USER_ROLES":          "ticker-editors,press",
 	}
 }
 
@@ -42,6 +59,11 @@ func (s *ConfigTestSuite) TestConfig() {
 				s.Equal("ticker.db", c.Database.DSN)
 				s.Equal(":8181", c.MetricsListen)
 				s.Equal("uploads", c.Upload.Path)
+				s.Equal(587, c.SMTP.Port)
+				s.False(c.SMTP.Enabled())
+				s.False(c.InboundMail.Enabled())
+				s.False(c.WebAuthn.Enabled())
+				s.False(c.OIDC.Enabled())
 			})
 
 			s.Run("loads config from env", func() {
@@ -59,6 +81,27 @@ func (s *ConfigTestSuite) TestConfig() {
 				s.Equal(s.envs["TICKER_DATABASE_DSN"], c.Database.DSN)
 				s.Equal(s.envs["TICKER_METRICS_LISTEN"], c.MetricsListen)
 				s.Equal(s.envs["TICKER_UPLOAD_PATH"], c.Upload.Path)
+				s.Equal(s.envs["TICKER_SMTP_HOST"], c.SMTP.Host)
+				s.Equal(465, c.SMTP.Port)
+				s.Equal(s.envs["TICKER_SMTP_USERNAME"], c.SMTP.Username)
+				s.Equal(s.envs["TICKER_SMTP_PASSWORD"], c.SMTP.Password)
+				s.Equal(s.envs["TICKER_SMTP_FROM"], c.SMTP.From)
+				s.True(c.SMTP.Enabled())
+				s.Equal(s.envs["TICKER_INBOUND_MAIL_LISTEN"], c.InboundMail.Listen)
+				s.Equal(s.envs["TICKER_INBOUND_MAIL_DOMAIN"], c.InboundMail.Domain)
+				s.Equal(s.envs["TICKER_INBOUND_MAIL_AUTHSERV_ID"], c.InboundMail.AuthServID)
+				s.True(c.InboundMail.Enabled())
+				s.Equal(s.envs["TICKER_WEBAUTHN_RP_ID"], c.WebAuthn.RPID)
+				s.Equal([]string{"https://admin.ticker.example.org", "https://ticker.example.org"}, c.WebAuthn.Origins)
+				s.True(c.WebAuthn.Enabled())
+				s.Equal(s.envs["TICKER_OIDC_ISSUER"], c.OIDC.Issuer)
+				s.Equal(s.envs["TICKER_OIDC_CLIENT_ID"], c.OIDC.ClientID)
+				s.Equal(s.envs["TICKER_OIDC_CLIENT_SECRET"], c.OIDC.ClientSecret)
+				s.Equal(s.envs["TICKER_OIDC_REDIRECT_URL"], c.OIDC.RedirectURL)
+				s.Equal(s.envs["TICKER_OIDC_ROLE_CLAIM"], c.OIDC.RoleClaim)
+				s.Equal([]string{"ticker-admins"}, c.OIDC.AdminRoles)
+				s.Equal([]string{"ticker-editors", "press"}, c.OIDC.UserRoles)
+				s.True(c.OIDC.Enabled())
 
 				for key := range s.envs {
 					os.Unsetenv(key)
@@ -0,0 +1,183 @@
+package mailer
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"crypto/tls"
+	"encoding/hex"
+	"fmt"
+	"mime"
+	"mime/quotedprintable"
+	"net"
+	"net/mail"
+	"net/smtp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/systemli/ticker/internal/config"
+)
+
+// implicitTLSPort is the submission port which speaks TLS from the start
+// instead of upgrading the connection with STARTTLS.
+const implicitTLSPort = 465
+
+const timeout = 30 * time.Second
+
+// Mail is a plain text mail to a single recipient.
+type Mail struct {
+	To      string
+	Subject string
+	Text    string
+	// Headers are added to the header of the mail, e.g. List-Unsubscribe.
+	Headers map[string]string
+}
+
+// Client sends mails through the configured mail server, one connection per
+// mail.
+type Client struct {
+	config config.SMTP
+	from   *mail.Address
+}
+
+func NewClient(smtp config.SMTP) (*Client, error) {
+	from, err := mail.ParseAddress(smtp.From)
+	if err != nil {
+		return nil, fmt.Errorf("invalid sender: %w", err)
+	}
+
+	return &Client{config: smtp, from: from}, nil
+}
+
+func (c *Client) Send(ctx context.Context, m Mail) error {
+	to, err := mail.ParseAddress(m.To)
+	if err != nil {
+		return fmt.Errorf("invalid recipient: %w", err)
+	}
+
+	data, err := c.message(to, m)
+	if err != nil {
+		return err
+	}
+
+	conn, err := c.dial(ctx)
+	if err != nil {
+		return err
+	}
+	if err := conn.SetDeadline(time.Now().Add(timeout)); err != nil {
+		conn.Close()
+		return err
+	}
+
+	client, err := smtp.NewClient(conn, c.config.Host)
+	if err != nil {
+		conn.Close()
+		return err
+	}
+	defer client.Close()
+
+	if c.config.Port != implicitTLSPort {
+		if ok, _ := client.Extension("STARTTLS"); ok {
+			if err := client.StartTLS(&tls.Config{ServerName: c.config.Host}); err != nil {
+				return err
+			}
+		}
+	}
+
+	if c.config.Username != "" {
+		if err := client.Auth(smtp.PlainAuth("", c.config.Username, c.config.Password, c.config.Host)); err != nil {
+			return err
+		}
+	}
+
+	if err := client.Mail(c.from.Address); err != nil {
+		return err
+	}
+	if err := client.Rcpt(to.Address); err != nil {
+		return err
+	}
+
+	w, err := client.Data()
+	if err != nil {
+		return err
+	}
+	if _, err := w.Write(data); err != nil {
+		return err
+	}
+	if err := w.Close(); err != nil {
+		return err
+	}
+
+	return client.Quit()
+}
+
+func (c *Client) dial(ctx context.Context) (net.Conn, error) {
+	addr := net.JoinHostPort(c.config.Host, strconv.Itoa(c.config.Port))
+	dialer := &net.Dialer{Timeout: timeout}
+
+	if c.config.Port == implicitTLSPort {
+		tlsDialer := &tls.Dialer{NetDialer: dialer, Config: &tls.Config{ServerName: c.config.Host}}
+		return tlsDialer.DialContext(ctx, "tcp", addr)
+	}
+
+	return dialer.DialContext(ctx, "tcp", addr)
+}
+
+// message renders the mail with its text quoted-printable encoded, so lines of
+// any length and all characters pass every mail server.
+func (c *Client) message(to *mail.Address, m Mail) ([]byte, error) {
+	id, err := c.messageID()
+	if err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	header := func(name, value string) {
+		fmt.Fprintf(&buf, "%s: %s\r\n", name, value)
+	}
+
+	header("From", c.from.String())
+	header("To", to.String())
+	header("Subject", mime.QEncoding.Encode("utf-8", m.Subject))
+	header("Date", time.Now().Format(time.RFC1123Z))
+	header("Message-ID", id)
+	header("MIME-Version", "1.0")
+	header("Content-Type", "text/plain; charset=utf-8")
+	header("Content-Transfer-Encoding", "quoted-printable")
+
+	names := make([]string, 0, len(m.Headers))
+	for name := range m.Headers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		header(name, strings.NewReplacer("\r", "", "\n", "").Replace(m.Headers[name]))
+	}
+	buf.WriteString("\r\n")
+
+	w := quotedprintable.NewWriter(&buf)
+	if _, err := w.Write([]byte(m.Text)); err != nil {
+		return nil, err
+	}
+	if err := w.Close(); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+func (c *Client) messageID() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+
+	domain := c.config.Host
+	if i := strings.LastIndex(c.from.Address, "@"); i >= 0 {
+		domain = c.from.Address[i+1:]
+	}
+
+	return fmt.Sprintf("<%s@%s>", hex.EncodeToString(b), domain), nil
+}
@@ -0,0 +1,67 @@
+package mailer
+
+import (
+	"context"
+	"io"
+	"mime"
+	"net/mail"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/systemli/ticker/internal/config"
+	"github.com/systemli/ticker/internal/mailer/mailertest"
+)
+
+func TestNewClient(t *testing.T) {
+	_, err := NewClient(config.SMTP{Host: "localhost", Port: 587, From: "not an address"})
+	assert.Error(t, err)
+
+	_, err = NewClient(config.SMTP{Host: "localhost", Port: 587, From: "Ticker <ticker@example.org>"})
+	assert.NoError(t, err)
+}
+
+func TestClientSend(t *testing.T) {
+	server := mailertest.NewServer(t)
+	client, err := NewClient(server.Config("Demo Ticker <ticker@example.org>"))
+	assert.NoError(t, err)
+
+	err = client.Send(context.Background(), Mail{
+		To:      "reader@example.org",
+		Subject: "Straßenfest\r\nBcc: someone@example.org",
+		Text:    "The demonstration starts at noon.\n\nBring water, it's going to be hot – really.",
+		Headers: map[string]string{"List-Unsubscribe": "<https://demo.example.org/unsubscribe>\r\nBcc: someone@example.org"},
+	})
+	assert.NoError(t, err)
+
+	mails := server.Mails()
+	assert.Len(t, mails, 1)
+	assert.Equal(t, "ticker@example.org", mails[0].From)
+	assert.Equal(t, []string{"reader@example.org"}, mails[0].To)
+
+	msg, err := mail.ReadMessage(strings.NewReader(mails[0].Data))
+	assert.NoError(t, err)
+	assert.Equal(t, `"Demo Ticker" <ticker@example.org>`, msg.Header.Get("From"))
+	assert.Equal(t, "<reader@example.org>", msg.Header.Get("To"))
+	assert.Empty(t, msg.Header.Get("Bcc"))
+	assert.Equal(t, "<https://demo.example.org/unsubscribe>Bcc: someone@example.org", msg.Header.Get("List-Unsubscribe"))
+	assert.True(t, strings.HasSuffix(msg.Header.Get("Message-ID"), "@example.org>"))
+
+	subject, err := new(mime.WordDecoder).DecodeHeader(msg.Header.Get("Subject"))
+	assert.NoError(t, err)
+	assert.Equal(t, "Straßenfest\r\nBcc: someone@example.org", subject)
+
+	body, err := io.ReadAll(msg.Body)
+	assert.NoError(t, err)
+	assert.Contains(t, string(body), "Bring water, it's going to be hot =E2=80=93 really.")
+}
+
+func TestClientSendInvalidRecipient(t *testing.T) {
+	server := mailertest.NewServer(t)
+	client, err := NewClient(server.Config("ticker@example.org"))
+	assert.NoError(t, err)
+
+	err = client.Send(context.Background(), Mail{To: "reader", Subject: "Ticker", Text: "Text"})
+	assert.Error(t, err)
+	assert.Empty(t, server.Mails())
+}
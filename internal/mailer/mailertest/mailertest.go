@@ -0,0 +1,135 @@
+// Package mailertest provides a mail server for tests, which accepts every
+// mail and keeps it.
+package mailertest
+
+import (
+	"bufio"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/systemli/ticker/internal/config"
+)
+
+// Mail is a mail the server accepted, with its data as sent by the client.
+type Mail struct {
+	From string
+	To   []string
+	Data string
+}
+
+type Server struct {
+	listener net.Listener
+
+	mu    sync.Mutex
+	mails []Mail
+}
+
+// NewServer starts a server on a local port, which is stopped when the test
+// ends.
+func NewServer(t testing.TB) *Server {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	s := &Server{listener: listener}
+	go s.serve()
+	t.Cleanup(func() { listener.Close() })
+
+	return s
+}
+
+// Config returns the settings to send mails to the server from the sender.
+func (s *Server) Config(from string) config.SMTP {
+	addr := s.listener.Addr().(*net.TCPAddr)
+
+	return config.SMTP{Host: addr.IP.String(), Port: addr.Port, From: from}
+}
+
+// Mails returns the mails accepted so far.
+func (s *Server) Mails() []Mail {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return append([]Mail(nil), s.mails...)
+}
+
+func (s *Server) serve() {
+	for {
+		conn, err := s.listener.Accept()
+		if err != nil {
+			return
+		}
+		go s.handle(conn)
+	}
+}
+
+func (s *Server) handle(conn net.Conn) {
+	defer conn.Close()
+
+	r := bufio.NewReader(conn)
+	reply := func(code int, text string) {
+		_, _ = conn.Write([]byte(strconv.Itoa(code) + " " + text + "\r\n"))
+	}
+
+	var mail Mail
+	reply(220, "localhost ready")
+	for {
+		line, err := r.ReadString('\n')
+		if err != nil {
+			return
+		}
+		line = strings.TrimRight(line, "\r\n")
+		verb := strings.ToUpper(strings.SplitN(line, " ", 2)[0])
+
+		switch verb {
+		case "EHLO", "HELO":
+			reply(250, "localhost")
+		case "MAIL":
+			mail = Mail{From: address(line)}
+			reply(250, "ok")
+		case "RCPT":
+			mail.To = append(mail.To, address(line))
+			reply(250, "ok")
+		case "DATA":
+			reply(354, "go ahead")
+			var data strings.Builder
+			for {
+				line, err := r.ReadString('\n')
+				if err != nil {
+					return
+				}
+				if line == ".\r\n" {
+					break
+				}
+				data.WriteString(strings.TrimPrefix(line, "."))
+			}
+			mail.Data = data.String()
+			s.mu.Lock()
+			s.mails = append(s.mails, mail)
+			s.mu.Unlock()
+			reply(250, "queued")
+		case "RSET", "NOOP":
+			reply(250, "ok")
+		case "QUIT":
+			reply(221, "bye")
+			return
+		default:
+			reply(502, "not implemented")
+		}
+	}
+}
+
+// address returns the address of a MAIL FROM:<…> or RCPT TO:<…> command.
+func address(line string) string {
+	start := strings.Index(line, "<")
+	end := strings.LastIndex(line, ">")
+	if start < 0 || end < start {
+		return ""
+	}
+
+	return line[start+1 : end]
+}
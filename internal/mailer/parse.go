@@ -0,0 +1,204 @@
+package mailer
+
+import (
+	"bytes"
+	"encoding/base64"
+	"errors"
+	"io"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net/mail"
+	"regexp"
+	"strings"
+)
+
+// maxParts limits the parts of a mail that are looked at, nested ones included.
+const maxParts = 50
+
+// Received is a mail received by the Server, reduced to its sender, subject,
+// plain text and files.
+type Received struct {
+	From    string
+	Subject string
+	Text    string
+	Files   []File
+	// AuthenticationResults is the topmost Authentication-Results header,
+	// the one added by the last mail server before ours.
+	AuthenticationResults string
+}
+
+// File is an attachment or inline part of a mail which is not text.
+type File struct {
+	ContentType string
+	Filename    string
+	Data        []byte
+}
+
+// Parse reads the mail. The text is taken from the first text/plain part, with
+// its line breaks as \n, HTML is left out. The From address is taken from the
+// header as it is, see Authenticated before trusting it.
+func Parse(data []byte) (Received, error) {
+	msg, err := mail.ReadMessage(bytes.NewReader(data))
+	if err != nil {
+		return Received{}, err
+	}
+
+	from, err := mail.ParseAddress(msg.Header.Get("From"))
+	if err != nil {
+		return Received{}, err
+	}
+
+	decoder := new(mime.WordDecoder)
+	subject, err := decoder.DecodeHeader(msg.Header.Get("Subject"))
+	if err != nil {
+		subject = msg.Header.Get("Subject")
+	}
+
+	received := Received{From: from.Address, Subject: subject, AuthenticationResults: msg.Header.Get("Authentication-Results")}
+	parts := 0
+	err = walk(msg.Header, msg.Body, &received, &parts)
+
+	return received, err
+}
+
+// comments are left out of the Authentication-Results header.
+var comments = regexp.MustCompile(`\([^()]*\)`)
+
+// Authenticated reports whether the mail server with the authserv-id vouches
+// for the From address: DMARC, DKIM or SPF passed for its domain. The header
+// is only trusted from that server, which has to remove headers of the same
+// name others put in.
+func (r Received) Authenticated(authservID string) bool {
+	at := strings.LastIndex(r.From, "@")
+	if authservID == "" || at < 0 {
+		return false
+	}
+	domain := strings.ToLower(r.From[at+1:])
+
+	results := strings.Split(comments.ReplaceAllString(r.AuthenticationResults, ""), ";")
+	if fields := strings.Fields(results[0]); len(fields) == 0 || !strings.EqualFold(fields[0], authservID) {
+		return false
+	}
+
+	for _, result := range results[1:] {
+		fields := strings.Fields(strings.ToLower(result))
+		if len(fields) == 0 {
+			continue
+		}
+
+		var property string
+		switch fields[0] {
+		case "dmarc=pass":
+			property = "header.from="
+		case "dkim=pass":
+			property = "header.d="
+		case "spf=pass":
+			property = "smtp.mailfrom="
+		default:
+			continue
+		}
+
+		for _, field := range fields[1:] {
+			value, ok := strings.CutPrefix(field, property)
+			if !ok {
+				continue
+			}
+			if i := strings.LastIndex(value, "@"); i >= 0 {
+				value = value[i+1:]
+			}
+			if value == domain {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// header is the part of the header of a mail or a body part Parse uses.
+type header interface {
+	Get(key string) string
+}
+
+func walk(h header, body io.Reader, received *Received, parts *int) error {
+	*parts++
+	if *parts > maxParts {
+		return errors.New("too many parts")
+	}
+
+	contentType := h.Get("Content-Type")
+	if contentType == "" {
+		contentType = "text/plain"
+	}
+	mediaType, params, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		mediaType, params = "application/octet-stream", map[string]string{}
+	}
+
+	if strings.HasPrefix(mediaType, "multipart/") {
+		reader := multipart.NewReader(body, params["boundary"])
+		for {
+			part, err := reader.NextRawPart()
+			if err == io.EOF {
+				return nil
+			}
+			if err != nil {
+				return err
+			}
+			if err := walk(part.Header, part, received, parts); err != nil {
+				return err
+			}
+		}
+	}
+
+	content, err := io.ReadAll(decode(h.Get("Content-Transfer-Encoding"), body))
+	if err != nil {
+		return err
+	}
+
+	disposition, dispositionParams, _ := mime.ParseMediaType(h.Get("Content-Disposition"))
+	if mediaType == "text/plain" && disposition != "attachment" {
+		if received.Text == "" {
+			received.Text = strings.ReplaceAll(charset(params["charset"], content), "\r\n", "\n")
+		}
+		return nil
+	}
+	if strings.HasPrefix(mediaType, "text/") || strings.HasPrefix(mediaType, "message/") {
+		return nil
+	}
+
+	filename := dispositionParams["filename"]
+	if filename == "" {
+		filename = params["name"]
+	}
+	received.Files = append(received.Files, File{ContentType: mediaType, Filename: filename, Data: content})
+
+	return nil
+}
+
+func decode(encoding string, r io.Reader) io.Reader {
+	switch strings.ToLower(strings.TrimSpace(encoding)) {
+	case "base64":
+		return base64.NewDecoder(base64.StdEncoding, r)
+	case "quoted-printable":
+		return quotedprintable.NewReader(r)
+	default:
+		return r
+	}
+}
+
+// charset converts the text to UTF-8. Besides UTF-8 and ASCII only Latin-1 is
+// known, other texts are taken as they are.
+func charset(name string, content []byte) string {
+	switch strings.ToLower(name) {
+	case "iso-8859-1", "latin1":
+		runes := make([]rune, len(content))
+		for i, b := range content {
+			runes[i] = rune(b)
+		}
+		return string(runes)
+	default:
+		return string(content)
+	}
+}
@@ -0,0 +1,115 @@
+package mailer
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const multipartMail = "From: Reporter <Reporter@example.org>\r\n" +
+	"To: secret@ticker.example.org\r\n" +
+	"Subject: =?utf-8?q?Stra=C3=9Fenfest?=\r\n" +
+	"MIME-Version: 1.0\r\n" +
+	"Content-Type: multipart/mixed; boundary=outer\r\n" +
+	"\r\n" +
+	"--outer\r\n" +
+	"Content-Type: multipart/alternative; boundary=inner\r\n" +
+	"\r\n" +
+	"--inner\r\n" +
+	"Content-Type: text/plain; charset=utf-8\r\n" +
+	"Content-Transfer-Encoding: quoted-printable\r\n" +
+	"\r\n" +
+	"The demonstration starts at noon =E2=80=93 bring water.\r\n" +
+	"--inner\r\n" +
+	"Content-Type: text/html; charset=utf-8\r\n" +
+	"\r\n" +
+	"<p>The demonstration starts at noon</p>\r\n" +
+	"--inner--\r\n" +
+	"--outer\r\n" +
+	"Content-Type: image/png; name=\"square.png\"\r\n" +
+	"Content-Disposition: attachment; filename=\"square.png\"\r\n" +
+	"Content-Transfer-Encoding: base64\r\n" +
+	"\r\n" +
+	"aW1h\r\n" +
+	"Z2U=\r\n" +
+	"--outer\r\n" +
+	"Content-Type: text/plain; name=\"notes.txt\"\r\n" +
+	"Content-Disposition: attachment; filename=\"notes.txt\"\r\n" +
+	"\r\n" +
+	"Notes\r\n" +
+	"--outer--\r\n"
+
+func TestParse(t *testing.T) {
+	t.Run("multipart mail", func(t *testing.T) {
+		received, err := Parse([]byte(multipartMail))
+		assert.NoError(t, err)
+		assert.Equal(t, "Reporter@example.org", received.From)
+		assert.Equal(t, "Straßenfest", received.Subject)
+		assert.Equal(t, "The demonstration starts at noon – bring water.", received.Text)
+		assert.Len(t, received.Files, 1)
+		assert.Equal(t, "image/png", received.Files[0].ContentType)
+		assert.Equal(t, "square.png", received.Files[0].Filename)
+		assert.Equal(t, []byte("image"), received.Files[0].Data)
+	})
+
+	t.Run("latin-1 mail", func(t *testing.T) {
+		mail := "From: reporter@example.org\r\nContent-Type: text/plain; charset=iso-8859-1\r\n\r\nStra\xdfe"
+
+		received, err := Parse([]byte(mail))
+		assert.NoError(t, err)
+		assert.Equal(t, "Straße", received.Text)
+	})
+
+	t.Run("mail without sender", func(t *testing.T) {
+		_, err := Parse([]byte("Subject: Hello\r\n\r\nText"))
+		assert.Error(t, err)
+	})
+
+	t.Run("mail with too many parts", func(t *testing.T) {
+		mail := "From: reporter@example.org\r\nContent-Type: multipart/mixed; boundary=b\r\n\r\n" +
+			strings.Repeat("--b\r\nContent-Type: text/plain\r\n\r\nText\r\n", maxParts+1) + "--b--\r\n"
+
+		_, err := Parse([]byte(mail))
+		assert.Error(t, err)
+	})
+}
+
+func TestAuthenticated(t *testing.T) {
+	testCases := []struct {
+		name    string
+		results string
+		from    string
+		want    bool
+	}{
+		{"dmarc passed", "mx.example.org; dkim=pass header.d=example.org; dmarc=pass (p=reject) header.from=example.org", "reporter@example.org", true},
+		{"dkim passed", "mx.example.org 1; dkim=pass (2048-bit key) header.d=Example.org header.s=mail", "Reporter@Example.org", true},
+		{"spf passed", "mx.example.org; spf=pass smtp.mailfrom=reporter@example.org", "reporter@example.org", true},
+		{"dkim of another domain", "mx.example.org; dkim=pass header.d=evil.example.net", "reporter@example.org", false},
+		{"dmarc failed", "mx.example.org; dmarc=fail header.from=example.org", "reporter@example.org", false},
+		{"another server", "evil.example.net; dmarc=pass header.from=example.org", "reporter@example.org", false},
+		{"no header", "", "reporter@example.org", false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			received := Received{From: tc.from, AuthenticationResults: tc.results}
+			assert.Equal(t, tc.want, received.Authenticated("mx.example.org"))
+		})
+	}
+
+	t.Run("without authserv-id", func(t *testing.T) {
+		received := Received{From: "reporter@example.org", AuthenticationResults: "mx.example.org; dmarc=pass header.from=example.org"}
+		assert.False(t, received.Authenticated(""))
+	})
+
+	t.Run("topmost header", func(t *testing.T) {
+		mail := "Authentication-Results: mx.example.org; dmarc=fail header.from=example.org\r\n" +
+			"Authentication-Results: mx.example.org; dmarc=pass header.from=example.org\r\n" +
+			"From: reporter@example.org\r\n\r\nText"
+
+		received, err := Parse([]byte(mail))
+		assert.NoError(t, err)
+		assert.False(t, received.Authenticated("mx.example.org"))
+	})
+}
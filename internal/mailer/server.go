@@ -0,0 +1,312 @@
+package mailer
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/textproto"
+	"strings"
+	"time"
+)
+
+const (
+	// commandTimeout is how long the server waits for the next command, and
+	// for the whole mail once the client started sending it.
+	commandTimeout = 5 * time.Minute
+	// sessionTimeout is the longest a connection is kept open, however busy
+	// the client is.
+	sessionTimeout = 30 * time.Minute
+	// maxLineLength is the longest command line the server reads, RFC 5321
+	// allows 512 bytes.
+	maxLineLength = 1024
+	// maxRecipients limits the recipients of a single mail.
+	maxRecipients = 10
+	// DefaultMaxSize is the size limit of a mail when the server has none set.
+	DefaultMaxSize = 25 << 20
+	// DefaultMaxSessions is the limit of connections handled at the same time
+	// when the server has none set.
+	DefaultMaxSessions = 50
+)
+
+// Envelope is a mail received by the Server, with the addresses of the SMTP
+// transaction and the mail as sent by the client.
+type Envelope struct {
+	From string
+	To   []string
+	Data []byte
+}
+
+// Server receives mails over SMTP and hands them to Deliver. It accepts mails
+// for the recipients Accept agrees to only, so it is no relay. The server does
+// not offer STARTTLS nor authentication; it is meant to receive mails from the
+// mail exchanger of the domain or directly over an internal network.
+type Server struct {
+	// Hostname is announced in the greeting.
+	Hostname string
+	// Accept reports whether mails to the recipient are accepted.
+	Accept func(recipient string) bool
+	// Deliver handles a received mail. An error rejects the mail with a
+	// temporary failure, so the sending server tries again later.
+	Deliver func(Envelope) error
+	// MaxSize is the size limit of a mail in bytes.
+	MaxSize int
+	// MaxSessions is the number of connections handled at the same time,
+	// further connections are turned away until one of them is closed.
+	MaxSessions int
+}
+
+// Serve accepts connections on the listener until the context is cancelled.
+// Connections beyond MaxSessions get a temporary failure, so the sending
+// server tries again later.
+func (s *Server) Serve(ctx context.Context, listener net.Listener) error {
+	go func() {
+		<-ctx.Done()
+		listener.Close()
+	}()
+
+	sessions := make(chan struct{}, s.maxSessions())
+	for {
+		conn, err := listener.Accept()
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+			return err
+		}
+
+		select {
+		case sessions <- struct{}{}:
+			go func() {
+				defer func() { <-sessions }()
+				s.handle(conn)
+			}()
+		default:
+			go s.refuse(conn)
+		}
+	}
+}
+
+// refuse turns the connection away while the server is busy.
+func (s *Server) refuse(conn net.Conn) {
+	defer conn.Close()
+
+	_ = conn.SetDeadline(time.Now().Add(time.Minute))
+	_, _ = fmt.Fprintf(conn, "421 %s too many connections, try again later\r\n", s.Hostname)
+}
+
+// session is the state of a connection, the transaction starts with MAIL and
+// ends with the mail or RSET.
+type session struct {
+	conn  net.Conn
+	r     *bufio.Reader
+	hello bool
+	from  *string
+	to    []string
+}
+
+func (s *Server) handle(conn net.Conn) {
+	defer conn.Close()
+
+	ss := &session{conn: conn, r: bufio.NewReaderSize(conn, maxLineLength)}
+	ss.reply(220, s.Hostname+" ESMTP ready")
+
+	end := time.Now().Add(sessionTimeout)
+	for {
+		deadline := time.Now().Add(commandTimeout)
+		if deadline.After(end) {
+			deadline = end
+		}
+		_ = conn.SetDeadline(deadline)
+
+		line, err := ss.r.ReadSlice('\n')
+		if errors.Is(err, bufio.ErrBufferFull) {
+			ss.reply(500, "line too long")
+			return
+		}
+		if err != nil {
+			return
+		}
+
+		command := strings.TrimRight(string(line), "\r\n")
+		verb, arg, _ := strings.Cut(command, " ")
+
+		switch strings.ToUpper(verb) {
+		case "EHLO":
+			ss.reset()
+			ss.hello = true
+			ss.replyLines(250, s.Hostname, "8BITMIME", fmt.Sprintf("SIZE %d", s.maxSize()))
+		case "HELO":
+			ss.reset()
+			ss.hello = true
+			ss.reply(250, s.Hostname)
+		case "MAIL":
+			s.mail(ss, arg)
+		case "RCPT":
+			s.rcpt(ss, arg)
+		case "DATA":
+			if !s.data(ss) {
+				return
+			}
+		case "RSET":
+			ss.reset()
+			ss.reply(250, "ok")
+		case "NOOP":
+			ss.reply(250, "ok")
+		case "VRFY":
+			ss.reply(252, "cannot verify the user")
+		case "QUIT":
+			ss.reply(221, "bye")
+			return
+		default:
+			ss.reply(502, "command not implemented")
+		}
+	}
+}
+
+func (s *Server) mail(ss *session, arg string) {
+	if !ss.hello {
+		ss.reply(503, "say hello first")
+		return
+	}
+	if ss.from != nil {
+		ss.reply(503, "nested MAIL command")
+		return
+	}
+
+	from, params, ok := path(arg, "FROM:")
+	if !ok {
+		ss.reply(501, "syntax error in MAIL command")
+		return
+	}
+	for _, param := range params {
+		name, value, _ := strings.Cut(param, "=")
+		if strings.EqualFold(name, "SIZE") {
+			var size int
+			if _, err := fmt.Sscanf(value, "%d", &size); err == nil && size > s.maxSize() {
+				ss.reply(552, "message size exceeds the limit")
+				return
+			}
+		}
+	}
+
+	ss.from = &from
+	ss.reply(250, "ok")
+}
+
+func (s *Server) rcpt(ss *session, arg string) {
+	if ss.from == nil {
+		ss.reply(503, "need MAIL before RCPT")
+		return
+	}
+
+	to, _, ok := path(arg, "TO:")
+	if !ok || to == "" {
+		ss.reply(501, "syntax error in RCPT command")
+		return
+	}
+	if len(ss.to) >= maxRecipients {
+		ss.reply(452, "too many recipients")
+		return
+	}
+	if !s.Accept(to) {
+		ss.reply(550, "no such mailbox")
+		return
+	}
+
+	ss.to = append(ss.to, to)
+	ss.reply(250, "ok")
+}
+
+// data receives the mail, it reports whether the connection can be used
+// further.
+func (s *Server) data(ss *session) bool {
+	if len(ss.to) == 0 {
+		ss.reply(503, "need RCPT before DATA")
+		return true
+	}
+
+	ss.reply(354, "end data with <CR><LF>.<CR><LF>")
+
+	dot := textproto.NewReader(ss.r).DotReader()
+	data, err := io.ReadAll(io.LimitReader(dot, int64(s.maxSize())+1))
+	if err != nil {
+		return false
+	}
+	if len(data) > s.maxSize() {
+		if _, err := io.Copy(io.Discard, dot); err != nil {
+			return false
+		}
+		ss.reset()
+		ss.reply(552, "message size exceeds the limit")
+		return true
+	}
+
+	envelope := Envelope{From: *ss.from, To: ss.to, Data: data}
+	ss.reset()
+
+	if err := s.Deliver(envelope); err != nil {
+		ss.reply(451, "mail could not be processed, try again later")
+		return true
+	}
+
+	ss.reply(250, "ok")
+	return true
+}
+
+func (s *Server) maxSize() int {
+	if s.MaxSize > 0 {
+		return s.MaxSize
+	}
+
+	return DefaultMaxSize
+}
+
+func (s *Server) maxSessions() int {
+	if s.MaxSessions > 0 {
+		return s.MaxSessions
+	}
+
+	return DefaultMaxSessions
+}
+
+func (ss *session) reset() {
+	ss.from = nil
+	ss.to = nil
+}
+
+func (ss *session) reply(code int, text string) {
+	_, _ = fmt.Fprintf(ss.conn, "%d %s\r\n", code, text)
+}
+
+func (ss *session) replyLines(code int, lines ...string) {
+	for i, line := range lines {
+		separator := "-"
+		if i == len(lines)-1 {
+			separator = " "
+		}
+		_, _ = fmt.Fprintf(ss.conn, "%d%s%s\r\n", code, separator, line)
+	}
+}
+
+// path returns the address of a "FROM:<address>" or "TO:<address>" argument
+// together with the parameters following it.
+func path(arg, prefix string) (string, []string, bool) {
+	arg = strings.TrimSpace(arg)
+	if len(arg) < len(prefix) || !strings.EqualFold(arg[:len(prefix)], prefix) {
+		return "", nil, false
+	}
+
+	rest := strings.TrimSpace(arg[len(prefix):])
+	if !strings.HasPrefix(rest, "<") {
+		return "", nil, false
+	}
+	end := strings.Index(rest, ">")
+	if end < 0 {
+		return "", nil, false
+	}
+
+	return rest[1:end], strings.Fields(rest[end+1:]), true
+}
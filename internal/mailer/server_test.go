@@ -0,0 +1,166 @@
+package mailer
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"net"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/systemli/ticker/internal/config"
+)
+
+type testServer struct {
+	addr *net.TCPAddr
+
+	mu        sync.Mutex
+	envelopes []Envelope
+	err       error
+}
+
+func startServer(t *testing.T, maxSize int) *testServer {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ts := &testServer{addr: listener.Addr().(*net.TCPAddr)}
+	server := &Server{
+		Hostname: "ticker.example.org",
+		Accept: func(recipient string) bool {
+			return strings.HasSuffix(recipient, "@ticker.example.org")
+		},
+		Deliver: func(envelope Envelope) error {
+			ts.mu.Lock()
+			defer ts.mu.Unlock()
+			ts.envelopes = append(ts.envelopes, envelope)
+			return ts.err
+		},
+		MaxSize: maxSize,
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	go func() { _ = server.Serve(ctx, listener) }()
+	t.Cleanup(cancel)
+
+	return ts
+}
+
+func (ts *testServer) client(t *testing.T) *Client {
+	client, err := NewClient(config.SMTP{Host: ts.addr.IP.String(), Port: ts.addr.Port, From: "reporter@example.org"})
+	assert.NoError(t, err)
+
+	return client
+}
+
+func (ts *testServer) received() []Envelope {
+	ts.mu.Lock()
+	defer ts.mu.Unlock()
+
+	return append([]Envelope(nil), ts.envelopes...)
+}
+
+func TestServerReceivesMail(t *testing.T) {
+	ts := startServer(t, 0)
+
+	err := ts.client(t).Send(context.Background(), Mail{To: "secret@ticker.example.org", Subject: "Update", Text: "Police\n.\n..blocks the street"})
+	assert.NoError(t, err)
+
+	envelopes := ts.received()
+	assert.Len(t, envelopes, 1)
+	assert.Equal(t, "reporter@example.org", envelopes[0].From)
+	assert.Equal(t, []string{"secret@ticker.example.org"}, envelopes[0].To)
+
+	received, err := Parse(envelopes[0].Data)
+	assert.NoError(t, err)
+	assert.Equal(t, "Update", received.Subject)
+	assert.Equal(t, "Police\n.\n..blocks the street\n", received.Text)
+}
+
+func TestServerRejectsUnknownRecipient(t *testing.T) {
+	ts := startServer(t, 0)
+
+	err := ts.client(t).Send(context.Background(), Mail{To: "someone@example.org", Text: "Relay me"})
+	assert.ErrorContains(t, err, "550")
+	assert.Empty(t, ts.received())
+}
+
+func TestServerRejectsLargeMail(t *testing.T) {
+	ts := startServer(t, 512)
+
+	err := ts.client(t).Send(context.Background(), Mail{To: "secret@ticker.example.org", Text: strings.Repeat("a", 1024)})
+	assert.ErrorContains(t, err, "552")
+	assert.Empty(t, ts.received())
+}
+
+func TestServerDefersOnDeliveryError(t *testing.T) {
+	ts := startServer(t, 0)
+	ts.err = errors.New("storage error")
+
+	err := ts.client(t).Send(context.Background(), Mail{To: "secret@ticker.example.org", Text: "Text"})
+	assert.ErrorContains(t, err, "451")
+}
+
+func TestServerEnforcesCommandOrder(t *testing.T) {
+	ts := startServer(t, 0)
+
+	conn, err := net.Dial("tcp", ts.addr.String())
+	assert.NoError(t, err)
+	defer conn.Close()
+
+	r := bufio.NewReader(conn)
+	send := func(command string) string {
+		if command != "" {
+			_, err := conn.Write([]byte(command + "\r\n"))
+			assert.NoError(t, err)
+		}
+		line, err := r.ReadString('\n')
+		assert.NoError(t, err)
+		return line
+	}
+
+	assert.True(t, strings.HasPrefix(send(""), "220 "))
+	assert.True(t, strings.HasPrefix(send("MAIL FROM:<reporter@example.org>"), "503 "))
+	assert.True(t, strings.HasPrefix(send("HELO client"), "250 "))
+	assert.True(t, strings.HasPrefix(send("RCPT TO:<secret@ticker.example.org>"), "503 "))
+	assert.True(t, strings.HasPrefix(send("MAIL FROM:<reporter@example.org> SIZE=100000000"), "552 "))
+	assert.True(t, strings.HasPrefix(send("MAIL FROM:reporter@example.org"), "501 "))
+	assert.True(t, strings.HasPrefix(send("MAIL FROM:<reporter@example.org>"), "250 "))
+	assert.True(t, strings.HasPrefix(send("DATA"), "503 "))
+	assert.True(t, strings.HasPrefix(send("AUTH PLAIN"), "502 "))
+	assert.True(t, strings.HasPrefix(send("QUIT"), "221 "))
+}
+
+func TestServerLimitsSessions(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	server := &Server{
+		Hostname:    "ticker.example.org",
+		Accept:      func(string) bool { return true },
+		Deliver:     func(Envelope) error { return nil },
+		MaxSessions: 1,
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	go func() { _ = server.Serve(ctx, listener) }()
+	t.Cleanup(cancel)
+
+	greeting := func() string {
+		conn, err := net.Dial("tcp", listener.Addr().String())
+		assert.NoError(t, err)
+		t.Cleanup(func() { conn.Close() })
+
+		line, err := bufio.NewReader(conn).ReadString('\n')
+		assert.NoError(t, err)
+		return line
+	}
+
+	assert.True(t, strings.HasPrefix(greeting(), "220 "))
+	assert.True(t, strings.HasPrefix(greeting(), "421 "))
+}
@@ -0,0 +1,158 @@
+package matrix
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/systemli/ticker/internal/util"
+)
+
+// HTTPClient is shared by all clients. The homeservers are configured by the
+// tickers, so it refuses to connect to internal addresses. Uploads of images
+// take longer than the other requests.
+var HTTPClient = &http.Client{Timeout: 30 * time.Second, Transport: util.PublicTransport()}
+
+// Client talks to the client-server API of a Matrix homeserver with the access
+// token of an account.
+type Client struct {
+	Homeserver  string
+	AccessToken string
+	http        *http.Client
+}
+
+// Error is the error body returned by the homeserver.
+type Error struct {
+	StatusCode int    `json:"-"`
+	Code       string `json:"errcode"`
+	Message    string `json:"error"`
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("matrix: %s (%d): %s", e.Code, e.StatusCode, e.Message)
+}
+
+func NewClient(homeserver, accessToken string) *Client {
+	return &Client{
+		Homeserver:  strings.TrimSuffix(homeserver, "/"),
+		AccessToken: accessToken,
+		http:        HTTPClient,
+	}
+}
+
+// WhoAmI returns the user ID of the account the access token belongs to.
+func (c *Client) WhoAmI(ctx context.Context) (string, error) {
+	var response struct {
+		UserID string `json:"user_id"`
+	}
+	err := c.call(ctx, http.MethodGet, "/_matrix/client/v3/account/whoami", nil, &response)
+
+	return response.UserID, err
+}
+
+// JoinRoom joins the room by its ID or alias and returns the room ID.
+func (c *Client) JoinRoom(ctx context.Context, room string) (string, error) {
+	var response struct {
+		RoomID string `json:"room_id"`
+	}
+	err := c.call(ctx, http.MethodPost, "/_matrix/client/v3/join/"+url.PathEscape(room), struct{}{}, &response)
+
+	return response.RoomID, err
+}
+
+// SendMessage sends an m.room.message event with the content to the room and
+// returns the event ID.
+func (c *Client) SendMessage(ctx context.Context, roomID string, content any) (string, error) {
+	path := fmt.Sprintf("/_matrix/client/v3/rooms/%s/send/m.room.message/%s", url.PathEscape(roomID), uuid.NewString())
+
+	var response struct {
+		EventID string `json:"event_id"`
+	}
+	err := c.call(ctx, http.MethodPut, path, content, &response)
+
+	return response.EventID, err
+}
+
+// Redact removes the content of the event, which is how messages are deleted
+// in Matrix.
+func (c *Client) Redact(ctx context.Context, roomID, eventID string) error {
+	path := fmt.Sprintf("/_matrix/client/v3/rooms/%s/redact/%s/%s", url.PathEscape(roomID), url.PathEscape(eventID), uuid.NewString())
+
+	return c.call(ctx, http.MethodPut, path, struct{}{}, nil)
+}
+
+// Upload stores the file in the media repository of the homeserver and returns
+// its mxc:// content URI.
+func (c *Client) Upload(ctx context.Context, filename, contentType string, data []byte) (string, error) {
+	path := "/_matrix/media/v3/upload?filename=" + url.QueryEscape(filename)
+	req, err := c.request(ctx, http.MethodPost, path, bytes.NewReader(data))
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Content-Type", contentType)
+
+	var response struct {
+		ContentURI string `json:"content_uri"`
+	}
+	err = c.do(req, &response)
+
+	return response.ContentURI, err
+}
+
+func (c *Client) call(ctx context.Context, method, path string, body, result any) error {
+	var reader io.Reader
+	if body != nil {
+		b, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+		reader = bytes.NewReader(b)
+	}
+
+	req, err := c.request(ctx, method, path, reader)
+	if err != nil {
+		return err
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	return c.do(req, result)
+}
+
+func (c *Client) request(ctx context.Context, method, path string, body io.Reader) (*http.Request, error) {
+	req, err := http.NewRequestWithContext(ctx, method, c.Homeserver+path, body)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Authorization", "Bearer "+c.AccessToken)
+
+	return req, nil
+}
+
+func (c *Client) do(req *http.Request, result any) error {
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		e := &Error{StatusCode: resp.StatusCode}
+		_ = json.NewDecoder(resp.Body).Decode(e)
+		return e
+	}
+
+	if result == nil {
+		return nil
+	}
+
+	return json.NewDecoder(resp.Body).Decode(result)
+}
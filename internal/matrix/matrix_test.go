@@ -0,0 +1,128 @@
+package matrix
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// homeserver is a stand-in for the parts of the client-server API the ticker
+// uses, it remembers the requests it served.
+func homeserver(t *testing.T) (*httptest.Server, *[]*http.Request) {
+	var requests []*http.Request
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /_matrix/client/v3/account/whoami", func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewEncoder(w).Encode(map[string]string{"user_id": "@ticker:example.org"})
+	})
+	mux.HandleFunc("POST /_matrix/client/v3/join/{room}", func(w http.ResponseWriter, r *http.Request) {
+		if r.PathValue("room") != "#ticker:example.org" {
+			w.WriteHeader(http.StatusNotFound)
+			_ = json.NewEncoder(w).Encode(map[string]string{"errcode": "M_NOT_FOUND", "error": "Room alias not found"})
+			return
+		}
+		_ = json.NewEncoder(w).Encode(map[string]string{"room_id": "!room:example.org"})
+	})
+	mux.HandleFunc("PUT /_matrix/client/v3/rooms/{room}/send/m.room.message/{txn}", func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewEncoder(w).Encode(map[string]string{"event_id": "$event"})
+	})
+	mux.HandleFunc("PUT /_matrix/client/v3/rooms/{room}/redact/{event}/{txn}", func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewEncoder(w).Encode(map[string]string{"event_id": "$redaction"})
+	})
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests = append(requests, r)
+		if r.Header.Get("Authorization") != "Bearer token" {
+			w.WriteHeader(http.StatusUnauthorized)
+			_ = json.NewEncoder(w).Encode(map[string]string{"errcode": "M_UNKNOWN_TOKEN", "error": "Invalid access token"})
+			return
+		}
+		mux.ServeHTTP(w, r)
+	}))
+	t.Cleanup(server.Close)
+
+	return server, &requests
+}
+
+// testClient connects to the test server, which listens on the loopback
+// address that HTTPClient refuses.
+func testClient(server *httptest.Server, homeserver, accessToken string) *Client {
+	client := NewClient(homeserver, accessToken)
+	client.http = server.Client()
+
+	return client
+}
+
+func TestWhoAmI(t *testing.T) {
+	server, _ := homeserver(t)
+
+	userID, err := testClient(server, server.URL+"/", "token").WhoAmI(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, "@ticker:example.org", userID)
+
+	_, err = testClient(server, server.URL, "invalid").WhoAmI(context.Background())
+	assert.Error(t, err)
+	var matrixErr *Error
+	assert.ErrorAs(t, err, &matrixErr)
+	assert.Equal(t, http.StatusUnauthorized, matrixErr.StatusCode)
+	assert.Equal(t, "M_UNKNOWN_TOKEN", matrixErr.Code)
+}
+
+func TestJoinRoom(t *testing.T) {
+	server, _ := homeserver(t)
+	client := testClient(server, server.URL, "token")
+
+	roomID, err := client.JoinRoom(context.Background(), "#ticker:example.org")
+	assert.NoError(t, err)
+	assert.Equal(t, "!room:example.org", roomID)
+
+	_, err = client.JoinRoom(context.Background(), "#unknown:example.org")
+	assert.Error(t, err)
+}
+
+func TestSendMessage(t *testing.T) {
+	server, requests := homeserver(t)
+	client := testClient(server, server.URL, "token")
+
+	eventID, err := client.SendMessage(context.Background(), "!room:example.org", map[string]string{"msgtype": "m.text", "body": "Hello"})
+	assert.NoError(t, err)
+	assert.Equal(t, "$event", eventID)
+
+	_, err = client.SendMessage(context.Background(), "!room:example.org", map[string]string{"msgtype": "m.text", "body": "Hello"})
+	assert.NoError(t, err)
+	assert.Len(t, *requests, 2)
+	assert.NotEqual(t, (*requests)[0].URL.Path, (*requests)[1].URL.Path, "every event needs its own transaction ID")
+}
+
+func TestRedact(t *testing.T) {
+	server, requests := homeserver(t)
+
+	err := testClient(server, server.URL, "token").Redact(context.Background(), "!room:example.org", "$event")
+	assert.NoError(t, err)
+	assert.True(t, strings.HasPrefix((*requests)[0].URL.Path, "/_matrix/client/v3/rooms/!room:example.org/redact/$event/"))
+}
+
+func TestUpload(t *testing.T) {
+	var body []byte
+	var contentType, filename string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ = io.ReadAll(r.Body)
+		contentType = r.Header.Get("Content-Type")
+		filename = r.URL.Query().Get("filename")
+		_ = json.NewEncoder(w).Encode(map[string]string{"content_uri": "mxc://example.org/image"})
+	}))
+	defer server.Close()
+
+	uri, err := testClient(server, server.URL, "token").Upload(context.Background(), "image.png", "image/png", []byte("image"))
+	assert.NoError(t, err)
+	assert.Equal(t, "mxc://example.org/image", uri)
+	assert.Equal(t, []byte("image"), body)
+	assert.Equal(t, "image/png", contentType)
+	assert.Equal(t, "image.png", filename)
+}
@@ -0,0 +1,385 @@
+// Package passkey runs the WebAuthn ceremonies for the admin login: adding a
+// passkey or security key to an account, and logging in with it, either in
+// place of the password or as the second factor.
+package passkey
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/go-webauthn/webauthn/protocol"
+	"github.com/go-webauthn/webauthn/webauthn"
+	"github.com/systemli/ticker/internal/config"
+	"github.com/systemli/ticker/internal/storage"
+)
+
+// displayName is the name of the service authenticators show next to the
+// account.
+const displayName = "Ticker"
+
+var (
+	// ErrNoCredentials is returned when a user without passkeys is asked for
+	// one.
+	ErrNoCredentials = errors.New("user has no passkeys")
+	// ErrSession is returned for an unknown or expired session, or one which
+	// was started for another user.
+	ErrSession = errors.New("invalid session")
+	// ErrCloned is returned when the signature counter of the authenticator
+	// went backwards, which hints at a copy of the key.
+	ErrCloned = errors.New("authenticator might be cloned")
+)
+
+// Response is the answer of the browser to the options of a ceremony,
+// together with the token of its session.
+type Response struct {
+	Session    string          `json:"session" binding:"required"`
+	Credential json.RawMessage `json:"credential" binding:"required"`
+}
+
+// RelyingParty verifies the passkeys of the users against the configured
+// domain and origins.
+type RelyingParty struct {
+	webauthn *webauthn.WebAuthn
+	storage  storage.Storage
+}
+
+func New(cfg config.WebAuthn, store storage.Storage) (*RelyingParty, error) {
+	timeout := webauthn.TimeoutConfig{
+		Enforce:    true,
+		Timeout:    storage.WebAuthnSessionTTL,
+		TimeoutUVD: storage.WebAuthnSessionTTL,
+	}
+
+	w, err := webauthn.New(&webauthn.Config{
+		RPID:          cfg.RPID,
+		RPDisplayName: displayName,
+		RPOrigins:     cfg.Origins,
+		AuthenticatorSelection: protocol.AuthenticatorSelection{
+			ResidentKey:        protocol.ResidentKeyRequirementPreferred,
+			RequireResidentKey: protocol.ResidentKeyNotRequired(),
+			UserVerification:   protocol.VerificationPreferred,
+		},
+		Timeouts: webauthn.TimeoutsConfig{Login: timeout, Registration: timeout},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &RelyingParty{webauthn: w, storage: store}, nil
+}
+
+// BeginRegistration returns the options for the browser to create a new
+// passkey for the user, and the token of the session to answer with.
+func (rp *RelyingParty) BeginRegistration(user *storage.User) (*protocol.CredentialCreation, string, error) {
+	if user.WebAuthnHandle == "" {
+		handle, err := storage.NewWebAuthnHandle()
+		if err != nil {
+			return nil, "", err
+		}
+
+		user.WebAuthnHandle = handle
+		if err := rp.storage.SaveWebAuthnHandle(user); err != nil {
+			return nil, "", err
+		}
+	}
+
+	u, err := rp.loadUser(*user)
+	if err != nil {
+		return nil, "", err
+	}
+
+	// Excluding the existing passkeys keeps an authenticator from being
+	// added twice.
+	exclusions := webauthn.Credentials(u.WebAuthnCredentials()).CredentialDescriptors()
+	creation, session, err := rp.webauthn.BeginRegistration(u, webauthn.WithExclusions(exclusions))
+	if err != nil {
+		return nil, "", err
+	}
+
+	token, err := rp.saveSession(user.ID, session)
+	if err != nil {
+		return nil, "", err
+	}
+
+	return creation, token, nil
+}
+
+// FinishRegistration verifies the new passkey and stores it under the name.
+func (rp *RelyingParty) FinishRegistration(user storage.User, name string, response Response) (storage.WebAuthnCredential, error) {
+	session, err := rp.takeSession(response.Session, user.ID)
+	if err != nil {
+		return storage.WebAuthnCredential{}, err
+	}
+
+	parsed, err := protocol.ParseCredentialCreationResponseBytes(response.Credential)
+	if err != nil {
+		return storage.WebAuthnCredential{}, err
+	}
+
+	u, err := rp.loadUser(user)
+	if err != nil {
+		return storage.WebAuthnCredential{}, err
+	}
+
+	credential, err := rp.webauthn.CreateCredential(u, session, parsed)
+	if err != nil {
+		return storage.WebAuthnCredential{}, err
+	}
+
+	c := fromCredential(user, credential)
+	c.Name = name
+	if err := rp.storage.SaveWebAuthnCredential(&c); err != nil {
+		return storage.WebAuthnCredential{}, err
+	}
+
+	return c, nil
+}
+
+// BeginLogin returns the options for the browser to sign in with a passkey.
+// Without a user, any passkey of the site can be chosen and it has to verify
+// the user, as it replaces the password. With a user, only the passkeys of
+// that user are accepted, as the second factor.
+func (rp *RelyingParty) BeginLogin(user *storage.User) (*protocol.CredentialAssertion, string, error) {
+	var (
+		assertion *protocol.CredentialAssertion
+		session   *webauthn.SessionData
+		userID    int
+		err       error
+	)
+
+	if user == nil {
+		assertion, session, err = rp.webauthn.BeginDiscoverableLogin(webauthn.WithUserVerification(protocol.VerificationRequired))
+	} else {
+		var u *webauthnUser
+		u, err = rp.loadUser(*user)
+		if err != nil {
+			return nil, "", err
+		}
+		if len(u.credentials) == 0 {
+			return nil, "", ErrNoCredentials
+		}
+
+		userID = user.ID
+		assertion, session, err = rp.webauthn.BeginLogin(u)
+	}
+	if err != nil {
+		return nil, "", err
+	}
+
+	token, err := rp.saveSession(userID, session)
+	if err != nil {
+		return nil, "", err
+	}
+
+	return assertion, token, nil
+}
+
+// FinishLogin verifies the answer to the options of BeginLogin without a
+// user and returns the user the passkey belongs to.
+func (rp *RelyingParty) FinishLogin(response Response) (storage.User, error) {
+	session, err := rp.takeSession(response.Session, 0)
+	if err != nil {
+		return storage.User{}, err
+	}
+
+	parsed, err := protocol.ParseCredentialRequestResponseBytes(response.Credential)
+	if err != nil {
+		return storage.User{}, err
+	}
+
+	var u *webauthnUser
+	handler := func(_, userHandle []byte) (webauthn.User, error) {
+		user, err := rp.storage.FindUserByWebAuthnHandle(string(userHandle), storage.WithPreload())
+		if err != nil {
+			return nil, err
+		}
+
+		u, err = rp.loadUser(user)
+		return u, err
+	}
+
+	credential, err := rp.webauthn.ValidateDiscoverableLogin(handler, session, parsed)
+	if err != nil {
+		return storage.User{}, err
+	}
+
+	if err := rp.updateCredential(u, credential); err != nil {
+		return storage.User{}, err
+	}
+
+	return u.User, nil
+}
+
+// Verify checks the answer to the options of BeginLogin for the user, when
+// the passkey is the second factor.
+func (rp *RelyingParty) Verify(user storage.User, response Response) error {
+	session, err := rp.takeSession(response.Session, user.ID)
+	if err != nil {
+		return err
+	}
+
+	parsed, err := protocol.ParseCredentialRequestResponseBytes(response.Credential)
+	if err != nil {
+		return err
+	}
+
+	u, err := rp.loadUser(user)
+	if err != nil {
+		return err
+	}
+
+	credential, err := rp.webauthn.ValidateLogin(u, session, parsed)
+	if err != nil {
+		return err
+	}
+
+	return rp.updateCredential(u, credential)
+}
+
+func (rp *RelyingParty) loadUser(user storage.User) (*webauthnUser, error) {
+	credentials, err := rp.storage.FindWebAuthnCredentials(user)
+	if err != nil {
+		return nil, err
+	}
+
+	return &webauthnUser{User: user, credentials: credentials}, nil
+}
+
+func (rp *RelyingParty) saveSession(userID int, data *webauthn.SessionData) (string, error) {
+	b, err := json.Marshal(data)
+	if err != nil {
+		return "", err
+	}
+
+	session, err := storage.NewWebAuthnSession(userID, b)
+	if err != nil {
+		return "", err
+	}
+
+	if err := rp.storage.SaveWebAuthnSession(&session); err != nil {
+		return "", err
+	}
+
+	return session.Token, nil
+}
+
+func (rp *RelyingParty) takeSession(token string, userID int) (webauthn.SessionData, error) {
+	var data webauthn.SessionData
+
+	session, err := rp.storage.TakeWebAuthnSession(token)
+	if err != nil || session.UserID != userID {
+		return data, ErrSession
+	}
+
+	err = json.Unmarshal(session.Data, &data)
+
+	return data, err
+}
+
+// updateCredential stores the signature counter and the flags of the login.
+// Logins with an authenticator which might be cloned are refused.
+func (rp *RelyingParty) updateCredential(u *webauthnUser, credential *webauthn.Credential) error {
+	for _, c := range u.credentials {
+		if !bytes.Equal(c.CredentialID, credential.ID) {
+			continue
+		}
+
+		c.LastUsed = time.Now()
+		c.SignCount = credential.Authenticator.SignCount
+		c.CloneWarning = credential.Authenticator.CloneWarning
+		c.BackupState = credential.Flags.BackupState
+		if err := rp.storage.SaveWebAuthnCredential(&c); err != nil {
+			return err
+		}
+
+		if c.CloneWarning {
+			return ErrCloned
+		}
+
+		return nil
+	}
+
+	return ErrSession
+}
+
+// webauthnUser is a user as the WebAuthn library sees it.
+type webauthnUser struct {
+	storage.User
+	credentials []storage.WebAuthnCredential
+}
+
+func (u *webauthnUser) WebAuthnID() []byte {
+	return []byte(u.WebAuthnHandle)
+}
+
+func (u *webauthnUser) WebAuthnName() string {
+	return u.Email
+}
+
+func (u *webauthnUser) WebAuthnDisplayName() string {
+	return u.Email
+}
+
+func (u *webauthnUser) WebAuthnCredentials() []webauthn.Credential {
+	credentials := make([]webauthn.Credential, 0, len(u.credentials))
+	for _, c := range u.credentials {
+		credentials = append(credentials, toCredential(c))
+	}
+
+	return credentials
+}
+
+func toCredential(c storage.WebAuthnCredential) webauthn.Credential {
+	transports := make([]protocol.AuthenticatorTransport, 0, len(c.Transports))
+	for _, t := range c.Transports {
+		transports = append(transports, protocol.AuthenticatorTransport(t))
+	}
+
+	flags := protocol.FlagUserPresent
+	if c.UserVerified {
+		flags |= protocol.FlagUserVerified
+	}
+	if c.BackupEligible {
+		flags |= protocol.FlagBackupEligible
+	}
+	if c.BackupState {
+		flags |= protocol.FlagBackupState
+	}
+
+	return webauthn.Credential{
+		ID:                c.CredentialID,
+		PublicKey:         c.PublicKey,
+		AttestationType:   c.AttestationType,
+		AttestationFormat: c.AttestationFormat,
+		Transport:         transports,
+		Flags:             webauthn.NewCredentialFlags(flags),
+		Authenticator: webauthn.Authenticator{
+			AAGUID:       c.AAGUID,
+			SignCount:    c.SignCount,
+			CloneWarning: c.CloneWarning,
+		},
+	}
+}
+
+func fromCredential(user storage.User, c *webauthn.Credential) storage.WebAuthnCredential {
+	transports := make([]string, 0, len(c.Transport))
+	for _, t := range c.Transport {
+		transports = append(transports, string(t))
+	}
+
+	return storage.WebAuthnCredential{
+		UserID:            user.ID,
+		CredentialID:      c.ID,
+		PublicKey:         c.PublicKey,
+		AttestationType:   c.AttestationType,
+		AttestationFormat: c.AttestationFormat,
+		Transports:        transports,
+		AAGUID:            c.Authenticator.AAGUID,
+		SignCount:         c.Authenticator.SignCount,
+		UserVerified:      c.Flags.UserVerified,
+		BackupEligible:    c.Flags.BackupEligible,
+		BackupState:       c.Flags.BackupState,
+	}
+}
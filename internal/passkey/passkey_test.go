@@ -0,0 +1,235 @@
+package passkey
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+	"github.com/systemli/ticker/internal/config"
+	"github.com/systemli/ticker/internal/passkey/passkeytest"
+	"github.com/systemli/ticker/internal/storage"
+	"gorm.io/gorm"
+)
+
+type PasskeyTestSuite struct {
+	store         *storage.MockStorage
+	rp            *RelyingParty
+	authenticator *passkeytest.Authenticator
+	user          storage.User
+	sessions      map[string]storage.WebAuthnSession
+	suite.Suite
+}
+
+func (s *PasskeyTestSuite) SetupTest() {
+	s.store = &storage.MockStorage{}
+	s.sessions = make(map[string]storage.WebAuthnSession)
+	s.authenticator = passkeytest.NewAuthenticator(s.T())
+	s.user = storage.User{ID: 1, Email: "user@systemli.org"}
+
+	var err error
+	s.rp, err = New(config.WebAuthn{RPID: passkeytest.RPID, Origins: []string{passkeytest.Origin}}, s.store)
+	s.Require().NoError(err)
+
+	s.store.On("SaveWebAuthnSession", mock.Anything).Run(func(args mock.Arguments) {
+		session := args.Get(0).(*storage.WebAuthnSession)
+		s.sessions[session.Token] = *session
+	}).Return(nil)
+	s.store.On("TakeWebAuthnSession", mock.Anything).Return(func(token string) (storage.WebAuthnSession, error) {
+		session, ok := s.sessions[token]
+		if !ok {
+			return storage.WebAuthnSession{}, gorm.ErrRecordNotFound
+		}
+		delete(s.sessions, token)
+
+		return session, nil
+	})
+}
+
+func (s *PasskeyTestSuite) TestNew() {
+	_, err := New(config.WebAuthn{RPID: passkeytest.RPID}, s.store)
+	s.Error(err)
+}
+
+func (s *PasskeyTestSuite) TestRegistration() {
+	s.store.On("SaveWebAuthnHandle", mock.Anything).Return(nil).Once()
+	s.store.On("FindWebAuthnCredentials", mock.Anything).Return([]storage.WebAuthnCredential{}, nil)
+	s.store.On("SaveWebAuthnCredential", mock.Anything).Return(nil).Once()
+
+	user := s.user
+	options, token, err := s.rp.BeginRegistration(&user)
+	s.Require().NoError(err)
+	s.Len(user.WebAuthnHandle, 64)
+	s.Equal(passkeytest.RPID, options.Response.RelyingParty.ID)
+	s.Equal(1, s.sessions[token].UserID)
+
+	response := Response{Session: token, Credential: s.authenticator.Create(s.T(), options)}
+	credential, err := s.rp.FinishRegistration(user, "Laptop", response)
+	s.Require().NoError(err)
+	s.Equal(1, credential.UserID)
+	s.Equal("Laptop", credential.Name)
+	s.Equal(s.authenticator.ID, credential.CredentialID)
+	s.Equal([]string{"internal"}, credential.Transports)
+	s.True(credential.UserVerified)
+
+	s.Run("when the session is used again", func() {
+		_, err := s.rp.FinishRegistration(user, "Laptop", response)
+		s.ErrorIs(err, ErrSession)
+	})
+
+	s.store.AssertExpectations(s.T())
+}
+
+func (s *PasskeyTestSuite) TestRegistrationWithWrongOrigin() {
+	s.store.On("FindWebAuthnCredentials", mock.Anything).Return([]storage.WebAuthnCredential{}, nil)
+
+	user := s.user
+	user.WebAuthnHandle = "handle"
+	options, token, err := s.rp.BeginRegistration(&user)
+	s.Require().NoError(err)
+
+	s.authenticator.Origin = "https://phishing.example.com"
+	_, err = s.rp.FinishRegistration(user, "Laptop", Response{Session: token, Credential: s.authenticator.Create(s.T(), options)})
+	s.Error(err)
+	s.store.AssertNotCalled(s.T(), "SaveWebAuthnCredential", mock.Anything)
+}
+
+func (s *PasskeyTestSuite) TestRegistrationForAnotherUser() {
+	s.store.On("FindWebAuthnCredentials", mock.Anything).Return([]storage.WebAuthnCredential{}, nil)
+
+	user := s.user
+	user.WebAuthnHandle = "handle"
+	options, token, err := s.rp.BeginRegistration(&user)
+	s.Require().NoError(err)
+
+	other := storage.User{ID: 2, WebAuthnHandle: "other"}
+	_, err = s.rp.FinishRegistration(other, "Laptop", Response{Session: token, Credential: s.authenticator.Create(s.T(), options)})
+	s.ErrorIs(err, ErrSession)
+}
+
+func (s *PasskeyTestSuite) TestLogin() {
+	user, credential := s.register()
+	s.store.On("FindUserByWebAuthnHandle", user.WebAuthnHandle, mock.Anything).Return(user, nil)
+	s.store.On("FindWebAuthnCredentials", mock.Anything).Return([]storage.WebAuthnCredential{credential}, nil)
+	s.store.On("SaveWebAuthnCredential", mock.MatchedBy(func(c *storage.WebAuthnCredential) bool {
+		return c.ID == credential.ID && c.SignCount == 1 && !c.LastUsed.IsZero()
+	})).Return(nil).Once()
+
+	options, token, err := s.rp.BeginLogin(nil)
+	s.Require().NoError(err)
+	s.Empty(options.Response.AllowedCredentials)
+	s.Equal(0, s.sessions[token].UserID)
+
+	found, err := s.rp.FinishLogin(Response{Session: token, Credential: s.authenticator.Get(s.T(), options)})
+	s.Require().NoError(err)
+	s.Equal(user.ID, found.ID)
+	s.store.AssertExpectations(s.T())
+}
+
+func (s *PasskeyTestSuite) TestLoginWithoutUserVerification() {
+	user, credential := s.register()
+	s.store.On("FindUserByWebAuthnHandle", user.WebAuthnHandle, mock.Anything).Return(user, nil)
+	s.store.On("FindWebAuthnCredentials", mock.Anything).Return([]storage.WebAuthnCredential{credential}, nil)
+
+	options, token, err := s.rp.BeginLogin(nil)
+	s.Require().NoError(err)
+
+	s.authenticator.SkipVerification = true
+	_, err = s.rp.FinishLogin(Response{Session: token, Credential: s.authenticator.Get(s.T(), options)})
+	s.Error(err)
+	s.store.AssertNotCalled(s.T(), "SaveWebAuthnCredential", mock.Anything)
+}
+
+func (s *PasskeyTestSuite) TestLoginWithClonedAuthenticator() {
+	user, credential := s.register()
+	credential.SignCount = 10
+	s.store.On("FindUserByWebAuthnHandle", user.WebAuthnHandle, mock.Anything).Return(user, nil)
+	s.store.On("FindWebAuthnCredentials", mock.Anything).Return([]storage.WebAuthnCredential{credential}, nil)
+	s.store.On("SaveWebAuthnCredential", mock.MatchedBy(func(c *storage.WebAuthnCredential) bool {
+		return c.CloneWarning
+	})).Return(nil).Once()
+
+	options, token, err := s.rp.BeginLogin(nil)
+	s.Require().NoError(err)
+
+	_, err = s.rp.FinishLogin(Response{Session: token, Credential: s.authenticator.Get(s.T(), options)})
+	s.ErrorIs(err, ErrCloned)
+	s.store.AssertExpectations(s.T())
+}
+
+func (s *PasskeyTestSuite) TestVerify() {
+	user, credential := s.register()
+	s.store.On("FindWebAuthnCredentials", mock.Anything).Return([]storage.WebAuthnCredential{credential}, nil)
+	s.store.On("SaveWebAuthnCredential", mock.Anything).Return(nil)
+
+	s.Run("when the passkey is the second factor", func() {
+		s.authenticator.SkipVerification = true
+		options, token, err := s.rp.BeginLogin(&user)
+		s.Require().NoError(err)
+		s.Len(options.Response.AllowedCredentials, 1)
+		s.Equal(user.ID, s.sessions[token].UserID)
+
+		err = s.rp.Verify(user, Response{Session: token, Credential: s.authenticator.Get(s.T(), options)})
+		s.NoError(err)
+	})
+
+	s.Run("when the session belongs to a passkey login", func() {
+		options, token, err := s.rp.BeginLogin(nil)
+		s.Require().NoError(err)
+
+		err = s.rp.Verify(user, Response{Session: token, Credential: s.authenticator.Get(s.T(), options)})
+		s.ErrorIs(err, ErrSession)
+	})
+
+	s.Run("when the answer is signed by another key", func() {
+		options, token, err := s.rp.BeginLogin(&user)
+		s.Require().NoError(err)
+
+		other := passkeytest.NewAuthenticator(s.T())
+		other.ID = s.authenticator.ID
+		other.UserHandle = s.authenticator.UserHandle
+
+		err = s.rp.Verify(user, Response{Session: token, Credential: other.Get(s.T(), options)})
+		s.Error(err)
+	})
+}
+
+func (s *PasskeyTestSuite) TestBeginLoginWithoutCredentials() {
+	s.store.On("FindWebAuthnCredentials", mock.Anything).Return([]storage.WebAuthnCredential{}, nil)
+
+	_, _, err := s.rp.BeginLogin(&s.user)
+	s.ErrorIs(err, ErrNoCredentials)
+}
+
+// register adds a passkey of the authenticator to the user, and returns both
+// as they are stored.
+func (s *PasskeyTestSuite) register() (storage.User, storage.WebAuthnCredential) {
+	var session storage.WebAuthnSession
+	registration := &storage.MockStorage{}
+	registration.On("SaveWebAuthnHandle", mock.Anything).Return(nil)
+	registration.On("FindWebAuthnCredentials", mock.Anything).Return([]storage.WebAuthnCredential{}, nil)
+	registration.On("SaveWebAuthnSession", mock.Anything).Run(func(args mock.Arguments) {
+		session = *args.Get(0).(*storage.WebAuthnSession)
+	}).Return(nil)
+	registration.On("TakeWebAuthnSession", mock.Anything).Return(func(string) (storage.WebAuthnSession, error) {
+		return session, nil
+	})
+	registration.On("SaveWebAuthnCredential", mock.Anything).Run(func(args mock.Arguments) {
+		args.Get(0).(*storage.WebAuthnCredential).ID = 1
+	}).Return(nil)
+
+	rp := *s.rp
+	rp.storage = registration
+
+	user := s.user
+	options, token, err := rp.BeginRegistration(&user)
+	s.Require().NoError(err)
+
+	credential, err := rp.FinishRegistration(user, "Laptop", Response{Session: token, Credential: s.authenticator.Create(s.T(), options)})
+	s.Require().NoError(err)
+
+	return user, credential
+}
+
+func TestPasskeyTestSuite(t *testing.T) {
+	suite.Run(t, new(PasskeyTestSuite))
+}
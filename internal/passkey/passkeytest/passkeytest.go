@@ -0,0 +1,162 @@
+// Package passkeytest provides a software authenticator for tests, which
+// answers the options of the passkey ceremonies the way a browser does.
+package passkeytest
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"testing"
+
+	"github.com/go-webauthn/webauthn/protocol"
+	"github.com/go-webauthn/webauthn/protocol/webauthncbor"
+	"github.com/go-webauthn/webauthn/protocol/webauthncose"
+)
+
+const (
+	RPID   = "ticker.example.org"
+	Origin = "https://admin.ticker.example.org"
+)
+
+var encoding = base64.RawURLEncoding
+
+// Authenticator holds a single credential. It verifies the user on every
+// request unless SkipVerification is set.
+type Authenticator struct {
+	ID     []byte
+	Key    *ecdsa.PrivateKey
+	Origin string
+	// SignCount is the signature counter, it goes up with every login.
+	SignCount        uint32
+	SkipVerification bool
+	// UserHandle is the handle the credential was created for.
+	UserHandle []byte
+}
+
+func NewAuthenticator(t testing.TB) *Authenticator {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	id := make([]byte, 16)
+	if _, err := rand.Read(id); err != nil {
+		t.Fatal(err)
+	}
+
+	return &Authenticator{ID: id, Key: key, Origin: Origin}
+}
+
+// Create answers the options of a registration with a new credential and an
+// attestation of the format "none".
+func (a *Authenticator) Create(t testing.TB, options *protocol.CredentialCreation) json.RawMessage {
+	if handle, ok := options.Response.User.ID.(protocol.URLEncodedBase64); ok {
+		a.UserHandle = handle
+	}
+
+	publicKey, err := webauthncbor.Marshal(webauthncose.EC2PublicKeyData{
+		PublicKeyData: webauthncose.PublicKeyData{
+			KeyType:   int64(webauthncose.EllipticKey),
+			Algorithm: int64(webauthncose.AlgES256),
+		},
+		Curve:  int64(webauthncose.P256),
+		XCoord: a.Key.PublicKey.X.FillBytes(make([]byte, 32)),
+		YCoord: a.Key.PublicKey.Y.FillBytes(make([]byte, 32)),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	attested := make([]byte, 16)
+	attested = binary.BigEndian.AppendUint16(attested, uint16(len(a.ID)))
+	attested = append(attested, a.ID...)
+	attested = append(attested, publicKey...)
+
+	authData := append(a.authData(options.Response.RelyingParty.ID, protocol.FlagAttestedCredentialData), attested...)
+
+	attestation, err := webauthncbor.Marshal(struct {
+		Format    string         `cbor:"fmt"`
+		Statement map[string]any `cbor:"attStmt"`
+		AuthData  []byte         `cbor:"authData"`
+	}{"none", map[string]any{}, authData})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return a.marshal(t, map[string]any{
+		"clientDataJSON":    encoding.EncodeToString(a.clientData(t, "webauthn.create", options.Response.Challenge)),
+		"attestationObject": encoding.EncodeToString(attestation),
+		"transports":        []string{"internal"},
+	})
+}
+
+// Get answers the options of a login with a signature of the credential.
+func (a *Authenticator) Get(t testing.TB, options *protocol.CredentialAssertion) json.RawMessage {
+	a.SignCount++
+
+	rpID := options.Response.RelyingPartyID
+	if rpID == "" {
+		rpID = RPID
+	}
+
+	authData := a.authData(rpID, 0)
+	clientData := a.clientData(t, "webauthn.get", options.Response.Challenge)
+	clientDataHash := sha256.Sum256(clientData)
+	digest := sha256.Sum256(append(authData, clientDataHash[:]...))
+
+	signature, err := ecdsa.SignASN1(rand.Reader, a.Key, digest[:])
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return a.marshal(t, map[string]any{
+		"clientDataJSON":    encoding.EncodeToString(clientData),
+		"authenticatorData": encoding.EncodeToString(authData),
+		"signature":         encoding.EncodeToString(signature),
+		"userHandle":        encoding.EncodeToString(a.UserHandle),
+	})
+}
+
+func (a *Authenticator) authData(rpID string, flags protocol.AuthenticatorFlags) []byte {
+	flags |= protocol.FlagUserPresent
+	if !a.SkipVerification {
+		flags |= protocol.FlagUserVerified
+	}
+
+	hash := sha256.Sum256([]byte(rpID))
+	data := append(hash[:], byte(flags))
+
+	return binary.BigEndian.AppendUint32(data, a.SignCount)
+}
+
+func (a *Authenticator) clientData(t testing.TB, kind string, challenge protocol.URLEncodedBase64) []byte {
+	data, err := json.Marshal(map[string]any{
+		"type":        kind,
+		"challenge":   challenge.String(),
+		"origin":      a.Origin,
+		"crossOrigin": false,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return data
+}
+
+func (a *Authenticator) marshal(t testing.TB, response map[string]any) json.RawMessage {
+	data, err := json.Marshal(map[string]any{
+		"id":       encoding.EncodeToString(a.ID),
+		"rawId":    encoding.EncodeToString(a.ID),
+		"type":     "public-key",
+		"response": response,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return data
+}
@@ -0,0 +1,182 @@
+package signal
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/systemli/ticker/internal/storage"
+	"github.com/ybbus/jsonrpc/v3"
+)
+
+// maxEventSize is the largest event read from the stream, attachments are not
+// part of the events, so they stay small.
+const maxEventSize = 1 << 20
+
+// ErrStreamClosed is returned when signal-cli ends the stream of events.
+var ErrStreamClosed = errors.New("event stream closed")
+
+// Attachment is a file sent along with a message, signal-cli keeps it until it
+// is fetched by its ID.
+type Attachment struct {
+	ID          string `json:"id"`
+	ContentType string `json:"contentType"`
+	Filename    string `json:"filename"`
+	Size        int    `json:"size"`
+}
+
+// IncomingMessage is a message sent directly to the account.
+type IncomingMessage struct {
+	Number      string
+	Name        string
+	Timestamp   time.Time
+	Text        string
+	Attachments []Attachment
+}
+
+type receiveEvent struct {
+	Account  string `json:"account"`
+	Envelope struct {
+		SourceNumber string `json:"sourceNumber"`
+		SourceName   string `json:"sourceName"`
+		Timestamp    int64  `json:"timestamp"`
+		DataMessage  *struct {
+			Message     string       `json:"message"`
+			Attachments []Attachment `json:"attachments"`
+			GroupInfo   *struct {
+				GroupID string `json:"groupId"`
+			} `json:"groupInfo"`
+		} `json:"dataMessage"`
+	} `json:"envelope"`
+}
+
+// Receiver follows the messages the account receives, through the events
+// endpoint signal-cli serves next to the JSON-RPC endpoint in HTTP mode.
+type Receiver struct {
+	settings storage.SignalGroupSettings
+	client   jsonrpc.RPCClient
+	http     *http.Client
+}
+
+func NewReceiverFromSettings(settings storage.SignalGroupSettings) *Receiver {
+	// The stream stays open as long as the receiver runs, it must not time out.
+	return &Receiver{settings: settings, client: ClientFromSettings(settings), http: &http.Client{}}
+}
+
+// Receive calls handle for every message sent directly to the account, until
+// the context is cancelled or the stream ends. Messages to groups, receipts and
+// reactions are skipped.
+func (r *Receiver) Receive(ctx context.Context, handle func(IncomingMessage)) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, r.eventsURL(), nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "text/event-stream")
+
+	resp, err := r.http.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("signal-cli responded with %s", resp.Status)
+	}
+
+	scanner := bufio.NewScanner(resp.Body)
+	scanner.Buffer(make([]byte, 0, 64*1024), maxEventSize)
+
+	var data bytes.Buffer
+	for scanner.Scan() {
+		line := scanner.Text()
+		switch {
+		case line == "":
+			if data.Len() > 0 {
+				r.dispatch(data.Bytes(), handle)
+				data.Reset()
+			}
+		case strings.HasPrefix(line, "data:"):
+			if data.Len() > 0 {
+				data.WriteByte('\n')
+			}
+			data.WriteString(strings.TrimPrefix(strings.TrimPrefix(line, "data:"), " "))
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return err
+	}
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	return ErrStreamClosed
+}
+
+func (r *Receiver) dispatch(data []byte, handle func(IncomingMessage)) {
+	var event receiveEvent
+	if err := json.Unmarshal(data, &event); err != nil {
+		log.WithError(err).Warn("failed to decode signal event")
+		return
+	}
+
+	if event.Account != "" && event.Account != r.settings.Account {
+		return
+	}
+
+	envelope := event.Envelope
+	message := envelope.DataMessage
+	if message == nil || message.GroupInfo != nil || envelope.SourceNumber == "" {
+		return
+	}
+	if message.Message == "" && len(message.Attachments) == 0 {
+		return
+	}
+
+	handle(IncomingMessage{
+		Number:      envelope.SourceNumber,
+		Name:        envelope.SourceName,
+		Timestamp:   time.UnixMilli(envelope.Timestamp),
+		Text:        message.Message,
+		Attachments: message.Attachments,
+	})
+}
+
+// Attachment fetches the content of an attachment the number sent.
+func (r *Receiver) Attachment(ctx context.Context, number string, attachment Attachment) ([]byte, error) {
+	params := struct {
+		Account   string `json:"account"`
+		ID        string `json:"id"`
+		Recipient string `json:"recipient"`
+	}{
+		Account:   r.settings.Account,
+		ID:        attachment.ID,
+		Recipient: number,
+	}
+
+	var response struct {
+		Data string `json:"data"`
+	}
+	err := r.client.CallFor(ctx, &response, "getAttachment", &params)
+	if err != nil {
+		return nil, err
+	}
+
+	return base64.StdEncoding.DecodeString(response.Data)
+}
+
+// eventsURL derives the events endpoint from the JSON-RPC endpoint, both are
+// served below /api/v1/.
+func (r *Receiver) eventsURL() string {
+	base := strings.TrimSuffix(strings.TrimSuffix(r.settings.ApiUrl, "/"), "/rpc")
+
+	return base + "/events?account=" + url.QueryEscape(r.settings.Account)
+}
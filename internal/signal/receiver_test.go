@@ -0,0 +1,115 @@
+package signal
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+	"github.com/systemli/ticker/internal/storage"
+)
+
+const account = "+491234567890"
+
+type ReceiverTestSuite struct {
+	suite.Suite
+	events []string
+	server *httptest.Server
+}
+
+func (s *ReceiverTestSuite) SetupTest() {
+	s.events = nil
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /api/v1/events", func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("account") != account {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+
+		w.Header().Set("Content-Type", "text/event-stream")
+		for _, event := range s.events {
+			fmt.Fprintf(w, "event:receive\ndata:%s\n\n", event)
+		}
+	})
+	mux.HandleFunc("POST /api/v1/rpc", func(w http.ResponseWriter, r *http.Request) {
+		var request struct {
+			ID     int               `json:"id"`
+			Method string            `json:"method"`
+			Params map[string]string `json:"params"`
+		}
+		s.Require().NoError(json.NewDecoder(r.Body).Decode(&request))
+
+		if request.Method != "getAttachment" || request.Params["id"] != "attachment.jpg" || request.Params["recipient"] != "+4915112345678" {
+			fmt.Fprintf(w, `{"jsonrpc":"2.0","id":%d,"error":{"code":-1,"message":"not found"}}`, request.ID)
+			return
+		}
+		fmt.Fprintf(w, `{"jsonrpc":"2.0","id":%d,"result":{"data":"aW1hZ2U="}}`, request.ID)
+	})
+
+	s.server = httptest.NewServer(mux)
+}
+
+func (s *ReceiverTestSuite) TearDownTest() {
+	s.server.Close()
+}
+
+func (s *ReceiverTestSuite) receiver() *Receiver {
+	return NewReceiverFromSettings(storage.SignalGroupSettings{ApiUrl: s.server.URL + "/api/v1/rpc", Account: account})
+}
+
+func (s *ReceiverTestSuite) TestReceive() {
+	s.Run("when messages arrive", func() {
+		s.events = []string{
+			`{"envelope":{"sourceNumber":"+4915112345678","sourceName":"Reporter","timestamp":1700000000000,"dataMessage":{"message":"Hello","attachments":[{"id":"attachment.jpg","contentType":"image/jpeg","size":5}]}},"account":"+491234567890"}`,
+			`{"envelope":{"sourceNumber":"+4915112345678","timestamp":1700000000001,"dataMessage":{"message":"To the group","groupInfo":{"groupId":"group"}}},"account":"+491234567890"}`,
+			`{"envelope":{"sourceNumber":"+4915112345678","timestamp":1700000000002,"receiptMessage":{"isDelivery":true}},"account":"+491234567890"}`,
+			`{"envelope":{"sourceNumber":"+4915112345678","timestamp":1700000000003,"dataMessage":{"message":null}},"account":"+491234567890"}`,
+			`invalid`,
+			`{"envelope":{"sourceNumber":"+4915187654321","timestamp":1700000000004,"dataMessage":{"message":"Second"}},"account":"+491234567890"}`,
+		}
+
+		var messages []IncomingMessage
+		err := s.receiver().Receive(context.Background(), func(message IncomingMessage) {
+			messages = append(messages, message)
+		})
+
+		s.ErrorIs(err, ErrStreamClosed)
+		s.Len(messages, 2)
+		s.Equal("+4915112345678", messages[0].Number)
+		s.Equal("Reporter", messages[0].Name)
+		s.Equal("Hello", messages[0].Text)
+		s.Equal(time.UnixMilli(1700000000000), messages[0].Timestamp)
+		s.Len(messages[0].Attachments, 1)
+		s.Equal("image/jpeg", messages[0].Attachments[0].ContentType)
+		s.Equal("Second", messages[1].Text)
+	})
+
+	s.Run("when the account is unknown", func() {
+		r := NewReceiverFromSettings(storage.SignalGroupSettings{ApiUrl: s.server.URL + "/api/v1/rpc", Account: "+490000000000"})
+
+		err := r.Receive(context.Background(), func(IncomingMessage) {})
+		s.ErrorContains(err, "400")
+	})
+}
+
+func (s *ReceiverTestSuite) TestAttachment() {
+	s.Run("when attachment exists", func() {
+		data, err := s.receiver().Attachment(context.Background(), "+4915112345678", Attachment{ID: "attachment.jpg"})
+		s.NoError(err)
+		s.Equal([]byte("image"), data)
+	})
+
+	s.Run("when attachment is missing", func() {
+		_, err := s.receiver().Attachment(context.Background(), "+4915112345678", Attachment{ID: "missing.jpg"})
+		s.Error(err)
+	})
+}
+
+func TestReceiverTestSuite(t *testing.T) {
+	suite.Run(t, new(ReceiverTestSuite))
+}
@@ -4,10 +4,13 @@ import (
 	"context"
 	"errors"
 
+	"github.com/systemli/ticker/internal/logger"
 	"github.com/systemli/ticker/internal/storage"
 	"github.com/ybbus/jsonrpc/v3"
 )
 
+var log = logger.GetWithPackage("signal")
+
 type GroupMember struct {
 	Number string `json:"number"`
 	Uuid   string `json:"uuid"`
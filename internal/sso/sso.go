@@ -0,0 +1,247 @@
+// Package sso runs the single sign-on to the admin interface with OpenID
+// Connect: the authorization code flow with PKCE against the configured
+// identity provider. Users are linked by their email address on the first
+// login, or created if there is none.
+package sso
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/coreos/go-oidc/v3/oidc"
+	"github.com/sethvargo/go-password/password"
+	"github.com/systemli/ticker/internal/config"
+	"github.com/systemli/ticker/internal/storage"
+	"golang.org/x/oauth2"
+	"gorm.io/gorm"
+)
+
+var (
+	// ErrSession is returned for an unknown or expired state, or an ID token
+	// which was issued for another login.
+	ErrSession = errors.New("invalid session")
+	// ErrEmail is returned when the provider does not vouch for the email
+	// address, which is needed to link or create the user.
+	ErrEmail = errors.New("email address is missing or not verified")
+	// ErrRole is returned for users without one of the configured roles.
+	ErrRole = errors.New("user has none of the roles")
+	// ErrLinked is returned when the user of the email address is linked to
+	// another account at the provider.
+	ErrLinked = errors.New("user is linked to another account")
+)
+
+// Callback is what the provider sends the users back to the admin interface
+// with.
+type Callback struct {
+	Code  string `json:"code" binding:"required"`
+	State string `json:"state" binding:"required"`
+}
+
+// Provider logs users in through the identity provider.
+type Provider struct {
+	config  config.OIDC
+	storage storage.Storage
+
+	mu       sync.Mutex
+	provider *oidc.Provider
+}
+
+func New(cfg config.OIDC, store storage.Storage) *Provider {
+	return &Provider{config: cfg, storage: store}
+}
+
+// AuthURL starts a login and returns the URL of the provider to send the user
+// to.
+func (p *Provider) AuthURL(ctx context.Context) (string, error) {
+	provider, err := p.discover(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	verifier := oauth2.GenerateVerifier()
+	session, err := storage.NewOIDCSession(verifier)
+	if err != nil {
+		return "", err
+	}
+
+	if err := p.storage.SaveOIDCSession(&session); err != nil {
+		return "", err
+	}
+
+	return p.oauth2(provider).AuthCodeURL(session.State, oauth2.S256ChallengeOption(verifier), oidc.Nonce(session.Nonce)), nil
+}
+
+// Login redeems the code of the callback and returns the user of the account
+// at the provider. The roles of the account are applied on every login.
+func (p *Provider) Login(ctx context.Context, callback Callback) (storage.User, error) {
+	session, err := p.storage.TakeOIDCSession(callback.State)
+	if err != nil {
+		return storage.User{}, ErrSession
+	}
+
+	provider, err := p.discover(ctx)
+	if err != nil {
+		return storage.User{}, err
+	}
+
+	token, err := p.oauth2(provider).Exchange(ctx, callback.Code, oauth2.VerifierOption(session.Verifier))
+	if err != nil {
+		return storage.User{}, err
+	}
+
+	raw, ok := token.Extra("id_token").(string)
+	if !ok {
+		return storage.User{}, errors.New("id token is missing")
+	}
+
+	idToken, err := provider.Verifier(&oidc.Config{ClientID: p.config.ClientID}).Verify(ctx, raw)
+	if err != nil {
+		return storage.User{}, err
+	}
+	if idToken.Nonce != session.Nonce {
+		return storage.User{}, ErrSession
+	}
+
+	var claims map[string]interface{}
+	if err := idToken.Claims(&claims); err != nil {
+		return storage.User{}, err
+	}
+
+	admin, allowed := p.roles(claims)
+	if !allowed {
+		return storage.User{}, ErrRole
+	}
+
+	user, err := p.findUser(idToken.Subject, claims)
+	if err != nil {
+		return storage.User{}, err
+	}
+
+	// Without admin roles, the super admins are managed in the interface.
+	if len(p.config.AdminRoles) > 0 {
+		user.IsSuperAdmin = admin
+	}
+	user.LastLogin = time.Now()
+	if err := p.storage.SaveUser(&user); err != nil {
+		return storage.User{}, err
+	}
+
+	return user, nil
+}
+
+// discover reads the discovery document of the provider on the first login,
+// and again after a failure, so the API starts while the provider is down.
+func (p *Provider) discover(ctx context.Context) (*oidc.Provider, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.provider != nil {
+		return p.provider, nil
+	}
+
+	provider, err := oidc.NewProvider(ctx, p.config.Issuer)
+	if err != nil {
+		return nil, err
+	}
+	p.provider = provider
+
+	return provider, nil
+}
+
+func (p *Provider) oauth2(provider *oidc.Provider) *oauth2.Config {
+	return &oauth2.Config{
+		ClientID:     p.config.ClientID,
+		ClientSecret: p.config.ClientSecret,
+		RedirectURL:  p.config.RedirectURL,
+		Endpoint:     provider.Endpoint(),
+		Scopes:       []string{oidc.ScopeOpenID, "email", "profile"},
+	}
+}
+
+// findUser returns the user linked to the subject. Otherwise, the user with
+// the verified email address is linked, or a new one is created.
+func (p *Provider) findUser(subject string, claims map[string]interface{}) (storage.User, error) {
+	user, err := p.storage.FindUserByOIDCSubject(subject, storage.WithPreload())
+	if err == nil {
+		return user, nil
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return storage.User{}, err
+	}
+
+	email, _ := claims["email"].(string)
+	verified, _ := claims["email_verified"].(bool)
+	if email == "" || !verified {
+		return storage.User{}, ErrEmail
+	}
+
+	user, err = p.storage.FindUserByEmail(email, storage.WithPreload())
+	if err == nil {
+		if user.OIDCSubject != "" {
+			return storage.User{}, ErrLinked
+		}
+
+		user.OIDCSubject = subject
+		return user, p.storage.SaveOIDCSubject(&user)
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return storage.User{}, err
+	}
+
+	// The password is never shown, linked users log in through the provider.
+	pw, err := password.Generate(24, 3, 3, false, false)
+	if err != nil {
+		return storage.User{}, err
+	}
+
+	user, err = storage.NewUser(email, pw)
+	user.OIDCSubject = subject
+
+	return user, err
+}
+
+// roles maps the role claim to the admin flag. Users are allowed in when no
+// roles are configured, or when they have one of them.
+func (p *Provider) roles(claims map[string]interface{}) (admin, allowed bool) {
+	if len(p.config.AdminRoles) == 0 && len(p.config.UserRoles) == 0 {
+		return false, true
+	}
+
+	values := claimValues(claims, p.config.RoleClaim)
+	admin = slices.ContainsFunc(values, func(v string) bool { return slices.Contains(p.config.AdminRoles, v) })
+	user := slices.ContainsFunc(values, func(v string) bool { return slices.Contains(p.config.UserRoles, v) })
+
+	return admin, admin || user
+}
+
+// claimValues returns the strings of the claim. Nested claims are separated
+// by dots, like "realm_access.roles" of Keycloak.
+func claimValues(claims map[string]interface{}, name string) []string {
+	var value interface{} = claims
+	for _, key := range strings.Split(name, ".") {
+		m, ok := value.(map[string]interface{})
+		if !ok {
+			return nil
+		}
+		value = m[key]
+	}
+
+	switch v := value.(type) {
+	case string:
+		return []string{v}
+	case []interface{}:
+		values := make([]string, 0, len(v))
+		for _, item := range v {
+			if s, ok := item.(string); ok {
+				values = append(values, s)
+			}
+		}
+		return values
+	}
+
+	return nil
+}
@@ -0,0 +1,219 @@
+package sso
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+	"github.com/systemli/ticker/internal/config"
+	"github.com/systemli/ticker/internal/sso/ssotest"
+	"github.com/systemli/ticker/internal/storage"
+	"gorm.io/gorm"
+)
+
+type SSOTestSuite struct {
+	server   *ssotest.Server
+	store    *storage.MockStorage
+	sessions map[string]storage.OIDCSession
+	suite.Suite
+}
+
+func (s *SSOTestSuite) SetupTest() {
+	s.server = ssotest.NewServer(s.T())
+}
+
+func (s *SSOTestSuite) Run(name string, subtest func()) {
+	s.T().Run(name, func(t *testing.T) {
+		s.setupStore()
+
+		subtest()
+	})
+}
+
+func (s *SSOTestSuite) setupStore() {
+	s.store = &storage.MockStorage{}
+	s.sessions = make(map[string]storage.OIDCSession)
+
+	s.store.On("SaveOIDCSession", mock.Anything).Run(func(args mock.Arguments) {
+		session := args.Get(0).(*storage.OIDCSession)
+		s.sessions[session.State] = *session
+	}).Return(nil)
+	s.store.On("TakeOIDCSession", mock.Anything).Return(func(state string) (storage.OIDCSession, error) {
+		session, ok := s.sessions[state]
+		if !ok {
+			return storage.OIDCSession{}, gorm.ErrRecordNotFound
+		}
+		delete(s.sessions, state)
+
+		return session, nil
+	})
+}
+
+func (s *SSOTestSuite) TestAuthURL() {
+	s.Run("when the provider is down", func() {
+		p := New(config.OIDC{Issuer: "http://127.0.0.1:1", ClientID: ssotest.ClientID, RedirectURL: ssotest.RedirectURL}, s.store)
+
+		_, err := p.AuthURL(context.Background())
+		s.Error(err)
+	})
+
+	s.Run("when a login starts", func() {
+		p := New(s.server.Config(), s.store)
+
+		url, err := p.AuthURL(context.Background())
+		s.NoError(err)
+		s.Contains(url, s.server.URL+"/auth?")
+		s.Contains(url, "code_challenge_method=S256")
+		s.Len(s.sessions, 1)
+	})
+}
+
+func (s *SSOTestSuite) TestLogin() {
+	claims := map[string]interface{}{"sub": "subject", "email": "user@systemli.org", "email_verified": true}
+
+	s.Run("when the user is new", func() {
+		s.store.On("FindUserByOIDCSubject", "subject", mock.Anything).Return(storage.User{}, gorm.ErrRecordNotFound).Once()
+		s.store.On("FindUserByEmail", "user@systemli.org", mock.Anything).Return(storage.User{}, gorm.ErrRecordNotFound).Once()
+		s.store.On("SaveUser", mock.MatchedBy(func(u *storage.User) bool {
+			return u.ID == 0 && u.Email == "user@systemli.org" && u.OIDCSubject == "subject" && u.EncryptedPassword != "" && !u.LastLogin.IsZero()
+		})).Return(nil).Once()
+
+		user, err := s.login(New(s.server.Config(), s.store), claims)
+		s.NoError(err)
+		s.Equal("subject", user.OIDCSubject)
+		s.store.AssertExpectations(s.T())
+	})
+
+	s.Run("when the user is linked", func() {
+		s.store.On("FindUserByOIDCSubject", "subject", mock.Anything).Return(storage.User{ID: 1, Email: "old@systemli.org", OIDCSubject: "subject"}, nil).Once()
+		s.store.On("SaveUser", mock.MatchedBy(func(u *storage.User) bool { return u.ID == 1 })).Return(nil).Once()
+
+		user, err := s.login(New(s.server.Config(), s.store), claims)
+		s.NoError(err)
+		s.Equal(1, user.ID)
+		s.store.AssertExpectations(s.T())
+	})
+
+	s.Run("when the user exists", func() {
+		s.store.On("FindUserByOIDCSubject", "subject", mock.Anything).Return(storage.User{}, gorm.ErrRecordNotFound).Once()
+		s.store.On("FindUserByEmail", "user@systemli.org", mock.Anything).Return(storage.User{ID: 1, Email: "user@systemli.org", IsSuperAdmin: true}, nil).Once()
+		s.store.On("SaveOIDCSubject", mock.MatchedBy(func(u *storage.User) bool { return u.ID == 1 && u.OIDCSubject == "subject" })).Return(nil).Once()
+		s.store.On("SaveUser", mock.MatchedBy(func(u *storage.User) bool { return u.ID == 1 && u.IsSuperAdmin })).Return(nil).Once()
+
+		user, err := s.login(New(s.server.Config(), s.store), claims)
+		s.NoError(err)
+		s.Equal(1, user.ID)
+		s.store.AssertExpectations(s.T())
+	})
+
+	s.Run("when the email address is not verified", func() {
+		s.store.On("FindUserByOIDCSubject", "subject", mock.Anything).Return(storage.User{}, gorm.ErrRecordNotFound).Once()
+
+		_, err := s.login(New(s.server.Config(), s.store), map[string]interface{}{"sub": "subject", "email": "user@systemli.org"})
+		s.ErrorIs(err, ErrEmail)
+	})
+
+	s.Run("when the user is linked to another account", func() {
+		s.store.On("FindUserByOIDCSubject", "subject", mock.Anything).Return(storage.User{}, gorm.ErrRecordNotFound).Once()
+		s.store.On("FindUserByEmail", "user@systemli.org", mock.Anything).Return(storage.User{ID: 1, OIDCSubject: "other"}, nil).Once()
+
+		_, err := s.login(New(s.server.Config(), s.store), claims)
+		s.ErrorIs(err, ErrLinked)
+		s.store.AssertNotCalled(s.T(), "SaveOIDCSubject", mock.Anything)
+	})
+
+	s.Run("when the state is used again", func() {
+		s.store.On("FindUserByOIDCSubject", "subject", mock.Anything).Return(storage.User{ID: 1, OIDCSubject: "subject"}, nil).Once()
+		s.store.On("SaveUser", mock.Anything).Return(nil).Once()
+
+		p := New(s.server.Config(), s.store)
+		url, err := p.AuthURL(context.Background())
+		s.Require().NoError(err)
+		code, state := s.server.Authorize(s.T(), url, claims)
+
+		_, err = p.Login(context.Background(), Callback{Code: code, State: state})
+		s.NoError(err)
+
+		_, err = p.Login(context.Background(), Callback{Code: code, State: state})
+		s.ErrorIs(err, ErrSession)
+	})
+
+	s.Run("when the code belongs to another login", func() {
+		p := New(s.server.Config(), s.store)
+		url, err := p.AuthURL(context.Background())
+		s.Require().NoError(err)
+		code, _ := s.server.Authorize(s.T(), url, claims)
+
+		other, err := p.AuthURL(context.Background())
+		s.Require().NoError(err)
+		_, state := s.server.Authorize(s.T(), other, claims)
+
+		_, err = p.Login(context.Background(), Callback{Code: code, State: state})
+		s.Error(err)
+		s.store.AssertNotCalled(s.T(), "SaveUser", mock.Anything)
+	})
+}
+
+func (s *SSOTestSuite) TestLoginWithRoles() {
+	cfg := s.server.Config()
+	cfg.RoleClaim = "realm_access.roles"
+	cfg.AdminRoles = []string{"ticker-admin"}
+	cfg.UserRoles = []string{"ticker-editor"}
+
+	claims := func(roles ...string) map[string]interface{} {
+		return map[string]interface{}{"sub": "subject", "realm_access": map[string]interface{}{"roles": roles}}
+	}
+
+	s.Run("when the user has an admin role", func() {
+		s.store.On("FindUserByOIDCSubject", "subject", mock.Anything).Return(storage.User{ID: 1, OIDCSubject: "subject"}, nil).Once()
+		s.store.On("SaveUser", mock.MatchedBy(func(u *storage.User) bool { return u.IsSuperAdmin })).Return(nil).Once()
+
+		_, err := s.login(New(cfg, s.store), claims("offline_access", "ticker-admin"))
+		s.NoError(err)
+		s.store.AssertExpectations(s.T())
+	})
+
+	s.Run("when the user has a user role", func() {
+		s.store.On("FindUserByOIDCSubject", "subject", mock.Anything).Return(storage.User{ID: 1, OIDCSubject: "subject", IsSuperAdmin: true}, nil).Once()
+		s.store.On("SaveUser", mock.MatchedBy(func(u *storage.User) bool { return !u.IsSuperAdmin })).Return(nil).Once()
+
+		_, err := s.login(New(cfg, s.store), claims("ticker-editor"))
+		s.NoError(err)
+		s.store.AssertExpectations(s.T())
+	})
+
+	s.Run("when the user has none of the roles", func() {
+		_, err := s.login(New(cfg, s.store), claims("offline_access"))
+		s.ErrorIs(err, ErrRole)
+		s.store.AssertNotCalled(s.T(), "FindUserByOIDCSubject", mock.Anything, mock.Anything)
+	})
+}
+
+func (s *SSOTestSuite) TestClaimValues() {
+	claims := map[string]interface{}{
+		"groups":       []interface{}{"a", 1, "b"},
+		"role":         "admin",
+		"realm_access": map[string]interface{}{"roles": []interface{}{"c"}},
+	}
+
+	s.Equal([]string{"a", "b"}, claimValues(claims, "groups"))
+	s.Equal([]string{"admin"}, claimValues(claims, "role"))
+	s.Equal([]string{"c"}, claimValues(claims, "realm_access.roles"))
+	s.Nil(claimValues(claims, "groups.roles"))
+	s.Nil(claimValues(claims, "missing"))
+}
+
+// login runs a login with the claims, from the auth URL to the callback.
+func (s *SSOTestSuite) login(p *Provider, claims map[string]interface{}) (storage.User, error) {
+	url, err := p.AuthURL(context.Background())
+	s.Require().NoError(err)
+
+	code, state := s.server.Authorize(s.T(), url, claims)
+
+	return p.Login(context.Background(), Callback{Code: code, State: state})
+}
+
+func TestSSOTestSuite(t *testing.T) {
+	suite.Run(t, new(SSOTestSuite))
+}
@@ -0,0 +1,91 @@
+package storage
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+const (
+	GeometryPoint      = "Point"
+	GeometryLineString = "LineString"
+	GeometryPolygon    = "Polygon"
+)
+
+// Geometry is a GeoJSON geometry (RFC 7946) marking a location on the map,
+// such as a kettle (Point), a blocked route (LineString) or a closed area
+// (Polygon). Positions are longitude, latitude.
+type Geometry struct {
+	Type        string          `json:"type"`
+	Coordinates json.RawMessage `json:"coordinates"`
+}
+
+type position []float64
+
+// Validate checks that the geometry is a Point, LineString or Polygon with
+// valid positions.
+func (g Geometry) Validate() error {
+	switch g.Type {
+	case GeometryPoint:
+		var p position
+		if err := json.Unmarshal(g.Coordinates, &p); err != nil {
+			return err
+		}
+		return p.validate()
+	case GeometryLineString:
+		var line []position
+		if err := json.Unmarshal(g.Coordinates, &line); err != nil {
+			return err
+		}
+		return validateLine(line, 2)
+	case GeometryPolygon:
+		var rings [][]position
+		if err := json.Unmarshal(g.Coordinates, &rings); err != nil {
+			return err
+		}
+		if len(rings) == 0 {
+			return errors.New("polygon needs at least one ring")
+		}
+		for _, ring := range rings {
+			if err := validateLine(ring, 4); err != nil {
+				return err
+			}
+			if !ring[0].equal(ring[len(ring)-1]) {
+				return errors.New("polygon rings must be closed")
+			}
+		}
+		return nil
+	default:
+		return fmt.Errorf("unsupported geometry type %q", g.Type)
+	}
+}
+
+func validateLine(line []position, min int) error {
+	if len(line) < min {
+		return fmt.Errorf("need at least %d positions", min)
+	}
+
+	for _, p := range line {
+		if err := p.validate(); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (p position) validate() error {
+	if len(p) < 2 || len(p) > 3 {
+		return errors.New("position needs longitude and latitude")
+	}
+
+	if p[0] < -180 || p[0] > 180 || p[1] < -90 || p[1] > 90 {
+		return errors.New("position is out of range")
+	}
+
+	return nil
+}
+
+func (p position) equal(o position) bool {
+	return len(p) >= 2 && len(o) >= 2 && p[0] == o[0] && p[1] == o[1]
+}
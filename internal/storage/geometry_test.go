@@ -0,0 +1,32 @@
+package storage
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGeometryValidate(t *testing.T) {
+	valid := []Geometry{
+		{Type: GeometryPoint, Coordinates: []byte(`[13.4, 52.5]`)},
+		{Type: GeometryPoint, Coordinates: []byte(`[13.4, 52.5, 34]`)},
+		{Type: GeometryLineString, Coordinates: []byte(`[[13.4, 52.5], [13.5, 52.6]]`)},
+		{Type: GeometryPolygon, Coordinates: []byte(`[[[13.4, 52.5], [13.5, 52.5], [13.5, 52.6], [13.4, 52.5]]]`)},
+	}
+	for _, g := range valid {
+		assert.NoError(t, g.Validate(), g.Type)
+	}
+
+	invalid := []Geometry{
+		{Type: "MultiPoint", Coordinates: []byte(`[[13.4, 52.5]]`)},
+		{Type: GeometryPoint, Coordinates: []byte(`[13.4]`)},
+		{Type: GeometryPoint, Coordinates: []byte(`[200, 52.5]`)},
+		{Type: GeometryPoint, Coordinates: []byte(`"13.4, 52.5"`)},
+		{Type: GeometryLineString, Coordinates: []byte(`[[13.4, 52.5]]`)},
+		{Type: GeometryPolygon, Coordinates: []byte(`[]`)},
+		{Type: GeometryPolygon, Coordinates: []byte(`[[[13.4, 52.5], [13.5, 52.5], [13.5, 52.6], [13.4, 52.6]]]`)},
+	}
+	for _, g := range invalid {
+		assert.Error(t, g.Validate(), string(g.Coordinates))
+	}
+}
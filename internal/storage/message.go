@@ -5,43 +5,117 @@ import (
 	"fmt"
 	"strings"
 	"time"
+	"unicode"
 
 	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"gorm.io/gorm"
+)
+
+const (
+	ReviewPending  = "pending"
+	ReviewApproved = "approved"
+	ReviewRejected = "rejected"
 )
 
 type Message struct {
-	ID          int `gorm:"primaryKey"`
-	CreatedAt   time.Time
-	UpdatedAt   time.Time
-	TickerID    int `gorm:"index"`
-	Text        string
-	Attachments []Attachment
-	Telegram    TelegramMeta    `gorm:"serializer:json"`
-	Mastodon    MastodonMeta    `gorm:"serializer:json"`
-	Bluesky     BlueskyMeta     `gorm:"serializer:json"`
-	SignalGroup SignalGroupMeta `gorm:"serializer:json"`
+	ID        int `gorm:"primaryKey"`
+	CreatedAt time.Time
+	UpdatedAt time.Time
+	// DeletedAt keeps a deleted message around without its content, so the
+	// outbox still finds what the bridges stored on it to delete it there.
+	DeletedAt gorm.DeletedAt `gorm:"index"`
+	TickerID  int            `gorm:"index"`
+	PublishAt *time.Time     `gorm:"index"`
+	Draft     bool           `gorm:"default:false;index"`
+	Pinned    bool           `gorm:"default:false;index"`
+	// Review is the state of the editorial review for messages from
+	// contributors, and empty for all other messages.
+	Review        string `gorm:"index"`
+	ReviewComment string
+	Text          string
+	Geometry      *Geometry `gorm:"serializer:json"`
+	Attachments   []Attachment
+	Tags          []MessageTag
+	Deliveries    []OutboxJob
+	Telegram      TelegramMeta    `gorm:"serializer:json"`
+	Mastodon      MastodonMeta    `gorm:"serializer:json"`
+	Bluesky       BlueskyMeta     `gorm:"serializer:json"`
+	SignalGroup   SignalGroupMeta `gorm:"serializer:json"`
+	Matrix        MatrixMeta      `gorm:"serializer:json"`
+	Webhook       WebhookMeta     `gorm:"serializer:json"`
+	// Bridges are the names of the bridges the message is sent to. Without a
+	// selection the message is sent to all bridges of the ticker.
+	Bridges []string `gorm:"serializer:json"`
 }
 
 func NewMessage() Message {
 	return Message{}
 }
 
+// IsScheduled reports whether the message is waiting to be published by the
+// scheduler. Scheduled messages are hidden from the timeline and the bridges.
+func (m *Message) IsScheduled() bool {
+	return m.PublishAt != nil
+}
+
+// IsPendingReview reports whether the message waits for an editor to approve
+// or reject it.
+func (m *Message) IsPendingReview() bool {
+	return m.Review == ReviewPending
+}
+
+// SendsTo reports whether the message is sent to the bridge with the name.
+func (m *Message) SendsTo(bridge string) bool {
+	if m.Bridges == nil {
+		return true
+	}
+
+	for _, name := range m.Bridges {
+		if name == bridge {
+			return true
+		}
+	}
+
+	return false
+}
+
+// IsPublished reports whether the message is visible to readers, i.e. neither
+// a draft nor scheduled for later.
+func (m *Message) IsPublished() bool {
+	return !m.Draft && !m.IsScheduled()
+}
+
+// AsMap returns the columns SaveMessage writes. What the bridges stored on the
+// message is left out, it is only written by SaveMessageBridgeMeta, so saving
+// a message loaded before the outbox delivered it keeps what the outbox saved.
 func (m *Message) AsMap() map[string]interface{} {
-	telegram, _ := json.Marshal(m.Telegram)
-	mastodon, _ := json.Marshal(m.Mastodon)
-	bluesky, _ := json.Marshal(m.Bluesky)
-	signalGroup, _ := json.Marshal(m.SignalGroup)
+	// A message without geometry is stored as NULL rather than "null", so
+	// WithGeometry can tell them apart.
+	var geometry any
+	if m.Geometry != nil {
+		g, _ := json.Marshal(m.Geometry)
+		geometry = string(g)
+	}
+
+	var bridges any
+	if m.Bridges != nil {
+		b, _ := json.Marshal(m.Bridges)
+		bridges = string(b)
+	}
 
 	return map[string]interface{}{
-		"id":           m.ID,
-		"created_at":   m.CreatedAt,
-		"updated_at":   m.UpdatedAt,
-		"ticker_id":    m.TickerID,
-		"text":         m.Text,
-		"telegram":     telegram,
-		"mastodon":     mastodon,
-		"bluesky":      bluesky,
-		"signal_group": signalGroup,
+		"id":             m.ID,
+		"created_at":     m.CreatedAt,
+		"updated_at":     m.UpdatedAt,
+		"ticker_id":      m.TickerID,
+		"publish_at":     m.PublishAt,
+		"draft":          m.Draft,
+		"pinned":         m.Pinned,
+		"review":         m.Review,
+		"review_comment": m.ReviewComment,
+		"text":           m.Text,
+		"geometry":       geometry,
+		"bridges":        bridges,
 	}
 }
 
@@ -53,18 +127,141 @@ type MastodonMeta struct {
 	ID  string
 	URI string
 	URL string
+	// Replies are the IDs of the statuses continuing the thread of a message
+	// which was too long for a single status.
+	Replies []string `json:",omitempty"`
 }
 
 type BlueskyMeta struct {
 	Handle string
 	Uri    string
 	Cid    string
+	// Replies are the posts continuing the thread of a message which was too
+	// long for a single post.
+	Replies []BlueskyPost `json:",omitempty"`
+}
+
+type BlueskyPost struct {
+	Uri string
+	Cid string
 }
 
 type SignalGroupMeta struct {
 	Timestamp int
 }
 
+type MatrixMeta struct {
+	RoomID  string
+	EventID string
+	// Attachments are the events of the images, which are sent separately
+	// from the text in Matrix.
+	Attachments []string `json:",omitempty"`
+}
+
+type WebhookMeta struct {
+	// Delivered are the IDs of the webhooks which accepted the message, so a
+	// retry only goes to the others.
+	Delivered []int `json:",omitempty"`
+}
+
+// The actions recorded with a revision.
+const (
+	RevisionCreated     = "created"
+	RevisionEdited      = "edited"
+	RevisionPublished   = "published"
+	RevisionApproved    = "approved"
+	RevisionRejected    = "rejected"
+	RevisionResubmitted = "resubmitted"
+	RevisionPinned      = "pinned"
+	RevisionUnpinned    = "unpinned"
+	RevisionDeleted     = "deleted"
+)
+
+// MessageRevision is a snapshot of a message, written every time the message is
+// created or changed. Together the revisions of a message form its history,
+// including who made each change. Revisions outlive the message, so the
+// history stays available after it was deleted.
+type MessageRevision struct {
+	ID          int `gorm:"primaryKey"`
+	CreatedAt   time.Time
+	TickerID    int    `gorm:"index"`
+	MessageID   int    `gorm:"index"`
+	UserID      int    `gorm:"index"`
+	Action      string `gorm:"size:20"`
+	Text        string
+	Attachments []Attachment `gorm:"serializer:json"`
+}
+
+// NewMessageRevision returns the revision for the action of the user on the
+// message. The user is 0 for changes made by the API itself, such as the
+// scheduler, or by senders without an account.
+func NewMessageRevision(message Message, userID int, action string) MessageRevision {
+	return MessageRevision{
+		TickerID:    message.TickerID,
+		MessageID:   message.ID,
+		UserID:      userID,
+		Action:      action,
+		Text:        message.Text,
+		Attachments: message.Attachments,
+	}
+}
+
+// MessageTag is a tag of a message, taken from a hashtag in the text or set
+// explicitly. Names are stored in lower case and without the hash sign.
+type MessageTag struct {
+	ID        int    `gorm:"primaryKey"`
+	MessageID int    `gorm:"uniqueIndex:idx_message_tag"`
+	Name      string `gorm:"uniqueIndex:idx_message_tag;index;size:100"`
+}
+
+// TagCount is the number of published messages of a ticker with a tag.
+type TagCount struct {
+	Name  string
+	Count int
+}
+
+// NormalizeTags turns hashtags and tags entered by users into tag names. The
+// hash sign and surrounding punctuation are removed and the names are lower
+// cased. Empty names and duplicates are left out.
+func NormalizeTags(tags []string) []string {
+	names := make([]string, 0, len(tags))
+	seen := make(map[string]bool)
+	for _, tag := range tags {
+		name := strings.ToLower(strings.TrimFunc(tag, func(r rune) bool {
+			return !unicode.IsLetter(r) && !unicode.IsNumber(r) && r != '_'
+		}))
+		if name == "" || len(name) > 100 || seen[name] {
+			continue
+		}
+
+		seen[name] = true
+		names = append(names, name)
+	}
+
+	return names
+}
+
+// TagNames returns the names of the tags of the message.
+func (m *Message) TagNames() []string {
+	names := make([]string, 0, len(m.Tags))
+	for _, tag := range m.Tags {
+		names = append(names, tag.Name)
+	}
+
+	return names
+}
+
+// HasTag reports whether the message has the tag with the given name.
+func (m *Message) HasTag(name string) bool {
+	for _, tag := range m.Tags {
+		if tag.Name == name {
+			return true
+		}
+	}
+
+	return false
+}
+
 type Attachment struct {
 	ID          int `gorm:"primaryKey"`
 	CreatedAt   time.Time
@@ -120,3 +317,13 @@ func (m *Message) BlueskyURL() string {
 
 	return fmt.Sprintf("https://bsky.app/profile/%s/post/%s", m.Bluesky.Handle, parts[len(parts)-1])
 }
+
+// MatrixURL links to the event of the message via matrix.to, so it opens in the
+// client of the reader.
+func (m *Message) MatrixURL() string {
+	if m.Matrix.EventID == "" {
+		return ""
+	}
+
+	return fmt.Sprintf("https://matrix.to/#/%s/%s", m.Matrix.RoomID, m.Matrix.EventID)
+}
@@ -15,6 +15,31 @@ func TestAddAttachments(t *testing.T) {
 	assert.Equal(t, 1, len(message.Attachments))
 }
 
+func TestNormalizeTags(t *testing.T) {
+	assert.Equal(t, []string{"police", "water_station", "berlin"}, NormalizeTags([]string{"#Police", "#water_station.", "police", "Berlin", "#", ""}))
+	assert.Empty(t, NormalizeTags(nil))
+}
+
+func TestHasTag(t *testing.T) {
+	message := Message{Tags: []MessageTag{{Name: "police"}}}
+
+	assert.True(t, message.HasTag("police"))
+	assert.False(t, message.HasTag("traffic"))
+	assert.Equal(t, []string{"police"}, message.TagNames())
+}
+
+func TestSendsTo(t *testing.T) {
+	message := NewMessage()
+	assert.True(t, message.SendsTo("mastodon"))
+
+	message.Bridges = []string{"signalGroup"}
+	assert.True(t, message.SendsTo("signalGroup"))
+	assert.False(t, message.SendsTo("mastodon"))
+
+	message.Bridges = []string{}
+	assert.False(t, message.SendsTo("signalGroup"))
+}
+
 func TestTelegramURL(t *testing.T) {
 	message := NewMessage()
 
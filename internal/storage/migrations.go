@@ -1,27 +1,90 @@
 package storage
 
 import (
+	"github.com/systemli/ticker/internal/util"
 	"gorm.io/gorm"
 )
 
 // MigrateDB migrates the database
 func MigrateDB(db *gorm.DB) error {
+	if err := setupJoinTables(db); err != nil {
+		return err
+	}
+
+	hasMessageTags := db.Migrator().HasTable(&MessageTag{})
+	hasRevisionTickers := !db.Migrator().HasTable(&MessageRevision{}) || db.Migrator().HasColumn(&MessageRevision{}, "ticker_id")
+
 	if err := db.AutoMigrate(
 		&Ticker{},
 		&TickerMastodon{},
 		&TickerTelegram{},
 		&TickerBluesky{},
 		&TickerSignalGroup{},
+		&TickerMatrix{},
+		&TickerWebhook{},
+		&TickerSignalSender{},
+		&TickerActivityPub{},
+		&ActivityPubFollower{},
+		&TickerWebPush{},
+		&WebPushSubscription{},
+		&TickerEmail{},
+		&EmailSubscription{},
+		&EmailDigestEntry{},
+		&TickerMailbox{},
+		&TelegramLink{},
+		&RecoveryCode{},
+		&WebAuthnCredential{},
+		&WebAuthnSession{},
+		&OIDCSession{},
+		&APIToken{},
 		&TickerWebsite{},
 		&User{},
+		&TickerUser{},
 		&Setting{},
 		&Upload{},
 		&Message{},
 		&Attachment{},
+		&MessageRevision{},
+		&MessageTag{},
+		&OutboxJob{},
 	); err != nil {
 		return err
 	}
 
+	if !hasMessageTags {
+		if err := backfillMessageTags(db); err != nil {
+			log.WithError(err).Error("failed to create tags for the existing messages")
+		}
+	}
+
+	// Revisions are kept after the message is deleted, so they need to know
+	// their ticker themselves
+	if !hasRevisionTickers {
+		err := db.Exec("UPDATE message_revisions SET ticker_id = COALESCE((SELECT ticker_id FROM messages WHERE messages.id = message_revisions.message_id), 0)").Error
+		if err != nil {
+			return err
+		}
+	}
+
+	if err := setupSearchIndex(db); err != nil {
+		return err
+	}
+
+	// Move the contributors into the memberships of the tickers
+	if db.Migrator().HasTable("ticker_contributors") {
+		err := db.Exec(
+			"UPDATE ticker_users SET role = ? WHERE EXISTS (SELECT 1 FROM ticker_contributors WHERE ticker_contributors.ticker_id = ticker_users.ticker_id AND ticker_contributors.user_id = ticker_users.user_id)",
+			TickerRoleContributor,
+		).Error
+		if err != nil {
+			return err
+		}
+
+		if err := db.Migrator().DropTable("ticker_contributors"); err != nil {
+			log.WithError(err).Error("failed to drop the table ticker_contributors")
+		}
+	}
+
 	// Drop the column geo_information from Message if it exists
 	if db.Migrator().HasColumn(&Message{}, "geo_information") {
 		if err := db.Migrator().DropColumn(&Message{}, "geo_information"); err != nil {
@@ -36,3 +99,33 @@ func MigrateDB(db *gorm.DB) error {
 
 	return nil
 }
+
+// backfillMessageTags creates the tags from the hashtags in the texts of the
+// messages written before tags were stored.
+func backfillMessageTags(db *gorm.DB) error {
+	var messages []Message
+	return db.Select("id", "text").FindInBatches(&messages, 500, func(tx *gorm.DB, batch int) error {
+		tags := make([]MessageTag, 0)
+		for _, message := range messages {
+			for _, name := range NormalizeTags(util.ExtractHashtags(message.Text)) {
+				tags = append(tags, MessageTag{MessageID: message.ID, Name: name})
+			}
+		}
+
+		if len(tags) == 0 {
+			return nil
+		}
+
+		return db.Create(&tags).Error
+	}).Error
+}
+
+// setupJoinTables registers the models with attributes for many2many
+// associations. It has to run before the associations are used.
+func setupJoinTables(db *gorm.DB) error {
+	if err := db.SetupJoinTable(&Ticker{}, "Users", &TickerUser{}); err != nil {
+		return err
+	}
+
+	return db.SetupJoinTable(&User{}, "Tickers", &TickerUser{})
+}
@@ -23,11 +23,32 @@ func (s *MigrationTestSuite) SetupSuite() {
 		&TickerMastodon{},
 		&TickerBluesky{},
 		&TickerSignalGroup{},
+		&TickerMatrix{},
+		&TickerWebhook{},
+		&TickerSignalSender{},
+		&TickerActivityPub{},
+		&ActivityPubFollower{},
+		&TickerWebPush{},
+		&WebPushSubscription{},
+		&TickerEmail{},
+		&EmailSubscription{},
+		&EmailDigestEntry{},
+		&TickerMailbox{},
+		&TelegramLink{},
+		&RecoveryCode{},
+		&WebAuthnCredential{},
+		&WebAuthnSession{},
+		&OIDCSession{},
+		&APIToken{},
 		&TickerWebsite{},
 		&User{},
+		&TickerUser{},
 		&Message{},
 		&Upload{},
 		&Attachment{},
+		&MessageRevision{},
+		&MessageTag{},
+		&OutboxJob{},
 		&Setting{},
 	)
 	s.NoError(err)
@@ -40,6 +61,54 @@ func (s *MigrationTestSuite) TestMigrateDB() {
 		err := MigrateDB(s.db)
 		s.NoError(err)
 	})
+
+	s.Run("with contributors", func() {
+		s.NoError(s.db.Exec("CREATE TABLE ticker_contributors (id integer PRIMARY KEY, created_at datetime, ticker_id integer, user_id integer)").Error)
+		s.NoError(s.db.Create(&TickerUser{TickerID: 1, UserID: 1}).Error)
+		s.NoError(s.db.Create(&TickerUser{TickerID: 1, UserID: 2}).Error)
+		s.NoError(s.db.Exec("INSERT INTO ticker_contributors (ticker_id, user_id) VALUES (1, 2)").Error)
+
+		err := MigrateDB(s.db)
+		s.NoError(err)
+		s.False(s.db.Migrator().HasTable("ticker_contributors"))
+
+		var tickerUsers []TickerUser
+		s.NoError(s.db.Order("user_id").Find(&tickerUsers).Error)
+		s.Len(tickerUsers, 2)
+		s.Equal(TickerRoleEditor, tickerUsers[0].Role)
+		s.Equal(TickerRoleContributor, tickerUsers[1].Role)
+	})
+
+	s.Run("without message tags", func() {
+		s.NoError(s.db.Migrator().DropTable(&MessageTag{}))
+		s.NoError(s.db.Create(&Message{TickerID: 1, Text: "Kettle at the #station, #Police everywhere"}).Error)
+		s.NoError(s.db.Create(&Message{TickerID: 1, Text: "No tags"}).Error)
+
+		err := MigrateDB(s.db)
+		s.NoError(err)
+
+		var tags []MessageTag
+		s.NoError(s.db.Order("name").Find(&tags).Error)
+		s.Len(tags, 2)
+		s.Equal("police", tags[0].Name)
+		s.Equal("station", tags[1].Name)
+	})
+
+	s.Run("with revisions without ticker", func() {
+		s.NoError(s.db.Migrator().DropColumn(&MessageRevision{}, "ticker_id"))
+		message := Message{TickerID: 3, Text: "text"}
+		s.NoError(s.db.Create(&message).Error)
+		s.NoError(s.db.Exec("INSERT INTO message_revisions (message_id, user_id, text) VALUES (?, 1, 'text'), (999, 1, 'gone')", message.ID).Error)
+
+		err := MigrateDB(s.db)
+		s.NoError(err)
+
+		var revisions []MessageRevision
+		s.NoError(s.db.Order("message_id").Find(&revisions).Error)
+		s.Len(revisions, 2)
+		s.Equal(3, revisions[0].TickerID)
+		s.Zero(revisions[1].TickerID)
+	})
 }
 
 func TestMigrationTestSuite(t *testing.T) {
@@ -5,6 +5,8 @@
 package storage
 
 import (
+	"time"
+
 	mock "github.com/stretchr/testify/mock"
 	"github.com/systemli/ticker/internal/api/pagination"
 	"gorm.io/gorm"
@@ -37,6 +39,66 @@ func (_m *MockStorage) EXPECT() *MockStorage_Expecter {
 	return &MockStorage_Expecter{mock: &_m.Mock}
 }
 
+// AddTOTPAttempt provides a mock function for the type MockStorage
+func (_mock *MockStorage) AddTOTPAttempt(user *User) (int, error) {
+	ret := _mock.Called(user)
+
+	if len(ret) == 0 {
+		panic("no return value specified for AddTOTPAttempt")
+	}
+
+	var r0 int
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(*User) (int, error)); ok {
+		return returnFunc(user)
+	}
+	if returnFunc, ok := ret.Get(0).(func(*User) int); ok {
+		r0 = returnFunc(user)
+	} else {
+		r0 = ret.Get(0).(int)
+	}
+	if returnFunc, ok := ret.Get(1).(func(*User) error); ok {
+		r1 = returnFunc(user)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockStorage_AddTOTPAttempt_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'AddTOTPAttempt'
+type MockStorage_AddTOTPAttempt_Call struct {
+	*mock.Call
+}
+
+// AddTOTPAttempt is a helper method to define mock.On call
+//   - user *User
+func (_e *MockStorage_Expecter) AddTOTPAttempt(user interface{}) *MockStorage_AddTOTPAttempt_Call {
+	return &MockStorage_AddTOTPAttempt_Call{Call: _e.mock.On("AddTOTPAttempt", user)}
+}
+
+func (_c *MockStorage_AddTOTPAttempt_Call) Run(run func(user *User)) *MockStorage_AddTOTPAttempt_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 *User
+		if args[0] != nil {
+			arg0 = args[0].(*User)
+		}
+		run(
+			arg0,
+		)
+	})
+	return _c
+}
+
+func (_c *MockStorage_AddTOTPAttempt_Call) Return(n int, err error) *MockStorage_AddTOTPAttempt_Call {
+	_c.Call.Return(n, err)
+	return _c
+}
+
+func (_c *MockStorage_AddTOTPAttempt_Call) RunAndReturn(run func(user *User) (int, error)) *MockStorage_AddTOTPAttempt_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // AddTickerUser provides a mock function for the type MockStorage
 func (_mock *MockStorage) AddTickerUser(ticker *Ticker, user *User) error {
 	ret := _mock.Called(ticker, user)
@@ -94,141 +156,188 @@ func (_c *MockStorage_AddTickerUser_Call) RunAndReturn(run func(ticker *Ticker,
 	return _c
 }
 
-// DeleteBluesky provides a mock function for the type MockStorage
-func (_mock *MockStorage) DeleteBluesky(ticker *Ticker) error {
-	ret := _mock.Called(ticker)
+// ClaimEmailConfirmation provides a mock function for the type MockStorage
+func (_mock *MockStorage) ClaimEmailConfirmation(subscription *EmailSubscription, interval time.Duration) (bool, error) {
+	ret := _mock.Called(subscription, interval)
 
 	if len(ret) == 0 {
-		panic("no return value specified for DeleteBluesky")
+		panic("no return value specified for ClaimEmailConfirmation")
 	}
 
-	var r0 error
-	if returnFunc, ok := ret.Get(0).(func(*Ticker) error); ok {
-		r0 = returnFunc(ticker)
+	var r0 bool
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(*EmailSubscription, time.Duration) (bool, error)); ok {
+		return returnFunc(subscription, interval)
+	}
+	if returnFunc, ok := ret.Get(0).(func(*EmailSubscription, time.Duration) bool); ok {
+		r0 = returnFunc(subscription, interval)
 	} else {
-		r0 = ret.Error(0)
+		r0 = ret.Get(0).(bool)
 	}
-	return r0
+	if returnFunc, ok := ret.Get(1).(func(*EmailSubscription, time.Duration) error); ok {
+		r1 = returnFunc(subscription, interval)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
 }
 
-// MockStorage_DeleteBluesky_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'DeleteBluesky'
-type MockStorage_DeleteBluesky_Call struct {
+// MockStorage_ClaimEmailConfirmation_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ClaimEmailConfirmation'
+type MockStorage_ClaimEmailConfirmation_Call struct {
 	*mock.Call
 }
 
-// DeleteBluesky is a helper method to define mock.On call
-//   - ticker *Ticker
-func (_e *MockStorage_Expecter) DeleteBluesky(ticker interface{}) *MockStorage_DeleteBluesky_Call {
-	return &MockStorage_DeleteBluesky_Call{Call: _e.mock.On("DeleteBluesky", ticker)}
+// ClaimEmailConfirmation is a helper method to define mock.On call
+//   - subscription *EmailSubscription
+//   - interval time.Duration
+func (_e *MockStorage_Expecter) ClaimEmailConfirmation(subscription interface{}, interval interface{}) *MockStorage_ClaimEmailConfirmation_Call {
+	return &MockStorage_ClaimEmailConfirmation_Call{Call: _e.mock.On("ClaimEmailConfirmation", subscription, interval)}
 }
 
-func (_c *MockStorage_DeleteBluesky_Call) Run(run func(ticker *Ticker)) *MockStorage_DeleteBluesky_Call {
+func (_c *MockStorage_ClaimEmailConfirmation_Call) Run(run func(subscription *EmailSubscription, interval time.Duration)) *MockStorage_ClaimEmailConfirmation_Call {
 	_c.Call.Run(func(args mock.Arguments) {
-		var arg0 *Ticker
+		var arg0 *EmailSubscription
 		if args[0] != nil {
-			arg0 = args[0].(*Ticker)
+			arg0 = args[0].(*EmailSubscription)
+		}
+		var arg1 time.Duration
+		if args[1] != nil {
+			arg1 = args[1].(time.Duration)
 		}
 		run(
 			arg0,
+			arg1,
 		)
 	})
 	return _c
 }
 
-func (_c *MockStorage_DeleteBluesky_Call) Return(err error) *MockStorage_DeleteBluesky_Call {
-	_c.Call.Return(err)
+func (_c *MockStorage_ClaimEmailConfirmation_Call) Return(b bool, err error) *MockStorage_ClaimEmailConfirmation_Call {
+	_c.Call.Return(b, err)
 	return _c
 }
 
-func (_c *MockStorage_DeleteBluesky_Call) RunAndReturn(run func(ticker *Ticker) error) *MockStorage_DeleteBluesky_Call {
+func (_c *MockStorage_ClaimEmailConfirmation_Call) RunAndReturn(run func(subscription *EmailSubscription, interval time.Duration) (bool, error)) *MockStorage_ClaimEmailConfirmation_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
-// DeleteIntegrations provides a mock function for the type MockStorage
-func (_mock *MockStorage) DeleteIntegrations(ticker *Ticker) error {
-	ret := _mock.Called(ticker)
+// ClaimOutboxJobs provides a mock function for the type MockStorage
+func (_mock *MockStorage) ClaimOutboxJobs(now time.Time, limit int, lease time.Duration) ([]OutboxJob, error) {
+	ret := _mock.Called(now, limit, lease)
 
 	if len(ret) == 0 {
-		panic("no return value specified for DeleteIntegrations")
+		panic("no return value specified for ClaimOutboxJobs")
 	}
 
-	var r0 error
-	if returnFunc, ok := ret.Get(0).(func(*Ticker) error); ok {
-		r0 = returnFunc(ticker)
+	var r0 []OutboxJob
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(time.Time, int, time.Duration) ([]OutboxJob, error)); ok {
+		return returnFunc(now, limit, lease)
+	}
+	if returnFunc, ok := ret.Get(0).(func(time.Time, int, time.Duration) []OutboxJob); ok {
+		r0 = returnFunc(now, limit, lease)
 	} else {
-		r0 = ret.Error(0)
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]OutboxJob)
+		}
 	}
-	return r0
+	if returnFunc, ok := ret.Get(1).(func(time.Time, int, time.Duration) error); ok {
+		r1 = returnFunc(now, limit, lease)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
 }
 
-// MockStorage_DeleteIntegrations_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'DeleteIntegrations'
-type MockStorage_DeleteIntegrations_Call struct {
+// MockStorage_ClaimOutboxJobs_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ClaimOutboxJobs'
+type MockStorage_ClaimOutboxJobs_Call struct {
 	*mock.Call
 }
 
-// DeleteIntegrations is a helper method to define mock.On call
-//   - ticker *Ticker
-func (_e *MockStorage_Expecter) DeleteIntegrations(ticker interface{}) *MockStorage_DeleteIntegrations_Call {
-	return &MockStorage_DeleteIntegrations_Call{Call: _e.mock.On("DeleteIntegrations", ticker)}
+// ClaimOutboxJobs is a helper method to define mock.On call
+//   - now time.Time
+//   - limit int
+//   - lease time.Duration
+func (_e *MockStorage_Expecter) ClaimOutboxJobs(now interface{}, limit interface{}, lease interface{}) *MockStorage_ClaimOutboxJobs_Call {
+	return &MockStorage_ClaimOutboxJobs_Call{Call: _e.mock.On("ClaimOutboxJobs", now, limit, lease)}
 }
 
-func (_c *MockStorage_DeleteIntegrations_Call) Run(run func(ticker *Ticker)) *MockStorage_DeleteIntegrations_Call {
+func (_c *MockStorage_ClaimOutboxJobs_Call) Run(run func(now time.Time, limit int, lease time.Duration)) *MockStorage_ClaimOutboxJobs_Call {
 	_c.Call.Run(func(args mock.Arguments) {
-		var arg0 *Ticker
+		var arg0 time.Time
 		if args[0] != nil {
-			arg0 = args[0].(*Ticker)
+			arg0 = args[0].(time.Time)
+		}
+		var arg1 int
+		if args[1] != nil {
+			arg1 = args[1].(int)
+		}
+		var arg2 time.Duration
+		if args[2] != nil {
+			arg2 = args[2].(time.Duration)
 		}
 		run(
 			arg0,
+			arg1,
+			arg2,
 		)
 	})
 	return _c
 }
 
-func (_c *MockStorage_DeleteIntegrations_Call) Return(err error) *MockStorage_DeleteIntegrations_Call {
-	_c.Call.Return(err)
+func (_c *MockStorage_ClaimOutboxJobs_Call) Return(outboxJobs []OutboxJob, err error) *MockStorage_ClaimOutboxJobs_Call {
+	_c.Call.Return(outboxJobs, err)
 	return _c
 }
 
-func (_c *MockStorage_DeleteIntegrations_Call) RunAndReturn(run func(ticker *Ticker) error) *MockStorage_DeleteIntegrations_Call {
+func (_c *MockStorage_ClaimOutboxJobs_Call) RunAndReturn(run func(now time.Time, limit int, lease time.Duration) ([]OutboxJob, error)) *MockStorage_ClaimOutboxJobs_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
-// DeleteMastodon provides a mock function for the type MockStorage
-func (_mock *MockStorage) DeleteMastodon(ticker *Ticker) error {
-	ret := _mock.Called(ticker)
+// CountRecoveryCodes provides a mock function for the type MockStorage
+func (_mock *MockStorage) CountRecoveryCodes(user User) (int64, error) {
+	ret := _mock.Called(user)
 
 	if len(ret) == 0 {
-		panic("no return value specified for DeleteMastodon")
+		panic("no return value specified for CountRecoveryCodes")
 	}
 
-	var r0 error
-	if returnFunc, ok := ret.Get(0).(func(*Ticker) error); ok {
-		r0 = returnFunc(ticker)
+	var r0 int64
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(User) (int64, error)); ok {
+		return returnFunc(user)
+	}
+	if returnFunc, ok := ret.Get(0).(func(User) int64); ok {
+		r0 = returnFunc(user)
 	} else {
-		r0 = ret.Error(0)
+		r0 = ret.Get(0).(int64)
 	}
-	return r0
+	if returnFunc, ok := ret.Get(1).(func(User) error); ok {
+		r1 = returnFunc(user)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
 }
 
-// MockStorage_DeleteMastodon_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'DeleteMastodon'
-type MockStorage_DeleteMastodon_Call struct {
+// MockStorage_CountRecoveryCodes_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'CountRecoveryCodes'
+type MockStorage_CountRecoveryCodes_Call struct {
 	*mock.Call
 }
 
-// DeleteMastodon is a helper method to define mock.On call
-//   - ticker *Ticker
-func (_e *MockStorage_Expecter) DeleteMastodon(ticker interface{}) *MockStorage_DeleteMastodon_Call {
-	return &MockStorage_DeleteMastodon_Call{Call: _e.mock.On("DeleteMastodon", ticker)}
+// CountRecoveryCodes is a helper method to define mock.On call
+//   - user User
+func (_e *MockStorage_Expecter) CountRecoveryCodes(user interface{}) *MockStorage_CountRecoveryCodes_Call {
+	return &MockStorage_CountRecoveryCodes_Call{Call: _e.mock.On("CountRecoveryCodes", user)}
 }
 
-func (_c *MockStorage_DeleteMastodon_Call) Run(run func(ticker *Ticker)) *MockStorage_DeleteMastodon_Call {
+func (_c *MockStorage_CountRecoveryCodes_Call) Run(run func(user User)) *MockStorage_CountRecoveryCodes_Call {
 	_c.Call.Run(func(args mock.Arguments) {
-		var arg0 *Ticker
+		var arg0 User
 		if args[0] != nil {
-			arg0 = args[0].(*Ticker)
+			arg0 = args[0].(User)
 		}
 		run(
 			arg0,
@@ -237,49 +346,58 @@ func (_c *MockStorage_DeleteMastodon_Call) Run(run func(ticker *Ticker)) *MockSt
 	return _c
 }
 
-func (_c *MockStorage_DeleteMastodon_Call) Return(err error) *MockStorage_DeleteMastodon_Call {
-	_c.Call.Return(err)
+func (_c *MockStorage_CountRecoveryCodes_Call) Return(n int64, err error) *MockStorage_CountRecoveryCodes_Call {
+	_c.Call.Return(n, err)
 	return _c
 }
 
-func (_c *MockStorage_DeleteMastodon_Call) RunAndReturn(run func(ticker *Ticker) error) *MockStorage_DeleteMastodon_Call {
+func (_c *MockStorage_CountRecoveryCodes_Call) RunAndReturn(run func(user User) (int64, error)) *MockStorage_CountRecoveryCodes_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
-// DeleteMessage provides a mock function for the type MockStorage
-func (_mock *MockStorage) DeleteMessage(message Message) error {
-	ret := _mock.Called(message)
+// CountWebAuthnCredentials provides a mock function for the type MockStorage
+func (_mock *MockStorage) CountWebAuthnCredentials(user User) (int64, error) {
+	ret := _mock.Called(user)
 
 	if len(ret) == 0 {
-		panic("no return value specified for DeleteMessage")
+		panic("no return value specified for CountWebAuthnCredentials")
 	}
 
-	var r0 error
-	if returnFunc, ok := ret.Get(0).(func(Message) error); ok {
-		r0 = returnFunc(message)
+	var r0 int64
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(User) (int64, error)); ok {
+		return returnFunc(user)
+	}
+	if returnFunc, ok := ret.Get(0).(func(User) int64); ok {
+		r0 = returnFunc(user)
 	} else {
-		r0 = ret.Error(0)
+		r0 = ret.Get(0).(int64)
 	}
-	return r0
+	if returnFunc, ok := ret.Get(1).(func(User) error); ok {
+		r1 = returnFunc(user)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
 }
 
-// MockStorage_DeleteMessage_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'DeleteMessage'
-type MockStorage_DeleteMessage_Call struct {
+// MockStorage_CountWebAuthnCredentials_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'CountWebAuthnCredentials'
+type MockStorage_CountWebAuthnCredentials_Call struct {
 	*mock.Call
 }
 
-// DeleteMessage is a helper method to define mock.On call
-//   - message Message
-func (_e *MockStorage_Expecter) DeleteMessage(message interface{}) *MockStorage_DeleteMessage_Call {
-	return &MockStorage_DeleteMessage_Call{Call: _e.mock.On("DeleteMessage", message)}
+// CountWebAuthnCredentials is a helper method to define mock.On call
+//   - user User
+func (_e *MockStorage_Expecter) CountWebAuthnCredentials(user interface{}) *MockStorage_CountWebAuthnCredentials_Call {
+	return &MockStorage_CountWebAuthnCredentials_Call{Call: _e.mock.On("CountWebAuthnCredentials", user)}
 }
 
-func (_c *MockStorage_DeleteMessage_Call) Run(run func(message Message)) *MockStorage_DeleteMessage_Call {
+func (_c *MockStorage_CountWebAuthnCredentials_Call) Run(run func(user User)) *MockStorage_CountWebAuthnCredentials_Call {
 	_c.Call.Run(func(args mock.Arguments) {
-		var arg0 Message
+		var arg0 User
 		if args[0] != nil {
-			arg0 = args[0].(Message)
+			arg0 = args[0].(User)
 		}
 		run(
 			arg0,
@@ -288,49 +406,58 @@ func (_c *MockStorage_DeleteMessage_Call) Run(run func(message Message)) *MockSt
 	return _c
 }
 
-func (_c *MockStorage_DeleteMessage_Call) Return(err error) *MockStorage_DeleteMessage_Call {
-	_c.Call.Return(err)
+func (_c *MockStorage_CountWebAuthnCredentials_Call) Return(n int64, err error) *MockStorage_CountWebAuthnCredentials_Call {
+	_c.Call.Return(n, err)
 	return _c
 }
 
-func (_c *MockStorage_DeleteMessage_Call) RunAndReturn(run func(message Message) error) *MockStorage_DeleteMessage_Call {
+func (_c *MockStorage_CountWebAuthnCredentials_Call) RunAndReturn(run func(user User) (int64, error)) *MockStorage_CountWebAuthnCredentials_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
-// DeleteMessages provides a mock function for the type MockStorage
-func (_mock *MockStorage) DeleteMessages(ticker *Ticker) error {
+// CountWebPushSubscriptions provides a mock function for the type MockStorage
+func (_mock *MockStorage) CountWebPushSubscriptions(ticker Ticker) (int64, error) {
 	ret := _mock.Called(ticker)
 
 	if len(ret) == 0 {
-		panic("no return value specified for DeleteMessages")
+		panic("no return value specified for CountWebPushSubscriptions")
 	}
 
-	var r0 error
-	if returnFunc, ok := ret.Get(0).(func(*Ticker) error); ok {
+	var r0 int64
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(Ticker) (int64, error)); ok {
+		return returnFunc(ticker)
+	}
+	if returnFunc, ok := ret.Get(0).(func(Ticker) int64); ok {
 		r0 = returnFunc(ticker)
 	} else {
-		r0 = ret.Error(0)
+		r0 = ret.Get(0).(int64)
 	}
-	return r0
+	if returnFunc, ok := ret.Get(1).(func(Ticker) error); ok {
+		r1 = returnFunc(ticker)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
 }
 
-// MockStorage_DeleteMessages_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'DeleteMessages'
-type MockStorage_DeleteMessages_Call struct {
+// MockStorage_CountWebPushSubscriptions_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'CountWebPushSubscriptions'
+type MockStorage_CountWebPushSubscriptions_Call struct {
 	*mock.Call
 }
 
-// DeleteMessages is a helper method to define mock.On call
-//   - ticker *Ticker
-func (_e *MockStorage_Expecter) DeleteMessages(ticker interface{}) *MockStorage_DeleteMessages_Call {
-	return &MockStorage_DeleteMessages_Call{Call: _e.mock.On("DeleteMessages", ticker)}
+// CountWebPushSubscriptions is a helper method to define mock.On call
+//   - ticker Ticker
+func (_e *MockStorage_Expecter) CountWebPushSubscriptions(ticker interface{}) *MockStorage_CountWebPushSubscriptions_Call {
+	return &MockStorage_CountWebPushSubscriptions_Call{Call: _e.mock.On("CountWebPushSubscriptions", ticker)}
 }
 
-func (_c *MockStorage_DeleteMessages_Call) Run(run func(ticker *Ticker)) *MockStorage_DeleteMessages_Call {
+func (_c *MockStorage_CountWebPushSubscriptions_Call) Run(run func(ticker Ticker)) *MockStorage_CountWebPushSubscriptions_Call {
 	_c.Call.Run(func(args mock.Arguments) {
-		var arg0 *Ticker
+		var arg0 Ticker
 		if args[0] != nil {
-			arg0 = args[0].(*Ticker)
+			arg0 = args[0].(Ticker)
 		}
 		run(
 			arg0,
@@ -339,151 +466,163 @@ func (_c *MockStorage_DeleteMessages_Call) Run(run func(ticker *Ticker)) *MockSt
 	return _c
 }
 
-func (_c *MockStorage_DeleteMessages_Call) Return(err error) *MockStorage_DeleteMessages_Call {
-	_c.Call.Return(err)
+func (_c *MockStorage_CountWebPushSubscriptions_Call) Return(n int64, err error) *MockStorage_CountWebPushSubscriptions_Call {
+	_c.Call.Return(n, err)
 	return _c
 }
 
-func (_c *MockStorage_DeleteMessages_Call) RunAndReturn(run func(ticker *Ticker) error) *MockStorage_DeleteMessages_Call {
+func (_c *MockStorage_CountWebPushSubscriptions_Call) RunAndReturn(run func(ticker Ticker) (int64, error)) *MockStorage_CountWebPushSubscriptions_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
-// DeleteSignalGroup provides a mock function for the type MockStorage
-func (_mock *MockStorage) DeleteSignalGroup(ticker *Ticker) error {
-	ret := _mock.Called(ticker)
+// CreateOutboxEditJobs provides a mock function for the type MockStorage
+func (_mock *MockStorage) CreateOutboxEditJobs(message Message, bridges []string) error {
+	ret := _mock.Called(message, bridges)
 
 	if len(ret) == 0 {
-		panic("no return value specified for DeleteSignalGroup")
+		panic("no return value specified for CreateOutboxEditJobs")
 	}
 
 	var r0 error
-	if returnFunc, ok := ret.Get(0).(func(*Ticker) error); ok {
-		r0 = returnFunc(ticker)
+	if returnFunc, ok := ret.Get(0).(func(Message, []string) error); ok {
+		r0 = returnFunc(message, bridges)
 	} else {
 		r0 = ret.Error(0)
 	}
 	return r0
 }
 
-// MockStorage_DeleteSignalGroup_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'DeleteSignalGroup'
-type MockStorage_DeleteSignalGroup_Call struct {
+// MockStorage_CreateOutboxEditJobs_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'CreateOutboxEditJobs'
+type MockStorage_CreateOutboxEditJobs_Call struct {
 	*mock.Call
 }
 
-// DeleteSignalGroup is a helper method to define mock.On call
-//   - ticker *Ticker
-func (_e *MockStorage_Expecter) DeleteSignalGroup(ticker interface{}) *MockStorage_DeleteSignalGroup_Call {
-	return &MockStorage_DeleteSignalGroup_Call{Call: _e.mock.On("DeleteSignalGroup", ticker)}
+// CreateOutboxEditJobs is a helper method to define mock.On call
+//   - message Message
+//   - bridges []string
+func (_e *MockStorage_Expecter) CreateOutboxEditJobs(message interface{}, bridges interface{}) *MockStorage_CreateOutboxEditJobs_Call {
+	return &MockStorage_CreateOutboxEditJobs_Call{Call: _e.mock.On("CreateOutboxEditJobs", message, bridges)}
 }
 
-func (_c *MockStorage_DeleteSignalGroup_Call) Run(run func(ticker *Ticker)) *MockStorage_DeleteSignalGroup_Call {
+func (_c *MockStorage_CreateOutboxEditJobs_Call) Run(run func(message Message, bridges []string)) *MockStorage_CreateOutboxEditJobs_Call {
 	_c.Call.Run(func(args mock.Arguments) {
-		var arg0 *Ticker
+		var arg0 Message
 		if args[0] != nil {
-			arg0 = args[0].(*Ticker)
+			arg0 = args[0].(Message)
+		}
+		var arg1 []string
+		if args[1] != nil {
+			arg1 = args[1].([]string)
 		}
 		run(
 			arg0,
+			arg1,
 		)
 	})
 	return _c
 }
 
-func (_c *MockStorage_DeleteSignalGroup_Call) Return(err error) *MockStorage_DeleteSignalGroup_Call {
+func (_c *MockStorage_CreateOutboxEditJobs_Call) Return(err error) *MockStorage_CreateOutboxEditJobs_Call {
 	_c.Call.Return(err)
 	return _c
 }
 
-func (_c *MockStorage_DeleteSignalGroup_Call) RunAndReturn(run func(ticker *Ticker) error) *MockStorage_DeleteSignalGroup_Call {
+func (_c *MockStorage_CreateOutboxEditJobs_Call) RunAndReturn(run func(message Message, bridges []string) error) *MockStorage_CreateOutboxEditJobs_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
-// DeleteTelegram provides a mock function for the type MockStorage
-func (_mock *MockStorage) DeleteTelegram(ticker *Ticker) error {
-	ret := _mock.Called(ticker)
+// CreateOutboxJobs provides a mock function for the type MockStorage
+func (_mock *MockStorage) CreateOutboxJobs(message Message, bridges []string) error {
+	ret := _mock.Called(message, bridges)
 
 	if len(ret) == 0 {
-		panic("no return value specified for DeleteTelegram")
+		panic("no return value specified for CreateOutboxJobs")
 	}
 
 	var r0 error
-	if returnFunc, ok := ret.Get(0).(func(*Ticker) error); ok {
-		r0 = returnFunc(ticker)
+	if returnFunc, ok := ret.Get(0).(func(Message, []string) error); ok {
+		r0 = returnFunc(message, bridges)
 	} else {
 		r0 = ret.Error(0)
 	}
 	return r0
 }
 
-// MockStorage_DeleteTelegram_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'DeleteTelegram'
-type MockStorage_DeleteTelegram_Call struct {
+// MockStorage_CreateOutboxJobs_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'CreateOutboxJobs'
+type MockStorage_CreateOutboxJobs_Call struct {
 	*mock.Call
 }
 
-// DeleteTelegram is a helper method to define mock.On call
-//   - ticker *Ticker
-func (_e *MockStorage_Expecter) DeleteTelegram(ticker interface{}) *MockStorage_DeleteTelegram_Call {
-	return &MockStorage_DeleteTelegram_Call{Call: _e.mock.On("DeleteTelegram", ticker)}
+// CreateOutboxJobs is a helper method to define mock.On call
+//   - message Message
+//   - bridges []string
+func (_e *MockStorage_Expecter) CreateOutboxJobs(message interface{}, bridges interface{}) *MockStorage_CreateOutboxJobs_Call {
+	return &MockStorage_CreateOutboxJobs_Call{Call: _e.mock.On("CreateOutboxJobs", message, bridges)}
 }
 
-func (_c *MockStorage_DeleteTelegram_Call) Run(run func(ticker *Ticker)) *MockStorage_DeleteTelegram_Call {
+func (_c *MockStorage_CreateOutboxJobs_Call) Run(run func(message Message, bridges []string)) *MockStorage_CreateOutboxJobs_Call {
 	_c.Call.Run(func(args mock.Arguments) {
-		var arg0 *Ticker
+		var arg0 Message
 		if args[0] != nil {
-			arg0 = args[0].(*Ticker)
+			arg0 = args[0].(Message)
+		}
+		var arg1 []string
+		if args[1] != nil {
+			arg1 = args[1].([]string)
 		}
 		run(
 			arg0,
+			arg1,
 		)
 	})
 	return _c
 }
 
-func (_c *MockStorage_DeleteTelegram_Call) Return(err error) *MockStorage_DeleteTelegram_Call {
+func (_c *MockStorage_CreateOutboxJobs_Call) Return(err error) *MockStorage_CreateOutboxJobs_Call {
 	_c.Call.Return(err)
 	return _c
 }
 
-func (_c *MockStorage_DeleteTelegram_Call) RunAndReturn(run func(ticker *Ticker) error) *MockStorage_DeleteTelegram_Call {
+func (_c *MockStorage_CreateOutboxJobs_Call) RunAndReturn(run func(message Message, bridges []string) error) *MockStorage_CreateOutboxJobs_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
-// DeleteTicker provides a mock function for the type MockStorage
-func (_mock *MockStorage) DeleteTicker(ticker *Ticker) error {
-	ret := _mock.Called(ticker)
+// DeleteAPIToken provides a mock function for the type MockStorage
+func (_mock *MockStorage) DeleteAPIToken(token APIToken) error {
+	ret := _mock.Called(token)
 
 	if len(ret) == 0 {
-		panic("no return value specified for DeleteTicker")
+		panic("no return value specified for DeleteAPIToken")
 	}
 
 	var r0 error
-	if returnFunc, ok := ret.Get(0).(func(*Ticker) error); ok {
-		r0 = returnFunc(ticker)
+	if returnFunc, ok := ret.Get(0).(func(APIToken) error); ok {
+		r0 = returnFunc(token)
 	} else {
 		r0 = ret.Error(0)
 	}
 	return r0
 }
 
-// MockStorage_DeleteTicker_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'DeleteTicker'
-type MockStorage_DeleteTicker_Call struct {
+// MockStorage_DeleteAPIToken_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'DeleteAPIToken'
+type MockStorage_DeleteAPIToken_Call struct {
 	*mock.Call
 }
 
-// DeleteTicker is a helper method to define mock.On call
-//   - ticker *Ticker
-func (_e *MockStorage_Expecter) DeleteTicker(ticker interface{}) *MockStorage_DeleteTicker_Call {
-	return &MockStorage_DeleteTicker_Call{Call: _e.mock.On("DeleteTicker", ticker)}
+// DeleteAPIToken is a helper method to define mock.On call
+//   - token APIToken
+func (_e *MockStorage_Expecter) DeleteAPIToken(token interface{}) *MockStorage_DeleteAPIToken_Call {
+	return &MockStorage_DeleteAPIToken_Call{Call: _e.mock.On("DeleteAPIToken", token)}
 }
 
-func (_c *MockStorage_DeleteTicker_Call) Run(run func(ticker *Ticker)) *MockStorage_DeleteTicker_Call {
+func (_c *MockStorage_DeleteAPIToken_Call) Run(run func(token APIToken)) *MockStorage_DeleteAPIToken_Call {
 	_c.Call.Run(func(args mock.Arguments) {
-		var arg0 *Ticker
+		var arg0 APIToken
 		if args[0] != nil {
-			arg0 = args[0].(*Ticker)
+			arg0 = args[0].(APIToken)
 		}
 		run(
 			arg0,
@@ -492,54 +631,105 @@ func (_c *MockStorage_DeleteTicker_Call) Run(run func(ticker *Ticker)) *MockStor
 	return _c
 }
 
-func (_c *MockStorage_DeleteTicker_Call) Return(err error) *MockStorage_DeleteTicker_Call {
+func (_c *MockStorage_DeleteAPIToken_Call) Return(err error) *MockStorage_DeleteAPIToken_Call {
 	_c.Call.Return(err)
 	return _c
 }
 
-func (_c *MockStorage_DeleteTicker_Call) RunAndReturn(run func(ticker *Ticker) error) *MockStorage_DeleteTicker_Call {
+func (_c *MockStorage_DeleteAPIToken_Call) RunAndReturn(run func(token APIToken) error) *MockStorage_DeleteAPIToken_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
-// DeleteTickerUser provides a mock function for the type MockStorage
-func (_mock *MockStorage) DeleteTickerUser(ticker *Ticker, user *User) error {
-	ret := _mock.Called(ticker, user)
+// DeleteActivityPub provides a mock function for the type MockStorage
+func (_mock *MockStorage) DeleteActivityPub(ticker *Ticker) error {
+	ret := _mock.Called(ticker)
 
 	if len(ret) == 0 {
-		panic("no return value specified for DeleteTickerUser")
+		panic("no return value specified for DeleteActivityPub")
 	}
 
 	var r0 error
-	if returnFunc, ok := ret.Get(0).(func(*Ticker, *User) error); ok {
-		r0 = returnFunc(ticker, user)
+	if returnFunc, ok := ret.Get(0).(func(*Ticker) error); ok {
+		r0 = returnFunc(ticker)
 	} else {
 		r0 = ret.Error(0)
 	}
 	return r0
 }
 
-// MockStorage_DeleteTickerUser_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'DeleteTickerUser'
-type MockStorage_DeleteTickerUser_Call struct {
+// MockStorage_DeleteActivityPub_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'DeleteActivityPub'
+type MockStorage_DeleteActivityPub_Call struct {
 	*mock.Call
 }
 
-// DeleteTickerUser is a helper method to define mock.On call
+// DeleteActivityPub is a helper method to define mock.On call
 //   - ticker *Ticker
-//   - user *User
-func (_e *MockStorage_Expecter) DeleteTickerUser(ticker interface{}, user interface{}) *MockStorage_DeleteTickerUser_Call {
-	return &MockStorage_DeleteTickerUser_Call{Call: _e.mock.On("DeleteTickerUser", ticker, user)}
+func (_e *MockStorage_Expecter) DeleteActivityPub(ticker interface{}) *MockStorage_DeleteActivityPub_Call {
+	return &MockStorage_DeleteActivityPub_Call{Call: _e.mock.On("DeleteActivityPub", ticker)}
 }
 
-func (_c *MockStorage_DeleteTickerUser_Call) Run(run func(ticker *Ticker, user *User)) *MockStorage_DeleteTickerUser_Call {
+func (_c *MockStorage_DeleteActivityPub_Call) Run(run func(ticker *Ticker)) *MockStorage_DeleteActivityPub_Call {
 	_c.Call.Run(func(args mock.Arguments) {
 		var arg0 *Ticker
 		if args[0] != nil {
 			arg0 = args[0].(*Ticker)
 		}
-		var arg1 *User
+		run(
+			arg0,
+		)
+	})
+	return _c
+}
+
+func (_c *MockStorage_DeleteActivityPub_Call) Return(err error) *MockStorage_DeleteActivityPub_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *MockStorage_DeleteActivityPub_Call) RunAndReturn(run func(ticker *Ticker) error) *MockStorage_DeleteActivityPub_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// DeleteActivityPubFollower provides a mock function for the type MockStorage
+func (_mock *MockStorage) DeleteActivityPubFollower(ticker Ticker, actor string) error {
+	ret := _mock.Called(ticker, actor)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteActivityPubFollower")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(Ticker, string) error); ok {
+		r0 = returnFunc(ticker, actor)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// MockStorage_DeleteActivityPubFollower_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'DeleteActivityPubFollower'
+type MockStorage_DeleteActivityPubFollower_Call struct {
+	*mock.Call
+}
+
+// DeleteActivityPubFollower is a helper method to define mock.On call
+//   - ticker Ticker
+//   - actor string
+func (_e *MockStorage_Expecter) DeleteActivityPubFollower(ticker interface{}, actor interface{}) *MockStorage_DeleteActivityPubFollower_Call {
+	return &MockStorage_DeleteActivityPubFollower_Call{Call: _e.mock.On("DeleteActivityPubFollower", ticker, actor)}
+}
+
+func (_c *MockStorage_DeleteActivityPubFollower_Call) Run(run func(ticker Ticker, actor string)) *MockStorage_DeleteActivityPubFollower_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 Ticker
+		if args[0] != nil {
+			arg0 = args[0].(Ticker)
+		}
+		var arg1 string
 		if args[1] != nil {
-			arg1 = args[1].(*User)
+			arg1 = args[1].(string)
 		}
 		run(
 			arg0,
@@ -549,22 +739,22 @@ func (_c *MockStorage_DeleteTickerUser_Call) Run(run func(ticker *Ticker, user *
 	return _c
 }
 
-func (_c *MockStorage_DeleteTickerUser_Call) Return(err error) *MockStorage_DeleteTickerUser_Call {
+func (_c *MockStorage_DeleteActivityPubFollower_Call) Return(err error) *MockStorage_DeleteActivityPubFollower_Call {
 	_c.Call.Return(err)
 	return _c
 }
 
-func (_c *MockStorage_DeleteTickerUser_Call) RunAndReturn(run func(ticker *Ticker, user *User) error) *MockStorage_DeleteTickerUser_Call {
+func (_c *MockStorage_DeleteActivityPubFollower_Call) RunAndReturn(run func(ticker Ticker, actor string) error) *MockStorage_DeleteActivityPubFollower_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
-// DeleteTickerUsers provides a mock function for the type MockStorage
-func (_mock *MockStorage) DeleteTickerUsers(ticker *Ticker) error {
+// DeleteBluesky provides a mock function for the type MockStorage
+func (_mock *MockStorage) DeleteBluesky(ticker *Ticker) error {
 	ret := _mock.Called(ticker)
 
 	if len(ret) == 0 {
-		panic("no return value specified for DeleteTickerUsers")
+		panic("no return value specified for DeleteBluesky")
 	}
 
 	var r0 error
@@ -576,18 +766,18 @@ func (_mock *MockStorage) DeleteTickerUsers(ticker *Ticker) error {
 	return r0
 }
 
-// MockStorage_DeleteTickerUsers_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'DeleteTickerUsers'
-type MockStorage_DeleteTickerUsers_Call struct {
+// MockStorage_DeleteBluesky_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'DeleteBluesky'
+type MockStorage_DeleteBluesky_Call struct {
 	*mock.Call
 }
 
-// DeleteTickerUsers is a helper method to define mock.On call
+// DeleteBluesky is a helper method to define mock.On call
 //   - ticker *Ticker
-func (_e *MockStorage_Expecter) DeleteTickerUsers(ticker interface{}) *MockStorage_DeleteTickerUsers_Call {
-	return &MockStorage_DeleteTickerUsers_Call{Call: _e.mock.On("DeleteTickerUsers", ticker)}
+func (_e *MockStorage_Expecter) DeleteBluesky(ticker interface{}) *MockStorage_DeleteBluesky_Call {
+	return &MockStorage_DeleteBluesky_Call{Call: _e.mock.On("DeleteBluesky", ticker)}
 }
 
-func (_c *MockStorage_DeleteTickerUsers_Call) Run(run func(ticker *Ticker)) *MockStorage_DeleteTickerUsers_Call {
+func (_c *MockStorage_DeleteBluesky_Call) Run(run func(ticker *Ticker)) *MockStorage_DeleteBluesky_Call {
 	_c.Call.Run(func(args mock.Arguments) {
 		var arg0 *Ticker
 		if args[0] != nil {
@@ -600,22 +790,22 @@ func (_c *MockStorage_DeleteTickerUsers_Call) Run(run func(ticker *Ticker)) *Moc
 	return _c
 }
 
-func (_c *MockStorage_DeleteTickerUsers_Call) Return(err error) *MockStorage_DeleteTickerUsers_Call {
+func (_c *MockStorage_DeleteBluesky_Call) Return(err error) *MockStorage_DeleteBluesky_Call {
 	_c.Call.Return(err)
 	return _c
 }
 
-func (_c *MockStorage_DeleteTickerUsers_Call) RunAndReturn(run func(ticker *Ticker) error) *MockStorage_DeleteTickerUsers_Call {
+func (_c *MockStorage_DeleteBluesky_Call) RunAndReturn(run func(ticker *Ticker) error) *MockStorage_DeleteBluesky_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
-// DeleteTickerWebsites provides a mock function for the type MockStorage
-func (_mock *MockStorage) DeleteTickerWebsites(ticker *Ticker) error {
+// DeleteEmail provides a mock function for the type MockStorage
+func (_mock *MockStorage) DeleteEmail(ticker *Ticker) error {
 	ret := _mock.Called(ticker)
 
 	if len(ret) == 0 {
-		panic("no return value specified for DeleteTickerWebsites")
+		panic("no return value specified for DeleteEmail")
 	}
 
 	var r0 error
@@ -627,18 +817,18 @@ func (_mock *MockStorage) DeleteTickerWebsites(ticker *Ticker) error {
 	return r0
 }
 
-// MockStorage_DeleteTickerWebsites_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'DeleteTickerWebsites'
-type MockStorage_DeleteTickerWebsites_Call struct {
+// MockStorage_DeleteEmail_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'DeleteEmail'
+type MockStorage_DeleteEmail_Call struct {
 	*mock.Call
 }
 
-// DeleteTickerWebsites is a helper method to define mock.On call
+// DeleteEmail is a helper method to define mock.On call
 //   - ticker *Ticker
-func (_e *MockStorage_Expecter) DeleteTickerWebsites(ticker interface{}) *MockStorage_DeleteTickerWebsites_Call {
-	return &MockStorage_DeleteTickerWebsites_Call{Call: _e.mock.On("DeleteTickerWebsites", ticker)}
+func (_e *MockStorage_Expecter) DeleteEmail(ticker interface{}) *MockStorage_DeleteEmail_Call {
+	return &MockStorage_DeleteEmail_Call{Call: _e.mock.On("DeleteEmail", ticker)}
 }
 
-func (_c *MockStorage_DeleteTickerWebsites_Call) Run(run func(ticker *Ticker)) *MockStorage_DeleteTickerWebsites_Call {
+func (_c *MockStorage_DeleteEmail_Call) Run(run func(ticker *Ticker)) *MockStorage_DeleteEmail_Call {
 	_c.Call.Run(func(args mock.Arguments) {
 		var arg0 *Ticker
 		if args[0] != nil {
@@ -651,89 +841,106 @@ func (_c *MockStorage_DeleteTickerWebsites_Call) Run(run func(ticker *Ticker)) *
 	return _c
 }
 
-func (_c *MockStorage_DeleteTickerWebsites_Call) Return(err error) *MockStorage_DeleteTickerWebsites_Call {
+func (_c *MockStorage_DeleteEmail_Call) Return(err error) *MockStorage_DeleteEmail_Call {
 	_c.Call.Return(err)
 	return _c
 }
 
-func (_c *MockStorage_DeleteTickerWebsites_Call) RunAndReturn(run func(ticker *Ticker) error) *MockStorage_DeleteTickerWebsites_Call {
+func (_c *MockStorage_DeleteEmail_Call) RunAndReturn(run func(ticker *Ticker) error) *MockStorage_DeleteEmail_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
-// DeleteUpload provides a mock function for the type MockStorage
-func (_mock *MockStorage) DeleteUpload(upload Upload) error {
-	ret := _mock.Called(upload)
+// DeleteEmailDigestEntries provides a mock function for the type MockStorage
+func (_mock *MockStorage) DeleteEmailDigestEntries(ticker Ticker, until time.Time) error {
+	ret := _mock.Called(ticker, until)
 
 	if len(ret) == 0 {
-		panic("no return value specified for DeleteUpload")
+		panic("no return value specified for DeleteEmailDigestEntries")
 	}
 
 	var r0 error
-	if returnFunc, ok := ret.Get(0).(func(Upload) error); ok {
-		r0 = returnFunc(upload)
+	if returnFunc, ok := ret.Get(0).(func(Ticker, time.Time) error); ok {
+		r0 = returnFunc(ticker, until)
 	} else {
 		r0 = ret.Error(0)
 	}
 	return r0
 }
 
-// MockStorage_DeleteUpload_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'DeleteUpload'
-type MockStorage_DeleteUpload_Call struct {
+// MockStorage_DeleteEmailDigestEntries_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'DeleteEmailDigestEntries'
+type MockStorage_DeleteEmailDigestEntries_Call struct {
 	*mock.Call
 }
 
-// DeleteUpload is a helper method to define mock.On call
-//   - upload Upload
-func (_e *MockStorage_Expecter) DeleteUpload(upload interface{}) *MockStorage_DeleteUpload_Call {
-	return &MockStorage_DeleteUpload_Call{Call: _e.mock.On("DeleteUpload", upload)}
+// DeleteEmailDigestEntries is a helper method to define mock.On call
+//   - ticker Ticker
+//   - until time.Time
+func (_e *MockStorage_Expecter) DeleteEmailDigestEntries(ticker interface{}, until interface{}) *MockStorage_DeleteEmailDigestEntries_Call {
+	return &MockStorage_DeleteEmailDigestEntries_Call{Call: _e.mock.On("DeleteEmailDigestEntries", ticker, until)}
 }
 
-func (_c *MockStorage_DeleteUpload_Call) Run(run func(upload Upload)) *MockStorage_DeleteUpload_Call {
+func (_c *MockStorage_DeleteEmailDigestEntries_Call) Run(run func(ticker Ticker, until time.Time)) *MockStorage_DeleteEmailDigestEntries_Call {
 	_c.Call.Run(func(args mock.Arguments) {
-		var arg0 Upload
+		var arg0 Ticker
 		if args[0] != nil {
-			arg0 = args[0].(Upload)
+			arg0 = args[0].(Ticker)
+		}
+		var arg1 time.Time
+		if args[1] != nil {
+			arg1 = args[1].(time.Time)
 		}
 		run(
 			arg0,
+			arg1,
 		)
 	})
 	return _c
 }
 
-func (_c *MockStorage_DeleteUpload_Call) Return(err error) *MockStorage_DeleteUpload_Call {
+func (_c *MockStorage_DeleteEmailDigestEntries_Call) Return(err error) *MockStorage_DeleteEmailDigestEntries_Call {
 	_c.Call.Return(err)
 	return _c
 }
 
-func (_c *MockStorage_DeleteUpload_Call) RunAndReturn(run func(upload Upload) error) *MockStorage_DeleteUpload_Call {
+func (_c *MockStorage_DeleteEmailDigestEntries_Call) RunAndReturn(run func(ticker Ticker, until time.Time) error) *MockStorage_DeleteEmailDigestEntries_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
-// DeleteUploads provides a mock function for the type MockStorage
-func (_mock *MockStorage) DeleteUploads(uploads []Upload) {
-	_mock.Called(uploads)
-	return
+// DeleteEmailSubscription provides a mock function for the type MockStorage
+func (_mock *MockStorage) DeleteEmailSubscription(subscription EmailSubscription) error {
+	ret := _mock.Called(subscription)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteEmailSubscription")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(EmailSubscription) error); ok {
+		r0 = returnFunc(subscription)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
 }
 
-// MockStorage_DeleteUploads_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'DeleteUploads'
-type MockStorage_DeleteUploads_Call struct {
+// MockStorage_DeleteEmailSubscription_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'DeleteEmailSubscription'
+type MockStorage_DeleteEmailSubscription_Call struct {
 	*mock.Call
 }
 
-// DeleteUploads is a helper method to define mock.On call
-//   - uploads []Upload
-func (_e *MockStorage_Expecter) DeleteUploads(uploads interface{}) *MockStorage_DeleteUploads_Call {
-	return &MockStorage_DeleteUploads_Call{Call: _e.mock.On("DeleteUploads", uploads)}
+// DeleteEmailSubscription is a helper method to define mock.On call
+//   - subscription EmailSubscription
+func (_e *MockStorage_Expecter) DeleteEmailSubscription(subscription interface{}) *MockStorage_DeleteEmailSubscription_Call {
+	return &MockStorage_DeleteEmailSubscription_Call{Call: _e.mock.On("DeleteEmailSubscription", subscription)}
 }
 
-func (_c *MockStorage_DeleteUploads_Call) Run(run func(uploads []Upload)) *MockStorage_DeleteUploads_Call {
+func (_c *MockStorage_DeleteEmailSubscription_Call) Run(run func(subscription EmailSubscription)) *MockStorage_DeleteEmailSubscription_Call {
 	_c.Call.Run(func(args mock.Arguments) {
-		var arg0 []Upload
+		var arg0 EmailSubscription
 		if args[0] != nil {
-			arg0 = args[0].([]Upload)
+			arg0 = args[0].(EmailSubscription)
 		}
 		run(
 			arg0,
@@ -742,22 +949,22 @@ func (_c *MockStorage_DeleteUploads_Call) Run(run func(uploads []Upload)) *MockS
 	return _c
 }
 
-func (_c *MockStorage_DeleteUploads_Call) Return() *MockStorage_DeleteUploads_Call {
-	_c.Call.Return()
+func (_c *MockStorage_DeleteEmailSubscription_Call) Return(err error) *MockStorage_DeleteEmailSubscription_Call {
+	_c.Call.Return(err)
 	return _c
 }
 
-func (_c *MockStorage_DeleteUploads_Call) RunAndReturn(run func(uploads []Upload)) *MockStorage_DeleteUploads_Call {
-	_c.Run(run)
+func (_c *MockStorage_DeleteEmailSubscription_Call) RunAndReturn(run func(subscription EmailSubscription) error) *MockStorage_DeleteEmailSubscription_Call {
+	_c.Call.Return(run)
 	return _c
 }
 
-// DeleteUploadsByTicker provides a mock function for the type MockStorage
-func (_mock *MockStorage) DeleteUploadsByTicker(ticker *Ticker) error {
+// DeleteIntegrations provides a mock function for the type MockStorage
+func (_mock *MockStorage) DeleteIntegrations(ticker *Ticker) error {
 	ret := _mock.Called(ticker)
 
 	if len(ret) == 0 {
-		panic("no return value specified for DeleteUploadsByTicker")
+		panic("no return value specified for DeleteIntegrations")
 	}
 
 	var r0 error
@@ -769,18 +976,18 @@ func (_mock *MockStorage) DeleteUploadsByTicker(ticker *Ticker) error {
 	return r0
 }
 
-// MockStorage_DeleteUploadsByTicker_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'DeleteUploadsByTicker'
-type MockStorage_DeleteUploadsByTicker_Call struct {
+// MockStorage_DeleteIntegrations_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'DeleteIntegrations'
+type MockStorage_DeleteIntegrations_Call struct {
 	*mock.Call
 }
 
-// DeleteUploadsByTicker is a helper method to define mock.On call
+// DeleteIntegrations is a helper method to define mock.On call
 //   - ticker *Ticker
-func (_e *MockStorage_Expecter) DeleteUploadsByTicker(ticker interface{}) *MockStorage_DeleteUploadsByTicker_Call {
-	return &MockStorage_DeleteUploadsByTicker_Call{Call: _e.mock.On("DeleteUploadsByTicker", ticker)}
+func (_e *MockStorage_Expecter) DeleteIntegrations(ticker interface{}) *MockStorage_DeleteIntegrations_Call {
+	return &MockStorage_DeleteIntegrations_Call{Call: _e.mock.On("DeleteIntegrations", ticker)}
 }
 
-func (_c *MockStorage_DeleteUploadsByTicker_Call) Run(run func(ticker *Ticker)) *MockStorage_DeleteUploadsByTicker_Call {
+func (_c *MockStorage_DeleteIntegrations_Call) Run(run func(ticker *Ticker)) *MockStorage_DeleteIntegrations_Call {
 	_c.Call.Run(func(args mock.Arguments) {
 		var arg0 *Ticker
 		if args[0] != nil {
@@ -793,49 +1000,49 @@ func (_c *MockStorage_DeleteUploadsByTicker_Call) Run(run func(ticker *Ticker))
 	return _c
 }
 
-func (_c *MockStorage_DeleteUploadsByTicker_Call) Return(err error) *MockStorage_DeleteUploadsByTicker_Call {
+func (_c *MockStorage_DeleteIntegrations_Call) Return(err error) *MockStorage_DeleteIntegrations_Call {
 	_c.Call.Return(err)
 	return _c
 }
 
-func (_c *MockStorage_DeleteUploadsByTicker_Call) RunAndReturn(run func(ticker *Ticker) error) *MockStorage_DeleteUploadsByTicker_Call {
+func (_c *MockStorage_DeleteIntegrations_Call) RunAndReturn(run func(ticker *Ticker) error) *MockStorage_DeleteIntegrations_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
-// DeleteUser provides a mock function for the type MockStorage
-func (_mock *MockStorage) DeleteUser(user User) error {
-	ret := _mock.Called(user)
+// DeleteMailbox provides a mock function for the type MockStorage
+func (_mock *MockStorage) DeleteMailbox(ticker *Ticker) error {
+	ret := _mock.Called(ticker)
 
 	if len(ret) == 0 {
-		panic("no return value specified for DeleteUser")
+		panic("no return value specified for DeleteMailbox")
 	}
 
 	var r0 error
-	if returnFunc, ok := ret.Get(0).(func(User) error); ok {
-		r0 = returnFunc(user)
+	if returnFunc, ok := ret.Get(0).(func(*Ticker) error); ok {
+		r0 = returnFunc(ticker)
 	} else {
 		r0 = ret.Error(0)
 	}
 	return r0
 }
 
-// MockStorage_DeleteUser_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'DeleteUser'
-type MockStorage_DeleteUser_Call struct {
+// MockStorage_DeleteMailbox_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'DeleteMailbox'
+type MockStorage_DeleteMailbox_Call struct {
 	*mock.Call
 }
 
-// DeleteUser is a helper method to define mock.On call
-//   - user User
-func (_e *MockStorage_Expecter) DeleteUser(user interface{}) *MockStorage_DeleteUser_Call {
-	return &MockStorage_DeleteUser_Call{Call: _e.mock.On("DeleteUser", user)}
+// DeleteMailbox is a helper method to define mock.On call
+//   - ticker *Ticker
+func (_e *MockStorage_Expecter) DeleteMailbox(ticker interface{}) *MockStorage_DeleteMailbox_Call {
+	return &MockStorage_DeleteMailbox_Call{Call: _e.mock.On("DeleteMailbox", ticker)}
 }
 
-func (_c *MockStorage_DeleteUser_Call) Run(run func(user User)) *MockStorage_DeleteUser_Call {
+func (_c *MockStorage_DeleteMailbox_Call) Run(run func(ticker *Ticker)) *MockStorage_DeleteMailbox_Call {
 	_c.Call.Run(func(args mock.Arguments) {
-		var arg0 User
+		var arg0 *Ticker
 		if args[0] != nil {
-			arg0 = args[0].(User)
+			arg0 = args[0].(*Ticker)
 		}
 		run(
 			arg0,
@@ -844,690 +1051,5410 @@ func (_c *MockStorage_DeleteUser_Call) Run(run func(user User)) *MockStorage_Del
 	return _c
 }
 
-func (_c *MockStorage_DeleteUser_Call) Return(err error) *MockStorage_DeleteUser_Call {
+func (_c *MockStorage_DeleteMailbox_Call) Return(err error) *MockStorage_DeleteMailbox_Call {
 	_c.Call.Return(err)
 	return _c
 }
 
-func (_c *MockStorage_DeleteUser_Call) RunAndReturn(run func(user User) error) *MockStorage_DeleteUser_Call {
+func (_c *MockStorage_DeleteMailbox_Call) RunAndReturn(run func(ticker *Ticker) error) *MockStorage_DeleteMailbox_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
-// FindMessage provides a mock function for the type MockStorage
-func (_mock *MockStorage) FindMessage(tickerID int, messageID int, opts ...func(*gorm.DB) *gorm.DB) (Message, error) {
-	var tmpRet mock.Arguments
-	if len(opts) > 0 {
-		tmpRet = _mock.Called(tickerID, messageID, opts)
-	} else {
-		tmpRet = _mock.Called(tickerID, messageID)
-	}
-	ret := tmpRet
+// DeleteMastodon provides a mock function for the type MockStorage
+func (_mock *MockStorage) DeleteMastodon(ticker *Ticker) error {
+	ret := _mock.Called(ticker)
 
 	if len(ret) == 0 {
-		panic("no return value specified for FindMessage")
+		panic("no return value specified for DeleteMastodon")
 	}
 
-	var r0 Message
-	var r1 error
-	if returnFunc, ok := ret.Get(0).(func(int, int, ...func(*gorm.DB) *gorm.DB) (Message, error)); ok {
-		return returnFunc(tickerID, messageID, opts...)
-	}
-	if returnFunc, ok := ret.Get(0).(func(int, int, ...func(*gorm.DB) *gorm.DB) Message); ok {
-		r0 = returnFunc(tickerID, messageID, opts...)
-	} else {
-		r0 = ret.Get(0).(Message)
-	}
-	if returnFunc, ok := ret.Get(1).(func(int, int, ...func(*gorm.DB) *gorm.DB) error); ok {
-		r1 = returnFunc(tickerID, messageID, opts...)
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(*Ticker) error); ok {
+		r0 = returnFunc(ticker)
 	} else {
-		r1 = ret.Error(1)
+		r0 = ret.Error(0)
 	}
-	return r0, r1
+	return r0
 }
 
-// MockStorage_FindMessage_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'FindMessage'
-type MockStorage_FindMessage_Call struct {
+// MockStorage_DeleteMastodon_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'DeleteMastodon'
+type MockStorage_DeleteMastodon_Call struct {
 	*mock.Call
 }
 
-// FindMessage is a helper method to define mock.On call
-//   - tickerID int
-//   - messageID int
-//   - opts ...func(*gorm.DB) *gorm.DB
-func (_e *MockStorage_Expecter) FindMessage(tickerID interface{}, messageID interface{}, opts ...interface{}) *MockStorage_FindMessage_Call {
-	return &MockStorage_FindMessage_Call{Call: _e.mock.On("FindMessage",
-		append([]interface{}{tickerID, messageID}, opts...)...)}
+// DeleteMastodon is a helper method to define mock.On call
+//   - ticker *Ticker
+func (_e *MockStorage_Expecter) DeleteMastodon(ticker interface{}) *MockStorage_DeleteMastodon_Call {
+	return &MockStorage_DeleteMastodon_Call{Call: _e.mock.On("DeleteMastodon", ticker)}
 }
 
-func (_c *MockStorage_FindMessage_Call) Run(run func(tickerID int, messageID int, opts ...func(*gorm.DB) *gorm.DB)) *MockStorage_FindMessage_Call {
+func (_c *MockStorage_DeleteMastodon_Call) Run(run func(ticker *Ticker)) *MockStorage_DeleteMastodon_Call {
 	_c.Call.Run(func(args mock.Arguments) {
-		var arg0 int
+		var arg0 *Ticker
 		if args[0] != nil {
-			arg0 = args[0].(int)
-		}
-		var arg1 int
-		if args[1] != nil {
-			arg1 = args[1].(int)
-		}
-		var arg2 []func(*gorm.DB) *gorm.DB
-		var variadicArgs []func(*gorm.DB) *gorm.DB
-		if len(args) > 2 {
-			variadicArgs = args[2].([]func(*gorm.DB) *gorm.DB)
+			arg0 = args[0].(*Ticker)
 		}
-		arg2 = variadicArgs
 		run(
 			arg0,
-			arg1,
-			arg2...,
 		)
 	})
 	return _c
 }
 
-func (_c *MockStorage_FindMessage_Call) Return(message Message, err error) *MockStorage_FindMessage_Call {
-	_c.Call.Return(message, err)
+func (_c *MockStorage_DeleteMastodon_Call) Return(err error) *MockStorage_DeleteMastodon_Call {
+	_c.Call.Return(err)
 	return _c
 }
 
-func (_c *MockStorage_FindMessage_Call) RunAndReturn(run func(tickerID int, messageID int, opts ...func(*gorm.DB) *gorm.DB) (Message, error)) *MockStorage_FindMessage_Call {
+func (_c *MockStorage_DeleteMastodon_Call) RunAndReturn(run func(ticker *Ticker) error) *MockStorage_DeleteMastodon_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
-// FindMessagesByTicker provides a mock function for the type MockStorage
-func (_mock *MockStorage) FindMessagesByTicker(ticker Ticker, opts ...func(*gorm.DB) *gorm.DB) ([]Message, error) {
-	var tmpRet mock.Arguments
-	if len(opts) > 0 {
-		tmpRet = _mock.Called(ticker, opts)
-	} else {
-		tmpRet = _mock.Called(ticker)
-	}
-	ret := tmpRet
+// DeleteMatrix provides a mock function for the type MockStorage
+func (_mock *MockStorage) DeleteMatrix(ticker *Ticker) error {
+	ret := _mock.Called(ticker)
 
 	if len(ret) == 0 {
-		panic("no return value specified for FindMessagesByTicker")
+		panic("no return value specified for DeleteMatrix")
 	}
 
-	var r0 []Message
-	var r1 error
-	if returnFunc, ok := ret.Get(0).(func(Ticker, ...func(*gorm.DB) *gorm.DB) ([]Message, error)); ok {
-		return returnFunc(ticker, opts...)
-	}
-	if returnFunc, ok := ret.Get(0).(func(Ticker, ...func(*gorm.DB) *gorm.DB) []Message); ok {
-		r0 = returnFunc(ticker, opts...)
-	} else {
-		if ret.Get(0) != nil {
-			r0 = ret.Get(0).([]Message)
-		}
-	}
-	if returnFunc, ok := ret.Get(1).(func(Ticker, ...func(*gorm.DB) *gorm.DB) error); ok {
-		r1 = returnFunc(ticker, opts...)
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(*Ticker) error); ok {
+		r0 = returnFunc(ticker)
 	} else {
-		r1 = ret.Error(1)
+		r0 = ret.Error(0)
 	}
-	return r0, r1
+	return r0
 }
 
-// MockStorage_FindMessagesByTicker_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'FindMessagesByTicker'
-type MockStorage_FindMessagesByTicker_Call struct {
+// MockStorage_DeleteMatrix_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'DeleteMatrix'
+type MockStorage_DeleteMatrix_Call struct {
 	*mock.Call
 }
 
-// FindMessagesByTicker is a helper method to define mock.On call
-//   - ticker Ticker
-//   - opts ...func(*gorm.DB) *gorm.DB
-func (_e *MockStorage_Expecter) FindMessagesByTicker(ticker interface{}, opts ...interface{}) *MockStorage_FindMessagesByTicker_Call {
-	return &MockStorage_FindMessagesByTicker_Call{Call: _e.mock.On("FindMessagesByTicker",
-		append([]interface{}{ticker}, opts...)...)}
+// DeleteMatrix is a helper method to define mock.On call
+//   - ticker *Ticker
+func (_e *MockStorage_Expecter) DeleteMatrix(ticker interface{}) *MockStorage_DeleteMatrix_Call {
+	return &MockStorage_DeleteMatrix_Call{Call: _e.mock.On("DeleteMatrix", ticker)}
 }
 
-func (_c *MockStorage_FindMessagesByTicker_Call) Run(run func(ticker Ticker, opts ...func(*gorm.DB) *gorm.DB)) *MockStorage_FindMessagesByTicker_Call {
+func (_c *MockStorage_DeleteMatrix_Call) Run(run func(ticker *Ticker)) *MockStorage_DeleteMatrix_Call {
 	_c.Call.Run(func(args mock.Arguments) {
-		var arg0 Ticker
+		var arg0 *Ticker
 		if args[0] != nil {
-			arg0 = args[0].(Ticker)
-		}
-		var arg1 []func(*gorm.DB) *gorm.DB
-		var variadicArgs []func(*gorm.DB) *gorm.DB
-		if len(args) > 1 {
-			variadicArgs = args[1].([]func(*gorm.DB) *gorm.DB)
+			arg0 = args[0].(*Ticker)
 		}
-		arg1 = variadicArgs
 		run(
 			arg0,
-			arg1...,
 		)
 	})
 	return _c
 }
 
-func (_c *MockStorage_FindMessagesByTicker_Call) Return(messages []Message, err error) *MockStorage_FindMessagesByTicker_Call {
-	_c.Call.Return(messages, err)
+func (_c *MockStorage_DeleteMatrix_Call) Return(err error) *MockStorage_DeleteMatrix_Call {
+	_c.Call.Return(err)
 	return _c
 }
 
-func (_c *MockStorage_FindMessagesByTicker_Call) RunAndReturn(run func(ticker Ticker, opts ...func(*gorm.DB) *gorm.DB) ([]Message, error)) *MockStorage_FindMessagesByTicker_Call {
+func (_c *MockStorage_DeleteMatrix_Call) RunAndReturn(run func(ticker *Ticker) error) *MockStorage_DeleteMatrix_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
-// FindMessagesByTickerAndPagination provides a mock function for the type MockStorage
-func (_mock *MockStorage) FindMessagesByTickerAndPagination(ticker Ticker, pagination1 pagination.Pagination, opts ...func(*gorm.DB) *gorm.DB) ([]Message, error) {
-	var tmpRet mock.Arguments
-	if len(opts) > 0 {
-		tmpRet = _mock.Called(ticker, pagination1, opts)
-	} else {
-		tmpRet = _mock.Called(ticker, pagination1)
-	}
-	ret := tmpRet
+// DeleteMessage provides a mock function for the type MockStorage
+func (_mock *MockStorage) DeleteMessage(message Message, bridges []string) error {
+	ret := _mock.Called(message, bridges)
 
 	if len(ret) == 0 {
-		panic("no return value specified for FindMessagesByTickerAndPagination")
+		panic("no return value specified for DeleteMessage")
 	}
 
-	var r0 []Message
-	var r1 error
-	if returnFunc, ok := ret.Get(0).(func(Ticker, pagination.Pagination, ...func(*gorm.DB) *gorm.DB) ([]Message, error)); ok {
-		return returnFunc(ticker, pagination1, opts...)
-	}
-	if returnFunc, ok := ret.Get(0).(func(Ticker, pagination.Pagination, ...func(*gorm.DB) *gorm.DB) []Message); ok {
-		r0 = returnFunc(ticker, pagination1, opts...)
-	} else {
-		if ret.Get(0) != nil {
-			r0 = ret.Get(0).([]Message)
-		}
-	}
-	if returnFunc, ok := ret.Get(1).(func(Ticker, pagination.Pagination, ...func(*gorm.DB) *gorm.DB) error); ok {
-		r1 = returnFunc(ticker, pagination1, opts...)
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(Message, []string) error); ok {
+		r0 = returnFunc(message, bridges)
 	} else {
-		r1 = ret.Error(1)
+		r0 = ret.Error(0)
 	}
-	return r0, r1
+	return r0
 }
 
-// MockStorage_FindMessagesByTickerAndPagination_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'FindMessagesByTickerAndPagination'
-type MockStorage_FindMessagesByTickerAndPagination_Call struct {
+// MockStorage_DeleteMessage_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'DeleteMessage'
+type MockStorage_DeleteMessage_Call struct {
 	*mock.Call
 }
 
-// FindMessagesByTickerAndPagination is a helper method to define mock.On call
-//   - ticker Ticker
-//   - pagination1 pagination.Pagination
-//   - opts ...func(*gorm.DB) *gorm.DB
-func (_e *MockStorage_Expecter) FindMessagesByTickerAndPagination(ticker interface{}, pagination1 interface{}, opts ...interface{}) *MockStorage_FindMessagesByTickerAndPagination_Call {
-	return &MockStorage_FindMessagesByTickerAndPagination_Call{Call: _e.mock.On("FindMessagesByTickerAndPagination",
-		append([]interface{}{ticker, pagination1}, opts...)...)}
+// DeleteMessage is a helper method to define mock.On call
+//   - message Message
+//   - bridges []string
+func (_e *MockStorage_Expecter) DeleteMessage(message interface{}, bridges interface{}) *MockStorage_DeleteMessage_Call {
+	return &MockStorage_DeleteMessage_Call{Call: _e.mock.On("DeleteMessage", message, bridges)}
 }
 
-func (_c *MockStorage_FindMessagesByTickerAndPagination_Call) Run(run func(ticker Ticker, pagination1 pagination.Pagination, opts ...func(*gorm.DB) *gorm.DB)) *MockStorage_FindMessagesByTickerAndPagination_Call {
+func (_c *MockStorage_DeleteMessage_Call) Run(run func(message Message, bridges []string)) *MockStorage_DeleteMessage_Call {
 	_c.Call.Run(func(args mock.Arguments) {
-		var arg0 Ticker
+		var arg0 Message
 		if args[0] != nil {
-			arg0 = args[0].(Ticker)
+			arg0 = args[0].(Message)
 		}
-		var arg1 pagination.Pagination
+		var arg1 []string
 		if args[1] != nil {
-			arg1 = args[1].(pagination.Pagination)
-		}
-		var arg2 []func(*gorm.DB) *gorm.DB
-		var variadicArgs []func(*gorm.DB) *gorm.DB
-		if len(args) > 2 {
-			variadicArgs = args[2].([]func(*gorm.DB) *gorm.DB)
+			arg1 = args[1].([]string)
 		}
-		arg2 = variadicArgs
 		run(
 			arg0,
 			arg1,
-			arg2...,
 		)
 	})
 	return _c
 }
 
-func (_c *MockStorage_FindMessagesByTickerAndPagination_Call) Return(messages []Message, err error) *MockStorage_FindMessagesByTickerAndPagination_Call {
-	_c.Call.Return(messages, err)
+func (_c *MockStorage_DeleteMessage_Call) Return(err error) *MockStorage_DeleteMessage_Call {
+	_c.Call.Return(err)
 	return _c
 }
 
-func (_c *MockStorage_FindMessagesByTickerAndPagination_Call) RunAndReturn(run func(ticker Ticker, pagination1 pagination.Pagination, opts ...func(*gorm.DB) *gorm.DB) ([]Message, error)) *MockStorage_FindMessagesByTickerAndPagination_Call {
+func (_c *MockStorage_DeleteMessage_Call) RunAndReturn(run func(message Message, bridges []string) error) *MockStorage_DeleteMessage_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
-// FindTickerByID provides a mock function for the type MockStorage
-func (_mock *MockStorage) FindTickerByID(id int, opts ...func(*gorm.DB) *gorm.DB) (Ticker, error) {
-	var tmpRet mock.Arguments
-	if len(opts) > 0 {
-		tmpRet = _mock.Called(id, opts)
-	} else {
-		tmpRet = _mock.Called(id)
-	}
-	ret := tmpRet
+// DeleteMessages provides a mock function for the type MockStorage
+func (_mock *MockStorage) DeleteMessages(ticker *Ticker) error {
+	ret := _mock.Called(ticker)
 
 	if len(ret) == 0 {
-		panic("no return value specified for FindTickerByID")
+		panic("no return value specified for DeleteMessages")
 	}
 
-	var r0 Ticker
-	var r1 error
-	if returnFunc, ok := ret.Get(0).(func(int, ...func(*gorm.DB) *gorm.DB) (Ticker, error)); ok {
-		return returnFunc(id, opts...)
-	}
-	if returnFunc, ok := ret.Get(0).(func(int, ...func(*gorm.DB) *gorm.DB) Ticker); ok {
-		r0 = returnFunc(id, opts...)
-	} else {
-		r0 = ret.Get(0).(Ticker)
-	}
-	if returnFunc, ok := ret.Get(1).(func(int, ...func(*gorm.DB) *gorm.DB) error); ok {
-		r1 = returnFunc(id, opts...)
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(*Ticker) error); ok {
+		r0 = returnFunc(ticker)
 	} else {
-		r1 = ret.Error(1)
+		r0 = ret.Error(0)
 	}
-	return r0, r1
+	return r0
 }
 
-// MockStorage_FindTickerByID_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'FindTickerByID'
-type MockStorage_FindTickerByID_Call struct {
+// MockStorage_DeleteMessages_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'DeleteMessages'
+type MockStorage_DeleteMessages_Call struct {
 	*mock.Call
 }
 
-// FindTickerByID is a helper method to define mock.On call
-//   - id int
-//   - opts ...func(*gorm.DB) *gorm.DB
-func (_e *MockStorage_Expecter) FindTickerByID(id interface{}, opts ...interface{}) *MockStorage_FindTickerByID_Call {
-	return &MockStorage_FindTickerByID_Call{Call: _e.mock.On("FindTickerByID",
-		append([]interface{}{id}, opts...)...)}
+// DeleteMessages is a helper method to define mock.On call
+//   - ticker *Ticker
+func (_e *MockStorage_Expecter) DeleteMessages(ticker interface{}) *MockStorage_DeleteMessages_Call {
+	return &MockStorage_DeleteMessages_Call{Call: _e.mock.On("DeleteMessages", ticker)}
 }
 
-func (_c *MockStorage_FindTickerByID_Call) Run(run func(id int, opts ...func(*gorm.DB) *gorm.DB)) *MockStorage_FindTickerByID_Call {
+func (_c *MockStorage_DeleteMessages_Call) Run(run func(ticker *Ticker)) *MockStorage_DeleteMessages_Call {
 	_c.Call.Run(func(args mock.Arguments) {
-		var arg0 int
+		var arg0 *Ticker
 		if args[0] != nil {
-			arg0 = args[0].(int)
-		}
-		var arg1 []func(*gorm.DB) *gorm.DB
-		var variadicArgs []func(*gorm.DB) *gorm.DB
-		if len(args) > 1 {
-			variadicArgs = args[1].([]func(*gorm.DB) *gorm.DB)
+			arg0 = args[0].(*Ticker)
 		}
-		arg1 = variadicArgs
 		run(
 			arg0,
-			arg1...,
 		)
 	})
 	return _c
 }
 
-func (_c *MockStorage_FindTickerByID_Call) Return(ticker Ticker, err error) *MockStorage_FindTickerByID_Call {
-	_c.Call.Return(ticker, err)
+func (_c *MockStorage_DeleteMessages_Call) Return(err error) *MockStorage_DeleteMessages_Call {
+	_c.Call.Return(err)
 	return _c
 }
 
-func (_c *MockStorage_FindTickerByID_Call) RunAndReturn(run func(id int, opts ...func(*gorm.DB) *gorm.DB) (Ticker, error)) *MockStorage_FindTickerByID_Call {
+func (_c *MockStorage_DeleteMessages_Call) RunAndReturn(run func(ticker *Ticker) error) *MockStorage_DeleteMessages_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
-// FindTickerByOrigin provides a mock function for the type MockStorage
-func (_mock *MockStorage) FindTickerByOrigin(origin string, opts ...func(*gorm.DB) *gorm.DB) (Ticker, error) {
-	var tmpRet mock.Arguments
-	if len(opts) > 0 {
-		tmpRet = _mock.Called(origin, opts)
-	} else {
-		tmpRet = _mock.Called(origin)
-	}
-	ret := tmpRet
+// DeleteSignalGroup provides a mock function for the type MockStorage
+func (_mock *MockStorage) DeleteSignalGroup(ticker *Ticker) error {
+	ret := _mock.Called(ticker)
 
 	if len(ret) == 0 {
-		panic("no return value specified for FindTickerByOrigin")
+		panic("no return value specified for DeleteSignalGroup")
 	}
 
-	var r0 Ticker
-	var r1 error
-	if returnFunc, ok := ret.Get(0).(func(string, ...func(*gorm.DB) *gorm.DB) (Ticker, error)); ok {
-		return returnFunc(origin, opts...)
-	}
-	if returnFunc, ok := ret.Get(0).(func(string, ...func(*gorm.DB) *gorm.DB) Ticker); ok {
-		r0 = returnFunc(origin, opts...)
-	} else {
-		r0 = ret.Get(0).(Ticker)
-	}
-	if returnFunc, ok := ret.Get(1).(func(string, ...func(*gorm.DB) *gorm.DB) error); ok {
-		r1 = returnFunc(origin, opts...)
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(*Ticker) error); ok {
+		r0 = returnFunc(ticker)
 	} else {
-		r1 = ret.Error(1)
+		r0 = ret.Error(0)
 	}
-	return r0, r1
+	return r0
 }
 
-// MockStorage_FindTickerByOrigin_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'FindTickerByOrigin'
-type MockStorage_FindTickerByOrigin_Call struct {
+// MockStorage_DeleteSignalGroup_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'DeleteSignalGroup'
+type MockStorage_DeleteSignalGroup_Call struct {
 	*mock.Call
 }
 
-// FindTickerByOrigin is a helper method to define mock.On call
-//   - origin string
-//   - opts ...func(*gorm.DB) *gorm.DB
-func (_e *MockStorage_Expecter) FindTickerByOrigin(origin interface{}, opts ...interface{}) *MockStorage_FindTickerByOrigin_Call {
-	return &MockStorage_FindTickerByOrigin_Call{Call: _e.mock.On("FindTickerByOrigin",
-		append([]interface{}{origin}, opts...)...)}
+// DeleteSignalGroup is a helper method to define mock.On call
+//   - ticker *Ticker
+func (_e *MockStorage_Expecter) DeleteSignalGroup(ticker interface{}) *MockStorage_DeleteSignalGroup_Call {
+	return &MockStorage_DeleteSignalGroup_Call{Call: _e.mock.On("DeleteSignalGroup", ticker)}
 }
 
-func (_c *MockStorage_FindTickerByOrigin_Call) Run(run func(origin string, opts ...func(*gorm.DB) *gorm.DB)) *MockStorage_FindTickerByOrigin_Call {
+func (_c *MockStorage_DeleteSignalGroup_Call) Run(run func(ticker *Ticker)) *MockStorage_DeleteSignalGroup_Call {
 	_c.Call.Run(func(args mock.Arguments) {
-		var arg0 string
+		var arg0 *Ticker
 		if args[0] != nil {
-			arg0 = args[0].(string)
-		}
-		var arg1 []func(*gorm.DB) *gorm.DB
-		var variadicArgs []func(*gorm.DB) *gorm.DB
-		if len(args) > 1 {
-			variadicArgs = args[1].([]func(*gorm.DB) *gorm.DB)
+			arg0 = args[0].(*Ticker)
 		}
-		arg1 = variadicArgs
 		run(
 			arg0,
-			arg1...,
 		)
 	})
 	return _c
 }
 
-func (_c *MockStorage_FindTickerByOrigin_Call) Return(ticker Ticker, err error) *MockStorage_FindTickerByOrigin_Call {
-	_c.Call.Return(ticker, err)
+func (_c *MockStorage_DeleteSignalGroup_Call) Return(err error) *MockStorage_DeleteSignalGroup_Call {
+	_c.Call.Return(err)
 	return _c
 }
 
-func (_c *MockStorage_FindTickerByOrigin_Call) RunAndReturn(run func(origin string, opts ...func(*gorm.DB) *gorm.DB) (Ticker, error)) *MockStorage_FindTickerByOrigin_Call {
+func (_c *MockStorage_DeleteSignalGroup_Call) RunAndReturn(run func(ticker *Ticker) error) *MockStorage_DeleteSignalGroup_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
-// FindTickerByUserAndID provides a mock function for the type MockStorage
-func (_mock *MockStorage) FindTickerByUserAndID(user User, id int, opts ...func(*gorm.DB) *gorm.DB) (Ticker, error) {
+// DeleteTelegram provides a mock function for the type MockStorage
+func (_mock *MockStorage) DeleteTelegram(ticker *Ticker) error {
+	ret := _mock.Called(ticker)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteTelegram")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(*Ticker) error); ok {
+		r0 = returnFunc(ticker)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// MockStorage_DeleteTelegram_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'DeleteTelegram'
+type MockStorage_DeleteTelegram_Call struct {
+	*mock.Call
+}
+
+// DeleteTelegram is a helper method to define mock.On call
+//   - ticker *Ticker
+func (_e *MockStorage_Expecter) DeleteTelegram(ticker interface{}) *MockStorage_DeleteTelegram_Call {
+	return &MockStorage_DeleteTelegram_Call{Call: _e.mock.On("DeleteTelegram", ticker)}
+}
+
+func (_c *MockStorage_DeleteTelegram_Call) Run(run func(ticker *Ticker)) *MockStorage_DeleteTelegram_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 *Ticker
+		if args[0] != nil {
+			arg0 = args[0].(*Ticker)
+		}
+		run(
+			arg0,
+		)
+	})
+	return _c
+}
+
+func (_c *MockStorage_DeleteTelegram_Call) Return(err error) *MockStorage_DeleteTelegram_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *MockStorage_DeleteTelegram_Call) RunAndReturn(run func(ticker *Ticker) error) *MockStorage_DeleteTelegram_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// DeleteTicker provides a mock function for the type MockStorage
+func (_mock *MockStorage) DeleteTicker(ticker *Ticker) error {
+	ret := _mock.Called(ticker)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteTicker")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(*Ticker) error); ok {
+		r0 = returnFunc(ticker)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// MockStorage_DeleteTicker_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'DeleteTicker'
+type MockStorage_DeleteTicker_Call struct {
+	*mock.Call
+}
+
+// DeleteTicker is a helper method to define mock.On call
+//   - ticker *Ticker
+func (_e *MockStorage_Expecter) DeleteTicker(ticker interface{}) *MockStorage_DeleteTicker_Call {
+	return &MockStorage_DeleteTicker_Call{Call: _e.mock.On("DeleteTicker", ticker)}
+}
+
+func (_c *MockStorage_DeleteTicker_Call) Run(run func(ticker *Ticker)) *MockStorage_DeleteTicker_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 *Ticker
+		if args[0] != nil {
+			arg0 = args[0].(*Ticker)
+		}
+		run(
+			arg0,
+		)
+	})
+	return _c
+}
+
+func (_c *MockStorage_DeleteTicker_Call) Return(err error) *MockStorage_DeleteTicker_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *MockStorage_DeleteTicker_Call) RunAndReturn(run func(ticker *Ticker) error) *MockStorage_DeleteTicker_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// DeleteTickerSignalSenders provides a mock function for the type MockStorage
+func (_mock *MockStorage) DeleteTickerSignalSenders(ticker *Ticker) error {
+	ret := _mock.Called(ticker)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteTickerSignalSenders")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(*Ticker) error); ok {
+		r0 = returnFunc(ticker)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// MockStorage_DeleteTickerSignalSenders_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'DeleteTickerSignalSenders'
+type MockStorage_DeleteTickerSignalSenders_Call struct {
+	*mock.Call
+}
+
+// DeleteTickerSignalSenders is a helper method to define mock.On call
+//   - ticker *Ticker
+func (_e *MockStorage_Expecter) DeleteTickerSignalSenders(ticker interface{}) *MockStorage_DeleteTickerSignalSenders_Call {
+	return &MockStorage_DeleteTickerSignalSenders_Call{Call: _e.mock.On("DeleteTickerSignalSenders", ticker)}
+}
+
+func (_c *MockStorage_DeleteTickerSignalSenders_Call) Run(run func(ticker *Ticker)) *MockStorage_DeleteTickerSignalSenders_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 *Ticker
+		if args[0] != nil {
+			arg0 = args[0].(*Ticker)
+		}
+		run(
+			arg0,
+		)
+	})
+	return _c
+}
+
+func (_c *MockStorage_DeleteTickerSignalSenders_Call) Return(err error) *MockStorage_DeleteTickerSignalSenders_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *MockStorage_DeleteTickerSignalSenders_Call) RunAndReturn(run func(ticker *Ticker) error) *MockStorage_DeleteTickerSignalSenders_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// DeleteTickerUser provides a mock function for the type MockStorage
+func (_mock *MockStorage) DeleteTickerUser(ticker *Ticker, user *User) error {
+	ret := _mock.Called(ticker, user)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteTickerUser")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(*Ticker, *User) error); ok {
+		r0 = returnFunc(ticker, user)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// MockStorage_DeleteTickerUser_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'DeleteTickerUser'
+type MockStorage_DeleteTickerUser_Call struct {
+	*mock.Call
+}
+
+// DeleteTickerUser is a helper method to define mock.On call
+//   - ticker *Ticker
+//   - user *User
+func (_e *MockStorage_Expecter) DeleteTickerUser(ticker interface{}, user interface{}) *MockStorage_DeleteTickerUser_Call {
+	return &MockStorage_DeleteTickerUser_Call{Call: _e.mock.On("DeleteTickerUser", ticker, user)}
+}
+
+func (_c *MockStorage_DeleteTickerUser_Call) Run(run func(ticker *Ticker, user *User)) *MockStorage_DeleteTickerUser_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 *Ticker
+		if args[0] != nil {
+			arg0 = args[0].(*Ticker)
+		}
+		var arg1 *User
+		if args[1] != nil {
+			arg1 = args[1].(*User)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *MockStorage_DeleteTickerUser_Call) Return(err error) *MockStorage_DeleteTickerUser_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *MockStorage_DeleteTickerUser_Call) RunAndReturn(run func(ticker *Ticker, user *User) error) *MockStorage_DeleteTickerUser_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// DeleteTickerUsers provides a mock function for the type MockStorage
+func (_mock *MockStorage) DeleteTickerUsers(ticker *Ticker) error {
+	ret := _mock.Called(ticker)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteTickerUsers")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(*Ticker) error); ok {
+		r0 = returnFunc(ticker)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// MockStorage_DeleteTickerUsers_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'DeleteTickerUsers'
+type MockStorage_DeleteTickerUsers_Call struct {
+	*mock.Call
+}
+
+// DeleteTickerUsers is a helper method to define mock.On call
+//   - ticker *Ticker
+func (_e *MockStorage_Expecter) DeleteTickerUsers(ticker interface{}) *MockStorage_DeleteTickerUsers_Call {
+	return &MockStorage_DeleteTickerUsers_Call{Call: _e.mock.On("DeleteTickerUsers", ticker)}
+}
+
+func (_c *MockStorage_DeleteTickerUsers_Call) Run(run func(ticker *Ticker)) *MockStorage_DeleteTickerUsers_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 *Ticker
+		if args[0] != nil {
+			arg0 = args[0].(*Ticker)
+		}
+		run(
+			arg0,
+		)
+	})
+	return _c
+}
+
+func (_c *MockStorage_DeleteTickerUsers_Call) Return(err error) *MockStorage_DeleteTickerUsers_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *MockStorage_DeleteTickerUsers_Call) RunAndReturn(run func(ticker *Ticker) error) *MockStorage_DeleteTickerUsers_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// DeleteTickerWebhooks provides a mock function for the type MockStorage
+func (_mock *MockStorage) DeleteTickerWebhooks(ticker *Ticker) error {
+	ret := _mock.Called(ticker)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteTickerWebhooks")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(*Ticker) error); ok {
+		r0 = returnFunc(ticker)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// MockStorage_DeleteTickerWebhooks_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'DeleteTickerWebhooks'
+type MockStorage_DeleteTickerWebhooks_Call struct {
+	*mock.Call
+}
+
+// DeleteTickerWebhooks is a helper method to define mock.On call
+//   - ticker *Ticker
+func (_e *MockStorage_Expecter) DeleteTickerWebhooks(ticker interface{}) *MockStorage_DeleteTickerWebhooks_Call {
+	return &MockStorage_DeleteTickerWebhooks_Call{Call: _e.mock.On("DeleteTickerWebhooks", ticker)}
+}
+
+func (_c *MockStorage_DeleteTickerWebhooks_Call) Run(run func(ticker *Ticker)) *MockStorage_DeleteTickerWebhooks_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 *Ticker
+		if args[0] != nil {
+			arg0 = args[0].(*Ticker)
+		}
+		run(
+			arg0,
+		)
+	})
+	return _c
+}
+
+func (_c *MockStorage_DeleteTickerWebhooks_Call) Return(err error) *MockStorage_DeleteTickerWebhooks_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *MockStorage_DeleteTickerWebhooks_Call) RunAndReturn(run func(ticker *Ticker) error) *MockStorage_DeleteTickerWebhooks_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// DeleteTickerWebsites provides a mock function for the type MockStorage
+func (_mock *MockStorage) DeleteTickerWebsites(ticker *Ticker) error {
+	ret := _mock.Called(ticker)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteTickerWebsites")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(*Ticker) error); ok {
+		r0 = returnFunc(ticker)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// MockStorage_DeleteTickerWebsites_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'DeleteTickerWebsites'
+type MockStorage_DeleteTickerWebsites_Call struct {
+	*mock.Call
+}
+
+// DeleteTickerWebsites is a helper method to define mock.On call
+//   - ticker *Ticker
+func (_e *MockStorage_Expecter) DeleteTickerWebsites(ticker interface{}) *MockStorage_DeleteTickerWebsites_Call {
+	return &MockStorage_DeleteTickerWebsites_Call{Call: _e.mock.On("DeleteTickerWebsites", ticker)}
+}
+
+func (_c *MockStorage_DeleteTickerWebsites_Call) Run(run func(ticker *Ticker)) *MockStorage_DeleteTickerWebsites_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 *Ticker
+		if args[0] != nil {
+			arg0 = args[0].(*Ticker)
+		}
+		run(
+			arg0,
+		)
+	})
+	return _c
+}
+
+func (_c *MockStorage_DeleteTickerWebsites_Call) Return(err error) *MockStorage_DeleteTickerWebsites_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *MockStorage_DeleteTickerWebsites_Call) RunAndReturn(run func(ticker *Ticker) error) *MockStorage_DeleteTickerWebsites_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// DeleteUpload provides a mock function for the type MockStorage
+func (_mock *MockStorage) DeleteUpload(upload Upload) error {
+	ret := _mock.Called(upload)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteUpload")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(Upload) error); ok {
+		r0 = returnFunc(upload)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// MockStorage_DeleteUpload_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'DeleteUpload'
+type MockStorage_DeleteUpload_Call struct {
+	*mock.Call
+}
+
+// DeleteUpload is a helper method to define mock.On call
+//   - upload Upload
+func (_e *MockStorage_Expecter) DeleteUpload(upload interface{}) *MockStorage_DeleteUpload_Call {
+	return &MockStorage_DeleteUpload_Call{Call: _e.mock.On("DeleteUpload", upload)}
+}
+
+func (_c *MockStorage_DeleteUpload_Call) Run(run func(upload Upload)) *MockStorage_DeleteUpload_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 Upload
+		if args[0] != nil {
+			arg0 = args[0].(Upload)
+		}
+		run(
+			arg0,
+		)
+	})
+	return _c
+}
+
+func (_c *MockStorage_DeleteUpload_Call) Return(err error) *MockStorage_DeleteUpload_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *MockStorage_DeleteUpload_Call) RunAndReturn(run func(upload Upload) error) *MockStorage_DeleteUpload_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// DeleteUploads provides a mock function for the type MockStorage
+func (_mock *MockStorage) DeleteUploads(uploads []Upload) {
+	_mock.Called(uploads)
+	return
+}
+
+// MockStorage_DeleteUploads_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'DeleteUploads'
+type MockStorage_DeleteUploads_Call struct {
+	*mock.Call
+}
+
+// DeleteUploads is a helper method to define mock.On call
+//   - uploads []Upload
+func (_e *MockStorage_Expecter) DeleteUploads(uploads interface{}) *MockStorage_DeleteUploads_Call {
+	return &MockStorage_DeleteUploads_Call{Call: _e.mock.On("DeleteUploads", uploads)}
+}
+
+func (_c *MockStorage_DeleteUploads_Call) Run(run func(uploads []Upload)) *MockStorage_DeleteUploads_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 []Upload
+		if args[0] != nil {
+			arg0 = args[0].([]Upload)
+		}
+		run(
+			arg0,
+		)
+	})
+	return _c
+}
+
+func (_c *MockStorage_DeleteUploads_Call) Return() *MockStorage_DeleteUploads_Call {
+	_c.Call.Return()
+	return _c
+}
+
+func (_c *MockStorage_DeleteUploads_Call) RunAndReturn(run func(uploads []Upload)) *MockStorage_DeleteUploads_Call {
+	_c.Run(run)
+	return _c
+}
+
+// DeleteUploadsByTicker provides a mock function for the type MockStorage
+func (_mock *MockStorage) DeleteUploadsByTicker(ticker *Ticker) error {
+	ret := _mock.Called(ticker)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteUploadsByTicker")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(*Ticker) error); ok {
+		r0 = returnFunc(ticker)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// MockStorage_DeleteUploadsByTicker_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'DeleteUploadsByTicker'
+type MockStorage_DeleteUploadsByTicker_Call struct {
+	*mock.Call
+}
+
+// DeleteUploadsByTicker is a helper method to define mock.On call
+//   - ticker *Ticker
+func (_e *MockStorage_Expecter) DeleteUploadsByTicker(ticker interface{}) *MockStorage_DeleteUploadsByTicker_Call {
+	return &MockStorage_DeleteUploadsByTicker_Call{Call: _e.mock.On("DeleteUploadsByTicker", ticker)}
+}
+
+func (_c *MockStorage_DeleteUploadsByTicker_Call) Run(run func(ticker *Ticker)) *MockStorage_DeleteUploadsByTicker_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 *Ticker
+		if args[0] != nil {
+			arg0 = args[0].(*Ticker)
+		}
+		run(
+			arg0,
+		)
+	})
+	return _c
+}
+
+func (_c *MockStorage_DeleteUploadsByTicker_Call) Return(err error) *MockStorage_DeleteUploadsByTicker_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *MockStorage_DeleteUploadsByTicker_Call) RunAndReturn(run func(ticker *Ticker) error) *MockStorage_DeleteUploadsByTicker_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// DeleteUser provides a mock function for the type MockStorage
+func (_mock *MockStorage) DeleteUser(user User) error {
+	ret := _mock.Called(user)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteUser")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(User) error); ok {
+		r0 = returnFunc(user)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// MockStorage_DeleteUser_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'DeleteUser'
+type MockStorage_DeleteUser_Call struct {
+	*mock.Call
+}
+
+// DeleteUser is a helper method to define mock.On call
+//   - user User
+func (_e *MockStorage_Expecter) DeleteUser(user interface{}) *MockStorage_DeleteUser_Call {
+	return &MockStorage_DeleteUser_Call{Call: _e.mock.On("DeleteUser", user)}
+}
+
+func (_c *MockStorage_DeleteUser_Call) Run(run func(user User)) *MockStorage_DeleteUser_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 User
+		if args[0] != nil {
+			arg0 = args[0].(User)
+		}
+		run(
+			arg0,
+		)
+	})
+	return _c
+}
+
+func (_c *MockStorage_DeleteUser_Call) Return(err error) *MockStorage_DeleteUser_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *MockStorage_DeleteUser_Call) RunAndReturn(run func(user User) error) *MockStorage_DeleteUser_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// DeleteWebAuthnCredential provides a mock function for the type MockStorage
+func (_mock *MockStorage) DeleteWebAuthnCredential(credential WebAuthnCredential) error {
+	ret := _mock.Called(credential)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteWebAuthnCredential")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(WebAuthnCredential) error); ok {
+		r0 = returnFunc(credential)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// MockStorage_DeleteWebAuthnCredential_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'DeleteWebAuthnCredential'
+type MockStorage_DeleteWebAuthnCredential_Call struct {
+	*mock.Call
+}
+
+// DeleteWebAuthnCredential is a helper method to define mock.On call
+//   - credential WebAuthnCredential
+func (_e *MockStorage_Expecter) DeleteWebAuthnCredential(credential interface{}) *MockStorage_DeleteWebAuthnCredential_Call {
+	return &MockStorage_DeleteWebAuthnCredential_Call{Call: _e.mock.On("DeleteWebAuthnCredential", credential)}
+}
+
+func (_c *MockStorage_DeleteWebAuthnCredential_Call) Run(run func(credential WebAuthnCredential)) *MockStorage_DeleteWebAuthnCredential_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 WebAuthnCredential
+		if args[0] != nil {
+			arg0 = args[0].(WebAuthnCredential)
+		}
+		run(
+			arg0,
+		)
+	})
+	return _c
+}
+
+func (_c *MockStorage_DeleteWebAuthnCredential_Call) Return(err error) *MockStorage_DeleteWebAuthnCredential_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *MockStorage_DeleteWebAuthnCredential_Call) RunAndReturn(run func(credential WebAuthnCredential) error) *MockStorage_DeleteWebAuthnCredential_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// DeleteWebAuthnCredentials provides a mock function for the type MockStorage
+func (_mock *MockStorage) DeleteWebAuthnCredentials(user User) error {
+	ret := _mock.Called(user)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteWebAuthnCredentials")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(User) error); ok {
+		r0 = returnFunc(user)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// MockStorage_DeleteWebAuthnCredentials_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'DeleteWebAuthnCredentials'
+type MockStorage_DeleteWebAuthnCredentials_Call struct {
+	*mock.Call
+}
+
+// DeleteWebAuthnCredentials is a helper method to define mock.On call
+//   - user User
+func (_e *MockStorage_Expecter) DeleteWebAuthnCredentials(user interface{}) *MockStorage_DeleteWebAuthnCredentials_Call {
+	return &MockStorage_DeleteWebAuthnCredentials_Call{Call: _e.mock.On("DeleteWebAuthnCredentials", user)}
+}
+
+func (_c *MockStorage_DeleteWebAuthnCredentials_Call) Run(run func(user User)) *MockStorage_DeleteWebAuthnCredentials_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 User
+		if args[0] != nil {
+			arg0 = args[0].(User)
+		}
+		run(
+			arg0,
+		)
+	})
+	return _c
+}
+
+func (_c *MockStorage_DeleteWebAuthnCredentials_Call) Return(err error) *MockStorage_DeleteWebAuthnCredentials_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *MockStorage_DeleteWebAuthnCredentials_Call) RunAndReturn(run func(user User) error) *MockStorage_DeleteWebAuthnCredentials_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// DeleteWebPush provides a mock function for the type MockStorage
+func (_mock *MockStorage) DeleteWebPush(ticker *Ticker) error {
+	ret := _mock.Called(ticker)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteWebPush")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(*Ticker) error); ok {
+		r0 = returnFunc(ticker)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// MockStorage_DeleteWebPush_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'DeleteWebPush'
+type MockStorage_DeleteWebPush_Call struct {
+	*mock.Call
+}
+
+// DeleteWebPush is a helper method to define mock.On call
+//   - ticker *Ticker
+func (_e *MockStorage_Expecter) DeleteWebPush(ticker interface{}) *MockStorage_DeleteWebPush_Call {
+	return &MockStorage_DeleteWebPush_Call{Call: _e.mock.On("DeleteWebPush", ticker)}
+}
+
+func (_c *MockStorage_DeleteWebPush_Call) Run(run func(ticker *Ticker)) *MockStorage_DeleteWebPush_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 *Ticker
+		if args[0] != nil {
+			arg0 = args[0].(*Ticker)
+		}
+		run(
+			arg0,
+		)
+	})
+	return _c
+}
+
+func (_c *MockStorage_DeleteWebPush_Call) Return(err error) *MockStorage_DeleteWebPush_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *MockStorage_DeleteWebPush_Call) RunAndReturn(run func(ticker *Ticker) error) *MockStorage_DeleteWebPush_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// DeleteWebPushSubscription provides a mock function for the type MockStorage
+func (_mock *MockStorage) DeleteWebPushSubscription(ticker Ticker, endpoint string) error {
+	ret := _mock.Called(ticker, endpoint)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteWebPushSubscription")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(Ticker, string) error); ok {
+		r0 = returnFunc(ticker, endpoint)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// MockStorage_DeleteWebPushSubscription_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'DeleteWebPushSubscription'
+type MockStorage_DeleteWebPushSubscription_Call struct {
+	*mock.Call
+}
+
+// DeleteWebPushSubscription is a helper method to define mock.On call
+//   - ticker Ticker
+//   - endpoint string
+func (_e *MockStorage_Expecter) DeleteWebPushSubscription(ticker interface{}, endpoint interface{}) *MockStorage_DeleteWebPushSubscription_Call {
+	return &MockStorage_DeleteWebPushSubscription_Call{Call: _e.mock.On("DeleteWebPushSubscription", ticker, endpoint)}
+}
+
+func (_c *MockStorage_DeleteWebPushSubscription_Call) Run(run func(ticker Ticker, endpoint string)) *MockStorage_DeleteWebPushSubscription_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 Ticker
+		if args[0] != nil {
+			arg0 = args[0].(Ticker)
+		}
+		var arg1 string
+		if args[1] != nil {
+			arg1 = args[1].(string)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *MockStorage_DeleteWebPushSubscription_Call) Return(err error) *MockStorage_DeleteWebPushSubscription_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *MockStorage_DeleteWebPushSubscription_Call) RunAndReturn(run func(ticker Ticker, endpoint string) error) *MockStorage_DeleteWebPushSubscription_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// DisableTOTP provides a mock function for the type MockStorage
+func (_mock *MockStorage) DisableTOTP(user *User) error {
+	ret := _mock.Called(user)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DisableTOTP")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(*User) error); ok {
+		r0 = returnFunc(user)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// MockStorage_DisableTOTP_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'DisableTOTP'
+type MockStorage_DisableTOTP_Call struct {
+	*mock.Call
+}
+
+// DisableTOTP is a helper method to define mock.On call
+//   - user *User
+func (_e *MockStorage_Expecter) DisableTOTP(user interface{}) *MockStorage_DisableTOTP_Call {
+	return &MockStorage_DisableTOTP_Call{Call: _e.mock.On("DisableTOTP", user)}
+}
+
+func (_c *MockStorage_DisableTOTP_Call) Run(run func(user *User)) *MockStorage_DisableTOTP_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 *User
+		if args[0] != nil {
+			arg0 = args[0].(*User)
+		}
+		run(
+			arg0,
+		)
+	})
+	return _c
+}
+
+func (_c *MockStorage_DisableTOTP_Call) Return(err error) *MockStorage_DisableTOTP_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *MockStorage_DisableTOTP_Call) RunAndReturn(run func(user *User) error) *MockStorage_DisableTOTP_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// EnableTOTP provides a mock function for the type MockStorage
+func (_mock *MockStorage) EnableTOTP(user *User, step int64, codes []RecoveryCode) error {
+	ret := _mock.Called(user, step, codes)
+
+	if len(ret) == 0 {
+		panic("no return value specified for EnableTOTP")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(*User, int64, []RecoveryCode) error); ok {
+		r0 = returnFunc(user, step, codes)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// MockStorage_EnableTOTP_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'EnableTOTP'
+type MockStorage_EnableTOTP_Call struct {
+	*mock.Call
+}
+
+// EnableTOTP is a helper method to define mock.On call
+//   - user *User
+//   - step int64
+//   - codes []RecoveryCode
+func (_e *MockStorage_Expecter) EnableTOTP(user interface{}, step interface{}, codes interface{}) *MockStorage_EnableTOTP_Call {
+	return &MockStorage_EnableTOTP_Call{Call: _e.mock.On("EnableTOTP", user, step, codes)}
+}
+
+func (_c *MockStorage_EnableTOTP_Call) Run(run func(user *User, step int64, codes []RecoveryCode)) *MockStorage_EnableTOTP_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 *User
+		if args[0] != nil {
+			arg0 = args[0].(*User)
+		}
+		var arg1 int64
+		if args[1] != nil {
+			arg1 = args[1].(int64)
+		}
+		var arg2 []RecoveryCode
+		if args[2] != nil {
+			arg2 = args[2].([]RecoveryCode)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+		)
+	})
+	return _c
+}
+
+func (_c *MockStorage_EnableTOTP_Call) Return(err error) *MockStorage_EnableTOTP_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *MockStorage_EnableTOTP_Call) RunAndReturn(run func(user *User, step int64, codes []RecoveryCode) error) *MockStorage_EnableTOTP_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// FindAPITokenByHash provides a mock function for the type MockStorage
+func (_mock *MockStorage) FindAPITokenByHash(hash string) (APIToken, error) {
+	ret := _mock.Called(hash)
+
+	if len(ret) == 0 {
+		panic("no return value specified for FindAPITokenByHash")
+	}
+
+	var r0 APIToken
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(string) (APIToken, error)); ok {
+		return returnFunc(hash)
+	}
+	if returnFunc, ok := ret.Get(0).(func(string) APIToken); ok {
+		r0 = returnFunc(hash)
+	} else {
+		r0 = ret.Get(0).(APIToken)
+	}
+	if returnFunc, ok := ret.Get(1).(func(string) error); ok {
+		r1 = returnFunc(hash)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockStorage_FindAPITokenByHash_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'FindAPITokenByHash'
+type MockStorage_FindAPITokenByHash_Call struct {
+	*mock.Call
+}
+
+// FindAPITokenByHash is a helper method to define mock.On call
+//   - hash string
+func (_e *MockStorage_Expecter) FindAPITokenByHash(hash interface{}) *MockStorage_FindAPITokenByHash_Call {
+	return &MockStorage_FindAPITokenByHash_Call{Call: _e.mock.On("FindAPITokenByHash", hash)}
+}
+
+func (_c *MockStorage_FindAPITokenByHash_Call) Run(run func(hash string)) *MockStorage_FindAPITokenByHash_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 string
+		if args[0] != nil {
+			arg0 = args[0].(string)
+		}
+		run(
+			arg0,
+		)
+	})
+	return _c
+}
+
+func (_c *MockStorage_FindAPITokenByHash_Call) Return(aPIToken APIToken, err error) *MockStorage_FindAPITokenByHash_Call {
+	_c.Call.Return(aPIToken, err)
+	return _c
+}
+
+func (_c *MockStorage_FindAPITokenByHash_Call) RunAndReturn(run func(hash string) (APIToken, error)) *MockStorage_FindAPITokenByHash_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// FindAPITokenByID provides a mock function for the type MockStorage
+func (_mock *MockStorage) FindAPITokenByID(user User, id int) (APIToken, error) {
+	ret := _mock.Called(user, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for FindAPITokenByID")
+	}
+
+	var r0 APIToken
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(User, int) (APIToken, error)); ok {
+		return returnFunc(user, id)
+	}
+	if returnFunc, ok := ret.Get(0).(func(User, int) APIToken); ok {
+		r0 = returnFunc(user, id)
+	} else {
+		r0 = ret.Get(0).(APIToken)
+	}
+	if returnFunc, ok := ret.Get(1).(func(User, int) error); ok {
+		r1 = returnFunc(user, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockStorage_FindAPITokenByID_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'FindAPITokenByID'
+type MockStorage_FindAPITokenByID_Call struct {
+	*mock.Call
+}
+
+// FindAPITokenByID is a helper method to define mock.On call
+//   - user User
+//   - id int
+func (_e *MockStorage_Expecter) FindAPITokenByID(user interface{}, id interface{}) *MockStorage_FindAPITokenByID_Call {
+	return &MockStorage_FindAPITokenByID_Call{Call: _e.mock.On("FindAPITokenByID", user, id)}
+}
+
+func (_c *MockStorage_FindAPITokenByID_Call) Run(run func(user User, id int)) *MockStorage_FindAPITokenByID_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 User
+		if args[0] != nil {
+			arg0 = args[0].(User)
+		}
+		var arg1 int
+		if args[1] != nil {
+			arg1 = args[1].(int)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *MockStorage_FindAPITokenByID_Call) Return(aPIToken APIToken, err error) *MockStorage_FindAPITokenByID_Call {
+	_c.Call.Return(aPIToken, err)
+	return _c
+}
+
+func (_c *MockStorage_FindAPITokenByID_Call) RunAndReturn(run func(user User, id int) (APIToken, error)) *MockStorage_FindAPITokenByID_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// FindAPITokens provides a mock function for the type MockStorage
+func (_mock *MockStorage) FindAPITokens(user User) ([]APIToken, error) {
+	ret := _mock.Called(user)
+
+	if len(ret) == 0 {
+		panic("no return value specified for FindAPITokens")
+	}
+
+	var r0 []APIToken
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(User) ([]APIToken, error)); ok {
+		return returnFunc(user)
+	}
+	if returnFunc, ok := ret.Get(0).(func(User) []APIToken); ok {
+		r0 = returnFunc(user)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]APIToken)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(User) error); ok {
+		r1 = returnFunc(user)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockStorage_FindAPITokens_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'FindAPITokens'
+type MockStorage_FindAPITokens_Call struct {
+	*mock.Call
+}
+
+// FindAPITokens is a helper method to define mock.On call
+//   - user User
+func (_e *MockStorage_Expecter) FindAPITokens(user interface{}) *MockStorage_FindAPITokens_Call {
+	return &MockStorage_FindAPITokens_Call{Call: _e.mock.On("FindAPITokens", user)}
+}
+
+func (_c *MockStorage_FindAPITokens_Call) Run(run func(user User)) *MockStorage_FindAPITokens_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 User
+		if args[0] != nil {
+			arg0 = args[0].(User)
+		}
+		run(
+			arg0,
+		)
+	})
+	return _c
+}
+
+func (_c *MockStorage_FindAPITokens_Call) Return(aPITokens []APIToken, err error) *MockStorage_FindAPITokens_Call {
+	_c.Call.Return(aPITokens, err)
+	return _c
+}
+
+func (_c *MockStorage_FindAPITokens_Call) RunAndReturn(run func(user User) ([]APIToken, error)) *MockStorage_FindAPITokens_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// FindActivityPubFollowers provides a mock function for the type MockStorage
+func (_mock *MockStorage) FindActivityPubFollowers(ticker Ticker) ([]ActivityPubFollower, error) {
+	ret := _mock.Called(ticker)
+
+	if len(ret) == 0 {
+		panic("no return value specified for FindActivityPubFollowers")
+	}
+
+	var r0 []ActivityPubFollower
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(Ticker) ([]ActivityPubFollower, error)); ok {
+		return returnFunc(ticker)
+	}
+	if returnFunc, ok := ret.Get(0).(func(Ticker) []ActivityPubFollower); ok {
+		r0 = returnFunc(ticker)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]ActivityPubFollower)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(Ticker) error); ok {
+		r1 = returnFunc(ticker)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockStorage_FindActivityPubFollowers_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'FindActivityPubFollowers'
+type MockStorage_FindActivityPubFollowers_Call struct {
+	*mock.Call
+}
+
+// FindActivityPubFollowers is a helper method to define mock.On call
+//   - ticker Ticker
+func (_e *MockStorage_Expecter) FindActivityPubFollowers(ticker interface{}) *MockStorage_FindActivityPubFollowers_Call {
+	return &MockStorage_FindActivityPubFollowers_Call{Call: _e.mock.On("FindActivityPubFollowers", ticker)}
+}
+
+func (_c *MockStorage_FindActivityPubFollowers_Call) Run(run func(ticker Ticker)) *MockStorage_FindActivityPubFollowers_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 Ticker
+		if args[0] != nil {
+			arg0 = args[0].(Ticker)
+		}
+		run(
+			arg0,
+		)
+	})
+	return _c
+}
+
+func (_c *MockStorage_FindActivityPubFollowers_Call) Return(activityPubFollowers []ActivityPubFollower, err error) *MockStorage_FindActivityPubFollowers_Call {
+	_c.Call.Return(activityPubFollowers, err)
+	return _c
+}
+
+func (_c *MockStorage_FindActivityPubFollowers_Call) RunAndReturn(run func(ticker Ticker) ([]ActivityPubFollower, error)) *MockStorage_FindActivityPubFollowers_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// FindConfirmedEmailSubscriptions provides a mock function for the type MockStorage
+func (_mock *MockStorage) FindConfirmedEmailSubscriptions(ticker Ticker) ([]EmailSubscription, error) {
+	ret := _mock.Called(ticker)
+
+	if len(ret) == 0 {
+		panic("no return value specified for FindConfirmedEmailSubscriptions")
+	}
+
+	var r0 []EmailSubscription
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(Ticker) ([]EmailSubscription, error)); ok {
+		return returnFunc(ticker)
+	}
+	if returnFunc, ok := ret.Get(0).(func(Ticker) []EmailSubscription); ok {
+		r0 = returnFunc(ticker)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]EmailSubscription)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(Ticker) error); ok {
+		r1 = returnFunc(ticker)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockStorage_FindConfirmedEmailSubscriptions_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'FindConfirmedEmailSubscriptions'
+type MockStorage_FindConfirmedEmailSubscriptions_Call struct {
+	*mock.Call
+}
+
+// FindConfirmedEmailSubscriptions is a helper method to define mock.On call
+//   - ticker Ticker
+func (_e *MockStorage_Expecter) FindConfirmedEmailSubscriptions(ticker interface{}) *MockStorage_FindConfirmedEmailSubscriptions_Call {
+	return &MockStorage_FindConfirmedEmailSubscriptions_Call{Call: _e.mock.On("FindConfirmedEmailSubscriptions", ticker)}
+}
+
+func (_c *MockStorage_FindConfirmedEmailSubscriptions_Call) Run(run func(ticker Ticker)) *MockStorage_FindConfirmedEmailSubscriptions_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 Ticker
+		if args[0] != nil {
+			arg0 = args[0].(Ticker)
+		}
+		run(
+			arg0,
+		)
+	})
+	return _c
+}
+
+func (_c *MockStorage_FindConfirmedEmailSubscriptions_Call) Return(emailSubscriptions []EmailSubscription, err error) *MockStorage_FindConfirmedEmailSubscriptions_Call {
+	_c.Call.Return(emailSubscriptions, err)
+	return _c
+}
+
+func (_c *MockStorage_FindConfirmedEmailSubscriptions_Call) RunAndReturn(run func(ticker Ticker) ([]EmailSubscription, error)) *MockStorage_FindConfirmedEmailSubscriptions_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// FindDraftMessagesByTicker provides a mock function for the type MockStorage
+func (_mock *MockStorage) FindDraftMessagesByTicker(ticker Ticker, opts ...func(*gorm.DB) *gorm.DB) ([]Message, error) {
+	var tmpRet mock.Arguments
+	if len(opts) > 0 {
+		tmpRet = _mock.Called(ticker, opts)
+	} else {
+		tmpRet = _mock.Called(ticker)
+	}
+	ret := tmpRet
+
+	if len(ret) == 0 {
+		panic("no return value specified for FindDraftMessagesByTicker")
+	}
+
+	var r0 []Message
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(Ticker, ...func(*gorm.DB) *gorm.DB) ([]Message, error)); ok {
+		return returnFunc(ticker, opts...)
+	}
+	if returnFunc, ok := ret.Get(0).(func(Ticker, ...func(*gorm.DB) *gorm.DB) []Message); ok {
+		r0 = returnFunc(ticker, opts...)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]Message)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(Ticker, ...func(*gorm.DB) *gorm.DB) error); ok {
+		r1 = returnFunc(ticker, opts...)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockStorage_FindDraftMessagesByTicker_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'FindDraftMessagesByTicker'
+type MockStorage_FindDraftMessagesByTicker_Call struct {
+	*mock.Call
+}
+
+// FindDraftMessagesByTicker is a helper method to define mock.On call
+//   - ticker Ticker
+//   - opts ...func(*gorm.DB) *gorm.DB
+func (_e *MockStorage_Expecter) FindDraftMessagesByTicker(ticker interface{}, opts ...interface{}) *MockStorage_FindDraftMessagesByTicker_Call {
+	return &MockStorage_FindDraftMessagesByTicker_Call{Call: _e.mock.On("FindDraftMessagesByTicker",
+		append([]interface{}{ticker}, opts...)...)}
+}
+
+func (_c *MockStorage_FindDraftMessagesByTicker_Call) Run(run func(ticker Ticker, opts ...func(*gorm.DB) *gorm.DB)) *MockStorage_FindDraftMessagesByTicker_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 Ticker
+		if args[0] != nil {
+			arg0 = args[0].(Ticker)
+		}
+		var arg1 []func(*gorm.DB) *gorm.DB
+		var variadicArgs []func(*gorm.DB) *gorm.DB
+		if len(args) > 1 {
+			variadicArgs = args[1].([]func(*gorm.DB) *gorm.DB)
+		}
+		arg1 = variadicArgs
+		run(
+			arg0,
+			arg1...,
+		)
+	})
+	return _c
+}
+
+func (_c *MockStorage_FindDraftMessagesByTicker_Call) Return(messages []Message, err error) *MockStorage_FindDraftMessagesByTicker_Call {
+	_c.Call.Return(messages, err)
+	return _c
+}
+
+func (_c *MockStorage_FindDraftMessagesByTicker_Call) RunAndReturn(run func(ticker Ticker, opts ...func(*gorm.DB) *gorm.DB) ([]Message, error)) *MockStorage_FindDraftMessagesByTicker_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// FindDueEmailDigests provides a mock function for the type MockStorage
+func (_mock *MockStorage) FindDueEmailDigests(before time.Time) ([]int, error) {
+	ret := _mock.Called(before)
+
+	if len(ret) == 0 {
+		panic("no return value specified for FindDueEmailDigests")
+	}
+
+	var r0 []int
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(time.Time) ([]int, error)); ok {
+		return returnFunc(before)
+	}
+	if returnFunc, ok := ret.Get(0).(func(time.Time) []int); ok {
+		r0 = returnFunc(before)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]int)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(time.Time) error); ok {
+		r1 = returnFunc(before)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockStorage_FindDueEmailDigests_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'FindDueEmailDigests'
+type MockStorage_FindDueEmailDigests_Call struct {
+	*mock.Call
+}
+
+// FindDueEmailDigests is a helper method to define mock.On call
+//   - before time.Time
+func (_e *MockStorage_Expecter) FindDueEmailDigests(before interface{}) *MockStorage_FindDueEmailDigests_Call {
+	return &MockStorage_FindDueEmailDigests_Call{Call: _e.mock.On("FindDueEmailDigests", before)}
+}
+
+func (_c *MockStorage_FindDueEmailDigests_Call) Run(run func(before time.Time)) *MockStorage_FindDueEmailDigests_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 time.Time
+		if args[0] != nil {
+			arg0 = args[0].(time.Time)
+		}
+		run(
+			arg0,
+		)
+	})
+	return _c
+}
+
+func (_c *MockStorage_FindDueEmailDigests_Call) Return(ints []int, err error) *MockStorage_FindDueEmailDigests_Call {
+	_c.Call.Return(ints, err)
+	return _c
+}
+
+func (_c *MockStorage_FindDueEmailDigests_Call) RunAndReturn(run func(before time.Time) ([]int, error)) *MockStorage_FindDueEmailDigests_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// FindDueMessages provides a mock function for the type MockStorage
+func (_mock *MockStorage) FindDueMessages(now time.Time, opts ...func(*gorm.DB) *gorm.DB) ([]Message, error) {
+	var tmpRet mock.Arguments
+	if len(opts) > 0 {
+		tmpRet = _mock.Called(now, opts)
+	} else {
+		tmpRet = _mock.Called(now)
+	}
+	ret := tmpRet
+
+	if len(ret) == 0 {
+		panic("no return value specified for FindDueMessages")
+	}
+
+	var r0 []Message
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(time.Time, ...func(*gorm.DB) *gorm.DB) ([]Message, error)); ok {
+		return returnFunc(now, opts...)
+	}
+	if returnFunc, ok := ret.Get(0).(func(time.Time, ...func(*gorm.DB) *gorm.DB) []Message); ok {
+		r0 = returnFunc(now, opts...)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]Message)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(time.Time, ...func(*gorm.DB) *gorm.DB) error); ok {
+		r1 = returnFunc(now, opts...)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockStorage_FindDueMessages_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'FindDueMessages'
+type MockStorage_FindDueMessages_Call struct {
+	*mock.Call
+}
+
+// FindDueMessages is a helper method to define mock.On call
+//   - now time.Time
+//   - opts ...func(*gorm.DB) *gorm.DB
+func (_e *MockStorage_Expecter) FindDueMessages(now interface{}, opts ...interface{}) *MockStorage_FindDueMessages_Call {
+	return &MockStorage_FindDueMessages_Call{Call: _e.mock.On("FindDueMessages",
+		append([]interface{}{now}, opts...)...)}
+}
+
+func (_c *MockStorage_FindDueMessages_Call) Run(run func(now time.Time, opts ...func(*gorm.DB) *gorm.DB)) *MockStorage_FindDueMessages_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 time.Time
+		if args[0] != nil {
+			arg0 = args[0].(time.Time)
+		}
+		var arg1 []func(*gorm.DB) *gorm.DB
+		var variadicArgs []func(*gorm.DB) *gorm.DB
+		if len(args) > 1 {
+			variadicArgs = args[1].([]func(*gorm.DB) *gorm.DB)
+		}
+		arg1 = variadicArgs
+		run(
+			arg0,
+			arg1...,
+		)
+	})
+	return _c
+}
+
+func (_c *MockStorage_FindDueMessages_Call) Return(messages []Message, err error) *MockStorage_FindDueMessages_Call {
+	_c.Call.Return(messages, err)
+	return _c
+}
+
+func (_c *MockStorage_FindDueMessages_Call) RunAndReturn(run func(now time.Time, opts ...func(*gorm.DB) *gorm.DB) ([]Message, error)) *MockStorage_FindDueMessages_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// FindEmailDigestMessages provides a mock function for the type MockStorage
+func (_mock *MockStorage) FindEmailDigestMessages(ticker Ticker, until time.Time) ([]Message, error) {
+	ret := _mock.Called(ticker, until)
+
+	if len(ret) == 0 {
+		panic("no return value specified for FindEmailDigestMessages")
+	}
+
+	var r0 []Message
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(Ticker, time.Time) ([]Message, error)); ok {
+		return returnFunc(ticker, until)
+	}
+	if returnFunc, ok := ret.Get(0).(func(Ticker, time.Time) []Message); ok {
+		r0 = returnFunc(ticker, until)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]Message)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(Ticker, time.Time) error); ok {
+		r1 = returnFunc(ticker, until)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockStorage_FindEmailDigestMessages_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'FindEmailDigestMessages'
+type MockStorage_FindEmailDigestMessages_Call struct {
+	*mock.Call
+}
+
+// FindEmailDigestMessages is a helper method to define mock.On call
+//   - ticker Ticker
+//   - until time.Time
+func (_e *MockStorage_Expecter) FindEmailDigestMessages(ticker interface{}, until interface{}) *MockStorage_FindEmailDigestMessages_Call {
+	return &MockStorage_FindEmailDigestMessages_Call{Call: _e.mock.On("FindEmailDigestMessages", ticker, until)}
+}
+
+func (_c *MockStorage_FindEmailDigestMessages_Call) Run(run func(ticker Ticker, until time.Time)) *MockStorage_FindEmailDigestMessages_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 Ticker
+		if args[0] != nil {
+			arg0 = args[0].(Ticker)
+		}
+		var arg1 time.Time
+		if args[1] != nil {
+			arg1 = args[1].(time.Time)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *MockStorage_FindEmailDigestMessages_Call) Return(messages []Message, err error) *MockStorage_FindEmailDigestMessages_Call {
+	_c.Call.Return(messages, err)
+	return _c
+}
+
+func (_c *MockStorage_FindEmailDigestMessages_Call) RunAndReturn(run func(ticker Ticker, until time.Time) ([]Message, error)) *MockStorage_FindEmailDigestMessages_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// FindEmailSubscription provides a mock function for the type MockStorage
+func (_mock *MockStorage) FindEmailSubscription(ticker Ticker, email string) (EmailSubscription, error) {
+	ret := _mock.Called(ticker, email)
+
+	if len(ret) == 0 {
+		panic("no return value specified for FindEmailSubscription")
+	}
+
+	var r0 EmailSubscription
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(Ticker, string) (EmailSubscription, error)); ok {
+		return returnFunc(ticker, email)
+	}
+	if returnFunc, ok := ret.Get(0).(func(Ticker, string) EmailSubscription); ok {
+		r0 = returnFunc(ticker, email)
+	} else {
+		r0 = ret.Get(0).(EmailSubscription)
+	}
+	if returnFunc, ok := ret.Get(1).(func(Ticker, string) error); ok {
+		r1 = returnFunc(ticker, email)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockStorage_FindEmailSubscription_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'FindEmailSubscription'
+type MockStorage_FindEmailSubscription_Call struct {
+	*mock.Call
+}
+
+// FindEmailSubscription is a helper method to define mock.On call
+//   - ticker Ticker
+//   - email string
+func (_e *MockStorage_Expecter) FindEmailSubscription(ticker interface{}, email interface{}) *MockStorage_FindEmailSubscription_Call {
+	return &MockStorage_FindEmailSubscription_Call{Call: _e.mock.On("FindEmailSubscription", ticker, email)}
+}
+
+func (_c *MockStorage_FindEmailSubscription_Call) Run(run func(ticker Ticker, email string)) *MockStorage_FindEmailSubscription_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 Ticker
+		if args[0] != nil {
+			arg0 = args[0].(Ticker)
+		}
+		var arg1 string
+		if args[1] != nil {
+			arg1 = args[1].(string)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *MockStorage_FindEmailSubscription_Call) Return(emailSubscription EmailSubscription, err error) *MockStorage_FindEmailSubscription_Call {
+	_c.Call.Return(emailSubscription, err)
+	return _c
+}
+
+func (_c *MockStorage_FindEmailSubscription_Call) RunAndReturn(run func(ticker Ticker, email string) (EmailSubscription, error)) *MockStorage_FindEmailSubscription_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// FindEmailSubscriptionByToken provides a mock function for the type MockStorage
+func (_mock *MockStorage) FindEmailSubscriptionByToken(token string) (EmailSubscription, error) {
+	ret := _mock.Called(token)
+
+	if len(ret) == 0 {
+		panic("no return value specified for FindEmailSubscriptionByToken")
+	}
+
+	var r0 EmailSubscription
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(string) (EmailSubscription, error)); ok {
+		return returnFunc(token)
+	}
+	if returnFunc, ok := ret.Get(0).(func(string) EmailSubscription); ok {
+		r0 = returnFunc(token)
+	} else {
+		r0 = ret.Get(0).(EmailSubscription)
+	}
+	if returnFunc, ok := ret.Get(1).(func(string) error); ok {
+		r1 = returnFunc(token)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockStorage_FindEmailSubscriptionByToken_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'FindEmailSubscriptionByToken'
+type MockStorage_FindEmailSubscriptionByToken_Call struct {
+	*mock.Call
+}
+
+// FindEmailSubscriptionByToken is a helper method to define mock.On call
+//   - token string
+func (_e *MockStorage_Expecter) FindEmailSubscriptionByToken(token interface{}) *MockStorage_FindEmailSubscriptionByToken_Call {
+	return &MockStorage_FindEmailSubscriptionByToken_Call{Call: _e.mock.On("FindEmailSubscriptionByToken", token)}
+}
+
+func (_c *MockStorage_FindEmailSubscriptionByToken_Call) Run(run func(token string)) *MockStorage_FindEmailSubscriptionByToken_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 string
+		if args[0] != nil {
+			arg0 = args[0].(string)
+		}
+		run(
+			arg0,
+		)
+	})
+	return _c
+}
+
+func (_c *MockStorage_FindEmailSubscriptionByToken_Call) Return(emailSubscription EmailSubscription, err error) *MockStorage_FindEmailSubscriptionByToken_Call {
+	_c.Call.Return(emailSubscription, err)
+	return _c
+}
+
+func (_c *MockStorage_FindEmailSubscriptionByToken_Call) RunAndReturn(run func(token string) (EmailSubscription, error)) *MockStorage_FindEmailSubscriptionByToken_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// FindMessage provides a mock function for the type MockStorage
+func (_mock *MockStorage) FindMessage(tickerID int, messageID int, opts ...func(*gorm.DB) *gorm.DB) (Message, error) {
+	var tmpRet mock.Arguments
+	if len(opts) > 0 {
+		tmpRet = _mock.Called(tickerID, messageID, opts)
+	} else {
+		tmpRet = _mock.Called(tickerID, messageID)
+	}
+	ret := tmpRet
+
+	if len(ret) == 0 {
+		panic("no return value specified for FindMessage")
+	}
+
+	var r0 Message
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(int, int, ...func(*gorm.DB) *gorm.DB) (Message, error)); ok {
+		return returnFunc(tickerID, messageID, opts...)
+	}
+	if returnFunc, ok := ret.Get(0).(func(int, int, ...func(*gorm.DB) *gorm.DB) Message); ok {
+		r0 = returnFunc(tickerID, messageID, opts...)
+	} else {
+		r0 = ret.Get(0).(Message)
+	}
+	if returnFunc, ok := ret.Get(1).(func(int, int, ...func(*gorm.DB) *gorm.DB) error); ok {
+		r1 = returnFunc(tickerID, messageID, opts...)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockStorage_FindMessage_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'FindMessage'
+type MockStorage_FindMessage_Call struct {
+	*mock.Call
+}
+
+// FindMessage is a helper method to define mock.On call
+//   - tickerID int
+//   - messageID int
+//   - opts ...func(*gorm.DB) *gorm.DB
+func (_e *MockStorage_Expecter) FindMessage(tickerID interface{}, messageID interface{}, opts ...interface{}) *MockStorage_FindMessage_Call {
+	return &MockStorage_FindMessage_Call{Call: _e.mock.On("FindMessage",
+		append([]interface{}{tickerID, messageID}, opts...)...)}
+}
+
+func (_c *MockStorage_FindMessage_Call) Run(run func(tickerID int, messageID int, opts ...func(*gorm.DB) *gorm.DB)) *MockStorage_FindMessage_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 int
+		if args[0] != nil {
+			arg0 = args[0].(int)
+		}
+		var arg1 int
+		if args[1] != nil {
+			arg1 = args[1].(int)
+		}
+		var arg2 []func(*gorm.DB) *gorm.DB
+		var variadicArgs []func(*gorm.DB) *gorm.DB
+		if len(args) > 2 {
+			variadicArgs = args[2].([]func(*gorm.DB) *gorm.DB)
+		}
+		arg2 = variadicArgs
+		run(
+			arg0,
+			arg1,
+			arg2...,
+		)
+	})
+	return _c
+}
+
+func (_c *MockStorage_FindMessage_Call) Return(message Message, err error) *MockStorage_FindMessage_Call {
+	_c.Call.Return(message, err)
+	return _c
+}
+
+func (_c *MockStorage_FindMessage_Call) RunAndReturn(run func(tickerID int, messageID int, opts ...func(*gorm.DB) *gorm.DB) (Message, error)) *MockStorage_FindMessage_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// FindMessageRevisions provides a mock function for the type MockStorage
+func (_mock *MockStorage) FindMessageRevisions(tickerID int, messageID int) ([]MessageRevision, error) {
+	ret := _mock.Called(tickerID, messageID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for FindMessageRevisions")
+	}
+
+	var r0 []MessageRevision
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(int, int) ([]MessageRevision, error)); ok {
+		return returnFunc(tickerID, messageID)
+	}
+	if returnFunc, ok := ret.Get(0).(func(int, int) []MessageRevision); ok {
+		r0 = returnFunc(tickerID, messageID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]MessageRevision)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(int, int) error); ok {
+		r1 = returnFunc(tickerID, messageID)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockStorage_FindMessageRevisions_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'FindMessageRevisions'
+type MockStorage_FindMessageRevisions_Call struct {
+	*mock.Call
+}
+
+// FindMessageRevisions is a helper method to define mock.On call
+//   - tickerID int
+//   - messageID int
+func (_e *MockStorage_Expecter) FindMessageRevisions(tickerID interface{}, messageID interface{}) *MockStorage_FindMessageRevisions_Call {
+	return &MockStorage_FindMessageRevisions_Call{Call: _e.mock.On("FindMessageRevisions", tickerID, messageID)}
+}
+
+func (_c *MockStorage_FindMessageRevisions_Call) Run(run func(tickerID int, messageID int)) *MockStorage_FindMessageRevisions_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 int
+		if args[0] != nil {
+			arg0 = args[0].(int)
+		}
+		var arg1 int
+		if args[1] != nil {
+			arg1 = args[1].(int)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *MockStorage_FindMessageRevisions_Call) Return(messageRevisions []MessageRevision, err error) *MockStorage_FindMessageRevisions_Call {
+	_c.Call.Return(messageRevisions, err)
+	return _c
+}
+
+func (_c *MockStorage_FindMessageRevisions_Call) RunAndReturn(run func(tickerID int, messageID int) ([]MessageRevision, error)) *MockStorage_FindMessageRevisions_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// FindMessagesByTicker provides a mock function for the type MockStorage
+func (_mock *MockStorage) FindMessagesByTicker(ticker Ticker, opts ...func(*gorm.DB) *gorm.DB) ([]Message, error) {
+	var tmpRet mock.Arguments
+	if len(opts) > 0 {
+		tmpRet = _mock.Called(ticker, opts)
+	} else {
+		tmpRet = _mock.Called(ticker)
+	}
+	ret := tmpRet
+
+	if len(ret) == 0 {
+		panic("no return value specified for FindMessagesByTicker")
+	}
+
+	var r0 []Message
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(Ticker, ...func(*gorm.DB) *gorm.DB) ([]Message, error)); ok {
+		return returnFunc(ticker, opts...)
+	}
+	if returnFunc, ok := ret.Get(0).(func(Ticker, ...func(*gorm.DB) *gorm.DB) []Message); ok {
+		r0 = returnFunc(ticker, opts...)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]Message)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(Ticker, ...func(*gorm.DB) *gorm.DB) error); ok {
+		r1 = returnFunc(ticker, opts...)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockStorage_FindMessagesByTicker_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'FindMessagesByTicker'
+type MockStorage_FindMessagesByTicker_Call struct {
+	*mock.Call
+}
+
+// FindMessagesByTicker is a helper method to define mock.On call
+//   - ticker Ticker
+//   - opts ...func(*gorm.DB) *gorm.DB
+func (_e *MockStorage_Expecter) FindMessagesByTicker(ticker interface{}, opts ...interface{}) *MockStorage_FindMessagesByTicker_Call {
+	return &MockStorage_FindMessagesByTicker_Call{Call: _e.mock.On("FindMessagesByTicker",
+		append([]interface{}{ticker}, opts...)...)}
+}
+
+func (_c *MockStorage_FindMessagesByTicker_Call) Run(run func(ticker Ticker, opts ...func(*gorm.DB) *gorm.DB)) *MockStorage_FindMessagesByTicker_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 Ticker
+		if args[0] != nil {
+			arg0 = args[0].(Ticker)
+		}
+		var arg1 []func(*gorm.DB) *gorm.DB
+		var variadicArgs []func(*gorm.DB) *gorm.DB
+		if len(args) > 1 {
+			variadicArgs = args[1].([]func(*gorm.DB) *gorm.DB)
+		}
+		arg1 = variadicArgs
+		run(
+			arg0,
+			arg1...,
+		)
+	})
+	return _c
+}
+
+func (_c *MockStorage_FindMessagesByTicker_Call) Return(messages []Message, err error) *MockStorage_FindMessagesByTicker_Call {
+	_c.Call.Return(messages, err)
+	return _c
+}
+
+func (_c *MockStorage_FindMessagesByTicker_Call) RunAndReturn(run func(ticker Ticker, opts ...func(*gorm.DB) *gorm.DB) ([]Message, error)) *MockStorage_FindMessagesByTicker_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// FindMessagesByTickerAndPagination provides a mock function for the type MockStorage
+func (_mock *MockStorage) FindMessagesByTickerAndPagination(ticker Ticker, pagination1 pagination.Pagination, opts ...func(*gorm.DB) *gorm.DB) ([]Message, error) {
+	var tmpRet mock.Arguments
+	if len(opts) > 0 {
+		tmpRet = _mock.Called(ticker, pagination1, opts)
+	} else {
+		tmpRet = _mock.Called(ticker, pagination1)
+	}
+	ret := tmpRet
+
+	if len(ret) == 0 {
+		panic("no return value specified for FindMessagesByTickerAndPagination")
+	}
+
+	var r0 []Message
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(Ticker, pagination.Pagination, ...func(*gorm.DB) *gorm.DB) ([]Message, error)); ok {
+		return returnFunc(ticker, pagination1, opts...)
+	}
+	if returnFunc, ok := ret.Get(0).(func(Ticker, pagination.Pagination, ...func(*gorm.DB) *gorm.DB) []Message); ok {
+		r0 = returnFunc(ticker, pagination1, opts...)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]Message)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(Ticker, pagination.Pagination, ...func(*gorm.DB) *gorm.DB) error); ok {
+		r1 = returnFunc(ticker, pagination1, opts...)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockStorage_FindMessagesByTickerAndPagination_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'FindMessagesByTickerAndPagination'
+type MockStorage_FindMessagesByTickerAndPagination_Call struct {
+	*mock.Call
+}
+
+// FindMessagesByTickerAndPagination is a helper method to define mock.On call
+//   - ticker Ticker
+//   - pagination1 pagination.Pagination
+//   - opts ...func(*gorm.DB) *gorm.DB
+func (_e *MockStorage_Expecter) FindMessagesByTickerAndPagination(ticker interface{}, pagination1 interface{}, opts ...interface{}) *MockStorage_FindMessagesByTickerAndPagination_Call {
+	return &MockStorage_FindMessagesByTickerAndPagination_Call{Call: _e.mock.On("FindMessagesByTickerAndPagination",
+		append([]interface{}{ticker, pagination1}, opts...)...)}
+}
+
+func (_c *MockStorage_FindMessagesByTickerAndPagination_Call) Run(run func(ticker Ticker, pagination1 pagination.Pagination, opts ...func(*gorm.DB) *gorm.DB)) *MockStorage_FindMessagesByTickerAndPagination_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 Ticker
+		if args[0] != nil {
+			arg0 = args[0].(Ticker)
+		}
+		var arg1 pagination.Pagination
+		if args[1] != nil {
+			arg1 = args[1].(pagination.Pagination)
+		}
+		var arg2 []func(*gorm.DB) *gorm.DB
+		var variadicArgs []func(*gorm.DB) *gorm.DB
+		if len(args) > 2 {
+			variadicArgs = args[2].([]func(*gorm.DB) *gorm.DB)
+		}
+		arg2 = variadicArgs
+		run(
+			arg0,
+			arg1,
+			arg2...,
+		)
+	})
+	return _c
+}
+
+func (_c *MockStorage_FindMessagesByTickerAndPagination_Call) Return(messages []Message, err error) *MockStorage_FindMessagesByTickerAndPagination_Call {
+	_c.Call.Return(messages, err)
+	return _c
+}
+
+func (_c *MockStorage_FindMessagesByTickerAndPagination_Call) RunAndReturn(run func(ticker Ticker, pagination1 pagination.Pagination, opts ...func(*gorm.DB) *gorm.DB) ([]Message, error)) *MockStorage_FindMessagesByTickerAndPagination_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// FindPendingReviewMessagesByTicker provides a mock function for the type MockStorage
+func (_mock *MockStorage) FindPendingReviewMessagesByTicker(ticker Ticker, opts ...func(*gorm.DB) *gorm.DB) ([]Message, error) {
+	var tmpRet mock.Arguments
+	if len(opts) > 0 {
+		tmpRet = _mock.Called(ticker, opts)
+	} else {
+		tmpRet = _mock.Called(ticker)
+	}
+	ret := tmpRet
+
+	if len(ret) == 0 {
+		panic("no return value specified for FindPendingReviewMessagesByTicker")
+	}
+
+	var r0 []Message
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(Ticker, ...func(*gorm.DB) *gorm.DB) ([]Message, error)); ok {
+		return returnFunc(ticker, opts...)
+	}
+	if returnFunc, ok := ret.Get(0).(func(Ticker, ...func(*gorm.DB) *gorm.DB) []Message); ok {
+		r0 = returnFunc(ticker, opts...)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]Message)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(Ticker, ...func(*gorm.DB) *gorm.DB) error); ok {
+		r1 = returnFunc(ticker, opts...)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockStorage_FindPendingReviewMessagesByTicker_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'FindPendingReviewMessagesByTicker'
+type MockStorage_FindPendingReviewMessagesByTicker_Call struct {
+	*mock.Call
+}
+
+// FindPendingReviewMessagesByTicker is a helper method to define mock.On call
+//   - ticker Ticker
+//   - opts ...func(*gorm.DB) *gorm.DB
+func (_e *MockStorage_Expecter) FindPendingReviewMessagesByTicker(ticker interface{}, opts ...interface{}) *MockStorage_FindPendingReviewMessagesByTicker_Call {
+	return &MockStorage_FindPendingReviewMessagesByTicker_Call{Call: _e.mock.On("FindPendingReviewMessagesByTicker",
+		append([]interface{}{ticker}, opts...)...)}
+}
+
+func (_c *MockStorage_FindPendingReviewMessagesByTicker_Call) Run(run func(ticker Ticker, opts ...func(*gorm.DB) *gorm.DB)) *MockStorage_FindPendingReviewMessagesByTicker_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 Ticker
+		if args[0] != nil {
+			arg0 = args[0].(Ticker)
+		}
+		var arg1 []func(*gorm.DB) *gorm.DB
+		var variadicArgs []func(*gorm.DB) *gorm.DB
+		if len(args) > 1 {
+			variadicArgs = args[1].([]func(*gorm.DB) *gorm.DB)
+		}
+		arg1 = variadicArgs
+		run(
+			arg0,
+			arg1...,
+		)
+	})
+	return _c
+}
+
+func (_c *MockStorage_FindPendingReviewMessagesByTicker_Call) Return(messages []Message, err error) *MockStorage_FindPendingReviewMessagesByTicker_Call {
+	_c.Call.Return(messages, err)
+	return _c
+}
+
+func (_c *MockStorage_FindPendingReviewMessagesByTicker_Call) RunAndReturn(run func(ticker Ticker, opts ...func(*gorm.DB) *gorm.DB) ([]Message, error)) *MockStorage_FindPendingReviewMessagesByTicker_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// FindPinnedMessagesByTicker provides a mock function for the type MockStorage
+func (_mock *MockStorage) FindPinnedMessagesByTicker(ticker Ticker, opts ...func(*gorm.DB) *gorm.DB) ([]Message, error) {
+	var tmpRet mock.Arguments
+	if len(opts) > 0 {
+		tmpRet = _mock.Called(ticker, opts)
+	} else {
+		tmpRet = _mock.Called(ticker)
+	}
+	ret := tmpRet
+
+	if len(ret) == 0 {
+		panic("no return value specified for FindPinnedMessagesByTicker")
+	}
+
+	var r0 []Message
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(Ticker, ...func(*gorm.DB) *gorm.DB) ([]Message, error)); ok {
+		return returnFunc(ticker, opts...)
+	}
+	if returnFunc, ok := ret.Get(0).(func(Ticker, ...func(*gorm.DB) *gorm.DB) []Message); ok {
+		r0 = returnFunc(ticker, opts...)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]Message)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(Ticker, ...func(*gorm.DB) *gorm.DB) error); ok {
+		r1 = returnFunc(ticker, opts...)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockStorage_FindPinnedMessagesByTicker_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'FindPinnedMessagesByTicker'
+type MockStorage_FindPinnedMessagesByTicker_Call struct {
+	*mock.Call
+}
+
+// FindPinnedMessagesByTicker is a helper method to define mock.On call
+//   - ticker Ticker
+//   - opts ...func(*gorm.DB) *gorm.DB
+func (_e *MockStorage_Expecter) FindPinnedMessagesByTicker(ticker interface{}, opts ...interface{}) *MockStorage_FindPinnedMessagesByTicker_Call {
+	return &MockStorage_FindPinnedMessagesByTicker_Call{Call: _e.mock.On("FindPinnedMessagesByTicker",
+		append([]interface{}{ticker}, opts...)...)}
+}
+
+func (_c *MockStorage_FindPinnedMessagesByTicker_Call) Run(run func(ticker Ticker, opts ...func(*gorm.DB) *gorm.DB)) *MockStorage_FindPinnedMessagesByTicker_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 Ticker
+		if args[0] != nil {
+			arg0 = args[0].(Ticker)
+		}
+		var arg1 []func(*gorm.DB) *gorm.DB
+		var variadicArgs []func(*gorm.DB) *gorm.DB
+		if len(args) > 1 {
+			variadicArgs = args[1].([]func(*gorm.DB) *gorm.DB)
+		}
+		arg1 = variadicArgs
+		run(
+			arg0,
+			arg1...,
+		)
+	})
+	return _c
+}
+
+func (_c *MockStorage_FindPinnedMessagesByTicker_Call) Return(messages []Message, err error) *MockStorage_FindPinnedMessagesByTicker_Call {
+	_c.Call.Return(messages, err)
+	return _c
+}
+
+func (_c *MockStorage_FindPinnedMessagesByTicker_Call) RunAndReturn(run func(ticker Ticker, opts ...func(*gorm.DB) *gorm.DB) ([]Message, error)) *MockStorage_FindPinnedMessagesByTicker_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// FindScheduledMessagesByTicker provides a mock function for the type MockStorage
+func (_mock *MockStorage) FindScheduledMessagesByTicker(ticker Ticker, opts ...func(*gorm.DB) *gorm.DB) ([]Message, error) {
+	var tmpRet mock.Arguments
+	if len(opts) > 0 {
+		tmpRet = _mock.Called(ticker, opts)
+	} else {
+		tmpRet = _mock.Called(ticker)
+	}
+	ret := tmpRet
+
+	if len(ret) == 0 {
+		panic("no return value specified for FindScheduledMessagesByTicker")
+	}
+
+	var r0 []Message
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(Ticker, ...func(*gorm.DB) *gorm.DB) ([]Message, error)); ok {
+		return returnFunc(ticker, opts...)
+	}
+	if returnFunc, ok := ret.Get(0).(func(Ticker, ...func(*gorm.DB) *gorm.DB) []Message); ok {
+		r0 = returnFunc(ticker, opts...)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]Message)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(Ticker, ...func(*gorm.DB) *gorm.DB) error); ok {
+		r1 = returnFunc(ticker, opts...)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockStorage_FindScheduledMessagesByTicker_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'FindScheduledMessagesByTicker'
+type MockStorage_FindScheduledMessagesByTicker_Call struct {
+	*mock.Call
+}
+
+// FindScheduledMessagesByTicker is a helper method to define mock.On call
+//   - ticker Ticker
+//   - opts ...func(*gorm.DB) *gorm.DB
+func (_e *MockStorage_Expecter) FindScheduledMessagesByTicker(ticker interface{}, opts ...interface{}) *MockStorage_FindScheduledMessagesByTicker_Call {
+	return &MockStorage_FindScheduledMessagesByTicker_Call{Call: _e.mock.On("FindScheduledMessagesByTicker",
+		append([]interface{}{ticker}, opts...)...)}
+}
+
+func (_c *MockStorage_FindScheduledMessagesByTicker_Call) Run(run func(ticker Ticker, opts ...func(*gorm.DB) *gorm.DB)) *MockStorage_FindScheduledMessagesByTicker_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 Ticker
+		if args[0] != nil {
+			arg0 = args[0].(Ticker)
+		}
+		var arg1 []func(*gorm.DB) *gorm.DB
+		var variadicArgs []func(*gorm.DB) *gorm.DB
+		if len(args) > 1 {
+			variadicArgs = args[1].([]func(*gorm.DB) *gorm.DB)
+		}
+		arg1 = variadicArgs
+		run(
+			arg0,
+			arg1...,
+		)
+	})
+	return _c
+}
+
+func (_c *MockStorage_FindScheduledMessagesByTicker_Call) Return(messages []Message, err error) *MockStorage_FindScheduledMessagesByTicker_Call {
+	_c.Call.Return(messages, err)
+	return _c
+}
+
+func (_c *MockStorage_FindScheduledMessagesByTicker_Call) RunAndReturn(run func(ticker Ticker, opts ...func(*gorm.DB) *gorm.DB) ([]Message, error)) *MockStorage_FindScheduledMessagesByTicker_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// FindTagsByTicker provides a mock function for the type MockStorage
+func (_mock *MockStorage) FindTagsByTicker(ticker Ticker) ([]TagCount, error) {
+	ret := _mock.Called(ticker)
+
+	if len(ret) == 0 {
+		panic("no return value specified for FindTagsByTicker")
+	}
+
+	var r0 []TagCount
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(Ticker) ([]TagCount, error)); ok {
+		return returnFunc(ticker)
+	}
+	if returnFunc, ok := ret.Get(0).(func(Ticker) []TagCount); ok {
+		r0 = returnFunc(ticker)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]TagCount)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(Ticker) error); ok {
+		r1 = returnFunc(ticker)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockStorage_FindTagsByTicker_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'FindTagsByTicker'
+type MockStorage_FindTagsByTicker_Call struct {
+	*mock.Call
+}
+
+// FindTagsByTicker is a helper method to define mock.On call
+//   - ticker Ticker
+func (_e *MockStorage_Expecter) FindTagsByTicker(ticker interface{}) *MockStorage_FindTagsByTicker_Call {
+	return &MockStorage_FindTagsByTicker_Call{Call: _e.mock.On("FindTagsByTicker", ticker)}
+}
+
+func (_c *MockStorage_FindTagsByTicker_Call) Run(run func(ticker Ticker)) *MockStorage_FindTagsByTicker_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 Ticker
+		if args[0] != nil {
+			arg0 = args[0].(Ticker)
+		}
+		run(
+			arg0,
+		)
+	})
+	return _c
+}
+
+func (_c *MockStorage_FindTagsByTicker_Call) Return(tagCounts []TagCount, err error) *MockStorage_FindTagsByTicker_Call {
+	_c.Call.Return(tagCounts, err)
+	return _c
+}
+
+func (_c *MockStorage_FindTagsByTicker_Call) RunAndReturn(run func(ticker Ticker) ([]TagCount, error)) *MockStorage_FindTagsByTicker_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// FindTelegramLinkByCode provides a mock function for the type MockStorage
+func (_mock *MockStorage) FindTelegramLinkByCode(code string) (TelegramLink, error) {
+	ret := _mock.Called(code)
+
+	if len(ret) == 0 {
+		panic("no return value specified for FindTelegramLinkByCode")
+	}
+
+	var r0 TelegramLink
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(string) (TelegramLink, error)); ok {
+		return returnFunc(code)
+	}
+	if returnFunc, ok := ret.Get(0).(func(string) TelegramLink); ok {
+		r0 = returnFunc(code)
+	} else {
+		r0 = ret.Get(0).(TelegramLink)
+	}
+	if returnFunc, ok := ret.Get(1).(func(string) error); ok {
+		r1 = returnFunc(code)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockStorage_FindTelegramLinkByCode_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'FindTelegramLinkByCode'
+type MockStorage_FindTelegramLinkByCode_Call struct {
+	*mock.Call
+}
+
+// FindTelegramLinkByCode is a helper method to define mock.On call
+//   - code string
+func (_e *MockStorage_Expecter) FindTelegramLinkByCode(code interface{}) *MockStorage_FindTelegramLinkByCode_Call {
+	return &MockStorage_FindTelegramLinkByCode_Call{Call: _e.mock.On("FindTelegramLinkByCode", code)}
+}
+
+func (_c *MockStorage_FindTelegramLinkByCode_Call) Run(run func(code string)) *MockStorage_FindTelegramLinkByCode_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 string
+		if args[0] != nil {
+			arg0 = args[0].(string)
+		}
+		run(
+			arg0,
+		)
+	})
+	return _c
+}
+
+func (_c *MockStorage_FindTelegramLinkByCode_Call) Return(telegramLink TelegramLink, err error) *MockStorage_FindTelegramLinkByCode_Call {
+	_c.Call.Return(telegramLink, err)
+	return _c
+}
+
+func (_c *MockStorage_FindTelegramLinkByCode_Call) RunAndReturn(run func(code string) (TelegramLink, error)) *MockStorage_FindTelegramLinkByCode_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// FindTickerByID provides a mock function for the type MockStorage
+func (_mock *MockStorage) FindTickerByID(id int, opts ...func(*gorm.DB) *gorm.DB) (Ticker, error) {
+	var tmpRet mock.Arguments
+	if len(opts) > 0 {
+		tmpRet = _mock.Called(id, opts)
+	} else {
+		tmpRet = _mock.Called(id)
+	}
+	ret := tmpRet
+
+	if len(ret) == 0 {
+		panic("no return value specified for FindTickerByID")
+	}
+
+	var r0 Ticker
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(int, ...func(*gorm.DB) *gorm.DB) (Ticker, error)); ok {
+		return returnFunc(id, opts...)
+	}
+	if returnFunc, ok := ret.Get(0).(func(int, ...func(*gorm.DB) *gorm.DB) Ticker); ok {
+		r0 = returnFunc(id, opts...)
+	} else {
+		r0 = ret.Get(0).(Ticker)
+	}
+	if returnFunc, ok := ret.Get(1).(func(int, ...func(*gorm.DB) *gorm.DB) error); ok {
+		r1 = returnFunc(id, opts...)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockStorage_FindTickerByID_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'FindTickerByID'
+type MockStorage_FindTickerByID_Call struct {
+	*mock.Call
+}
+
+// FindTickerByID is a helper method to define mock.On call
+//   - id int
+//   - opts ...func(*gorm.DB) *gorm.DB
+func (_e *MockStorage_Expecter) FindTickerByID(id interface{}, opts ...interface{}) *MockStorage_FindTickerByID_Call {
+	return &MockStorage_FindTickerByID_Call{Call: _e.mock.On("FindTickerByID",
+		append([]interface{}{id}, opts...)...)}
+}
+
+func (_c *MockStorage_FindTickerByID_Call) Run(run func(id int, opts ...func(*gorm.DB) *gorm.DB)) *MockStorage_FindTickerByID_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 int
+		if args[0] != nil {
+			arg0 = args[0].(int)
+		}
+		var arg1 []func(*gorm.DB) *gorm.DB
+		var variadicArgs []func(*gorm.DB) *gorm.DB
+		if len(args) > 1 {
+			variadicArgs = args[1].([]func(*gorm.DB) *gorm.DB)
+		}
+		arg1 = variadicArgs
+		run(
+			arg0,
+			arg1...,
+		)
+	})
+	return _c
+}
+
+func (_c *MockStorage_FindTickerByID_Call) Return(ticker Ticker, err error) *MockStorage_FindTickerByID_Call {
+	_c.Call.Return(ticker, err)
+	return _c
+}
+
+func (_c *MockStorage_FindTickerByID_Call) RunAndReturn(run func(id int, opts ...func(*gorm.DB) *gorm.DB) (Ticker, error)) *MockStorage_FindTickerByID_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// FindTickerByMailboxAddress provides a mock function for the type MockStorage
+func (_mock *MockStorage) FindTickerByMailboxAddress(address string, opts ...func(*gorm.DB) *gorm.DB) (Ticker, error) {
+	var tmpRet mock.Arguments
+	if len(opts) > 0 {
+		tmpRet = _mock.Called(address, opts)
+	} else {
+		tmpRet = _mock.Called(address)
+	}
+	ret := tmpRet
+
+	if len(ret) == 0 {
+		panic("no return value specified for FindTickerByMailboxAddress")
+	}
+
+	var r0 Ticker
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(string, ...func(*gorm.DB) *gorm.DB) (Ticker, error)); ok {
+		return returnFunc(address, opts...)
+	}
+	if returnFunc, ok := ret.Get(0).(func(string, ...func(*gorm.DB) *gorm.DB) Ticker); ok {
+		r0 = returnFunc(address, opts...)
+	} else {
+		r0 = ret.Get(0).(Ticker)
+	}
+	if returnFunc, ok := ret.Get(1).(func(string, ...func(*gorm.DB) *gorm.DB) error); ok {
+		r1 = returnFunc(address, opts...)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockStorage_FindTickerByMailboxAddress_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'FindTickerByMailboxAddress'
+type MockStorage_FindTickerByMailboxAddress_Call struct {
+	*mock.Call
+}
+
+// FindTickerByMailboxAddress is a helper method to define mock.On call
+//   - address string
+//   - opts ...func(*gorm.DB) *gorm.DB
+func (_e *MockStorage_Expecter) FindTickerByMailboxAddress(address interface{}, opts ...interface{}) *MockStorage_FindTickerByMailboxAddress_Call {
+	return &MockStorage_FindTickerByMailboxAddress_Call{Call: _e.mock.On("FindTickerByMailboxAddress",
+		append([]interface{}{address}, opts...)...)}
+}
+
+func (_c *MockStorage_FindTickerByMailboxAddress_Call) Run(run func(address string, opts ...func(*gorm.DB) *gorm.DB)) *MockStorage_FindTickerByMailboxAddress_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 string
+		if args[0] != nil {
+			arg0 = args[0].(string)
+		}
+		var arg1 []func(*gorm.DB) *gorm.DB
+		var variadicArgs []func(*gorm.DB) *gorm.DB
+		if len(args) > 1 {
+			variadicArgs = args[1].([]func(*gorm.DB) *gorm.DB)
+		}
+		arg1 = variadicArgs
+		run(
+			arg0,
+			arg1...,
+		)
+	})
+	return _c
+}
+
+func (_c *MockStorage_FindTickerByMailboxAddress_Call) Return(ticker Ticker, err error) *MockStorage_FindTickerByMailboxAddress_Call {
+	_c.Call.Return(ticker, err)
+	return _c
+}
+
+func (_c *MockStorage_FindTickerByMailboxAddress_Call) RunAndReturn(run func(address string, opts ...func(*gorm.DB) *gorm.DB) (Ticker, error)) *MockStorage_FindTickerByMailboxAddress_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// FindTickerByOrigin provides a mock function for the type MockStorage
+func (_mock *MockStorage) FindTickerByOrigin(origin string, opts ...func(*gorm.DB) *gorm.DB) (Ticker, error) {
+	var tmpRet mock.Arguments
+	if len(opts) > 0 {
+		tmpRet = _mock.Called(origin, opts)
+	} else {
+		tmpRet = _mock.Called(origin)
+	}
+	ret := tmpRet
+
+	if len(ret) == 0 {
+		panic("no return value specified for FindTickerByOrigin")
+	}
+
+	var r0 Ticker
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(string, ...func(*gorm.DB) *gorm.DB) (Ticker, error)); ok {
+		return returnFunc(origin, opts...)
+	}
+	if returnFunc, ok := ret.Get(0).(func(string, ...func(*gorm.DB) *gorm.DB) Ticker); ok {
+		r0 = returnFunc(origin, opts...)
+	} else {
+		r0 = ret.Get(0).(Ticker)
+	}
+	if returnFunc, ok := ret.Get(1).(func(string, ...func(*gorm.DB) *gorm.DB) error); ok {
+		r1 = returnFunc(origin, opts...)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockStorage_FindTickerByOrigin_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'FindTickerByOrigin'
+type MockStorage_FindTickerByOrigin_Call struct {
+	*mock.Call
+}
+
+// FindTickerByOrigin is a helper method to define mock.On call
+//   - origin string
+//   - opts ...func(*gorm.DB) *gorm.DB
+func (_e *MockStorage_Expecter) FindTickerByOrigin(origin interface{}, opts ...interface{}) *MockStorage_FindTickerByOrigin_Call {
+	return &MockStorage_FindTickerByOrigin_Call{Call: _e.mock.On("FindTickerByOrigin",
+		append([]interface{}{origin}, opts...)...)}
+}
+
+func (_c *MockStorage_FindTickerByOrigin_Call) Run(run func(origin string, opts ...func(*gorm.DB) *gorm.DB)) *MockStorage_FindTickerByOrigin_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 string
+		if args[0] != nil {
+			arg0 = args[0].(string)
+		}
+		var arg1 []func(*gorm.DB) *gorm.DB
+		var variadicArgs []func(*gorm.DB) *gorm.DB
+		if len(args) > 1 {
+			variadicArgs = args[1].([]func(*gorm.DB) *gorm.DB)
+		}
+		arg1 = variadicArgs
+		run(
+			arg0,
+			arg1...,
+		)
+	})
+	return _c
+}
+
+func (_c *MockStorage_FindTickerByOrigin_Call) Return(ticker Ticker, err error) *MockStorage_FindTickerByOrigin_Call {
+	_c.Call.Return(ticker, err)
+	return _c
+}
+
+func (_c *MockStorage_FindTickerByOrigin_Call) RunAndReturn(run func(origin string, opts ...func(*gorm.DB) *gorm.DB) (Ticker, error)) *MockStorage_FindTickerByOrigin_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// FindTickerByUserAndID provides a mock function for the type MockStorage
+func (_mock *MockStorage) FindTickerByUserAndID(user User, id int, opts ...func(*gorm.DB) *gorm.DB) (Ticker, error) {
+	var tmpRet mock.Arguments
+	if len(opts) > 0 {
+		tmpRet = _mock.Called(user, id, opts)
+	} else {
+		tmpRet = _mock.Called(user, id)
+	}
+	ret := tmpRet
+
+	if len(ret) == 0 {
+		panic("no return value specified for FindTickerByUserAndID")
+	}
+
+	var r0 Ticker
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(User, int, ...func(*gorm.DB) *gorm.DB) (Ticker, error)); ok {
+		return returnFunc(user, id, opts...)
+	}
+	if returnFunc, ok := ret.Get(0).(func(User, int, ...func(*gorm.DB) *gorm.DB) Ticker); ok {
+		r0 = returnFunc(user, id, opts...)
+	} else {
+		r0 = ret.Get(0).(Ticker)
+	}
+	if returnFunc, ok := ret.Get(1).(func(User, int, ...func(*gorm.DB) *gorm.DB) error); ok {
+		r1 = returnFunc(user, id, opts...)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockStorage_FindTickerByUserAndID_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'FindTickerByUserAndID'
+type MockStorage_FindTickerByUserAndID_Call struct {
+	*mock.Call
+}
+
+// FindTickerByUserAndID is a helper method to define mock.On call
+//   - user User
+//   - id int
+//   - opts ...func(*gorm.DB) *gorm.DB
+func (_e *MockStorage_Expecter) FindTickerByUserAndID(user interface{}, id interface{}, opts ...interface{}) *MockStorage_FindTickerByUserAndID_Call {
+	return &MockStorage_FindTickerByUserAndID_Call{Call: _e.mock.On("FindTickerByUserAndID",
+		append([]interface{}{user, id}, opts...)...)}
+}
+
+func (_c *MockStorage_FindTickerByUserAndID_Call) Run(run func(user User, id int, opts ...func(*gorm.DB) *gorm.DB)) *MockStorage_FindTickerByUserAndID_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 User
+		if args[0] != nil {
+			arg0 = args[0].(User)
+		}
+		var arg1 int
+		if args[1] != nil {
+			arg1 = args[1].(int)
+		}
+		var arg2 []func(*gorm.DB) *gorm.DB
+		var variadicArgs []func(*gorm.DB) *gorm.DB
+		if len(args) > 2 {
+			variadicArgs = args[2].([]func(*gorm.DB) *gorm.DB)
+		}
+		arg2 = variadicArgs
+		run(
+			arg0,
+			arg1,
+			arg2...,
+		)
+	})
+	return _c
+}
+
+func (_c *MockStorage_FindTickerByUserAndID_Call) Return(ticker Ticker, err error) *MockStorage_FindTickerByUserAndID_Call {
+	_c.Call.Return(ticker, err)
+	return _c
+}
+
+func (_c *MockStorage_FindTickerByUserAndID_Call) RunAndReturn(run func(user User, id int, opts ...func(*gorm.DB) *gorm.DB) (Ticker, error)) *MockStorage_FindTickerByUserAndID_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// FindTickerSignalSenderByNumber provides a mock function for the type MockStorage
+func (_mock *MockStorage) FindTickerSignalSenderByNumber(number string) (TickerSignalSender, error) {
+	ret := _mock.Called(number)
+
+	if len(ret) == 0 {
+		panic("no return value specified for FindTickerSignalSenderByNumber")
+	}
+
+	var r0 TickerSignalSender
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(string) (TickerSignalSender, error)); ok {
+		return returnFunc(number)
+	}
+	if returnFunc, ok := ret.Get(0).(func(string) TickerSignalSender); ok {
+		r0 = returnFunc(number)
+	} else {
+		r0 = ret.Get(0).(TickerSignalSender)
+	}
+	if returnFunc, ok := ret.Get(1).(func(string) error); ok {
+		r1 = returnFunc(number)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockStorage_FindTickerSignalSenderByNumber_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'FindTickerSignalSenderByNumber'
+type MockStorage_FindTickerSignalSenderByNumber_Call struct {
+	*mock.Call
+}
+
+// FindTickerSignalSenderByNumber is a helper method to define mock.On call
+//   - number string
+func (_e *MockStorage_Expecter) FindTickerSignalSenderByNumber(number interface{}) *MockStorage_FindTickerSignalSenderByNumber_Call {
+	return &MockStorage_FindTickerSignalSenderByNumber_Call{Call: _e.mock.On("FindTickerSignalSenderByNumber", number)}
+}
+
+func (_c *MockStorage_FindTickerSignalSenderByNumber_Call) Run(run func(number string)) *MockStorage_FindTickerSignalSenderByNumber_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 string
+		if args[0] != nil {
+			arg0 = args[0].(string)
+		}
+		run(
+			arg0,
+		)
+	})
+	return _c
+}
+
+func (_c *MockStorage_FindTickerSignalSenderByNumber_Call) Return(tickerSignalSender TickerSignalSender, err error) *MockStorage_FindTickerSignalSenderByNumber_Call {
+	_c.Call.Return(tickerSignalSender, err)
+	return _c
+}
+
+func (_c *MockStorage_FindTickerSignalSenderByNumber_Call) RunAndReturn(run func(number string) (TickerSignalSender, error)) *MockStorage_FindTickerSignalSenderByNumber_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// FindTickerUser provides a mock function for the type MockStorage
+func (_mock *MockStorage) FindTickerUser(ticker Ticker, user User) (TickerUser, error) {
+	ret := _mock.Called(ticker, user)
+
+	if len(ret) == 0 {
+		panic("no return value specified for FindTickerUser")
+	}
+
+	var r0 TickerUser
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(Ticker, User) (TickerUser, error)); ok {
+		return returnFunc(ticker, user)
+	}
+	if returnFunc, ok := ret.Get(0).(func(Ticker, User) TickerUser); ok {
+		r0 = returnFunc(ticker, user)
+	} else {
+		r0 = ret.Get(0).(TickerUser)
+	}
+	if returnFunc, ok := ret.Get(1).(func(Ticker, User) error); ok {
+		r1 = returnFunc(ticker, user)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockStorage_FindTickerUser_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'FindTickerUser'
+type MockStorage_FindTickerUser_Call struct {
+	*mock.Call
+}
+
+// FindTickerUser is a helper method to define mock.On call
+//   - ticker Ticker
+//   - user User
+func (_e *MockStorage_Expecter) FindTickerUser(ticker interface{}, user interface{}) *MockStorage_FindTickerUser_Call {
+	return &MockStorage_FindTickerUser_Call{Call: _e.mock.On("FindTickerUser", ticker, user)}
+}
+
+func (_c *MockStorage_FindTickerUser_Call) Run(run func(ticker Ticker, user User)) *MockStorage_FindTickerUser_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 Ticker
+		if args[0] != nil {
+			arg0 = args[0].(Ticker)
+		}
+		var arg1 User
+		if args[1] != nil {
+			arg1 = args[1].(User)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *MockStorage_FindTickerUser_Call) Return(tickerUser TickerUser, err error) *MockStorage_FindTickerUser_Call {
+	_c.Call.Return(tickerUser, err)
+	return _c
+}
+
+func (_c *MockStorage_FindTickerUser_Call) RunAndReturn(run func(ticker Ticker, user User) (TickerUser, error)) *MockStorage_FindTickerUser_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// FindTickerUsers provides a mock function for the type MockStorage
+func (_mock *MockStorage) FindTickerUsers(ticker Ticker) ([]TickerUser, error) {
+	ret := _mock.Called(ticker)
+
+	if len(ret) == 0 {
+		panic("no return value specified for FindTickerUsers")
+	}
+
+	var r0 []TickerUser
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(Ticker) ([]TickerUser, error)); ok {
+		return returnFunc(ticker)
+	}
+	if returnFunc, ok := ret.Get(0).(func(Ticker) []TickerUser); ok {
+		r0 = returnFunc(ticker)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]TickerUser)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(Ticker) error); ok {
+		r1 = returnFunc(ticker)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockStorage_FindTickerUsers_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'FindTickerUsers'
+type MockStorage_FindTickerUsers_Call struct {
+	*mock.Call
+}
+
+// FindTickerUsers is a helper method to define mock.On call
+//   - ticker Ticker
+func (_e *MockStorage_Expecter) FindTickerUsers(ticker interface{}) *MockStorage_FindTickerUsers_Call {
+	return &MockStorage_FindTickerUsers_Call{Call: _e.mock.On("FindTickerUsers", ticker)}
+}
+
+func (_c *MockStorage_FindTickerUsers_Call) Run(run func(ticker Ticker)) *MockStorage_FindTickerUsers_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 Ticker
+		if args[0] != nil {
+			arg0 = args[0].(Ticker)
+		}
+		run(
+			arg0,
+		)
+	})
+	return _c
+}
+
+func (_c *MockStorage_FindTickerUsers_Call) Return(tickerUsers []TickerUser, err error) *MockStorage_FindTickerUsers_Call {
+	_c.Call.Return(tickerUsers, err)
+	return _c
+}
+
+func (_c *MockStorage_FindTickerUsers_Call) RunAndReturn(run func(ticker Ticker) ([]TickerUser, error)) *MockStorage_FindTickerUsers_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// FindTickersByIDs provides a mock function for the type MockStorage
+func (_mock *MockStorage) FindTickersByIDs(ids []int, opts ...func(*gorm.DB) *gorm.DB) ([]Ticker, error) {
+	var tmpRet mock.Arguments
+	if len(opts) > 0 {
+		tmpRet = _mock.Called(ids, opts)
+	} else {
+		tmpRet = _mock.Called(ids)
+	}
+	ret := tmpRet
+
+	if len(ret) == 0 {
+		panic("no return value specified for FindTickersByIDs")
+	}
+
+	var r0 []Ticker
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func([]int, ...func(*gorm.DB) *gorm.DB) ([]Ticker, error)); ok {
+		return returnFunc(ids, opts...)
+	}
+	if returnFunc, ok := ret.Get(0).(func([]int, ...func(*gorm.DB) *gorm.DB) []Ticker); ok {
+		r0 = returnFunc(ids, opts...)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]Ticker)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func([]int, ...func(*gorm.DB) *gorm.DB) error); ok {
+		r1 = returnFunc(ids, opts...)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockStorage_FindTickersByIDs_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'FindTickersByIDs'
+type MockStorage_FindTickersByIDs_Call struct {
+	*mock.Call
+}
+
+// FindTickersByIDs is a helper method to define mock.On call
+//   - ids []int
+//   - opts ...func(*gorm.DB) *gorm.DB
+func (_e *MockStorage_Expecter) FindTickersByIDs(ids interface{}, opts ...interface{}) *MockStorage_FindTickersByIDs_Call {
+	return &MockStorage_FindTickersByIDs_Call{Call: _e.mock.On("FindTickersByIDs",
+		append([]interface{}{ids}, opts...)...)}
+}
+
+func (_c *MockStorage_FindTickersByIDs_Call) Run(run func(ids []int, opts ...func(*gorm.DB) *gorm.DB)) *MockStorage_FindTickersByIDs_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 []int
+		if args[0] != nil {
+			arg0 = args[0].([]int)
+		}
+		var arg1 []func(*gorm.DB) *gorm.DB
+		var variadicArgs []func(*gorm.DB) *gorm.DB
+		if len(args) > 1 {
+			variadicArgs = args[1].([]func(*gorm.DB) *gorm.DB)
+		}
+		arg1 = variadicArgs
+		run(
+			arg0,
+			arg1...,
+		)
+	})
+	return _c
+}
+
+func (_c *MockStorage_FindTickersByIDs_Call) Return(tickers []Ticker, err error) *MockStorage_FindTickersByIDs_Call {
+	_c.Call.Return(tickers, err)
+	return _c
+}
+
+func (_c *MockStorage_FindTickersByIDs_Call) RunAndReturn(run func(ids []int, opts ...func(*gorm.DB) *gorm.DB) ([]Ticker, error)) *MockStorage_FindTickersByIDs_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// FindTickersByUser provides a mock function for the type MockStorage
+func (_mock *MockStorage) FindTickersByUser(user User, filter TickerFilter, opts ...func(*gorm.DB) *gorm.DB) ([]Ticker, error) {
+	var tmpRet mock.Arguments
+	if len(opts) > 0 {
+		tmpRet = _mock.Called(user, filter, opts)
+	} else {
+		tmpRet = _mock.Called(user, filter)
+	}
+	ret := tmpRet
+
+	if len(ret) == 0 {
+		panic("no return value specified for FindTickersByUser")
+	}
+
+	var r0 []Ticker
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(User, TickerFilter, ...func(*gorm.DB) *gorm.DB) ([]Ticker, error)); ok {
+		return returnFunc(user, filter, opts...)
+	}
+	if returnFunc, ok := ret.Get(0).(func(User, TickerFilter, ...func(*gorm.DB) *gorm.DB) []Ticker); ok {
+		r0 = returnFunc(user, filter, opts...)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]Ticker)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(User, TickerFilter, ...func(*gorm.DB) *gorm.DB) error); ok {
+		r1 = returnFunc(user, filter, opts...)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockStorage_FindTickersByUser_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'FindTickersByUser'
+type MockStorage_FindTickersByUser_Call struct {
+	*mock.Call
+}
+
+// FindTickersByUser is a helper method to define mock.On call
+//   - user User
+//   - filter TickerFilter
+//   - opts ...func(*gorm.DB) *gorm.DB
+func (_e *MockStorage_Expecter) FindTickersByUser(user interface{}, filter interface{}, opts ...interface{}) *MockStorage_FindTickersByUser_Call {
+	return &MockStorage_FindTickersByUser_Call{Call: _e.mock.On("FindTickersByUser",
+		append([]interface{}{user, filter}, opts...)...)}
+}
+
+func (_c *MockStorage_FindTickersByUser_Call) Run(run func(user User, filter TickerFilter, opts ...func(*gorm.DB) *gorm.DB)) *MockStorage_FindTickersByUser_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 User
+		if args[0] != nil {
+			arg0 = args[0].(User)
+		}
+		var arg1 TickerFilter
+		if args[1] != nil {
+			arg1 = args[1].(TickerFilter)
+		}
+		var arg2 []func(*gorm.DB) *gorm.DB
+		var variadicArgs []func(*gorm.DB) *gorm.DB
+		if len(args) > 2 {
+			variadicArgs = args[2].([]func(*gorm.DB) *gorm.DB)
+		}
+		arg2 = variadicArgs
+		run(
+			arg0,
+			arg1,
+			arg2...,
+		)
+	})
+	return _c
+}
+
+func (_c *MockStorage_FindTickersByUser_Call) Return(tickers []Ticker, err error) *MockStorage_FindTickersByUser_Call {
+	_c.Call.Return(tickers, err)
+	return _c
+}
+
+func (_c *MockStorage_FindTickersByUser_Call) RunAndReturn(run func(user User, filter TickerFilter, opts ...func(*gorm.DB) *gorm.DB) ([]Ticker, error)) *MockStorage_FindTickersByUser_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// FindUploadByUUID provides a mock function for the type MockStorage
+func (_mock *MockStorage) FindUploadByUUID(uuid string) (Upload, error) {
+	ret := _mock.Called(uuid)
+
+	if len(ret) == 0 {
+		panic("no return value specified for FindUploadByUUID")
+	}
+
+	var r0 Upload
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(string) (Upload, error)); ok {
+		return returnFunc(uuid)
+	}
+	if returnFunc, ok := ret.Get(0).(func(string) Upload); ok {
+		r0 = returnFunc(uuid)
+	} else {
+		r0 = ret.Get(0).(Upload)
+	}
+	if returnFunc, ok := ret.Get(1).(func(string) error); ok {
+		r1 = returnFunc(uuid)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockStorage_FindUploadByUUID_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'FindUploadByUUID'
+type MockStorage_FindUploadByUUID_Call struct {
+	*mock.Call
+}
+
+// FindUploadByUUID is a helper method to define mock.On call
+//   - uuid string
+func (_e *MockStorage_Expecter) FindUploadByUUID(uuid interface{}) *MockStorage_FindUploadByUUID_Call {
+	return &MockStorage_FindUploadByUUID_Call{Call: _e.mock.On("FindUploadByUUID", uuid)}
+}
+
+func (_c *MockStorage_FindUploadByUUID_Call) Run(run func(uuid string)) *MockStorage_FindUploadByUUID_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 string
+		if args[0] != nil {
+			arg0 = args[0].(string)
+		}
+		run(
+			arg0,
+		)
+	})
+	return _c
+}
+
+func (_c *MockStorage_FindUploadByUUID_Call) Return(upload Upload, err error) *MockStorage_FindUploadByUUID_Call {
+	_c.Call.Return(upload, err)
+	return _c
+}
+
+func (_c *MockStorage_FindUploadByUUID_Call) RunAndReturn(run func(uuid string) (Upload, error)) *MockStorage_FindUploadByUUID_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// FindUploadsByIDs provides a mock function for the type MockStorage
+func (_mock *MockStorage) FindUploadsByIDs(ids []int) ([]Upload, error) {
+	ret := _mock.Called(ids)
+
+	if len(ret) == 0 {
+		panic("no return value specified for FindUploadsByIDs")
+	}
+
+	var r0 []Upload
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func([]int) ([]Upload, error)); ok {
+		return returnFunc(ids)
+	}
+	if returnFunc, ok := ret.Get(0).(func([]int) []Upload); ok {
+		r0 = returnFunc(ids)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]Upload)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func([]int) error); ok {
+		r1 = returnFunc(ids)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockStorage_FindUploadsByIDs_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'FindUploadsByIDs'
+type MockStorage_FindUploadsByIDs_Call struct {
+	*mock.Call
+}
+
+// FindUploadsByIDs is a helper method to define mock.On call
+//   - ids []int
+func (_e *MockStorage_Expecter) FindUploadsByIDs(ids interface{}) *MockStorage_FindUploadsByIDs_Call {
+	return &MockStorage_FindUploadsByIDs_Call{Call: _e.mock.On("FindUploadsByIDs", ids)}
+}
+
+func (_c *MockStorage_FindUploadsByIDs_Call) Run(run func(ids []int)) *MockStorage_FindUploadsByIDs_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 []int
+		if args[0] != nil {
+			arg0 = args[0].([]int)
+		}
+		run(
+			arg0,
+		)
+	})
+	return _c
+}
+
+func (_c *MockStorage_FindUploadsByIDs_Call) Return(uploads []Upload, err error) *MockStorage_FindUploadsByIDs_Call {
+	_c.Call.Return(uploads, err)
+	return _c
+}
+
+func (_c *MockStorage_FindUploadsByIDs_Call) RunAndReturn(run func(ids []int) ([]Upload, error)) *MockStorage_FindUploadsByIDs_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// FindUserByEmail provides a mock function for the type MockStorage
+func (_mock *MockStorage) FindUserByEmail(email string, opts ...func(*gorm.DB) *gorm.DB) (User, error) {
+	var tmpRet mock.Arguments
+	if len(opts) > 0 {
+		tmpRet = _mock.Called(email, opts)
+	} else {
+		tmpRet = _mock.Called(email)
+	}
+	ret := tmpRet
+
+	if len(ret) == 0 {
+		panic("no return value specified for FindUserByEmail")
+	}
+
+	var r0 User
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(string, ...func(*gorm.DB) *gorm.DB) (User, error)); ok {
+		return returnFunc(email, opts...)
+	}
+	if returnFunc, ok := ret.Get(0).(func(string, ...func(*gorm.DB) *gorm.DB) User); ok {
+		r0 = returnFunc(email, opts...)
+	} else {
+		r0 = ret.Get(0).(User)
+	}
+	if returnFunc, ok := ret.Get(1).(func(string, ...func(*gorm.DB) *gorm.DB) error); ok {
+		r1 = returnFunc(email, opts...)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockStorage_FindUserByEmail_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'FindUserByEmail'
+type MockStorage_FindUserByEmail_Call struct {
+	*mock.Call
+}
+
+// FindUserByEmail is a helper method to define mock.On call
+//   - email string
+//   - opts ...func(*gorm.DB) *gorm.DB
+func (_e *MockStorage_Expecter) FindUserByEmail(email interface{}, opts ...interface{}) *MockStorage_FindUserByEmail_Call {
+	return &MockStorage_FindUserByEmail_Call{Call: _e.mock.On("FindUserByEmail",
+		append([]interface{}{email}, opts...)...)}
+}
+
+func (_c *MockStorage_FindUserByEmail_Call) Run(run func(email string, opts ...func(*gorm.DB) *gorm.DB)) *MockStorage_FindUserByEmail_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 string
+		if args[0] != nil {
+			arg0 = args[0].(string)
+		}
+		var arg1 []func(*gorm.DB) *gorm.DB
+		var variadicArgs []func(*gorm.DB) *gorm.DB
+		if len(args) > 1 {
+			variadicArgs = args[1].([]func(*gorm.DB) *gorm.DB)
+		}
+		arg1 = variadicArgs
+		run(
+			arg0,
+			arg1...,
+		)
+	})
+	return _c
+}
+
+func (_c *MockStorage_FindUserByEmail_Call) Return(user User, err error) *MockStorage_FindUserByEmail_Call {
+	_c.Call.Return(user, err)
+	return _c
+}
+
+func (_c *MockStorage_FindUserByEmail_Call) RunAndReturn(run func(email string, opts ...func(*gorm.DB) *gorm.DB) (User, error)) *MockStorage_FindUserByEmail_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// FindUserByID provides a mock function for the type MockStorage
+func (_mock *MockStorage) FindUserByID(id int, opts ...func(*gorm.DB) *gorm.DB) (User, error) {
+	var tmpRet mock.Arguments
+	if len(opts) > 0 {
+		tmpRet = _mock.Called(id, opts)
+	} else {
+		tmpRet = _mock.Called(id)
+	}
+	ret := tmpRet
+
+	if len(ret) == 0 {
+		panic("no return value specified for FindUserByID")
+	}
+
+	var r0 User
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(int, ...func(*gorm.DB) *gorm.DB) (User, error)); ok {
+		return returnFunc(id, opts...)
+	}
+	if returnFunc, ok := ret.Get(0).(func(int, ...func(*gorm.DB) *gorm.DB) User); ok {
+		r0 = returnFunc(id, opts...)
+	} else {
+		r0 = ret.Get(0).(User)
+	}
+	if returnFunc, ok := ret.Get(1).(func(int, ...func(*gorm.DB) *gorm.DB) error); ok {
+		r1 = returnFunc(id, opts...)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockStorage_FindUserByID_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'FindUserByID'
+type MockStorage_FindUserByID_Call struct {
+	*mock.Call
+}
+
+// FindUserByID is a helper method to define mock.On call
+//   - id int
+//   - opts ...func(*gorm.DB) *gorm.DB
+func (_e *MockStorage_Expecter) FindUserByID(id interface{}, opts ...interface{}) *MockStorage_FindUserByID_Call {
+	return &MockStorage_FindUserByID_Call{Call: _e.mock.On("FindUserByID",
+		append([]interface{}{id}, opts...)...)}
+}
+
+func (_c *MockStorage_FindUserByID_Call) Run(run func(id int, opts ...func(*gorm.DB) *gorm.DB)) *MockStorage_FindUserByID_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 int
+		if args[0] != nil {
+			arg0 = args[0].(int)
+		}
+		var arg1 []func(*gorm.DB) *gorm.DB
+		var variadicArgs []func(*gorm.DB) *gorm.DB
+		if len(args) > 1 {
+			variadicArgs = args[1].([]func(*gorm.DB) *gorm.DB)
+		}
+		arg1 = variadicArgs
+		run(
+			arg0,
+			arg1...,
+		)
+	})
+	return _c
+}
+
+func (_c *MockStorage_FindUserByID_Call) Return(user User, err error) *MockStorage_FindUserByID_Call {
+	_c.Call.Return(user, err)
+	return _c
+}
+
+func (_c *MockStorage_FindUserByID_Call) RunAndReturn(run func(id int, opts ...func(*gorm.DB) *gorm.DB) (User, error)) *MockStorage_FindUserByID_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// FindUserByOIDCSubject provides a mock function for the type MockStorage
+func (_mock *MockStorage) FindUserByOIDCSubject(subject string, opts ...func(*gorm.DB) *gorm.DB) (User, error) {
+	var tmpRet mock.Arguments
+	if len(opts) > 0 {
+		tmpRet = _mock.Called(subject, opts)
+	} else {
+		tmpRet = _mock.Called(subject)
+	}
+	ret := tmpRet
+
+	if len(ret) == 0 {
+		panic("no return value specified for FindUserByOIDCSubject")
+	}
+
+	var r0 User
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(string, ...func(*gorm.DB) *gorm.DB) (User, error)); ok {
+		return returnFunc(subject, opts...)
+	}
+	if returnFunc, ok := ret.Get(0).(func(string, ...func(*gorm.DB) *gorm.DB) User); ok {
+		r0 = returnFunc(subject, opts...)
+	} else {
+		r0 = ret.Get(0).(User)
+	}
+	if returnFunc, ok := ret.Get(1).(func(string, ...func(*gorm.DB) *gorm.DB) error); ok {
+		r1 = returnFunc(subject, opts...)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockStorage_FindUserByOIDCSubject_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'FindUserByOIDCSubject'
+type MockStorage_FindUserByOIDCSubject_Call struct {
+	*mock.Call
+}
+
+// FindUserByOIDCSubject is a helper method to define mock.On call
+//   - subject string
+//   - opts ...func(*gorm.DB) *gorm.DB
+func (_e *MockStorage_Expecter) FindUserByOIDCSubject(subject interface{}, opts ...interface{}) *MockStorage_FindUserByOIDCSubject_Call {
+	return &MockStorage_FindUserByOIDCSubject_Call{Call: _e.mock.On("FindUserByOIDCSubject",
+		append([]interface{}{subject}, opts...)...)}
+}
+
+func (_c *MockStorage_FindUserByOIDCSubject_Call) Run(run func(subject string, opts ...func(*gorm.DB) *gorm.DB)) *MockStorage_FindUserByOIDCSubject_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 string
+		if args[0] != nil {
+			arg0 = args[0].(string)
+		}
+		var arg1 []func(*gorm.DB) *gorm.DB
+		var variadicArgs []func(*gorm.DB) *gorm.DB
+		if len(args) > 1 {
+			variadicArgs = args[1].([]func(*gorm.DB) *gorm.DB)
+		}
+		arg1 = variadicArgs
+		run(
+			arg0,
+			arg1...,
+		)
+	})
+	return _c
+}
+
+func (_c *MockStorage_FindUserByOIDCSubject_Call) Return(user User, err error) *MockStorage_FindUserByOIDCSubject_Call {
+	_c.Call.Return(user, err)
+	return _c
+}
+
+func (_c *MockStorage_FindUserByOIDCSubject_Call) RunAndReturn(run func(subject string, opts ...func(*gorm.DB) *gorm.DB) (User, error)) *MockStorage_FindUserByOIDCSubject_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// FindUserByTelegramID provides a mock function for the type MockStorage
+func (_mock *MockStorage) FindUserByTelegramID(telegramID int64, opts ...func(*gorm.DB) *gorm.DB) (User, error) {
+	var tmpRet mock.Arguments
+	if len(opts) > 0 {
+		tmpRet = _mock.Called(telegramID, opts)
+	} else {
+		tmpRet = _mock.Called(telegramID)
+	}
+	ret := tmpRet
+
+	if len(ret) == 0 {
+		panic("no return value specified for FindUserByTelegramID")
+	}
+
+	var r0 User
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(int64, ...func(*gorm.DB) *gorm.DB) (User, error)); ok {
+		return returnFunc(telegramID, opts...)
+	}
+	if returnFunc, ok := ret.Get(0).(func(int64, ...func(*gorm.DB) *gorm.DB) User); ok {
+		r0 = returnFunc(telegramID, opts...)
+	} else {
+		r0 = ret.Get(0).(User)
+	}
+	if returnFunc, ok := ret.Get(1).(func(int64, ...func(*gorm.DB) *gorm.DB) error); ok {
+		r1 = returnFunc(telegramID, opts...)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockStorage_FindUserByTelegramID_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'FindUserByTelegramID'
+type MockStorage_FindUserByTelegramID_Call struct {
+	*mock.Call
+}
+
+// FindUserByTelegramID is a helper method to define mock.On call
+//   - telegramID int64
+//   - opts ...func(*gorm.DB) *gorm.DB
+func (_e *MockStorage_Expecter) FindUserByTelegramID(telegramID interface{}, opts ...interface{}) *MockStorage_FindUserByTelegramID_Call {
+	return &MockStorage_FindUserByTelegramID_Call{Call: _e.mock.On("FindUserByTelegramID",
+		append([]interface{}{telegramID}, opts...)...)}
+}
+
+func (_c *MockStorage_FindUserByTelegramID_Call) Run(run func(telegramID int64, opts ...func(*gorm.DB) *gorm.DB)) *MockStorage_FindUserByTelegramID_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 int64
+		if args[0] != nil {
+			arg0 = args[0].(int64)
+		}
+		var arg1 []func(*gorm.DB) *gorm.DB
+		var variadicArgs []func(*gorm.DB) *gorm.DB
+		if len(args) > 1 {
+			variadicArgs = args[1].([]func(*gorm.DB) *gorm.DB)
+		}
+		arg1 = variadicArgs
+		run(
+			arg0,
+			arg1...,
+		)
+	})
+	return _c
+}
+
+func (_c *MockStorage_FindUserByTelegramID_Call) Return(user User, err error) *MockStorage_FindUserByTelegramID_Call {
+	_c.Call.Return(user, err)
+	return _c
+}
+
+func (_c *MockStorage_FindUserByTelegramID_Call) RunAndReturn(run func(telegramID int64, opts ...func(*gorm.DB) *gorm.DB) (User, error)) *MockStorage_FindUserByTelegramID_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// FindUserByWebAuthnHandle provides a mock function for the type MockStorage
+func (_mock *MockStorage) FindUserByWebAuthnHandle(handle string, opts ...func(*gorm.DB) *gorm.DB) (User, error) {
 	var tmpRet mock.Arguments
 	if len(opts) > 0 {
-		tmpRet = _mock.Called(user, id, opts)
+		tmpRet = _mock.Called(handle, opts)
+	} else {
+		tmpRet = _mock.Called(handle)
+	}
+	ret := tmpRet
+
+	if len(ret) == 0 {
+		panic("no return value specified for FindUserByWebAuthnHandle")
+	}
+
+	var r0 User
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(string, ...func(*gorm.DB) *gorm.DB) (User, error)); ok {
+		return returnFunc(handle, opts...)
+	}
+	if returnFunc, ok := ret.Get(0).(func(string, ...func(*gorm.DB) *gorm.DB) User); ok {
+		r0 = returnFunc(handle, opts...)
+	} else {
+		r0 = ret.Get(0).(User)
+	}
+	if returnFunc, ok := ret.Get(1).(func(string, ...func(*gorm.DB) *gorm.DB) error); ok {
+		r1 = returnFunc(handle, opts...)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockStorage_FindUserByWebAuthnHandle_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'FindUserByWebAuthnHandle'
+type MockStorage_FindUserByWebAuthnHandle_Call struct {
+	*mock.Call
+}
+
+// FindUserByWebAuthnHandle is a helper method to define mock.On call
+//   - handle string
+//   - opts ...func(*gorm.DB) *gorm.DB
+func (_e *MockStorage_Expecter) FindUserByWebAuthnHandle(handle interface{}, opts ...interface{}) *MockStorage_FindUserByWebAuthnHandle_Call {
+	return &MockStorage_FindUserByWebAuthnHandle_Call{Call: _e.mock.On("FindUserByWebAuthnHandle",
+		append([]interface{}{handle}, opts...)...)}
+}
+
+func (_c *MockStorage_FindUserByWebAuthnHandle_Call) Run(run func(handle string, opts ...func(*gorm.DB) *gorm.DB)) *MockStorage_FindUserByWebAuthnHandle_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 string
+		if args[0] != nil {
+			arg0 = args[0].(string)
+		}
+		var arg1 []func(*gorm.DB) *gorm.DB
+		var variadicArgs []func(*gorm.DB) *gorm.DB
+		if len(args) > 1 {
+			variadicArgs = args[1].([]func(*gorm.DB) *gorm.DB)
+		}
+		arg1 = variadicArgs
+		run(
+			arg0,
+			arg1...,
+		)
+	})
+	return _c
+}
+
+func (_c *MockStorage_FindUserByWebAuthnHandle_Call) Return(user User, err error) *MockStorage_FindUserByWebAuthnHandle_Call {
+	_c.Call.Return(user, err)
+	return _c
+}
+
+func (_c *MockStorage_FindUserByWebAuthnHandle_Call) RunAndReturn(run func(handle string, opts ...func(*gorm.DB) *gorm.DB) (User, error)) *MockStorage_FindUserByWebAuthnHandle_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// FindUsers provides a mock function for the type MockStorage
+func (_mock *MockStorage) FindUsers(filter UserFilter, opts ...func(*gorm.DB) *gorm.DB) ([]User, error) {
+	var tmpRet mock.Arguments
+	if len(opts) > 0 {
+		tmpRet = _mock.Called(filter, opts)
+	} else {
+		tmpRet = _mock.Called(filter)
+	}
+	ret := tmpRet
+
+	if len(ret) == 0 {
+		panic("no return value specified for FindUsers")
+	}
+
+	var r0 []User
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(UserFilter, ...func(*gorm.DB) *gorm.DB) ([]User, error)); ok {
+		return returnFunc(filter, opts...)
+	}
+	if returnFunc, ok := ret.Get(0).(func(UserFilter, ...func(*gorm.DB) *gorm.DB) []User); ok {
+		r0 = returnFunc(filter, opts...)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]User)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(UserFilter, ...func(*gorm.DB) *gorm.DB) error); ok {
+		r1 = returnFunc(filter, opts...)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockStorage_FindUsers_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'FindUsers'
+type MockStorage_FindUsers_Call struct {
+	*mock.Call
+}
+
+// FindUsers is a helper method to define mock.On call
+//   - filter UserFilter
+//   - opts ...func(*gorm.DB) *gorm.DB
+func (_e *MockStorage_Expecter) FindUsers(filter interface{}, opts ...interface{}) *MockStorage_FindUsers_Call {
+	return &MockStorage_FindUsers_Call{Call: _e.mock.On("FindUsers",
+		append([]interface{}{filter}, opts...)...)}
+}
+
+func (_c *MockStorage_FindUsers_Call) Run(run func(filter UserFilter, opts ...func(*gorm.DB) *gorm.DB)) *MockStorage_FindUsers_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 UserFilter
+		if args[0] != nil {
+			arg0 = args[0].(UserFilter)
+		}
+		var arg1 []func(*gorm.DB) *gorm.DB
+		var variadicArgs []func(*gorm.DB) *gorm.DB
+		if len(args) > 1 {
+			variadicArgs = args[1].([]func(*gorm.DB) *gorm.DB)
+		}
+		arg1 = variadicArgs
+		run(
+			arg0,
+			arg1...,
+		)
+	})
+	return _c
+}
+
+func (_c *MockStorage_FindUsers_Call) Return(users []User, err error) *MockStorage_FindUsers_Call {
+	_c.Call.Return(users, err)
+	return _c
+}
+
+func (_c *MockStorage_FindUsers_Call) RunAndReturn(run func(filter UserFilter, opts ...func(*gorm.DB) *gorm.DB) ([]User, error)) *MockStorage_FindUsers_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// FindUsersByIDs provides a mock function for the type MockStorage
+func (_mock *MockStorage) FindUsersByIDs(ids []int, opts ...func(*gorm.DB) *gorm.DB) ([]User, error) {
+	var tmpRet mock.Arguments
+	if len(opts) > 0 {
+		tmpRet = _mock.Called(ids, opts)
+	} else {
+		tmpRet = _mock.Called(ids)
+	}
+	ret := tmpRet
+
+	if len(ret) == 0 {
+		panic("no return value specified for FindUsersByIDs")
+	}
+
+	var r0 []User
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func([]int, ...func(*gorm.DB) *gorm.DB) ([]User, error)); ok {
+		return returnFunc(ids, opts...)
+	}
+	if returnFunc, ok := ret.Get(0).(func([]int, ...func(*gorm.DB) *gorm.DB) []User); ok {
+		r0 = returnFunc(ids, opts...)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]User)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func([]int, ...func(*gorm.DB) *gorm.DB) error); ok {
+		r1 = returnFunc(ids, opts...)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockStorage_FindUsersByIDs_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'FindUsersByIDs'
+type MockStorage_FindUsersByIDs_Call struct {
+	*mock.Call
+}
+
+// FindUsersByIDs is a helper method to define mock.On call
+//   - ids []int
+//   - opts ...func(*gorm.DB) *gorm.DB
+func (_e *MockStorage_Expecter) FindUsersByIDs(ids interface{}, opts ...interface{}) *MockStorage_FindUsersByIDs_Call {
+	return &MockStorage_FindUsersByIDs_Call{Call: _e.mock.On("FindUsersByIDs",
+		append([]interface{}{ids}, opts...)...)}
+}
+
+func (_c *MockStorage_FindUsersByIDs_Call) Run(run func(ids []int, opts ...func(*gorm.DB) *gorm.DB)) *MockStorage_FindUsersByIDs_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 []int
+		if args[0] != nil {
+			arg0 = args[0].([]int)
+		}
+		var arg1 []func(*gorm.DB) *gorm.DB
+		var variadicArgs []func(*gorm.DB) *gorm.DB
+		if len(args) > 1 {
+			variadicArgs = args[1].([]func(*gorm.DB) *gorm.DB)
+		}
+		arg1 = variadicArgs
+		run(
+			arg0,
+			arg1...,
+		)
+	})
+	return _c
+}
+
+func (_c *MockStorage_FindUsersByIDs_Call) Return(users []User, err error) *MockStorage_FindUsersByIDs_Call {
+	_c.Call.Return(users, err)
+	return _c
+}
+
+func (_c *MockStorage_FindUsersByIDs_Call) RunAndReturn(run func(ids []int, opts ...func(*gorm.DB) *gorm.DB) ([]User, error)) *MockStorage_FindUsersByIDs_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// FindUsersByTicker provides a mock function for the type MockStorage
+func (_mock *MockStorage) FindUsersByTicker(ticker Ticker, opts ...func(*gorm.DB) *gorm.DB) ([]User, error) {
+	var tmpRet mock.Arguments
+	if len(opts) > 0 {
+		tmpRet = _mock.Called(ticker, opts)
+	} else {
+		tmpRet = _mock.Called(ticker)
+	}
+	ret := tmpRet
+
+	if len(ret) == 0 {
+		panic("no return value specified for FindUsersByTicker")
+	}
+
+	var r0 []User
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(Ticker, ...func(*gorm.DB) *gorm.DB) ([]User, error)); ok {
+		return returnFunc(ticker, opts...)
+	}
+	if returnFunc, ok := ret.Get(0).(func(Ticker, ...func(*gorm.DB) *gorm.DB) []User); ok {
+		r0 = returnFunc(ticker, opts...)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]User)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(Ticker, ...func(*gorm.DB) *gorm.DB) error); ok {
+		r1 = returnFunc(ticker, opts...)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockStorage_FindUsersByTicker_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'FindUsersByTicker'
+type MockStorage_FindUsersByTicker_Call struct {
+	*mock.Call
+}
+
+// FindUsersByTicker is a helper method to define mock.On call
+//   - ticker Ticker
+//   - opts ...func(*gorm.DB) *gorm.DB
+func (_e *MockStorage_Expecter) FindUsersByTicker(ticker interface{}, opts ...interface{}) *MockStorage_FindUsersByTicker_Call {
+	return &MockStorage_FindUsersByTicker_Call{Call: _e.mock.On("FindUsersByTicker",
+		append([]interface{}{ticker}, opts...)...)}
+}
+
+func (_c *MockStorage_FindUsersByTicker_Call) Run(run func(ticker Ticker, opts ...func(*gorm.DB) *gorm.DB)) *MockStorage_FindUsersByTicker_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 Ticker
+		if args[0] != nil {
+			arg0 = args[0].(Ticker)
+		}
+		var arg1 []func(*gorm.DB) *gorm.DB
+		var variadicArgs []func(*gorm.DB) *gorm.DB
+		if len(args) > 1 {
+			variadicArgs = args[1].([]func(*gorm.DB) *gorm.DB)
+		}
+		arg1 = variadicArgs
+		run(
+			arg0,
+			arg1...,
+		)
+	})
+	return _c
+}
+
+func (_c *MockStorage_FindUsersByTicker_Call) Return(users []User, err error) *MockStorage_FindUsersByTicker_Call {
+	_c.Call.Return(users, err)
+	return _c
+}
+
+func (_c *MockStorage_FindUsersByTicker_Call) RunAndReturn(run func(ticker Ticker, opts ...func(*gorm.DB) *gorm.DB) ([]User, error)) *MockStorage_FindUsersByTicker_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// FindWebAuthnCredentialByID provides a mock function for the type MockStorage
+func (_mock *MockStorage) FindWebAuthnCredentialByID(user User, id int) (WebAuthnCredential, error) {
+	ret := _mock.Called(user, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for FindWebAuthnCredentialByID")
+	}
+
+	var r0 WebAuthnCredential
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(User, int) (WebAuthnCredential, error)); ok {
+		return returnFunc(user, id)
+	}
+	if returnFunc, ok := ret.Get(0).(func(User, int) WebAuthnCredential); ok {
+		r0 = returnFunc(user, id)
+	} else {
+		r0 = ret.Get(0).(WebAuthnCredential)
+	}
+	if returnFunc, ok := ret.Get(1).(func(User, int) error); ok {
+		r1 = returnFunc(user, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockStorage_FindWebAuthnCredentialByID_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'FindWebAuthnCredentialByID'
+type MockStorage_FindWebAuthnCredentialByID_Call struct {
+	*mock.Call
+}
+
+// FindWebAuthnCredentialByID is a helper method to define mock.On call
+//   - user User
+//   - id int
+func (_e *MockStorage_Expecter) FindWebAuthnCredentialByID(user interface{}, id interface{}) *MockStorage_FindWebAuthnCredentialByID_Call {
+	return &MockStorage_FindWebAuthnCredentialByID_Call{Call: _e.mock.On("FindWebAuthnCredentialByID", user, id)}
+}
+
+func (_c *MockStorage_FindWebAuthnCredentialByID_Call) Run(run func(user User, id int)) *MockStorage_FindWebAuthnCredentialByID_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 User
+		if args[0] != nil {
+			arg0 = args[0].(User)
+		}
+		var arg1 int
+		if args[1] != nil {
+			arg1 = args[1].(int)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *MockStorage_FindWebAuthnCredentialByID_Call) Return(webAuthnCredential WebAuthnCredential, err error) *MockStorage_FindWebAuthnCredentialByID_Call {
+	_c.Call.Return(webAuthnCredential, err)
+	return _c
+}
+
+func (_c *MockStorage_FindWebAuthnCredentialByID_Call) RunAndReturn(run func(user User, id int) (WebAuthnCredential, error)) *MockStorage_FindWebAuthnCredentialByID_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// FindWebAuthnCredentials provides a mock function for the type MockStorage
+func (_mock *MockStorage) FindWebAuthnCredentials(user User) ([]WebAuthnCredential, error) {
+	ret := _mock.Called(user)
+
+	if len(ret) == 0 {
+		panic("no return value specified for FindWebAuthnCredentials")
+	}
+
+	var r0 []WebAuthnCredential
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(User) ([]WebAuthnCredential, error)); ok {
+		return returnFunc(user)
+	}
+	if returnFunc, ok := ret.Get(0).(func(User) []WebAuthnCredential); ok {
+		r0 = returnFunc(user)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]WebAuthnCredential)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(User) error); ok {
+		r1 = returnFunc(user)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockStorage_FindWebAuthnCredentials_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'FindWebAuthnCredentials'
+type MockStorage_FindWebAuthnCredentials_Call struct {
+	*mock.Call
+}
+
+// FindWebAuthnCredentials is a helper method to define mock.On call
+//   - user User
+func (_e *MockStorage_Expecter) FindWebAuthnCredentials(user interface{}) *MockStorage_FindWebAuthnCredentials_Call {
+	return &MockStorage_FindWebAuthnCredentials_Call{Call: _e.mock.On("FindWebAuthnCredentials", user)}
+}
+
+func (_c *MockStorage_FindWebAuthnCredentials_Call) Run(run func(user User)) *MockStorage_FindWebAuthnCredentials_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 User
+		if args[0] != nil {
+			arg0 = args[0].(User)
+		}
+		run(
+			arg0,
+		)
+	})
+	return _c
+}
+
+func (_c *MockStorage_FindWebAuthnCredentials_Call) Return(webAuthnCredentials []WebAuthnCredential, err error) *MockStorage_FindWebAuthnCredentials_Call {
+	_c.Call.Return(webAuthnCredentials, err)
+	return _c
+}
+
+func (_c *MockStorage_FindWebAuthnCredentials_Call) RunAndReturn(run func(user User) ([]WebAuthnCredential, error)) *MockStorage_FindWebAuthnCredentials_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// FindWebPushSubscriptions provides a mock function for the type MockStorage
+func (_mock *MockStorage) FindWebPushSubscriptions(ticker Ticker) ([]WebPushSubscription, error) {
+	ret := _mock.Called(ticker)
+
+	if len(ret) == 0 {
+		panic("no return value specified for FindWebPushSubscriptions")
+	}
+
+	var r0 []WebPushSubscription
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(Ticker) ([]WebPushSubscription, error)); ok {
+		return returnFunc(ticker)
+	}
+	if returnFunc, ok := ret.Get(0).(func(Ticker) []WebPushSubscription); ok {
+		r0 = returnFunc(ticker)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]WebPushSubscription)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(Ticker) error); ok {
+		r1 = returnFunc(ticker)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockStorage_FindWebPushSubscriptions_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'FindWebPushSubscriptions'
+type MockStorage_FindWebPushSubscriptions_Call struct {
+	*mock.Call
+}
+
+// FindWebPushSubscriptions is a helper method to define mock.On call
+//   - ticker Ticker
+func (_e *MockStorage_Expecter) FindWebPushSubscriptions(ticker interface{}) *MockStorage_FindWebPushSubscriptions_Call {
+	return &MockStorage_FindWebPushSubscriptions_Call{Call: _e.mock.On("FindWebPushSubscriptions", ticker)}
+}
+
+func (_c *MockStorage_FindWebPushSubscriptions_Call) Run(run func(ticker Ticker)) *MockStorage_FindWebPushSubscriptions_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 Ticker
+		if args[0] != nil {
+			arg0 = args[0].(Ticker)
+		}
+		run(
+			arg0,
+		)
+	})
+	return _c
+}
+
+func (_c *MockStorage_FindWebPushSubscriptions_Call) Return(webPushSubscriptions []WebPushSubscription, err error) *MockStorage_FindWebPushSubscriptions_Call {
+	_c.Call.Return(webPushSubscriptions, err)
+	return _c
+}
+
+func (_c *MockStorage_FindWebPushSubscriptions_Call) RunAndReturn(run func(ticker Ticker) ([]WebPushSubscription, error)) *MockStorage_FindWebPushSubscriptions_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetInactiveSettings provides a mock function for the type MockStorage
+func (_mock *MockStorage) GetInactiveSettings() InactiveSettings {
+	ret := _mock.Called()
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetInactiveSettings")
+	}
+
+	var r0 InactiveSettings
+	if returnFunc, ok := ret.Get(0).(func() InactiveSettings); ok {
+		r0 = returnFunc()
+	} else {
+		r0 = ret.Get(0).(InactiveSettings)
+	}
+	return r0
+}
+
+// MockStorage_GetInactiveSettings_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetInactiveSettings'
+type MockStorage_GetInactiveSettings_Call struct {
+	*mock.Call
+}
+
+// GetInactiveSettings is a helper method to define mock.On call
+func (_e *MockStorage_Expecter) GetInactiveSettings() *MockStorage_GetInactiveSettings_Call {
+	return &MockStorage_GetInactiveSettings_Call{Call: _e.mock.On("GetInactiveSettings")}
+}
+
+func (_c *MockStorage_GetInactiveSettings_Call) Run(run func()) *MockStorage_GetInactiveSettings_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run()
+	})
+	return _c
+}
+
+func (_c *MockStorage_GetInactiveSettings_Call) Return(inactiveSettings InactiveSettings) *MockStorage_GetInactiveSettings_Call {
+	_c.Call.Return(inactiveSettings)
+	return _c
+}
+
+func (_c *MockStorage_GetInactiveSettings_Call) RunAndReturn(run func() InactiveSettings) *MockStorage_GetInactiveSettings_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetSecuritySettings provides a mock function for the type MockStorage
+func (_mock *MockStorage) GetSecuritySettings() SecuritySettings {
+	ret := _mock.Called()
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetSecuritySettings")
+	}
+
+	var r0 SecuritySettings
+	if returnFunc, ok := ret.Get(0).(func() SecuritySettings); ok {
+		r0 = returnFunc()
+	} else {
+		r0 = ret.Get(0).(SecuritySettings)
+	}
+	return r0
+}
+
+// MockStorage_GetSecuritySettings_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetSecuritySettings'
+type MockStorage_GetSecuritySettings_Call struct {
+	*mock.Call
+}
+
+// GetSecuritySettings is a helper method to define mock.On call
+func (_e *MockStorage_Expecter) GetSecuritySettings() *MockStorage_GetSecuritySettings_Call {
+	return &MockStorage_GetSecuritySettings_Call{Call: _e.mock.On("GetSecuritySettings")}
+}
+
+func (_c *MockStorage_GetSecuritySettings_Call) Run(run func()) *MockStorage_GetSecuritySettings_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run()
+	})
+	return _c
+}
+
+func (_c *MockStorage_GetSecuritySettings_Call) Return(securitySettings SecuritySettings) *MockStorage_GetSecuritySettings_Call {
+	_c.Call.Return(securitySettings)
+	return _c
+}
+
+func (_c *MockStorage_GetSecuritySettings_Call) RunAndReturn(run func() SecuritySettings) *MockStorage_GetSecuritySettings_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetSignalGroupSettings provides a mock function for the type MockStorage
+func (_mock *MockStorage) GetSignalGroupSettings() SignalGroupSettings {
+	ret := _mock.Called()
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetSignalGroupSettings")
+	}
+
+	var r0 SignalGroupSettings
+	if returnFunc, ok := ret.Get(0).(func() SignalGroupSettings); ok {
+		r0 = returnFunc()
+	} else {
+		r0 = ret.Get(0).(SignalGroupSettings)
+	}
+	return r0
+}
+
+// MockStorage_GetSignalGroupSettings_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetSignalGroupSettings'
+type MockStorage_GetSignalGroupSettings_Call struct {
+	*mock.Call
+}
+
+// GetSignalGroupSettings is a helper method to define mock.On call
+func (_e *MockStorage_Expecter) GetSignalGroupSettings() *MockStorage_GetSignalGroupSettings_Call {
+	return &MockStorage_GetSignalGroupSettings_Call{Call: _e.mock.On("GetSignalGroupSettings")}
+}
+
+func (_c *MockStorage_GetSignalGroupSettings_Call) Run(run func()) *MockStorage_GetSignalGroupSettings_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run()
+	})
+	return _c
+}
+
+func (_c *MockStorage_GetSignalGroupSettings_Call) Return(signalGroupSettings SignalGroupSettings) *MockStorage_GetSignalGroupSettings_Call {
+	_c.Call.Return(signalGroupSettings)
+	return _c
+}
+
+func (_c *MockStorage_GetSignalGroupSettings_Call) RunAndReturn(run func() SignalGroupSettings) *MockStorage_GetSignalGroupSettings_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetTelegramSettings provides a mock function for the type MockStorage
+func (_mock *MockStorage) GetTelegramSettings() TelegramSettings {
+	ret := _mock.Called()
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetTelegramSettings")
+	}
+
+	var r0 TelegramSettings
+	if returnFunc, ok := ret.Get(0).(func() TelegramSettings); ok {
+		r0 = returnFunc()
+	} else {
+		r0 = ret.Get(0).(TelegramSettings)
+	}
+	return r0
+}
+
+// MockStorage_GetTelegramSettings_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetTelegramSettings'
+type MockStorage_GetTelegramSettings_Call struct {
+	*mock.Call
+}
+
+// GetTelegramSettings is a helper method to define mock.On call
+func (_e *MockStorage_Expecter) GetTelegramSettings() *MockStorage_GetTelegramSettings_Call {
+	return &MockStorage_GetTelegramSettings_Call{Call: _e.mock.On("GetTelegramSettings")}
+}
+
+func (_c *MockStorage_GetTelegramSettings_Call) Run(run func()) *MockStorage_GetTelegramSettings_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run()
+	})
+	return _c
+}
+
+func (_c *MockStorage_GetTelegramSettings_Call) Return(telegramSettings TelegramSettings) *MockStorage_GetTelegramSettings_Call {
+	_c.Call.Return(telegramSettings)
+	return _c
+}
+
+func (_c *MockStorage_GetTelegramSettings_Call) RunAndReturn(run func() TelegramSettings) *MockStorage_GetTelegramSettings_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetWebPushSettings provides a mock function for the type MockStorage
+func (_mock *MockStorage) GetWebPushSettings() WebPushSettings {
+	ret := _mock.Called()
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetWebPushSettings")
+	}
+
+	var r0 WebPushSettings
+	if returnFunc, ok := ret.Get(0).(func() WebPushSettings); ok {
+		r0 = returnFunc()
+	} else {
+		r0 = ret.Get(0).(WebPushSettings)
+	}
+	return r0
+}
+
+// MockStorage_GetWebPushSettings_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetWebPushSettings'
+type MockStorage_GetWebPushSettings_Call struct {
+	*mock.Call
+}
+
+// GetWebPushSettings is a helper method to define mock.On call
+func (_e *MockStorage_Expecter) GetWebPushSettings() *MockStorage_GetWebPushSettings_Call {
+	return &MockStorage_GetWebPushSettings_Call{Call: _e.mock.On("GetWebPushSettings")}
+}
+
+func (_c *MockStorage_GetWebPushSettings_Call) Run(run func()) *MockStorage_GetWebPushSettings_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run()
+	})
+	return _c
+}
+
+func (_c *MockStorage_GetWebPushSettings_Call) Return(webPushSettings WebPushSettings) *MockStorage_GetWebPushSettings_Call {
+	_c.Call.Return(webPushSettings)
+	return _c
+}
+
+func (_c *MockStorage_GetWebPushSettings_Call) RunAndReturn(run func() WebPushSettings) *MockStorage_GetWebPushSettings_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// LinkTelegramAccount provides a mock function for the type MockStorage
+func (_mock *MockStorage) LinkTelegramAccount(link TelegramLink, telegramID int64) error {
+	ret := _mock.Called(link, telegramID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for LinkTelegramAccount")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(TelegramLink, int64) error); ok {
+		r0 = returnFunc(link, telegramID)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// MockStorage_LinkTelegramAccount_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'LinkTelegramAccount'
+type MockStorage_LinkTelegramAccount_Call struct {
+	*mock.Call
+}
+
+// LinkTelegramAccount is a helper method to define mock.On call
+//   - link TelegramLink
+//   - telegramID int64
+func (_e *MockStorage_Expecter) LinkTelegramAccount(link interface{}, telegramID interface{}) *MockStorage_LinkTelegramAccount_Call {
+	return &MockStorage_LinkTelegramAccount_Call{Call: _e.mock.On("LinkTelegramAccount", link, telegramID)}
+}
+
+func (_c *MockStorage_LinkTelegramAccount_Call) Run(run func(link TelegramLink, telegramID int64)) *MockStorage_LinkTelegramAccount_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 TelegramLink
+		if args[0] != nil {
+			arg0 = args[0].(TelegramLink)
+		}
+		var arg1 int64
+		if args[1] != nil {
+			arg1 = args[1].(int64)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *MockStorage_LinkTelegramAccount_Call) Return(err error) *MockStorage_LinkTelegramAccount_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *MockStorage_LinkTelegramAccount_Call) RunAndReturn(run func(link TelegramLink, telegramID int64) error) *MockStorage_LinkTelegramAccount_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// PublishMessage provides a mock function for the type MockStorage
+func (_mock *MockStorage) PublishMessage(message *Message) error {
+	ret := _mock.Called(message)
+
+	if len(ret) == 0 {
+		panic("no return value specified for PublishMessage")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(*Message) error); ok {
+		r0 = returnFunc(message)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// MockStorage_PublishMessage_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'PublishMessage'
+type MockStorage_PublishMessage_Call struct {
+	*mock.Call
+}
+
+// PublishMessage is a helper method to define mock.On call
+//   - message *Message
+func (_e *MockStorage_Expecter) PublishMessage(message interface{}) *MockStorage_PublishMessage_Call {
+	return &MockStorage_PublishMessage_Call{Call: _e.mock.On("PublishMessage", message)}
+}
+
+func (_c *MockStorage_PublishMessage_Call) Run(run func(message *Message)) *MockStorage_PublishMessage_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 *Message
+		if args[0] != nil {
+			arg0 = args[0].(*Message)
+		}
+		run(
+			arg0,
+		)
+	})
+	return _c
+}
+
+func (_c *MockStorage_PublishMessage_Call) Return(err error) *MockStorage_PublishMessage_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *MockStorage_PublishMessage_Call) RunAndReturn(run func(message *Message) error) *MockStorage_PublishMessage_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// ResetTicker provides a mock function for the type MockStorage
+func (_mock *MockStorage) ResetTicker(ticker *Ticker) error {
+	ret := _mock.Called(ticker)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ResetTicker")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(*Ticker) error); ok {
+		r0 = returnFunc(ticker)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// MockStorage_ResetTicker_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ResetTicker'
+type MockStorage_ResetTicker_Call struct {
+	*mock.Call
+}
+
+// ResetTicker is a helper method to define mock.On call
+//   - ticker *Ticker
+func (_e *MockStorage_Expecter) ResetTicker(ticker interface{}) *MockStorage_ResetTicker_Call {
+	return &MockStorage_ResetTicker_Call{Call: _e.mock.On("ResetTicker", ticker)}
+}
+
+func (_c *MockStorage_ResetTicker_Call) Run(run func(ticker *Ticker)) *MockStorage_ResetTicker_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 *Ticker
+		if args[0] != nil {
+			arg0 = args[0].(*Ticker)
+		}
+		run(
+			arg0,
+		)
+	})
+	return _c
+}
+
+func (_c *MockStorage_ResetTicker_Call) Return(err error) *MockStorage_ResetTicker_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *MockStorage_ResetTicker_Call) RunAndReturn(run func(ticker *Ticker) error) *MockStorage_ResetTicker_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// SaveAPIToken provides a mock function for the type MockStorage
+func (_mock *MockStorage) SaveAPIToken(token *APIToken) error {
+	ret := _mock.Called(token)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SaveAPIToken")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(*APIToken) error); ok {
+		r0 = returnFunc(token)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// MockStorage_SaveAPIToken_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'SaveAPIToken'
+type MockStorage_SaveAPIToken_Call struct {
+	*mock.Call
+}
+
+// SaveAPIToken is a helper method to define mock.On call
+//   - token *APIToken
+func (_e *MockStorage_Expecter) SaveAPIToken(token interface{}) *MockStorage_SaveAPIToken_Call {
+	return &MockStorage_SaveAPIToken_Call{Call: _e.mock.On("SaveAPIToken", token)}
+}
+
+func (_c *MockStorage_SaveAPIToken_Call) Run(run func(token *APIToken)) *MockStorage_SaveAPIToken_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 *APIToken
+		if args[0] != nil {
+			arg0 = args[0].(*APIToken)
+		}
+		run(
+			arg0,
+		)
+	})
+	return _c
+}
+
+func (_c *MockStorage_SaveAPIToken_Call) Return(err error) *MockStorage_SaveAPIToken_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *MockStorage_SaveAPIToken_Call) RunAndReturn(run func(token *APIToken) error) *MockStorage_SaveAPIToken_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// SaveAPITokenLastUsed provides a mock function for the type MockStorage
+func (_mock *MockStorage) SaveAPITokenLastUsed(token *APIToken) error {
+	ret := _mock.Called(token)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SaveAPITokenLastUsed")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(*APIToken) error); ok {
+		r0 = returnFunc(token)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// MockStorage_SaveAPITokenLastUsed_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'SaveAPITokenLastUsed'
+type MockStorage_SaveAPITokenLastUsed_Call struct {
+	*mock.Call
+}
+
+// SaveAPITokenLastUsed is a helper method to define mock.On call
+//   - token *APIToken
+func (_e *MockStorage_Expecter) SaveAPITokenLastUsed(token interface{}) *MockStorage_SaveAPITokenLastUsed_Call {
+	return &MockStorage_SaveAPITokenLastUsed_Call{Call: _e.mock.On("SaveAPITokenLastUsed", token)}
+}
+
+func (_c *MockStorage_SaveAPITokenLastUsed_Call) Run(run func(token *APIToken)) *MockStorage_SaveAPITokenLastUsed_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 *APIToken
+		if args[0] != nil {
+			arg0 = args[0].(*APIToken)
+		}
+		run(
+			arg0,
+		)
+	})
+	return _c
+}
+
+func (_c *MockStorage_SaveAPITokenLastUsed_Call) Return(err error) *MockStorage_SaveAPITokenLastUsed_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *MockStorage_SaveAPITokenLastUsed_Call) RunAndReturn(run func(token *APIToken) error) *MockStorage_SaveAPITokenLastUsed_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// SaveActivityPubFollower provides a mock function for the type MockStorage
+func (_mock *MockStorage) SaveActivityPubFollower(follower *ActivityPubFollower) error {
+	ret := _mock.Called(follower)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SaveActivityPubFollower")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(*ActivityPubFollower) error); ok {
+		r0 = returnFunc(follower)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// MockStorage_SaveActivityPubFollower_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'SaveActivityPubFollower'
+type MockStorage_SaveActivityPubFollower_Call struct {
+	*mock.Call
+}
+
+// SaveActivityPubFollower is a helper method to define mock.On call
+//   - follower *ActivityPubFollower
+func (_e *MockStorage_Expecter) SaveActivityPubFollower(follower interface{}) *MockStorage_SaveActivityPubFollower_Call {
+	return &MockStorage_SaveActivityPubFollower_Call{Call: _e.mock.On("SaveActivityPubFollower", follower)}
+}
+
+func (_c *MockStorage_SaveActivityPubFollower_Call) Run(run func(follower *ActivityPubFollower)) *MockStorage_SaveActivityPubFollower_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 *ActivityPubFollower
+		if args[0] != nil {
+			arg0 = args[0].(*ActivityPubFollower)
+		}
+		run(
+			arg0,
+		)
+	})
+	return _c
+}
+
+func (_c *MockStorage_SaveActivityPubFollower_Call) Return(err error) *MockStorage_SaveActivityPubFollower_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *MockStorage_SaveActivityPubFollower_Call) RunAndReturn(run func(follower *ActivityPubFollower) error) *MockStorage_SaveActivityPubFollower_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// SaveEmailDigestEntry provides a mock function for the type MockStorage
+func (_mock *MockStorage) SaveEmailDigestEntry(entry *EmailDigestEntry) error {
+	ret := _mock.Called(entry)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SaveEmailDigestEntry")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(*EmailDigestEntry) error); ok {
+		r0 = returnFunc(entry)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// MockStorage_SaveEmailDigestEntry_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'SaveEmailDigestEntry'
+type MockStorage_SaveEmailDigestEntry_Call struct {
+	*mock.Call
+}
+
+// SaveEmailDigestEntry is a helper method to define mock.On call
+//   - entry *EmailDigestEntry
+func (_e *MockStorage_Expecter) SaveEmailDigestEntry(entry interface{}) *MockStorage_SaveEmailDigestEntry_Call {
+	return &MockStorage_SaveEmailDigestEntry_Call{Call: _e.mock.On("SaveEmailDigestEntry", entry)}
+}
+
+func (_c *MockStorage_SaveEmailDigestEntry_Call) Run(run func(entry *EmailDigestEntry)) *MockStorage_SaveEmailDigestEntry_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 *EmailDigestEntry
+		if args[0] != nil {
+			arg0 = args[0].(*EmailDigestEntry)
+		}
+		run(
+			arg0,
+		)
+	})
+	return _c
+}
+
+func (_c *MockStorage_SaveEmailDigestEntry_Call) Return(err error) *MockStorage_SaveEmailDigestEntry_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *MockStorage_SaveEmailDigestEntry_Call) RunAndReturn(run func(entry *EmailDigestEntry) error) *MockStorage_SaveEmailDigestEntry_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// SaveEmailSubscription provides a mock function for the type MockStorage
+func (_mock *MockStorage) SaveEmailSubscription(subscription *EmailSubscription) error {
+	ret := _mock.Called(subscription)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SaveEmailSubscription")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(*EmailSubscription) error); ok {
+		r0 = returnFunc(subscription)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// MockStorage_SaveEmailSubscription_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'SaveEmailSubscription'
+type MockStorage_SaveEmailSubscription_Call struct {
+	*mock.Call
+}
+
+// SaveEmailSubscription is a helper method to define mock.On call
+//   - subscription *EmailSubscription
+func (_e *MockStorage_Expecter) SaveEmailSubscription(subscription interface{}) *MockStorage_SaveEmailSubscription_Call {
+	return &MockStorage_SaveEmailSubscription_Call{Call: _e.mock.On("SaveEmailSubscription", subscription)}
+}
+
+func (_c *MockStorage_SaveEmailSubscription_Call) Run(run func(subscription *EmailSubscription)) *MockStorage_SaveEmailSubscription_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 *EmailSubscription
+		if args[0] != nil {
+			arg0 = args[0].(*EmailSubscription)
+		}
+		run(
+			arg0,
+		)
+	})
+	return _c
+}
+
+func (_c *MockStorage_SaveEmailSubscription_Call) Return(err error) *MockStorage_SaveEmailSubscription_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *MockStorage_SaveEmailSubscription_Call) RunAndReturn(run func(subscription *EmailSubscription) error) *MockStorage_SaveEmailSubscription_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// SaveInactiveSettings provides a mock function for the type MockStorage
+func (_mock *MockStorage) SaveInactiveSettings(inactiveSettings InactiveSettings) error {
+	ret := _mock.Called(inactiveSettings)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SaveInactiveSettings")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(InactiveSettings) error); ok {
+		r0 = returnFunc(inactiveSettings)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// MockStorage_SaveInactiveSettings_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'SaveInactiveSettings'
+type MockStorage_SaveInactiveSettings_Call struct {
+	*mock.Call
+}
+
+// SaveInactiveSettings is a helper method to define mock.On call
+//   - inactiveSettings InactiveSettings
+func (_e *MockStorage_Expecter) SaveInactiveSettings(inactiveSettings interface{}) *MockStorage_SaveInactiveSettings_Call {
+	return &MockStorage_SaveInactiveSettings_Call{Call: _e.mock.On("SaveInactiveSettings", inactiveSettings)}
+}
+
+func (_c *MockStorage_SaveInactiveSettings_Call) Run(run func(inactiveSettings InactiveSettings)) *MockStorage_SaveInactiveSettings_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 InactiveSettings
+		if args[0] != nil {
+			arg0 = args[0].(InactiveSettings)
+		}
+		run(
+			arg0,
+		)
+	})
+	return _c
+}
+
+func (_c *MockStorage_SaveInactiveSettings_Call) Return(err error) *MockStorage_SaveInactiveSettings_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *MockStorage_SaveInactiveSettings_Call) RunAndReturn(run func(inactiveSettings InactiveSettings) error) *MockStorage_SaveInactiveSettings_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// SaveMessage provides a mock function for the type MockStorage
+func (_mock *MockStorage) SaveMessage(message *Message) error {
+	ret := _mock.Called(message)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SaveMessage")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(*Message) error); ok {
+		r0 = returnFunc(message)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// MockStorage_SaveMessage_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'SaveMessage'
+type MockStorage_SaveMessage_Call struct {
+	*mock.Call
+}
+
+// SaveMessage is a helper method to define mock.On call
+//   - message *Message
+func (_e *MockStorage_Expecter) SaveMessage(message interface{}) *MockStorage_SaveMessage_Call {
+	return &MockStorage_SaveMessage_Call{Call: _e.mock.On("SaveMessage", message)}
+}
+
+func (_c *MockStorage_SaveMessage_Call) Run(run func(message *Message)) *MockStorage_SaveMessage_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 *Message
+		if args[0] != nil {
+			arg0 = args[0].(*Message)
+		}
+		run(
+			arg0,
+		)
+	})
+	return _c
+}
+
+func (_c *MockStorage_SaveMessage_Call) Return(err error) *MockStorage_SaveMessage_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *MockStorage_SaveMessage_Call) RunAndReturn(run func(message *Message) error) *MockStorage_SaveMessage_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// SaveMessageBridgeMeta provides a mock function for the type MockStorage
+func (_mock *MockStorage) SaveMessageBridgeMeta(message *Message, bridge string) error {
+	ret := _mock.Called(message, bridge)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SaveMessageBridgeMeta")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(*Message, string) error); ok {
+		r0 = returnFunc(message, bridge)
 	} else {
-		tmpRet = _mock.Called(user, id)
+		r0 = ret.Error(0)
 	}
-	ret := tmpRet
+	return r0
+}
+
+// MockStorage_SaveMessageBridgeMeta_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'SaveMessageBridgeMeta'
+type MockStorage_SaveMessageBridgeMeta_Call struct {
+	*mock.Call
+}
+
+// SaveMessageBridgeMeta is a helper method to define mock.On call
+//   - message *Message
+//   - bridge string
+func (_e *MockStorage_Expecter) SaveMessageBridgeMeta(message interface{}, bridge interface{}) *MockStorage_SaveMessageBridgeMeta_Call {
+	return &MockStorage_SaveMessageBridgeMeta_Call{Call: _e.mock.On("SaveMessageBridgeMeta", message, bridge)}
+}
+
+func (_c *MockStorage_SaveMessageBridgeMeta_Call) Run(run func(message *Message, bridge string)) *MockStorage_SaveMessageBridgeMeta_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 *Message
+		if args[0] != nil {
+			arg0 = args[0].(*Message)
+		}
+		var arg1 string
+		if args[1] != nil {
+			arg1 = args[1].(string)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *MockStorage_SaveMessageBridgeMeta_Call) Return(err error) *MockStorage_SaveMessageBridgeMeta_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *MockStorage_SaveMessageBridgeMeta_Call) RunAndReturn(run func(message *Message, bridge string) error) *MockStorage_SaveMessageBridgeMeta_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// SaveMessageRevision provides a mock function for the type MockStorage
+func (_mock *MockStorage) SaveMessageRevision(revision *MessageRevision) error {
+	ret := _mock.Called(revision)
 
 	if len(ret) == 0 {
-		panic("no return value specified for FindTickerByUserAndID")
+		panic("no return value specified for SaveMessageRevision")
 	}
 
-	var r0 Ticker
-	var r1 error
-	if returnFunc, ok := ret.Get(0).(func(User, int, ...func(*gorm.DB) *gorm.DB) (Ticker, error)); ok {
-		return returnFunc(user, id, opts...)
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(*MessageRevision) error); ok {
+		r0 = returnFunc(revision)
+	} else {
+		r0 = ret.Error(0)
 	}
-	if returnFunc, ok := ret.Get(0).(func(User, int, ...func(*gorm.DB) *gorm.DB) Ticker); ok {
-		r0 = returnFunc(user, id, opts...)
+	return r0
+}
+
+// MockStorage_SaveMessageRevision_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'SaveMessageRevision'
+type MockStorage_SaveMessageRevision_Call struct {
+	*mock.Call
+}
+
+// SaveMessageRevision is a helper method to define mock.On call
+//   - revision *MessageRevision
+func (_e *MockStorage_Expecter) SaveMessageRevision(revision interface{}) *MockStorage_SaveMessageRevision_Call {
+	return &MockStorage_SaveMessageRevision_Call{Call: _e.mock.On("SaveMessageRevision", revision)}
+}
+
+func (_c *MockStorage_SaveMessageRevision_Call) Run(run func(revision *MessageRevision)) *MockStorage_SaveMessageRevision_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 *MessageRevision
+		if args[0] != nil {
+			arg0 = args[0].(*MessageRevision)
+		}
+		run(
+			arg0,
+		)
+	})
+	return _c
+}
+
+func (_c *MockStorage_SaveMessageRevision_Call) Return(err error) *MockStorage_SaveMessageRevision_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *MockStorage_SaveMessageRevision_Call) RunAndReturn(run func(revision *MessageRevision) error) *MockStorage_SaveMessageRevision_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// SaveMessageTags provides a mock function for the type MockStorage
+func (_mock *MockStorage) SaveMessageTags(message *Message, names []string) error {
+	ret := _mock.Called(message, names)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SaveMessageTags")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(*Message, []string) error); ok {
+		r0 = returnFunc(message, names)
 	} else {
-		r0 = ret.Get(0).(Ticker)
+		r0 = ret.Error(0)
 	}
-	if returnFunc, ok := ret.Get(1).(func(User, int, ...func(*gorm.DB) *gorm.DB) error); ok {
-		r1 = returnFunc(user, id, opts...)
+	return r0
+}
+
+// MockStorage_SaveMessageTags_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'SaveMessageTags'
+type MockStorage_SaveMessageTags_Call struct {
+	*mock.Call
+}
+
+// SaveMessageTags is a helper method to define mock.On call
+//   - message *Message
+//   - names []string
+func (_e *MockStorage_Expecter) SaveMessageTags(message interface{}, names interface{}) *MockStorage_SaveMessageTags_Call {
+	return &MockStorage_SaveMessageTags_Call{Call: _e.mock.On("SaveMessageTags", message, names)}
+}
+
+func (_c *MockStorage_SaveMessageTags_Call) Run(run func(message *Message, names []string)) *MockStorage_SaveMessageTags_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 *Message
+		if args[0] != nil {
+			arg0 = args[0].(*Message)
+		}
+		var arg1 []string
+		if args[1] != nil {
+			arg1 = args[1].([]string)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *MockStorage_SaveMessageTags_Call) Return(err error) *MockStorage_SaveMessageTags_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *MockStorage_SaveMessageTags_Call) RunAndReturn(run func(message *Message, names []string) error) *MockStorage_SaveMessageTags_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// SaveOIDCSession provides a mock function for the type MockStorage
+func (_mock *MockStorage) SaveOIDCSession(session *OIDCSession) error {
+	ret := _mock.Called(session)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SaveOIDCSession")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(*OIDCSession) error); ok {
+		r0 = returnFunc(session)
 	} else {
-		r1 = ret.Error(1)
+		r0 = ret.Error(0)
 	}
-	return r0, r1
+	return r0
 }
 
-// MockStorage_FindTickerByUserAndID_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'FindTickerByUserAndID'
-type MockStorage_FindTickerByUserAndID_Call struct {
+// MockStorage_SaveOIDCSession_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'SaveOIDCSession'
+type MockStorage_SaveOIDCSession_Call struct {
 	*mock.Call
 }
 
-// FindTickerByUserAndID is a helper method to define mock.On call
-//   - user User
-//   - id int
-//   - opts ...func(*gorm.DB) *gorm.DB
-func (_e *MockStorage_Expecter) FindTickerByUserAndID(user interface{}, id interface{}, opts ...interface{}) *MockStorage_FindTickerByUserAndID_Call {
-	return &MockStorage_FindTickerByUserAndID_Call{Call: _e.mock.On("FindTickerByUserAndID",
-		append([]interface{}{user, id}, opts...)...)}
+// SaveOIDCSession is a helper method to define mock.On call
+//   - session *OIDCSession
+func (_e *MockStorage_Expecter) SaveOIDCSession(session interface{}) *MockStorage_SaveOIDCSession_Call {
+	return &MockStorage_SaveOIDCSession_Call{Call: _e.mock.On("SaveOIDCSession", session)}
+}
+
+func (_c *MockStorage_SaveOIDCSession_Call) Run(run func(session *OIDCSession)) *MockStorage_SaveOIDCSession_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 *OIDCSession
+		if args[0] != nil {
+			arg0 = args[0].(*OIDCSession)
+		}
+		run(
+			arg0,
+		)
+	})
+	return _c
+}
+
+func (_c *MockStorage_SaveOIDCSession_Call) Return(err error) *MockStorage_SaveOIDCSession_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *MockStorage_SaveOIDCSession_Call) RunAndReturn(run func(session *OIDCSession) error) *MockStorage_SaveOIDCSession_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// SaveOIDCSubject provides a mock function for the type MockStorage
+func (_mock *MockStorage) SaveOIDCSubject(user *User) error {
+	ret := _mock.Called(user)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SaveOIDCSubject")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(*User) error); ok {
+		r0 = returnFunc(user)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// MockStorage_SaveOIDCSubject_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'SaveOIDCSubject'
+type MockStorage_SaveOIDCSubject_Call struct {
+	*mock.Call
+}
+
+// SaveOIDCSubject is a helper method to define mock.On call
+//   - user *User
+func (_e *MockStorage_Expecter) SaveOIDCSubject(user interface{}) *MockStorage_SaveOIDCSubject_Call {
+	return &MockStorage_SaveOIDCSubject_Call{Call: _e.mock.On("SaveOIDCSubject", user)}
+}
+
+func (_c *MockStorage_SaveOIDCSubject_Call) Run(run func(user *User)) *MockStorage_SaveOIDCSubject_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 *User
+		if args[0] != nil {
+			arg0 = args[0].(*User)
+		}
+		run(
+			arg0,
+		)
+	})
+	return _c
+}
+
+func (_c *MockStorage_SaveOIDCSubject_Call) Return(err error) *MockStorage_SaveOIDCSubject_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *MockStorage_SaveOIDCSubject_Call) RunAndReturn(run func(user *User) error) *MockStorage_SaveOIDCSubject_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// SaveOutboxJob provides a mock function for the type MockStorage
+func (_mock *MockStorage) SaveOutboxJob(job *OutboxJob) error {
+	ret := _mock.Called(job)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SaveOutboxJob")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(*OutboxJob) error); ok {
+		r0 = returnFunc(job)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// MockStorage_SaveOutboxJob_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'SaveOutboxJob'
+type MockStorage_SaveOutboxJob_Call struct {
+	*mock.Call
+}
+
+// SaveOutboxJob is a helper method to define mock.On call
+//   - job *OutboxJob
+func (_e *MockStorage_Expecter) SaveOutboxJob(job interface{}) *MockStorage_SaveOutboxJob_Call {
+	return &MockStorage_SaveOutboxJob_Call{Call: _e.mock.On("SaveOutboxJob", job)}
+}
+
+func (_c *MockStorage_SaveOutboxJob_Call) Run(run func(job *OutboxJob)) *MockStorage_SaveOutboxJob_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 *OutboxJob
+		if args[0] != nil {
+			arg0 = args[0].(*OutboxJob)
+		}
+		run(
+			arg0,
+		)
+	})
+	return _c
+}
+
+func (_c *MockStorage_SaveOutboxJob_Call) Return(err error) *MockStorage_SaveOutboxJob_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *MockStorage_SaveOutboxJob_Call) RunAndReturn(run func(job *OutboxJob) error) *MockStorage_SaveOutboxJob_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// SaveRecoveryCodes provides a mock function for the type MockStorage
+func (_mock *MockStorage) SaveRecoveryCodes(user User, codes []RecoveryCode) error {
+	ret := _mock.Called(user, codes)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SaveRecoveryCodes")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(User, []RecoveryCode) error); ok {
+		r0 = returnFunc(user, codes)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// MockStorage_SaveRecoveryCodes_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'SaveRecoveryCodes'
+type MockStorage_SaveRecoveryCodes_Call struct {
+	*mock.Call
+}
+
+// SaveRecoveryCodes is a helper method to define mock.On call
+//   - user User
+//   - codes []RecoveryCode
+func (_e *MockStorage_Expecter) SaveRecoveryCodes(user interface{}, codes interface{}) *MockStorage_SaveRecoveryCodes_Call {
+	return &MockStorage_SaveRecoveryCodes_Call{Call: _e.mock.On("SaveRecoveryCodes", user, codes)}
 }
 
-func (_c *MockStorage_FindTickerByUserAndID_Call) Run(run func(user User, id int, opts ...func(*gorm.DB) *gorm.DB)) *MockStorage_FindTickerByUserAndID_Call {
+func (_c *MockStorage_SaveRecoveryCodes_Call) Run(run func(user User, codes []RecoveryCode)) *MockStorage_SaveRecoveryCodes_Call {
 	_c.Call.Run(func(args mock.Arguments) {
 		var arg0 User
 		if args[0] != nil {
 			arg0 = args[0].(User)
 		}
-		var arg1 int
+		var arg1 []RecoveryCode
 		if args[1] != nil {
-			arg1 = args[1].(int)
-		}
-		var arg2 []func(*gorm.DB) *gorm.DB
-		var variadicArgs []func(*gorm.DB) *gorm.DB
-		if len(args) > 2 {
-			variadicArgs = args[2].([]func(*gorm.DB) *gorm.DB)
+			arg1 = args[1].([]RecoveryCode)
 		}
-		arg2 = variadicArgs
 		run(
 			arg0,
 			arg1,
-			arg2...,
 		)
 	})
 	return _c
 }
 
-func (_c *MockStorage_FindTickerByUserAndID_Call) Return(ticker Ticker, err error) *MockStorage_FindTickerByUserAndID_Call {
-	_c.Call.Return(ticker, err)
+func (_c *MockStorage_SaveRecoveryCodes_Call) Return(err error) *MockStorage_SaveRecoveryCodes_Call {
+	_c.Call.Return(err)
 	return _c
 }
 
-func (_c *MockStorage_FindTickerByUserAndID_Call) RunAndReturn(run func(user User, id int, opts ...func(*gorm.DB) *gorm.DB) (Ticker, error)) *MockStorage_FindTickerByUserAndID_Call {
+func (_c *MockStorage_SaveRecoveryCodes_Call) RunAndReturn(run func(user User, codes []RecoveryCode) error) *MockStorage_SaveRecoveryCodes_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
-// FindTickersByIDs provides a mock function for the type MockStorage
-func (_mock *MockStorage) FindTickersByIDs(ids []int, opts ...func(*gorm.DB) *gorm.DB) ([]Ticker, error) {
-	var tmpRet mock.Arguments
-	if len(opts) > 0 {
-		tmpRet = _mock.Called(ids, opts)
-	} else {
-		tmpRet = _mock.Called(ids)
-	}
-	ret := tmpRet
+// SaveSecuritySettings provides a mock function for the type MockStorage
+func (_mock *MockStorage) SaveSecuritySettings(securitySettings SecuritySettings) error {
+	ret := _mock.Called(securitySettings)
 
 	if len(ret) == 0 {
-		panic("no return value specified for FindTickersByIDs")
+		panic("no return value specified for SaveSecuritySettings")
 	}
 
-	var r0 []Ticker
-	var r1 error
-	if returnFunc, ok := ret.Get(0).(func([]int, ...func(*gorm.DB) *gorm.DB) ([]Ticker, error)); ok {
-		return returnFunc(ids, opts...)
-	}
-	if returnFunc, ok := ret.Get(0).(func([]int, ...func(*gorm.DB) *gorm.DB) []Ticker); ok {
-		r0 = returnFunc(ids, opts...)
-	} else {
-		if ret.Get(0) != nil {
-			r0 = ret.Get(0).([]Ticker)
-		}
-	}
-	if returnFunc, ok := ret.Get(1).(func([]int, ...func(*gorm.DB) *gorm.DB) error); ok {
-		r1 = returnFunc(ids, opts...)
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(SecuritySettings) error); ok {
+		r0 = returnFunc(securitySettings)
 	} else {
-		r1 = ret.Error(1)
+		r0 = ret.Error(0)
 	}
-	return r0, r1
+	return r0
 }
 
-// MockStorage_FindTickersByIDs_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'FindTickersByIDs'
-type MockStorage_FindTickersByIDs_Call struct {
+// MockStorage_SaveSecuritySettings_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'SaveSecuritySettings'
+type MockStorage_SaveSecuritySettings_Call struct {
 	*mock.Call
 }
 
-// FindTickersByIDs is a helper method to define mock.On call
-//   - ids []int
-//   - opts ...func(*gorm.DB) *gorm.DB
-func (_e *MockStorage_Expecter) FindTickersByIDs(ids interface{}, opts ...interface{}) *MockStorage_FindTickersByIDs_Call {
-	return &MockStorage_FindTickersByIDs_Call{Call: _e.mock.On("FindTickersByIDs",
-		append([]interface{}{ids}, opts...)...)}
+// SaveSecuritySettings is a helper method to define mock.On call
+//   - securitySettings SecuritySettings
+func (_e *MockStorage_Expecter) SaveSecuritySettings(securitySettings interface{}) *MockStorage_SaveSecuritySettings_Call {
+	return &MockStorage_SaveSecuritySettings_Call{Call: _e.mock.On("SaveSecuritySettings", securitySettings)}
 }
 
-func (_c *MockStorage_FindTickersByIDs_Call) Run(run func(ids []int, opts ...func(*gorm.DB) *gorm.DB)) *MockStorage_FindTickersByIDs_Call {
+func (_c *MockStorage_SaveSecuritySettings_Call) Run(run func(securitySettings SecuritySettings)) *MockStorage_SaveSecuritySettings_Call {
 	_c.Call.Run(func(args mock.Arguments) {
-		var arg0 []int
+		var arg0 SecuritySettings
 		if args[0] != nil {
-			arg0 = args[0].([]int)
-		}
-		var arg1 []func(*gorm.DB) *gorm.DB
-		var variadicArgs []func(*gorm.DB) *gorm.DB
-		if len(args) > 1 {
-			variadicArgs = args[1].([]func(*gorm.DB) *gorm.DB)
+			arg0 = args[0].(SecuritySettings)
 		}
-		arg1 = variadicArgs
 		run(
 			arg0,
-			arg1...,
 		)
 	})
 	return _c
 }
 
-func (_c *MockStorage_FindTickersByIDs_Call) Return(tickers []Ticker, err error) *MockStorage_FindTickersByIDs_Call {
-	_c.Call.Return(tickers, err)
+func (_c *MockStorage_SaveSecuritySettings_Call) Return(err error) *MockStorage_SaveSecuritySettings_Call {
+	_c.Call.Return(err)
 	return _c
 }
 
-func (_c *MockStorage_FindTickersByIDs_Call) RunAndReturn(run func(ids []int, opts ...func(*gorm.DB) *gorm.DB) ([]Ticker, error)) *MockStorage_FindTickersByIDs_Call {
+func (_c *MockStorage_SaveSecuritySettings_Call) RunAndReturn(run func(securitySettings SecuritySettings) error) *MockStorage_SaveSecuritySettings_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
-// FindTickersByUser provides a mock function for the type MockStorage
-func (_mock *MockStorage) FindTickersByUser(user User, filter TickerFilter, opts ...func(*gorm.DB) *gorm.DB) ([]Ticker, error) {
-	var tmpRet mock.Arguments
-	if len(opts) > 0 {
-		tmpRet = _mock.Called(user, filter, opts)
-	} else {
-		tmpRet = _mock.Called(user, filter)
-	}
-	ret := tmpRet
+// SaveSignalGroupSettings provides a mock function for the type MockStorage
+func (_mock *MockStorage) SaveSignalGroupSettings(signalGroupSettings SignalGroupSettings) error {
+	ret := _mock.Called(signalGroupSettings)
 
 	if len(ret) == 0 {
-		panic("no return value specified for FindTickersByUser")
+		panic("no return value specified for SaveSignalGroupSettings")
 	}
 
-	var r0 []Ticker
-	var r1 error
-	if returnFunc, ok := ret.Get(0).(func(User, TickerFilter, ...func(*gorm.DB) *gorm.DB) ([]Ticker, error)); ok {
-		return returnFunc(user, filter, opts...)
-	}
-	if returnFunc, ok := ret.Get(0).(func(User, TickerFilter, ...func(*gorm.DB) *gorm.DB) []Ticker); ok {
-		r0 = returnFunc(user, filter, opts...)
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(SignalGroupSettings) error); ok {
+		r0 = returnFunc(signalGroupSettings)
 	} else {
-		if ret.Get(0) != nil {
-			r0 = ret.Get(0).([]Ticker)
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// MockStorage_SaveSignalGroupSettings_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'SaveSignalGroupSettings'
+type MockStorage_SaveSignalGroupSettings_Call struct {
+	*mock.Call
+}
+
+// SaveSignalGroupSettings is a helper method to define mock.On call
+//   - signalGroupSettings SignalGroupSettings
+func (_e *MockStorage_Expecter) SaveSignalGroupSettings(signalGroupSettings interface{}) *MockStorage_SaveSignalGroupSettings_Call {
+	return &MockStorage_SaveSignalGroupSettings_Call{Call: _e.mock.On("SaveSignalGroupSettings", signalGroupSettings)}
+}
+
+func (_c *MockStorage_SaveSignalGroupSettings_Call) Run(run func(signalGroupSettings SignalGroupSettings)) *MockStorage_SaveSignalGroupSettings_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 SignalGroupSettings
+		if args[0] != nil {
+			arg0 = args[0].(SignalGroupSettings)
 		}
+		run(
+			arg0,
+		)
+	})
+	return _c
+}
+
+func (_c *MockStorage_SaveSignalGroupSettings_Call) Return(err error) *MockStorage_SaveSignalGroupSettings_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *MockStorage_SaveSignalGroupSettings_Call) RunAndReturn(run func(signalGroupSettings SignalGroupSettings) error) *MockStorage_SaveSignalGroupSettings_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// SaveTOTPLock provides a mock function for the type MockStorage
+func (_mock *MockStorage) SaveTOTPLock(user *User) error {
+	ret := _mock.Called(user)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SaveTOTPLock")
 	}
-	if returnFunc, ok := ret.Get(1).(func(User, TickerFilter, ...func(*gorm.DB) *gorm.DB) error); ok {
-		r1 = returnFunc(user, filter, opts...)
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(*User) error); ok {
+		r0 = returnFunc(user)
 	} else {
-		r1 = ret.Error(1)
+		r0 = ret.Error(0)
 	}
-	return r0, r1
+	return r0
 }
 
-// MockStorage_FindTickersByUser_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'FindTickersByUser'
-type MockStorage_FindTickersByUser_Call struct {
+// MockStorage_SaveTOTPLock_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'SaveTOTPLock'
+type MockStorage_SaveTOTPLock_Call struct {
 	*mock.Call
 }
 
-// FindTickersByUser is a helper method to define mock.On call
-//   - user User
-//   - filter TickerFilter
-//   - opts ...func(*gorm.DB) *gorm.DB
-func (_e *MockStorage_Expecter) FindTickersByUser(user interface{}, filter interface{}, opts ...interface{}) *MockStorage_FindTickersByUser_Call {
-	return &MockStorage_FindTickersByUser_Call{Call: _e.mock.On("FindTickersByUser",
-		append([]interface{}{user, filter}, opts...)...)}
+// SaveTOTPLock is a helper method to define mock.On call
+//   - user *User
+func (_e *MockStorage_Expecter) SaveTOTPLock(user interface{}) *MockStorage_SaveTOTPLock_Call {
+	return &MockStorage_SaveTOTPLock_Call{Call: _e.mock.On("SaveTOTPLock", user)}
 }
 
-func (_c *MockStorage_FindTickersByUser_Call) Run(run func(user User, filter TickerFilter, opts ...func(*gorm.DB) *gorm.DB)) *MockStorage_FindTickersByUser_Call {
+func (_c *MockStorage_SaveTOTPLock_Call) Run(run func(user *User)) *MockStorage_SaveTOTPLock_Call {
 	_c.Call.Run(func(args mock.Arguments) {
-		var arg0 User
+		var arg0 *User
 		if args[0] != nil {
-			arg0 = args[0].(User)
-		}
-		var arg1 TickerFilter
-		if args[1] != nil {
-			arg1 = args[1].(TickerFilter)
-		}
-		var arg2 []func(*gorm.DB) *gorm.DB
-		var variadicArgs []func(*gorm.DB) *gorm.DB
-		if len(args) > 2 {
-			variadicArgs = args[2].([]func(*gorm.DB) *gorm.DB)
+			arg0 = args[0].(*User)
 		}
-		arg2 = variadicArgs
 		run(
 			arg0,
-			arg1,
-			arg2...,
 		)
 	})
 	return _c
 }
 
-func (_c *MockStorage_FindTickersByUser_Call) Return(tickers []Ticker, err error) *MockStorage_FindTickersByUser_Call {
-	_c.Call.Return(tickers, err)
+func (_c *MockStorage_SaveTOTPLock_Call) Return(err error) *MockStorage_SaveTOTPLock_Call {
+	_c.Call.Return(err)
 	return _c
 }
 
-func (_c *MockStorage_FindTickersByUser_Call) RunAndReturn(run func(user User, filter TickerFilter, opts ...func(*gorm.DB) *gorm.DB) ([]Ticker, error)) *MockStorage_FindTickersByUser_Call {
+func (_c *MockStorage_SaveTOTPLock_Call) RunAndReturn(run func(user *User) error) *MockStorage_SaveTOTPLock_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
-// FindUploadByUUID provides a mock function for the type MockStorage
-func (_mock *MockStorage) FindUploadByUUID(uuid string) (Upload, error) {
-	ret := _mock.Called(uuid)
+// SaveTOTPSecret provides a mock function for the type MockStorage
+func (_mock *MockStorage) SaveTOTPSecret(user *User) error {
+	ret := _mock.Called(user)
 
 	if len(ret) == 0 {
-		panic("no return value specified for FindUploadByUUID")
+		panic("no return value specified for SaveTOTPSecret")
 	}
 
-	var r0 Upload
-	var r1 error
-	if returnFunc, ok := ret.Get(0).(func(string) (Upload, error)); ok {
-		return returnFunc(uuid)
-	}
-	if returnFunc, ok := ret.Get(0).(func(string) Upload); ok {
-		r0 = returnFunc(uuid)
-	} else {
-		r0 = ret.Get(0).(Upload)
-	}
-	if returnFunc, ok := ret.Get(1).(func(string) error); ok {
-		r1 = returnFunc(uuid)
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(*User) error); ok {
+		r0 = returnFunc(user)
 	} else {
-		r1 = ret.Error(1)
+		r0 = ret.Error(0)
 	}
-	return r0, r1
+	return r0
 }
 
-// MockStorage_FindUploadByUUID_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'FindUploadByUUID'
-type MockStorage_FindUploadByUUID_Call struct {
+// MockStorage_SaveTOTPSecret_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'SaveTOTPSecret'
+type MockStorage_SaveTOTPSecret_Call struct {
 	*mock.Call
 }
 
-// FindUploadByUUID is a helper method to define mock.On call
-//   - uuid string
-func (_e *MockStorage_Expecter) FindUploadByUUID(uuid interface{}) *MockStorage_FindUploadByUUID_Call {
-	return &MockStorage_FindUploadByUUID_Call{Call: _e.mock.On("FindUploadByUUID", uuid)}
+// SaveTOTPSecret is a helper method to define mock.On call
+//   - user *User
+func (_e *MockStorage_Expecter) SaveTOTPSecret(user interface{}) *MockStorage_SaveTOTPSecret_Call {
+	return &MockStorage_SaveTOTPSecret_Call{Call: _e.mock.On("SaveTOTPSecret", user)}
 }
 
-func (_c *MockStorage_FindUploadByUUID_Call) Run(run func(uuid string)) *MockStorage_FindUploadByUUID_Call {
+func (_c *MockStorage_SaveTOTPSecret_Call) Run(run func(user *User)) *MockStorage_SaveTOTPSecret_Call {
 	_c.Call.Run(func(args mock.Arguments) {
-		var arg0 string
+		var arg0 *User
 		if args[0] != nil {
-			arg0 = args[0].(string)
+			arg0 = args[0].(*User)
 		}
 		run(
 			arg0,
@@ -1536,60 +6463,100 @@ func (_c *MockStorage_FindUploadByUUID_Call) Run(run func(uuid string)) *MockSto
 	return _c
 }
 
-func (_c *MockStorage_FindUploadByUUID_Call) Return(upload Upload, err error) *MockStorage_FindUploadByUUID_Call {
-	_c.Call.Return(upload, err)
+func (_c *MockStorage_SaveTOTPSecret_Call) Return(err error) *MockStorage_SaveTOTPSecret_Call {
+	_c.Call.Return(err)
 	return _c
 }
 
-func (_c *MockStorage_FindUploadByUUID_Call) RunAndReturn(run func(uuid string) (Upload, error)) *MockStorage_FindUploadByUUID_Call {
+func (_c *MockStorage_SaveTOTPSecret_Call) RunAndReturn(run func(user *User) error) *MockStorage_SaveTOTPSecret_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
-// FindUploadsByIDs provides a mock function for the type MockStorage
-func (_mock *MockStorage) FindUploadsByIDs(ids []int) ([]Upload, error) {
-	ret := _mock.Called(ids)
+// SaveTelegramLink provides a mock function for the type MockStorage
+func (_mock *MockStorage) SaveTelegramLink(link *TelegramLink) error {
+	ret := _mock.Called(link)
 
 	if len(ret) == 0 {
-		panic("no return value specified for FindUploadsByIDs")
+		panic("no return value specified for SaveTelegramLink")
 	}
 
-	var r0 []Upload
-	var r1 error
-	if returnFunc, ok := ret.Get(0).(func([]int) ([]Upload, error)); ok {
-		return returnFunc(ids)
-	}
-	if returnFunc, ok := ret.Get(0).(func([]int) []Upload); ok {
-		r0 = returnFunc(ids)
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(*TelegramLink) error); ok {
+		r0 = returnFunc(link)
 	} else {
-		if ret.Get(0) != nil {
-			r0 = ret.Get(0).([]Upload)
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// MockStorage_SaveTelegramLink_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'SaveTelegramLink'
+type MockStorage_SaveTelegramLink_Call struct {
+	*mock.Call
+}
+
+// SaveTelegramLink is a helper method to define mock.On call
+//   - link *TelegramLink
+func (_e *MockStorage_Expecter) SaveTelegramLink(link interface{}) *MockStorage_SaveTelegramLink_Call {
+	return &MockStorage_SaveTelegramLink_Call{Call: _e.mock.On("SaveTelegramLink", link)}
+}
+
+func (_c *MockStorage_SaveTelegramLink_Call) Run(run func(link *TelegramLink)) *MockStorage_SaveTelegramLink_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 *TelegramLink
+		if args[0] != nil {
+			arg0 = args[0].(*TelegramLink)
 		}
+		run(
+			arg0,
+		)
+	})
+	return _c
+}
+
+func (_c *MockStorage_SaveTelegramLink_Call) Return(err error) *MockStorage_SaveTelegramLink_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *MockStorage_SaveTelegramLink_Call) RunAndReturn(run func(link *TelegramLink) error) *MockStorage_SaveTelegramLink_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// SaveTelegramSettings provides a mock function for the type MockStorage
+func (_mock *MockStorage) SaveTelegramSettings(telegramSettings TelegramSettings) error {
+	ret := _mock.Called(telegramSettings)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SaveTelegramSettings")
 	}
-	if returnFunc, ok := ret.Get(1).(func([]int) error); ok {
-		r1 = returnFunc(ids)
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(TelegramSettings) error); ok {
+		r0 = returnFunc(telegramSettings)
 	} else {
-		r1 = ret.Error(1)
+		r0 = ret.Error(0)
 	}
-	return r0, r1
+	return r0
 }
 
-// MockStorage_FindUploadsByIDs_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'FindUploadsByIDs'
-type MockStorage_FindUploadsByIDs_Call struct {
+// MockStorage_SaveTelegramSettings_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'SaveTelegramSettings'
+type MockStorage_SaveTelegramSettings_Call struct {
 	*mock.Call
 }
 
-// FindUploadsByIDs is a helper method to define mock.On call
-//   - ids []int
-func (_e *MockStorage_Expecter) FindUploadsByIDs(ids interface{}) *MockStorage_FindUploadsByIDs_Call {
-	return &MockStorage_FindUploadsByIDs_Call{Call: _e.mock.On("FindUploadsByIDs", ids)}
+// SaveTelegramSettings is a helper method to define mock.On call
+//   - telegramSettings TelegramSettings
+func (_e *MockStorage_Expecter) SaveTelegramSettings(telegramSettings interface{}) *MockStorage_SaveTelegramSettings_Call {
+	return &MockStorage_SaveTelegramSettings_Call{Call: _e.mock.On("SaveTelegramSettings", telegramSettings)}
 }
 
-func (_c *MockStorage_FindUploadsByIDs_Call) Run(run func(ids []int)) *MockStorage_FindUploadsByIDs_Call {
+func (_c *MockStorage_SaveTelegramSettings_Call) Run(run func(telegramSettings TelegramSettings)) *MockStorage_SaveTelegramSettings_Call {
 	_c.Call.Run(func(args mock.Arguments) {
-		var arg0 []int
+		var arg0 TelegramSettings
 		if args[0] != nil {
-			arg0 = args[0].([]int)
+			arg0 = args[0].(TelegramSettings)
 		}
 		run(
 			arg0,
@@ -1598,562 +6565,481 @@ func (_c *MockStorage_FindUploadsByIDs_Call) Run(run func(ids []int)) *MockStora
 	return _c
 }
 
-func (_c *MockStorage_FindUploadsByIDs_Call) Return(uploads []Upload, err error) *MockStorage_FindUploadsByIDs_Call {
-	_c.Call.Return(uploads, err)
+func (_c *MockStorage_SaveTelegramSettings_Call) Return(err error) *MockStorage_SaveTelegramSettings_Call {
+	_c.Call.Return(err)
 	return _c
 }
 
-func (_c *MockStorage_FindUploadsByIDs_Call) RunAndReturn(run func(ids []int) ([]Upload, error)) *MockStorage_FindUploadsByIDs_Call {
+func (_c *MockStorage_SaveTelegramSettings_Call) RunAndReturn(run func(telegramSettings TelegramSettings) error) *MockStorage_SaveTelegramSettings_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
-// FindUserByEmail provides a mock function for the type MockStorage
-func (_mock *MockStorage) FindUserByEmail(email string, opts ...func(*gorm.DB) *gorm.DB) (User, error) {
-	var tmpRet mock.Arguments
-	if len(opts) > 0 {
-		tmpRet = _mock.Called(email, opts)
-	} else {
-		tmpRet = _mock.Called(email)
-	}
-	ret := tmpRet
+// SaveTelegramTicker provides a mock function for the type MockStorage
+func (_mock *MockStorage) SaveTelegramTicker(user *User) error {
+	ret := _mock.Called(user)
 
 	if len(ret) == 0 {
-		panic("no return value specified for FindUserByEmail")
+		panic("no return value specified for SaveTelegramTicker")
 	}
 
-	var r0 User
-	var r1 error
-	if returnFunc, ok := ret.Get(0).(func(string, ...func(*gorm.DB) *gorm.DB) (User, error)); ok {
-		return returnFunc(email, opts...)
-	}
-	if returnFunc, ok := ret.Get(0).(func(string, ...func(*gorm.DB) *gorm.DB) User); ok {
-		r0 = returnFunc(email, opts...)
-	} else {
-		r0 = ret.Get(0).(User)
-	}
-	if returnFunc, ok := ret.Get(1).(func(string, ...func(*gorm.DB) *gorm.DB) error); ok {
-		r1 = returnFunc(email, opts...)
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(*User) error); ok {
+		r0 = returnFunc(user)
 	} else {
-		r1 = ret.Error(1)
+		r0 = ret.Error(0)
 	}
-	return r0, r1
+	return r0
 }
 
-// MockStorage_FindUserByEmail_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'FindUserByEmail'
-type MockStorage_FindUserByEmail_Call struct {
+// MockStorage_SaveTelegramTicker_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'SaveTelegramTicker'
+type MockStorage_SaveTelegramTicker_Call struct {
 	*mock.Call
 }
 
-// FindUserByEmail is a helper method to define mock.On call
-//   - email string
-//   - opts ...func(*gorm.DB) *gorm.DB
-func (_e *MockStorage_Expecter) FindUserByEmail(email interface{}, opts ...interface{}) *MockStorage_FindUserByEmail_Call {
-	return &MockStorage_FindUserByEmail_Call{Call: _e.mock.On("FindUserByEmail",
-		append([]interface{}{email}, opts...)...)}
+// SaveTelegramTicker is a helper method to define mock.On call
+//   - user *User
+func (_e *MockStorage_Expecter) SaveTelegramTicker(user interface{}) *MockStorage_SaveTelegramTicker_Call {
+	return &MockStorage_SaveTelegramTicker_Call{Call: _e.mock.On("SaveTelegramTicker", user)}
 }
 
-func (_c *MockStorage_FindUserByEmail_Call) Run(run func(email string, opts ...func(*gorm.DB) *gorm.DB)) *MockStorage_FindUserByEmail_Call {
+func (_c *MockStorage_SaveTelegramTicker_Call) Run(run func(user *User)) *MockStorage_SaveTelegramTicker_Call {
 	_c.Call.Run(func(args mock.Arguments) {
-		var arg0 string
+		var arg0 *User
 		if args[0] != nil {
-			arg0 = args[0].(string)
-		}
-		var arg1 []func(*gorm.DB) *gorm.DB
-		var variadicArgs []func(*gorm.DB) *gorm.DB
-		if len(args) > 1 {
-			variadicArgs = args[1].([]func(*gorm.DB) *gorm.DB)
+			arg0 = args[0].(*User)
 		}
-		arg1 = variadicArgs
 		run(
 			arg0,
-			arg1...,
 		)
 	})
 	return _c
 }
 
-func (_c *MockStorage_FindUserByEmail_Call) Return(user User, err error) *MockStorage_FindUserByEmail_Call {
-	_c.Call.Return(user, err)
+func (_c *MockStorage_SaveTelegramTicker_Call) Return(err error) *MockStorage_SaveTelegramTicker_Call {
+	_c.Call.Return(err)
 	return _c
 }
 
-func (_c *MockStorage_FindUserByEmail_Call) RunAndReturn(run func(email string, opts ...func(*gorm.DB) *gorm.DB) (User, error)) *MockStorage_FindUserByEmail_Call {
+func (_c *MockStorage_SaveTelegramTicker_Call) RunAndReturn(run func(user *User) error) *MockStorage_SaveTelegramTicker_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
-// FindUserByID provides a mock function for the type MockStorage
-func (_mock *MockStorage) FindUserByID(id int, opts ...func(*gorm.DB) *gorm.DB) (User, error) {
-	var tmpRet mock.Arguments
-	if len(opts) > 0 {
-		tmpRet = _mock.Called(id, opts)
-	} else {
-		tmpRet = _mock.Called(id)
-	}
-	ret := tmpRet
+// SaveTicker provides a mock function for the type MockStorage
+func (_mock *MockStorage) SaveTicker(ticker *Ticker) error {
+	ret := _mock.Called(ticker)
 
 	if len(ret) == 0 {
-		panic("no return value specified for FindUserByID")
+		panic("no return value specified for SaveTicker")
 	}
 
-	var r0 User
-	var r1 error
-	if returnFunc, ok := ret.Get(0).(func(int, ...func(*gorm.DB) *gorm.DB) (User, error)); ok {
-		return returnFunc(id, opts...)
-	}
-	if returnFunc, ok := ret.Get(0).(func(int, ...func(*gorm.DB) *gorm.DB) User); ok {
-		r0 = returnFunc(id, opts...)
-	} else {
-		r0 = ret.Get(0).(User)
-	}
-	if returnFunc, ok := ret.Get(1).(func(int, ...func(*gorm.DB) *gorm.DB) error); ok {
-		r1 = returnFunc(id, opts...)
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(*Ticker) error); ok {
+		r0 = returnFunc(ticker)
 	} else {
-		r1 = ret.Error(1)
+		r0 = ret.Error(0)
 	}
-	return r0, r1
+	return r0
 }
 
-// MockStorage_FindUserByID_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'FindUserByID'
-type MockStorage_FindUserByID_Call struct {
+// MockStorage_SaveTicker_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'SaveTicker'
+type MockStorage_SaveTicker_Call struct {
 	*mock.Call
 }
 
-// FindUserByID is a helper method to define mock.On call
-//   - id int
-//   - opts ...func(*gorm.DB) *gorm.DB
-func (_e *MockStorage_Expecter) FindUserByID(id interface{}, opts ...interface{}) *MockStorage_FindUserByID_Call {
-	return &MockStorage_FindUserByID_Call{Call: _e.mock.On("FindUserByID",
-		append([]interface{}{id}, opts...)...)}
+// SaveTicker is a helper method to define mock.On call
+//   - ticker *Ticker
+func (_e *MockStorage_Expecter) SaveTicker(ticker interface{}) *MockStorage_SaveTicker_Call {
+	return &MockStorage_SaveTicker_Call{Call: _e.mock.On("SaveTicker", ticker)}
 }
 
-func (_c *MockStorage_FindUserByID_Call) Run(run func(id int, opts ...func(*gorm.DB) *gorm.DB)) *MockStorage_FindUserByID_Call {
+func (_c *MockStorage_SaveTicker_Call) Run(run func(ticker *Ticker)) *MockStorage_SaveTicker_Call {
 	_c.Call.Run(func(args mock.Arguments) {
-		var arg0 int
+		var arg0 *Ticker
 		if args[0] != nil {
-			arg0 = args[0].(int)
-		}
-		var arg1 []func(*gorm.DB) *gorm.DB
-		var variadicArgs []func(*gorm.DB) *gorm.DB
-		if len(args) > 1 {
-			variadicArgs = args[1].([]func(*gorm.DB) *gorm.DB)
+			arg0 = args[0].(*Ticker)
 		}
-		arg1 = variadicArgs
 		run(
 			arg0,
-			arg1...,
 		)
 	})
 	return _c
 }
 
-func (_c *MockStorage_FindUserByID_Call) Return(user User, err error) *MockStorage_FindUserByID_Call {
-	_c.Call.Return(user, err)
+func (_c *MockStorage_SaveTicker_Call) Return(err error) *MockStorage_SaveTicker_Call {
+	_c.Call.Return(err)
 	return _c
 }
 
-func (_c *MockStorage_FindUserByID_Call) RunAndReturn(run func(id int, opts ...func(*gorm.DB) *gorm.DB) (User, error)) *MockStorage_FindUserByID_Call {
+func (_c *MockStorage_SaveTicker_Call) RunAndReturn(run func(ticker *Ticker) error) *MockStorage_SaveTicker_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
-// FindUsers provides a mock function for the type MockStorage
-func (_mock *MockStorage) FindUsers(filter UserFilter, opts ...func(*gorm.DB) *gorm.DB) ([]User, error) {
-	var tmpRet mock.Arguments
-	if len(opts) > 0 {
-		tmpRet = _mock.Called(filter, opts)
-	} else {
-		tmpRet = _mock.Called(filter)
-	}
-	ret := tmpRet
+// SaveTickerSignalSenders provides a mock function for the type MockStorage
+func (_mock *MockStorage) SaveTickerSignalSenders(ticker *Ticker, senders []TickerSignalSender) error {
+	ret := _mock.Called(ticker, senders)
 
 	if len(ret) == 0 {
-		panic("no return value specified for FindUsers")
+		panic("no return value specified for SaveTickerSignalSenders")
 	}
 
-	var r0 []User
-	var r1 error
-	if returnFunc, ok := ret.Get(0).(func(UserFilter, ...func(*gorm.DB) *gorm.DB) ([]User, error)); ok {
-		return returnFunc(filter, opts...)
-	}
-	if returnFunc, ok := ret.Get(0).(func(UserFilter, ...func(*gorm.DB) *gorm.DB) []User); ok {
-		r0 = returnFunc(filter, opts...)
-	} else {
-		if ret.Get(0) != nil {
-			r0 = ret.Get(0).([]User)
-		}
-	}
-	if returnFunc, ok := ret.Get(1).(func(UserFilter, ...func(*gorm.DB) *gorm.DB) error); ok {
-		r1 = returnFunc(filter, opts...)
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(*Ticker, []TickerSignalSender) error); ok {
+		r0 = returnFunc(ticker, senders)
 	} else {
-		r1 = ret.Error(1)
+		r0 = ret.Error(0)
 	}
-	return r0, r1
+	return r0
 }
 
-// MockStorage_FindUsers_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'FindUsers'
-type MockStorage_FindUsers_Call struct {
+// MockStorage_SaveTickerSignalSenders_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'SaveTickerSignalSenders'
+type MockStorage_SaveTickerSignalSenders_Call struct {
 	*mock.Call
 }
 
-// FindUsers is a helper method to define mock.On call
-//   - filter UserFilter
-//   - opts ...func(*gorm.DB) *gorm.DB
-func (_e *MockStorage_Expecter) FindUsers(filter interface{}, opts ...interface{}) *MockStorage_FindUsers_Call {
-	return &MockStorage_FindUsers_Call{Call: _e.mock.On("FindUsers",
-		append([]interface{}{filter}, opts...)...)}
+// SaveTickerSignalSenders is a helper method to define mock.On call
+//   - ticker *Ticker
+//   - senders []TickerSignalSender
+func (_e *MockStorage_Expecter) SaveTickerSignalSenders(ticker interface{}, senders interface{}) *MockStorage_SaveTickerSignalSenders_Call {
+	return &MockStorage_SaveTickerSignalSenders_Call{Call: _e.mock.On("SaveTickerSignalSenders", ticker, senders)}
 }
 
-func (_c *MockStorage_FindUsers_Call) Run(run func(filter UserFilter, opts ...func(*gorm.DB) *gorm.DB)) *MockStorage_FindUsers_Call {
+func (_c *MockStorage_SaveTickerSignalSenders_Call) Run(run func(ticker *Ticker, senders []TickerSignalSender)) *MockStorage_SaveTickerSignalSenders_Call {
 	_c.Call.Run(func(args mock.Arguments) {
-		var arg0 UserFilter
+		var arg0 *Ticker
 		if args[0] != nil {
-			arg0 = args[0].(UserFilter)
+			arg0 = args[0].(*Ticker)
 		}
-		var arg1 []func(*gorm.DB) *gorm.DB
-		var variadicArgs []func(*gorm.DB) *gorm.DB
-		if len(args) > 1 {
-			variadicArgs = args[1].([]func(*gorm.DB) *gorm.DB)
+		var arg1 []TickerSignalSender
+		if args[1] != nil {
+			arg1 = args[1].([]TickerSignalSender)
 		}
-		arg1 = variadicArgs
 		run(
 			arg0,
-			arg1...,
+			arg1,
 		)
 	})
 	return _c
 }
 
-func (_c *MockStorage_FindUsers_Call) Return(users []User, err error) *MockStorage_FindUsers_Call {
-	_c.Call.Return(users, err)
+func (_c *MockStorage_SaveTickerSignalSenders_Call) Return(err error) *MockStorage_SaveTickerSignalSenders_Call {
+	_c.Call.Return(err)
 	return _c
 }
 
-func (_c *MockStorage_FindUsers_Call) RunAndReturn(run func(filter UserFilter, opts ...func(*gorm.DB) *gorm.DB) ([]User, error)) *MockStorage_FindUsers_Call {
+func (_c *MockStorage_SaveTickerSignalSenders_Call) RunAndReturn(run func(ticker *Ticker, senders []TickerSignalSender) error) *MockStorage_SaveTickerSignalSenders_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
-// FindUsersByIDs provides a mock function for the type MockStorage
-func (_mock *MockStorage) FindUsersByIDs(ids []int, opts ...func(*gorm.DB) *gorm.DB) ([]User, error) {
-	var tmpRet mock.Arguments
-	if len(opts) > 0 {
-		tmpRet = _mock.Called(ids, opts)
-	} else {
-		tmpRet = _mock.Called(ids)
-	}
-	ret := tmpRet
+// SaveTickerUsers provides a mock function for the type MockStorage
+func (_mock *MockStorage) SaveTickerUsers(ticker *Ticker, tickerUsers []TickerUser) error {
+	ret := _mock.Called(ticker, tickerUsers)
 
 	if len(ret) == 0 {
-		panic("no return value specified for FindUsersByIDs")
+		panic("no return value specified for SaveTickerUsers")
 	}
 
-	var r0 []User
-	var r1 error
-	if returnFunc, ok := ret.Get(0).(func([]int, ...func(*gorm.DB) *gorm.DB) ([]User, error)); ok {
-		return returnFunc(ids, opts...)
-	}
-	if returnFunc, ok := ret.Get(0).(func([]int, ...func(*gorm.DB) *gorm.DB) []User); ok {
-		r0 = returnFunc(ids, opts...)
-	} else {
-		if ret.Get(0) != nil {
-			r0 = ret.Get(0).([]User)
-		}
-	}
-	if returnFunc, ok := ret.Get(1).(func([]int, ...func(*gorm.DB) *gorm.DB) error); ok {
-		r1 = returnFunc(ids, opts...)
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(*Ticker, []TickerUser) error); ok {
+		r0 = returnFunc(ticker, tickerUsers)
 	} else {
-		r1 = ret.Error(1)
+		r0 = ret.Error(0)
 	}
-	return r0, r1
-}
-
-// MockStorage_FindUsersByIDs_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'FindUsersByIDs'
-type MockStorage_FindUsersByIDs_Call struct {
-	*mock.Call
+	return r0
 }
-
-// FindUsersByIDs is a helper method to define mock.On call
-//   - ids []int
-//   - opts ...func(*gorm.DB) *gorm.DB
-func (_e *MockStorage_Expecter) FindUsersByIDs(ids interface{}, opts ...interface{}) *MockStorage_FindUsersByIDs_Call {
-	return &MockStorage_FindUsersByIDs_Call{Call: _e.mock.On("FindUsersByIDs",
-		append([]interface{}{ids}, opts...)...)}
+
+// MockStorage_SaveTickerUsers_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'SaveTickerUsers'
+type MockStorage_SaveTickerUsers_Call struct {
+	*mock.Call
 }
 
-func (_c *MockStorage_FindUsersByIDs_Call) Run(run func(ids []int, opts ...func(*gorm.DB) *gorm.DB)) *MockStorage_FindUsersByIDs_Call {
+// SaveTickerUsers is a helper method to define mock.On call
+//   - ticker *Ticker
+//   - tickerUsers []TickerUser
+func (_e *MockStorage_Expecter) SaveTickerUsers(ticker interface{}, tickerUsers interface{}) *MockStorage_SaveTickerUsers_Call {
+	return &MockStorage_SaveTickerUsers_Call{Call: _e.mock.On("SaveTickerUsers", ticker, tickerUsers)}
+}
+
+func (_c *MockStorage_SaveTickerUsers_Call) Run(run func(ticker *Ticker, tickerUsers []TickerUser)) *MockStorage_SaveTickerUsers_Call {
 	_c.Call.Run(func(args mock.Arguments) {
-		var arg0 []int
+		var arg0 *Ticker
 		if args[0] != nil {
-			arg0 = args[0].([]int)
+			arg0 = args[0].(*Ticker)
 		}
-		var arg1 []func(*gorm.DB) *gorm.DB
-		var variadicArgs []func(*gorm.DB) *gorm.DB
-		if len(args) > 1 {
-			variadicArgs = args[1].([]func(*gorm.DB) *gorm.DB)
+		var arg1 []TickerUser
+		if args[1] != nil {
+			arg1 = args[1].([]TickerUser)
 		}
-		arg1 = variadicArgs
 		run(
 			arg0,
-			arg1...,
+			arg1,
 		)
 	})
 	return _c
 }
 
-func (_c *MockStorage_FindUsersByIDs_Call) Return(users []User, err error) *MockStorage_FindUsersByIDs_Call {
-	_c.Call.Return(users, err)
+func (_c *MockStorage_SaveTickerUsers_Call) Return(err error) *MockStorage_SaveTickerUsers_Call {
+	_c.Call.Return(err)
 	return _c
 }
 
-func (_c *MockStorage_FindUsersByIDs_Call) RunAndReturn(run func(ids []int, opts ...func(*gorm.DB) *gorm.DB) ([]User, error)) *MockStorage_FindUsersByIDs_Call {
+func (_c *MockStorage_SaveTickerUsers_Call) RunAndReturn(run func(ticker *Ticker, tickerUsers []TickerUser) error) *MockStorage_SaveTickerUsers_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
-// FindUsersByTicker provides a mock function for the type MockStorage
-func (_mock *MockStorage) FindUsersByTicker(ticker Ticker, opts ...func(*gorm.DB) *gorm.DB) ([]User, error) {
-	var tmpRet mock.Arguments
-	if len(opts) > 0 {
-		tmpRet = _mock.Called(ticker, opts)
-	} else {
-		tmpRet = _mock.Called(ticker)
-	}
-	ret := tmpRet
+// SaveTickerWebhooks provides a mock function for the type MockStorage
+func (_mock *MockStorage) SaveTickerWebhooks(ticker *Ticker, webhooks []TickerWebhook) error {
+	ret := _mock.Called(ticker, webhooks)
 
 	if len(ret) == 0 {
-		panic("no return value specified for FindUsersByTicker")
+		panic("no return value specified for SaveTickerWebhooks")
 	}
 
-	var r0 []User
-	var r1 error
-	if returnFunc, ok := ret.Get(0).(func(Ticker, ...func(*gorm.DB) *gorm.DB) ([]User, error)); ok {
-		return returnFunc(ticker, opts...)
-	}
-	if returnFunc, ok := ret.Get(0).(func(Ticker, ...func(*gorm.DB) *gorm.DB) []User); ok {
-		r0 = returnFunc(ticker, opts...)
-	} else {
-		if ret.Get(0) != nil {
-			r0 = ret.Get(0).([]User)
-		}
-	}
-	if returnFunc, ok := ret.Get(1).(func(Ticker, ...func(*gorm.DB) *gorm.DB) error); ok {
-		r1 = returnFunc(ticker, opts...)
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(*Ticker, []TickerWebhook) error); ok {
+		r0 = returnFunc(ticker, webhooks)
 	} else {
-		r1 = ret.Error(1)
+		r0 = ret.Error(0)
 	}
-	return r0, r1
+	return r0
 }
 
-// MockStorage_FindUsersByTicker_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'FindUsersByTicker'
-type MockStorage_FindUsersByTicker_Call struct {
+// MockStorage_SaveTickerWebhooks_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'SaveTickerWebhooks'
+type MockStorage_SaveTickerWebhooks_Call struct {
 	*mock.Call
 }
 
-// FindUsersByTicker is a helper method to define mock.On call
-//   - ticker Ticker
-//   - opts ...func(*gorm.DB) *gorm.DB
-func (_e *MockStorage_Expecter) FindUsersByTicker(ticker interface{}, opts ...interface{}) *MockStorage_FindUsersByTicker_Call {
-	return &MockStorage_FindUsersByTicker_Call{Call: _e.mock.On("FindUsersByTicker",
-		append([]interface{}{ticker}, opts...)...)}
+// SaveTickerWebhooks is a helper method to define mock.On call
+//   - ticker *Ticker
+//   - webhooks []TickerWebhook
+func (_e *MockStorage_Expecter) SaveTickerWebhooks(ticker interface{}, webhooks interface{}) *MockStorage_SaveTickerWebhooks_Call {
+	return &MockStorage_SaveTickerWebhooks_Call{Call: _e.mock.On("SaveTickerWebhooks", ticker, webhooks)}
 }
 
-func (_c *MockStorage_FindUsersByTicker_Call) Run(run func(ticker Ticker, opts ...func(*gorm.DB) *gorm.DB)) *MockStorage_FindUsersByTicker_Call {
+func (_c *MockStorage_SaveTickerWebhooks_Call) Run(run func(ticker *Ticker, webhooks []TickerWebhook)) *MockStorage_SaveTickerWebhooks_Call {
 	_c.Call.Run(func(args mock.Arguments) {
-		var arg0 Ticker
+		var arg0 *Ticker
 		if args[0] != nil {
-			arg0 = args[0].(Ticker)
+			arg0 = args[0].(*Ticker)
 		}
-		var arg1 []func(*gorm.DB) *gorm.DB
-		var variadicArgs []func(*gorm.DB) *gorm.DB
-		if len(args) > 1 {
-			variadicArgs = args[1].([]func(*gorm.DB) *gorm.DB)
+		var arg1 []TickerWebhook
+		if args[1] != nil {
+			arg1 = args[1].([]TickerWebhook)
 		}
-		arg1 = variadicArgs
 		run(
 			arg0,
-			arg1...,
+			arg1,
 		)
 	})
 	return _c
 }
 
-func (_c *MockStorage_FindUsersByTicker_Call) Return(users []User, err error) *MockStorage_FindUsersByTicker_Call {
-	_c.Call.Return(users, err)
+func (_c *MockStorage_SaveTickerWebhooks_Call) Return(err error) *MockStorage_SaveTickerWebhooks_Call {
+	_c.Call.Return(err)
 	return _c
 }
 
-func (_c *MockStorage_FindUsersByTicker_Call) RunAndReturn(run func(ticker Ticker, opts ...func(*gorm.DB) *gorm.DB) ([]User, error)) *MockStorage_FindUsersByTicker_Call {
+func (_c *MockStorage_SaveTickerWebhooks_Call) RunAndReturn(run func(ticker *Ticker, webhooks []TickerWebhook) error) *MockStorage_SaveTickerWebhooks_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
-// GetInactiveSettings provides a mock function for the type MockStorage
-func (_mock *MockStorage) GetInactiveSettings() InactiveSettings {
-	ret := _mock.Called()
+// SaveTickerWebsites provides a mock function for the type MockStorage
+func (_mock *MockStorage) SaveTickerWebsites(ticker *Ticker, websites []TickerWebsite) error {
+	ret := _mock.Called(ticker, websites)
 
 	if len(ret) == 0 {
-		panic("no return value specified for GetInactiveSettings")
+		panic("no return value specified for SaveTickerWebsites")
 	}
 
-	var r0 InactiveSettings
-	if returnFunc, ok := ret.Get(0).(func() InactiveSettings); ok {
-		r0 = returnFunc()
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(*Ticker, []TickerWebsite) error); ok {
+		r0 = returnFunc(ticker, websites)
 	} else {
-		r0 = ret.Get(0).(InactiveSettings)
+		r0 = ret.Error(0)
 	}
 	return r0
 }
 
-// MockStorage_GetInactiveSettings_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetInactiveSettings'
-type MockStorage_GetInactiveSettings_Call struct {
+// MockStorage_SaveTickerWebsites_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'SaveTickerWebsites'
+type MockStorage_SaveTickerWebsites_Call struct {
 	*mock.Call
 }
 
-// GetInactiveSettings is a helper method to define mock.On call
-func (_e *MockStorage_Expecter) GetInactiveSettings() *MockStorage_GetInactiveSettings_Call {
-	return &MockStorage_GetInactiveSettings_Call{Call: _e.mock.On("GetInactiveSettings")}
+// SaveTickerWebsites is a helper method to define mock.On call
+//   - ticker *Ticker
+//   - websites []TickerWebsite
+func (_e *MockStorage_Expecter) SaveTickerWebsites(ticker interface{}, websites interface{}) *MockStorage_SaveTickerWebsites_Call {
+	return &MockStorage_SaveTickerWebsites_Call{Call: _e.mock.On("SaveTickerWebsites", ticker, websites)}
 }
 
-func (_c *MockStorage_GetInactiveSettings_Call) Run(run func()) *MockStorage_GetInactiveSettings_Call {
+func (_c *MockStorage_SaveTickerWebsites_Call) Run(run func(ticker *Ticker, websites []TickerWebsite)) *MockStorage_SaveTickerWebsites_Call {
 	_c.Call.Run(func(args mock.Arguments) {
-		run()
+		var arg0 *Ticker
+		if args[0] != nil {
+			arg0 = args[0].(*Ticker)
+		}
+		var arg1 []TickerWebsite
+		if args[1] != nil {
+			arg1 = args[1].([]TickerWebsite)
+		}
+		run(
+			arg0,
+			arg1,
+		)
 	})
 	return _c
 }
 
-func (_c *MockStorage_GetInactiveSettings_Call) Return(inactiveSettings InactiveSettings) *MockStorage_GetInactiveSettings_Call {
-	_c.Call.Return(inactiveSettings)
+func (_c *MockStorage_SaveTickerWebsites_Call) Return(err error) *MockStorage_SaveTickerWebsites_Call {
+	_c.Call.Return(err)
 	return _c
 }
 
-func (_c *MockStorage_GetInactiveSettings_Call) RunAndReturn(run func() InactiveSettings) *MockStorage_GetInactiveSettings_Call {
+func (_c *MockStorage_SaveTickerWebsites_Call) RunAndReturn(run func(ticker *Ticker, websites []TickerWebsite) error) *MockStorage_SaveTickerWebsites_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
-// GetSignalGroupSettings provides a mock function for the type MockStorage
-func (_mock *MockStorage) GetSignalGroupSettings() SignalGroupSettings {
-	ret := _mock.Called()
+// SaveUpload provides a mock function for the type MockStorage
+func (_mock *MockStorage) SaveUpload(upload *Upload) error {
+	ret := _mock.Called(upload)
 
 	if len(ret) == 0 {
-		panic("no return value specified for GetSignalGroupSettings")
+		panic("no return value specified for SaveUpload")
 	}
 
-	var r0 SignalGroupSettings
-	if returnFunc, ok := ret.Get(0).(func() SignalGroupSettings); ok {
-		r0 = returnFunc()
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(*Upload) error); ok {
+		r0 = returnFunc(upload)
 	} else {
-		r0 = ret.Get(0).(SignalGroupSettings)
+		r0 = ret.Error(0)
 	}
 	return r0
 }
 
-// MockStorage_GetSignalGroupSettings_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetSignalGroupSettings'
-type MockStorage_GetSignalGroupSettings_Call struct {
+// MockStorage_SaveUpload_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'SaveUpload'
+type MockStorage_SaveUpload_Call struct {
 	*mock.Call
 }
 
-// GetSignalGroupSettings is a helper method to define mock.On call
-func (_e *MockStorage_Expecter) GetSignalGroupSettings() *MockStorage_GetSignalGroupSettings_Call {
-	return &MockStorage_GetSignalGroupSettings_Call{Call: _e.mock.On("GetSignalGroupSettings")}
+// SaveUpload is a helper method to define mock.On call
+//   - upload *Upload
+func (_e *MockStorage_Expecter) SaveUpload(upload interface{}) *MockStorage_SaveUpload_Call {
+	return &MockStorage_SaveUpload_Call{Call: _e.mock.On("SaveUpload", upload)}
 }
 
-func (_c *MockStorage_GetSignalGroupSettings_Call) Run(run func()) *MockStorage_GetSignalGroupSettings_Call {
+func (_c *MockStorage_SaveUpload_Call) Run(run func(upload *Upload)) *MockStorage_SaveUpload_Call {
 	_c.Call.Run(func(args mock.Arguments) {
-		run()
+		var arg0 *Upload
+		if args[0] != nil {
+			arg0 = args[0].(*Upload)
+		}
+		run(
+			arg0,
+		)
 	})
 	return _c
 }
 
-func (_c *MockStorage_GetSignalGroupSettings_Call) Return(signalGroupSettings SignalGroupSettings) *MockStorage_GetSignalGroupSettings_Call {
-	_c.Call.Return(signalGroupSettings)
+func (_c *MockStorage_SaveUpload_Call) Return(err error) *MockStorage_SaveUpload_Call {
+	_c.Call.Return(err)
 	return _c
 }
 
-func (_c *MockStorage_GetSignalGroupSettings_Call) RunAndReturn(run func() SignalGroupSettings) *MockStorage_GetSignalGroupSettings_Call {
+func (_c *MockStorage_SaveUpload_Call) RunAndReturn(run func(upload *Upload) error) *MockStorage_SaveUpload_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
-// GetTelegramSettings provides a mock function for the type MockStorage
-func (_mock *MockStorage) GetTelegramSettings() TelegramSettings {
-	ret := _mock.Called()
+// SaveUser provides a mock function for the type MockStorage
+func (_mock *MockStorage) SaveUser(user *User) error {
+	ret := _mock.Called(user)
 
 	if len(ret) == 0 {
-		panic("no return value specified for GetTelegramSettings")
+		panic("no return value specified for SaveUser")
 	}
 
-	var r0 TelegramSettings
-	if returnFunc, ok := ret.Get(0).(func() TelegramSettings); ok {
-		r0 = returnFunc()
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(*User) error); ok {
+		r0 = returnFunc(user)
 	} else {
-		r0 = ret.Get(0).(TelegramSettings)
+		r0 = ret.Error(0)
 	}
 	return r0
 }
 
-// MockStorage_GetTelegramSettings_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetTelegramSettings'
-type MockStorage_GetTelegramSettings_Call struct {
+// MockStorage_SaveUser_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'SaveUser'
+type MockStorage_SaveUser_Call struct {
 	*mock.Call
 }
 
-// GetTelegramSettings is a helper method to define mock.On call
-func (_e *MockStorage_Expecter) GetTelegramSettings() *MockStorage_GetTelegramSettings_Call {
-	return &MockStorage_GetTelegramSettings_Call{Call: _e.mock.On("GetTelegramSettings")}
+// SaveUser is a helper method to define mock.On call
+//   - user *User
+func (_e *MockStorage_Expecter) SaveUser(user interface{}) *MockStorage_SaveUser_Call {
+	return &MockStorage_SaveUser_Call{Call: _e.mock.On("SaveUser", user)}
 }
 
-func (_c *MockStorage_GetTelegramSettings_Call) Run(run func()) *MockStorage_GetTelegramSettings_Call {
+func (_c *MockStorage_SaveUser_Call) Run(run func(user *User)) *MockStorage_SaveUser_Call {
 	_c.Call.Run(func(args mock.Arguments) {
-		run()
+		var arg0 *User
+		if args[0] != nil {
+			arg0 = args[0].(*User)
+		}
+		run(
+			arg0,
+		)
 	})
 	return _c
 }
 
-func (_c *MockStorage_GetTelegramSettings_Call) Return(telegramSettings TelegramSettings) *MockStorage_GetTelegramSettings_Call {
-	_c.Call.Return(telegramSettings)
+func (_c *MockStorage_SaveUser_Call) Return(err error) *MockStorage_SaveUser_Call {
+	_c.Call.Return(err)
 	return _c
 }
 
-func (_c *MockStorage_GetTelegramSettings_Call) RunAndReturn(run func() TelegramSettings) *MockStorage_GetTelegramSettings_Call {
+func (_c *MockStorage_SaveUser_Call) RunAndReturn(run func(user *User) error) *MockStorage_SaveUser_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
-// ResetTicker provides a mock function for the type MockStorage
-func (_mock *MockStorage) ResetTicker(ticker *Ticker) error {
-	ret := _mock.Called(ticker)
+// SaveWebAuthnCredential provides a mock function for the type MockStorage
+func (_mock *MockStorage) SaveWebAuthnCredential(credential *WebAuthnCredential) error {
+	ret := _mock.Called(credential)
 
 	if len(ret) == 0 {
-		panic("no return value specified for ResetTicker")
+		panic("no return value specified for SaveWebAuthnCredential")
 	}
 
 	var r0 error
-	if returnFunc, ok := ret.Get(0).(func(*Ticker) error); ok {
-		r0 = returnFunc(ticker)
+	if returnFunc, ok := ret.Get(0).(func(*WebAuthnCredential) error); ok {
+		r0 = returnFunc(credential)
 	} else {
 		r0 = ret.Error(0)
 	}
 	return r0
 }
 
-// MockStorage_ResetTicker_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ResetTicker'
-type MockStorage_ResetTicker_Call struct {
+// MockStorage_SaveWebAuthnCredential_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'SaveWebAuthnCredential'
+type MockStorage_SaveWebAuthnCredential_Call struct {
 	*mock.Call
 }
 
-// ResetTicker is a helper method to define mock.On call
-//   - ticker *Ticker
-func (_e *MockStorage_Expecter) ResetTicker(ticker interface{}) *MockStorage_ResetTicker_Call {
-	return &MockStorage_ResetTicker_Call{Call: _e.mock.On("ResetTicker", ticker)}
+// SaveWebAuthnCredential is a helper method to define mock.On call
+//   - credential *WebAuthnCredential
+func (_e *MockStorage_Expecter) SaveWebAuthnCredential(credential interface{}) *MockStorage_SaveWebAuthnCredential_Call {
+	return &MockStorage_SaveWebAuthnCredential_Call{Call: _e.mock.On("SaveWebAuthnCredential", credential)}
 }
 
-func (_c *MockStorage_ResetTicker_Call) Run(run func(ticker *Ticker)) *MockStorage_ResetTicker_Call {
+func (_c *MockStorage_SaveWebAuthnCredential_Call) Run(run func(credential *WebAuthnCredential)) *MockStorage_SaveWebAuthnCredential_Call {
 	_c.Call.Run(func(args mock.Arguments) {
-		var arg0 *Ticker
+		var arg0 *WebAuthnCredential
 		if args[0] != nil {
-			arg0 = args[0].(*Ticker)
+			arg0 = args[0].(*WebAuthnCredential)
 		}
 		run(
 			arg0,
@@ -2162,49 +7048,49 @@ func (_c *MockStorage_ResetTicker_Call) Run(run func(ticker *Ticker)) *MockStora
 	return _c
 }
 
-func (_c *MockStorage_ResetTicker_Call) Return(err error) *MockStorage_ResetTicker_Call {
+func (_c *MockStorage_SaveWebAuthnCredential_Call) Return(err error) *MockStorage_SaveWebAuthnCredential_Call {
 	_c.Call.Return(err)
 	return _c
 }
 
-func (_c *MockStorage_ResetTicker_Call) RunAndReturn(run func(ticker *Ticker) error) *MockStorage_ResetTicker_Call {
+func (_c *MockStorage_SaveWebAuthnCredential_Call) RunAndReturn(run func(credential *WebAuthnCredential) error) *MockStorage_SaveWebAuthnCredential_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
-// SaveInactiveSettings provides a mock function for the type MockStorage
-func (_mock *MockStorage) SaveInactiveSettings(inactiveSettings InactiveSettings) error {
-	ret := _mock.Called(inactiveSettings)
+// SaveWebAuthnHandle provides a mock function for the type MockStorage
+func (_mock *MockStorage) SaveWebAuthnHandle(user *User) error {
+	ret := _mock.Called(user)
 
 	if len(ret) == 0 {
-		panic("no return value specified for SaveInactiveSettings")
+		panic("no return value specified for SaveWebAuthnHandle")
 	}
 
 	var r0 error
-	if returnFunc, ok := ret.Get(0).(func(InactiveSettings) error); ok {
-		r0 = returnFunc(inactiveSettings)
+	if returnFunc, ok := ret.Get(0).(func(*User) error); ok {
+		r0 = returnFunc(user)
 	} else {
 		r0 = ret.Error(0)
 	}
 	return r0
 }
 
-// MockStorage_SaveInactiveSettings_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'SaveInactiveSettings'
-type MockStorage_SaveInactiveSettings_Call struct {
+// MockStorage_SaveWebAuthnHandle_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'SaveWebAuthnHandle'
+type MockStorage_SaveWebAuthnHandle_Call struct {
 	*mock.Call
 }
 
-// SaveInactiveSettings is a helper method to define mock.On call
-//   - inactiveSettings InactiveSettings
-func (_e *MockStorage_Expecter) SaveInactiveSettings(inactiveSettings interface{}) *MockStorage_SaveInactiveSettings_Call {
-	return &MockStorage_SaveInactiveSettings_Call{Call: _e.mock.On("SaveInactiveSettings", inactiveSettings)}
+// SaveWebAuthnHandle is a helper method to define mock.On call
+//   - user *User
+func (_e *MockStorage_Expecter) SaveWebAuthnHandle(user interface{}) *MockStorage_SaveWebAuthnHandle_Call {
+	return &MockStorage_SaveWebAuthnHandle_Call{Call: _e.mock.On("SaveWebAuthnHandle", user)}
 }
 
-func (_c *MockStorage_SaveInactiveSettings_Call) Run(run func(inactiveSettings InactiveSettings)) *MockStorage_SaveInactiveSettings_Call {
+func (_c *MockStorage_SaveWebAuthnHandle_Call) Run(run func(user *User)) *MockStorage_SaveWebAuthnHandle_Call {
 	_c.Call.Run(func(args mock.Arguments) {
-		var arg0 InactiveSettings
+		var arg0 *User
 		if args[0] != nil {
-			arg0 = args[0].(InactiveSettings)
+			arg0 = args[0].(*User)
 		}
 		run(
 			arg0,
@@ -2213,49 +7099,49 @@ func (_c *MockStorage_SaveInactiveSettings_Call) Run(run func(inactiveSettings I
 	return _c
 }
 
-func (_c *MockStorage_SaveInactiveSettings_Call) Return(err error) *MockStorage_SaveInactiveSettings_Call {
+func (_c *MockStorage_SaveWebAuthnHandle_Call) Return(err error) *MockStorage_SaveWebAuthnHandle_Call {
 	_c.Call.Return(err)
 	return _c
 }
 
-func (_c *MockStorage_SaveInactiveSettings_Call) RunAndReturn(run func(inactiveSettings InactiveSettings) error) *MockStorage_SaveInactiveSettings_Call {
+func (_c *MockStorage_SaveWebAuthnHandle_Call) RunAndReturn(run func(user *User) error) *MockStorage_SaveWebAuthnHandle_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
-// SaveMessage provides a mock function for the type MockStorage
-func (_mock *MockStorage) SaveMessage(message *Message) error {
-	ret := _mock.Called(message)
+// SaveWebAuthnSession provides a mock function for the type MockStorage
+func (_mock *MockStorage) SaveWebAuthnSession(session *WebAuthnSession) error {
+	ret := _mock.Called(session)
 
 	if len(ret) == 0 {
-		panic("no return value specified for SaveMessage")
+		panic("no return value specified for SaveWebAuthnSession")
 	}
 
 	var r0 error
-	if returnFunc, ok := ret.Get(0).(func(*Message) error); ok {
-		r0 = returnFunc(message)
+	if returnFunc, ok := ret.Get(0).(func(*WebAuthnSession) error); ok {
+		r0 = returnFunc(session)
 	} else {
 		r0 = ret.Error(0)
 	}
 	return r0
 }
 
-// MockStorage_SaveMessage_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'SaveMessage'
-type MockStorage_SaveMessage_Call struct {
+// MockStorage_SaveWebAuthnSession_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'SaveWebAuthnSession'
+type MockStorage_SaveWebAuthnSession_Call struct {
 	*mock.Call
 }
 
-// SaveMessage is a helper method to define mock.On call
-//   - message *Message
-func (_e *MockStorage_Expecter) SaveMessage(message interface{}) *MockStorage_SaveMessage_Call {
-	return &MockStorage_SaveMessage_Call{Call: _e.mock.On("SaveMessage", message)}
+// SaveWebAuthnSession is a helper method to define mock.On call
+//   - session *WebAuthnSession
+func (_e *MockStorage_Expecter) SaveWebAuthnSession(session interface{}) *MockStorage_SaveWebAuthnSession_Call {
+	return &MockStorage_SaveWebAuthnSession_Call{Call: _e.mock.On("SaveWebAuthnSession", session)}
 }
 
-func (_c *MockStorage_SaveMessage_Call) Run(run func(message *Message)) *MockStorage_SaveMessage_Call {
+func (_c *MockStorage_SaveWebAuthnSession_Call) Run(run func(session *WebAuthnSession)) *MockStorage_SaveWebAuthnSession_Call {
 	_c.Call.Run(func(args mock.Arguments) {
-		var arg0 *Message
+		var arg0 *WebAuthnSession
 		if args[0] != nil {
-			arg0 = args[0].(*Message)
+			arg0 = args[0].(*WebAuthnSession)
 		}
 		run(
 			arg0,
@@ -2264,49 +7150,49 @@ func (_c *MockStorage_SaveMessage_Call) Run(run func(message *Message)) *MockSto
 	return _c
 }
 
-func (_c *MockStorage_SaveMessage_Call) Return(err error) *MockStorage_SaveMessage_Call {
+func (_c *MockStorage_SaveWebAuthnSession_Call) Return(err error) *MockStorage_SaveWebAuthnSession_Call {
 	_c.Call.Return(err)
 	return _c
 }
 
-func (_c *MockStorage_SaveMessage_Call) RunAndReturn(run func(message *Message) error) *MockStorage_SaveMessage_Call {
+func (_c *MockStorage_SaveWebAuthnSession_Call) RunAndReturn(run func(session *WebAuthnSession) error) *MockStorage_SaveWebAuthnSession_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
-// SaveSignalGroupSettings provides a mock function for the type MockStorage
-func (_mock *MockStorage) SaveSignalGroupSettings(signalGroupSettings SignalGroupSettings) error {
-	ret := _mock.Called(signalGroupSettings)
+// SaveWebPushSettings provides a mock function for the type MockStorage
+func (_mock *MockStorage) SaveWebPushSettings(webPushSettings WebPushSettings) error {
+	ret := _mock.Called(webPushSettings)
 
 	if len(ret) == 0 {
-		panic("no return value specified for SaveSignalGroupSettings")
+		panic("no return value specified for SaveWebPushSettings")
 	}
 
 	var r0 error
-	if returnFunc, ok := ret.Get(0).(func(SignalGroupSettings) error); ok {
-		r0 = returnFunc(signalGroupSettings)
+	if returnFunc, ok := ret.Get(0).(func(WebPushSettings) error); ok {
+		r0 = returnFunc(webPushSettings)
 	} else {
 		r0 = ret.Error(0)
 	}
 	return r0
 }
 
-// MockStorage_SaveSignalGroupSettings_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'SaveSignalGroupSettings'
-type MockStorage_SaveSignalGroupSettings_Call struct {
+// MockStorage_SaveWebPushSettings_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'SaveWebPushSettings'
+type MockStorage_SaveWebPushSettings_Call struct {
 	*mock.Call
 }
 
-// SaveSignalGroupSettings is a helper method to define mock.On call
-//   - signalGroupSettings SignalGroupSettings
-func (_e *MockStorage_Expecter) SaveSignalGroupSettings(signalGroupSettings interface{}) *MockStorage_SaveSignalGroupSettings_Call {
-	return &MockStorage_SaveSignalGroupSettings_Call{Call: _e.mock.On("SaveSignalGroupSettings", signalGroupSettings)}
+// SaveWebPushSettings is a helper method to define mock.On call
+//   - webPushSettings WebPushSettings
+func (_e *MockStorage_Expecter) SaveWebPushSettings(webPushSettings interface{}) *MockStorage_SaveWebPushSettings_Call {
+	return &MockStorage_SaveWebPushSettings_Call{Call: _e.mock.On("SaveWebPushSettings", webPushSettings)}
 }
 
-func (_c *MockStorage_SaveSignalGroupSettings_Call) Run(run func(signalGroupSettings SignalGroupSettings)) *MockStorage_SaveSignalGroupSettings_Call {
+func (_c *MockStorage_SaveWebPushSettings_Call) Run(run func(webPushSettings WebPushSettings)) *MockStorage_SaveWebPushSettings_Call {
 	_c.Call.Run(func(args mock.Arguments) {
-		var arg0 SignalGroupSettings
+		var arg0 WebPushSettings
 		if args[0] != nil {
-			arg0 = args[0].(SignalGroupSettings)
+			arg0 = args[0].(WebPushSettings)
 		}
 		run(
 			arg0,
@@ -2315,49 +7201,49 @@ func (_c *MockStorage_SaveSignalGroupSettings_Call) Run(run func(signalGroupSett
 	return _c
 }
 
-func (_c *MockStorage_SaveSignalGroupSettings_Call) Return(err error) *MockStorage_SaveSignalGroupSettings_Call {
+func (_c *MockStorage_SaveWebPushSettings_Call) Return(err error) *MockStorage_SaveWebPushSettings_Call {
 	_c.Call.Return(err)
 	return _c
 }
 
-func (_c *MockStorage_SaveSignalGroupSettings_Call) RunAndReturn(run func(signalGroupSettings SignalGroupSettings) error) *MockStorage_SaveSignalGroupSettings_Call {
+func (_c *MockStorage_SaveWebPushSettings_Call) RunAndReturn(run func(webPushSettings WebPushSettings) error) *MockStorage_SaveWebPushSettings_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
-// SaveTelegramSettings provides a mock function for the type MockStorage
-func (_mock *MockStorage) SaveTelegramSettings(telegramSettings TelegramSettings) error {
-	ret := _mock.Called(telegramSettings)
+// SaveWebPushSubscription provides a mock function for the type MockStorage
+func (_mock *MockStorage) SaveWebPushSubscription(subscription *WebPushSubscription) error {
+	ret := _mock.Called(subscription)
 
 	if len(ret) == 0 {
-		panic("no return value specified for SaveTelegramSettings")
+		panic("no return value specified for SaveWebPushSubscription")
 	}
 
 	var r0 error
-	if returnFunc, ok := ret.Get(0).(func(TelegramSettings) error); ok {
-		r0 = returnFunc(telegramSettings)
+	if returnFunc, ok := ret.Get(0).(func(*WebPushSubscription) error); ok {
+		r0 = returnFunc(subscription)
 	} else {
 		r0 = ret.Error(0)
 	}
 	return r0
 }
 
-// MockStorage_SaveTelegramSettings_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'SaveTelegramSettings'
-type MockStorage_SaveTelegramSettings_Call struct {
+// MockStorage_SaveWebPushSubscription_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'SaveWebPushSubscription'
+type MockStorage_SaveWebPushSubscription_Call struct {
 	*mock.Call
 }
 
-// SaveTelegramSettings is a helper method to define mock.On call
-//   - telegramSettings TelegramSettings
-func (_e *MockStorage_Expecter) SaveTelegramSettings(telegramSettings interface{}) *MockStorage_SaveTelegramSettings_Call {
-	return &MockStorage_SaveTelegramSettings_Call{Call: _e.mock.On("SaveTelegramSettings", telegramSettings)}
+// SaveWebPushSubscription is a helper method to define mock.On call
+//   - subscription *WebPushSubscription
+func (_e *MockStorage_Expecter) SaveWebPushSubscription(subscription interface{}) *MockStorage_SaveWebPushSubscription_Call {
+	return &MockStorage_SaveWebPushSubscription_Call{Call: _e.mock.On("SaveWebPushSubscription", subscription)}
 }
 
-func (_c *MockStorage_SaveTelegramSettings_Call) Run(run func(telegramSettings TelegramSettings)) *MockStorage_SaveTelegramSettings_Call {
+func (_c *MockStorage_SaveWebPushSubscription_Call) Run(run func(subscription *WebPushSubscription)) *MockStorage_SaveWebPushSubscription_Call {
 	_c.Call.Run(func(args mock.Arguments) {
-		var arg0 TelegramSettings
+		var arg0 *WebPushSubscription
 		if args[0] != nil {
-			arg0 = args[0].(TelegramSettings)
+			arg0 = args[0].(*WebPushSubscription)
 		}
 		run(
 			arg0,
@@ -2366,157 +7252,207 @@ func (_c *MockStorage_SaveTelegramSettings_Call) Run(run func(telegramSettings T
 	return _c
 }
 
-func (_c *MockStorage_SaveTelegramSettings_Call) Return(err error) *MockStorage_SaveTelegramSettings_Call {
+func (_c *MockStorage_SaveWebPushSubscription_Call) Return(err error) *MockStorage_SaveWebPushSubscription_Call {
 	_c.Call.Return(err)
 	return _c
 }
 
-func (_c *MockStorage_SaveTelegramSettings_Call) RunAndReturn(run func(telegramSettings TelegramSettings) error) *MockStorage_SaveTelegramSettings_Call {
+func (_c *MockStorage_SaveWebPushSubscription_Call) RunAndReturn(run func(subscription *WebPushSubscription) error) *MockStorage_SaveWebPushSubscription_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
-// SaveTicker provides a mock function for the type MockStorage
-func (_mock *MockStorage) SaveTicker(ticker *Ticker) error {
-	ret := _mock.Called(ticker)
+// SearchMessagesByTicker provides a mock function for the type MockStorage
+func (_mock *MockStorage) SearchMessagesByTicker(ticker Ticker, query string, pagination1 pagination.Pagination, opts ...func(*gorm.DB) *gorm.DB) ([]Message, error) {
+	var tmpRet mock.Arguments
+	if len(opts) > 0 {
+		tmpRet = _mock.Called(ticker, query, pagination1, opts)
+	} else {
+		tmpRet = _mock.Called(ticker, query, pagination1)
+	}
+	ret := tmpRet
 
 	if len(ret) == 0 {
-		panic("no return value specified for SaveTicker")
+		panic("no return value specified for SearchMessagesByTicker")
 	}
 
-	var r0 error
-	if returnFunc, ok := ret.Get(0).(func(*Ticker) error); ok {
-		r0 = returnFunc(ticker)
+	var r0 []Message
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(Ticker, string, pagination.Pagination, ...func(*gorm.DB) *gorm.DB) ([]Message, error)); ok {
+		return returnFunc(ticker, query, pagination1, opts...)
+	}
+	if returnFunc, ok := ret.Get(0).(func(Ticker, string, pagination.Pagination, ...func(*gorm.DB) *gorm.DB) []Message); ok {
+		r0 = returnFunc(ticker, query, pagination1, opts...)
 	} else {
-		r0 = ret.Error(0)
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]Message)
+		}
 	}
-	return r0
+	if returnFunc, ok := ret.Get(1).(func(Ticker, string, pagination.Pagination, ...func(*gorm.DB) *gorm.DB) error); ok {
+		r1 = returnFunc(ticker, query, pagination1, opts...)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
 }
 
-// MockStorage_SaveTicker_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'SaveTicker'
-type MockStorage_SaveTicker_Call struct {
+// MockStorage_SearchMessagesByTicker_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'SearchMessagesByTicker'
+type MockStorage_SearchMessagesByTicker_Call struct {
 	*mock.Call
 }
 
-// SaveTicker is a helper method to define mock.On call
-//   - ticker *Ticker
-func (_e *MockStorage_Expecter) SaveTicker(ticker interface{}) *MockStorage_SaveTicker_Call {
-	return &MockStorage_SaveTicker_Call{Call: _e.mock.On("SaveTicker", ticker)}
+// SearchMessagesByTicker is a helper method to define mock.On call
+//   - ticker Ticker
+//   - query string
+//   - pagination1 pagination.Pagination
+//   - opts ...func(*gorm.DB) *gorm.DB
+func (_e *MockStorage_Expecter) SearchMessagesByTicker(ticker interface{}, query interface{}, pagination1 interface{}, opts ...interface{}) *MockStorage_SearchMessagesByTicker_Call {
+	return &MockStorage_SearchMessagesByTicker_Call{Call: _e.mock.On("SearchMessagesByTicker",
+		append([]interface{}{ticker, query, pagination1}, opts...)...)}
 }
 
-func (_c *MockStorage_SaveTicker_Call) Run(run func(ticker *Ticker)) *MockStorage_SaveTicker_Call {
+func (_c *MockStorage_SearchMessagesByTicker_Call) Run(run func(ticker Ticker, query string, pagination1 pagination.Pagination, opts ...func(*gorm.DB) *gorm.DB)) *MockStorage_SearchMessagesByTicker_Call {
 	_c.Call.Run(func(args mock.Arguments) {
-		var arg0 *Ticker
+		var arg0 Ticker
 		if args[0] != nil {
-			arg0 = args[0].(*Ticker)
+			arg0 = args[0].(Ticker)
+		}
+		var arg1 string
+		if args[1] != nil {
+			arg1 = args[1].(string)
+		}
+		var arg2 pagination.Pagination
+		if args[2] != nil {
+			arg2 = args[2].(pagination.Pagination)
 		}
+		var arg3 []func(*gorm.DB) *gorm.DB
+		var variadicArgs []func(*gorm.DB) *gorm.DB
+		if len(args) > 3 {
+			variadicArgs = args[3].([]func(*gorm.DB) *gorm.DB)
+		}
+		arg3 = variadicArgs
 		run(
 			arg0,
+			arg1,
+			arg2,
+			arg3...,
 		)
 	})
 	return _c
 }
 
-func (_c *MockStorage_SaveTicker_Call) Return(err error) *MockStorage_SaveTicker_Call {
-	_c.Call.Return(err)
+func (_c *MockStorage_SearchMessagesByTicker_Call) Return(messages []Message, err error) *MockStorage_SearchMessagesByTicker_Call {
+	_c.Call.Return(messages, err)
 	return _c
 }
 
-func (_c *MockStorage_SaveTicker_Call) RunAndReturn(run func(ticker *Ticker) error) *MockStorage_SaveTicker_Call {
+func (_c *MockStorage_SearchMessagesByTicker_Call) RunAndReturn(run func(ticker Ticker, query string, pagination1 pagination.Pagination, opts ...func(*gorm.DB) *gorm.DB) ([]Message, error)) *MockStorage_SearchMessagesByTicker_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
-// SaveTickerWebsites provides a mock function for the type MockStorage
-func (_mock *MockStorage) SaveTickerWebsites(ticker *Ticker, websites []TickerWebsite) error {
-	ret := _mock.Called(ticker, websites)
+// TakeOIDCSession provides a mock function for the type MockStorage
+func (_mock *MockStorage) TakeOIDCSession(state string) (OIDCSession, error) {
+	ret := _mock.Called(state)
 
 	if len(ret) == 0 {
-		panic("no return value specified for SaveTickerWebsites")
+		panic("no return value specified for TakeOIDCSession")
 	}
 
-	var r0 error
-	if returnFunc, ok := ret.Get(0).(func(*Ticker, []TickerWebsite) error); ok {
-		r0 = returnFunc(ticker, websites)
+	var r0 OIDCSession
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(string) (OIDCSession, error)); ok {
+		return returnFunc(state)
+	}
+	if returnFunc, ok := ret.Get(0).(func(string) OIDCSession); ok {
+		r0 = returnFunc(state)
 	} else {
-		r0 = ret.Error(0)
+		r0 = ret.Get(0).(OIDCSession)
 	}
-	return r0
+	if returnFunc, ok := ret.Get(1).(func(string) error); ok {
+		r1 = returnFunc(state)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
 }
 
-// MockStorage_SaveTickerWebsites_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'SaveTickerWebsites'
-type MockStorage_SaveTickerWebsites_Call struct {
+// MockStorage_TakeOIDCSession_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'TakeOIDCSession'
+type MockStorage_TakeOIDCSession_Call struct {
 	*mock.Call
 }
 
-// SaveTickerWebsites is a helper method to define mock.On call
-//   - ticker *Ticker
-//   - websites []TickerWebsite
-func (_e *MockStorage_Expecter) SaveTickerWebsites(ticker interface{}, websites interface{}) *MockStorage_SaveTickerWebsites_Call {
-	return &MockStorage_SaveTickerWebsites_Call{Call: _e.mock.On("SaveTickerWebsites", ticker, websites)}
+// TakeOIDCSession is a helper method to define mock.On call
+//   - state string
+func (_e *MockStorage_Expecter) TakeOIDCSession(state interface{}) *MockStorage_TakeOIDCSession_Call {
+	return &MockStorage_TakeOIDCSession_Call{Call: _e.mock.On("TakeOIDCSession", state)}
 }
 
-func (_c *MockStorage_SaveTickerWebsites_Call) Run(run func(ticker *Ticker, websites []TickerWebsite)) *MockStorage_SaveTickerWebsites_Call {
+func (_c *MockStorage_TakeOIDCSession_Call) Run(run func(state string)) *MockStorage_TakeOIDCSession_Call {
 	_c.Call.Run(func(args mock.Arguments) {
-		var arg0 *Ticker
+		var arg0 string
 		if args[0] != nil {
-			arg0 = args[0].(*Ticker)
-		}
-		var arg1 []TickerWebsite
-		if args[1] != nil {
-			arg1 = args[1].([]TickerWebsite)
+			arg0 = args[0].(string)
 		}
 		run(
 			arg0,
-			arg1,
 		)
 	})
 	return _c
 }
 
-func (_c *MockStorage_SaveTickerWebsites_Call) Return(err error) *MockStorage_SaveTickerWebsites_Call {
-	_c.Call.Return(err)
+func (_c *MockStorage_TakeOIDCSession_Call) Return(oIDCSession OIDCSession, err error) *MockStorage_TakeOIDCSession_Call {
+	_c.Call.Return(oIDCSession, err)
 	return _c
 }
 
-func (_c *MockStorage_SaveTickerWebsites_Call) RunAndReturn(run func(ticker *Ticker, websites []TickerWebsite) error) *MockStorage_SaveTickerWebsites_Call {
+func (_c *MockStorage_TakeOIDCSession_Call) RunAndReturn(run func(state string) (OIDCSession, error)) *MockStorage_TakeOIDCSession_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
-// SaveUpload provides a mock function for the type MockStorage
-func (_mock *MockStorage) SaveUpload(upload *Upload) error {
-	ret := _mock.Called(upload)
+// TakeWebAuthnSession provides a mock function for the type MockStorage
+func (_mock *MockStorage) TakeWebAuthnSession(token string) (WebAuthnSession, error) {
+	ret := _mock.Called(token)
 
 	if len(ret) == 0 {
-		panic("no return value specified for SaveUpload")
+		panic("no return value specified for TakeWebAuthnSession")
 	}
 
-	var r0 error
-	if returnFunc, ok := ret.Get(0).(func(*Upload) error); ok {
-		r0 = returnFunc(upload)
+	var r0 WebAuthnSession
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(string) (WebAuthnSession, error)); ok {
+		return returnFunc(token)
+	}
+	if returnFunc, ok := ret.Get(0).(func(string) WebAuthnSession); ok {
+		r0 = returnFunc(token)
 	} else {
-		r0 = ret.Error(0)
+		r0 = ret.Get(0).(WebAuthnSession)
 	}
-	return r0
+	if returnFunc, ok := ret.Get(1).(func(string) error); ok {
+		r1 = returnFunc(token)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
 }
 
-// MockStorage_SaveUpload_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'SaveUpload'
-type MockStorage_SaveUpload_Call struct {
+// MockStorage_TakeWebAuthnSession_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'TakeWebAuthnSession'
+type MockStorage_TakeWebAuthnSession_Call struct {
 	*mock.Call
 }
 
-// SaveUpload is a helper method to define mock.On call
-//   - upload *Upload
-func (_e *MockStorage_Expecter) SaveUpload(upload interface{}) *MockStorage_SaveUpload_Call {
-	return &MockStorage_SaveUpload_Call{Call: _e.mock.On("SaveUpload", upload)}
+// TakeWebAuthnSession is a helper method to define mock.On call
+//   - token string
+func (_e *MockStorage_Expecter) TakeWebAuthnSession(token interface{}) *MockStorage_TakeWebAuthnSession_Call {
+	return &MockStorage_TakeWebAuthnSession_Call{Call: _e.mock.On("TakeWebAuthnSession", token)}
 }
 
-func (_c *MockStorage_SaveUpload_Call) Run(run func(upload *Upload)) *MockStorage_SaveUpload_Call {
+func (_c *MockStorage_TakeWebAuthnSession_Call) Run(run func(token string)) *MockStorage_TakeWebAuthnSession_Call {
 	_c.Call.Run(func(args mock.Arguments) {
-		var arg0 *Upload
+		var arg0 string
 		if args[0] != nil {
-			arg0 = args[0].(*Upload)
+			arg0 = args[0].(string)
 		}
 		run(
 			arg0,
@@ -2525,22 +7461,22 @@ func (_c *MockStorage_SaveUpload_Call) Run(run func(upload *Upload)) *MockStorag
 	return _c
 }
 
-func (_c *MockStorage_SaveUpload_Call) Return(err error) *MockStorage_SaveUpload_Call {
-	_c.Call.Return(err)
+func (_c *MockStorage_TakeWebAuthnSession_Call) Return(webAuthnSession WebAuthnSession, err error) *MockStorage_TakeWebAuthnSession_Call {
+	_c.Call.Return(webAuthnSession, err)
 	return _c
 }
 
-func (_c *MockStorage_SaveUpload_Call) RunAndReturn(run func(upload *Upload) error) *MockStorage_SaveUpload_Call {
+func (_c *MockStorage_TakeWebAuthnSession_Call) RunAndReturn(run func(token string) (WebAuthnSession, error)) *MockStorage_TakeWebAuthnSession_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
-// SaveUser provides a mock function for the type MockStorage
-func (_mock *MockStorage) SaveUser(user *User) error {
+// UnlinkTelegramAccount provides a mock function for the type MockStorage
+func (_mock *MockStorage) UnlinkTelegramAccount(user *User) error {
 	ret := _mock.Called(user)
 
 	if len(ret) == 0 {
-		panic("no return value specified for SaveUser")
+		panic("no return value specified for UnlinkTelegramAccount")
 	}
 
 	var r0 error
@@ -2552,18 +7488,18 @@ func (_mock *MockStorage) SaveUser(user *User) error {
 	return r0
 }
 
-// MockStorage_SaveUser_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'SaveUser'
-type MockStorage_SaveUser_Call struct {
+// MockStorage_UnlinkTelegramAccount_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'UnlinkTelegramAccount'
+type MockStorage_UnlinkTelegramAccount_Call struct {
 	*mock.Call
 }
 
-// SaveUser is a helper method to define mock.On call
+// UnlinkTelegramAccount is a helper method to define mock.On call
 //   - user *User
-func (_e *MockStorage_Expecter) SaveUser(user interface{}) *MockStorage_SaveUser_Call {
-	return &MockStorage_SaveUser_Call{Call: _e.mock.On("SaveUser", user)}
+func (_e *MockStorage_Expecter) UnlinkTelegramAccount(user interface{}) *MockStorage_UnlinkTelegramAccount_Call {
+	return &MockStorage_UnlinkTelegramAccount_Call{Call: _e.mock.On("UnlinkTelegramAccount", user)}
 }
 
-func (_c *MockStorage_SaveUser_Call) Run(run func(user *User)) *MockStorage_SaveUser_Call {
+func (_c *MockStorage_UnlinkTelegramAccount_Call) Run(run func(user *User)) *MockStorage_UnlinkTelegramAccount_Call {
 	_c.Call.Run(func(args mock.Arguments) {
 		var arg0 *User
 		if args[0] != nil {
@@ -2576,12 +7512,12 @@ func (_c *MockStorage_SaveUser_Call) Run(run func(user *User)) *MockStorage_Save
 	return _c
 }
 
-func (_c *MockStorage_SaveUser_Call) Return(err error) *MockStorage_SaveUser_Call {
+func (_c *MockStorage_UnlinkTelegramAccount_Call) Return(err error) *MockStorage_UnlinkTelegramAccount_Call {
 	_c.Call.Return(err)
 	return _c
 }
 
-func (_c *MockStorage_SaveUser_Call) RunAndReturn(run func(user *User) error) *MockStorage_SaveUser_Call {
+func (_c *MockStorage_UnlinkTelegramAccount_Call) RunAndReturn(run func(user *User) error) *MockStorage_UnlinkTelegramAccount_Call {
 	_c.Call.Return(run)
 	return _c
 }
@@ -2629,3 +7565,135 @@ func (_c *MockStorage_UploadPath_Call) RunAndReturn(run func() string) *MockStor
 	_c.Call.Return(run)
 	return _c
 }
+
+// UseRecoveryCode provides a mock function for the type MockStorage
+func (_mock *MockStorage) UseRecoveryCode(user User, code string) (bool, error) {
+	ret := _mock.Called(user, code)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UseRecoveryCode")
+	}
+
+	var r0 bool
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(User, string) (bool, error)); ok {
+		return returnFunc(user, code)
+	}
+	if returnFunc, ok := ret.Get(0).(func(User, string) bool); ok {
+		r0 = returnFunc(user, code)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+	if returnFunc, ok := ret.Get(1).(func(User, string) error); ok {
+		r1 = returnFunc(user, code)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockStorage_UseRecoveryCode_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'UseRecoveryCode'
+type MockStorage_UseRecoveryCode_Call struct {
+	*mock.Call
+}
+
+// UseRecoveryCode is a helper method to define mock.On call
+//   - user User
+//   - code string
+func (_e *MockStorage_Expecter) UseRecoveryCode(user interface{}, code interface{}) *MockStorage_UseRecoveryCode_Call {
+	return &MockStorage_UseRecoveryCode_Call{Call: _e.mock.On("UseRecoveryCode", user, code)}
+}
+
+func (_c *MockStorage_UseRecoveryCode_Call) Run(run func(user User, code string)) *MockStorage_UseRecoveryCode_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 User
+		if args[0] != nil {
+			arg0 = args[0].(User)
+		}
+		var arg1 string
+		if args[1] != nil {
+			arg1 = args[1].(string)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *MockStorage_UseRecoveryCode_Call) Return(b bool, err error) *MockStorage_UseRecoveryCode_Call {
+	_c.Call.Return(b, err)
+	return _c
+}
+
+func (_c *MockStorage_UseRecoveryCode_Call) RunAndReturn(run func(user User, code string) (bool, error)) *MockStorage_UseRecoveryCode_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// UseTOTPStep provides a mock function for the type MockStorage
+func (_mock *MockStorage) UseTOTPStep(user *User, step int64) (bool, error) {
+	ret := _mock.Called(user, step)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UseTOTPStep")
+	}
+
+	var r0 bool
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(*User, int64) (bool, error)); ok {
+		return returnFunc(user, step)
+	}
+	if returnFunc, ok := ret.Get(0).(func(*User, int64) bool); ok {
+		r0 = returnFunc(user, step)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+	if returnFunc, ok := ret.Get(1).(func(*User, int64) error); ok {
+		r1 = returnFunc(user, step)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockStorage_UseTOTPStep_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'UseTOTPStep'
+type MockStorage_UseTOTPStep_Call struct {
+	*mock.Call
+}
+
+// UseTOTPStep is a helper method to define mock.On call
+//   - user *User
+//   - step int64
+func (_e *MockStorage_Expecter) UseTOTPStep(user interface{}, step interface{}) *MockStorage_UseTOTPStep_Call {
+	return &MockStorage_UseTOTPStep_Call{Call: _e.mock.On("UseTOTPStep", user, step)}
+}
+
+func (_c *MockStorage_UseTOTPStep_Call) Run(run func(user *User, step int64)) *MockStorage_UseTOTPStep_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 *User
+		if args[0] != nil {
+			arg0 = args[0].(*User)
+		}
+		var arg1 int64
+		if args[1] != nil {
+			arg1 = args[1].(int64)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *MockStorage_UseTOTPStep_Call) Return(b bool, err error) *MockStorage_UseTOTPStep_Call {
+	_c.Call.Return(b, err)
+	return _c
+}
+
+func (_c *MockStorage_UseTOTPStep_Call) RunAndReturn(run func(user *User, step int64) (bool, error)) *MockStorage_UseTOTPStep_Call {
+	_c.Call.Return(run)
+	return _c
+}
@@ -0,0 +1,80 @@
+package storage
+
+import "time"
+
+const (
+	OutboxPending = "pending"
+	OutboxSent    = "sent"
+	OutboxFailed  = "failed"
+
+	OutboxSend   = "send"
+	OutboxEdit   = "edit"
+	OutboxDelete = "delete"
+)
+
+// OutboxJob is the delivery of a message to one bridge. Jobs are created
+// together with the message and worked off in the background, so a slow or
+// unreachable service neither blocks the editor nor loses the message.
+type OutboxJob struct {
+	ID        int `gorm:"primaryKey"`
+	CreatedAt time.Time
+	UpdatedAt time.Time
+	TickerID  int    `gorm:"index"`
+	MessageID int    `gorm:"index"`
+	Bridge    string `gorm:"size:50"`
+	// Action is OutboxSend for the first delivery, OutboxEdit for an edit,
+	// which waits until the message was sent to the bridge, or OutboxDelete
+	// for a deletion, which waits until no send or edit is pending.
+	Action        string `gorm:"size:20;default:send"`
+	Status        string `gorm:"default:pending;index:idx_outbox_due"`
+	Attempts      int
+	LastError     string
+	NextAttemptAt time.Time `gorm:"index:idx_outbox_due"`
+}
+
+func NewOutboxJob(message Message, bridge string) OutboxJob {
+	return OutboxJob{
+		TickerID:      message.TickerID,
+		MessageID:     message.ID,
+		Bridge:        bridge,
+		Action:        OutboxSend,
+		Status:        OutboxPending,
+		NextAttemptAt: time.Now(),
+	}
+}
+
+func NewOutboxEditJob(message Message, bridge string) OutboxJob {
+	job := NewOutboxJob(message, bridge)
+	job.Action = OutboxEdit
+
+	return job
+}
+
+func NewOutboxDeleteJob(message Message, bridge string) OutboxJob {
+	job := NewOutboxJob(message, bridge)
+	job.Action = OutboxDelete
+
+	return job
+}
+
+// IsEdit reports whether the job edits the message. Jobs from before edits
+// went through the outbox have no action and are sends.
+func (j *OutboxJob) IsEdit() bool {
+	return j.Action == OutboxEdit
+}
+
+// IsDelete reports whether the job deletes the message from the bridge.
+func (j *OutboxJob) IsDelete() bool {
+	return j.Action == OutboxDelete
+}
+
+// messageMetaColumns maps the bridge names to the columns of the message that
+// hold what the bridge needs to edit or delete the message later on.
+var messageMetaColumns = map[string]string{
+	"telegram":    "telegram",
+	"mastodon":    "mastodon",
+	"bluesky":     "bluesky",
+	"signalGroup": "signal_group",
+	"matrix":      "matrix",
+	"webhook":     "webhook",
+}
@@ -0,0 +1,91 @@
+package storage
+
+import (
+	"fmt"
+	"strings"
+
+	"gorm.io/gorm"
+)
+
+// messagesSearchTable is the FTS5 index of the message texts on SQLite. It is
+// an external content table, kept in sync with the messages by triggers.
+const messagesSearchTable = "messages_fts"
+
+// setupSearchIndex creates the full-text index for the messages. SQLite only
+// supports it when built with the sqlite_fts5 tag; without it, searching falls
+// back to a plain LIKE comparison.
+func setupSearchIndex(db *gorm.DB) error {
+	switch db.Dialector.Name() {
+	case "sqlite":
+		if db.Migrator().HasTable(messagesSearchTable) {
+			return nil
+		}
+
+		err := db.Exec("CREATE VIRTUAL TABLE " + messagesSearchTable + " USING fts5(text, content='messages', content_rowid='id')").Error
+		if err != nil {
+			log.WithError(err).Warn("full-text search is not available, the binary was built without the sqlite_fts5 tag")
+			return nil
+		}
+
+		statements := []string{
+			"CREATE TRIGGER IF NOT EXISTS messages_fts_ai AFTER INSERT ON messages BEGIN INSERT INTO messages_fts(rowid, text) VALUES (new.id, new.text); END",
+			"CREATE TRIGGER IF NOT EXISTS messages_fts_ad AFTER DELETE ON messages BEGIN INSERT INTO messages_fts(messages_fts, rowid, text) VALUES ('delete', old.id, old.text); END",
+			"CREATE TRIGGER IF NOT EXISTS messages_fts_au AFTER UPDATE OF text ON messages BEGIN INSERT INTO messages_fts(messages_fts, rowid, text) VALUES ('delete', old.id, old.text); INSERT INTO messages_fts(rowid, text) VALUES (new.id, new.text); END",
+			"INSERT INTO messages_fts(messages_fts) VALUES ('rebuild')",
+		}
+		for _, statement := range statements {
+			if err := db.Exec(statement).Error; err != nil {
+				return err
+			}
+		}
+	case "mysql":
+		if db.Migrator().HasIndex(&Message{}, "idx_messages_text_fulltext") {
+			return nil
+		}
+
+		return db.Exec("CREATE FULLTEXT INDEX idx_messages_text_fulltext ON messages (text)").Error
+	case "postgres":
+		return db.Exec("CREATE INDEX IF NOT EXISTS idx_messages_text_search ON messages USING GIN (to_tsvector('simple', text))").Error
+	}
+
+	return nil
+}
+
+// searchCondition returns the condition to match the text of the messages
+// against the search terms, using the full-text index of the database.
+// Every term has to match, and on SQLite and MySQL a term also matches words
+// starting with it.
+func searchCondition(db *gorm.DB, terms []string) (string, []interface{}) {
+	switch db.Dialector.Name() {
+	case "sqlite":
+		if db.Migrator().HasTable(messagesSearchTable) {
+			quoted := make([]string, 0, len(terms))
+			for _, term := range terms {
+				quoted = append(quoted, fmt.Sprintf(`"%s"*`, strings.ReplaceAll(term, `"`, `""`)))
+			}
+
+			return "id IN (SELECT rowid FROM " + messagesSearchTable + " WHERE " + messagesSearchTable + " MATCH ?)", []interface{}{strings.Join(quoted, " ")}
+		}
+	case "mysql":
+		words := make([]string, 0, len(terms))
+		for _, term := range terms {
+			term = strings.Trim(term, `+-<>()~*"@`)
+			if term != "" {
+				words = append(words, "+"+term+"*")
+			}
+		}
+
+		return "MATCH (text) AGAINST (? IN BOOLEAN MODE)", []interface{}{strings.Join(words, " ")}
+	case "postgres":
+		return "to_tsvector('simple', text) @@ plainto_tsquery('simple', ?)", []interface{}{strings.Join(terms, " ")}
+	}
+
+	conditions := make([]string, 0, len(terms))
+	args := make([]interface{}, 0, len(terms))
+	for _, term := range terms {
+		conditions = append(conditions, "text LIKE ?")
+		args = append(args, "%"+term+"%")
+	}
+
+	return strings.Join(conditions, " AND "), args
+}
@@ -14,6 +14,8 @@ const (
 	SettingDefaultRefreshInterval int = 10000
 	SettingTelegramName               = `telegram_settings`
 	SettingSignalGroupName            = `signal_group_settings`
+	SettingWebPushName                = `webpush_settings`
+	SettingSecurityName               = `security_settings`
 )
 
 type Setting struct {
@@ -37,6 +39,14 @@ type InactiveSettings struct {
 type TelegramSettings struct {
 	Token       string `json:"token"`
 	BotUsername string `json:"botUsername"`
+	// Inbound lets users with a linked Telegram account post to their tickers
+	// by sending messages to the bot.
+	Inbound bool `json:"inbound"`
+}
+
+// InboundEnabled reports whether the bot receives messages to post.
+func (s TelegramSettings) InboundEnabled() bool {
+	return s.Token != "" && s.Inbound
 }
 
 func DefaultInactiveSettings() InactiveSettings {
@@ -61,6 +71,9 @@ type SignalGroupSettings struct {
 	ApiUrl  string `json:"apiUrl"`
 	Account string `json:"account"`
 	Avatar  string `json:"avatar"`
+	// Inbound posts the messages the account receives from the allow-listed
+	// numbers of a ticker to that ticker.
+	Inbound bool `json:"inbound"`
 }
 
 // Enabled returns true if required API URL and account are set.
@@ -68,6 +81,11 @@ func (s *SignalGroupSettings) Enabled() bool {
 	return s.ApiUrl != "" && s.Account != ""
 }
 
+// InboundEnabled reports whether the account receives messages to post.
+func (s *SignalGroupSettings) InboundEnabled() bool {
+	return s.Enabled() && s.Inbound
+}
+
 func DefaultSignalGroupSettings() SignalGroupSettings {
 	return SignalGroupSettings{
 		ApiUrl:  "",
@@ -75,3 +93,22 @@ func DefaultSignalGroupSettings() SignalGroupSettings {
 		Avatar:  "",
 	}
 }
+
+// WebPushSettings hold the VAPID key pair identifying the instance to the push
+// services of the browsers. Subscriptions are bound to the public key, they
+// stop working when the keys change.
+type WebPushSettings struct {
+	PublicKey  string `json:"publicKey"`
+	PrivateKey string `json:"privateKey"`
+}
+
+func (s *WebPushSettings) Enabled() bool {
+	return s.PublicKey != "" && s.PrivateKey != ""
+}
+
+// SecuritySettings are the rules for the logins to the admin interface.
+type SecuritySettings struct {
+	// RequireTwoFactor keeps users without a second factor from everything
+	// but setting one up.
+	RequireTwoFactor bool `json:"requireTwoFactor"`
+}
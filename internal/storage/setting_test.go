@@ -72,3 +72,20 @@ func TestSignalGroupSettingsEnabled(t *testing.T) {
 		assert.True(t, settings.Enabled())
 	})
 }
+
+func TestSignalGroupSettingsInboundEnabled(t *testing.T) {
+	t.Run("returns false when inbound is off", func(t *testing.T) {
+		settings := SignalGroupSettings{ApiUrl: "http://localhost:8080", Account: "+491234567890"}
+		assert.False(t, settings.InboundEnabled())
+	})
+
+	t.Run("returns false when the account is missing", func(t *testing.T) {
+		settings := SignalGroupSettings{ApiUrl: "http://localhost:8080", Inbound: true}
+		assert.False(t, settings.InboundEnabled())
+	})
+
+	t.Run("returns true when inbound is on", func(t *testing.T) {
+		settings := SignalGroupSettings{ApiUrl: "http://localhost:8080", Account: "+491234567890", Inbound: true}
+		assert.True(t, settings.InboundEnabled())
+	})
+}
@@ -4,6 +4,8 @@ import (
 	"encoding/json"
 	"fmt"
 	"os"
+	"strings"
+	"time"
 
 	"github.com/systemli/ticker/internal/api/pagination"
 	"gorm.io/gorm"
@@ -21,6 +23,10 @@ type SqlStorage struct {
 }
 
 func NewSqlStorage(db *gorm.DB, uploadPath string) *SqlStorage {
+	if err := setupJoinTables(db); err != nil {
+		log.WithError(err).Error("failed to set up join tables")
+	}
+
 	return &SqlStorage{
 		DB:         db,
 		uploadPath: uploadPath,
@@ -81,6 +87,14 @@ func (s *SqlStorage) FindUserByEmail(email string, opts ...func(*gorm.DB) *gorm.
 	return user, err
 }
 
+func (s *SqlStorage) FindUserByTelegramID(telegramID int64, opts ...func(*gorm.DB) *gorm.DB) (User, error) {
+	var user User
+	db := s.prepareDb(opts...)
+	err := db.First(&user, "telegram_id = ?", telegramID).Error
+
+	return user, err
+}
+
 func (s *SqlStorage) SaveUser(user *User) error {
 	if user.ID == 0 {
 		return s.DB.Create(user).Error
@@ -95,7 +109,389 @@ func (s *SqlStorage) SaveUser(user *User) error {
 	return s.DB.Session(&gorm.Session{FullSaveAssociations: true}).Model(user).Updates(user.AsMap()).Error
 }
 
+// SaveTelegramLink stores the code for the user, replacing an earlier one.
+func (s *SqlStorage) SaveTelegramLink(link *TelegramLink) error {
+	return s.DB.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "user_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"created_at", "code"}),
+	}).Create(link).Error
+}
+
+func (s *SqlStorage) FindTelegramLinkByCode(code string) (TelegramLink, error) {
+	var link TelegramLink
+	err := s.DB.First(&link, "code = ?", code).Error
+
+	return link, err
+}
+
+// LinkTelegramAccount links the Telegram account to the user of the code and
+// uses the code up. An account can only be linked to one user, so it is taken
+// away from a user it was linked to before. The columns are updated directly,
+// as SaveUser would replace the tickers of the user.
+func (s *SqlStorage) LinkTelegramAccount(link TelegramLink, telegramID int64) error {
+	return s.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&User{}).Where("telegram_id = ?", telegramID).UpdateColumns(map[string]interface{}{"telegram_id": nil, "telegram_ticker_id": 0}).Error; err != nil {
+			return err
+		}
+
+		if err := tx.Model(&User{}).Where("id = ?", link.UserID).UpdateColumns(map[string]interface{}{"telegram_id": telegramID, "telegram_ticker_id": 0}).Error; err != nil {
+			return err
+		}
+
+		return tx.Delete(&TelegramLink{}, link.ID).Error
+	})
+}
+
+func (s *SqlStorage) UnlinkTelegramAccount(user *User) error {
+	user.TelegramID = nil
+	user.TelegramTickerID = 0
+
+	return s.DB.Model(&User{}).Where("id = ?", user.ID).UpdateColumns(map[string]interface{}{"telegram_id": nil, "telegram_ticker_id": 0}).Error
+}
+
+// SaveTelegramTicker stores the ticker the user chose in the bot.
+func (s *SqlStorage) SaveTelegramTicker(user *User) error {
+	return s.DB.Model(&User{}).Where("id = ?", user.ID).UpdateColumn("telegram_ticker_id", user.TelegramTickerID).Error
+}
+
+// SaveTOTPSecret stores the secret of a new enrollment. It takes effect with
+// EnableTOTP, an enrollment in progress does not change the login.
+func (s *SqlStorage) SaveTOTPSecret(user *User) error {
+	user.TOTPEnabled = false
+	user.TOTPLastStep = 0
+
+	return s.DB.Model(&User{}).Where("id = ?", user.ID).UpdateColumns(map[string]interface{}{"totp_secret": user.TOTPSecret, "totp_enabled": false, "totp_last_step": 0}).Error
+}
+
+// EnableTOTP turns the second factor on with the code of the given time step
+// used, and replaces the recovery codes of the user.
+func (s *SqlStorage) EnableTOTP(user *User, step int64, codes []RecoveryCode) error {
+	err := s.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&User{}).Where("id = ?", user.ID).UpdateColumns(map[string]interface{}{"totp_enabled": true, "totp_last_step": step}).Error; err != nil {
+			return err
+		}
+
+		return saveRecoveryCodes(tx, *user, codes)
+	})
+	if err != nil {
+		return err
+	}
+
+	user.TOTPEnabled = true
+	user.TOTPLastStep = step
+
+	return nil
+}
+
+// DisableTOTP turns the second factor off and removes the secret together
+// with the recovery codes.
+func (s *SqlStorage) DisableTOTP(user *User) error {
+	err := s.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&User{}).Where("id = ?", user.ID).UpdateColumns(map[string]interface{}{"totp_secret": "", "totp_enabled": false, "totp_last_step": 0}).Error; err != nil {
+			return err
+		}
+
+		return tx.Where("user_id = ?", user.ID).Delete(&RecoveryCode{}).Error
+	})
+	if err != nil {
+		return err
+	}
+
+	user.TOTPSecret = ""
+	user.TOTPEnabled = false
+	user.TOTPLastStep = 0
+
+	return nil
+}
+
+// UseTOTPStep marks the time step as used. It reports false when a code of
+// this or a later step was used before, so a code cannot be replayed.
+func (s *SqlStorage) UseTOTPStep(user *User, step int64) (bool, error) {
+	result := s.DB.Model(&User{}).Where("id = ? AND totp_last_step < ?", user.ID, step).UpdateColumn("totp_last_step", step)
+	if result.Error != nil {
+		return false, result.Error
+	}
+	if result.RowsAffected == 0 {
+		return false, nil
+	}
+
+	user.TOTPLastStep = step
+
+	return true, nil
+}
+
+// AddTOTPAttempt counts a code entered by the user and returns the number of
+// codes since the last correct one. The count is raised in the database, so
+// parallel requests cannot slip past it.
+func (s *SqlStorage) AddTOTPAttempt(user *User) (int, error) {
+	err := s.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&User{}).Where("id = ?", user.ID).UpdateColumn("totp_attempts", gorm.Expr("totp_attempts + 1")).Error; err != nil {
+			return err
+		}
+
+		return tx.Model(&User{}).Where("id = ?", user.ID).Pluck("totp_attempts", &user.TOTPAttempts).Error
+	})
+
+	return user.TOTPAttempts, err
+}
+
+// SaveTOTPLock stores the count of codes and the time until which the codes
+// of the user are refused.
+func (s *SqlStorage) SaveTOTPLock(user *User) error {
+	return s.DB.Model(&User{}).Where("id = ?", user.ID).UpdateColumns(map[string]interface{}{"totp_attempts": user.TOTPAttempts, "totp_locked_until": user.TOTPLockedUntil}).Error
+}
+
+// SaveRecoveryCodes replaces the recovery codes of the user.
+func (s *SqlStorage) SaveRecoveryCodes(user User, codes []RecoveryCode) error {
+	return s.DB.Transaction(func(tx *gorm.DB) error {
+		return saveRecoveryCodes(tx, user, codes)
+	})
+}
+
+func saveRecoveryCodes(tx *gorm.DB, user User, codes []RecoveryCode) error {
+	if err := tx.Where("user_id = ?", user.ID).Delete(&RecoveryCode{}).Error; err != nil {
+		return err
+	}
+	if len(codes) == 0 {
+		return nil
+	}
+
+	for i := range codes {
+		codes[i].UserID = user.ID
+	}
+
+	return tx.Create(&codes).Error
+}
+
+// UseRecoveryCode removes the code of the user. It reports false when the user
+// has no such code.
+func (s *SqlStorage) UseRecoveryCode(user User, code string) (bool, error) {
+	result := s.DB.Where("user_id = ? AND hash = ?", user.ID, HashRecoveryCode(code)).Delete(&RecoveryCode{})
+	if result.Error != nil {
+		return false, result.Error
+	}
+
+	return result.RowsAffected > 0, nil
+}
+
+func (s *SqlStorage) CountRecoveryCodes(user User) (int64, error) {
+	var count int64
+	err := s.DB.Model(&RecoveryCode{}).Where("user_id = ?", user.ID).Count(&count).Error
+
+	return count, err
+}
+
+// SaveWebAuthnHandle stores the handle the passkeys of the user are
+// registered with.
+func (s *SqlStorage) SaveWebAuthnHandle(user *User) error {
+	return s.DB.Model(&User{}).Where("id = ?", user.ID).UpdateColumn("web_authn_handle", user.WebAuthnHandle).Error
+}
+
+func (s *SqlStorage) FindUserByWebAuthnHandle(handle string, opts ...func(*gorm.DB) *gorm.DB) (User, error) {
+	var user User
+	if handle == "" {
+		return user, gorm.ErrRecordNotFound
+	}
+
+	db := s.prepareDb(opts...)
+	err := db.First(&user, "web_authn_handle = ?", handle).Error
+
+	return user, err
+}
+
+func (s *SqlStorage) FindWebAuthnCredentials(user User) ([]WebAuthnCredential, error) {
+	credentials := make([]WebAuthnCredential, 0)
+	err := s.DB.Where("user_id = ?", user.ID).Order("id ASC").Find(&credentials).Error
+
+	return credentials, err
+}
+
+func (s *SqlStorage) FindWebAuthnCredentialByID(user User, id int) (WebAuthnCredential, error) {
+	var credential WebAuthnCredential
+	err := s.DB.Where("user_id = ?", user.ID).First(&credential, id).Error
+
+	return credential, err
+}
+
+func (s *SqlStorage) CountWebAuthnCredentials(user User) (int64, error) {
+	var count int64
+	err := s.DB.Model(&WebAuthnCredential{}).Where("user_id = ?", user.ID).Count(&count).Error
+
+	return count, err
+}
+
+func (s *SqlStorage) SaveWebAuthnCredential(credential *WebAuthnCredential) error {
+	return s.DB.Save(credential).Error
+}
+
+func (s *SqlStorage) DeleteWebAuthnCredential(credential WebAuthnCredential) error {
+	return s.DB.Delete(&credential).Error
+}
+
+// DeleteWebAuthnCredentials removes all passkeys of the user.
+func (s *SqlStorage) DeleteWebAuthnCredentials(user User) error {
+	return s.DB.Where("user_id = ?", user.ID).Delete(&WebAuthnCredential{}).Error
+}
+
+// SaveWebAuthnSession stores a new session, and removes those nobody answered
+// in time.
+func (s *SqlStorage) SaveWebAuthnSession(session *WebAuthnSession) error {
+	err := s.DB.Where("created_at < ?", time.Now().Add(-WebAuthnSessionTTL)).Delete(&WebAuthnSession{}).Error
+	if err != nil {
+		log.WithError(err).Error("failed to delete expired webauthn sessions")
+	}
+
+	return s.DB.Create(session).Error
+}
+
+// TakeWebAuthnSession returns the session and removes it, so the answer to a
+// challenge is accepted once. Expired sessions are not returned.
+func (s *SqlStorage) TakeWebAuthnSession(token string) (WebAuthnSession, error) {
+	var session WebAuthnSession
+	err := s.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.First(&session, "token = ?", token).Error; err != nil {
+			return err
+		}
+
+		result := tx.Delete(&WebAuthnSession{}, session.ID)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return gorm.ErrRecordNotFound
+		}
+
+		return nil
+	})
+	if err != nil {
+		return WebAuthnSession{}, err
+	}
+	if session.Expired() {
+		return WebAuthnSession{}, gorm.ErrRecordNotFound
+	}
+
+	return session, nil
+}
+
+// SaveOIDCSubject links the user to the account at the identity provider.
+func (s *SqlStorage) SaveOIDCSubject(user *User) error {
+	return s.DB.Model(&User{}).Where("id = ?", user.ID).UpdateColumn("oidc_subject", user.OIDCSubject).Error
+}
+
+func (s *SqlStorage) FindUserByOIDCSubject(subject string, opts ...func(*gorm.DB) *gorm.DB) (User, error) {
+	var user User
+	if subject == "" {
+		return user, gorm.ErrRecordNotFound
+	}
+
+	db := s.prepareDb(opts...)
+	err := db.First(&user, "oidc_subject = ?", subject).Error
+
+	return user, err
+}
+
+// SaveOIDCSession stores a new session, and removes those of logins which
+// were never finished.
+func (s *SqlStorage) SaveOIDCSession(session *OIDCSession) error {
+	err := s.DB.Where("created_at < ?", time.Now().Add(-OIDCSessionTTL)).Delete(&OIDCSession{}).Error
+	if err != nil {
+		log.WithError(err).Error("failed to delete expired oidc sessions")
+	}
+
+	return s.DB.Create(session).Error
+}
+
+// TakeOIDCSession returns the session of the state and removes it, so a
+// redirect from the identity provider is accepted once. Expired sessions are
+// not returned.
+func (s *SqlStorage) TakeOIDCSession(state string) (OIDCSession, error) {
+	var session OIDCSession
+	err := s.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.First(&session, "state = ?", state).Error; err != nil {
+			return err
+		}
+
+		result := tx.Delete(&OIDCSession{}, session.ID)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return gorm.ErrRecordNotFound
+		}
+
+		return nil
+	})
+	if err != nil {
+		return OIDCSession{}, err
+	}
+	if session.Expired() {
+		return OIDCSession{}, gorm.ErrRecordNotFound
+	}
+
+	return session, nil
+}
+
+func (s *SqlStorage) FindAPITokens(user User) ([]APIToken, error) {
+	tokens := make([]APIToken, 0)
+	err := s.DB.Where("user_id = ?", user.ID).Order("id ASC").Find(&tokens).Error
+
+	return tokens, err
+}
+
+func (s *SqlStorage) FindAPITokenByID(user User, id int) (APIToken, error) {
+	var token APIToken
+	err := s.DB.Where("user_id = ?", user.ID).First(&token, id).Error
+
+	return token, err
+}
+
+func (s *SqlStorage) FindAPITokenByHash(hash string) (APIToken, error) {
+	var token APIToken
+	err := s.DB.First(&token, "hash = ?", hash).Error
+
+	return token, err
+}
+
+func (s *SqlStorage) SaveAPIToken(token *APIToken) error {
+	return s.DB.Save(token).Error
+}
+
+// SaveAPITokenLastUsed stores when the token was last used, without touching
+// the rest of it.
+func (s *SqlStorage) SaveAPITokenLastUsed(token *APIToken) error {
+	return s.DB.Model(&APIToken{}).Where("id = ?", token.ID).UpdateColumn("last_used", token.LastUsed).Error
+}
+
+func (s *SqlStorage) DeleteAPIToken(token APIToken) error {
+	return s.DB.Delete(&token).Error
+}
+
 func (s *SqlStorage) DeleteUser(user User) error {
+	err := s.DB.Where("user_id = ?", user.ID).Delete(&TickerUser{}).Error
+	if err != nil {
+		log.WithError(err).WithField("user_id", user.ID).Error("failed to delete user memberships")
+	}
+
+	err = s.DB.Where("user_id = ?", user.ID).Delete(&TelegramLink{}).Error
+	if err != nil {
+		log.WithError(err).WithField("user_id", user.ID).Error("failed to delete telegram link")
+	}
+
+	err = s.DB.Where("user_id = ?", user.ID).Delete(&RecoveryCode{}).Error
+	if err != nil {
+		log.WithError(err).WithField("user_id", user.ID).Error("failed to delete recovery codes")
+	}
+
+	err = s.DB.Where("user_id = ?", user.ID).Delete(&WebAuthnCredential{}).Error
+	if err != nil {
+		log.WithError(err).WithField("user_id", user.ID).Error("failed to delete passkeys")
+	}
+
+	err = s.DB.Where("user_id = ?", user.ID).Delete(&APIToken{}).Error
+	if err != nil {
+		log.WithError(err).WithField("user_id", user.ID).Error("failed to delete api tokens")
+	}
+
 	return s.DB.Delete(&user).Error
 }
 
@@ -117,6 +513,52 @@ func (s *SqlStorage) AddTickerUser(ticker *Ticker, user *User) error {
 	return err
 }
 
+func (s *SqlStorage) FindTickerUser(ticker Ticker, user User) (TickerUser, error) {
+	var tickerUser TickerUser
+	err := s.DB.First(&tickerUser, "ticker_id = ? AND user_id = ?", ticker.ID, user.ID).Error
+
+	return tickerUser, err
+}
+
+func (s *SqlStorage) FindTickerUsers(ticker Ticker) ([]TickerUser, error) {
+	tickerUsers := make([]TickerUser, 0)
+	err := s.DB.Where(EqualTickerID, ticker.ID).Find(&tickerUsers).Error
+
+	return tickerUsers, err
+}
+
+// SaveTickerUsers replaces the members of a ticker. Members which are kept
+// get the role from the given list.
+func (s *SqlStorage) SaveTickerUsers(ticker *Ticker, tickerUsers []TickerUser) error {
+	return s.DB.Transaction(func(tx *gorm.DB) error {
+		userIDs := make([]int, 0, len(tickerUsers))
+		for _, tickerUser := range tickerUsers {
+			userIDs = append(userIDs, tickerUser.UserID)
+		}
+
+		query := tx.Where(EqualTickerID, ticker.ID)
+		if len(userIDs) > 0 {
+			query = query.Where("user_id NOT IN ?", userIDs)
+		}
+		if err := query.Delete(&TickerUser{}).Error; err != nil {
+			return err
+		}
+
+		for _, tickerUser := range tickerUsers {
+			tickerUser.TickerID = ticker.ID
+			err := tx.Clauses(clause.OnConflict{
+				Columns:   []clause.Column{{Name: "ticker_id"}, {Name: "user_id"}},
+				DoUpdates: clause.AssignmentColumns([]string{"role"}),
+			}).Create(&tickerUser).Error
+			if err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+}
+
 func (s *SqlStorage) FindTickersByUser(user User, filter TickerFilter, opts ...func(*gorm.DB) *gorm.DB) ([]Ticker, error) {
 	tickers := make([]Ticker, 0)
 	db := s.prepareDb(opts...)
@@ -179,6 +621,19 @@ func (s *SqlStorage) FindTickerByOrigin(origin string, opts ...func(*gorm.DB) *g
 	return ticker, err
 }
 
+// FindTickerByMailboxAddress returns the ticker with the active mailbox at the
+// address. Addresses are compared in lower case.
+func (s *SqlStorage) FindTickerByMailboxAddress(address string, opts ...func(*gorm.DB) *gorm.DB) (Ticker, error) {
+	var ticker Ticker
+	db := s.prepareDb(opts...)
+
+	err := db.Joins("JOIN ticker_mailboxes ON tickers.id = ticker_mailboxes.ticker_id").
+		Where("ticker_mailboxes.address = ? AND ticker_mailboxes.active = ?", strings.ToLower(address), true).
+		First(&ticker).Error
+
+	return ticker, err
+}
+
 func (s *SqlStorage) FindTickerByID(id int, opts ...func(*gorm.DB) *gorm.DB) (Ticker, error) {
 	var ticker Ticker
 	db := s.prepareDb(opts...)
@@ -202,12 +657,18 @@ func (s *SqlStorage) SaveTicker(ticker *Ticker) error {
 	return s.DB.Session(&gorm.Session{FullSaveAssociations: true}).Model(ticker).Updates(ticker.AsMap()).Error
 }
 
-// DeleteTicker deletes a ticker and all associated data.
+// DeleteTicker deletes a ticker and all associated data. Unlike a reset, this
+// includes the history of its messages.
 func (s *SqlStorage) DeleteTicker(ticker *Ticker) error {
 	if err := s.deleteTickerAssociations(ticker); err != nil {
 		return err
 	}
 
+	if err := s.DB.Where(EqualTickerID, ticker.ID).Delete(&MessageRevision{}).Error; err != nil {
+		log.WithError(err).WithField("ticker_id", ticker.ID).Error("failed to delete message revisions")
+		return err
+	}
+
 	return s.DB.Delete(&ticker).Error
 }
 
@@ -257,6 +718,98 @@ func (s *SqlStorage) DeleteTickerWebsites(ticker *Ticker) error {
 	return nil
 }
 
+// SaveTickerWebhooks replaces the webhooks of the ticker. Webhooks with an ID
+// are updated, the others are created, and webhooks missing from the list are
+// deleted.
+func (s *SqlStorage) SaveTickerWebhooks(ticker *Ticker, webhooks []TickerWebhook) error {
+	err := s.DB.Transaction(func(tx *gorm.DB) error {
+		ids := make([]int, 0, len(webhooks))
+		for _, webhook := range webhooks {
+			if webhook.ID != 0 {
+				ids = append(ids, webhook.ID)
+			}
+		}
+
+		query := tx.Where(EqualTickerID, ticker.ID)
+		if len(ids) > 0 {
+			query = query.Where("id NOT IN ?", ids)
+		}
+		if err := query.Delete(&TickerWebhook{}).Error; err != nil {
+			return err
+		}
+
+		for i := range webhooks {
+			webhooks[i].TickerID = ticker.ID
+			if err := tx.Save(&webhooks[i]).Error; err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	ticker.Webhooks = webhooks
+
+	return nil
+}
+
+func (s *SqlStorage) DeleteTickerWebhooks(ticker *Ticker) error {
+	ticker.Webhooks = make([]TickerWebhook, 0)
+
+	return s.DB.Delete(TickerWebhook{}, EqualTickerID, ticker.ID).Error
+}
+
+func (s *SqlStorage) SaveTickerSignalSenders(ticker *Ticker, senders []TickerSignalSender) error {
+	err := s.DB.Transaction(func(tx *gorm.DB) error {
+		ids := make([]int, 0, len(senders))
+		for _, sender := range senders {
+			if sender.ID != 0 {
+				ids = append(ids, sender.ID)
+			}
+		}
+
+		query := tx.Where(EqualTickerID, ticker.ID)
+		if len(ids) > 0 {
+			query = query.Where("id NOT IN ?", ids)
+		}
+		if err := query.Delete(&TickerSignalSender{}).Error; err != nil {
+			return err
+		}
+
+		for i := range senders {
+			senders[i].TickerID = ticker.ID
+			if err := tx.Save(&senders[i]).Error; err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	ticker.SignalSenders = senders
+
+	return nil
+}
+
+func (s *SqlStorage) DeleteTickerSignalSenders(ticker *Ticker) error {
+	ticker.SignalSenders = make([]TickerSignalSender, 0)
+
+	return s.DB.Delete(TickerSignalSender{}, EqualTickerID, ticker.ID).Error
+}
+
+func (s *SqlStorage) FindTickerSignalSenderByNumber(number string) (TickerSignalSender, error) {
+	var sender TickerSignalSender
+	err := s.DB.First(&sender, "number = ?", number).Error
+
+	return sender, err
+}
+
 func (s *SqlStorage) ResetTicker(ticker *Ticker) error {
 	if err := s.deleteTickerAssociations(ticker); err != nil {
 		return err
@@ -269,80 +822,306 @@ func (s *SqlStorage) ResetTicker(ticker *Ticker) error {
 	ticker.Location = TickerLocation{}
 	ticker.Users = make([]User, 0)
 
-	return s.DB.Save(&ticker).Error
+	return s.DB.Save(&ticker).Error
+}
+
+func (s *SqlStorage) deleteTickerAssociations(ticker *Ticker) error {
+	if err := s.DeleteTickerUsers(ticker); err != nil {
+		log.WithError(err).WithField("ticker_id", ticker.ID).Error("failed to delete ticker users")
+		return err
+	}
+
+	if err := s.DeleteTickerWebsites(ticker); err != nil {
+		log.WithError(err).WithField("ticker_id", ticker.ID).Error("failed to delete ticker websites")
+		return err
+	}
+
+	if err := s.DeleteUploadsByTicker(ticker); err != nil {
+		log.WithError(err).WithField("ticker_id", ticker.ID).Error("failed to delete ticker uploads")
+		return err
+	}
+
+	if err := s.DeleteMessages(ticker); err != nil {
+		log.WithError(err).WithField("ticker_id", ticker.ID).Error("failed to delete ticker messages")
+		return err
+	}
+
+	if err := s.DeleteIntegrations(ticker); err != nil {
+		log.WithError(err).WithField("ticker_id", ticker.ID).Error("failed to delete ticker integrations")
+		return err
+	}
+
+	return nil
+}
+
+func (s *SqlStorage) DeleteIntegrations(ticker *Ticker) error {
+	if err := s.DeleteMastodon(ticker); err != nil {
+		return err
+	}
+
+	if err := s.DeleteTelegram(ticker); err != nil {
+		return err
+	}
+
+	if err := s.DeleteBluesky(ticker); err != nil {
+		return err
+	}
+
+	if err := s.DeleteSignalGroup(ticker); err != nil {
+		return err
+	}
+
+	if err := s.DeleteTickerSignalSenders(ticker); err != nil {
+		return err
+	}
+
+	if err := s.DeleteMatrix(ticker); err != nil {
+		return err
+	}
+
+	if err := s.DeleteTickerWebhooks(ticker); err != nil {
+		return err
+	}
+
+	if err := s.DeleteActivityPub(ticker); err != nil {
+		return err
+	}
+
+	if err := s.DeleteWebPush(ticker); err != nil {
+		return err
+	}
+
+	if err := s.DeleteEmail(ticker); err != nil {
+		return err
+	}
+
+	if err := s.DeleteMailbox(ticker); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (s *SqlStorage) DeleteMastodon(ticker *Ticker) error {
+	ticker.Mastodon = TickerMastodon{}
+
+	return s.DB.Delete(TickerMastodon{}, EqualTickerID, ticker.ID).Error
+}
+
+func (s *SqlStorage) DeleteTelegram(ticker *Ticker) error {
+	ticker.Telegram = TickerTelegram{}
+
+	return s.DB.Delete(TickerTelegram{}, EqualTickerID, ticker.ID).Error
+}
+
+func (s *SqlStorage) DeleteBluesky(ticker *Ticker) error {
+	ticker.Bluesky = TickerBluesky{}
+
+	return s.DB.Delete(TickerBluesky{}, EqualTickerID, ticker.ID).Error
+}
+
+func (s *SqlStorage) DeleteSignalGroup(ticker *Ticker) error {
+	ticker.SignalGroup = TickerSignalGroup{}
+
+	return s.DB.Delete(TickerSignalGroup{}, EqualTickerID, ticker.ID).Error
+}
+
+func (s *SqlStorage) DeleteMatrix(ticker *Ticker) error {
+	ticker.Matrix = TickerMatrix{}
+
+	return s.DB.Delete(TickerMatrix{}, EqualTickerID, ticker.ID).Error
+}
+
+// DeleteActivityPub removes the actor of the ticker together with its
+// followers, a new actor starts without any.
+func (s *SqlStorage) DeleteActivityPub(ticker *Ticker) error {
+	ticker.ActivityPub = TickerActivityPub{}
+
+	return s.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Delete(ActivityPubFollower{}, EqualTickerID, ticker.ID).Error; err != nil {
+			return err
+		}
+
+		return tx.Delete(TickerActivityPub{}, EqualTickerID, ticker.ID).Error
+	})
+}
+
+func (s *SqlStorage) FindActivityPubFollowers(ticker Ticker) ([]ActivityPubFollower, error) {
+	var followers []ActivityPubFollower
+
+	err := s.DB.Where(EqualTickerID, ticker.ID).Order("id ASC").Find(&followers).Error
+
+	return followers, err
+}
+
+// SaveActivityPubFollower adds the follower, or updates the inboxes when the
+// actor already follows the ticker.
+func (s *SqlStorage) SaveActivityPubFollower(follower *ActivityPubFollower) error {
+	return s.DB.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "ticker_id"}, {Name: "actor"}},
+		DoUpdates: clause.AssignmentColumns([]string{"updated_at", "inbox", "shared_inbox"}),
+	}).Create(follower).Error
+}
+
+func (s *SqlStorage) DeleteActivityPubFollower(ticker Ticker, actor string) error {
+	return s.DB.Where("ticker_id = ? AND actor = ?", ticker.ID, actor).Delete(&ActivityPubFollower{}).Error
+}
+
+// DeleteWebPush turns push notifications off and drops the subscriptions of the
+// readers.
+func (s *SqlStorage) DeleteWebPush(ticker *Ticker) error {
+	ticker.WebPush = TickerWebPush{}
+
+	return s.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Delete(WebPushSubscription{}, EqualTickerID, ticker.ID).Error; err != nil {
+			return err
+		}
+
+		return tx.Delete(TickerWebPush{}, EqualTickerID, ticker.ID).Error
+	})
+}
+
+func (s *SqlStorage) FindWebPushSubscriptions(ticker Ticker) ([]WebPushSubscription, error) {
+	var subscriptions []WebPushSubscription
+
+	err := s.DB.Where(EqualTickerID, ticker.ID).Order("id ASC").Find(&subscriptions).Error
+
+	return subscriptions, err
+}
+
+func (s *SqlStorage) CountWebPushSubscriptions(ticker Ticker) (int64, error) {
+	var count int64
+
+	err := s.DB.Model(&WebPushSubscription{}).Where(EqualTickerID, ticker.ID).Count(&count).Error
+
+	return count, err
+}
+
+// SaveWebPushSubscription adds the subscription, or updates its keys when the
+// browser subscribes again with the same endpoint.
+func (s *SqlStorage) SaveWebPushSubscription(subscription *WebPushSubscription) error {
+	return s.DB.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "ticker_id"}, {Name: "endpoint"}},
+		DoUpdates: clause.AssignmentColumns([]string{"updated_at", "p256dh", "auth"}),
+	}).Create(subscription).Error
+}
+
+func (s *SqlStorage) DeleteWebPushSubscription(ticker Ticker, endpoint string) error {
+	return s.DB.Where("ticker_id = ? AND endpoint = ?", ticker.ID, endpoint).Delete(&WebPushSubscription{}).Error
+}
+
+// DeleteEmail turns the email subscriptions off and drops the addresses of the
+// readers together with a pending digest.
+func (s *SqlStorage) DeleteEmail(ticker *Ticker) error {
+	ticker.Email = TickerEmail{}
+
+	return s.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Delete(EmailSubscription{}, EqualTickerID, ticker.ID).Error; err != nil {
+			return err
+		}
+
+		if err := tx.Delete(EmailDigestEntry{}, EqualTickerID, ticker.ID).Error; err != nil {
+			return err
+		}
+
+		return tx.Delete(TickerEmail{}, EqualTickerID, ticker.ID).Error
+	})
+}
+
+func (s *SqlStorage) FindEmailSubscription(ticker Ticker, email string) (EmailSubscription, error) {
+	var subscription EmailSubscription
+
+	err := s.DB.First(&subscription, "ticker_id = ? AND email = ?", ticker.ID, email).Error
+
+	return subscription, err
 }
 
-func (s *SqlStorage) deleteTickerAssociations(ticker *Ticker) error {
-	if err := s.DeleteTickerUsers(ticker); err != nil {
-		log.WithError(err).WithField("ticker_id", ticker.ID).Error("failed to delete ticker users")
-		return err
-	}
+func (s *SqlStorage) FindEmailSubscriptionByToken(token string) (EmailSubscription, error) {
+	var subscription EmailSubscription
 
-	if err := s.DeleteTickerWebsites(ticker); err != nil {
-		log.WithError(err).WithField("ticker_id", ticker.ID).Error("failed to delete ticker websites")
-		return err
-	}
+	err := s.DB.First(&subscription, "token = ?", token).Error
 
-	if err := s.DeleteUploadsByTicker(ticker); err != nil {
-		log.WithError(err).WithField("ticker_id", ticker.ID).Error("failed to delete ticker uploads")
-		return err
-	}
+	return subscription, err
+}
 
-	if err := s.DeleteMessages(ticker); err != nil {
-		log.WithError(err).WithField("ticker_id", ticker.ID).Error("failed to delete ticker messages")
-		return err
-	}
+// FindConfirmedEmailSubscriptions returns the subscriptions of the ticker which
+// receive mails.
+func (s *SqlStorage) FindConfirmedEmailSubscriptions(ticker Ticker) ([]EmailSubscription, error) {
+	var subscriptions []EmailSubscription
 
-	if err := s.DeleteIntegrations(ticker); err != nil {
-		log.WithError(err).WithField("ticker_id", ticker.ID).Error("failed to delete ticker integrations")
-		return err
-	}
+	err := s.DB.Where(EqualTickerID, ticker.ID).Where("confirmed_at IS NOT NULL").Order("id ASC").Find(&subscriptions).Error
 
-	return nil
+	return subscriptions, err
 }
 
-func (s *SqlStorage) DeleteIntegrations(ticker *Ticker) error {
-	if err := s.DeleteMastodon(ticker); err != nil {
-		return err
-	}
+func (s *SqlStorage) SaveEmailSubscription(subscription *EmailSubscription) error {
+	return s.DB.Save(subscription).Error
+}
 
-	if err := s.DeleteTelegram(ticker); err != nil {
-		return err
+// ClaimEmailConfirmation marks the confirmation mail of an unconfirmed
+// subscription as sent. It reports false when one was sent within the
+// interval, so parallel requests send it at most once.
+func (s *SqlStorage) ClaimEmailConfirmation(subscription *EmailSubscription, interval time.Duration) (bool, error) {
+	now := time.Now()
+	result := s.DB.Model(&EmailSubscription{}).
+		Where("id = ? AND confirmed_at IS NULL AND (confirmation_sent_at IS NULL OR confirmation_sent_at < ?)", subscription.ID, now.Add(-interval)).
+		UpdateColumn("confirmation_sent_at", now)
+	if result.Error != nil {
+		return false, result.Error
 	}
-
-	if err := s.DeleteBluesky(ticker); err != nil {
-		return err
+	if result.RowsAffected == 0 {
+		return false, nil
 	}
 
-	if err := s.DeleteSignalGroup(ticker); err != nil {
-		return err
-	}
+	subscription.ConfirmationSentAt = &now
 
-	return nil
+	return true, nil
 }
 
-func (s *SqlStorage) DeleteMastodon(ticker *Ticker) error {
-	ticker.Mastodon = TickerMastodon{}
+func (s *SqlStorage) DeleteEmailSubscription(subscription EmailSubscription) error {
+	return s.DB.Delete(&subscription).Error
+}
 
-	return s.DB.Delete(TickerMastodon{}, EqualTickerID, ticker.ID).Error
+func (s *SqlStorage) SaveEmailDigestEntry(entry *EmailDigestEntry) error {
+	return s.DB.Create(entry).Error
 }
 
-func (s *SqlStorage) DeleteTelegram(ticker *Ticker) error {
-	ticker.Telegram = TickerTelegram{}
+// FindDueEmailDigests returns the IDs of the tickers whose oldest message
+// waiting for the digest was added before the given time.
+func (s *SqlStorage) FindDueEmailDigests(before time.Time) ([]int, error) {
+	var tickerIDs []int
 
-	return s.DB.Delete(TickerTelegram{}, EqualTickerID, ticker.ID).Error
+	err := s.DB.Model(&EmailDigestEntry{}).
+		Group("ticker_id").
+		Having("MIN(created_at) <= ?", before).
+		Order("ticker_id ASC").
+		Pluck("ticker_id", &tickerIDs).Error
+
+	return tickerIDs, err
 }
 
-func (s *SqlStorage) DeleteBluesky(ticker *Ticker) error {
-	ticker.Bluesky = TickerBluesky{}
+// FindEmailDigestMessages returns the messages added to the digest of the
+// ticker until the given time, the oldest first. Messages deleted in the
+// meantime are left out.
+func (s *SqlStorage) FindEmailDigestMessages(ticker Ticker, until time.Time) ([]Message, error) {
+	messages := make([]Message, 0)
 
-	return s.DB.Delete(TickerBluesky{}, EqualTickerID, ticker.ID).Error
+	entries := s.DB.Model(&EmailDigestEntry{}).Select("message_id").Where("ticker_id = ? AND created_at <= ?", ticker.ID, until)
+	err := s.DB.Where("ticker_id = ? AND id IN (?)", ticker.ID, entries).Order("id ASC").Find(&messages).Error
+
+	return messages, err
 }
 
-func (s *SqlStorage) DeleteSignalGroup(ticker *Ticker) error {
-	ticker.SignalGroup = TickerSignalGroup{}
+// DeleteEmailDigestEntries clears the digest of the ticker up to the given
+// time, after it was sent.
+func (s *SqlStorage) DeleteEmailDigestEntries(ticker Ticker, until time.Time) error {
+	return s.DB.Where("ticker_id = ? AND created_at <= ?", ticker.ID, until).Delete(&EmailDigestEntry{}).Error
+}
 
-	return s.DB.Delete(TickerSignalGroup{}, EqualTickerID, ticker.ID).Error
+func (s *SqlStorage) DeleteMailbox(ticker *Ticker) error {
+	ticker.Mailbox = TickerMailbox{}
+
+	return s.DB.Delete(TickerMailbox{}, EqualTickerID, ticker.ID).Error
 }
 
 func (s *SqlStorage) FindUploadByUUID(uuid string) (Upload, error) {
@@ -424,18 +1203,136 @@ func (s *SqlStorage) FindMessagesByTicker(ticker Ticker, opts ...func(*gorm.DB)
 func (s *SqlStorage) FindMessagesByTickerAndPagination(ticker Ticker, pagination pagination.Pagination, opts ...func(*gorm.DB) *gorm.DB) ([]Message, error) {
 	messages := make([]Message, 0)
 	db := s.prepareDb(opts...)
-	query := db.Where(EqualTickerID, ticker.ID)
+	query := db.Where(EqualTickerID, ticker.ID).Where("publish_at IS NULL AND draft = ?", false)
+	query = s.paginateMessages(query, pagination)
+
+	err := query.Order("created_at desc, id desc").Limit(pagination.GetLimit()).Find(&messages).Error
+	return messages, err
+}
+
+// paginateMessages limits the query to the messages before or after the
+// message of the pagination. The timeline is ordered by the time of
+// publishing, which for drafts and scheduled messages is later than their id
+// suggests, so the position of that message is looked up first. A message
+// which is gone falls back to its id.
+func (s *SqlStorage) paginateMessages(query *gorm.DB, pagination pagination.Pagination) *gorm.DB {
+	id, op := pagination.GetBefore(), "<"
+	if id <= 0 {
+		id, op = pagination.GetAfter(), ">"
+	}
+	if id <= 0 {
+		return query
+	}
+
+	var count int64
+	if err := s.DB.Model(&Message{}).Where("id = ?", id).Count(&count).Error; err != nil || count == 0 {
+		return query.Where("id "+op+" ?", id)
+	}
+
+	// The time is compared within the database, so it is not converted on
+	// the way.
+	createdAt := s.DB.Session(&gorm.Session{NewDB: true}).Model(&Message{}).Select("created_at").Where("id = ?", id)
 
-	if pagination.GetBefore() > 0 {
-		query = query.Where("id < ?", pagination.GetBefore())
-	} else if pagination.GetAfter() > 0 {
-		query = query.Where("id > ?", pagination.GetAfter())
+	return query.Where(fmt.Sprintf("(created_at %[1]s (?) OR (created_at = (?) AND id %[1]s ?))", op), createdAt, createdAt, id)
+}
+
+// SearchMessagesByTicker returns the published messages of a ticker whose text
+// contains all words of the query, the newest first.
+func (s *SqlStorage) SearchMessagesByTicker(ticker Ticker, query string, pagination pagination.Pagination, opts ...func(*gorm.DB) *gorm.DB) ([]Message, error) {
+	messages := make([]Message, 0)
+	terms := strings.Fields(query)
+	if len(terms) == 0 {
+		return messages, nil
 	}
 
-	err := query.Order("id desc").Limit(pagination.GetLimit()).Find(&messages).Error
+	condition, args := searchCondition(s.DB, terms)
+	db := s.prepareDb(opts...)
+	q := db.Where(EqualTickerID, ticker.ID).Where("publish_at IS NULL AND draft = ?", false).Where(condition, args...)
+	q = s.paginateMessages(q, pagination)
+
+	err := q.Order("created_at desc, id desc").Limit(pagination.GetLimit()).Find(&messages).Error
+	return messages, err
+}
+
+// FindScheduledMessagesByTicker returns the messages of a ticker which are not
+// yet published, the next one due first.
+func (s *SqlStorage) FindScheduledMessagesByTicker(ticker Ticker, opts ...func(*gorm.DB) *gorm.DB) ([]Message, error) {
+	messages := make([]Message, 0)
+	db := s.prepareDb(opts...)
+
+	err := db.Where(EqualTickerID, ticker.ID).Where("publish_at IS NOT NULL AND draft = ?", false).Order("publish_at asc").Find(&messages).Error
+
+	return messages, err
+}
+
+// FindPinnedMessagesByTicker returns the published messages of a ticker which
+// are pinned, the newest first.
+func (s *SqlStorage) FindPinnedMessagesByTicker(ticker Ticker, opts ...func(*gorm.DB) *gorm.DB) ([]Message, error) {
+	messages := make([]Message, 0)
+	db := s.prepareDb(opts...)
+
+	err := db.Where(EqualTickerID, ticker.ID).Where("pinned = ? AND publish_at IS NULL AND draft = ?", true, false).Order("created_at desc, id desc").Find(&messages).Error
+
+	return messages, err
+}
+
+// FindDraftMessagesByTicker returns the drafts of a ticker, the most recently
+// changed first.
+func (s *SqlStorage) FindDraftMessagesByTicker(ticker Ticker, opts ...func(*gorm.DB) *gorm.DB) ([]Message, error) {
+	messages := make([]Message, 0)
+	db := s.prepareDb(opts...)
+
+	err := db.Where(EqualTickerID, ticker.ID).Where("draft = ?", true).Order("updated_at desc").Find(&messages).Error
+
+	return messages, err
+}
+
+// FindPendingReviewMessagesByTicker returns the messages from contributors
+// which wait for a review, the oldest first.
+func (s *SqlStorage) FindPendingReviewMessagesByTicker(ticker Ticker, opts ...func(*gorm.DB) *gorm.DB) ([]Message, error) {
+	messages := make([]Message, 0)
+	db := s.prepareDb(opts...)
+
+	err := db.Where(EqualTickerID, ticker.ID).Where("review = ?", ReviewPending).Order("id asc").Find(&messages).Error
+
+	return messages, err
+}
+
+// FindDueMessages returns the scheduled messages of all tickers which are due
+// for publishing at the given time.
+func (s *SqlStorage) FindDueMessages(now time.Time, opts ...func(*gorm.DB) *gorm.DB) ([]Message, error) {
+	messages := make([]Message, 0)
+	db := s.prepareDb(opts...)
+
+	err := db.Where("publish_at IS NOT NULL AND publish_at <= ? AND draft = ?", now, false).Order("publish_at asc").Find(&messages).Error
+
 	return messages, err
 }
 
+// PublishMessage moves a scheduled message or a draft into the timeline. The
+// message is only published if it is not yet, so it returns
+// gorm.ErrRecordNotFound when the message was deleted or published in the
+// meantime.
+func (s *SqlStorage) PublishMessage(message *Message) error {
+	now := time.Now()
+	result := s.DB.Model(&Message{}).
+		Where("id = ? AND (publish_at IS NOT NULL OR draft = ?)", message.ID, true).
+		Updates(map[string]interface{}{"publish_at": nil, "draft": false, "created_at": now})
+	if result.Error != nil {
+		return result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+
+	message.PublishAt = nil
+	message.Draft = false
+	message.CreatedAt = now
+
+	return nil
+}
+
 func (s *SqlStorage) SaveMessage(message *Message) error {
 	if message.ID == 0 {
 		return s.DB.Create(message).Error
@@ -444,7 +1341,10 @@ func (s *SqlStorage) SaveMessage(message *Message) error {
 	return s.DB.Session(&gorm.Session{FullSaveAssociations: true}).Model(message).Updates(message.AsMap()).Error
 }
 
-func (s *SqlStorage) DeleteMessage(message Message) error {
+// DeleteMessage deletes the message and queues its deletion on the given
+// bridges. The message is kept without its content, so a send still running
+// can store what the bridge returned and the deletion finds it afterwards.
+func (s *SqlStorage) DeleteMessage(message Message, bridges []string) error {
 	if len(message.Attachments) > 0 {
 		err := s.DB.Where("message_id = ?", message.ID).Delete(&Attachment{}).Error
 		if err != nil {
@@ -452,12 +1352,37 @@ func (s *SqlStorage) DeleteMessage(message Message) error {
 		}
 	}
 
-	return s.DB.Delete(&message).Error
+	err := s.DB.Where("message_id = ?", message.ID).Delete(&MessageTag{}).Error
+	if err != nil {
+		log.WithError(err).WithField("message_id", message.ID).Error("failed to delete message tags")
+	}
+
+	return s.DB.Transaction(func(tx *gorm.DB) error {
+		err := tx.Model(&Message{ID: message.ID}).Updates(map[string]interface{}{"text": "", "geometry": nil}).Error
+		if err != nil {
+			return err
+		}
+
+		if err := tx.Delete(&Message{ID: message.ID}).Error; err != nil {
+			return err
+		}
+
+		if len(bridges) == 0 {
+			return nil
+		}
+
+		jobs := make([]OutboxJob, 0, len(bridges))
+		for _, bridge := range bridges {
+			jobs = append(jobs, NewOutboxDeleteJob(message, bridge))
+		}
+
+		return tx.Create(&jobs).Error
+	})
 }
 
 func (s *SqlStorage) DeleteMessages(ticker *Ticker) error {
 	var msgIds []int
-	err := s.DB.Model(&Message{}).Where(EqualTickerID, ticker.ID).Pluck("id", &msgIds).Error
+	err := s.DB.Unscoped().Model(&Message{}).Where(EqualTickerID, ticker.ID).Pluck("id", &msgIds).Error
 	if err != nil {
 		return err
 	}
@@ -467,7 +1392,143 @@ func (s *SqlStorage) DeleteMessages(ticker *Ticker) error {
 		return err
 	}
 
-	return s.DB.Where(EqualTickerID, ticker.ID).Delete(&Message{}).Error
+	err = s.DB.Where("message_id IN ?", msgIds).Delete(&MessageTag{}).Error
+	if err != nil {
+		return err
+	}
+
+	err = s.DB.Where("message_id IN ?", msgIds).Delete(&OutboxJob{}).Error
+	if err != nil {
+		return err
+	}
+
+	return s.DB.Unscoped().Where(EqualTickerID, ticker.ID).Delete(&Message{}).Error
+}
+
+// SaveMessageTags replaces the tags of the message with the given names.
+func (s *SqlStorage) SaveMessageTags(message *Message, names []string) error {
+	tags := make([]MessageTag, 0, len(names))
+	for _, name := range names {
+		tags = append(tags, MessageTag{MessageID: message.ID, Name: name})
+	}
+
+	err := s.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("message_id = ?", message.ID).Delete(&MessageTag{}).Error; err != nil {
+			return err
+		}
+
+		if len(tags) == 0 {
+			return nil
+		}
+
+		return tx.Create(&tags).Error
+	})
+	if err != nil {
+		return err
+	}
+
+	message.Tags = tags
+	return nil
+}
+
+// SaveMessageBridgeMeta only saves what the bridge stored on the message when
+// sending it, so bridges delivering the same message at the same time do not
+// overwrite each other. It is saved for deleted messages as well, for the
+// deletion queued behind the send.
+func (s *SqlStorage) SaveMessageBridgeMeta(message *Message, bridge string) error {
+	column, ok := messageMetaColumns[bridge]
+	if !ok {
+		return nil
+	}
+
+	return s.DB.Unscoped().Model(message).Select(column).Updates(message).Error
+}
+
+// CreateOutboxJobs queues the delivery of the message to the given bridges.
+func (s *SqlStorage) CreateOutboxJobs(message Message, bridges []string) error {
+	if len(bridges) == 0 {
+		return nil
+	}
+
+	jobs := make([]OutboxJob, 0, len(bridges))
+	for _, bridge := range bridges {
+		jobs = append(jobs, NewOutboxJob(message, bridge))
+	}
+
+	return s.DB.Create(&jobs).Error
+}
+
+// CreateOutboxEditJobs queues an edit of the message on the given bridges.
+func (s *SqlStorage) CreateOutboxEditJobs(message Message, bridges []string) error {
+	if len(bridges) == 0 {
+		return nil
+	}
+
+	jobs := make([]OutboxJob, 0, len(bridges))
+	for _, bridge := range bridges {
+		jobs = append(jobs, NewOutboxEditJob(message, bridge))
+	}
+
+	return s.DB.Create(&jobs).Error
+}
+
+// ClaimOutboxJobs returns up to limit pending jobs which are due. The next
+// attempt of each claimed job is moved behind the lease, so other instances
+// leave it alone and it is picked up again if the delivery never finishes.
+func (s *SqlStorage) ClaimOutboxJobs(now time.Time, limit int, lease time.Duration) ([]OutboxJob, error) {
+	var candidates []OutboxJob
+	err := s.DB.Where("status = ? AND next_attempt_at <= ?", OutboxPending, now).Order("id asc").Limit(limit).Find(&candidates).Error
+	if err != nil {
+		return nil, err
+	}
+
+	jobs := make([]OutboxJob, 0, len(candidates))
+	for _, job := range candidates {
+		result := s.DB.Model(&OutboxJob{}).
+			Where("id = ? AND next_attempt_at = ?", job.ID, job.NextAttemptAt).
+			Update("next_attempt_at", now.Add(lease))
+		if result.Error != nil {
+			return jobs, result.Error
+		}
+		if result.RowsAffected == 1 {
+			job.NextAttemptAt = now.Add(lease)
+			jobs = append(jobs, job)
+		}
+	}
+
+	return jobs, nil
+}
+
+func (s *SqlStorage) SaveOutboxJob(job *OutboxJob) error {
+	return s.DB.Save(job).Error
+}
+
+// FindTagsByTicker returns the tags used in the published messages of a
+// ticker with the number of messages, the most used first.
+func (s *SqlStorage) FindTagsByTicker(ticker Ticker) ([]TagCount, error) {
+	tags := make([]TagCount, 0)
+	err := s.DB.Model(&MessageTag{}).
+		Select("message_tags.name AS name, COUNT(*) AS count").
+		Joins("JOIN messages ON messages.id = message_tags.message_id").
+		Where("messages.ticker_id = ? AND messages.publish_at IS NULL AND messages.draft = ?", ticker.ID, false).
+		Group("message_tags.name").
+		Order("count desc, name asc").
+		Scan(&tags).Error
+
+	return tags, err
+}
+
+// FindMessageRevisions returns all revisions of a message of the ticker, the
+// oldest first. They are found after the message was deleted, too.
+func (s *SqlStorage) FindMessageRevisions(tickerID, messageID int) ([]MessageRevision, error) {
+	revisions := make([]MessageRevision, 0)
+	err := s.DB.Where("ticker_id = ? AND message_id = ?", tickerID, messageID).Order("id asc").Find(&revisions).Error
+
+	return revisions, err
+}
+
+func (s *SqlStorage) SaveMessageRevision(revision *MessageRevision) error {
+	return s.DB.Create(revision).Error
 }
 
 func (s *SqlStorage) GetInactiveSettings() InactiveSettings {
@@ -566,6 +1627,70 @@ func (s *SqlStorage) SaveSignalGroupSettings(signalGroupSettings SignalGroupSett
 	return s.DB.Save(&setting).Error
 }
 
+func (s *SqlStorage) GetWebPushSettings() WebPushSettings {
+	var setting Setting
+	err := s.DB.First(&setting, EqualName, SettingWebPushName).Error
+	if err != nil {
+		return WebPushSettings{}
+	}
+
+	var webPushSettings WebPushSettings
+	err = json.Unmarshal([]byte(setting.Value), &webPushSettings)
+	if err != nil {
+		return WebPushSettings{}
+	}
+
+	return webPushSettings
+}
+
+func (s *SqlStorage) SaveWebPushSettings(webPushSettings WebPushSettings) error {
+	var setting Setting
+	err := s.DB.First(&setting, EqualName, SettingWebPushName).Error
+	if err != nil {
+		setting = Setting{Name: SettingWebPushName}
+	}
+
+	value, err := json.Marshal(webPushSettings)
+	if err != nil {
+		return err
+	}
+	setting.Value = string(value)
+
+	return s.DB.Save(&setting).Error
+}
+
+func (s *SqlStorage) GetSecuritySettings() SecuritySettings {
+	var setting Setting
+	err := s.DB.First(&setting, EqualName, SettingSecurityName).Error
+	if err != nil {
+		return SecuritySettings{}
+	}
+
+	var securitySettings SecuritySettings
+	err = json.Unmarshal([]byte(setting.Value), &securitySettings)
+	if err != nil {
+		return SecuritySettings{}
+	}
+
+	return securitySettings
+}
+
+func (s *SqlStorage) SaveSecuritySettings(securitySettings SecuritySettings) error {
+	var setting Setting
+	err := s.DB.First(&setting, EqualName, SettingSecurityName).Error
+	if err != nil {
+		setting = Setting{Name: SettingSecurityName}
+	}
+
+	value, err := json.Marshal(securitySettings)
+	if err != nil {
+		return err
+	}
+	setting.Value = string(value)
+
+	return s.DB.Save(&setting).Error
+}
+
 func (s *SqlStorage) prepareDb(opts ...func(*gorm.DB) *gorm.DB) *gorm.DB {
 	db := s.DB
 	for _, opt := range opts {
@@ -589,6 +1714,60 @@ func WithAttachments() func(*gorm.DB) *gorm.DB {
 	}
 }
 
+// WithTags is a helper function to preload the tags association.
+func WithTags() func(*gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		return db.Preload("Tags")
+	}
+}
+
+// WithTag is a helper function to only find messages with the given tag.
+func WithTag(name string) func(*gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		return db.Where("id IN (?)", db.Session(&gorm.Session{NewDB: true}).Model(&MessageTag{}).Select("message_id").Where("name = ?", name))
+	}
+}
+
+// WithAuthor is a helper function to only find messages written by the user,
+// who is recorded with the first revision of a message.
+func WithAuthor(userID int) func(*gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		return db.Where("id IN (?)", db.Session(&gorm.Session{NewDB: true}).Model(&MessageRevision{}).Select("message_id").Where("action = ? AND user_id = ?", RevisionCreated, userID))
+	}
+}
+
+// WithoutPinned is a helper function to leave out pinned messages, for lists
+// which show them separately.
+func WithoutPinned() func(*gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		return db.Where("pinned = ?", false)
+	}
+}
+
+// WithDeliveries is a helper function to preload the delivery state of the
+// message for each bridge.
+func WithDeliveries() func(*gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		return db.Preload("Deliveries", func(db *gorm.DB) *gorm.DB {
+			return db.Order("bridge asc")
+		})
+	}
+}
+
+// WithGeometry is a helper function to only find messages with a geometry.
+func WithGeometry() func(*gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		return db.Where("geometry IS NOT NULL")
+	}
+}
+
+// WithDeleted is a helper function to find deleted messages as well.
+func WithDeleted() func(*gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		return db.Unscoped()
+	}
+}
+
 // WithTickers is a helper function to preload the tickers association.
 func WithTickers() func(*gorm.DB) *gorm.DB {
 	return func(db *gorm.DB) *gorm.DB {
@@ -3,7 +3,9 @@ package storage
 import (
 	"net/http"
 	"net/url"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/stretchr/testify/suite"
@@ -31,25 +33,68 @@ func (s *SqlStorageTestSuite) SetupTest() {
 		&TickerMastodon{},
 		&TickerBluesky{},
 		&TickerSignalGroup{},
+		&TickerMatrix{},
+		&TickerWebhook{},
+		&TickerSignalSender{},
+		&TickerActivityPub{},
+		&ActivityPubFollower{},
+		&TickerWebPush{},
+		&WebPushSubscription{},
+		&TickerEmail{},
+		&EmailSubscription{},
+		&EmailDigestEntry{},
+		&TickerMailbox{},
+		&TelegramLink{},
+		&RecoveryCode{},
+		&WebAuthnCredential{},
+		&WebAuthnSession{},
+		&OIDCSession{},
+		&APIToken{},
 		&TickerWebsite{},
 		&User{},
+		&TickerUser{},
 		&Message{},
 		&Upload{},
 		&Attachment{},
+		&MessageRevision{},
+		&MessageTag{},
+		&OutboxJob{},
 		&Setting{},
 	)
 	s.NoError(err)
+	s.NoError(setupSearchIndex(db))
 }
 
 func (s *SqlStorageTestSuite) BeforeTest(suiteName, testName string) {
 	s.NoError(s.db.Exec("DELETE FROM users").Error)
 	s.NoError(s.db.Exec("DELETE FROM messages").Error)
 	s.NoError(s.db.Exec("DELETE FROM attachments").Error)
+	s.NoError(s.db.Exec("DELETE FROM message_revisions").Error)
+	s.NoError(s.db.Exec("DELETE FROM message_tags").Error)
+	s.NoError(s.db.Exec("DELETE FROM outbox_jobs").Error)
 	s.NoError(s.db.Exec("DELETE FROM tickers").Error)
+	s.NoError(s.db.Exec("DELETE FROM ticker_users").Error)
 	s.NoError(s.db.Exec("DELETE FROM ticker_mastodons").Error)
 	s.NoError(s.db.Exec("DELETE FROM ticker_telegrams").Error)
 	s.NoError(s.db.Exec("DELETE FROM ticker_blueskies").Error)
 	s.NoError(s.db.Exec("DELETE FROM ticker_signal_groups").Error)
+	s.NoError(s.db.Exec("DELETE FROM ticker_matrices").Error)
+	s.NoError(s.db.Exec("DELETE FROM ticker_webhooks").Error)
+	s.NoError(s.db.Exec("DELETE FROM ticker_signal_senders").Error)
+	s.NoError(s.db.Exec("DELETE FROM ticker_activity_pubs").Error)
+	s.NoError(s.db.Exec("DELETE FROM activity_pub_followers").Error)
+	s.NoError(s.db.Exec("DELETE FROM ticker_web_pushes").Error)
+	s.NoError(s.db.Exec("DELETE FROM web_push_subscriptions").Error)
+	s.NoError(s.db.Exec("DELETE FROM ticker_emails").Error)
+	s.NoError(s.db.Exec("DELETE FROM email_subscriptions").Error)
+	s.NoError(s.db.Exec("DELETE FROM email_digest_entries").Error)
+	s.NoError(s.db.Exec("DELETE FROM ticker_mailboxes").Error)
+	s.NoError(s.db.Exec("DELETE FROM telegram_links").Error)
+	s.NoError(s.db.Exec("DELETE FROM recovery_codes").Error)
+	s.NoError(s.db.Exec("DELETE FROM web_authn_credentials").Error)
+	s.NoError(s.db.Exec("DELETE FROM web_authn_sessions").Error)
+	s.NoError(s.db.Exec("DELETE FROM oidc_sessions").Error)
+	s.NoError(s.db.Exec("DELETE FROM api_tokens").Error)
 	s.NoError(s.db.Exec("DELETE FROM ticker_websites").Error)
 	s.NoError(s.db.Exec("DELETE FROM settings").Error)
 	s.NoError(s.db.Exec("DELETE FROM uploads").Error)
@@ -323,6 +368,464 @@ func (s *SqlStorageTestSuite) TestDeleteUser() {
 	})
 }
 
+func (s *SqlStorageTestSuite) TestTOTP() {
+	user, err := NewUser("user@example.org", "password")
+	s.NoError(err)
+	s.NoError(s.db.Create(&user).Error)
+
+	s.Run("when the secret is saved", func() {
+		user.TOTPSecret = "GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ"
+		err := s.store.SaveTOTPSecret(&user)
+		s.NoError(err)
+
+		found, err := s.store.FindUserByID(user.ID)
+		s.NoError(err)
+		s.Equal("GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ", found.TOTPSecret)
+		s.False(found.TOTPEnabled)
+	})
+
+	s.Run("when the second factor is turned on", func() {
+		plain, codes, err := NewRecoveryCodes(user)
+		s.NoError(err)
+		s.Len(plain, 10)
+
+		err = s.store.EnableTOTP(&user, 100, codes)
+		s.NoError(err)
+		s.True(user.TOTPEnabled)
+
+		found, err := s.store.FindUserByID(user.ID)
+		s.NoError(err)
+		s.True(found.TOTPEnabled)
+		s.Equal(int64(100), found.TOTPLastStep)
+
+		count, err := s.store.CountRecoveryCodes(user)
+		s.NoError(err)
+		s.Equal(int64(10), count)
+
+		used, err := s.store.UseRecoveryCode(user, strings.ToUpper(plain[0]))
+		s.NoError(err)
+		s.True(used)
+
+		used, err = s.store.UseRecoveryCode(user, plain[0])
+		s.NoError(err)
+		s.False(used)
+
+		count, err = s.store.CountRecoveryCodes(user)
+		s.NoError(err)
+		s.Equal(int64(9), count)
+	})
+
+	s.Run("when a time step is used", func() {
+		used, err := s.store.UseTOTPStep(&user, 100)
+		s.NoError(err)
+		s.False(used)
+
+		used, err = s.store.UseTOTPStep(&user, 101)
+		s.NoError(err)
+		s.True(used)
+		s.Equal(int64(101), user.TOTPLastStep)
+	})
+
+	s.Run("when codes are entered", func() {
+		attempts, err := s.store.AddTOTPAttempt(&user)
+		s.NoError(err)
+		s.Equal(1, attempts)
+
+		attempts, err = s.store.AddTOTPAttempt(&user)
+		s.NoError(err)
+		s.Equal(2, attempts)
+
+		user.TOTPAttempts = 0
+		user.TOTPLockedUntil = time.Now().Add(time.Minute).Truncate(time.Second)
+		err = s.store.SaveTOTPLock(&user)
+		s.NoError(err)
+
+		found, err := s.store.FindUserByID(user.ID)
+		s.NoError(err)
+		s.Zero(found.TOTPAttempts)
+		s.True(user.TOTPLockedUntil.Equal(found.TOTPLockedUntil))
+	})
+
+	s.Run("when the recovery codes are renewed", func() {
+		_, codes, err := NewRecoveryCodes(user)
+		s.NoError(err)
+
+		err = s.store.SaveRecoveryCodes(user, codes)
+		s.NoError(err)
+
+		count, err := s.store.CountRecoveryCodes(user)
+		s.NoError(err)
+		s.Equal(int64(10), count)
+	})
+
+	s.Run("when the second factor is turned off", func() {
+		err := s.store.DisableTOTP(&user)
+		s.NoError(err)
+		s.Empty(user.TOTPSecret)
+
+		found, err := s.store.FindUserByID(user.ID)
+		s.NoError(err)
+		s.Empty(found.TOTPSecret)
+		s.False(found.TOTPEnabled)
+
+		count, err := s.store.CountRecoveryCodes(user)
+		s.NoError(err)
+		s.Zero(count)
+	})
+}
+
+func (s *SqlStorageTestSuite) TestAPITokens() {
+	user, err := NewUser("user@example.org", "password")
+	s.NoError(err)
+	s.NoError(s.db.Create(&user).Error)
+
+	other, err := NewUser("other@example.org", "password")
+	s.NoError(err)
+	s.NoError(s.db.Create(&other).Error)
+
+	plain, token, err := NewAPIToken(user, "Sensor", []string{APITokenScopeMessagesWrite}, 5)
+	s.NoError(err)
+
+	s.Run("when a token is saved", func() {
+		s.NoError(s.store.SaveAPIToken(&token))
+		s.NotZero(token.ID)
+
+		tokens, err := s.store.FindAPITokens(user)
+		s.NoError(err)
+		s.Len(tokens, 1)
+		s.Equal([]string{APITokenScopeMessagesWrite}, tokens[0].Scopes)
+		s.Equal(5, tokens[0].TickerID)
+
+		tokens, err = s.store.FindAPITokens(other)
+		s.NoError(err)
+		s.Empty(tokens)
+	})
+
+	s.Run("when a token is found", func() {
+		found, err := s.store.FindAPITokenByHash(HashAPIToken(plain))
+		s.NoError(err)
+		s.Equal(token.ID, found.ID)
+
+		_, err = s.store.FindAPITokenByHash(HashAPIToken(plain + "0"))
+		s.Error(err)
+
+		found, err = s.store.FindAPITokenByID(user, token.ID)
+		s.NoError(err)
+		s.Equal("Sensor", found.Name)
+
+		_, err = s.store.FindAPITokenByID(other, token.ID)
+		s.Error(err)
+	})
+
+	s.Run("when the token is used", func() {
+		token.LastUsed = time.Now()
+		s.NoError(s.store.SaveAPITokenLastUsed(&token))
+
+		found, err := s.store.FindAPITokenByID(user, token.ID)
+		s.NoError(err)
+		s.WithinDuration(token.LastUsed, found.LastUsed, time.Second)
+	})
+
+	s.Run("when a token is deleted", func() {
+		s.NoError(s.store.DeleteAPIToken(token))
+
+		_, err := s.store.FindAPITokenByHash(HashAPIToken(plain))
+		s.Error(err)
+	})
+
+	s.Run("when the user is deleted", func() {
+		_, token, err := NewAPIToken(user, "Bot", []string{APITokenScopeMessagesRead}, 0)
+		s.NoError(err)
+		s.NoError(s.store.SaveAPIToken(&token))
+
+		s.NoError(s.store.DeleteUser(user))
+
+		tokens, err := s.store.FindAPITokens(user)
+		s.NoError(err)
+		s.Empty(tokens)
+	})
+}
+
+func (s *SqlStorageTestSuite) TestWebAuthnCredentials() {
+	user, err := NewUser("user@example.org", "password")
+	s.NoError(err)
+	s.NoError(s.db.Create(&user).Error)
+
+	other, err := NewUser("other@example.org", "password")
+	s.NoError(err)
+	s.NoError(s.db.Create(&other).Error)
+
+	s.Run("when the handle is saved", func() {
+		_, err := s.store.FindUserByWebAuthnHandle("")
+		s.Error(err)
+
+		user.WebAuthnHandle, err = NewWebAuthnHandle()
+		s.NoError(err)
+		s.NoError(s.store.SaveWebAuthnHandle(&user))
+
+		found, err := s.store.FindUserByWebAuthnHandle(user.WebAuthnHandle)
+		s.NoError(err)
+		s.Equal(user.ID, found.ID)
+	})
+
+	credential := WebAuthnCredential{
+		UserID:       user.ID,
+		Name:         "Laptop",
+		CredentialID: []byte{1, 2, 3},
+		PublicKey:    []byte{4, 5, 6},
+		Transports:   []string{"internal", "hybrid"},
+	}
+
+	s.Run("when a credential is saved", func() {
+		s.NoError(s.store.SaveWebAuthnCredential(&credential))
+		s.NotZero(credential.ID)
+
+		credential.SignCount = 5
+		s.NoError(s.store.SaveWebAuthnCredential(&credential))
+
+		credentials, err := s.store.FindWebAuthnCredentials(user)
+		s.NoError(err)
+		s.Len(credentials, 1)
+		s.Equal([]byte{1, 2, 3}, credentials[0].CredentialID)
+		s.Equal([]string{"internal", "hybrid"}, credentials[0].Transports)
+		s.Equal(uint32(5), credentials[0].SignCount)
+
+		count, err := s.store.CountWebAuthnCredentials(user)
+		s.NoError(err)
+		s.Equal(int64(1), count)
+
+		count, err = s.store.CountWebAuthnCredentials(other)
+		s.NoError(err)
+		s.Zero(count)
+	})
+
+	s.Run("when a credential is found by id", func() {
+		found, err := s.store.FindWebAuthnCredentialByID(user, credential.ID)
+		s.NoError(err)
+		s.Equal("Laptop", found.Name)
+
+		_, err = s.store.FindWebAuthnCredentialByID(other, credential.ID)
+		s.Error(err)
+	})
+
+	s.Run("when a credential is deleted", func() {
+		s.NoError(s.store.DeleteWebAuthnCredential(credential))
+
+		count, err := s.store.CountWebAuthnCredentials(user)
+		s.NoError(err)
+		s.Zero(count)
+	})
+
+	s.Run("when all credentials of a user are deleted", func() {
+		s.NoError(s.store.SaveWebAuthnCredential(&WebAuthnCredential{UserID: user.ID, CredentialID: []byte{1}, PublicKey: []byte{2}}))
+		s.NoError(s.store.SaveWebAuthnCredential(&WebAuthnCredential{UserID: user.ID, CredentialID: []byte{3}, PublicKey: []byte{4}}))
+		s.NoError(s.store.SaveWebAuthnCredential(&WebAuthnCredential{UserID: other.ID, CredentialID: []byte{5}, PublicKey: []byte{6}}))
+
+		s.NoError(s.store.DeleteWebAuthnCredentials(user))
+
+		count, err := s.store.CountWebAuthnCredentials(user)
+		s.NoError(err)
+		s.Zero(count)
+
+		count, err = s.store.CountWebAuthnCredentials(other)
+		s.NoError(err)
+		s.Equal(int64(1), count)
+	})
+}
+
+func (s *SqlStorageTestSuite) TestWebAuthnSessions() {
+	s.Run("when a session is taken", func() {
+		session, err := NewWebAuthnSession(1, []byte(`{"challenge":"abc"}`))
+		s.NoError(err)
+		s.NoError(s.store.SaveWebAuthnSession(&session))
+
+		taken, err := s.store.TakeWebAuthnSession(session.Token)
+		s.NoError(err)
+		s.Equal(1, taken.UserID)
+		s.Equal([]byte(`{"challenge":"abc"}`), taken.Data)
+
+		_, err = s.store.TakeWebAuthnSession(session.Token)
+		s.Error(err)
+	})
+
+	s.Run("when a session is expired", func() {
+		session, err := NewWebAuthnSession(0, []byte(`{}`))
+		s.NoError(err)
+		session.CreatedAt = time.Now().Add(-WebAuthnSessionTTL - time.Minute)
+		s.NoError(s.db.Create(&session).Error)
+
+		_, err = s.store.TakeWebAuthnSession(session.Token)
+		s.Error(err)
+	})
+
+	s.Run("when expired sessions are cleaned up", func() {
+		expired, err := NewWebAuthnSession(0, []byte(`{}`))
+		s.NoError(err)
+		expired.CreatedAt = time.Now().Add(-WebAuthnSessionTTL - time.Minute)
+		s.NoError(s.db.Create(&expired).Error)
+
+		session, err := NewWebAuthnSession(0, []byte(`{}`))
+		s.NoError(err)
+		s.NoError(s.store.SaveWebAuthnSession(&session))
+
+		var count int64
+		s.NoError(s.db.Model(&WebAuthnSession{}).Count(&count).Error)
+		s.Equal(int64(1), count)
+	})
+}
+
+func (s *SqlStorageTestSuite) TestOIDCSubject() {
+	user, err := NewUser("user@example.org", "password")
+	s.NoError(err)
+	s.NoError(s.db.Create(&user).Error)
+
+	s.Run("when subject is empty", func() {
+		_, err := s.store.FindUserByOIDCSubject("")
+		s.Error(err)
+	})
+
+	s.Run("when user is linked", func() {
+		user.OIDCSubject = "f4f2ac0e-0d5c-4e0b-9d4a-0e4c6a4c2b11"
+		s.NoError(s.store.SaveOIDCSubject(&user))
+
+		found, err := s.store.FindUserByOIDCSubject(user.OIDCSubject)
+		s.NoError(err)
+		s.Equal(user.ID, found.ID)
+	})
+
+	s.Run("when the user is saved", func() {
+		user.IsSuperAdmin = true
+		s.NoError(s.store.SaveUser(&user))
+
+		found, err := s.store.FindUserByID(user.ID)
+		s.NoError(err)
+		s.True(found.IsSuperAdmin)
+		s.Equal(user.OIDCSubject, found.OIDCSubject)
+	})
+}
+
+func (s *SqlStorageTestSuite) TestOIDCSessions() {
+	s.Run("when a session is taken", func() {
+		session, err := NewOIDCSession("verifier")
+		s.NoError(err)
+		s.NoError(s.store.SaveOIDCSession(&session))
+
+		taken, err := s.store.TakeOIDCSession(session.State)
+		s.NoError(err)
+		s.Equal("verifier", taken.Verifier)
+		s.Equal(session.Nonce, taken.Nonce)
+
+		_, err = s.store.TakeOIDCSession(session.State)
+		s.Error(err)
+	})
+
+	s.Run("when a session is expired", func() {
+		session, err := NewOIDCSession("verifier")
+		s.NoError(err)
+		session.CreatedAt = time.Now().Add(-OIDCSessionTTL - time.Minute)
+		s.NoError(s.db.Create(&session).Error)
+
+		_, err = s.store.TakeOIDCSession(session.State)
+		s.Error(err)
+	})
+
+	s.Run("when expired sessions are cleaned up", func() {
+		expired, err := NewOIDCSession("verifier")
+		s.NoError(err)
+		expired.CreatedAt = time.Now().Add(-OIDCSessionTTL - time.Minute)
+		s.NoError(s.db.Create(&expired).Error)
+
+		session, err := NewOIDCSession("verifier")
+		s.NoError(err)
+		s.NoError(s.store.SaveOIDCSession(&session))
+
+		var count int64
+		s.NoError(s.db.Model(&OIDCSession{}).Count(&count).Error)
+		s.Equal(int64(1), count)
+	})
+}
+
+func (s *SqlStorageTestSuite) TestTelegramAccount() {
+	user, err := NewUser("user@example.org", "password")
+	s.NoError(err)
+	s.NoError(s.db.Create(&user).Error)
+
+	other, err := NewUser("other@example.org", "password")
+	s.NoError(err)
+	s.NoError(s.db.Create(&other).Error)
+
+	s.Run("when a code is requested twice", func() {
+		first, err := NewTelegramLink(user)
+		s.NoError(err)
+		s.NoError(s.store.SaveTelegramLink(&first))
+
+		second, err := NewTelegramLink(user)
+		s.NoError(err)
+		s.NoError(s.store.SaveTelegramLink(&second))
+
+		_, err = s.store.FindTelegramLinkByCode(first.Code)
+		s.Error(err)
+
+		link, err := s.store.FindTelegramLinkByCode(second.Code)
+		s.NoError(err)
+		s.Equal(user.ID, link.UserID)
+		s.False(link.Expired())
+	})
+
+	s.Run("when the account is linked", func() {
+		link, err := NewTelegramLink(user)
+		s.NoError(err)
+		s.NoError(s.store.SaveTelegramLink(&link))
+		link, err = s.store.FindTelegramLinkByCode(link.Code)
+		s.NoError(err)
+
+		err = s.store.LinkTelegramAccount(link, 42)
+		s.NoError(err)
+
+		found, err := s.store.FindUserByTelegramID(42)
+		s.NoError(err)
+		s.Equal(user.ID, found.ID)
+
+		_, err = s.store.FindTelegramLinkByCode(link.Code)
+		s.Error(err)
+	})
+
+	s.Run("when a ticker is chosen", func() {
+		user.TelegramTickerID = 7
+		err := s.store.SaveTelegramTicker(&user)
+		s.NoError(err)
+
+		found, err := s.store.FindUserByTelegramID(42)
+		s.NoError(err)
+		s.Equal(7, found.TelegramTickerID)
+	})
+
+	s.Run("when the account is linked to another user", func() {
+		link, err := NewTelegramLink(other)
+		s.NoError(err)
+		s.NoError(s.store.SaveTelegramLink(&link))
+		link, err = s.store.FindTelegramLinkByCode(link.Code)
+		s.NoError(err)
+
+		err = s.store.LinkTelegramAccount(link, 42)
+		s.NoError(err)
+
+		found, err := s.store.FindUserByTelegramID(42)
+		s.NoError(err)
+		s.Equal(other.ID, found.ID)
+		s.Zero(found.TelegramTickerID)
+	})
+
+	s.Run("when the account is unlinked", func() {
+		err := s.store.UnlinkTelegramAccount(&other)
+		s.NoError(err)
+		s.Nil(other.TelegramID)
+
+		_, err = s.store.FindUserByTelegramID(42)
+		s.Error(err)
+	})
+}
+
 func (s *SqlStorageTestSuite) TestDeleteTickerUsers() {
 	s.Run("when ticker does not exist", func() {
 		ticker := &Ticker{ID: 1}
@@ -505,36 +1008,111 @@ func (s *SqlStorageTestSuite) TestFindTickerByOrigin() {
 	})
 }
 
-func (s *SqlStorageTestSuite) TestFindTickersByUser() {
-	s.Run("when no tickers exist", func() {
-		filter := TickerFilter{OrderBy: "id", Sort: "desc"}
-		tickers, err := s.store.FindTickersByUser(User{ID: 1}, filter)
-		s.NoError(err)
-		s.Empty(tickers)
-	})
-
-	user, err := NewUser("user@example.org", "password")
+func (s *SqlStorageTestSuite) TestTickerUsers() {
+	ticker := Ticker{ID: 1}
+	err := s.db.Create(&ticker).Error
 	s.NoError(err)
 
+	user, err := NewUser("user@systemli.org", "password")
+	s.NoError(err)
 	err = s.db.Create(&user).Error
 	s.NoError(err)
 
-	ticker := Ticker{Users: []User{user}, Active: false, Title: "title", Websites: []TickerWebsite{{Origin: "http://localhost"}}}
-	err = s.db.Create(&ticker).Error
+	owner, err := NewUser("owner@systemli.org", "password")
+	s.NoError(err)
+	err = s.db.Create(&owner).Error
 	s.NoError(err)
 
-	s.Run("when tickers exist", func() {
-		filter := TickerFilter{OrderBy: "id", Sort: "desc"}
-		tickers, err := s.store.FindTickersByUser(user, filter)
+	s.Run("when user is no member", func() {
+		_, err := s.store.FindTickerUser(ticker, user)
+		s.Error(err)
+
+		tickerUsers, err := s.store.FindTickerUsers(ticker)
 		s.NoError(err)
-		s.Len(tickers, 1)
+		s.Empty(tickerUsers)
 	})
 
-	s.Run("when tickers exist with preload", func() {
-		filter := TickerFilter{OrderBy: "id", Sort: "desc"}
-		tickers, err := s.store.FindTickersByUser(user, filter, WithPreload())
+	s.Run("when user is added without a role", func() {
+		err := s.store.AddTickerUser(&ticker, &user)
 		s.NoError(err)
-		s.Len(tickers, 1)
+
+		tickerUser, err := s.store.FindTickerUser(ticker, user)
+		s.NoError(err)
+		s.Equal(TickerRoleEditor, tickerUser.Role)
+	})
+
+	s.Run("when members are saved with roles", func() {
+		err := s.store.SaveTickerUsers(&ticker, []TickerUser{
+			{UserID: user.ID, Role: TickerRoleContributor},
+			{UserID: owner.ID, Role: TickerRoleOwner},
+		})
+		s.NoError(err)
+
+		tickerUser, err := s.store.FindTickerUser(ticker, user)
+		s.NoError(err)
+		s.Equal(TickerRoleContributor, tickerUser.Role)
+
+		tickerUsers, err := s.store.FindTickerUsers(ticker)
+		s.NoError(err)
+		s.Len(tickerUsers, 2)
+
+		users, err := s.store.FindUsersByTicker(ticker)
+		s.NoError(err)
+		s.Len(users, 2)
+	})
+
+	s.Run("when members are replaced", func() {
+		err := s.store.SaveTickerUsers(&ticker, []TickerUser{{UserID: owner.ID, Role: TickerRoleOwner}})
+		s.NoError(err)
+
+		_, err = s.store.FindTickerUser(ticker, user)
+		s.Error(err)
+
+		tickerUsers, err := s.store.FindTickerUsers(ticker)
+		s.NoError(err)
+		s.Len(tickerUsers, 1)
+	})
+
+	s.Run("when all members are removed", func() {
+		err := s.store.SaveTickerUsers(&ticker, []TickerUser{})
+		s.NoError(err)
+
+		tickerUsers, err := s.store.FindTickerUsers(ticker)
+		s.NoError(err)
+		s.Empty(tickerUsers)
+	})
+}
+
+func (s *SqlStorageTestSuite) TestFindTickersByUser() {
+	s.Run("when no tickers exist", func() {
+		filter := TickerFilter{OrderBy: "id", Sort: "desc"}
+		tickers, err := s.store.FindTickersByUser(User{ID: 1}, filter)
+		s.NoError(err)
+		s.Empty(tickers)
+	})
+
+	user, err := NewUser("user@example.org", "password")
+	s.NoError(err)
+
+	err = s.db.Create(&user).Error
+	s.NoError(err)
+
+	ticker := Ticker{Users: []User{user}, Active: false, Title: "title", Websites: []TickerWebsite{{Origin: "http://localhost"}}}
+	err = s.db.Create(&ticker).Error
+	s.NoError(err)
+
+	s.Run("when tickers exist", func() {
+		filter := TickerFilter{OrderBy: "id", Sort: "desc"}
+		tickers, err := s.store.FindTickersByUser(user, filter)
+		s.NoError(err)
+		s.Len(tickers, 1)
+	})
+
+	s.Run("when tickers exist with preload", func() {
+		filter := TickerFilter{OrderBy: "id", Sort: "desc"}
+		tickers, err := s.store.FindTickersByUser(user, filter, WithPreload())
+		s.NoError(err)
+		s.Len(tickers, 1)
 		s.Len(tickers[0].Users, 1)
 	})
 
@@ -707,13 +1285,14 @@ func (s *SqlStorageTestSuite) TestSaveTicker() {
 
 func (s *SqlStorageTestSuite) TestDeleteIntegrations() {
 	s.Run("when ticker is resetting integrations", func() {
-		var telegramCount, mastodonCount, blueskyCount, signalGroupCount int64
+		var telegramCount, mastodonCount, blueskyCount, signalGroupCount, matrixCount int64
 
 		ticker := Ticker{}
 		ticker.Telegram = TickerTelegram{Active: true, ChannelName: "channel"}
 		ticker.Mastodon = TickerMastodon{Active: true, Server: "server", Token: "token", AccessToken: "access_token"}
 		ticker.Bluesky = TickerBluesky{Active: true, AppKey: "app_key"}
 		ticker.SignalGroup = TickerSignalGroup{Active: true, GroupID: "group_id", GroupInviteLink: "group_invite_link"}
+		ticker.Matrix = TickerMatrix{Active: true, Homeserver: "https://matrix.example.org", AccessToken: "access_token", RoomID: "!room:example.org"}
 
 		err := s.store.SaveTicker(&ticker)
 		s.NoError(err)
@@ -722,11 +1301,13 @@ func (s *SqlStorageTestSuite) TestDeleteIntegrations() {
 		s.store.DB.Model(&TickerMastodon{}).Where("ticker_id = ?", ticker.ID).Count(&mastodonCount)
 		s.store.DB.Model(&TickerBluesky{}).Where("ticker_id = ?", ticker.ID).Count(&blueskyCount)
 		s.store.DB.Model(&TickerSignalGroup{}).Where("ticker_id = ?", ticker.ID).Count(&signalGroupCount)
+		s.store.DB.Model(&TickerMatrix{}).Where("ticker_id = ?", ticker.ID).Count(&matrixCount)
 
 		s.Equal(int64(1), telegramCount)
 		s.Equal(int64(1), mastodonCount)
 		s.Equal(int64(1), blueskyCount)
 		s.Equal(int64(1), signalGroupCount)
+		s.Equal(int64(1), matrixCount)
 
 		s.True(ticker.Telegram.Active)
 		s.Equal("channel", ticker.Telegram.ChannelName)
@@ -754,15 +1335,19 @@ func (s *SqlStorageTestSuite) TestDeleteIntegrations() {
 		s.False(ticker.SignalGroup.Active)
 		s.Empty(ticker.SignalGroup.GroupID)
 		s.Empty(ticker.SignalGroup.GroupInviteLink)
+		s.False(ticker.Matrix.Active)
+		s.Empty(ticker.Matrix.AccessToken)
 
 		s.store.DB.Model(&TickerTelegram{}).Where("ticker_id = ?", ticker.ID).Count(&telegramCount)
 		s.store.DB.Model(&TickerMastodon{}).Where("ticker_id = ?", ticker.ID).Count(&mastodonCount)
 		s.store.DB.Model(&TickerBluesky{}).Where("ticker_id = ?", ticker.ID).Count(&blueskyCount)
 		s.store.DB.Model(&TickerSignalGroup{}).Where("ticker_id = ?", ticker.ID).Count(&signalGroupCount)
+		s.store.DB.Model(&TickerMatrix{}).Where("ticker_id = ?", ticker.ID).Count(&matrixCount)
 		s.Equal(int64(0), telegramCount)
 		s.Equal(int64(0), mastodonCount)
 		s.Equal(int64(0), blueskyCount)
 		s.Equal(int64(0), signalGroupCount)
+		s.Equal(int64(0), matrixCount)
 	})
 }
 
@@ -1034,6 +1619,97 @@ func (s *SqlStorageTestSuite) TestSaveTickerWebsites() {
 	})
 }
 
+func (s *SqlStorageTestSuite) TestSaveTickerWebhooks() {
+	ticker := Ticker{}
+	err := s.db.Create(&ticker).Error
+	s.NoError(err)
+
+	s.Run("when webhooks are new", func() {
+		err = s.store.SaveTickerWebhooks(&ticker, []TickerWebhook{
+			{URL: "https://hooks.example.org/one", Secret: "one"},
+			{URL: "https://hooks.example.org/two", Secret: "two"},
+		})
+		s.NoError(err)
+		s.Len(ticker.Webhooks, 2)
+		s.NotZero(ticker.Webhooks[0].ID)
+
+		found, err := s.store.FindTickerByID(ticker.ID, WithPreload())
+		s.NoError(err)
+		s.Len(found.Webhooks, 2)
+	})
+
+	s.Run("when a webhook is updated and one removed", func() {
+		updated := ticker.Webhooks[1]
+		updated.URL = "https://hooks.example.org/updated"
+		err = s.store.SaveTickerWebhooks(&ticker, []TickerWebhook{updated})
+		s.NoError(err)
+
+		var webhooks []TickerWebhook
+		s.NoError(s.db.Where("ticker_id = ?", ticker.ID).Find(&webhooks).Error)
+		s.Len(webhooks, 1)
+		s.Equal(updated.ID, webhooks[0].ID)
+		s.Equal("https://hooks.example.org/updated", webhooks[0].URL)
+		s.Equal("two", webhooks[0].Secret)
+	})
+
+	s.Run("when webhooks are deleted", func() {
+		err = s.store.DeleteTickerWebhooks(&ticker)
+		s.NoError(err)
+		s.Empty(ticker.Webhooks)
+
+		var count int64
+		s.NoError(s.db.Model(&TickerWebhook{}).Where("ticker_id = ?", ticker.ID).Count(&count).Error)
+		s.Equal(int64(0), count)
+	})
+}
+
+func (s *SqlStorageTestSuite) TestSaveTickerSignalSenders() {
+	ticker := Ticker{}
+	err := s.db.Create(&ticker).Error
+	s.NoError(err)
+
+	s.Run("when senders are new", func() {
+		err = s.store.SaveTickerSignalSenders(&ticker, []TickerSignalSender{
+			{Number: "+4915112345678", Name: "Reporter"},
+			{Number: "+4915187654321"},
+		})
+		s.NoError(err)
+		s.Len(ticker.SignalSenders, 2)
+
+		found, err := s.store.FindTickerByID(ticker.ID, WithPreload())
+		s.NoError(err)
+		s.Len(found.SignalSenders, 2)
+	})
+
+	s.Run("when sender is found by number", func() {
+		sender, err := s.store.FindTickerSignalSenderByNumber("+4915112345678")
+		s.NoError(err)
+		s.Equal(ticker.ID, sender.TickerID)
+		s.Equal("Reporter", sender.Name)
+
+		_, err = s.store.FindTickerSignalSenderByNumber("+4900000000000")
+		s.Error(err)
+	})
+
+	s.Run("when number belongs to another ticker", func() {
+		other := Ticker{}
+		s.NoError(s.db.Create(&other).Error)
+
+		err = s.store.SaveTickerSignalSenders(&other, []TickerSignalSender{{Number: "+4915112345678"}})
+		s.Error(err)
+	})
+
+	s.Run("when senders are deleted", func() {
+		err = s.store.DeleteTickerSignalSenders(&ticker)
+		s.NoError(err)
+		s.Empty(ticker.SignalSenders)
+
+		var count int64
+		s.NoError(s.db.Model(&TickerSignalSender{}).Where("ticker_id = ?", ticker.ID).Count(&count).Error)
+		s.Equal(int64(0), count)
+	})
+}
+
 func (s *SqlStorageTestSuite) TestFindUploadByUUID() {
 	s.Run("when upload does not exist", func() {
 		_, err := s.store.FindUploadByUUID("uuid")
@@ -1234,75 +1910,566 @@ func (s *SqlStorageTestSuite) TestFindMessagesByTicker() {
 
 		messages, err := s.store.FindMessagesByTicker(ticker, WithAttachments())
 		s.NoError(err)
-		s.Len(messages, 1)
+		s.Len(messages, 1)
+
+		s.Len(messages[0].Attachments, 1)
+		s.Equal("uuid", messages[0].Attachments[0].UUID)
+	})
+}
+
+func (s *SqlStorageTestSuite) TestFindMessagesByTickerAndPagination() {
+	ticker := Ticker{ID: 1}
+	err := s.db.Create(&ticker).Error
+	s.NoError(err)
+
+	s.Run("when no messages exist", func() {
+		p := pagination.NewPagination(&gin.Context{})
+		messages, err := s.store.FindMessagesByTickerAndPagination(ticker, *p)
+		s.NoError(err)
+		s.Empty(messages)
+	})
+
+	err = s.db.Create(&[]Message{
+		{TickerID: ticker.ID, ID: 1, Attachments: []Attachment{{ID: 1, MessageID: 1, UUID: "uuid", ContentType: "image/jpg", Extension: "jpg"}}},
+		{TickerID: ticker.ID, ID: 2, Attachments: []Attachment{{ID: 2, MessageID: 2, UUID: "uuid", ContentType: "image/jpg", Extension: "jpg"}}},
+		{TickerID: ticker.ID, ID: 3, Attachments: []Attachment{{ID: 3, MessageID: 3, UUID: "uuid", ContentType: "image/jpg", Extension: "jpg"}}},
+		{TickerID: ticker.ID, ID: 4, Attachments: []Attachment{{ID: 4, MessageID: 4, UUID: "uuid", ContentType: "image/jpg", Extension: "jpg"}}},
+	}).Error
+	s.NoError(err)
+
+	s.Run("when messages exist with attachments", func() {
+		p := pagination.NewPagination(&gin.Context{})
+		messages, err := s.store.FindMessagesByTickerAndPagination(ticker, *p, WithAttachments())
+		s.NoError(err)
+		s.Len(messages, 4)
+		s.Equal("uuid", messages[0].Attachments[0].UUID)
+		s.Equal("uuid", messages[1].Attachments[0].UUID)
+		s.Equal("uuid", messages[2].Attachments[0].UUID)
+		s.Equal("uuid", messages[3].Attachments[0].UUID)
+	})
+
+	s.Run("when messages exist with limit set", func() {
+		c := &gin.Context{}
+		c.Request = &http.Request{URL: &url.URL{RawQuery: "limit=2"}}
+		p := pagination.NewPagination(c)
+		messages, err := s.store.FindMessagesByTickerAndPagination(ticker, *p)
+		s.NoError(err)
+		s.Len(messages, 2)
+		s.Equal(4, messages[0].ID)
+		s.Equal(3, messages[1].ID)
+	})
+
+	s.Run("when messages exist with limit and after set", func() {
+		c := &gin.Context{}
+		c.Request = &http.Request{URL: &url.URL{RawQuery: "limit=2&after=2"}}
+		p := pagination.NewPagination(c)
+		messages, err := s.store.FindMessagesByTickerAndPagination(ticker, *p)
+		s.NoError(err)
+		s.Len(messages, 2)
+		s.Equal(4, messages[0].ID)
+		s.Equal(3, messages[1].ID)
+	})
+
+	s.Run("when messages exist with limit and before set", func() {
+		c := &gin.Context{}
+		c.Request = &http.Request{URL: &url.URL{RawQuery: "limit=2&before=4"}}
+		p := pagination.NewPagination(c)
+		messages, err := s.store.FindMessagesByTickerAndPagination(ticker, *p)
+		s.NoError(err)
+		s.Len(messages, 2)
+		s.Equal(3, messages[0].ID)
+		s.Equal(2, messages[1].ID)
+	})
+
+	s.Run("when scheduled messages and drafts exist", func() {
+		publishAt := time.Now().Add(time.Hour)
+		err := s.db.Create(&Message{TickerID: ticker.ID, ID: 5, PublishAt: &publishAt}).Error
+		s.NoError(err)
+		err = s.db.Create(&Message{TickerID: ticker.ID, ID: 6, Draft: true}).Error
+		s.NoError(err)
+
+		p := pagination.NewPagination(&gin.Context{})
+		messages, err := s.store.FindMessagesByTickerAndPagination(ticker, *p)
+		s.NoError(err)
+		s.Len(messages, 4)
+		s.Equal(4, messages[0].ID)
+	})
+
+	s.Run("when a draft and a scheduled message are published", func() {
+		err := s.store.PublishMessage(&Message{ID: 6})
+		s.NoError(err)
+		err = s.store.PublishMessage(&Message{ID: 5})
+		s.NoError(err)
+
+		c := &gin.Context{}
+		c.Request = &http.Request{URL: &url.URL{RawQuery: "after=6"}}
+		messages, err := s.store.FindMessagesByTickerAndPagination(ticker, *pagination.NewPagination(c))
+		s.NoError(err)
+		s.Len(messages, 1)
+		s.Equal(5, messages[0].ID)
+
+		c = &gin.Context{}
+		c.Request = &http.Request{URL: &url.URL{RawQuery: "limit=2&before=5"}}
+		messages, err = s.store.FindMessagesByTickerAndPagination(ticker, *pagination.NewPagination(c))
+		s.NoError(err)
+		s.Len(messages, 2)
+		s.Equal(6, messages[0].ID)
+		s.Equal(4, messages[1].ID)
+
+		c = &gin.Context{}
+		c.Request = &http.Request{URL: &url.URL{RawQuery: "after=5"}}
+		messages, err = s.store.FindMessagesByTickerAndPagination(ticker, *pagination.NewPagination(c))
+		s.NoError(err)
+		s.Empty(messages)
+	})
+}
+
+func (s *SqlStorageTestSuite) TestMessageGeometry() {
+	ticker := Ticker{ID: 1}
+	s.NoError(s.db.Create(&ticker).Error)
+
+	withGeometry := Message{TickerID: ticker.ID, Text: "Kettle", Geometry: &Geometry{Type: GeometryPoint, Coordinates: []byte(`[13.4,52.5]`)}}
+	withoutGeometry := Message{TickerID: ticker.ID, Text: "No location"}
+	s.NoError(s.store.SaveMessage(&withGeometry))
+	s.NoError(s.store.SaveMessage(&withoutGeometry))
+
+	p := pagination.NewPagination(&gin.Context{})
+
+	s.Run("when finding messages with geometry", func() {
+		messages, err := s.store.FindMessagesByTickerAndPagination(ticker, *p, WithGeometry())
+		s.NoError(err)
+		s.Len(messages, 1)
+		s.Equal(GeometryPoint, messages[0].Geometry.Type)
+		s.JSONEq(`[13.4,52.5]`, string(messages[0].Geometry.Coordinates))
+	})
+
+	s.Run("when geometry is removed", func() {
+		withGeometry.Geometry = nil
+		s.NoError(s.store.SaveMessage(&withGeometry))
+
+		messages, err := s.store.FindMessagesByTickerAndPagination(ticker, *p, WithGeometry())
+		s.NoError(err)
+		s.Empty(messages)
+	})
+}
+
+func (s *SqlStorageTestSuite) TestMessageBridges() {
+	all := Message{TickerID: 1, Text: "all"}
+	none := Message{TickerID: 1, Text: "none", Bridges: []string{}}
+	signal := Message{TickerID: 1, Text: "signal", Bridges: []string{"signalGroup"}}
+	s.NoError(s.store.SaveMessage(&all))
+	s.NoError(s.store.SaveMessage(&none))
+	s.NoError(s.store.SaveMessage(&signal))
+
+	found, err := s.store.FindMessage(1, all.ID)
+	s.NoError(err)
+	s.Nil(found.Bridges)
+
+	found, err = s.store.FindMessage(1, none.ID)
+	s.NoError(err)
+	s.NotNil(found.Bridges)
+	s.Empty(found.Bridges)
+
+	found, err = s.store.FindMessage(1, signal.ID)
+	s.NoError(err)
+	s.Equal([]string{"signalGroup"}, found.Bridges)
+
+	signal.Bridges = nil
+	s.NoError(s.store.SaveMessage(&signal))
+	found, err = s.store.FindMessage(1, signal.ID)
+	s.NoError(err)
+	s.Nil(found.Bridges)
+}
+
+func (s *SqlStorageTestSuite) TestOutbox() {
+	message := Message{ID: 1, TickerID: 1, Text: "text"}
+	s.NoError(s.db.Create(&message).Error)
+
+	s.Run("CreateOutboxJobs", func() {
+		s.NoError(s.store.CreateOutboxJobs(message, nil))
+		s.NoError(s.store.CreateOutboxJobs(message, []string{"mastodon", "telegram"}))
+
+		var count int64
+		s.NoError(s.db.Model(&OutboxJob{}).Where("status = ?", OutboxPending).Count(&count).Error)
+		s.Equal(int64(2), count)
+	})
+
+	s.Run("CreateOutboxEditJobs", func() {
+		s.NoError(s.store.CreateOutboxEditJobs(message, nil))
+
+		found, err := s.store.FindMessage(1, 1, WithDeliveries())
+		s.NoError(err)
+		s.Len(found.Deliveries, 2)
+		s.Equal(OutboxSend, found.Deliveries[0].Action)
+	})
+
+	s.Run("ClaimOutboxJobs", func() {
+		now := time.Now().Add(time.Second)
+		jobs, err := s.store.ClaimOutboxJobs(now, 1, time.Minute)
+		s.NoError(err)
+		s.Len(jobs, 1)
+		s.Equal("mastodon", jobs[0].Bridge)
+
+		jobs, err = s.store.ClaimOutboxJobs(now, 10, time.Minute)
+		s.NoError(err)
+		s.Len(jobs, 1)
+		s.Equal("telegram", jobs[0].Bridge)
+
+		jobs, err = s.store.ClaimOutboxJobs(now, 10, time.Minute)
+		s.NoError(err)
+		s.Empty(jobs)
+
+		jobs, err = s.store.ClaimOutboxJobs(now.Add(2*time.Minute), 10, time.Minute)
+		s.NoError(err)
+		s.Len(jobs, 2)
+	})
+
+	s.Run("SaveOutboxJob", func() {
+		var job OutboxJob
+		s.NoError(s.db.First(&job, "bridge = ?", "telegram").Error)
+		job.Status = OutboxFailed
+		job.LastError = "timeout"
+		s.NoError(s.store.SaveOutboxJob(&job))
+
+		found, err := s.store.FindMessage(1, 1, WithDeliveries())
+		s.NoError(err)
+		s.Len(found.Deliveries, 2)
+		s.Equal("mastodon", found.Deliveries[0].Bridge)
+		s.Equal(OutboxFailed, found.Deliveries[1].Status)
+		s.Equal("timeout", found.Deliveries[1].LastError)
+	})
+
+	s.Run("SaveMessageBridgeMeta", func() {
+		other := message
+		other.Text = "changed"
+		other.Mastodon = MastodonMeta{ID: "1"}
+		other.Bluesky = BlueskyMeta{Uri: "at://uri"}
+		s.NoError(s.store.SaveMessageBridgeMeta(&other, "mastodon"))
+		s.NoError(s.store.SaveMessageBridgeMeta(&other, "unknown"))
+
+		found, err := s.store.FindMessage(1, 1)
+		s.NoError(err)
+		s.Equal("text", found.Text)
+		s.Equal("1", found.Mastodon.ID)
+		s.Empty(found.Bluesky.Uri)
+	})
+
+	s.Run("when an edit is queued", func() {
+		s.NoError(s.store.CreateOutboxEditJobs(message, []string{"mastodon"}))
+
+		var job OutboxJob
+		s.NoError(s.db.Last(&job).Error)
+		s.True(job.IsEdit())
+		s.Equal(OutboxPending, job.Status)
+	})
+
+	s.Run("DeleteMessage", func() {
+		var before int64
+		s.NoError(s.db.Model(&OutboxJob{}).Count(&before).Error)
+
+		s.NoError(s.store.DeleteMessage(message, []string{"mastodon"}))
+
+		var count int64
+		s.NoError(s.db.Model(&OutboxJob{}).Count(&count).Error)
+		s.Equal(before+1, count)
+
+		var job OutboxJob
+		s.NoError(s.db.Last(&job).Error)
+		s.True(job.IsDelete())
+		s.Equal("mastodon", job.Bridge)
+		s.Equal(OutboxPending, job.Status)
+
+		_, err := s.store.FindMessage(1, 1)
+		s.ErrorIs(err, gorm.ErrRecordNotFound)
+
+		other := message
+		other.Mastodon = MastodonMeta{ID: "2"}
+		s.NoError(s.store.SaveMessageBridgeMeta(&other, "mastodon"))
+
+		found, err := s.store.FindMessage(1, 1, WithDeleted(), WithDeliveries())
+		s.NoError(err)
+		s.Empty(found.Text)
+		s.Equal("2", found.Mastodon.ID)
+		s.Len(found.Deliveries, int(count))
+	})
+}
+
+func (s *SqlStorageTestSuite) TestMessageTags() {
+	ticker := Ticker{ID: 1}
+	err := s.db.Create(&ticker).Error
+	s.NoError(err)
+
+	messages := []Message{
+		{TickerID: ticker.ID, ID: 1, Text: "first"},
+		{TickerID: ticker.ID, ID: 2, Text: "second"},
+		{TickerID: ticker.ID, ID: 3, Text: "draft", Draft: true},
+		{TickerID: 2, ID: 4, Text: "other ticker"},
+	}
+	s.NoError(s.db.Create(&messages).Error)
+
+	s.Run("SaveMessageTags", func() {
+		s.NoError(s.store.SaveMessageTags(&messages[0], []string{"police", "traffic"}))
+		s.NoError(s.store.SaveMessageTags(&messages[0], []string{"police"}))
+		s.Equal([]string{"police"}, messages[0].TagNames())
+
+		s.NoError(s.store.SaveMessageTags(&messages[1], []string{"police", "water"}))
+		s.NoError(s.store.SaveMessageTags(&messages[2], []string{"police"}))
+		s.NoError(s.store.SaveMessageTags(&messages[3], []string{"police"}))
+
+		var count int64
+		s.NoError(s.db.Model(&MessageTag{}).Count(&count).Error)
+		s.Equal(int64(5), count)
+	})
+
+	s.Run("FindTagsByTicker", func() {
+		tags, err := s.store.FindTagsByTicker(ticker)
+		s.NoError(err)
+		s.Equal([]TagCount{{Name: "police", Count: 2}, {Name: "water", Count: 1}}, tags)
+	})
+
+	s.Run("WithTag", func() {
+		p := pagination.NewPagination(&gin.Context{})
+		result, err := s.store.FindMessagesByTickerAndPagination(ticker, *p, WithTag("water"), WithTags())
+		s.NoError(err)
+		s.Len(result, 1)
+		s.Equal(2, result[0].ID)
+		s.Equal([]string{"police", "water"}, result[0].TagNames())
+	})
+
+	s.Run("DeleteMessage", func() {
+		s.NoError(s.store.DeleteMessage(messages[1], nil))
+
+		var count int64
+		s.NoError(s.db.Model(&MessageTag{}).Where("message_id = ?", 2).Count(&count).Error)
+		s.Equal(int64(0), count)
+	})
+}
+
+func (s *SqlStorageTestSuite) TestSearchMessagesByTicker() {
+	ticker := Ticker{ID: 1}
+	err := s.db.Create(&ticker).Error
+	s.NoError(err)
+
+	err = s.db.Create(&[]Message{
+		{TickerID: ticker.ID, ID: 1, Text: "The water station moved to the main square"},
+		{TickerID: ticker.ID, ID: 2, Text: "Police is blocking the bridge"},
+		{TickerID: ticker.ID, ID: 3, Text: "Water is available at the station", Draft: true},
+		{TickerID: ticker.ID, ID: 4, Text: "Free water at the station behind the stage"},
+		{TickerID: 2, ID: 5, Text: "Water station in another ticker"},
+	}).Error
+	s.NoError(err)
+
+	p := pagination.NewPagination(&gin.Context{})
+
+	s.Run("when query is empty", func() {
+		messages, err := s.store.SearchMessagesByTicker(ticker, "  ", *p)
+		s.NoError(err)
+		s.Empty(messages)
+	})
+
+	s.Run("when all words match", func() {
+		messages, err := s.store.SearchMessagesByTicker(ticker, "water station", *p)
+		s.NoError(err)
+		s.Len(messages, 2)
+		s.Equal(4, messages[0].ID)
+		s.Equal(1, messages[1].ID)
+	})
+
+	s.Run("when a word does not match", func() {
+		messages, err := s.store.SearchMessagesByTicker(ticker, "water police", *p)
+		s.NoError(err)
+		s.Empty(messages)
+	})
+
+	s.Run("when query has a word prefix", func() {
+		messages, err := s.store.SearchMessagesByTicker(ticker, "poli", *p)
+		s.NoError(err)
+		s.Len(messages, 1)
+		s.Equal(2, messages[0].ID)
+	})
+
+	s.Run("when query contains quotes", func() {
+		_, err := s.store.SearchMessagesByTicker(ticker, `"water OR`, *p)
+		s.NoError(err)
+	})
+
+	s.Run("when message text is changed", func() {
+		err := s.store.SaveMessage(&Message{ID: 2, TickerID: ticker.ID, Text: "The bridge is open again"})
+		s.NoError(err)
+
+		messages, err := s.store.SearchMessagesByTicker(ticker, "police", *p)
+		s.NoError(err)
+		s.Empty(messages)
+
+		messages, err = s.store.SearchMessagesByTicker(ticker, "bridge", *p)
+		s.NoError(err)
+		s.Len(messages, 1)
+	})
+
+	s.Run("with pagination", func() {
+		c := &gin.Context{}
+		c.Request = &http.Request{URL: &url.URL{RawQuery: "limit=1&before=4"}}
+		messages, err := s.store.SearchMessagesByTicker(ticker, "water", *pagination.NewPagination(c))
+		s.NoError(err)
+		s.Len(messages, 1)
+		s.Equal(1, messages[0].ID)
+	})
+}
+
+func (s *SqlStorageTestSuite) TestScheduledMessages() {
+	ticker := Ticker{ID: 1}
+	err := s.db.Create(&ticker).Error
+	s.NoError(err)
+
+	past := time.Now().Add(-time.Minute)
+	future := time.Now().Add(time.Hour)
+	err = s.db.Create(&[]Message{
+		{TickerID: ticker.ID, ID: 1},
+		{TickerID: ticker.ID, ID: 2, PublishAt: &future},
+		{TickerID: ticker.ID, ID: 3, PublishAt: &past},
+		{TickerID: ticker.ID, ID: 4, PublishAt: &past, Draft: true},
+	}).Error
+	s.NoError(err)
+
+	s.Run("find scheduled messages by ticker", func() {
+		messages, err := s.store.FindScheduledMessagesByTicker(ticker)
+		s.NoError(err)
+		s.Len(messages, 2)
+		s.Equal(3, messages[0].ID)
+		s.Equal(2, messages[1].ID)
+	})
+
+	s.Run("find due messages", func() {
+		messages, err := s.store.FindDueMessages(time.Now())
+		s.NoError(err)
+		s.Len(messages, 1)
+		s.Equal(3, messages[0].ID)
+	})
+
+	s.Run("publish message", func() {
+		message := Message{ID: 3, TickerID: ticker.ID, PublishAt: &past}
+		err := s.store.PublishMessage(&message)
+		s.NoError(err)
+		s.False(message.IsScheduled())
+
+		var published Message
+		err = s.db.First(&published, 3).Error
+		s.NoError(err)
+		s.Nil(published.PublishAt)
+		s.WithinDuration(time.Now(), published.CreatedAt, time.Minute)
+	})
+
+	s.Run("publish message twice", func() {
+		message := Message{ID: 3, TickerID: ticker.ID, PublishAt: &past}
+		err := s.store.PublishMessage(&message)
+		s.ErrorIs(err, gorm.ErrRecordNotFound)
+	})
+}
+
+func (s *SqlStorageTestSuite) TestFindPendingReviewMessagesByTicker() {
+	ticker := Ticker{ID: 1}
+	err := s.db.Create(&ticker).Error
+	s.NoError(err)
+
+	err = s.db.Create(&[]Message{
+		{TickerID: ticker.ID, ID: 1, Draft: true},
+		{TickerID: ticker.ID, ID: 2, Draft: true, Review: ReviewPending},
+		{TickerID: ticker.ID, ID: 3, Draft: true, Review: ReviewRejected},
+	}).Error
+	s.NoError(err)
+
+	messages, err := s.store.FindPendingReviewMessagesByTicker(ticker)
+	s.NoError(err)
+	s.Len(messages, 1)
+	s.Equal(2, messages[0].ID)
+}
+
+func (s *SqlStorageTestSuite) TestDraftMessages() {
+	ticker := Ticker{ID: 1}
+	err := s.db.Create(&ticker).Error
+	s.NoError(err)
+
+	err = s.db.Create(&[]Message{
+		{TickerID: ticker.ID, ID: 1},
+		{TickerID: ticker.ID, ID: 2, Draft: true},
+	}).Error
+	s.NoError(err)
+
+	s.Run("find draft messages by ticker", func() {
+		messages, err := s.store.FindDraftMessagesByTicker(ticker)
+		s.NoError(err)
+		s.Len(messages, 1)
+		s.Equal(2, messages[0].ID)
+	})
+
+	s.Run("find draft messages by author", func() {
+		revision := NewMessageRevision(Message{ID: 2, TickerID: ticker.ID}, 3, RevisionCreated)
+		s.NoError(s.store.SaveMessageRevision(&revision))
+
+		messages, err := s.store.FindDraftMessagesByTicker(ticker, WithAuthor(3))
+		s.NoError(err)
+		s.Len(messages, 1)
+
+		messages, err = s.store.FindDraftMessagesByTicker(ticker, WithAuthor(4))
+		s.NoError(err)
+		s.Empty(messages)
+	})
+
+	s.Run("publish draft", func() {
+		message := Message{ID: 2, TickerID: ticker.ID, Draft: true}
+		err := s.store.PublishMessage(&message)
+		s.NoError(err)
+		s.True(message.IsPublished())
+
+		messages, err := s.store.FindDraftMessagesByTicker(ticker)
+		s.NoError(err)
+		s.Empty(messages)
+	})
 
-		s.Len(messages[0].Attachments, 1)
-		s.Equal("uuid", messages[0].Attachments[0].UUID)
+	s.Run("publish published message", func() {
+		message := Message{ID: 1, TickerID: ticker.ID}
+		err := s.store.PublishMessage(&message)
+		s.ErrorIs(err, gorm.ErrRecordNotFound)
 	})
 }
 
-func (s *SqlStorageTestSuite) TestFindMessagesByTickerAndPagination() {
+func (s *SqlStorageTestSuite) TestPinnedMessages() {
 	ticker := Ticker{ID: 1}
 	err := s.db.Create(&ticker).Error
 	s.NoError(err)
 
-	s.Run("when no messages exist", func() {
-		p := pagination.NewPagination(&gin.Context{})
-		messages, err := s.store.FindMessagesByTickerAndPagination(ticker, *p)
-		s.NoError(err)
-		s.Empty(messages)
-	})
-
 	err = s.db.Create(&[]Message{
-		{TickerID: ticker.ID, ID: 1, Attachments: []Attachment{{ID: 1, MessageID: 1, UUID: "uuid", ContentType: "image/jpg", Extension: "jpg"}}},
-		{TickerID: ticker.ID, ID: 2, Attachments: []Attachment{{ID: 2, MessageID: 2, UUID: "uuid", ContentType: "image/jpg", Extension: "jpg"}}},
-		{TickerID: ticker.ID, ID: 3, Attachments: []Attachment{{ID: 3, MessageID: 3, UUID: "uuid", ContentType: "image/jpg", Extension: "jpg"}}},
-		{TickerID: ticker.ID, ID: 4, Attachments: []Attachment{{ID: 4, MessageID: 4, UUID: "uuid", ContentType: "image/jpg", Extension: "jpg"}}},
+		{TickerID: ticker.ID, ID: 1},
+		{TickerID: ticker.ID, ID: 2, Pinned: true},
+		{TickerID: ticker.ID, ID: 3, Pinned: true, Draft: true},
+		{TickerID: ticker.ID, ID: 4, Pinned: true},
 	}).Error
 	s.NoError(err)
 
-	s.Run("when messages exist with attachments", func() {
-		p := pagination.NewPagination(&gin.Context{})
-		messages, err := s.store.FindMessagesByTickerAndPagination(ticker, *p, WithAttachments())
-		s.NoError(err)
-		s.Len(messages, 4)
-		s.Equal("uuid", messages[0].Attachments[0].UUID)
-		s.Equal("uuid", messages[1].Attachments[0].UUID)
-		s.Equal("uuid", messages[2].Attachments[0].UUID)
-		s.Equal("uuid", messages[3].Attachments[0].UUID)
-	})
-
-	s.Run("when messages exist with limit set", func() {
-		c := &gin.Context{}
-		c.Request = &http.Request{URL: &url.URL{RawQuery: "limit=2"}}
-		p := pagination.NewPagination(c)
-		messages, err := s.store.FindMessagesByTickerAndPagination(ticker, *p)
+	s.Run("find pinned messages by ticker", func() {
+		messages, err := s.store.FindPinnedMessagesByTicker(ticker)
 		s.NoError(err)
 		s.Len(messages, 2)
 		s.Equal(4, messages[0].ID)
-		s.Equal(3, messages[1].ID)
+		s.Equal(2, messages[1].ID)
 	})
 
-	s.Run("when messages exist with limit and after set", func() {
-		c := &gin.Context{}
-		c.Request = &http.Request{URL: &url.URL{RawQuery: "limit=2&after=2"}}
-		p := pagination.NewPagination(c)
-		messages, err := s.store.FindMessagesByTickerAndPagination(ticker, *p)
+	s.Run("find messages without pinned", func() {
+		p := pagination.NewPagination(&gin.Context{})
+		messages, err := s.store.FindMessagesByTickerAndPagination(ticker, *p, WithoutPinned())
 		s.NoError(err)
-		s.Len(messages, 2)
-		s.Equal(4, messages[0].ID)
-		s.Equal(3, messages[1].ID)
+		s.Len(messages, 1)
+		s.Equal(1, messages[0].ID)
 	})
 
-	s.Run("when messages exist with limit and before set", func() {
-		c := &gin.Context{}
-		c.Request = &http.Request{URL: &url.URL{RawQuery: "limit=2&before=4"}}
-		p := pagination.NewPagination(c)
-		messages, err := s.store.FindMessagesByTickerAndPagination(ticker, *p)
+	s.Run("unpin message", func() {
+		message := Message{ID: 2, TickerID: ticker.ID}
+		err := s.store.SaveMessage(&message)
 		s.NoError(err)
-		s.Len(messages, 2)
-		s.Equal(3, messages[0].ID)
-		s.Equal(2, messages[1].ID)
+
+		messages, err := s.store.FindPinnedMessagesByTicker(ticker)
+		s.NoError(err)
+		s.Len(messages, 1)
 	})
 }
 
@@ -1338,12 +2505,26 @@ func (s *SqlStorageTestSuite) TestSaveMessage() {
 		s.NoError(err)
 		s.Equal(int64(2), count)
 	})
+
+	s.Run("when the outbox saved bridge metadata meanwhile", func() {
+		delivered := message
+		delivered.Mastodon = MastodonMeta{ID: "1"}
+		s.NoError(s.store.SaveMessageBridgeMeta(&delivered, "mastodon"))
+
+		message.Text = "typo fixed"
+		s.NoError(s.store.SaveMessage(&message))
+
+		found, err := s.store.FindMessage(1, message.ID)
+		s.NoError(err)
+		s.Equal("typo fixed", found.Text)
+		s.Equal("1", found.Mastodon.ID)
+	})
 }
 
 func (s *SqlStorageTestSuite) TestDeleteMessage() {
 	s.Run("when message does not exist", func() {
 		message := Message{ID: 1}
-		err := s.store.DeleteMessage(message)
+		err := s.store.DeleteMessage(message, nil)
 		s.NoError(err)
 	})
 
@@ -1352,7 +2533,11 @@ func (s *SqlStorageTestSuite) TestDeleteMessage() {
 		err := s.db.Create(&message).Error
 		s.NoError(err)
 
-		err = s.store.DeleteMessage(message)
+		revision := NewMessageRevision(message, 1, RevisionCreated)
+		err = s.store.SaveMessageRevision(&revision)
+		s.NoError(err)
+
+		err = s.store.DeleteMessage(message, nil)
 		s.NoError(err)
 
 		var count int64
@@ -1363,6 +2548,10 @@ func (s *SqlStorageTestSuite) TestDeleteMessage() {
 		err = s.db.Model(&Attachment{}).Count(&count).Error
 		s.NoError(err)
 		s.Equal(int64(0), count)
+
+		err = s.db.Model(&MessageRevision{}).Count(&count).Error
+		s.NoError(err)
+		s.Equal(int64(1), count)
 	})
 }
 
@@ -1381,7 +2570,11 @@ func (s *SqlStorageTestSuite) TestDeleteMessages() {
 	})
 
 	s.Run("when messages exist", func() {
-		err := s.store.DeleteMessages(ticker)
+		revision := NewMessageRevision(message, 1, RevisionCreated)
+		err := s.store.SaveMessageRevision(&revision)
+		s.NoError(err)
+
+		err = s.store.DeleteMessages(ticker)
 		s.NoError(err)
 
 		var count int64
@@ -1392,6 +2585,57 @@ func (s *SqlStorageTestSuite) TestDeleteMessages() {
 		err = s.db.Model(&Attachment{}).Count(&count).Error
 		s.NoError(err)
 		s.Equal(int64(0), count)
+
+		err = s.db.Model(&MessageRevision{}).Count(&count).Error
+		s.NoError(err)
+		s.Equal(int64(1), count)
+	})
+}
+
+func (s *SqlStorageTestSuite) TestMessageRevisions() {
+	message := Message{ID: 1, TickerID: 1, Text: "first", Attachments: []Attachment{{UUID: "uuid", ContentType: "image/jpg", Extension: "jpg"}}}
+
+	s.Run("when no revisions exist", func() {
+		revisions, err := s.store.FindMessageRevisions(message.TickerID, message.ID)
+		s.NoError(err)
+		s.Empty(revisions)
+	})
+
+	s.Run("when revisions exist", func() {
+		first := NewMessageRevision(message, 1, RevisionCreated)
+		err := s.store.SaveMessageRevision(&first)
+		s.NoError(err)
+
+		message.Text = "second"
+		second := NewMessageRevision(message, 2, RevisionEdited)
+		err = s.store.SaveMessageRevision(&second)
+		s.NoError(err)
+
+		revisions, err := s.store.FindMessageRevisions(message.TickerID, message.ID)
+		s.NoError(err)
+		s.Len(revisions, 2)
+		s.Equal("first", revisions[0].Text)
+		s.Equal(1, revisions[0].UserID)
+		s.Equal(RevisionCreated, revisions[0].Action)
+		s.Len(revisions[0].Attachments, 1)
+		s.Equal("second", revisions[1].Text)
+		s.Equal(2, revisions[1].UserID)
+		s.Equal(RevisionEdited, revisions[1].Action)
+	})
+
+	s.Run("when the message belongs to another ticker", func() {
+		revisions, err := s.store.FindMessageRevisions(2, message.ID)
+		s.NoError(err)
+		s.Empty(revisions)
+	})
+
+	s.Run("when the ticker is deleted", func() {
+		err := s.store.DeleteTicker(&Ticker{ID: message.TickerID})
+		s.NoError(err)
+
+		revisions, err := s.store.FindMessageRevisions(message.TickerID, message.ID)
+		s.NoError(err)
+		s.Empty(revisions)
 	})
 }
 
@@ -1606,6 +2850,316 @@ func (s *SqlStorageTestSuite) TestSaveSignalGroupSettings() {
 	})
 }
 
+func (s *SqlStorageTestSuite) TestWebPushSettings() {
+	s.Run("when no settings exist", func() {
+		settings := s.store.GetWebPushSettings()
+		s.False(settings.Enabled())
+	})
+
+	s.Run("when settings are saved", func() {
+		err := s.store.SaveWebPushSettings(WebPushSettings{PublicKey: "public", PrivateKey: "private"})
+		s.NoError(err)
+
+		settings := s.store.GetWebPushSettings()
+		s.True(settings.Enabled())
+		s.Equal("public", settings.PublicKey)
+		s.Equal("private", settings.PrivateKey)
+	})
+}
+
+func (s *SqlStorageTestSuite) TestSecuritySettings() {
+	s.Run("when no settings exist", func() {
+		settings := s.store.GetSecuritySettings()
+		s.False(settings.RequireTwoFactor)
+	})
+
+	s.Run("when settings are saved", func() {
+		err := s.store.SaveSecuritySettings(SecuritySettings{RequireTwoFactor: true})
+		s.NoError(err)
+
+		settings := s.store.GetSecuritySettings()
+		s.True(settings.RequireTwoFactor)
+	})
+}
+
+func (s *SqlStorageTestSuite) TestWebPushSubscriptions() {
+	ticker := Ticker{WebPush: TickerWebPush{Active: true}}
+	err := s.store.SaveTicker(&ticker)
+	s.NoError(err)
+
+	s.Run("when a browser subscribes", func() {
+		err := s.store.SaveWebPushSubscription(&WebPushSubscription{TickerID: ticker.ID, Endpoint: "https://push.example.org/1", P256dh: "key", Auth: "auth"})
+		s.NoError(err)
+
+		subscriptions, err := s.store.FindWebPushSubscriptions(ticker)
+		s.NoError(err)
+		s.Len(subscriptions, 1)
+	})
+
+	s.Run("when the browser subscribes again", func() {
+		err := s.store.SaveWebPushSubscription(&WebPushSubscription{TickerID: ticker.ID, Endpoint: "https://push.example.org/1", P256dh: "new-key", Auth: "new-auth"})
+		s.NoError(err)
+
+		subscriptions, err := s.store.FindWebPushSubscriptions(ticker)
+		s.NoError(err)
+		s.Len(subscriptions, 1)
+		s.Equal("new-key", subscriptions[0].P256dh)
+
+		count, err := s.store.CountWebPushSubscriptions(ticker)
+		s.NoError(err)
+		s.Equal(int64(1), count)
+		s.Equal("new-auth", subscriptions[0].Auth)
+	})
+
+	s.Run("when the browser unsubscribes", func() {
+		err := s.store.DeleteWebPushSubscription(ticker, "https://push.example.org/1")
+		s.NoError(err)
+
+		subscriptions, err := s.store.FindWebPushSubscriptions(ticker)
+		s.NoError(err)
+		s.Empty(subscriptions)
+	})
+
+	s.Run("when push is turned off", func() {
+		err := s.store.SaveWebPushSubscription(&WebPushSubscription{TickerID: ticker.ID, Endpoint: "https://push.example.org/2", P256dh: "key", Auth: "auth"})
+		s.NoError(err)
+
+		err = s.store.DeleteWebPush(&ticker)
+		s.NoError(err)
+		s.False(ticker.WebPush.Active)
+
+		subscriptions, err := s.store.FindWebPushSubscriptions(ticker)
+		s.NoError(err)
+		s.Empty(subscriptions)
+	})
+}
+
+func (s *SqlStorageTestSuite) TestEmailSubscriptions() {
+	ticker := Ticker{Email: TickerEmail{Active: true}}
+	err := s.store.SaveTicker(&ticker)
+	s.NoError(err)
+
+	subscription, err := NewEmailSubscription(ticker.ID, "reader@example.org")
+	s.NoError(err)
+	s.Len(subscription.Token, 64)
+
+	s.Run("when a reader subscribes", func() {
+		err := s.store.SaveEmailSubscription(&subscription)
+		s.NoError(err)
+
+		found, err := s.store.FindEmailSubscription(ticker, "reader@example.org")
+		s.NoError(err)
+		s.False(found.Confirmed())
+
+		subscriptions, err := s.store.FindConfirmedEmailSubscriptions(ticker)
+		s.NoError(err)
+		s.Empty(subscriptions)
+	})
+
+	s.Run("when the confirmation mail is claimed", func() {
+		claimed, err := s.store.ClaimEmailConfirmation(&subscription, time.Hour)
+		s.NoError(err)
+		s.True(claimed)
+		s.NotNil(subscription.ConfirmationSentAt)
+
+		claimed, err = s.store.ClaimEmailConfirmation(&subscription, time.Hour)
+		s.NoError(err)
+		s.False(claimed)
+	})
+
+	s.Run("when the reader confirms", func() {
+		found, err := s.store.FindEmailSubscriptionByToken(subscription.Token)
+		s.NoError(err)
+
+		now := time.Now()
+		found.ConfirmedAt = &now
+		err = s.store.SaveEmailSubscription(&found)
+		s.NoError(err)
+
+		subscriptions, err := s.store.FindConfirmedEmailSubscriptions(ticker)
+		s.NoError(err)
+		s.Len(subscriptions, 1)
+	})
+
+	s.Run("when the address subscribes twice", func() {
+		duplicate, err := NewEmailSubscription(ticker.ID, "reader@example.org")
+		s.NoError(err)
+
+		err = s.store.SaveEmailSubscription(&duplicate)
+		s.Error(err)
+	})
+
+	s.Run("when the reader unsubscribes", func() {
+		found, err := s.store.FindEmailSubscriptionByToken(subscription.Token)
+		s.NoError(err)
+
+		err = s.store.DeleteEmailSubscription(found)
+		s.NoError(err)
+
+		_, err = s.store.FindEmailSubscriptionByToken(subscription.Token)
+		s.Error(err)
+	})
+
+	s.Run("when email is turned off", func() {
+		other, err := NewEmailSubscription(ticker.ID, "other@example.org")
+		s.NoError(err)
+		err = s.store.SaveEmailSubscription(&other)
+		s.NoError(err)
+
+		err = s.store.DeleteEmail(&ticker)
+		s.NoError(err)
+		s.False(ticker.Email.Active)
+
+		_, err = s.store.FindEmailSubscription(ticker, "other@example.org")
+		s.Error(err)
+	})
+}
+
+func (s *SqlStorageTestSuite) TestMailbox() {
+	address, err := NewMailboxAddress("Ticker.example.org")
+	s.NoError(err)
+
+	ticker := Ticker{Mailbox: TickerMailbox{Active: true, Address: address}}
+	err = s.store.SaveTicker(&ticker)
+	s.NoError(err)
+
+	s.Run("when the address is known", func() {
+		found, err := s.store.FindTickerByMailboxAddress(strings.ToUpper(address), WithPreload())
+		s.NoError(err)
+		s.Equal(ticker.ID, found.ID)
+		s.Equal(address, found.Mailbox.Address)
+	})
+
+	s.Run("when the address is unknown", func() {
+		_, err := s.store.FindTickerByMailboxAddress("unknown@ticker.example.org")
+		s.Error(err)
+	})
+
+	s.Run("when the mailbox is inactive", func() {
+		ticker.Mailbox.Active = false
+		s.NoError(s.store.SaveTicker(&ticker))
+
+		_, err := s.store.FindTickerByMailboxAddress(address)
+		s.Error(err)
+	})
+
+	s.Run("when the mailbox is deleted", func() {
+		err := s.store.DeleteMailbox(&ticker)
+		s.NoError(err)
+		s.Empty(ticker.Mailbox.Address)
+
+		var count int64
+		s.NoError(s.db.Model(&TickerMailbox{}).Where("ticker_id = ?", ticker.ID).Count(&count).Error)
+		s.Zero(count)
+	})
+}
+
+func (s *SqlStorageTestSuite) TestEmailDigest() {
+	ticker := Ticker{Email: TickerEmail{Active: true, Digest: true}}
+	err := s.store.SaveTicker(&ticker)
+	s.NoError(err)
+
+	first := Message{TickerID: ticker.ID, Text: "First"}
+	second := Message{TickerID: ticker.ID, Text: "Second"}
+	s.NoError(s.store.SaveMessage(&first))
+	s.NoError(s.store.SaveMessage(&second))
+
+	s.NoError(s.store.SaveEmailDigestEntry(&EmailDigestEntry{TickerID: ticker.ID, MessageID: first.ID, CreatedAt: time.Now().Add(-2 * time.Hour)}))
+	s.NoError(s.store.SaveEmailDigestEntry(&EmailDigestEntry{TickerID: ticker.ID, MessageID: second.ID}))
+
+	s.Run("when the digest is not due", func() {
+		tickerIDs, err := s.store.FindDueEmailDigests(time.Now().Add(-3 * time.Hour))
+		s.NoError(err)
+		s.Empty(tickerIDs)
+	})
+
+	s.Run("when the digest is due", func() {
+		tickerIDs, err := s.store.FindDueEmailDigests(time.Now().Add(-time.Hour))
+		s.NoError(err)
+		s.Equal([]int{ticker.ID}, tickerIDs)
+
+		messages, err := s.store.FindEmailDigestMessages(ticker, time.Now().Add(-time.Hour))
+		s.NoError(err)
+		s.Len(messages, 1)
+
+		messages, err = s.store.FindEmailDigestMessages(ticker, time.Now())
+		s.NoError(err)
+		s.Len(messages, 2)
+		s.Equal("First", messages[0].Text)
+	})
+
+	s.Run("when a message was deleted", func() {
+		s.NoError(s.store.DeleteMessage(second, nil))
+
+		messages, err := s.store.FindEmailDigestMessages(ticker, time.Now())
+		s.NoError(err)
+		s.Len(messages, 1)
+	})
+
+	s.Run("when the digest was sent", func() {
+		err := s.store.DeleteEmailDigestEntries(ticker, time.Now())
+		s.NoError(err)
+
+		tickerIDs, err := s.store.FindDueEmailDigests(time.Now())
+		s.NoError(err)
+		s.Empty(tickerIDs)
+	})
+}
+
 func TestSqlStorageTestSuite(t *testing.T) {
 	suite.Run(t, new(SqlStorageTestSuite))
 }
+
+func (s *SqlStorageTestSuite) TestActivityPubFollowers() {
+	ticker := Ticker{ActivityPub: TickerActivityPub{Active: true, Username: "ticker", Origin: "https://demo.example.org"}}
+	err := s.store.SaveTicker(&ticker)
+	s.NoError(err)
+
+	s.Run("when an actor follows", func() {
+		follower := ActivityPubFollower{TickerID: ticker.ID, Actor: "https://social.example.org/users/alice", Inbox: "https://social.example.org/users/alice/inbox"}
+		err := s.store.SaveActivityPubFollower(&follower)
+		s.NoError(err)
+
+		followers, err := s.store.FindActivityPubFollowers(ticker)
+		s.NoError(err)
+		s.Len(followers, 1)
+		s.Empty(followers[0].SharedInbox)
+	})
+
+	s.Run("when the actor follows again", func() {
+		follower := ActivityPubFollower{TickerID: ticker.ID, Actor: "https://social.example.org/users/alice", Inbox: "https://social.example.org/users/alice/inbox", SharedInbox: "https://social.example.org/inbox"}
+		err := s.store.SaveActivityPubFollower(&follower)
+		s.NoError(err)
+
+		followers, err := s.store.FindActivityPubFollowers(ticker)
+		s.NoError(err)
+		s.Len(followers, 1)
+		s.Equal("https://social.example.org/inbox", followers[0].SharedInbox)
+	})
+
+	s.Run("when the actor unfollows", func() {
+		err := s.store.DeleteActivityPubFollower(ticker, "https://social.example.org/users/alice")
+		s.NoError(err)
+
+		followers, err := s.store.FindActivityPubFollowers(ticker)
+		s.NoError(err)
+		s.Empty(followers)
+	})
+
+	s.Run("when the actor is deleted", func() {
+		err := s.store.SaveActivityPubFollower(&ActivityPubFollower{TickerID: ticker.ID, Actor: "https://social.example.org/users/bob", Inbox: "https://social.example.org/users/bob/inbox"})
+		s.NoError(err)
+
+		err = s.store.DeleteActivityPub(&ticker)
+		s.NoError(err)
+		s.False(ticker.ActivityPub.Active)
+
+		followers, err := s.store.FindActivityPubFollowers(ticker)
+		s.NoError(err)
+		s.Empty(followers)
+
+		var count int64
+		s.NoError(s.db.Model(&TickerActivityPub{}).Where("ticker_id = ?", ticker.ID).Count(&count).Error)
+		s.Equal(int64(0), count)
+	})
+}
@@ -1,6 +1,8 @@
 package storage
 
 import (
+	"time"
+
 	"github.com/systemli/ticker/internal/api/pagination"
 	"github.com/systemli/ticker/internal/logger"
 	"gorm.io/gorm"
@@ -14,26 +16,92 @@ type Storage interface {
 	FindUsersByIDs(ids []int, opts ...func(*gorm.DB) *gorm.DB) ([]User, error)
 	FindUserByEmail(email string, opts ...func(*gorm.DB) *gorm.DB) (User, error)
 	FindUsersByTicker(ticker Ticker, opts ...func(*gorm.DB) *gorm.DB) ([]User, error)
+	FindUserByTelegramID(telegramID int64, opts ...func(*gorm.DB) *gorm.DB) (User, error)
 	SaveUser(user *User) error
+	SaveTelegramLink(link *TelegramLink) error
+	FindTelegramLinkByCode(code string) (TelegramLink, error)
+	LinkTelegramAccount(link TelegramLink, telegramID int64) error
+	UnlinkTelegramAccount(user *User) error
+	SaveTelegramTicker(user *User) error
+	SaveTOTPSecret(user *User) error
+	EnableTOTP(user *User, step int64, codes []RecoveryCode) error
+	DisableTOTP(user *User) error
+	UseTOTPStep(user *User, step int64) (bool, error)
+	AddTOTPAttempt(user *User) (int, error)
+	SaveTOTPLock(user *User) error
+	SaveRecoveryCodes(user User, codes []RecoveryCode) error
+	UseRecoveryCode(user User, code string) (bool, error)
+	CountRecoveryCodes(user User) (int64, error)
+	SaveWebAuthnHandle(user *User) error
+	FindUserByWebAuthnHandle(handle string, opts ...func(*gorm.DB) *gorm.DB) (User, error)
+	FindWebAuthnCredentials(user User) ([]WebAuthnCredential, error)
+	FindWebAuthnCredentialByID(user User, id int) (WebAuthnCredential, error)
+	CountWebAuthnCredentials(user User) (int64, error)
+	SaveWebAuthnCredential(credential *WebAuthnCredential) error
+	DeleteWebAuthnCredential(credential WebAuthnCredential) error
+	DeleteWebAuthnCredentials(user User) error
+	SaveWebAuthnSession(session *WebAuthnSession) error
+	TakeWebAuthnSession(token string) (WebAuthnSession, error)
+	SaveOIDCSubject(user *User) error
+	FindUserByOIDCSubject(subject string, opts ...func(*gorm.DB) *gorm.DB) (User, error)
+	SaveOIDCSession(session *OIDCSession) error
+	TakeOIDCSession(state string) (OIDCSession, error)
+	FindAPITokens(user User) ([]APIToken, error)
+	FindAPITokenByID(user User, id int) (APIToken, error)
+	FindAPITokenByHash(hash string) (APIToken, error)
+	SaveAPIToken(token *APIToken) error
+	SaveAPITokenLastUsed(token *APIToken) error
+	DeleteAPIToken(token APIToken) error
 	DeleteUser(user User) error
 	DeleteTickerUsers(ticker *Ticker) error
 	DeleteTickerUser(ticker *Ticker, user *User) error
 	AddTickerUser(ticker *Ticker, user *User) error
+	FindTickerUser(ticker Ticker, user User) (TickerUser, error)
+	FindTickerUsers(ticker Ticker) ([]TickerUser, error)
+	SaveTickerUsers(ticker *Ticker, tickerUsers []TickerUser) error
 	FindTickersByUser(user User, filter TickerFilter, opts ...func(*gorm.DB) *gorm.DB) ([]Ticker, error)
 	FindTickerByUserAndID(user User, id int, opts ...func(*gorm.DB) *gorm.DB) (Ticker, error)
 	FindTickersByIDs(ids []int, opts ...func(*gorm.DB) *gorm.DB) ([]Ticker, error)
 	FindTickerByOrigin(origin string, opts ...func(*gorm.DB) *gorm.DB) (Ticker, error)
+	FindTickerByMailboxAddress(address string, opts ...func(*gorm.DB) *gorm.DB) (Ticker, error)
 	FindTickerByID(id int, opts ...func(*gorm.DB) *gorm.DB) (Ticker, error)
 	SaveTicker(ticker *Ticker) error
 	DeleteTicker(ticker *Ticker) error
 	SaveTickerWebsites(ticker *Ticker, websites []TickerWebsite) error
 	DeleteTickerWebsites(ticker *Ticker) error
+	SaveTickerWebhooks(ticker *Ticker, webhooks []TickerWebhook) error
+	DeleteTickerWebhooks(ticker *Ticker) error
+	SaveTickerSignalSenders(ticker *Ticker, senders []TickerSignalSender) error
+	DeleteTickerSignalSenders(ticker *Ticker) error
+	FindTickerSignalSenderByNumber(number string) (TickerSignalSender, error)
 	ResetTicker(ticker *Ticker) error
 	DeleteIntegrations(ticker *Ticker) error
 	DeleteMastodon(ticker *Ticker) error
 	DeleteTelegram(ticker *Ticker) error
 	DeleteBluesky(ticker *Ticker) error
 	DeleteSignalGroup(ticker *Ticker) error
+	DeleteMatrix(ticker *Ticker) error
+	DeleteActivityPub(ticker *Ticker) error
+	FindActivityPubFollowers(ticker Ticker) ([]ActivityPubFollower, error)
+	SaveActivityPubFollower(follower *ActivityPubFollower) error
+	DeleteActivityPubFollower(ticker Ticker, actor string) error
+	DeleteWebPush(ticker *Ticker) error
+	FindWebPushSubscriptions(ticker Ticker) ([]WebPushSubscription, error)
+	CountWebPushSubscriptions(ticker Ticker) (int64, error)
+	SaveWebPushSubscription(subscription *WebPushSubscription) error
+	DeleteWebPushSubscription(ticker Ticker, endpoint string) error
+	DeleteEmail(ticker *Ticker) error
+	DeleteMailbox(ticker *Ticker) error
+	FindEmailSubscription(ticker Ticker, email string) (EmailSubscription, error)
+	FindEmailSubscriptionByToken(token string) (EmailSubscription, error)
+	FindConfirmedEmailSubscriptions(ticker Ticker) ([]EmailSubscription, error)
+	SaveEmailSubscription(subscription *EmailSubscription) error
+	ClaimEmailConfirmation(subscription *EmailSubscription, interval time.Duration) (bool, error)
+	DeleteEmailSubscription(subscription EmailSubscription) error
+	SaveEmailDigestEntry(entry *EmailDigestEntry) error
+	FindDueEmailDigests(before time.Time) ([]int, error)
+	FindEmailDigestMessages(ticker Ticker, until time.Time) ([]Message, error)
+	DeleteEmailDigestEntries(ticker Ticker, until time.Time) error
 	SaveUpload(upload *Upload) error
 	FindUploadByUUID(uuid string) (Upload, error)
 	FindUploadsByIDs(ids []int) ([]Upload, error)
@@ -43,14 +111,34 @@ type Storage interface {
 	FindMessage(tickerID, messageID int, opts ...func(*gorm.DB) *gorm.DB) (Message, error)
 	FindMessagesByTicker(ticker Ticker, opts ...func(*gorm.DB) *gorm.DB) ([]Message, error)
 	FindMessagesByTickerAndPagination(ticker Ticker, pagination pagination.Pagination, opts ...func(*gorm.DB) *gorm.DB) ([]Message, error)
+	SearchMessagesByTicker(ticker Ticker, query string, pagination pagination.Pagination, opts ...func(*gorm.DB) *gorm.DB) ([]Message, error)
+	FindScheduledMessagesByTicker(ticker Ticker, opts ...func(*gorm.DB) *gorm.DB) ([]Message, error)
+	FindPinnedMessagesByTicker(ticker Ticker, opts ...func(*gorm.DB) *gorm.DB) ([]Message, error)
+	FindDraftMessagesByTicker(ticker Ticker, opts ...func(*gorm.DB) *gorm.DB) ([]Message, error)
+	FindPendingReviewMessagesByTicker(ticker Ticker, opts ...func(*gorm.DB) *gorm.DB) ([]Message, error)
+	FindDueMessages(now time.Time, opts ...func(*gorm.DB) *gorm.DB) ([]Message, error)
+	PublishMessage(message *Message) error
 	SaveMessage(message *Message) error
-	DeleteMessage(message Message) error
+	DeleteMessage(message Message, bridges []string) error
 	DeleteMessages(ticker *Ticker) error
+	SaveMessageTags(message *Message, names []string) error
+	FindTagsByTicker(ticker Ticker) ([]TagCount, error)
+	SaveMessageBridgeMeta(message *Message, bridge string) error
+	CreateOutboxJobs(message Message, bridges []string) error
+	CreateOutboxEditJobs(message Message, bridges []string) error
+	ClaimOutboxJobs(now time.Time, limit int, lease time.Duration) ([]OutboxJob, error)
+	SaveOutboxJob(job *OutboxJob) error
+	FindMessageRevisions(tickerID, messageID int) ([]MessageRevision, error)
+	SaveMessageRevision(revision *MessageRevision) error
 	GetInactiveSettings() InactiveSettings
 	SaveInactiveSettings(inactiveSettings InactiveSettings) error
 	GetTelegramSettings() TelegramSettings
 	SaveTelegramSettings(telegramSettings TelegramSettings) error
 	GetSignalGroupSettings() SignalGroupSettings
 	SaveSignalGroupSettings(signalGroupSettings SignalGroupSettings) error
+	GetWebPushSettings() WebPushSettings
+	SaveWebPushSettings(webPushSettings WebPushSettings) error
+	GetSecuritySettings() SecuritySettings
+	SaveSecuritySettings(securitySettings SecuritySettings) error
 	UploadPath() string
 }
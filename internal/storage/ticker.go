@@ -1,27 +1,38 @@
 package storage
 
 import (
+	"crypto/rand"
+	"encoding/hex"
 	"net/http"
+	"net/url"
 	"strconv"
+	"strings"
 	"time"
 )
 
 type Ticker struct {
-	ID          int `gorm:"primaryKey"`
-	CreatedAt   time.Time
-	UpdatedAt   time.Time
-	Domain      string
-	Title       string
-	Description string
-	Active      bool
-	Information TickerInformation `gorm:"embedded"`
-	Location    TickerLocation    `gorm:"embedded"`
-	Telegram    TickerTelegram
-	Mastodon    TickerMastodon
-	Bluesky     TickerBluesky
-	SignalGroup TickerSignalGroup
-	Websites    []TickerWebsite `gorm:"foreignKey:TickerID;"`
-	Users       []User          `gorm:"many2many:ticker_users;"`
+	ID            int `gorm:"primaryKey"`
+	CreatedAt     time.Time
+	UpdatedAt     time.Time
+	Domain        string
+	Title         string
+	Description   string
+	Active        bool
+	Information   TickerInformation `gorm:"embedded"`
+	Location      TickerLocation    `gorm:"embedded"`
+	Telegram      TickerTelegram
+	Mastodon      TickerMastodon
+	Bluesky       TickerBluesky
+	SignalGroup   TickerSignalGroup
+	Matrix        TickerMatrix
+	ActivityPub   TickerActivityPub
+	WebPush       TickerWebPush
+	Email         TickerEmail
+	Mailbox       TickerMailbox
+	Websites      []TickerWebsite      `gorm:"foreignKey:TickerID;"`
+	Webhooks      []TickerWebhook      `gorm:"foreignKey:TickerID;"`
+	SignalSenders []TickerSignalSender `gorm:"foreignKey:TickerID;"`
+	Users         []User               `gorm:"many2many:ticker_users;"`
 }
 
 func NewTicker() Ticker {
@@ -73,6 +84,65 @@ type TickerWebsite struct {
 	Origin    string `gorm:"unique;not null"`
 }
 
+// TickerWebhook is a URL the messages of the ticker are posted to. The requests
+// are signed with the secret, so the receiver can verify they come from the
+// ticker.
+type TickerWebhook struct {
+	ID        int `gorm:"primaryKey"`
+	CreatedAt time.Time
+	UpdatedAt time.Time
+	TickerID  int    `gorm:"index;not null"`
+	URL       string `gorm:"not null"`
+	Secret    string `gorm:"not null"`
+}
+
+// TickerSignalSender is a phone number whose Signal messages to the account of
+// the instance are posted to the ticker. A number belongs to a single ticker.
+type TickerSignalSender struct {
+	ID        int `gorm:"primaryKey"`
+	CreatedAt time.Time
+	UpdatedAt time.Time
+	TickerID  int    `gorm:"index;not null"`
+	Number    string `gorm:"unique;not null"`
+	Name      string
+}
+
+const (
+	TickerRoleViewer      = "viewer"
+	TickerRoleContributor = "contributor"
+	TickerRoleEditor      = "editor"
+	TickerRoleOwner       = "owner"
+)
+
+// tickerRoleRanks orders the roles, each one includes the permissions of the
+// roles below.
+var tickerRoleRanks = map[string]int{
+	TickerRoleViewer:      1,
+	TickerRoleContributor: 2,
+	TickerRoleEditor:      3,
+	TickerRoleOwner:       4,
+}
+
+// IsValidTickerRole reports whether role is one of the known ticker roles.
+func IsValidTickerRole(role string) bool {
+	_, ok := tickerRoleRanks[role]
+	return ok
+}
+
+// HasTickerRole reports whether role grants at least the permissions of
+// required. Unknown roles grant nothing.
+func HasTickerRole(role, required string) bool {
+	return IsValidTickerRole(role) && tickerRoleRanks[role] >= tickerRoleRanks[required]
+}
+
+// TickerUser is the membership of a user in a ticker, it backs the many2many
+// association between tickers and users.
+type TickerUser struct {
+	TickerID int    `gorm:"primaryKey"`
+	UserID   int    `gorm:"primaryKey"`
+	Role     string `gorm:"default:editor;not null"`
+}
+
 type TickerTelegram struct {
 	ID          int `gorm:"primaryKey"`
 	CreatedAt   time.Time
@@ -143,6 +213,161 @@ func (s *TickerSignalGroup) Connected() bool {
 	return s.GroupID != ""
 }
 
+// TickerMatrix is the Matrix room the messages of the ticker are posted to,
+// with the access token of the account posting them.
+type TickerMatrix struct {
+	ID          int `gorm:"primaryKey"`
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+	TickerID    int `gorm:"index"`
+	Active      bool
+	Homeserver  string
+	AccessToken string
+	// UserID is the account the access token belongs to, e.g. @ticker:example.org
+	UserID string
+	// RoomID is the internal ID of the room, e.g. !abc:example.org. Aliases are
+	// resolved when the room is joined.
+	RoomID string
+}
+
+func (m *TickerMatrix) Connected() bool {
+	return m.Homeserver != "" && m.AccessToken != "" && m.RoomID != ""
+}
+
+// TickerActivityPub makes the ticker an ActivityPub actor of its own, which
+// accounts on the fediverse follow as @Username@host.
+type TickerActivityPub struct {
+	ID        int `gorm:"primaryKey"`
+	CreatedAt time.Time
+	UpdatedAt time.Time
+	TickerID  int `gorm:"index"`
+	Active    bool
+	Username  string
+	// Origin is the website of the ticker the actor lives on, e.g.
+	// https://demo.example.org
+	Origin     string
+	PrivateKey string
+	PublicKey  string
+}
+
+func (a *TickerActivityPub) Connected() bool {
+	return a.Origin != "" && a.PrivateKey != ""
+}
+
+// Handle returns the address of the actor, e.g. @ticker@demo.example.org
+func (a *TickerActivityPub) Handle() string {
+	u, err := url.Parse(a.Origin)
+	if err != nil || u.Host == "" {
+		return ""
+	}
+
+	return "@" + a.Username + "@" + u.Host
+}
+
+// ActivityPubFollower is a remote actor following a ticker. Activities are
+// delivered to its shared inbox if it has one.
+type ActivityPubFollower struct {
+	ID          int `gorm:"primaryKey"`
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+	TickerID    int    `gorm:"uniqueIndex:idx_ticker_actor;not null"`
+	Actor       string `gorm:"uniqueIndex:idx_ticker_actor;size:255;not null"`
+	Inbox       string `gorm:"not null"`
+	SharedInbox string
+}
+
+// TickerWebPush lets the readers of the ticker subscribe to notifications of
+// new messages in their browsers.
+type TickerWebPush struct {
+	ID        int `gorm:"primaryKey"`
+	CreatedAt time.Time
+	UpdatedAt time.Time
+	TickerID  int `gorm:"index"`
+	Active    bool
+}
+
+// WebPushSubscription is the push subscription of a reader's browser. The keys
+// are base64url encoded, as the browser hands them out.
+type WebPushSubscription struct {
+	ID        int `gorm:"primaryKey"`
+	CreatedAt time.Time
+	UpdatedAt time.Time
+	TickerID  int    `gorm:"uniqueIndex:idx_ticker_endpoint;not null"`
+	Endpoint  string `gorm:"uniqueIndex:idx_ticker_endpoint;size:500;not null"`
+	P256dh    string `gorm:"not null"`
+	Auth      string `gorm:"not null"`
+}
+
+// TickerEmail sends the messages of the ticker to the readers who subscribed
+// with their email address, one by one or as an hourly digest.
+type TickerEmail struct {
+	ID        int `gorm:"primaryKey"`
+	CreatedAt time.Time
+	UpdatedAt time.Time
+	TickerID  int `gorm:"index"`
+	Active    bool
+	Digest    bool
+}
+
+// EmailSubscription is the email address of a reader. It only receives mails
+// once the reader followed the link in the confirmation mail. The token is in
+// the links to confirm and to unsubscribe.
+type EmailSubscription struct {
+	ID          int `gorm:"primaryKey"`
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+	TickerID    int    `gorm:"uniqueIndex:idx_ticker_email;not null"`
+	Email       string `gorm:"uniqueIndex:idx_ticker_email;size:320;not null"`
+	Token       string `gorm:"uniqueIndex;size:64;not null"`
+	ConfirmedAt *time.Time
+	// ConfirmationSentAt is when the last confirmation mail was sent, so
+	// repeated subscriptions do not flood the address.
+	ConfirmationSentAt *time.Time
+}
+
+func NewEmailSubscription(tickerID int, email string) (EmailSubscription, error) {
+	token := make([]byte, 32)
+	if _, err := rand.Read(token); err != nil {
+		return EmailSubscription{}, err
+	}
+
+	return EmailSubscription{TickerID: tickerID, Email: email, Token: hex.EncodeToString(token)}, nil
+}
+
+func (s *EmailSubscription) Confirmed() bool {
+	return s.ConfirmedAt != nil
+}
+
+// EmailDigestEntry is a message waiting for the next digest of its ticker.
+type EmailDigestEntry struct {
+	ID        int `gorm:"primaryKey"`
+	CreatedAt time.Time
+	TickerID  int `gorm:"index;not null"`
+	MessageID int `gorm:"not null"`
+}
+
+// TickerMailbox is the secret address mails are posted to the ticker through.
+// Only mails from users of the ticker are taken, the address keeps others from
+// even trying.
+type TickerMailbox struct {
+	ID        int `gorm:"primaryKey"`
+	CreatedAt time.Time
+	UpdatedAt time.Time
+	TickerID  int `gorm:"index"`
+	Active    bool
+	Address   string `gorm:"index;size:320"`
+}
+
+// NewMailboxAddress returns a random address at the domain.
+func NewMailboxAddress(domain string) (string, error) {
+	local := make([]byte, 16)
+	if _, err := rand.Read(local); err != nil {
+		return "", err
+	}
+
+	return hex.EncodeToString(local) + "@" + strings.ToLower(domain), nil
+}
+
 type TickerLocation struct {
 	Lat float64
 	Lon float64
@@ -38,6 +38,37 @@ func TestTickerSignalGroupConnect(t *testing.T) {
 	assert.True(t, ticker.SignalGroup.Connected())
 }
 
+func TestTickerActivityPubConnected(t *testing.T) {
+	assert.False(t, ticker.ActivityPub.Connected())
+	assert.Empty(t, ticker.ActivityPub.Handle())
+
+	ticker.ActivityPub.Username = "ticker"
+	ticker.ActivityPub.Origin = "https://demo.example.org"
+	ticker.ActivityPub.PrivateKey = "PrivateKey"
+
+	assert.True(t, ticker.ActivityPub.Connected())
+	assert.Equal(t, "@ticker@demo.example.org", ticker.ActivityPub.Handle())
+}
+
+func TestNewMailboxAddress(t *testing.T) {
+	address, err := NewMailboxAddress("Ticker.example.org")
+	assert.NoError(t, err)
+	assert.Regexp(t, `^[0-9a-f]{32}@ticker\.example\.org$`, address)
+
+	other, err := NewMailboxAddress("ticker.example.org")
+	assert.NoError(t, err)
+	assert.NotEqual(t, address, other)
+}
+
+func TestHasTickerRole(t *testing.T) {
+	assert.True(t, HasTickerRole(TickerRoleOwner, TickerRoleEditor))
+	assert.True(t, HasTickerRole(TickerRoleEditor, TickerRoleEditor))
+	assert.False(t, HasTickerRole(TickerRoleContributor, TickerRoleEditor))
+	assert.False(t, HasTickerRole(TickerRoleViewer, TickerRoleContributor))
+	assert.False(t, HasTickerRole("", TickerRoleViewer))
+	assert.False(t, HasTickerRole("admin", TickerRoleViewer))
+}
+
 func TestNewTickerFilter(t *testing.T) {
 	filter := NewTickerFilter(nil)
 	assert.Nil(t, filter.Active)
@@ -1,9 +1,14 @@
 package storage
 
 import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
 	"errors"
 	"net/http"
+	"slices"
 	"strconv"
+	"strings"
 	"time"
 
 	"golang.org/x/crypto/bcrypt"
@@ -18,7 +23,30 @@ type User struct {
 	Email             string `gorm:"uniqueIndex;not null"`
 	EncryptedPassword string `gorm:"not null"`
 	IsSuperAdmin      bool
-	Tickers           []Ticker `gorm:"many2many:ticker_users;"`
+	// TelegramID is the Telegram account the user posts from through the bot,
+	// and TelegramTickerID the ticker chosen in the bot.
+	TelegramID       *int64 `gorm:"uniqueIndex"`
+	TelegramTickerID int
+	// TOTPSecret is the secret of the user's authenticator app. The login only
+	// asks for a code once TOTPEnabled is set, after the user entered a first
+	// one. TOTPLastStep is the time step of the last code used, so every code
+	// works once.
+	TOTPSecret   string
+	TOTPEnabled  bool
+	TOTPLastStep int64
+	// TOTPAttempts counts the codes entered since the last correct one, and
+	// TOTPLockedUntil is when codes are accepted again after too many wrong
+	// ones.
+	TOTPAttempts    int
+	TOTPLockedUntil time.Time
+	// WebAuthnHandle identifies the user to passkeys. It is random, so the
+	// authenticators learn nothing about the account, and set with the first
+	// passkey.
+	WebAuthnHandle string `gorm:"index;size:64"`
+	// OIDCSubject is the account at the identity provider of the single
+	// sign-on. Linked users log in through the provider only.
+	OIDCSubject string   `gorm:"column:oidc_subject;index;size:255"`
+	Tickers     []Ticker `gorm:"many2many:ticker_users;"`
 }
 
 func NewUser(email, password string) (User, error) {
@@ -78,6 +106,73 @@ func (u *User) AsMap() map[string]interface{} {
 	}
 }
 
+// TelegramLinkTTL is how long the code to link a Telegram account is valid.
+const TelegramLinkTTL = 15 * time.Minute
+
+// TelegramLink is the code a user sends to the bot to link a Telegram account.
+// Every user has at most one, requesting a new code replaces it.
+type TelegramLink struct {
+	ID        int `gorm:"primaryKey"`
+	CreatedAt time.Time
+	UserID    int    `gorm:"uniqueIndex;not null"`
+	Code      string `gorm:"uniqueIndex;size:64;not null"`
+}
+
+func NewTelegramLink(user User) (TelegramLink, error) {
+	code := make([]byte, 16)
+	if _, err := rand.Read(code); err != nil {
+		return TelegramLink{}, err
+	}
+
+	return TelegramLink{CreatedAt: time.Now(), UserID: user.ID, Code: hex.EncodeToString(code)}, nil
+}
+
+func (l *TelegramLink) Expired() bool {
+	return time.Since(l.CreatedAt) > TelegramLinkTTL
+}
+
+// recoveryCodeCount is the number of recovery codes a user gets at once.
+const recoveryCodeCount = 10
+
+// RecoveryCode stands in for a code of the authenticator app when the device
+// is lost. Only a hash is stored, and every code is removed once it is used.
+type RecoveryCode struct {
+	ID        int `gorm:"primaryKey"`
+	CreatedAt time.Time
+	UserID    int    `gorm:"index;not null"`
+	Hash      string `gorm:"size:64;not null"`
+}
+
+// NewRecoveryCodes returns a new set of codes to show the user once, together
+// with their hashes to store.
+func NewRecoveryCodes(user User) ([]string, []RecoveryCode, error) {
+	plain := make([]string, 0, recoveryCodeCount)
+	codes := make([]RecoveryCode, 0, recoveryCodeCount)
+
+	for i := 0; i < recoveryCodeCount; i++ {
+		b := make([]byte, 5)
+		if _, err := rand.Read(b); err != nil {
+			return nil, nil, err
+		}
+
+		code := hex.EncodeToString(b)
+		code = code[:5] + "-" + code[5:]
+		plain = append(plain, code)
+		codes = append(codes, RecoveryCode{UserID: user.ID, Hash: HashRecoveryCode(code)})
+	}
+
+	return plain, codes, nil
+}
+
+// HashRecoveryCode hashes the code as it is stored. The codes are random, so
+// a fast hash is enough. Case, spaces and dashes are ignored.
+func HashRecoveryCode(code string) string {
+	normalized := strings.NewReplacer("-", "", " ", "").Replace(strings.ToLower(strings.TrimSpace(code)))
+	sum := sha256.Sum256([]byte(normalized))
+
+	return hex.EncodeToString(sum[:])
+}
+
 func hashPassword(password string) (string, error) {
 	pw, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
 	if err != nil {
@@ -132,3 +227,191 @@ func NewUserFilter(req *http.Request) UserFilter {
 
 	return filter
 }
+
+// NewWebAuthnHandle returns a random handle for the passkeys of a user.
+func NewWebAuthnHandle() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+
+	return hex.EncodeToString(b), nil
+}
+
+// WebAuthnCredential is a passkey or security key of a user. It holds what is
+// needed to verify the logins with it, but nothing secret.
+type WebAuthnCredential struct {
+	ID        int `gorm:"primaryKey"`
+	CreatedAt time.Time
+	UpdatedAt time.Time
+	LastUsed  time.Time
+	UserID    int    `gorm:"index;not null"`
+	Name      string `gorm:"size:255"`
+	// CredentialID is the ID the authenticator chose for the credential.
+	CredentialID      []byte `gorm:"not null"`
+	PublicKey         []byte `gorm:"not null"`
+	AttestationType   string
+	AttestationFormat string
+	Transports        []string `gorm:"serializer:json"`
+	AAGUID            []byte
+	// SignCount is the last signature counter of the authenticator. It only
+	// goes up, a lower one hints at a cloned authenticator.
+	SignCount      uint32
+	CloneWarning   bool
+	UserVerified   bool
+	BackupEligible bool
+	BackupState    bool
+}
+
+// WebAuthnSessionTTL is how long a user has to answer the browser prompt for
+// a passkey.
+const WebAuthnSessionTTL = 5 * time.Minute
+
+// WebAuthnSession holds the challenge of a passkey registration or login
+// between the options and the answer of the browser. The client only gets the
+// token, and every session can be used once.
+type WebAuthnSession struct {
+	ID        int `gorm:"primaryKey"`
+	CreatedAt time.Time
+	Token     string `gorm:"uniqueIndex;size:64;not null"`
+	// UserID is empty for a login with a passkey, where the user is not known
+	// before the answer.
+	UserID int `gorm:"index"`
+	// Data is the session of the ceremony as the passkey package encodes it.
+	Data []byte `gorm:"not null"`
+}
+
+func NewWebAuthnSession(userID int, data []byte) (WebAuthnSession, error) {
+	token := make([]byte, 32)
+	if _, err := rand.Read(token); err != nil {
+		return WebAuthnSession{}, err
+	}
+
+	return WebAuthnSession{CreatedAt: time.Now(), Token: hex.EncodeToString(token), UserID: userID, Data: data}, nil
+}
+
+func (s *WebAuthnSession) Expired() bool {
+	return time.Since(s.CreatedAt) > WebAuthnSessionTTL
+}
+
+// OIDCSessionTTL is how long a user has to log in at the identity provider.
+const OIDCSessionTTL = 10 * time.Minute
+
+// OIDCSession holds the secrets of a single sign-on between the redirect to
+// the identity provider and the way back. The state is the token the client
+// gets, every session can be used once.
+type OIDCSession struct {
+	ID        int `gorm:"primaryKey"`
+	CreatedAt time.Time
+	State     string `gorm:"uniqueIndex;size:64;not null"`
+	// Verifier is the PKCE code verifier, which proves that the code is
+	// redeemed by the one who asked for it.
+	Verifier string `gorm:"size:128;not null"`
+	// Nonce ties the ID token to the session.
+	Nonce string `gorm:"size:64;not null"`
+}
+
+// TableName keeps gorm from splitting the initialism into "o_id_c_sessions".
+func (OIDCSession) TableName() string {
+	return "oidc_sessions"
+}
+
+func NewOIDCSession(verifier string) (OIDCSession, error) {
+	b := make([]byte, 64)
+	if _, err := rand.Read(b); err != nil {
+		return OIDCSession{}, err
+	}
+
+	return OIDCSession{
+		CreatedAt: time.Now(),
+		State:     hex.EncodeToString(b[:32]),
+		Verifier:  verifier,
+		Nonce:     hex.EncodeToString(b[32:]),
+	}, nil
+}
+
+func (s *OIDCSession) Expired() bool {
+	return time.Since(s.CreatedAt) > OIDCSessionTTL
+}
+
+// The scopes of API tokens. A token can only do what its user may do, the
+// scopes narrow that down further.
+const (
+	APITokenScopeMessagesRead  = "messages:read"
+	APITokenScopeMessagesWrite = "messages:write"
+)
+
+var APITokenScopes = []string{APITokenScopeMessagesRead, APITokenScopeMessagesWrite}
+
+const (
+	// APITokenLifetime is how long a token is valid when it is created
+	// without an expiry.
+	APITokenLifetime = 90 * 24 * time.Hour
+	// LinkedAPITokenLifetime is the longest a token of a user who is linked
+	// to the identity provider is valid while single sign-on is on. Disabling
+	// the user at the provider does not revoke the token.
+	LinkedAPITokenLifetime = 30 * 24 * time.Hour
+)
+
+// APITokenPrefix marks API tokens, so they are easy to tell from the JWT and
+// to find when they leak.
+const APITokenPrefix = "ticker_"
+
+// APIToken lets scripts and integrations call the admin API for a user,
+// without a password. Only the hash of the token is stored.
+type APIToken struct {
+	ID        int `gorm:"primaryKey"`
+	CreatedAt time.Time
+	UpdatedAt time.Time
+	LastUsed  time.Time
+	// ExpiresAt is empty for tokens created before tokens expired by
+	// default, these are valid until they are revoked.
+	ExpiresAt *time.Time
+	UserID    int      `gorm:"index;not null"`
+	Name      string   `gorm:"size:255;not null"`
+	Hash      string   `gorm:"uniqueIndex;size:64;not null"`
+	Scopes    []string `gorm:"serializer:json"`
+	// TickerID limits the token to one ticker, it is 0 for all tickers of
+	// the user.
+	TickerID int
+}
+
+// NewAPIToken returns the token to hand to the user once, and the record to
+// store.
+func NewAPIToken(user User, name string, scopes []string, tickerID int) (string, APIToken, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", APIToken{}, err
+	}
+
+	plain := APITokenPrefix + hex.EncodeToString(b)
+
+	return plain, APIToken{UserID: user.ID, Name: name, Hash: HashAPIToken(plain), Scopes: scopes, TickerID: tickerID}, nil
+}
+
+// HashAPIToken hashes the token as it is stored. Tokens are random, so a fast
+// hash is enough.
+func HashAPIToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+
+	return hex.EncodeToString(sum[:])
+}
+
+func (t *APIToken) Expired() bool {
+	return t.ExpiresAt != nil && time.Now().After(*t.ExpiresAt)
+}
+
+// Outlives reports whether the token is valid for longer than the lifetime
+// after it was created.
+func (t *APIToken) Outlives(lifetime time.Duration) bool {
+	return t.ExpiresAt == nil || t.ExpiresAt.After(t.CreatedAt.Add(lifetime))
+}
+
+func (t *APIToken) HasScope(scope string) bool {
+	return slices.Contains(t.Scopes, scope)
+}
+
+// AllowsTicker reports whether the token may be used for the ticker.
+func (t *APIToken) AllowsTicker(tickerID int) bool {
+	return t.TickerID == 0 || t.TickerID == tickerID
+}
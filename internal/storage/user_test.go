@@ -2,7 +2,9 @@ package storage
 
 import (
 	"net/http/httptest"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 )
@@ -40,6 +42,98 @@ func TestNewUser(t *testing.T) {
 	assert.NotNil(t, err)
 }
 
+func TestNewRecoveryCodes(t *testing.T) {
+	user := User{ID: 1}
+
+	plain, codes, err := NewRecoveryCodes(user)
+	assert.Nil(t, err)
+	assert.Len(t, plain, 10)
+	assert.Len(t, codes, 10)
+
+	for i, code := range plain {
+		assert.Regexp(t, `^[0-9a-f]{5}-[0-9a-f]{5}$`, code)
+		assert.Equal(t, HashRecoveryCode(code), codes[i].Hash)
+		assert.Equal(t, 1, codes[i].UserID)
+	}
+	assert.Equal(t, HashRecoveryCode(plain[0]), HashRecoveryCode(" "+strings.ToUpper(strings.ReplaceAll(plain[0], "-", ""))))
+	assert.NotEqual(t, plain[0], plain[1])
+}
+
+func TestNewWebAuthnHandle(t *testing.T) {
+	handle, err := NewWebAuthnHandle()
+	assert.Nil(t, err)
+	assert.Regexp(t, `^[0-9a-f]{64}$`, handle)
+
+	other, err := NewWebAuthnHandle()
+	assert.Nil(t, err)
+	assert.NotEqual(t, handle, other)
+}
+
+func TestWebAuthnSessionExpired(t *testing.T) {
+	session, err := NewWebAuthnSession(1, []byte(`{}`))
+	assert.Nil(t, err)
+	assert.Len(t, session.Token, 64)
+	assert.False(t, session.Expired())
+
+	session.CreatedAt = time.Now().Add(-WebAuthnSessionTTL - time.Second)
+	assert.True(t, session.Expired())
+}
+
+func TestOIDCSessionExpired(t *testing.T) {
+	session, err := NewOIDCSession("verifier")
+	assert.Nil(t, err)
+	assert.Len(t, session.State, 64)
+	assert.Len(t, session.Nonce, 64)
+	assert.NotEqual(t, session.State, session.Nonce)
+	assert.False(t, session.Expired())
+
+	session.CreatedAt = time.Now().Add(-OIDCSessionTTL - time.Second)
+	assert.True(t, session.Expired())
+}
+
+func TestNewAPIToken(t *testing.T) {
+	plain, token, err := NewAPIToken(User{ID: 1}, "Sensor", []string{APITokenScopeMessagesWrite}, 5)
+	assert.Nil(t, err)
+	assert.Regexp(t, `^ticker_[0-9a-f]{64}$`, plain)
+	assert.Equal(t, HashAPIToken(plain), token.Hash)
+	assert.NotContains(t, token.Hash, plain)
+	assert.Equal(t, 1, token.UserID)
+
+	assert.True(t, token.HasScope(APITokenScopeMessagesWrite))
+	assert.False(t, token.HasScope(APITokenScopeMessagesRead))
+	assert.True(t, token.AllowsTicker(5))
+	assert.False(t, token.AllowsTicker(6))
+
+	token.TickerID = 0
+	assert.True(t, token.AllowsTicker(6))
+}
+
+func TestAPITokenExpired(t *testing.T) {
+	token := APIToken{}
+	assert.False(t, token.Expired())
+
+	future := time.Now().Add(time.Hour)
+	token.ExpiresAt = &future
+	assert.False(t, token.Expired())
+
+	past := time.Now().Add(-time.Second)
+	token.ExpiresAt = &past
+	assert.True(t, token.Expired())
+}
+
+func TestAPITokenOutlives(t *testing.T) {
+	token := APIToken{CreatedAt: time.Now()}
+	assert.True(t, token.Outlives(time.Hour))
+
+	soon := token.CreatedAt.Add(time.Minute)
+	token.ExpiresAt = &soon
+	assert.False(t, token.Outlives(time.Hour))
+
+	late := token.CreatedAt.Add(2 * time.Hour)
+	token.ExpiresAt = &late
+	assert.True(t, token.Outlives(time.Hour))
+}
+
 func TestNewUserFilter(t *testing.T) {
 	filter := NewUserFilter(nil)
 	assert.Nil(t, filter.Email)
@@ -0,0 +1,86 @@
+package totp
+
+import (
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// rfcSecret is the SHA1 key of the test vectors in RFC 6238, Appendix B,
+// "12345678901234567890" base32 encoded.
+const rfcSecret = "GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ"
+
+// TestCode uses the test vectors of RFC 6238, Appendix B, cut to six digits.
+func TestCode(t *testing.T) {
+	vectors := map[int64]string{
+		59:          "287082",
+		1111111109:  "081804",
+		1111111111:  "050471",
+		1234567890:  "005924",
+		2000000000:  "279037",
+		20000000000: "353130",
+	}
+
+	for seconds, expected := range vectors {
+		code, err := Code(rfcSecret, time.Unix(seconds, 0))
+		require.NoError(t, err)
+		assert.Equal(t, expected, code, "at %d", seconds)
+	}
+}
+
+func TestValidate(t *testing.T) {
+	now := time.Unix(1111111111, 0)
+
+	step, ok := Validate(rfcSecret, "050471", now)
+	assert.True(t, ok)
+	assert.Equal(t, int64(1111111111/Period), step)
+
+	previous, err := Code(rfcSecret, now.Add(-Period*time.Second))
+	require.NoError(t, err)
+	step, ok = Validate(rfcSecret, previous, now)
+	assert.True(t, ok)
+	assert.Equal(t, int64(1111111111/Period-1), step)
+
+	tooOld, err := Code(rfcSecret, now.Add(-2*Period*time.Second))
+	require.NoError(t, err)
+	_, ok = Validate(rfcSecret, tooOld, now)
+	assert.False(t, ok)
+
+	_, ok = Validate(rfcSecret, "050 471", now)
+	assert.True(t, ok)
+	_, ok = Validate(rfcSecret, "", now)
+	assert.False(t, ok)
+	_, ok = Validate(rfcSecret, "50471", now)
+	assert.False(t, ok)
+	_, ok = Validate("not base32!", "050471", now)
+	assert.False(t, ok)
+}
+
+func TestGenerateSecret(t *testing.T) {
+	secret, err := GenerateSecret()
+	require.NoError(t, err)
+	assert.Len(t, secret, 32)
+
+	other, err := GenerateSecret()
+	require.NoError(t, err)
+	assert.NotEqual(t, secret, other)
+
+	_, err = Code(secret, time.Now())
+	assert.NoError(t, err)
+}
+
+func TestURI(t *testing.T) {
+	uri := URI(rfcSecret, "Ticker", "user@systemli.org")
+
+	u, err := url.Parse(uri)
+	require.NoError(t, err)
+	assert.Equal(t, "otpauth", u.Scheme)
+	assert.Equal(t, "totp", u.Host)
+	assert.Equal(t, "/Ticker:user@systemli.org", u.Path)
+	assert.Equal(t, rfcSecret, u.Query().Get("secret"))
+	assert.Equal(t, "Ticker", u.Query().Get("issuer"))
+	assert.Equal(t, "6", u.Query().Get("digits"))
+}
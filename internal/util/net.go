@@ -0,0 +1,95 @@
+package util
+
+import (
+	"errors"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"syscall"
+	"time"
+)
+
+// ErrPrivateAddress is returned when a request to another server would reach
+// an address of the network the ticker runs in.
+var ErrPrivateAddress = errors.New("address is not public")
+
+// sharedAddressSpace is the carrier-grade NAT range, which is as internal as
+// the private ranges.
+var sharedAddressSpace = &net.IPNet{IP: net.IPv4(100, 64, 0, 0), Mask: net.CIDRMask(10, 32)}
+
+// IsPublicIP reports whether the ip is an address on the internet, as opposed
+// to loopback, private, link-local or multicast addresses.
+func IsPublicIP(ip net.IP) bool {
+	return ip != nil &&
+		!ip.IsLoopback() &&
+		!ip.IsPrivate() &&
+		!ip.IsUnspecified() &&
+		!ip.IsLinkLocalUnicast() &&
+		!ip.IsLinkLocalMulticast() &&
+		!ip.IsInterfaceLocalMulticast() &&
+		!ip.IsMulticast() &&
+		!sharedAddressSpace.Contains(ip) &&
+		!ip.Equal(net.IPv4bcast)
+}
+
+// IsPublicURL reports whether the url is an absolute URL with one of the
+// schemes, whose host is not an internal address. Names are resolved only when
+// connecting, PublicTransport checks the addresses they resolve to.
+func IsPublicURL(raw string, schemes ...string) bool {
+	u, err := url.Parse(raw)
+	if err != nil || u.Hostname() == "" {
+		return false
+	}
+
+	valid := false
+	for _, scheme := range schemes {
+		if u.Scheme == scheme {
+			valid = true
+		}
+	}
+	if !valid {
+		return false
+	}
+
+	host := strings.ToLower(strings.TrimSuffix(u.Hostname(), "."))
+	if host == "localhost" || strings.HasSuffix(host, ".localhost") {
+		return false
+	}
+	if ip := net.ParseIP(host); ip != nil {
+		return IsPublicIP(ip)
+	}
+
+	return true
+}
+
+// PublicTransport returns a transport which refuses to connect to internal
+// addresses, for requests to URLs other people control. The address is
+// checked after the name is resolved, so names pointing inwards are refused,
+// too. Proxies are not used, as the check would apply to the proxy.
+func PublicTransport() *http.Transport {
+	dialer := &net.Dialer{
+		Timeout:   30 * time.Second,
+		KeepAlive: 30 * time.Second,
+		Control: func(_, address string, _ syscall.RawConn) error {
+			host, _, err := net.SplitHostPort(address)
+			if err != nil {
+				return err
+			}
+			if !IsPublicIP(net.ParseIP(host)) {
+				return ErrPrivateAddress
+			}
+
+			return nil
+		},
+	}
+
+	return &http.Transport{
+		DialContext:           dialer.DialContext,
+		ForceAttemptHTTP2:     true,
+		MaxIdleConns:          100,
+		IdleConnTimeout:       90 * time.Second,
+		TLSHandshakeTimeout:   10 * time.Second,
+		ExpectContinueTimeout: 1 * time.Second,
+	}
+}
@@ -0,0 +1,56 @@
+package util
+
+import (
+	"errors"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIsPublicIP(t *testing.T) {
+	for _, ip := range []string{"127.0.0.1", "10.0.0.1", "172.16.0.1", "192.168.1.1", "169.254.169.254", "100.64.0.1", "0.0.0.0", "::1", "fe80::1", "fd00::1", "::ffff:127.0.0.1"} {
+		assert.False(t, IsPublicIP(net.ParseIP(ip)), ip)
+	}
+
+	for _, ip := range []string{"1.1.1.1", "2001:4860:4860::8888"} {
+		assert.True(t, IsPublicIP(net.ParseIP(ip)), ip)
+	}
+
+	assert.False(t, IsPublicIP(nil))
+}
+
+func TestIsPublicURL(t *testing.T) {
+	testCases := []struct {
+		url      string
+		expected bool
+	}{
+		{"https://hooks.example.org/ticker", true},
+		{"http://hooks.example.org/ticker", false},
+		{"https://1.1.1.1/ticker", true},
+		{"https://127.0.0.1:8080/", false},
+		{"https://[::1]/", false},
+		{"https://169.254.169.254/latest/meta-data", false},
+		{"https://localhost/", false},
+		{"https://signal.localhost./", false},
+		{"/ticker", false},
+		{"https://", false},
+	}
+
+	for _, tc := range testCases {
+		assert.Equal(t, tc.expected, IsPublicURL(tc.url, "https"), tc.url)
+	}
+
+	assert.True(t, IsPublicURL("http://hooks.example.org/ticker", "http", "https"))
+}
+
+func TestPublicTransport(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {}))
+	defer server.Close()
+
+	client := &http.Client{Transport: PublicTransport()}
+	_, err := client.Get(server.URL)
+	assert.True(t, errors.Is(err, ErrPrivateAddress))
+}
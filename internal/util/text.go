@@ -0,0 +1,65 @@
+package util
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// sentenceBreaks are the places a text is preferably split at, in order of
+// preference.
+var sentenceBreaks = []string{"\n\n", "\n", ". ", "! ", "? "}
+
+// SplitText breaks the text into chunks of at most limit characters for
+// services with a length limit. It splits after a paragraph or sentence if one
+// ends in the second half of the chunk, otherwise between words, and only cuts
+// words which are longer than the limit. There is always at least one chunk,
+// even for an empty text.
+func SplitText(text string, limit int) []string {
+	text = strings.TrimSpace(text)
+
+	chunks := make([]string, 0)
+	for utf8.RuneCountInString(text) > limit {
+		cut := splitIndex(text, limit)
+		chunks = append(chunks, strings.TrimSpace(text[:cut]))
+		text = strings.TrimSpace(text[cut:])
+	}
+
+	if text != "" || len(chunks) == 0 {
+		chunks = append(chunks, text)
+	}
+
+	return chunks
+}
+
+// splitIndex returns the byte offset at which the first chunk of at most limit
+// characters ends.
+func splitIndex(text string, limit int) int {
+	end := len(text)
+	count := 0
+	for i := range text {
+		if count == limit {
+			end = i
+			break
+		}
+		count++
+	}
+
+	window := text[:end]
+	for _, sep := range sentenceBreaks {
+		if i := strings.LastIndex(window, sep); i > 0 && i >= len(window)/2 {
+			return i + len(sep)
+		}
+	}
+
+	r, _ := utf8.DecodeRuneInString(text[end:])
+	if unicode.IsSpace(r) {
+		return end
+	}
+
+	if i := strings.LastIndexFunc(window, unicode.IsSpace); i > 0 {
+		return i
+	}
+
+	return end
+}
@@ -0,0 +1,79 @@
+package util
+
+import (
+	"strings"
+	"testing"
+	"unicode/utf8"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSplitText(t *testing.T) {
+	testCases := []struct {
+		name     string
+		text     string
+		limit    int
+		expected []string
+	}{
+		{
+			name:     "short text",
+			text:     "Hello world",
+			limit:    20,
+			expected: []string{"Hello world"},
+		},
+		{
+			name:     "empty text",
+			text:     "",
+			limit:    20,
+			expected: []string{""},
+		},
+		{
+			name:     "at a sentence",
+			text:     "The bridge is closed. Use the ferry instead.",
+			limit:    30,
+			expected: []string{"The bridge is closed.", "Use the ferry instead."},
+		},
+		{
+			name:     "at a paragraph",
+			text:     "Water station at the square\nBehind the stage",
+			limit:    30,
+			expected: []string{"Water station at the square", "Behind the stage"},
+		},
+		{
+			name:     "between words",
+			text:     "Kettle at the station, please avoid the area",
+			limit:    20,
+			expected: []string{"Kettle at the", "station, please", "avoid the area"},
+		},
+		{
+			name:     "long word",
+			text:     "abcdefghij",
+			limit:    4,
+			expected: []string{"abcd", "efgh", "ij"},
+		},
+		{
+			name:     "multibyte characters",
+			text:     "äöü äöü äöü",
+			limit:    7,
+			expected: []string{"äöü äöü", "äöü"},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, SplitText(tc.text, tc.limit))
+		})
+	}
+}
+
+func TestSplitTextKeepsLimit(t *testing.T) {
+	text := strings.Repeat("Demonstration at the main square https://example.org/route #police. ", 20)
+
+	chunks := SplitText(text, 300)
+	assert.Greater(t, len(chunks), 1)
+	for _, chunk := range chunks {
+		assert.LessOrEqual(t, utf8.RuneCountInString(chunk), 300)
+		assert.NotContains(t, chunk, " https://example.org/ro ")
+	}
+	assert.Equal(t, strings.Join(strings.Fields(text), " "), strings.Join(chunks, " "))
+}
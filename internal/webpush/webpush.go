@@ -0,0 +1,281 @@
+package webpush
+
+import (
+	"bytes"
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/ecdh"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/hkdf"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/systemli/ticker/internal/util"
+)
+
+// recordSize is the record size announced in the header of the encrypted
+// content. Notifications are sent as a single record.
+const recordSize = 4096
+
+// MaxPayload is the largest payload push services are required to accept: the
+// body is limited to 4096 bytes, of which the header takes 86 and the padding
+// delimiter and authentication tag another 17.
+const MaxPayload = 4096 - 86 - 17
+
+var (
+	// ErrSubscriptionGone is returned when the push service no longer knows
+	// the subscription, it should be removed.
+	ErrSubscriptionGone = errors.New("subscription is gone")
+	ErrPayloadTooLarge  = errors.New("payload is too large")
+)
+
+var encoding = base64.RawURLEncoding
+
+// HTTPClient is shared by all clients. The endpoints come from the browsers of
+// the readers, so it refuses to connect to internal addresses.
+var HTTPClient = &http.Client{Timeout: 10 * time.Second, Transport: util.PublicTransport()}
+
+// Subscription is the PushSubscription of a browser, with its keys base64url
+// encoded the way PushSubscription.toJSON() returns them.
+type Subscription struct {
+	Endpoint string
+	P256dh   string
+	Auth     string
+}
+
+// Validate checks that the subscription can be sent to: the endpoint has to be
+// a public https URL and the keys have to be those of a P-256 key pair and a 16
+// byte authentication secret.
+func (s Subscription) Validate() error {
+	if !util.IsPublicURL(s.Endpoint, "https") {
+		return errors.New("endpoint is not a public https url")
+	}
+
+	public, err := encoding.DecodeString(s.P256dh)
+	if err != nil {
+		return err
+	}
+	if _, err := ecdh.P256().NewPublicKey(public); err != nil {
+		return err
+	}
+
+	auth, err := encoding.DecodeString(s.Auth)
+	if err != nil {
+		return err
+	}
+	if len(auth) != 16 {
+		return errors.New("auth secret is not 16 bytes")
+	}
+
+	return nil
+}
+
+// Client sends notifications on behalf of an application server identified by
+// its VAPID key pair.
+type Client struct {
+	PublicKey string
+	// Subject is the contact of the application server for the push services,
+	// a mailto: or https: URL.
+	Subject string
+
+	key  *ecdsa.PrivateKey
+	http *http.Client
+}
+
+// GenerateVAPIDKeys returns a new key pair for the application server, as the
+// base64url encoded public point and private scalar.
+func GenerateVAPIDKeys() (string, string, error) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return "", "", err
+	}
+
+	public, err := key.PublicKey.Bytes()
+	if err != nil {
+		return "", "", err
+	}
+	private, err := key.Bytes()
+	if err != nil {
+		return "", "", err
+	}
+
+	return encoding.EncodeToString(public), encoding.EncodeToString(private), nil
+}
+
+func NewClient(publicKey, privateKey, subject string) (*Client, error) {
+	raw, err := encoding.DecodeString(privateKey)
+	if err != nil {
+		return nil, err
+	}
+
+	key, err := ecdsa.ParseRawPrivateKey(elliptic.P256(), raw)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Client{
+		PublicKey: publicKey,
+		Subject:   subject,
+		key:       key,
+		http:      HTTPClient,
+	}, nil
+}
+
+// Send encrypts the payload for the subscription and hands it to the push
+// service, which keeps it for ttl while the browser is offline.
+func (c *Client) Send(ctx context.Context, subscription Subscription, payload []byte, ttl time.Duration) error {
+	body, err := Encrypt(subscription, payload)
+	if err != nil {
+		return err
+	}
+
+	authorization, err := c.vapid(subscription.Endpoint)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, subscription.Endpoint, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", authorization)
+	req.Header.Set("Content-Encoding", "aes128gcm")
+	req.Header.Set("Content-Type", "application/octet-stream")
+	req.Header.Set("TTL", strconv.Itoa(int(ttl.Seconds())))
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	switch {
+	case resp.StatusCode == http.StatusNotFound || resp.StatusCode == http.StatusGone:
+		return ErrSubscriptionGone
+	case resp.StatusCode < 200 || resp.StatusCode > 299:
+		return fmt.Errorf("push service responded with %s", resp.Status)
+	}
+
+	return nil
+}
+
+// vapid returns the Authorization header identifying the application server
+// to the push service of the endpoint (RFC 8292).
+func (c *Client) vapid(endpoint string) (string, error) {
+	u, err := url.Parse(endpoint)
+	if err != nil {
+		return "", err
+	}
+
+	header := encoding.EncodeToString([]byte(`{"typ":"JWT","alg":"ES256"}`))
+	claims := map[string]any{
+		"aud": u.Scheme + "://" + u.Host,
+		"exp": time.Now().Add(12 * time.Hour).Unix(),
+	}
+	if c.Subject != "" {
+		claims["sub"] = c.Subject
+	}
+	payload, err := json.Marshal(claims)
+	if err != nil {
+		return "", err
+	}
+
+	unsigned := header + "." + encoding.EncodeToString(payload)
+	hash := sha256.Sum256([]byte(unsigned))
+	r, s, err := ecdsa.Sign(rand.Reader, c.key, hash[:])
+	if err != nil {
+		return "", err
+	}
+
+	signature := make([]byte, 64)
+	r.FillBytes(signature[:32])
+	s.FillBytes(signature[32:])
+
+	return fmt.Sprintf("vapid t=%s.%s, k=%s", unsigned, encoding.EncodeToString(signature), c.PublicKey), nil
+}
+
+// Encrypt encrypts the payload for the subscription as aes128gcm content
+// (RFC 8291), with a fresh key pair and salt for every message.
+func Encrypt(subscription Subscription, payload []byte) ([]byte, error) {
+	key, err := ecdh.P256().GenerateKey(rand.Reader)
+	if err != nil {
+		return nil, err
+	}
+
+	salt := make([]byte, 16)
+	if _, err := rand.Read(salt); err != nil {
+		return nil, err
+	}
+
+	return encrypt(subscription, payload, key, salt)
+}
+
+func encrypt(subscription Subscription, payload []byte, key *ecdh.PrivateKey, salt []byte) ([]byte, error) {
+	if len(payload) > MaxPayload {
+		return nil, ErrPayloadTooLarge
+	}
+
+	rawPublic, err := encoding.DecodeString(subscription.P256dh)
+	if err != nil {
+		return nil, err
+	}
+	public, err := ecdh.P256().NewPublicKey(rawPublic)
+	if err != nil {
+		return nil, err
+	}
+	auth, err := encoding.DecodeString(subscription.Auth)
+	if err != nil {
+		return nil, err
+	}
+
+	secret, err := key.ECDH(public)
+	if err != nil {
+		return nil, err
+	}
+
+	serverPublic := key.PublicKey().Bytes()
+	info := append(append([]byte("WebPush: info\x00"), rawPublic...), serverPublic...)
+	ikm, err := hkdf.Key(sha256.New, secret, auth, string(info), 32)
+	if err != nil {
+		return nil, err
+	}
+	cek, err := hkdf.Key(sha256.New, ikm, salt, "Content-Encoding: aes128gcm\x00", 16)
+	if err != nil {
+		return nil, err
+	}
+	nonce, err := hkdf.Key(sha256.New, ikm, salt, "Content-Encoding: nonce\x00", 12)
+	if err != nil {
+		return nil, err
+	}
+
+	block, err := aes.NewCipher(cek)
+	if err != nil {
+		return nil, err
+	}
+	gcm, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+
+	header := make([]byte, 0, 16+4+1+len(serverPublic))
+	header = append(header, salt...)
+	header = binary.BigEndian.AppendUint32(header, recordSize)
+	header = append(header, byte(len(serverPublic)))
+	header = append(header, serverPublic...)
+
+	// The delimiter 0x02 marks the last and only record.
+	plaintext := append(append([]byte{}, payload...), 0x02)
+
+	return gcm.Seal(header, nonce, plaintext, nil), nil
+}
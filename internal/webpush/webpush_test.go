@@ -0,0 +1,126 @@
+package webpush
+
+import (
+	"context"
+	"crypto/ecdh"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestEncrypt uses the example of RFC 8291, Appendix A.
+func TestEncrypt(t *testing.T) {
+	rawKey, err := encoding.DecodeString("yfWPiYE-n46HLnH0KqZOF1fJJU3MYrct3AELtAQ-oRw")
+	require.NoError(t, err)
+	key, err := ecdh.P256().NewPrivateKey(rawKey)
+	require.NoError(t, err)
+	salt, err := encoding.DecodeString("DGv6ra1nlYgDCS1FRnbzlw")
+	require.NoError(t, err)
+
+	subscription := Subscription{
+		Endpoint: "https://push.example.net/push/JzLQ3raZJfFBR0aqvOMsLrt54w4rJUsV",
+		P256dh:   "BCVxsr7N_eNgVRqvHtD0zTZsEc6-VV-JvLexhqUzORcxaOzi6-AYWXvTBHm4bjyPjs7Vd8pZGH6SRpkNtoIAiw4",
+		Auth:     "BTBZMqHH6r4Tts7J_aSIgg",
+	}
+
+	body, err := encrypt(subscription, []byte("When I grow up, I want to be a watermelon"), key, salt)
+	require.NoError(t, err)
+	assert.Equal(t, "DGv6ra1nlYgDCS1FRnbzlwAAEABBBP4z9KsN6nGRTbVYI_c7VJSPQTBtkgcy27mlmlMoZIIgDll6e3vCYLocInmYWAmS6TlzAC8wEqKK6PBru3jl7A_yl95bQpu6cVPTpK4Mqgkf1CXztLVBSt2Ks3oZwbuwXPXLWyouBWLVWGNWQexSgSxsj_Qulcy4a-fN", encoding.EncodeToString(body))
+
+	_, err = encrypt(subscription, make([]byte, MaxPayload+1), key, salt)
+	assert.ErrorIs(t, err, ErrPayloadTooLarge)
+}
+
+func TestSubscriptionValidate(t *testing.T) {
+	subscription := Subscription{
+		Endpoint: "https://push.example.net/push/JzLQ3raZJfFBR0aqvOMsLrt54w4rJUsV",
+		P256dh:   "BCVxsr7N_eNgVRqvHtD0zTZsEc6-VV-JvLexhqUzORcxaOzi6-AYWXvTBHm4bjyPjs7Vd8pZGH6SRpkNtoIAiw4",
+		Auth:     "BTBZMqHH6r4Tts7J_aSIgg",
+	}
+	assert.NoError(t, subscription.Validate())
+
+	invalid := subscription
+	invalid.Endpoint = "http://push.example.net/push"
+	assert.Error(t, invalid.Validate())
+
+	invalid = subscription
+	invalid.Endpoint = "https://10.0.0.1/push"
+	assert.Error(t, invalid.Validate())
+
+	invalid = subscription
+	invalid.P256dh = "BCVxsr7N"
+	assert.Error(t, invalid.Validate())
+
+	invalid = subscription
+	invalid.Auth = "BTBZ"
+	assert.Error(t, invalid.Validate())
+}
+
+func TestClientSend(t *testing.T) {
+	publicKey, privateKey, err := GenerateVAPIDKeys()
+	require.NoError(t, err)
+	client, err := NewClient(publicKey, privateKey, "https://demo.example.org")
+	require.NoError(t, err)
+
+	browser, err := ecdh.P256().GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	subscription := Subscription{P256dh: encoding.EncodeToString(browser.PublicKey().Bytes()), Auth: "BTBZMqHH6r4Tts7J_aSIgg"}
+
+	var req *http.Request
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		req = r
+		if strings.HasSuffix(r.URL.Path, "/gone") {
+			w.WriteHeader(http.StatusGone)
+			return
+		}
+		w.WriteHeader(http.StatusCreated)
+	}))
+	defer server.Close()
+	// The test server listens on the loopback address, which HTTPClient
+	// refuses.
+	client.http = server.Client()
+
+	subscription.Endpoint = server.URL + "/push/1"
+	err = client.Send(context.Background(), subscription, []byte(`{"title":"Ticker"}`), time.Hour)
+	require.NoError(t, err)
+	assert.Equal(t, "aes128gcm", req.Header.Get("Content-Encoding"))
+	assert.Equal(t, "3600", req.Header.Get("TTL"))
+
+	// The JWT is signed with the private key and addressed to the push service.
+	token, key, ok := strings.Cut(strings.TrimPrefix(req.Header.Get("Authorization"), "vapid t="), ", k=")
+	require.True(t, ok)
+	assert.Equal(t, publicKey, key)
+
+	parts := strings.Split(token, ".")
+	require.Len(t, parts, 3)
+	rawClaims, err := encoding.DecodeString(parts[1])
+	require.NoError(t, err)
+	var claims map[string]any
+	require.NoError(t, json.Unmarshal(rawClaims, &claims))
+	assert.Equal(t, server.URL, claims["aud"])
+	assert.Equal(t, "https://demo.example.org", claims["sub"])
+
+	rawPublic, err := encoding.DecodeString(publicKey)
+	require.NoError(t, err)
+	public, err := ecdsa.ParseUncompressedPublicKey(elliptic.P256(), rawPublic)
+	require.NoError(t, err)
+	signature, err := encoding.DecodeString(parts[2])
+	require.NoError(t, err)
+	hash := sha256.Sum256([]byte(parts[0] + "." + parts[1]))
+	assert.True(t, ecdsa.Verify(public, hash[:], new(big.Int).SetBytes(signature[:32]), new(big.Int).SetBytes(signature[32:])))
+
+	subscription.Endpoint = server.URL + "/push/gone"
+	err = client.Send(context.Background(), subscription, []byte(`{}`), time.Hour)
+	assert.ErrorIs(t, err, ErrSubscriptionGone)
+}